@@ -0,0 +1,211 @@
+// Package command provides a CredentialStore that keeps credentials in an external
+// secret manager, such as 1Password (op), pass, or Bitwarden (bw), by running its CLI.
+// ccx then holds no copy of the session itself.
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// AccountIDPlaceholder is replaced with the account ID in every command argument
+const AccountIDPlaceholder = "{account_id}"
+
+// DefaultTimeout bounds each command when Config.Timeout is zero. Password managers may
+// prompt to unlock, so it leaves time for that.
+const DefaultTimeout = 30 * time.Second
+
+// maxStderrLength is the most command stderr quoted in an error message
+const maxStderrLength = 1024
+
+// redacted replaces secrets found in command stderr
+const redacted = "[REDACTED]"
+
+// Config names the commands a CommandCredentialStore runs. Each command is an argv run
+// directly, not through a shell, so account IDs can't inject anything; wrap it in
+// "sh", "-c" if shell features are needed.
+type Config struct {
+	// Retrieve prints the account's session JSON on stdout. Required.
+	Retrieve []string
+	// Store reads the account's session JSON from stdin and saves it. Without it the
+	// store is read-only and Store returns domain.ErrStoreReadOnly.
+	Store []string
+	// Delete removes the account's entry. Without it Delete returns
+	// domain.ErrStoreReadOnly.
+	Delete []string
+	// NotFoundExitCode is the exit status with which Retrieve and Delete report a
+	// missing entry, mapped to domain.ErrCredentialsNotFound. 0 means the commands
+	// have no such status and every failure is an error.
+	NotFoundExitCode int
+	// Timeout bounds each command on top of the caller's context; DefaultTimeout if zero
+	Timeout time.Duration
+}
+
+// CommandCredentialStore implements CredentialStore by running external commands.
+// The secret manager holds the plaintext session JSON; credentials are encrypted in
+// memory once read, like those from any other store.
+type CommandCredentialStore struct { //nolint:revive // keeps the backend in the name like FileCredentialStore
+	config Config
+}
+
+// Ensure CommandCredentialStore implements CredentialStore at compile time
+var _ ports.CredentialStore = (*CommandCredentialStore)(nil)
+
+// NewCommandCredentialStore creates a credential store running the configured commands
+func NewCommandCredentialStore(config Config) (ports.CredentialStore, error) {
+	if len(config.Retrieve) == 0 || config.Retrieve[0] == "" {
+		return nil, errors.New("retrieve command is required")
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	return &CommandCredentialStore{
+		config: config,
+	}, nil
+}
+
+// Store pipes the decrypted session JSON to the store command. Passphrase-protected and
+// still-wrapped envelope credentials can't be decrypted here and are refused.
+func (s *CommandCredentialStore) Store(ctx context.Context, creds *domain.Credentials) error {
+	if len(s.config.Store) == 0 {
+		return fmt.Errorf("%w: no store command configured", domain.ErrStoreReadOnly)
+	}
+
+	plaintext, err := creds.Decrypt()
+	if err != nil {
+		return fmt.Errorf("failed to decrypt credentials for %s: %w", creds.AccountID(), err)
+	}
+	defer clear(plaintext)
+
+	if _, err := s.run(ctx, s.config.Store, creds.AccountID(), plaintext, secretsIn(plaintext)); err != nil {
+		return fmt.Errorf("failed to store credentials for %s: %w", creds.AccountID(), err)
+	}
+	return nil
+}
+
+// Retrieve runs the retrieve command and wraps its output in credentials. Empty output
+// is treated as a missing entry.
+func (s *CommandCredentialStore) Retrieve(ctx context.Context, accountID domain.AccountID) (*domain.Credentials, error) {
+	output, err := s.run(ctx, s.config.Retrieve, accountID, nil, nil)
+	if err != nil {
+		if errors.Is(err, domain.ErrCredentialsNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to retrieve credentials for %s: %w", accountID, err)
+	}
+	defer clear(output)
+
+	data := bytes.TrimSpace(output)
+	if len(data) == 0 {
+		return nil, domain.ErrCredentialsNotFound
+	}
+	if err := domain.ValidateCredentialData(data); err != nil {
+		return nil, fmt.Errorf("retrieve command output for %s: %w", accountID, err)
+	}
+
+	creds, err := domain.NewCredentials(accountID, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credentials: %w", err)
+	}
+	return creds, nil
+}
+
+// Delete runs the delete command for the account
+func (s *CommandCredentialStore) Delete(ctx context.Context, accountID domain.AccountID) error {
+	if len(s.config.Delete) == 0 {
+		return fmt.Errorf("%w: no delete command configured", domain.ErrStoreReadOnly)
+	}
+
+	if _, err := s.run(ctx, s.config.Delete, accountID, nil, nil); err != nil {
+		if errors.Is(err, domain.ErrCredentialsNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to delete credentials for %s: %w", accountID, err)
+	}
+	return nil
+}
+
+// run executes template for accountID with stdin, returning its stdout. An account ID
+// that isn't a valid ccx ID is rejected before anything runs, since it is substituted
+// into the command's arguments. A missing entry returns domain.ErrCredentialsNotFound.
+// Other failures quote stderr with the secrets, and anything stdout held, scrubbed out.
+func (s *CommandCredentialStore) run(ctx context.Context, template []string, accountID domain.AccountID, stdin []byte, secrets [][]byte) ([]byte, error) {
+	if err := domain.ValidateAccountID(accountID); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	args := make([]string, len(template))
+	for i, arg := range template {
+		args[i] = strings.ReplaceAll(arg, AccountIDPlaceholder, string(accountID))
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...) // #nosec G204 - commands come from the user's own configuration
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	// Don't wait forever on pipes held open by a killed command's children
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if err == nil {
+		return stdout.Bytes(), nil
+	}
+
+	secrets = append(secrets, secretsIn(stdout.Bytes())...)
+	clear(stdout.Bytes())
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("%s did not finish: %w", args[0], ctxErr)
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && s.config.NotFoundExitCode != 0 && exitErr.ExitCode() == s.config.NotFoundExitCode {
+		return nil, domain.ErrCredentialsNotFound
+	}
+
+	if msg := scrub(stderr.String(), secrets); msg != "" {
+		return nil, fmt.Errorf("%s: %w: %s", args[0], err, msg)
+	}
+	return nil, fmt.Errorf("%s: %w", args[0], err)
+}
+
+// secretsIn returns the values in a session payload that must never reach an error:
+// the payload itself and its session key
+func secretsIn(data []byte) [][]byte {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil
+	}
+	secrets := [][]byte{data}
+	if payload, err := domain.ParseCredentialPayload(data); err == nil && payload.SessionKey() != "" {
+		secrets = append(secrets, []byte(payload.SessionKey()))
+	}
+	return secrets
+}
+
+// scrub removes every secret from command stderr and trims it to a quotable length
+func scrub(stderr string, secrets [][]byte) string {
+	for _, secret := range secrets {
+		if len(secret) > 0 {
+			stderr = strings.ReplaceAll(stderr, string(secret), redacted)
+		}
+	}
+	stderr = strings.TrimSpace(stderr)
+	if len(stderr) > maxStderrLength {
+		stderr = stderr[:maxStderrLength] + "..."
+	}
+	return stderr
+}
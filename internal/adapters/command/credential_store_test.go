@@ -0,0 +1,209 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+// notFoundCode is the exit status the fake secret manager uses for a missing entry
+const notFoundCode = 3
+
+// TestHelperProcess is not a real test: it acts as a fake secret manager CLI when the
+// store runs the test binary. Entries are files in $CCX_FAKE_VAULT named by account ID.
+func TestHelperProcess(t *testing.T) {
+	vault := os.Getenv("CCX_FAKE_VAULT")
+	if vault == "" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	if len(args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: -- get|set|rm|echo|hang <id>")
+		os.Exit(2)
+	}
+	entry := filepath.Join(vault, args[2])
+
+	switch args[1] {
+	case "get":
+		data, err := os.ReadFile(entry) // #nosec G304 - test vault path
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "no such item")
+			os.Exit(notFoundCode)
+		}
+		_, _ = os.Stdout.Write(data)
+	case "set":
+		data, _ := io.ReadAll(os.Stdin)
+		if err := os.WriteFile(entry, data, 0o600); err != nil {
+			os.Exit(1)
+		}
+	case "rm":
+		if err := os.Remove(entry); err != nil {
+			os.Exit(notFoundCode)
+		}
+	case "echo":
+		// A misbehaving tool that echoes its input into an error
+		data, _ := io.ReadAll(os.Stdin)
+		fmt.Fprintf(os.Stderr, "vault locked, could not save %s\n", data)
+		os.Exit(1)
+	case "hang":
+		time.Sleep(time.Minute)
+	}
+	os.Exit(0)
+}
+
+// fakeCommand returns a command template running TestHelperProcess with op
+func fakeCommand(op string) []string {
+	return []string{os.Args[0], "-test.run=^TestHelperProcess$", "--", op, AccountIDPlaceholder}
+}
+
+func newFakeStore(t *testing.T) (*CommandCredentialStore, string) {
+	t.Helper()
+
+	vault := t.TempDir()
+	t.Setenv("CCX_FAKE_VAULT", vault)
+	store, err := NewCommandCredentialStore(Config{
+		Retrieve:         fakeCommand("get"),
+		Store:            fakeCommand("set"),
+		Delete:           fakeCommand("rm"),
+		NotFoundExitCode: notFoundCode,
+	})
+	if err != nil {
+		t.Fatalf("NewCommandCredentialStore() error = %v", err)
+	}
+	return store.(*CommandCredentialStore), vault
+}
+
+func TestCommandCredentialStore_StoreRetrieveDelete(t *testing.T) {
+	store, vault := newFakeStore(t)
+	ctx := context.Background()
+
+	accountID := domain.AccountID("abc12345")
+	testData := []byte(`{"sessionKey":"secret-key"}`)
+	creds, _ := domain.NewCredentials(accountID, testData)
+	if err := store.Store(ctx, creds); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	// The secret manager holds the plaintext session, keyed by account ID
+	saved, err := os.ReadFile(filepath.Join(vault, "abc12345")) // #nosec G304 - test vault path
+	if err != nil || string(saved) != string(testData) {
+		t.Fatalf("Vault entry = %q, %v; want the session JSON", saved, err)
+	}
+
+	// Trailing newlines, as most CLIs print, are ignored
+	if err := os.WriteFile(filepath.Join(vault, "abc12345"), append(testData, '\n'), 0o600); err != nil {
+		t.Fatalf("Failed to write vault entry: %v", err)
+	}
+	retrieved, err := store.Retrieve(ctx, accountID)
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if data, _ := retrieved.Decrypt(); string(data) != string(testData) {
+		t.Errorf("Retrieved data = %s, want %s", data, testData)
+	}
+
+	if err := store.Delete(ctx, accountID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Retrieve(ctx, accountID); !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Retrieve() after Delete error = %v, want ErrCredentialsNotFound", err)
+	}
+	if err := store.Delete(ctx, accountID); !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Delete() of missing entry error = %v, want ErrCredentialsNotFound", err)
+	}
+}
+
+func TestCommandCredentialStore_Errors(t *testing.T) {
+	ctx := context.Background()
+	creds, _ := domain.NewCredentials("abc12345", []byte(`{"sessionKey":"hunter2-session"}`))
+
+	t.Run("stderr is scrubbed", func(t *testing.T) {
+		store, _ := newFakeStore(t)
+		store.config.Store = fakeCommand("echo")
+
+		err := store.Store(ctx, creds)
+		if err == nil {
+			t.Fatal("Store() error = nil, want error")
+		}
+		if strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("Store() error leaks the secret: %v", err)
+		}
+		if !strings.Contains(err.Error(), "vault locked") || !strings.Contains(err.Error(), redacted) {
+			t.Errorf("Store() error = %v, want the scrubbed stderr", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		store, _ := newFakeStore(t)
+		store.config.Retrieve = fakeCommand("hang")
+		store.config.Timeout = 100 * time.Millisecond
+
+		start := time.Now()
+		_, err := store.Retrieve(ctx, "abc12345")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Retrieve() error = %v, want DeadlineExceeded", err)
+		}
+		if elapsed := time.Since(start); elapsed > 10*time.Second {
+			t.Errorf("Retrieve() took %v, want it stopped at the timeout", elapsed)
+		}
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		store, vault := newFakeStore(t)
+		_ = os.WriteFile(filepath.Join(vault, "abc12345"), []byte("not json hunter2"), 0o600)
+
+		_, err := store.Retrieve(ctx, "abc12345")
+		if !errors.Is(err, domain.ErrInvalidCredentialData) || strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("Retrieve() error = %v, want a redacted ErrInvalidCredentialData", err)
+		}
+	})
+
+	t.Run("invalid account ID", func(t *testing.T) {
+		store, vault := newFakeStore(t)
+		accountID := domain.AccountID("../escaped")
+		badCreds, _ := domain.NewCredentials(accountID, []byte(`{"sessionKey":"hunter2-session"}`))
+
+		if err := store.Store(ctx, badCreds); !errors.Is(err, domain.ErrInvalidAccountID) {
+			t.Errorf("Store() error = %v, want ErrInvalidAccountID", err)
+		}
+		if _, err := store.Retrieve(ctx, accountID); !errors.Is(err, domain.ErrInvalidAccountID) {
+			t.Errorf("Retrieve() error = %v, want ErrInvalidAccountID", err)
+		}
+		if err := store.Delete(ctx, accountID); !errors.Is(err, domain.ErrInvalidAccountID) {
+			t.Errorf("Delete() error = %v, want ErrInvalidAccountID", err)
+		}
+		if _, err := os.Stat(filepath.Join(vault, "..", "escaped")); !os.IsNotExist(err) {
+			t.Errorf("Store() wrote outside the vault: %v", err)
+		}
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		store, err := NewCommandCredentialStore(Config{Retrieve: fakeCommand("get")})
+		if err != nil {
+			t.Fatalf("NewCommandCredentialStore() error = %v", err)
+		}
+		if err := store.Store(ctx, creds); !errors.Is(err, domain.ErrStoreReadOnly) {
+			t.Errorf("Store() error = %v, want ErrStoreReadOnly", err)
+		}
+		if err := store.Delete(ctx, "abc12345"); !errors.Is(err, domain.ErrStoreReadOnly) {
+			t.Errorf("Delete() error = %v, want ErrStoreReadOnly", err)
+		}
+	})
+
+	t.Run("no retrieve command", func(t *testing.T) {
+		if _, err := NewCommandCredentialStore(Config{}); err == nil {
+			t.Error("NewCommandCredentialStore() without a retrieve command error = nil, want error")
+		}
+	})
+}
@@ -0,0 +1,207 @@
+// Package credstore picks the credential store ccx uses: the operating system's native
+// secret store when it works, and the encrypted file store otherwise.
+package credstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/evanschultz/ccx/internal/adapters/json"
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// EnvBackend is the environment variable choosing the backend: file, keychain, or auto.
+// keychain means the platform's native store; auto, the default, tries it and falls
+// back to the file store.
+const EnvBackend = "CCX_CREDENTIAL_BACKEND"
+
+// Backend names a credential store implementation
+type Backend string
+
+// Backends New can select
+const (
+	BackendFile          Backend = "file"          // Encrypted files in the data directory
+	BackendKeychain      Backend = "keychain"      // macOS Keychain
+	BackendWinCred       Backend = "wincred"       // Windows Credential Manager
+	BackendSecretService Backend = "secretservice" // freedesktop Secret Service on Linux
+)
+
+// modeAuto is the EnvBackend value, and the default, that tries the native store first
+const modeAuto = "auto"
+
+// ErrUnknownBackend is returned when EnvBackend names no known backend
+var ErrUnknownBackend = errors.New("unknown credential backend")
+
+// ErrNoNativeBackend is returned on platforms without a supported native store
+var ErrNoNativeBackend = errors.New("no native credential store on this platform")
+
+// ErrFileCredentialsExist is the Fallback reason when auto mode keeps the file store
+// because it already holds credentials, which the native store would not have
+var ErrFileCredentialsExist = errors.New("file credential store already holds credentials")
+
+// probeCacheFile records in the data directory which native backend passed the probe,
+// so the test write happens once rather than on every run
+const probeCacheFile = ".credential-probe"
+
+// probeAccountID names the throwaway credentials used to check a native store works.
+// Reusing one ID means a probe that fails to clean up is overwritten by the next.
+const probeAccountID domain.AccountID = "ccx-probe"
+
+// Selection is the credential store New picked
+type Selection struct {
+	Store   ports.CredentialStore
+	Backend Backend // Which implementation Store is, for display
+
+	// Fallback is why the native store was passed over for the file store in auto
+	// mode, or nil if it was not
+	Fallback error
+}
+
+// Option configures optional New behavior
+type Option func(*options)
+
+type options struct {
+	fileOpts   []json.CredentialStoreOption
+	warn       func(error)
+	getenv     func(string) string
+	openNative func() (ports.CredentialStore, Backend, error)
+}
+
+// WithFileOptions configures the file store when it is selected
+func WithFileOptions(opts ...json.CredentialStoreOption) Option {
+	return func(o *options) {
+		o.fileOpts = append(o.fileOpts, opts...)
+	}
+}
+
+// WithWarnings reports why the native store was passed over to warn
+func WithWarnings(warn func(error)) Option {
+	return func(o *options) {
+		o.warn = warn
+	}
+}
+
+// New returns the credential store selected by EnvBackend. In auto mode a native store
+// is only used if storing, reading back, and deleting a throwaway credential works;
+// otherwise the file store in dataDir is used and a warning reported, so a broken
+// keychain doesn't lock users out. Asking for keychain explicitly returns an error instead.
+// The probe's success is remembered in dataDir, so it runs once per backend. Auto mode
+// also keeps the file store, without a warning, while dataDir/credentials holds
+// credentials, so upgrading doesn't strand them; they have to be migrated, and the
+// native store chosen explicitly, to move.
+func New(ctx context.Context, dataDir string, opts ...Option) (*Selection, error) {
+	o := &options{
+		warn:       func(error) {},
+		getenv:     os.Getenv,
+		openNative: openNative,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	mode := strings.ToLower(strings.TrimSpace(o.getenv(EnvBackend)))
+	switch mode {
+	case string(BackendFile):
+		return o.fileSelection(dataDir, nil), nil
+	case string(BackendKeychain), modeAuto, "":
+	default:
+		return nil, fmt.Errorf("%w %q in %s; use file, keychain, or auto", ErrUnknownBackend, mode, EnvBackend)
+	}
+
+	if mode != string(BackendKeychain) {
+		exist, err := hasFileCredentials(dataDir)
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			return o.fileSelection(dataDir, ErrFileCredentialsExist), nil
+		}
+	}
+
+	store, backend, err := o.openNative()
+	if err == nil && !probed(dataDir, backend) {
+		if err = probe(ctx, store); err != nil {
+			err = fmt.Errorf("%s credential store failed a test write: %w", backend, err)
+		} else {
+			rememberProbe(dataDir, backend)
+		}
+	}
+	if err == nil {
+		return &Selection{Store: store, Backend: backend}, nil
+	}
+
+	if mode == string(BackendKeychain) {
+		return nil, err
+	}
+	o.warn(fmt.Errorf("using file credential store: %w", err))
+	return o.fileSelection(dataDir, err), nil
+}
+
+func (o *options) fileSelection(dataDir string, fallback error) *Selection {
+	return &Selection{
+		Store:    json.NewFileCredentialStore(dataDir, o.fileOpts...),
+		Backend:  BackendFile,
+		Fallback: fallback,
+	}
+}
+
+// hasFileCredentials reports whether the file store in dataDir holds any credentials
+func hasFileCredentials(dataDir string) (bool, error) {
+	entries, err := os.ReadDir(filepath.Join(dataDir, "credentials"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check for file credentials: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// probed reports whether backend already passed the probe for dataDir
+func probed(dataDir string, backend Backend) bool {
+	data, err := os.ReadFile(filepath.Join(dataDir, probeCacheFile)) // #nosec G304 - path within the data directory
+	return err == nil && strings.TrimSpace(string(data)) == string(backend)
+}
+
+// rememberProbe records that backend passed the probe. Failing to record it only means
+// the probe runs again next time, so errors are ignored.
+func rememberProbe(dataDir string, backend Backend) {
+	if err := os.MkdirAll(dataDir, 0o700); err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(dataDir, probeCacheFile), []byte(backend+"\n"), 0o600)
+}
+
+// probe checks store can store, retrieve, and delete credentials
+func probe(ctx context.Context, store ports.CredentialStore) error {
+	data := []byte(`{"sessionKey":"ccx-probe"}`)
+	creds, err := domain.NewCredentials(probeAccountID, data)
+	if err != nil {
+		return err
+	}
+
+	if err := store.Store(ctx, creds); err != nil {
+		return err
+	}
+	retrieved, err := store.Retrieve(ctx, probeAccountID)
+	if err != nil {
+		_ = store.Delete(ctx, probeAccountID)
+		return err
+	}
+	if got, err := retrieved.Decrypt(); err != nil || string(got) != string(data) {
+		_ = store.Delete(ctx, probeAccountID)
+		return errors.New("read back different credentials than were stored")
+	}
+	return store.Delete(ctx, probeAccountID)
+}
@@ -0,0 +1,163 @@
+package credstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/adapters/json"
+	"github.com/evanschultz/ccx/internal/adapters/memory"
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// brokenStore is a native store whose writes fail, like a locked keychain
+type brokenStore struct {
+	*memory.CredentialStore
+}
+
+func (brokenStore) Store(_ context.Context, _ *domain.Credentials) error {
+	return errors.New("keychain is locked")
+}
+
+// withEnv sets the EnvBackend value New sees
+func withEnv(value string) Option {
+	return func(o *options) {
+		o.getenv = func(key string) string {
+			if key == EnvBackend {
+				return value
+			}
+			return ""
+		}
+	}
+}
+
+// withNative replaces the platform's native store
+func withNative(store ports.CredentialStore, err error) Option {
+	return func(o *options) {
+		o.openNative = func() (ports.CredentialStore, Backend, error) {
+			return store, BackendKeychain, err
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	native := memory.NewCredentialStore()
+	errNoBus := errors.New("no session bus")
+
+	tests := []struct {
+		name         string
+		env          string
+		native       ports.CredentialStore
+		nativeErr    error
+		wantBackend  Backend
+		wantFallback bool
+	}{
+		{"auto by default uses native", "", native, nil, BackendKeychain, false},
+		{"auto uses native", "auto", native, nil, BackendKeychain, false},
+		{"explicit keychain", "Keychain", native, nil, BackendKeychain, false},
+		{"explicit file", "file", native, nil, BackendFile, false},
+		{"auto falls back when native is missing", "auto", nil, errNoBus, BackendFile, true},
+		{"auto falls back when the probe fails", "", brokenStore{memory.NewCredentialStore()}, nil, BackendFile, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warnings []error
+			selection, err := New(ctx, t.TempDir(), withEnv(tt.env), withNative(tt.native, tt.nativeErr),
+				WithWarnings(func(err error) { warnings = append(warnings, err) }))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if selection.Backend != tt.wantBackend {
+				t.Errorf("Backend = %s, want %s", selection.Backend, tt.wantBackend)
+			}
+			if (selection.Fallback != nil) != tt.wantFallback || (len(warnings) > 0) != tt.wantFallback {
+				t.Errorf("Fallback = %v, warnings = %v; want fallback %v", selection.Fallback, warnings, tt.wantFallback)
+			}
+			if tt.wantBackend == BackendFile {
+				if _, ok := selection.Store.(*json.FileCredentialStore); !ok {
+					t.Errorf("Store = %T, want *json.FileCredentialStore", selection.Store)
+				}
+			}
+		})
+	}
+
+	// The probe cleans up after itself
+	if ids, _ := native.ListAccountIDs(ctx); len(ids) != 0 {
+		t.Errorf("Probe left credentials behind: %v", ids)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := New(ctx, t.TempDir(), withEnv("vault"), withNative(memory.NewCredentialStore(), nil)); !errors.Is(err, ErrUnknownBackend) {
+		t.Errorf("New() with unknown backend error = %v, want ErrUnknownBackend", err)
+	}
+
+	// Asking for the native store explicitly never silently falls back
+	if _, err := New(ctx, t.TempDir(), withEnv("keychain"), withNative(nil, ErrNoNativeBackend)); !errors.Is(err, ErrNoNativeBackend) {
+		t.Errorf("New() with keychain unavailable error = %v, want ErrNoNativeBackend", err)
+	}
+	if _, err := New(ctx, t.TempDir(), withEnv("keychain"), withNative(brokenStore{memory.NewCredentialStore()}, nil)); err == nil {
+		t.Error("New() with broken keychain error = nil, want error")
+	}
+}
+
+// countingStore is a native store that counts writes, to tell whether a probe ran
+type countingStore struct {
+	*memory.CredentialStore
+	stores int
+}
+
+func (s *countingStore) Store(ctx context.Context, creds *domain.Credentials) error {
+	s.stores++
+	return s.CredentialStore.Store(ctx, creds)
+}
+
+func TestNew_KeepsFileCredentials(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	creds, _ := domain.NewCredentials("abc12345", []byte(`{"sessionKey":"sk-test"}`))
+	if err := json.NewFileCredentialStore(dataDir).Store(ctx, creds); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	native := &countingStore{CredentialStore: memory.NewCredentialStore()}
+
+	var warnings []error
+	selection, err := New(ctx, dataDir, withEnv("auto"), withNative(native, nil),
+		WithWarnings(func(err error) { warnings = append(warnings, err) }))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if selection.Backend != BackendFile || !errors.Is(selection.Fallback, ErrFileCredentialsExist) {
+		t.Errorf("Selection = %s (fallback %v), want the file store kept for its credentials", selection.Backend, selection.Fallback)
+	}
+	if len(warnings) != 0 || native.stores != 0 {
+		t.Errorf("warnings = %v, native writes = %d; want neither", warnings, native.stores)
+	}
+
+	// Asking for the native store explicitly still gets it
+	selection, err = New(ctx, dataDir, withEnv("keychain"), withNative(native, nil))
+	if err != nil || selection.Backend != BackendKeychain {
+		t.Errorf("New(keychain) = %v, %v; want the native store", selection, err)
+	}
+}
+
+func TestNew_ProbesOnce(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	native := &countingStore{CredentialStore: memory.NewCredentialStore()}
+
+	for range 3 {
+		selection, err := New(ctx, dataDir, withNative(native, nil))
+		if err != nil || selection.Backend != BackendKeychain {
+			t.Fatalf("New() = %v, %v; want the native store", selection, err)
+		}
+	}
+	if native.stores != 1 {
+		t.Errorf("Probe wrote %d times, want once", native.stores)
+	}
+}
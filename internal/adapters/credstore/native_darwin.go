@@ -0,0 +1,14 @@
+//go:build darwin
+
+package credstore
+
+import (
+	"github.com/evanschultz/ccx/internal/adapters/keychain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// openNative opens the macOS Keychain
+func openNative() (ports.CredentialStore, Backend, error) {
+	store, err := keychain.NewKeychainCredentialStore(keychain.DefaultServiceName)
+	return store, BackendKeychain, err
+}
@@ -0,0 +1,14 @@
+//go:build linux
+
+package credstore
+
+import (
+	"github.com/evanschultz/ccx/internal/adapters/secretservice"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// openNative connects to the Secret Service
+func openNative() (ports.CredentialStore, Backend, error) {
+	store, err := secretservice.NewSecretServiceCredentialStore()
+	return store, BackendSecretService, err
+}
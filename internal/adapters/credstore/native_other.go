@@ -0,0 +1,10 @@
+//go:build !darwin && !windows && !linux
+
+package credstore
+
+import "github.com/evanschultz/ccx/internal/ports"
+
+// openNative reports that this platform has no supported native store
+func openNative() (ports.CredentialStore, Backend, error) {
+	return nil, "", ErrNoNativeBackend
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package credstore
+
+import (
+	"github.com/evanschultz/ccx/internal/adapters/wincred"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// openNative opens the Windows Credential Manager
+func openNative() (ports.CredentialStore, Backend, error) {
+	return wincred.NewWinCredCredentialStore(), BackendWinCred, nil
+}
@@ -5,8 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,20 +17,55 @@ import (
 	"github.com/evanschultz/ccx/internal/ports"
 )
 
+// ErrInvalidAccountRecord is returned when looking up a record in accounts.json that is
+// missing a required field or holds a malformed value. List skips such records instead;
+// LoadWarnings reports them.
+var ErrInvalidAccountRecord = errors.New("invalid account record in accounts file")
+
+// Files holding the account list in the data directory. Only one of them is used at a
+// time, depending on whether the repository encrypts accounts.
+const (
+	AccountsFile          = "accounts.json"
+	EncryptedAccountsFile = "accounts.enc"
+)
+
+// ErrAccountsNotEncrypted is returned by an encrypting repository when the data directory
+// holds only the plaintext accounts file, so it would otherwise start out empty
+var ErrAccountsNotEncrypted = errors.New("accounts file is not encrypted; run EncryptAccountsFile first")
+
+// ErrAccountsEncrypted is returned by a plaintext repository when the data directory
+// holds only the encrypted accounts file
+var ErrAccountsEncrypted = errors.New("accounts file is encrypted and needs the master key")
+
+// accountsEnvelopeID labels the envelope sealing the account list. It is covered by the
+// envelope's MAC, so a credential file copied over accounts.enc is rejected.
+const accountsEnvelopeID domain.AccountID = "accounts"
+
 // FileAccountRepository implements AccountRepository using JSON files
 type FileAccountRepository struct {
-	dataDir string
-	mu      sync.RWMutex
+	dataDir   string
+	masterKey []byte // Encrypts the account list when set
+	mu        sync.RWMutex
 }
 
+// Ensure FileAccountRepository reports malformed records at compile time
+var _ ports.AccountRecordValidator = (*FileAccountRepository)(nil)
+
 // accountData represents the JSON structure for persistence
 type accountData struct {
-	ID        string `json:"id"`
-	Email     string `json:"email"`
-	Alias     string `json:"alias"`
-	UUID      string `json:"uuid"`
-	CreatedAt string `json:"created_at"`
-	LastUsed  string `json:"last_used"`
+	ID          string            `json:"id"`
+	Email       string            `json:"email"`
+	Alias       string            `json:"alias"`
+	UUID        string            `json:"uuid"`
+	Tags        []string          `json:"tags,omitempty"`
+	Color       string            `json:"color,omitempty"`
+	Label       string            `json:"label,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+	RawOAuth    json.RawMessage   `json:"raw_oauth,omitempty"`
+	CreatedAt   string            `json:"created_at"`
+	LastUsed    string            `json:"last_used"`
+	ArchivedAt  string            `json:"archived_at,omitempty"`
 }
 
 // NewFileAccountRepository creates a new file-based account repository
@@ -37,30 +75,101 @@ func NewFileAccountRepository(dataDir string) ports.AccountRepository {
 	}
 }
 
+// NewEncryptedFileAccountRepository creates a file-based account repository that keeps
+// the account list in accounts.enc, sealed with the envelope scheme used for credentials:
+// the JSON is encrypted with a random data key wrapped by masterKey. Lookups decrypt the
+// whole list into memory. Existing plaintext data must be moved over once with
+// EncryptAccountsFile. See LoadOrCreateMasterKey.
+func NewEncryptedFileAccountRepository(dataDir string, masterKey []byte) ports.AccountRepository {
+	return &FileAccountRepository{
+		dataDir:   dataDir,
+		masterKey: append([]byte(nil), masterKey...),
+	}
+}
+
+// EncryptAccountsFile is the one-time migration of a plaintext accounts.json to the
+// accounts.enc read by NewEncryptedFileAccountRepository. The encrypted file is written
+// before the plaintext one is removed, so an interrupted migration loses nothing. It does
+// nothing if there is no plaintext file, and refuses to overwrite an existing encrypted one.
+func EncryptAccountsFile(ctx context.Context, dataDir string, masterKey []byte) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := lockDataDir(ctx, dataDir, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	plainPath := filepath.Join(dataDir, AccountsFile)
+	if _, err := os.Stat(plainPath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to check %s: %w", AccountsFile, err)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, EncryptedAccountsFile)); err == nil {
+		return fmt.Errorf("%s already exists; refusing to overwrite it with %s", EncryptedAccountsFile, AccountsFile)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to check %s: %w", EncryptedAccountsFile, err)
+	}
+
+	plain := &FileAccountRepository{dataDir: dataDir}
+	accounts, err := plain.loadAccounts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", AccountsFile, err)
+	}
+
+	encrypted := &FileAccountRepository{dataDir: dataDir, masterKey: masterKey}
+	if err := encrypted.saveAccounts(ctx, accounts); err != nil {
+		return fmt.Errorf("failed to write %s: %w", EncryptedAccountsFile, err)
+	}
+
+	if err := os.Remove(plainPath); err != nil {
+		return fmt.Errorf("failed to remove %s after encrypting it: %w", AccountsFile, err)
+	}
+	return nil
+}
+
 // Save persists an account to the JSON file
-func (r *FileAccountRepository) Save(_ context.Context, account *domain.Account) error {
+func (r *FileAccountRepository) Save(ctx context.Context, account *domain.Account) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// Ensure data directory exists
-	if err := os.MkdirAll(r.dataDir, 0o700); err != nil {
+	unlock, err := lockDataDir(ctx, r.dataDir, true)
+	if err != nil {
 		return err
 	}
+	defer unlock()
 
 	// Load existing accounts
-	accounts, err := r.loadAccounts()
+	accounts, err := r.loadAccounts(ctx)
 	if err != nil {
 		return err
 	}
 
 	// Convert domain account to accountData
 	data := accountData{
-		ID:        string(account.ID()),
-		Email:     string(account.Email()),
-		Alias:     account.Alias(),
-		UUID:      account.UUID(),
-		CreatedAt: account.CreatedAt().Format("2006-01-02T15:04:05Z07:00"),
-		LastUsed:  account.LastUsed().Format("2006-01-02T15:04:05Z07:00"),
+		ID:          string(account.ID()),
+		Email:       string(account.Email()),
+		Alias:       account.Alias(),
+		UUID:        account.UUID(),
+		Tags:        account.Tags(),
+		Color:       account.Color(),
+		Label:       account.Label(),
+		Description: account.Description(),
+		Env:         account.EnvOverrides(),
+		RawOAuth:    account.RawOAuth(),
+		CreatedAt:   account.CreatedAt().Format("2006-01-02T15:04:05Z07:00"),
+		LastUsed:    account.LastUsed().Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if account.Archived() {
+		data.ArchivedAt = account.ArchivedAt().Format("2006-01-02T15:04:05Z07:00")
 	}
 
 	// Check if account already exists (update scenario)
@@ -79,15 +188,25 @@ func (r *FileAccountRepository) Save(_ context.Context, account *domain.Account)
 	}
 
 	// Save back to file
-	return r.saveAccounts(accounts)
+	return r.saveAccounts(ctx, accounts)
 }
 
 // FindByID retrieves an account by its ID
-func (r *FileAccountRepository) FindByID(_ context.Context, id domain.AccountID) (*domain.Account, error) {
+func (r *FileAccountRepository) FindByID(ctx context.Context, id domain.AccountID) (*domain.Account, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	accounts, err := r.loadAccounts()
+	unlock, err := lockDataDir(ctx, r.dataDir, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	accounts, err := r.loadAccounts(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -98,34 +217,55 @@ func (r *FileAccountRepository) FindByID(_ context.Context, id domain.AccountID)
 		}
 	}
 
-	return nil, errors.New("account not found")
+	return nil, domain.ErrAccountNotFound
 }
 
-// FindByEmail retrieves an account by email
-func (r *FileAccountRepository) FindByEmail(_ context.Context, email domain.Email) (*domain.Account, error) {
+// FindByEmail retrieves an account by email, ignoring case and surrounding whitespace
+func (r *FileAccountRepository) FindByEmail(ctx context.Context, email domain.Email) (*domain.Account, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	accounts, err := r.loadAccounts()
+	unlock, err := lockDataDir(ctx, r.dataDir, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	accounts, err := r.loadAccounts(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	email = domain.NormalizeEmail(string(email))
 	for _, acc := range accounts {
-		if acc.Email == string(email) {
+		if domain.NormalizeEmail(acc.Email) == email {
 			return r.convertToAccount(acc)
 		}
 	}
 
-	return nil, errors.New("account not found")
+	return nil, domain.ErrAccountNotFound
 }
 
 // FindByAlias retrieves an account by alias
-func (r *FileAccountRepository) FindByAlias(_ context.Context, alias string) (*domain.Account, error) {
+func (r *FileAccountRepository) FindByAlias(ctx context.Context, alias string) (*domain.Account, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	accounts, err := r.loadAccounts()
+	unlock, err := lockDataDir(ctx, r.dataDir, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	accounts, err := r.loadAccounts(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -136,24 +276,64 @@ func (r *FileAccountRepository) FindByAlias(_ context.Context, alias string) (*d
 		}
 	}
 
-	return nil, errors.New("account not found")
+	return nil, domain.ErrAccountNotFound
+}
+
+// FindByUUID retrieves an account by its Claude account UUID
+func (r *FileAccountRepository) FindByUUID(ctx context.Context, uuid string) (*domain.Account, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	unlock, err := lockDataDir(ctx, r.dataDir, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	accounts, err := r.loadAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, acc := range accounts {
+		if acc.UUID == uuid {
+			return r.convertToAccount(acc)
+		}
+	}
+
+	return nil, domain.ErrAccountNotFound
 }
 
 // List returns all accounts
-func (r *FileAccountRepository) List(_ context.Context) ([]*domain.Account, error) {
+func (r *FileAccountRepository) List(ctx context.Context) ([]*domain.Account, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	accounts, err := r.loadAccounts()
+	unlock, err := lockDataDir(ctx, r.dataDir, false)
 	if err != nil {
 		return nil, err
 	}
+	defer unlock()
 
+	accounts, err := r.loadAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// One malformed record must not hide every other account; LoadWarnings reports it
 	result := make([]*domain.Account, 0, len(accounts))
 	for _, acc := range accounts {
-		account, err := r.convertToAccount(acc)
-		if err != nil {
-			return nil, err
+		account, problems := r.checkAccountData(acc)
+		if len(problems) > 0 {
+			continue
 		}
 		result = append(result, account)
 	}
@@ -161,12 +341,67 @@ func (r *FileAccountRepository) List(_ context.Context) ([]*domain.Account, erro
 	return result, nil
 }
 
+// LoadWarnings validates every record in the accounts file and reports the malformed
+// ones, which List skips, and the duplicated ones, which lose to another record with
+// the same ID
+func (r *FileAccountRepository) LoadWarnings(ctx context.Context) ([]ports.AccountRecordIssue, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	unlock, err := lockDataDir(ctx, r.dataDir, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	accounts, err := r.readAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, dropped := dedupeAccounts(accounts)
+
+	var issues []ports.AccountRecordIssue
+	for i, acc := range accounts {
+		if kept, ok := dropped[i]; ok {
+			issues = append(issues, ports.AccountRecordIssue{
+				Index:     i,
+				AccountID: domain.AccountID(acc.ID),
+				Problems:  []string{fmt.Sprintf("duplicate id; record %d is used instead", kept)},
+			})
+			continue
+		}
+		if _, problems := r.checkAccountData(acc); len(problems) > 0 {
+			issues = append(issues, ports.AccountRecordIssue{
+				Index:     i,
+				AccountID: domain.AccountID(acc.ID),
+				Problems:  problems,
+			})
+		}
+	}
+
+	return issues, nil
+}
+
 // Delete removes an account
-func (r *FileAccountRepository) Delete(_ context.Context, id domain.AccountID) error {
+func (r *FileAccountRepository) Delete(ctx context.Context, id domain.AccountID) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	accounts, err := r.loadAccounts()
+	unlock, err := lockDataDir(ctx, r.dataDir, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	accounts, err := r.loadAccounts(ctx)
 	if err != nil {
 		return err
 	}
@@ -182,26 +417,35 @@ func (r *FileAccountRepository) Delete(_ context.Context, id domain.AccountID) e
 	}
 
 	if !found {
-		return errors.New("account not found")
+		return domain.ErrAccountNotFound
 	}
 
-	return r.saveAccounts(accounts)
+	return r.saveAccounts(ctx, accounts)
 }
 
-// loadAccounts loads accounts from the JSON file
-func (r *FileAccountRepository) loadAccounts() ([]accountData, error) {
-	filePath := filepath.Join(r.dataDir, "accounts.json")
-
-	// If file doesn't exist, return empty slice
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return []accountData{}, nil
+// readAccounts reads every record in the accounts file as stored, giving up if ctx is
+// done first
+func (r *FileAccountRepository) readAccounts(ctx context.Context) ([]accountData, error) {
+	name, other := AccountsFile, EncryptedAccountsFile
+	if r.masterKey != nil {
+		name, other = EncryptedAccountsFile, AccountsFile
 	}
 
-	data, err := os.ReadFile(filePath) // #nosec G304 - controlled file path within app data directory
+	// A missing file or a missing data directory (fresh install) both mean no accounts yet
+	data, err := readFileContext(ctx, filepath.Join(r.dataDir, name))
 	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return r.checkNoOtherAccountsFile(other)
+		}
 		return nil, err
 	}
 
+	if r.masterKey != nil {
+		if data, err = openAccountsEnvelope(data, r.masterKey); err != nil {
+			return nil, err
+		}
+	}
+
 	var accounts []accountData
 	if err := json.Unmarshal(data, &accounts); err != nil {
 		return nil, err
@@ -210,20 +454,173 @@ func (r *FileAccountRepository) loadAccounts() ([]accountData, error) {
 	return accounts, nil
 }
 
-// saveAccounts saves accounts to the JSON file
-func (r *FileAccountRepository) saveAccounts(accounts []accountData) error {
-	filePath := filepath.Join(r.dataDir, "accounts.json")
+// loadAccounts loads the records in the accounts file, collapsing records that share an
+// ID into one. The next write drops the duplicates from the file; until then
+// LoadWarnings reports them.
+func (r *FileAccountRepository) loadAccounts(ctx context.Context) ([]accountData, error) {
+	accounts, err := r.readAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accounts, _ = dedupeAccounts(accounts)
+	return accounts, nil
+}
+
+// dedupeAccounts keeps one record per ID, which can be repeated after a hand-edit or a
+// bad merge. The most recently used record wins, the later one on a tie, and takes the
+// place of the first record with its ID. It also returns, for each dropped record's
+// index, the index of the record kept instead.
+func dedupeAccounts(accounts []accountData) ([]accountData, map[int]int) {
+	winner := make(map[string]int, len(accounts))
+	for i, acc := range accounts {
+		if prev, ok := winner[acc.ID]; !ok || !lastUsedTime(acc).Before(lastUsedTime(accounts[prev])) {
+			winner[acc.ID] = i
+		}
+	}
+	if len(winner) == len(accounts) {
+		return accounts, nil
+	}
+
+	kept := make([]accountData, 0, len(winner))
+	dropped := make(map[int]int, len(accounts)-len(winner))
+	placed := make(map[string]bool, len(winner))
+	for i, acc := range accounts {
+		if w := winner[acc.ID]; w != i {
+			dropped[i] = w
+		}
+		if !placed[acc.ID] {
+			kept = append(kept, accounts[winner[acc.ID]])
+			placed[acc.ID] = true
+		}
+	}
+	return kept, dropped
+}
+
+// lastUsedTime returns when a record was last used, or the zero time if unreadable
+func lastUsedTime(data accountData) time.Time {
+	t, _ := time.Parse("2006-01-02T15:04:05Z07:00", data.LastUsed)
+	return t
+}
+
+// checkNoOtherAccountsFile returns no accounts when the accounts file this repository
+// doesn't use is missing too. If it exists, the accounts are stored in the other format,
+// and starting from an empty list would hide them.
+func (r *FileAccountRepository) checkNoOtherAccountsFile(name string) ([]accountData, error) {
+	if _, err := os.Stat(filepath.Join(r.dataDir, name)); err == nil {
+		if r.masterKey != nil {
+			return nil, ErrAccountsNotEncrypted
+		}
+		return nil, ErrAccountsEncrypted
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to check %s: %w", name, err)
+	}
+	return []accountData{}, nil
+}
+
+// saveAccounts atomically replaces the JSON file with the given accounts.
+// Marshalling happens before any file is touched, so a marshal failure leaves
+// neither a truncated accounts.json nor a stray temp file behind.
+func (r *FileAccountRepository) saveAccounts(ctx context.Context, accounts []accountData) error {
+	name := AccountsFile
 
 	data, err := json.MarshalIndent(accounts, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(filePath, data, 0o600)
+	if r.masterKey != nil {
+		name = EncryptedAccountsFile
+		if data, err = sealAccountsEnvelope(data, r.masterKey); err != nil {
+			return err
+		}
+	}
+
+	// Last chance to back out; once the write starts it runs to completion
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(filepath.Join(r.dataDir, name), data, 0o600)
+}
+
+// sealAccountsEnvelope encrypts the serialized account list under a new data key wrapped
+// by masterKey
+func sealAccountsEnvelope(data, masterKey []byte) ([]byte, error) {
+	envelope, err := domain.NewCredentialsEnvelope(accountsEnvelopeID, data, masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt accounts: %w", err)
+	}
+	return envelope.Serialize()
+}
+
+// openAccountsEnvelope decrypts an account list sealed by sealAccountsEnvelope
+func openAccountsEnvelope(data, masterKey []byte) ([]byte, error) {
+	envelope, err := domain.DeserializeCredentials(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", EncryptedAccountsFile, err)
+	}
+	if envelope.AccountID() != accountsEnvelopeID || !envelope.IsEnvelopeEncrypted() {
+		return nil, fmt.Errorf("%s does not hold an encrypted account list", EncryptedAccountsFile)
+	}
+	if err := envelope.Unwrap(masterKey); err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", EncryptedAccountsFile, err)
+	}
+	return envelope.Decrypt()
 }
 
-// convertToAccount converts accountData to domain.Account
+// convertToAccount converts accountData to domain.Account, naming every problem of a
+// malformed record in an error wrapping ErrInvalidAccountRecord
 func (r *FileAccountRepository) convertToAccount(data accountData) (*domain.Account, error) {
+	account, problems := r.checkAccountData(data)
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: record %q: %s", ErrInvalidAccountRecord, data.ID, strings.Join(problems, "; "))
+	}
+	return account, nil
+}
+
+// checkAccountData validates data against the accounts file schema and converts it. A
+// malformed record yields every problem found rather than just the first, so a
+// hand-edited file can be fixed in one pass.
+func (r *FileAccountRepository) checkAccountData(data accountData) (*domain.Account, []string) {
+	var problems []string
+	if data.ID == "" {
+		problems = append(problems, "missing id")
+	}
+	if data.Email == "" {
+		problems = append(problems, "missing email")
+	}
+	if data.UUID == "" {
+		problems = append(problems, "missing uuid")
+	}
+	for _, field := range []struct{ name, value string }{
+		{"created_at", data.CreatedAt},
+		{"last_used", data.LastUsed},
+	} {
+		if field.value == "" {
+			problems = append(problems, "missing "+field.name)
+		} else if _, err := time.Parse("2006-01-02T15:04:05Z07:00", field.value); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid %s %q", field.name, field.value))
+		}
+	}
+	if data.ArchivedAt != "" {
+		if _, err := time.Parse("2006-01-02T15:04:05Z07:00", data.ArchivedAt); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid archived_at %q", data.ArchivedAt))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+
+	// The remaining checks, such as the email and alias format, belong to the domain
+	account, err := r.buildAccount(data)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	return account, nil
+}
+
+// buildAccount reconstructs the domain.Account held in a record
+func (r *FileAccountRepository) buildAccount(data accountData) (*domain.Account, error) {
 	// Parse timestamps
 	createdAt, err := time.Parse("2006-01-02T15:04:05Z07:00", data.CreatedAt)
 	if err != nil {
@@ -236,12 +633,43 @@ func (r *FileAccountRepository) convertToAccount(data accountData) (*domain.Acco
 	}
 
 	// Reconstruct account with original values
-	return domain.ReconstructAccount(
+	account, err := domain.ReconstructAccount(
 		domain.AccountID(data.ID),
 		data.Email,
 		data.Alias,
 		data.UUID,
+		data.RawOAuth,
 		createdAt,
 		lastUsed,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tag := range data.Tags {
+		if err := account.AddTag(tag); err != nil {
+			return nil, err
+		}
+	}
+	if err := account.SetColor(data.Color); err != nil {
+		return nil, err
+	}
+	if err := account.SetLabel(data.Label); err != nil {
+		return nil, err
+	}
+	if err := account.SetDescription(data.Description); err != nil {
+		return nil, err
+	}
+	if err := account.SetEnvOverrides(data.Env); err != nil {
+		return nil, err
+	}
+	if data.ArchivedAt != "" {
+		archivedAt, err := time.Parse("2006-01-02T15:04:05Z07:00", data.ArchivedAt)
+		if err != nil {
+			return nil, err
+		}
+		account.Archive(archivedAt)
+	}
+
+	return account, nil
 }
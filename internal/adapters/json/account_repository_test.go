@@ -3,11 +3,15 @@ package json
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
 )
 
 func TestFileAccountRepository_Save(t *testing.T) {
@@ -104,6 +108,35 @@ func TestFileAccountRepository_FindByID(t *testing.T) {
 	}
 }
 
+func TestFileAccountRepository_FindByEmailNormalized(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	// A legacy file may hold a mixed-case email
+	legacy := `[{"id":"abc12345","email":"Work@Example.com","alias":"work","uuid":"uuid-work",` +
+		`"created_at":"2024-01-01T00:00:00Z","last_used":"2024-01-01T00:00:00Z"}]`
+	if err := os.WriteFile(filepath.Join(tmpDir, "accounts.json"), []byte(legacy), 0o600); err != nil {
+		t.Fatalf("Failed to write accounts file: %v", err)
+	}
+
+	repo := NewFileAccountRepository(tmpDir)
+	ctx := context.Background()
+
+	for _, email := range []domain.Email{"work@example.com", "WORK@EXAMPLE.COM", " work@example.com "} {
+		found, err := repo.FindByEmail(ctx, email)
+		if err != nil {
+			t.Errorf("FindByEmail(%q) error = %v", email, err)
+			continue
+		}
+		if found.Email() != "work@example.com" {
+			t.Errorf("FindByEmail(%q) email = %q, want normalized work@example.com", email, found.Email())
+		}
+	}
+}
+
 func TestFileAccountRepository_List(t *testing.T) {
 	// Setup temporary directory
 	tmpDir, err := os.MkdirTemp("", "ccx-test-*")
@@ -161,3 +194,435 @@ func TestFileAccountRepository_Delete(t *testing.T) {
 		t.Fatal("Expected account to be deleted")
 	}
 }
+
+func TestFileAccountRepository_MissingDataDir(t *testing.T) {
+	// Setup temporary directory and point the repo at a subdirectory that is never created
+	tmpDir, err := os.MkdirTemp("", "ccx-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	repo := NewFileAccountRepository(filepath.Join(tmpDir, "does-not-exist"))
+	ctx := context.Background()
+
+	// List should report no accounts rather than a stat error
+	accounts, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error listing a missing data dir, got: %v", err)
+	}
+	if len(accounts) != 0 {
+		t.Errorf("Expected 0 accounts, got %d", len(accounts))
+	}
+
+	// Finders should report not-found
+	if _, err := repo.FindByID(ctx, domain.AccountID("deadbeef")); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("FindByID() error = %v, want ErrAccountNotFound", err)
+	}
+	if _, err := repo.FindByEmail(ctx, domain.Email("missing@example.com")); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("FindByEmail() error = %v, want ErrAccountNotFound", err)
+	}
+	if _, err := repo.FindByAlias(ctx, "missing"); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("FindByAlias() error = %v, want ErrAccountNotFound", err)
+	}
+	if _, err := repo.FindByUUID(ctx, "missing"); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("FindByUUID() error = %v, want ErrAccountNotFound", err)
+	}
+
+	// Reads must not create the directory
+	if _, err := os.Stat(filepath.Join(tmpDir, "does-not-exist")); !os.IsNotExist(err) {
+		t.Error("Expected data directory to not be created by reads")
+	}
+}
+
+func TestFileAccountRepository_TagsRoundTrip(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	repo := NewFileAccountRepository(tmpDir)
+	ctx := context.Background()
+
+	account, _ := domain.NewAccount("test@example.com", "test", "uuid-test")
+	_ = account.AddTag("work")
+	_ = account.AddTag("client")
+	if err := repo.Save(ctx, account); err != nil {
+		t.Fatalf("Failed to save account: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, account.ID())
+	if err != nil {
+		t.Fatalf("Failed to find account: %v", err)
+	}
+	tags := found.Tags()
+	if len(tags) != 2 || tags[0] != "client" || tags[1] != "work" {
+		t.Errorf("Tags() = %v, want [client work]", tags)
+	}
+
+	// Untagged accounts omit the field entirely
+	untagged, _ := domain.NewAccount("plain@example.com", "", "uuid-plain")
+	if err := repo.Save(ctx, untagged); err != nil {
+		t.Fatalf("Failed to save account: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(tmpDir, "accounts.json")) // #nosec G304 - test file path
+	var stored []map[string]any
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("Failed to parse accounts file: %v", err)
+	}
+	if _, ok := stored[1]["tags"]; ok {
+		t.Error("Untagged account should not persist a tags field")
+	}
+}
+
+func TestFileAccountRepository_DisplayRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := NewFileAccountRepository(tmpDir)
+	ctx := context.Background()
+
+	account, _ := domain.NewAccount("test@example.com", "test", "uuid-test")
+	_ = account.SetColor("#ff8800")
+	_ = account.SetLabel("prod, be careful!")
+	_ = account.SetDescription("2FA via Yubikey, billing owner is Jane")
+	_ = account.SetEnvOverride("HTTPS_PROXY", "http://proxy:3128")
+	if err := repo.Save(ctx, account); err != nil {
+		t.Fatalf("Failed to save account: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, account.ID())
+	if err != nil {
+		t.Fatalf("Failed to find account: %v", err)
+	}
+	if found.Color() != "#ff8800" || found.Label() != "prod, be careful!" {
+		t.Errorf("Color/Label = %q/%q, want #ff8800/prod, be careful!", found.Color(), found.Label())
+	}
+	if found.Description() != "2FA via Yubikey, billing owner is Jane" {
+		t.Errorf("Description() = %q, want the saved description", found.Description())
+	}
+	if env := found.EnvOverrides(); len(env) != 1 || env["HTTPS_PROXY"] != "http://proxy:3128" {
+		t.Errorf("EnvOverrides() = %v, want the saved override", env)
+	}
+
+	// A hand-edited color outside the palette is rejected on load
+	data, _ := os.ReadFile(filepath.Join(tmpDir, "accounts.json")) // #nosec G304 - test file path
+	data = []byte(strings.Replace(string(data), "#ff8800", "purple", 1))
+	if err := os.WriteFile(filepath.Join(tmpDir, "accounts.json"), data, 0o600); err != nil {
+		t.Fatalf("Failed to write accounts file: %v", err)
+	}
+	if _, err := repo.FindByID(ctx, account.ID()); err == nil {
+		t.Error("FindByID() with invalid color error = nil, want error")
+	}
+}
+
+func TestFileAccountRepository_ArchiveRoundTrip(t *testing.T) {
+	repo := NewFileAccountRepository(t.TempDir())
+	ctx := context.Background()
+
+	account, _ := domain.NewAccount("test@example.com", "test", "uuid-test")
+	archivedAt := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	account.Archive(archivedAt)
+	if err := repo.Save(ctx, account); err != nil {
+		t.Fatalf("Failed to save account: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, account.ID())
+	if err != nil {
+		t.Fatalf("Failed to find account: %v", err)
+	}
+	if !found.Archived() || !found.ArchivedAt().Equal(archivedAt) {
+		t.Errorf("ArchivedAt() = %v, want %v", found.ArchivedAt(), archivedAt)
+	}
+
+	found.Unarchive()
+	if err := repo.Save(ctx, found); err != nil {
+		t.Fatalf("Failed to save account: %v", err)
+	}
+	if found, _ := repo.FindByID(ctx, account.ID()); found.Archived() {
+		t.Error("Archived() = true after unarchiving, want false")
+	}
+}
+
+func TestFileAccountRepository_RawOAuthRoundTrip(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	repo := NewFileAccountRepository(tmpDir)
+	ctx := context.Background()
+
+	blob := `{"accountUuid":"uuid-test","emailAddress":"test@example.com","organizationUuid":"org-123"}`
+	account, _ := domain.NewAccount("test@example.com", "test", "uuid-test")
+	if err := account.SetRawOAuth(json.RawMessage(blob)); err != nil {
+		t.Fatalf("Failed to set oauth blob: %v", err)
+	}
+	if err := repo.Save(ctx, account); err != nil {
+		t.Fatalf("Failed to save account: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, account.ID())
+	if err != nil {
+		t.Fatalf("Failed to find account: %v", err)
+	}
+	var got, want map[string]any
+	if err := json.Unmarshal(found.RawOAuth(), &got); err != nil {
+		t.Fatalf("Failed to parse stored blob %q: %v", found.RawOAuth(), err)
+	}
+	_ = json.Unmarshal([]byte(blob), &want)
+	if len(got) != len(want) || got["organizationUuid"] != "org-123" {
+		t.Errorf("RawOAuth() = %s, want %s", found.RawOAuth(), blob)
+	}
+}
+
+func TestFileAccountRepository_DuplicateIDs(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	// Simulate a hand-edited file with the same ID twice; the second was used more recently
+	content := `[
+  {"id": "dup12345", "email": "first@example.com", "alias": "first", "uuid": "uuid-1",
+   "created_at": "2025-01-01T00:00:00Z", "last_used": "2025-01-03T00:00:00Z"},
+  {"id": "other123", "email": "other@example.com", "alias": "other", "uuid": "uuid-o",
+   "created_at": "2025-01-01T00:00:00Z", "last_used": "2025-01-01T00:00:00Z"},
+  {"id": "dup12345", "email": "second@example.com", "alias": "second", "uuid": "uuid-2",
+   "created_at": "2025-01-02T00:00:00Z", "last_used": "2025-01-04T00:00:00Z"}
+]`
+	if err := os.WriteFile(filepath.Join(tmpDir, "accounts.json"), []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write accounts file: %v", err)
+	}
+
+	repo := NewFileAccountRepository(tmpDir)
+	ctx := context.Background()
+
+	found, err := repo.FindByID(ctx, "dup12345")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if found.Email() != "second@example.com" {
+		t.Errorf("FindByID() = %s, want the most recently used record", found.Email())
+	}
+	if _, err := repo.FindByAlias(ctx, "first"); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("FindByAlias() of the dropped record error = %v, want ErrAccountNotFound", err)
+	}
+
+	accounts, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(accounts) != 2 || accounts[0].ID() != "dup12345" || accounts[1].ID() != "other123" {
+		t.Errorf("List() = %v, want dup12345 in its first position and other123", accounts)
+	}
+
+	// The duplicate is reported until the next write drops it
+	issues, err := repo.(ports.AccountRecordValidator).LoadWarnings(ctx)
+	if err != nil {
+		t.Fatalf("LoadWarnings() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Index != 0 || issues[0].AccountID != "dup12345" ||
+		!strings.Contains(issues[0].Problems[0], "record 2") {
+		t.Errorf("LoadWarnings() = %+v, want record 0 superseded by record 2", issues)
+	}
+
+	account, _ := domain.NewAccount("third@example.com", "third", "uuid-3")
+	if err := repo.Save(ctx, account); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(tmpDir, "accounts.json"))
+	if n := strings.Count(string(data), `"dup12345"`); n != 1 {
+		t.Errorf("accounts file holds dup12345 %d times after Save(), want 1", n)
+	}
+	if issues, _ := repo.(ports.AccountRecordValidator).LoadWarnings(ctx); len(issues) != 0 {
+		t.Errorf("LoadWarnings() after Save() = %+v, want none", issues)
+	}
+}
+
+func TestFileAccountRepository_InvalidRecord(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// One good record and one hand-edited record missing its uuid and creation time
+	content := `[
+  {"id": "good1234", "email": "good@example.com", "alias": "good", "uuid": "uuid-1",
+   "created_at": "2025-01-01T00:00:00Z", "last_used": "2025-01-01T00:00:00Z"},
+  {"id": "bad12345", "email": "bad@example.com", "alias": "bad",
+   "last_used": "2025-01-02T00:00:00Z"}
+]`
+	if err := os.WriteFile(filepath.Join(tmpDir, "accounts.json"), []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write accounts file: %v", err)
+	}
+
+	repo := NewFileAccountRepository(tmpDir)
+	ctx := context.Background()
+
+	accounts, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v, want the bad record skipped", err)
+	}
+	if len(accounts) != 1 || accounts[0].ID() != "good1234" {
+		t.Fatalf("List() = %v, want only good1234", accounts)
+	}
+	if accounts[0].Description() != "" {
+		t.Errorf("Description() = %q, want empty for a record without one", accounts[0].Description())
+	}
+
+	_, err = repo.FindByID(ctx, "bad12345")
+	if !errors.Is(err, ErrInvalidAccountRecord) {
+		t.Fatalf("FindByID() error = %v, want %v", err, ErrInvalidAccountRecord)
+	}
+	if !strings.Contains(err.Error(), "missing uuid; missing created_at") {
+		t.Errorf("Error should name every problem, got %v", err)
+	}
+
+	issues, err := repo.(ports.AccountRecordValidator).LoadWarnings(ctx)
+	if err != nil {
+		t.Fatalf("LoadWarnings() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Index != 1 || issues[0].AccountID != "bad12345" || len(issues[0].Problems) != 2 {
+		t.Errorf("LoadWarnings() = %+v, want record 1 with two problems", issues)
+	}
+
+	// Saving another account keeps the bad record for the user to fix
+	account, _ := domain.NewAccount("third@example.com", "third", "uuid-3")
+	if err := repo.Save(ctx, account); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if issues, _ := repo.(ports.AccountRecordValidator).LoadWarnings(ctx); len(issues) != 1 {
+		t.Errorf("LoadWarnings() after Save() = %+v, want the bad record kept", issues)
+	}
+}
+
+func TestEncryptedFileAccountRepository_RoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	masterKey, _ := domain.GenerateMasterKey()
+	repo := NewEncryptedFileAccountRepository(tmpDir, masterKey)
+
+	account, _ := domain.NewAccount("secret@example.com", "work", "uuid-secret-org")
+	if err := repo.Save(ctx, account); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(tmpDir, EncryptedAccountsFile)) // #nosec G304 - test file path
+	if err != nil {
+		t.Fatalf("Failed to read encrypted file: %v", err)
+	}
+	for _, secret := range []string{"secret@example.com", "uuid-secret-org"} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("%s contains %q in plaintext", EncryptedAccountsFile, secret)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, AccountsFile)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("%s should not be written, stat error = %v", AccountsFile, err)
+	}
+
+	// A fresh repository with the same key reads it back through every finder
+	repo = NewEncryptedFileAccountRepository(tmpDir, masterKey)
+	if found, err := repo.FindByEmail(ctx, "SECRET@example.com"); err != nil || found.UUID() != "uuid-secret-org" {
+		t.Errorf("FindByEmail() = %v, %v", found, err)
+	}
+	if _, err := repo.FindByUUID(ctx, "uuid-secret-org"); err != nil {
+		t.Errorf("FindByUUID() error = %v", err)
+	}
+	if _, err := repo.FindByAlias(ctx, "work"); err != nil {
+		t.Errorf("FindByAlias() error = %v", err)
+	}
+	if _, err := repo.FindByID(ctx, account.ID()); err != nil {
+		t.Errorf("FindByID() error = %v", err)
+	}
+	if err := repo.Delete(ctx, account.ID()); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	if accounts, err := repo.List(ctx); err != nil || len(accounts) != 0 {
+		t.Errorf("List() after Delete = %d accounts, %v; want none", len(accounts), err)
+	}
+}
+
+func TestEncryptedFileAccountRepository_Rejects(t *testing.T) {
+	ctx := context.Background()
+	masterKey, _ := domain.GenerateMasterKey()
+	account, _ := domain.NewAccount("secret@example.com", "work", "uuid-secret")
+
+	t.Run("wrong master key", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		_ = NewEncryptedFileAccountRepository(tmpDir, masterKey).Save(ctx, account)
+
+		otherKey, _ := domain.GenerateMasterKey()
+		if _, err := NewEncryptedFileAccountRepository(tmpDir, otherKey).List(ctx); err == nil {
+			t.Error("List() with the wrong master key error = nil, want error")
+		}
+	})
+
+	t.Run("credential file swapped in", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		creds, _ := domain.NewCredentialsEnvelope("abc12345", []byte(`[]`), masterKey)
+		data, _ := creds.Serialize()
+		_ = os.WriteFile(filepath.Join(tmpDir, EncryptedAccountsFile), data, 0o600)
+
+		if _, err := NewEncryptedFileAccountRepository(tmpDir, masterKey).List(ctx); err == nil {
+			t.Error("List() of a credential envelope error = nil, want error")
+		}
+	})
+
+	t.Run("plaintext not migrated", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		_ = NewFileAccountRepository(tmpDir).Save(ctx, account)
+
+		if _, err := NewEncryptedFileAccountRepository(tmpDir, masterKey).List(ctx); !errors.Is(err, ErrAccountsNotEncrypted) {
+			t.Errorf("List() error = %v, want ErrAccountsNotEncrypted", err)
+		}
+	})
+
+	t.Run("plaintext repository over encrypted data", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		_ = NewEncryptedFileAccountRepository(tmpDir, masterKey).Save(ctx, account)
+
+		if _, err := NewFileAccountRepository(tmpDir).List(ctx); !errors.Is(err, ErrAccountsEncrypted) {
+			t.Errorf("List() error = %v, want ErrAccountsEncrypted", err)
+		}
+	})
+}
+
+func TestEncryptAccountsFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	masterKey, _ := domain.GenerateMasterKey()
+
+	// Nothing to migrate yet
+	if err := EncryptAccountsFile(ctx, tmpDir, masterKey); err != nil {
+		t.Fatalf("EncryptAccountsFile() with no accounts error = %v", err)
+	}
+
+	plain := NewFileAccountRepository(tmpDir)
+	first, _ := domain.NewAccount("first@example.com", "first", "uuid-1")
+	second, _ := domain.NewAccount("second@example.com", "", "uuid-2")
+	_ = plain.Save(ctx, first)
+	_ = plain.Save(ctx, second)
+
+	if err := EncryptAccountsFile(ctx, tmpDir, masterKey); err != nil {
+		t.Fatalf("EncryptAccountsFile() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, AccountsFile)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("%s should be removed after migration, stat error = %v", AccountsFile, err)
+	}
+
+	accounts, err := NewEncryptedFileAccountRepository(tmpDir, masterKey).List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(accounts) != 2 || accounts[0].ID() != first.ID() || accounts[1].ID() != second.ID() {
+		t.Errorf("List() after migration = %v, want both accounts in save order", accounts)
+	}
+
+	// Migrating again is a no-op, but a new plaintext file never overwrites encrypted data
+	if err := EncryptAccountsFile(ctx, tmpDir, masterKey); err != nil {
+		t.Errorf("EncryptAccountsFile() again error = %v", err)
+	}
+	_ = os.WriteFile(filepath.Join(tmpDir, AccountsFile), []byte(`[]`), 0o600)
+	if err := EncryptAccountsFile(ctx, tmpDir, masterKey); err == nil {
+		t.Error("EncryptAccountsFile() over an existing encrypted file error = nil, want error")
+	}
+}
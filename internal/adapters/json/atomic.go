@@ -0,0 +1,67 @@
+package json
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic writes data to path without ever exposing a partially written file.
+// The data is written and synced to a uniquely named temp file in the same directory,
+// then renamed over the target; rename is atomic on a single filesystem, so readers see
+// either the old contents or the new ones, and concurrent writers never share a temp
+// file. The temp file is removed if any step fails.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeAtomic(path, data, perm, nil)
+}
+
+// replaceFileAtomic is writeFileAtomic for an existing file whose info is like: the
+// new file gets the same permission bits and, where the platform allows, the same owner.
+func replaceFileAtomic(path string, data []byte, like fs.FileInfo) error {
+	return writeAtomic(path, data, like.Mode().Perm(), func(f *os.File) error {
+		return chownLike(f, like)
+	})
+}
+
+// writeAtomic implements writeFileAtomic, calling prepare (if not nil) on the temp
+// file before its contents are written
+func writeAtomic(path string, data []byte, perm os.FileMode, prepare func(f *os.File) error) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := f.Name()
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	// CreateTemp always uses 0600
+	if err = f.Chmod(perm); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to prepare temp file: %w", err)
+	}
+	if prepare != nil {
+		if err = prepare(f); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to prepare temp file: %w", err)
+		}
+	}
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	return nil
+}
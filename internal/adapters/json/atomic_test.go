@@ -0,0 +1,171 @@
+package json
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-atomic-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	path := filepath.Join(tmpDir, "data.json")
+
+	// A stale temp file from an earlier crash must not leak into the result
+	if err := os.WriteFile(path+".tmp", []byte("partial garbage that is longer"), 0o600); err != nil {
+		t.Fatalf("Failed to write stale temp file: %v", err)
+	}
+
+	if err := writeFileAtomic(path, []byte(`{"v":1}`), 0o600); err != nil {
+		t.Fatalf("writeFileAtomic() error = %v", err)
+	}
+	if err := writeFileAtomic(path, []byte(`{"v":2}`), 0o600); err != nil {
+		t.Fatalf("writeFileAtomic() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 - test file path
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(data) != `{"v":2}` {
+		t.Errorf("File contents = %s, want %s", data, `{"v":2}`)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat file: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("File permissions = %o, want 0600", info.Mode().Perm())
+	}
+
+	if leftover := tempFiles(t, path); len(leftover) != 0 {
+		t.Errorf("Temp files %v should not remain after a successful write", leftover)
+	}
+}
+
+func TestWriteFileAtomic_ConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	contents := []string{`{"writer":"a","padding":"longer than the other writer"}`, `{"writer":"b"}`}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*len(contents)*20)
+	for _, content := range contents {
+		wg.Add(1)
+		go func(content string) {
+			defer wg.Done()
+			for range 20 {
+				errs <- writeFileAtomic(path, []byte(content), 0o600)
+			}
+		}(content)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("writeFileAtomic() error = %v", err)
+		}
+	}
+
+	// Writers sharing a temp file could interleave into contents neither of them wrote
+	data, err := os.ReadFile(path) // #nosec G304 - test file path
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(data) != contents[0] && string(data) != contents[1] {
+		t.Errorf("File contents = %s, want one writer's contents", data)
+	}
+	if leftover := tempFiles(t, path); len(leftover) != 0 {
+		t.Errorf("Temp files %v should not remain after concurrent writes", leftover)
+	}
+}
+
+// tempFiles returns the temp files writeFileAtomic may have left beside path
+func tempFiles(t *testing.T, path string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(path + ".*.tmp")
+	if err != nil {
+		t.Fatalf("Failed to list temp files: %v", err)
+	}
+	return matches
+}
+
+func TestWriteFileAtomic_FailureLeavesTargetIntact(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-atomic-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	// Renaming a file over a non-empty directory fails, simulating an interrupted replace
+	target := filepath.Join(tmpDir, "target")
+	if err := os.MkdirAll(filepath.Join(target, "child"), 0o700); err != nil {
+		t.Fatalf("Failed to create target directory: %v", err)
+	}
+
+	if err := writeFileAtomic(target, []byte("new"), 0o600); err == nil {
+		t.Fatal("writeFileAtomic() error = nil, want error")
+	}
+
+	if leftover := tempFiles(t, target); len(leftover) != 0 {
+		t.Errorf("Temp files %v should be removed after a failed write", leftover)
+	}
+	if info, err := os.Stat(target); err != nil || !info.IsDir() {
+		t.Error("Target should be left untouched after a failed write")
+	}
+}
+
+func TestFileAccountRepository_SaveIsAtomic(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	repo := NewFileAccountRepository(tmpDir)
+	ctx := context.Background()
+
+	first, _ := domain.NewAccount("first@example.com", "first", "uuid-first")
+	if err := repo.Save(ctx, first); err != nil {
+		t.Fatalf("Failed to save account: %v", err)
+	}
+
+	// Simulate a crash that left a truncated temp file behind
+	tmpPath := filepath.Join(tmpDir, "accounts.json.tmp")
+	if err := os.WriteFile(tmpPath, []byte(`[{"id":`), 0o600); err != nil {
+		t.Fatalf("Failed to write truncated temp file: %v", err)
+	}
+
+	// Existing data is still readable despite the partial temp file
+	accounts, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(accounts) != 1 {
+		t.Fatalf("Expected 1 account, got %d", len(accounts))
+	}
+
+	second, _ := domain.NewAccount("second@example.com", "second", "uuid-second")
+	if err := repo.Save(ctx, second); err != nil {
+		t.Fatalf("Failed to save account: %v", err)
+	}
+
+	accounts, err = repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(accounts) != 2 {
+		t.Errorf("Expected 2 accounts, got %d", len(accounts))
+	}
+	if leftover := tempFiles(t, filepath.Join(tmpDir, "accounts.json")); len(leftover) != 0 {
+		t.Errorf("Temp files %v should not remain after a successful save", leftover)
+	}
+}
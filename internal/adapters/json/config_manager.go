@@ -3,40 +3,166 @@ package json
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 
 	"github.com/evanschultz/ccx/internal/domain"
 	"github.com/evanschultz/ccx/internal/ports"
 )
 
+// ErrConfigSymlink is returned when .claude.json is a symlink and the manager was
+// created WithRefuseSymlinks
+var ErrConfigSymlink = errors.New("config file is a symlink")
+
+// ErrConfigReadOnly is returned when .claude.json cannot be written because of its permissions
+var ErrConfigReadOnly = errors.New("config file is read-only")
+
+// ConfigFileName is the name of Claude's config file within its config directory
+const ConfigFileName = ".claude.json"
+
 // BasicConfigManager implements ConfigManager using Claude's .claude.json files
 type BasicConfigManager struct {
-	configDir string
-	mu        sync.RWMutex
+	configPath     string
+	refuseSymlinks bool
+	stateDir       string // ccx data directory recording the session keys ccx wrote, or ""
+	mu             sync.RWMutex
+	sessionMu      sync.Mutex // Serializes session replacements with their recorded state
+}
+
+// ConfigManagerOption configures optional BasicConfigManager behavior
+type ConfigManagerOption func(*BasicConfigManager)
+
+// WithRefuseSymlinks makes SetCurrentAccount fail with ErrConfigSymlink instead of
+// writing through a symlinked .claude.json, for users who want ccx to leave a
+// dotfiles-managed config alone
+func WithRefuseSymlinks() ConfigManagerOption {
+	return func(m *BasicConfigManager) {
+		m.refuseSymlinks = true
+	}
+}
+
+// WithSessionState records which session keys SetCredentials wrote in SessionStateFile
+// under dataDir, so keys Claude config may also hold for other reasons, such as
+// primaryApiKey, are only cleared on a switch when ccx wrote them. Without it every
+// allowlisted session key is cleared.
+func WithSessionState(dataDir string) ConfigManagerOption {
+	return func(m *BasicConfigManager) {
+		m.stateDir = dataDir
+	}
 }
 
-// oauthAccount represents the OAuth account section in Claude config
+// oauthAccount represents the OAuth account section in Claude config.
+// Fields ccx doesn't manage (organization details and the like) are kept in extra
+// so they survive being rewritten on a switch.
 type oauthAccount struct {
-	EmailAddress string `json:"emailAddress"`
-	AccountUUID  string `json:"accountUuid"`
+	EmailAddress string
+	AccountUUID  string
+	extra        map[string]json.RawMessage
+}
+
+// Keys of the oauthAccount fields managed by ccx
+const (
+	oauthEmailKey = "emailAddress"
+	oauthUUIDKey  = "accountUuid"
+)
+
+// SessionStateFile is the file in the ccx data directory recording which session keys
+// SetCredentials last wrote into Claude config
+const SessionStateFile = "claude_session.json"
+
+// legacySessionKeysKey is the config key earlier versions used to record the session
+// keys they wrote; it is removed, with the allowlisted keys it names, on the next write
+const legacySessionKeysKey = "ccxSessionKeys"
+
+// sessionKeys are the only top-level keys SetCredentials copies from a credential
+// payload. Anything else a stored or imported payload holds is ignored, so it can't
+// overwrite unrelated Claude settings.
+var sessionKeys = []string{"sessionKey", "session_key", "sessionKeyExpiresAt", "claudeAiOauth", "primaryApiKey"}
+
+// knownSessionKeys are the session keys that only ever hold a session. They are cleared
+// on every switch, whoever wrote them; the other session keys only when ccx wrote them.
+var knownSessionKeys = []string{"sessionKey", "session_key", "sessionKeyExpiresAt", "claudeAiOauth"}
+
+// UnmarshalJSON decodes the known fields and retains all others
+func (o *oauthAccount) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	o.EmailAddress, o.AccountUUID = "", ""
+	if raw, ok := fields[oauthEmailKey]; ok {
+		if err := json.Unmarshal(raw, &o.EmailAddress); err != nil {
+			return fmt.Errorf("invalid %s: %w", oauthEmailKey, err)
+		}
+		delete(fields, oauthEmailKey)
+	}
+	if raw, ok := fields[oauthUUIDKey]; ok {
+		if err := json.Unmarshal(raw, &o.AccountUUID); err != nil {
+			return fmt.Errorf("invalid %s: %w", oauthUUIDKey, err)
+		}
+		delete(fields, oauthUUIDKey)
+	}
+
+	o.extra = fields
+	return nil
 }
 
-// NewBasicConfigManager creates a new basic config manager
-func NewBasicConfigManager(configDir string) ports.ConfigManager {
-	return &BasicConfigManager{
-		configDir: configDir,
+// MarshalJSON encodes the known fields together with the retained extras
+func (o oauthAccount) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]json.RawMessage, len(o.extra)+2)
+	for key, value := range o.extra {
+		fields[key] = value
+	}
+
+	email, err := json.Marshal(o.EmailAddress)
+	if err != nil {
+		return nil, err
+	}
+	uuid, err := json.Marshal(o.AccountUUID)
+	if err != nil {
+		return nil, err
 	}
+	fields[oauthEmailKey] = email
+	fields[oauthUUIDKey] = uuid
+
+	return json.Marshal(fields)
+}
+
+// NewBasicConfigManager creates a new basic config manager for the .claude.json in configDir
+func NewBasicConfigManager(configDir string, opts ...ConfigManagerOption) ports.ConfigManager {
+	return NewBasicConfigManagerWithPath(filepath.Join(configDir, ConfigFileName), opts...)
+}
+
+// NewBasicConfigManagerWithPath creates a basic config manager for the config file at
+// configPath, whatever it is named, for setups that keep Claude's config outside its
+// usual directory such as a devcontainer mount
+func NewBasicConfigManagerWithPath(configPath string, opts ...ConfigManagerOption) ports.ConfigManager {
+	m := &BasicConfigManager{
+		configPath: configPath,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
 // GetCurrentAccount reads the current account from Claude config
-func (m *BasicConfigManager) GetCurrentAccount(_ context.Context) (*domain.Account, error) {
+func (m *BasicConfigManager) GetCurrentAccount(ctx context.Context) (*domain.Account, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	configPath := filepath.Join(m.configDir, ".claude.json")
+	configPath := m.configPath
 
 	// If config doesn't exist, return nil (no current account)
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
@@ -44,7 +170,7 @@ func (m *BasicConfigManager) GetCurrentAccount(_ context.Context) (*domain.Accou
 	}
 
 	// Read config file
-	data, err := os.ReadFile(configPath) // #nosec G304 - controlled config path within app directory
+	data, err := readFileContext(ctx, configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
@@ -77,44 +203,200 @@ func (m *BasicConfigManager) GetCurrentAccount(_ context.Context) (*domain.Accou
 	if err != nil {
 		return nil, fmt.Errorf("failed to create account from config: %w", err)
 	}
+	if err := account.SetRawOAuth(oauthData); err != nil {
+		return nil, fmt.Errorf("failed to capture oauthAccount: %w", err)
+	}
 
 	return account, nil
 }
 
-// SetCurrentAccount updates Claude config with the new account
-func (m *BasicConfigManager) SetCurrentAccount(_ context.Context, account *domain.Account) error {
+// SetCurrentAccount updates Claude config with the new account; a nil account removes
+// the oauthAccount section and the session SetCredentials wrote. The file is replaced
+// atomically, keeping its mode and owner; a symlinked config is written through to its
+// target so the link itself survives.
+func (m *BasicConfigManager) SetCurrentAccount(ctx context.Context, account *domain.Account) error {
+	if account == nil {
+		return m.replaceSession(ctx, nil, func(config map[string]json.RawMessage) {
+			delete(config, "oauthAccount")
+		})
+	}
+
+	return m.updateConfig(ctx, func(config map[string]json.RawMessage) error {
+
+		// Restore the account's captured OAuth blob when we have one; otherwise update
+		// only the managed fields of the existing OAuth account, keeping the rest
+		var oauth oauthAccount
+		if raw := account.RawOAuth(); len(raw) > 0 {
+			if err := json.Unmarshal(raw, &oauth); err != nil {
+				return fmt.Errorf("failed to parse stored oauthAccount: %w", err)
+			}
+		} else if existing, ok := config["oauthAccount"]; ok {
+			if err := json.Unmarshal(existing, &oauth); err != nil {
+				return fmt.Errorf("failed to parse existing oauthAccount: %w", err)
+			}
+		}
+		oauth.EmailAddress = string(account.Email())
+		oauth.AccountUUID = account.UUID()
+
+		oauthData, err := json.Marshal(oauth)
+		if err != nil {
+			return fmt.Errorf("failed to marshal oauth account: %w", err)
+		}
+
+		// Update config with new OAuth account
+		config["oauthAccount"] = oauthData
+		return nil
+	})
+}
+
+// SetCredentials decrypts creds and writes their allowlisted session keys into Claude
+// config, replacing the previous account's session: the keys ccx last wrote, and the
+// known session keys, are removed first so none of them outlive a switch. Other keys in
+// the payload are ignored; oauthAccount is owned by SetCurrentAccount.
+// Passphrase-protected credentials return domain.ErrPassphraseRequired.
+func (m *BasicConfigManager) SetCredentials(ctx context.Context, creds *domain.Credentials) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	plaintext, err := creds.Decrypt()
+	if err != nil {
+		return fmt.Errorf("failed to decrypt credentials: %w", err)
+	}
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(plaintext, &payload); err != nil {
+		return fmt.Errorf("failed to parse credentials for account %s: %w", creds.AccountID(), domain.RedactError(err))
+	}
+
+	session := make(map[string]json.RawMessage, len(sessionKeys))
+	for _, key := range sessionKeys {
+		if value, ok := payload[key]; ok {
+			session[key] = value
+		}
+	}
+	return m.replaceSession(ctx, session, nil)
+}
+
+// replaceSession clears the previous session from Claude config, writes session in its
+// place, applies update if set, and records the keys written
+func (m *BasicConfigManager) replaceSession(ctx context.Context, session map[string]json.RawMessage, update func(config map[string]json.RawMessage)) error {
+	m.sessionMu.Lock()
+	defer m.sessionMu.Unlock()
+
+	owned, err := m.loadSessionState()
+	if err != nil {
+		return err
+	}
+
+	err = m.updateConfig(ctx, func(config map[string]json.RawMessage) error {
+		clearSession(config, owned)
+		for key, value := range session {
+			config[key] = value
+		}
+		if update != nil {
+			update(config)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	written := slices.Sorted(maps.Keys(session))
+	return m.saveSessionState(written)
+}
+
+// clearSession removes the known session keys, the keys ccx recorded writing, and
+// anything recorded by earlier versions in the config itself
+func clearSession(config map[string]json.RawMessage, owned []string) {
+	if raw, ok := config[legacySessionKeysKey]; ok {
+		var keys []string
+		if json.Unmarshal(raw, &keys) == nil {
+			owned = append(owned, keys...)
+		}
+		delete(config, legacySessionKeysKey)
+	}
+	for _, key := range owned {
+		if slices.Contains(sessionKeys, key) {
+			delete(config, key)
+		}
+	}
+	for _, key := range knownSessionKeys {
+		delete(config, key)
+	}
+}
+
+// loadSessionState returns the session keys ccx last wrote. Without a state directory
+// every session key is treated as written by ccx.
+func (m *BasicConfigManager) loadSessionState() ([]string, error) {
+	if m.stateDir == "" {
+		return sessionKeys, nil
+	}
+	data, err := os.ReadFile(filepath.Join(m.stateDir, SessionStateFile)) // #nosec G304 - path within the ccx data directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session state: %w", err)
+	}
+	var keys []string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse session state: %w", err)
+	}
+	return keys, nil
+}
+
+// saveSessionState records the session keys ccx wrote
+func (m *BasicConfigManager) saveSessionState(keys []string) error {
+	if m.stateDir == "" {
+		return nil
+	}
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session state: %w", err)
+	}
+	if err := os.MkdirAll(m.stateDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(m.stateDir, SessionStateFile), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write session state: %w", err)
+	}
+	return nil
+}
+
+// updateConfig reads .claude.json (or starts an empty config), applies update, and
+// atomically writes the result back
+func (m *BasicConfigManager) updateConfig(ctx context.Context, update func(config map[string]json.RawMessage) error) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	configPath := filepath.Join(m.configDir, ".claude.json")
+	configPath, info, err := m.resolveConfigPath(m.configPath)
+	if err != nil {
+		return err
+	}
+
+	// Fail up front with a fix rather than after rewriting the config in memory
+	if info != nil && info.Mode().Perm()&0o200 == 0 {
+		return readOnlyConfigError(configPath)
+	}
 
 	// Read existing config or create new one
 	var config map[string]json.RawMessage
-	// #nosec G304 - controlled config path within app directory
-	if data, err := os.ReadFile(configPath); err == nil {
+	if data, err := readFileContext(ctx, configPath); err == nil {
 		if err := json.Unmarshal(data, &config); err != nil {
 			return fmt.Errorf("failed to parse existing config: %w", err)
 		}
-	} else if !os.IsNotExist(err) {
+	} else if !errors.Is(err, fs.ErrNotExist) {
 		return fmt.Errorf("failed to read existing config: %w", err)
-	} else {
-		config = make(map[string]json.RawMessage)
 	}
-
-	// Create OAuth account data
-	oauth := oauthAccount{
-		EmailAddress: string(account.Email()),
-		AccountUUID:  account.UUID(),
+	if config == nil {
+		config = make(map[string]json.RawMessage)
 	}
 
-	oauthData, err := json.Marshal(oauth)
-	if err != nil {
-		return fmt.Errorf("failed to marshal oauth account: %w", err)
+	if err := update(config); err != nil {
+		return err
 	}
 
-	// Update config with new OAuth account
-	config["oauthAccount"] = oauthData
-
 	// Write updated config
 	updatedData, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
@@ -122,14 +404,61 @@ func (m *BasicConfigManager) SetCurrentAccount(_ context.Context, account *domai
 	}
 
 	// Ensure config directory exists
-	if err := os.MkdirAll(m.configDir, 0o700); err != nil {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o700); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
 	// Write to file
-	if err := os.WriteFile(configPath, updatedData, 0o600); err != nil {
+	if info == nil {
+		err = writeFileAtomic(configPath, updatedData, 0o600)
+	} else {
+		err = replaceFileAtomic(configPath, updatedData, info)
+	}
+	if errors.Is(err, fs.ErrPermission) {
+		return readOnlyConfigError(configPath)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
 	return nil
 }
+
+// resolveConfigPath returns the file that holds the config at configPath, following a
+// symlink unless symlinks are refused, along with its info (nil if it does not exist yet)
+func (m *BasicConfigManager) resolveConfigPath(configPath string) (string, fs.FileInfo, error) {
+	info, err := os.Lstat(configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return configPath, nil, nil
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to stat config file: %w", err)
+	}
+	if info.Mode()&fs.ModeSymlink == 0 {
+		return configPath, info, nil
+	}
+
+	target, err := filepath.EvalSymlinks(configPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve config symlink %s: %w", configPath, err)
+	}
+	if m.refuseSymlinks {
+		return "", nil, fmt.Errorf("%w: %s points to %s; edit the target directly or replace the link with a regular file",
+			ErrConfigSymlink, configPath, target)
+	}
+
+	info, err = os.Stat(target)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to stat config file: %w", err)
+	}
+	return target, info, nil
+}
+
+// readOnlyConfigError explains how to make the config at path writable again
+func readOnlyConfigError(path string) error {
+	return fmt.Errorf("%w: %s; make it and its directory writable, e.g. chmod u+w %s", ErrConfigReadOnly, path, path)
+}
@@ -3,8 +3,10 @@ package json
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/evanschultz/ccx/internal/domain"
@@ -186,3 +188,439 @@ func TestBasicConfigManager_UpdateExistingConfig(t *testing.T) {
 		t.Errorf("Expected other_setting to be preserved, got '%v'", config["other_setting"])
 	}
 }
+
+func TestBasicConfigManager_PreservesOAuthAccountFields(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-config-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	existingConfig := `{
+  "oauthAccount": {
+    "emailAddress": "old@example.com",
+    "accountUuid": "old-uuid",
+    "organizationName": "Acme",
+    "organizationUuid": "org-123",
+    "emailVerified": true,
+    "billing": {"plan": "max"}
+  },
+  "numStartups": 42
+}`
+	configPath := filepath.Join(tmpDir, ".claude.json")
+	if err := os.WriteFile(configPath, []byte(existingConfig), 0o600); err != nil {
+		t.Fatalf("Failed to create existing config: %v", err)
+	}
+
+	configManager := NewBasicConfigManager(tmpDir)
+	account, _ := domain.NewAccount("new@example.com", "new", "new-uuid")
+	if err := configManager.SetCurrentAccount(context.Background(), account); err != nil {
+		t.Fatalf("Failed to set current account: %v", err)
+	}
+
+	configData, err := os.ReadFile(configPath) // #nosec G304 - test file with controlled path
+	if err != nil {
+		t.Fatalf("Failed to read updated config: %v", err)
+	}
+
+	var config struct {
+		OAuthAccount map[string]any `json:"oauthAccount"`
+		NumStartups  int            `json:"numStartups"`
+	}
+	if err := json.Unmarshal(configData, &config); err != nil {
+		t.Fatalf("Failed to unmarshal updated config: %v", err)
+	}
+
+	want := map[string]any{
+		"emailAddress":     "new@example.com",
+		"accountUuid":      "new-uuid",
+		"organizationName": "Acme",
+		"organizationUuid": "org-123",
+		"emailVerified":    true,
+	}
+	for key, value := range want {
+		if config.OAuthAccount[key] != value {
+			t.Errorf("oauthAccount.%s = %v, want %v", key, config.OAuthAccount[key], value)
+		}
+	}
+	if billing, ok := config.OAuthAccount["billing"].(map[string]any); !ok || billing["plan"] != "max" {
+		t.Errorf("oauthAccount.billing = %v, want nested object preserved", config.OAuthAccount["billing"])
+	}
+	if config.NumStartups != 42 {
+		t.Errorf("numStartups = %d, want 42", config.NumStartups)
+	}
+
+	// The current account still reads back from the rewritten config
+	current, err := configManager.GetCurrentAccount(context.Background())
+	if err != nil || current == nil || current.Email() != "new@example.com" {
+		t.Errorf("GetCurrentAccount() = %v, %v; want new@example.com", current, err)
+	}
+}
+
+func TestBasicConfigManager_RestoresStoredOAuthAccount(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-config-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	// Current config belongs to a different organization
+	existingConfig := `{
+  "oauthAccount": {
+    "emailAddress": "other@example.com",
+    "accountUuid": "other-uuid",
+    "organizationName": "Other Org",
+    "organizationUuid": "org-other"
+  }
+}`
+	configPath := filepath.Join(tmpDir, ".claude.json")
+	if err := os.WriteFile(configPath, []byte(existingConfig), 0o600); err != nil {
+		t.Fatalf("Failed to create existing config: %v", err)
+	}
+
+	configManager := NewBasicConfigManager(tmpDir)
+	account, _ := domain.NewAccount("work@example.com", "work", "work-uuid")
+	blob := `{"emailAddress":"work@example.com","accountUuid":"work-uuid","organizationName":"Acme","organizationUuid":"org-acme"}`
+	if err := account.SetRawOAuth(json.RawMessage(blob)); err != nil {
+		t.Fatalf("Failed to set oauth blob: %v", err)
+	}
+	if err := configManager.SetCurrentAccount(context.Background(), account); err != nil {
+		t.Fatalf("Failed to set current account: %v", err)
+	}
+
+	configData, err := os.ReadFile(configPath) // #nosec G304 - test file with controlled path
+	if err != nil {
+		t.Fatalf("Failed to read updated config: %v", err)
+	}
+	var config struct {
+		OAuthAccount map[string]any `json:"oauthAccount"`
+	}
+	if err := json.Unmarshal(configData, &config); err != nil {
+		t.Fatalf("Failed to unmarshal updated config: %v", err)
+	}
+
+	want := map[string]any{
+		"emailAddress":     "work@example.com",
+		"accountUuid":      "work-uuid",
+		"organizationName": "Acme",
+		"organizationUuid": "org-acme",
+	}
+	if len(config.OAuthAccount) != len(want) {
+		t.Errorf("oauthAccount = %v, want %v", config.OAuthAccount, want)
+	}
+	for key, value := range want {
+		if config.OAuthAccount[key] != value {
+			t.Errorf("oauthAccount.%s = %v, want %v", key, config.OAuthAccount[key], value)
+		}
+	}
+
+	// Reading the current account captures the blob for later restores
+	current, err := configManager.GetCurrentAccount(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get current account: %v", err)
+	}
+	var captured map[string]any
+	if err := json.Unmarshal(current.RawOAuth(), &captured); err != nil {
+		t.Fatalf("Failed to parse captured blob %q: %v", current.RawOAuth(), err)
+	}
+	if captured["organizationUuid"] != "org-acme" {
+		t.Errorf("captured oauthAccount = %v, want organizationUuid org-acme", captured)
+	}
+}
+
+func TestBasicConfigManager_WritesThroughSymlink(t *testing.T) {
+	configDir := t.TempDir()
+	dotfilesDir := t.TempDir()
+	ctx := context.Background()
+
+	target := filepath.Join(dotfilesDir, "claude.json")
+	// A group-readable mode, unlike the 0600 ccx uses for files it creates
+	// #nosec G306 - test file
+	if err := os.WriteFile(target, []byte(`{"theme":"dark"}`), 0o644); err != nil {
+		t.Fatalf("Failed to write target: %v", err)
+	}
+	link := filepath.Join(configDir, ".claude.json")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+
+	account, _ := domain.NewAccount("linked@example.com", "", "uuid-linked")
+	if err := NewBasicConfigManager(configDir).SetCurrentAccount(ctx, account); err != nil {
+		t.Fatalf("SetCurrentAccount() error = %v", err)
+	}
+
+	// The link is untouched and the target holds the new account with its mode kept
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf(".claude.json should still be a symlink (err %v)", err)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		t.Fatalf("Failed to stat target: %v", err)
+	}
+	if info.Mode().Perm() != 0o644 {
+		t.Errorf("Target permissions = %o, want 0644", info.Mode().Perm())
+	}
+	data, err := os.ReadFile(target) // #nosec G304 - test file with controlled path
+	if err != nil {
+		t.Fatalf("Failed to read target: %v", err)
+	}
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("Failed to parse target: %v", err)
+	}
+	if string(config["theme"]) != `"dark"` {
+		t.Errorf("Existing settings were lost: %s", data)
+	}
+	if _, err := os.Stat(target + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Temp file should not remain after a successful write")
+	}
+}
+
+func TestBasicConfigManager_RefuseSymlinks(t *testing.T) {
+	configDir := t.TempDir()
+	target := filepath.Join(t.TempDir(), "claude.json")
+	if err := os.WriteFile(target, []byte(`{}`), 0o600); err != nil {
+		t.Fatalf("Failed to write target: %v", err)
+	}
+	if err := os.Symlink(target, filepath.Join(configDir, ".claude.json")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+
+	account, _ := domain.NewAccount("linked@example.com", "", "uuid-linked")
+	err := NewBasicConfigManager(configDir, WithRefuseSymlinks()).SetCurrentAccount(context.Background(), account)
+	if !errors.Is(err, ErrConfigSymlink) {
+		t.Fatalf("SetCurrentAccount() error = %v, want ErrConfigSymlink", err)
+	}
+
+	data, _ := os.ReadFile(target) // #nosec G304 - test file with controlled path
+	if string(data) != `{}` {
+		t.Errorf("Target was modified: %s", data)
+	}
+}
+
+func TestBasicConfigManager_ReadOnlyConfig(t *testing.T) {
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, ".claude.json")
+	if err := os.WriteFile(configPath, []byte(`{}`), 0o400); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	account, _ := domain.NewAccount("readonly@example.com", "", "uuid-readonly")
+	err := NewBasicConfigManager(configDir).SetCurrentAccount(context.Background(), account)
+	if !errors.Is(err, ErrConfigReadOnly) {
+		t.Fatalf("SetCurrentAccount() error = %v, want ErrConfigReadOnly", err)
+	}
+	if !strings.Contains(err.Error(), "chmod u+w") {
+		t.Errorf("Error should suggest chmod, got: %v", err)
+	}
+
+	data, _ := os.ReadFile(configPath) // #nosec G304 - test file with controlled path
+	if string(data) != `{}` {
+		t.Errorf("Read-only config was modified: %s", data)
+	}
+}
+
+func TestBasicConfigManager_SetCredentials(t *testing.T) {
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, ".claude.json")
+	existing := `{"oauthAccount": {"emailAddress": "work@example.com", "accountUuid": "uuid-work"}, "theme": "dark", "sessionKey": "old"}`
+	if err := os.WriteFile(configPath, []byte(existing), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	account, _ := domain.NewAccount("work@example.com", "work", "uuid-work")
+	creds, _ := domain.NewCredentials(account.ID(), []byte(`{"sessionKey": "new", "oauthAccount": {"emailAddress": "other@example.com"}}`))
+
+	ctx := context.Background()
+	configManager := NewBasicConfigManager(configDir)
+	if err := configManager.SetCredentials(ctx, creds); err != nil {
+		t.Fatalf("SetCredentials() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(configPath) // #nosec G304 - test file with controlled path
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+	if config["sessionKey"] != "new" {
+		t.Errorf("sessionKey = %v, want new", config["sessionKey"])
+	}
+	if config["theme"] != "dark" {
+		t.Errorf("theme = %v, want dark to be preserved", config["theme"])
+	}
+
+	// The OAuth account is owned by SetCurrentAccount, not the stored session
+	current, err := configManager.GetCurrentAccount(ctx)
+	if err != nil || current == nil || current.Email() != account.Email() {
+		t.Errorf("GetCurrentAccount() = %v, %v; want %s", current, err, account.Email())
+	}
+
+	// Passphrase-protected credentials loaded from storage cannot be written without
+	// the passphrase
+	protected, _ := domain.NewCredentialsWithPassphrase(account.ID(), []byte(`{"sessionKey": "secret"}`), []byte("passphrase"))
+	stored, _ := protected.Serialize()
+	protected, _ = domain.DeserializeCredentials(stored)
+	if err := configManager.SetCredentials(ctx, protected); !errors.Is(err, domain.ErrPassphraseRequired) {
+		t.Errorf("SetCredentials() error = %v, want ErrPassphraseRequired", err)
+	}
+
+	// A payload that is not JSON is refused without quoting any of it
+	malformed, _ := domain.NewCredentials(account.ID(), []byte(`sk-ant-do-not-log`))
+	err = configManager.SetCredentials(ctx, malformed)
+	if err == nil || strings.Contains(err.Error(), "sk-ant") || strings.Contains(err.Error(), "'s'") {
+		t.Errorf("SetCredentials() error = %v, want an error that does not quote the payload", err)
+	}
+}
+
+func TestBasicConfigManager_SetCredentialsReplacesSession(t *testing.T) {
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, ".claude.json")
+	// A session written before ccx recorded its keys, and keys recorded in the config
+	// by earlier versions
+	existing := `{"theme": "dark", "claudeAiOauth": {"accessToken": "legacy"}, "ccxSessionKeys": ["sessionKeyExpiresAt"], "sessionKeyExpiresAt": 1}`
+	if err := os.WriteFile(configPath, []byte(existing), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	configManager := NewBasicConfigManager(configDir, WithSessionState(dataDir))
+	readConfig := func() map[string]interface{} {
+		t.Helper()
+		data, _ := os.ReadFile(configPath) // #nosec G304 - test file with controlled path
+		var config map[string]interface{}
+		if err := json.Unmarshal(data, &config); err != nil {
+			t.Fatalf("Failed to parse config: %v", err)
+		}
+		return config
+	}
+
+	// Only allowlisted session keys are taken from the payload
+	first, _ := domain.NewCredentials("aaaa1111", []byte(`{"sessionKey": "key-a", "primaryApiKey": "api-a", "theme": "light", "orgToken": "org-a"}`))
+	if err := configManager.SetCredentials(ctx, first); err != nil {
+		t.Fatalf("SetCredentials(a) error = %v", err)
+	}
+	config := readConfig()
+	if config["sessionKey"] != "key-a" || config["primaryApiKey"] != "api-a" {
+		t.Errorf("Config after SetCredentials(a) = %v, want a's session", config)
+	}
+	for _, key := range []string{"claudeAiOauth", "orgToken", "sessionKeyExpiresAt", legacySessionKeysKey} {
+		if _, ok := config[key]; ok {
+			t.Errorf("%s = %v after SetCredentials(a), want it absent", key, config[key])
+		}
+	}
+	if config["theme"] != "dark" {
+		t.Errorf("theme = %v, want dark to be preserved", config["theme"])
+	}
+	state, err := os.ReadFile(filepath.Join(dataDir, SessionStateFile)) // #nosec G304 - test file with controlled path
+	if err != nil || string(state) != `["primaryApiKey","sessionKey"]` {
+		t.Errorf("Session state = %s, %v; want the keys written", state, err)
+	}
+
+	// The second account has neither of the first one's session fields
+	second, _ := domain.NewCredentials("bbbb2222", []byte(`{"claudeAiOauth": {"accessToken": "token-b"}}`))
+	if err := configManager.SetCredentials(ctx, second); err != nil {
+		t.Fatalf("SetCredentials(b) error = %v", err)
+	}
+	config = readConfig()
+	for _, key := range []string{"sessionKey", "primaryApiKey"} {
+		if _, ok := config[key]; ok {
+			t.Errorf("%s = %v, want a's field removed", key, config[key])
+		}
+	}
+	if oauth, _ := config["claudeAiOauth"].(map[string]interface{}); oauth["accessToken"] != "token-b" {
+		t.Errorf("claudeAiOauth = %v, want b's token", config["claudeAiOauth"])
+	}
+
+	// Clearing the current account takes its session with it
+	if err := configManager.SetCurrentAccount(ctx, nil); err != nil {
+		t.Fatalf("SetCurrentAccount(nil) error = %v", err)
+	}
+	config = readConfig()
+	if _, ok := config["claudeAiOauth"]; ok {
+		t.Errorf("claudeAiOauth = %v after clearing the account, want it removed", config["claudeAiOauth"])
+	}
+	if config["theme"] != "dark" {
+		t.Errorf("theme = %v, want dark to be preserved", config["theme"])
+	}
+}
+
+// TestBasicConfigManager_SetCredentialsKeepsForeignKeys tests that a session key ccx
+// didn't write, such as the user's own API key, survives a switch
+func TestBasicConfigManager_SetCredentialsKeepsForeignKeys(t *testing.T) {
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, ".claude.json")
+	if err := os.WriteFile(configPath, []byte(`{"primaryApiKey": "users-own"}`), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	configManager := NewBasicConfigManager(configDir, WithSessionState(t.TempDir()))
+	creds, _ := domain.NewCredentials("aaaa1111", []byte(`{"sessionKey": "key-a"}`))
+	if err := configManager.SetCredentials(context.Background(), creds); err != nil {
+		t.Fatalf("SetCredentials() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(configPath) // #nosec G304 - test file with controlled path
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+	if config["primaryApiKey"] != "users-own" || config["sessionKey"] != "key-a" {
+		t.Errorf("Config = %v, want the user's API key kept alongside the session", config)
+	}
+}
+
+func TestBasicConfigManager_ClearCurrentAccount(t *testing.T) {
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, ".claude.json")
+	existing := `{"oauthAccount": {"emailAddress": "work@example.com", "accountUuid": "uuid-work"}, "theme": "dark"}`
+	if err := os.WriteFile(configPath, []byte(existing), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	ctx := context.Background()
+	configManager := NewBasicConfigManager(configDir)
+	if err := configManager.SetCurrentAccount(ctx, nil); err != nil {
+		t.Fatalf("SetCurrentAccount(nil) error = %v", err)
+	}
+
+	if current, err := configManager.GetCurrentAccount(ctx); current != nil || err != nil {
+		t.Errorf("GetCurrentAccount() = %v, %v; want nil, nil", current, err)
+	}
+	data, _ := os.ReadFile(configPath) // #nosec G304 - test file with controlled path
+	if !strings.Contains(string(data), `"theme": "dark"`) {
+		t.Errorf("Other settings were not preserved: %s", data)
+	}
+}
+
+func TestBasicConfigManager_WithPath(t *testing.T) {
+	// A config kept under another name, in a directory that does not exist yet
+	configDir := filepath.Join(t.TempDir(), "mnt", "claude")
+	configPath := filepath.Join(configDir, "claude-config.json")
+
+	ctx := context.Background()
+	configManager := NewBasicConfigManagerWithPath(configPath)
+	if current, err := configManager.GetCurrentAccount(ctx); current != nil || err != nil {
+		t.Fatalf("GetCurrentAccount() = %v, %v; want nil, nil before the file exists", current, err)
+	}
+
+	account, _ := domain.NewAccount("work@example.com", "work", "uuid-work")
+	if err := configManager.SetCurrentAccount(ctx, account); err != nil {
+		t.Fatalf("SetCurrentAccount() error = %v", err)
+	}
+
+	if _, err := os.Stat(configPath); err != nil {
+		t.Fatalf("Config was not written to %s: %v", configPath, err)
+	}
+	if _, err := os.Stat(filepath.Join(configDir, ConfigFileName)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected no %s next to the override, got err %v", ConfigFileName, err)
+	}
+
+	current, err := NewBasicConfigManagerWithPath(configPath).GetCurrentAccount(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentAccount() error = %v", err)
+	}
+	if current == nil || current.Email() != account.Email() {
+		t.Errorf("GetCurrentAccount() = %v, want %s", current, account.Email())
+	}
+}
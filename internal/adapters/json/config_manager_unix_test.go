@@ -0,0 +1,46 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package json
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestBasicConfigManager_PreservesOwner(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("Changing file ownership requires root")
+	}
+
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, ".claude.json")
+	if err := os.WriteFile(configPath, []byte(`{}`), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	const uid, gid = 1234, 5678
+	if err := os.Chown(configPath, uid, gid); err != nil {
+		t.Fatalf("Failed to chown config: %v", err)
+	}
+
+	account, _ := domain.NewAccount("owner@example.com", "", "uuid-owner")
+	if err := NewBasicConfigManager(configDir).SetCurrentAccount(context.Background(), account); err != nil {
+		t.Fatalf("SetCurrentAccount() error = %v", err)
+	}
+
+	info, err := os.Stat(configPath)
+	if err != nil {
+		t.Fatalf("Failed to stat config: %v", err)
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		t.Skip("No ownership information available")
+	}
+	if stat.Uid != uid || stat.Gid != gid {
+		t.Errorf("Owner = %d:%d, want %d:%d", stat.Uid, stat.Gid, uid, gid)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"sync"
 
 	"github.com/evanschultz/ccx/internal/domain"
@@ -14,61 +17,193 @@ import (
 
 // FileCredentialStore implements CredentialStore using encrypted files
 type FileCredentialStore struct {
-	dataDir string
-	mu      sync.RWMutex
+	dataDir   string
+	masterKey []byte // Enables envelope encryption when set
+	mu        sync.RWMutex
+}
+
+// Ensure FileCredentialStore can enumerate its credentials and check their permissions at compile time
+var (
+	_ ports.CredentialLister   = (*FileCredentialStore)(nil)
+	_ ports.PermissionVerifier = (*FileCredentialStore)(nil)
+)
+
+// ErrCredentialPermissions is returned when Store finds the credentials directory
+// accessible by other users, as happens when a backup is restored with its original modes
+var ErrCredentialPermissions = errors.New("credential directory is accessible by other users")
+
+// Permissions credentials are kept with. Group and other users get no access.
+const (
+	credentialDirPerm  fs.FileMode = 0o700
+	credentialFilePerm fs.FileMode = 0o600
+)
+
+// CredentialStoreOption configures optional FileCredentialStore behavior
+type CredentialStoreOption func(*FileCredentialStore)
+
+// WithMasterKey enables envelope encryption: credentials encrypted with the legacy
+// account-derived key are re-sealed under a data key wrapped by masterKey when stored,
+// and envelope-encrypted credentials are unwrapped when retrieved. Legacy files stay
+// readable until they are next stored. See LoadOrCreateMasterKey.
+func WithMasterKey(masterKey []byte) CredentialStoreOption {
+	return func(s *FileCredentialStore) {
+		s.masterKey = append([]byte(nil), masterKey...)
+	}
 }
 
 // NewFileCredentialStore creates a new file-based credential store
-func NewFileCredentialStore(dataDir string) ports.CredentialStore {
-	return &FileCredentialStore{
+func NewFileCredentialStore(dataDir string, opts ...CredentialStoreOption) ports.CredentialStore {
+	s := &FileCredentialStore{
 		dataDir: dataDir,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// seal re-encrypts legacy credentials under envelope encryption when a master key is
+// set. Passphrase-protected and already envelope-encrypted credentials are kept as is.
+func (s *FileCredentialStore) seal(creds *domain.Credentials) (*domain.Credentials, error) {
+	if s.masterKey == nil || creds.IsPassphraseProtected() || creds.IsEnvelopeEncrypted() {
+		return creds, nil
+	}
+	plaintext, err := creds.Decrypt()
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewCredentialsEnvelope(creds.AccountID(), plaintext, s.masterKey)
 }
 
 // Store securely saves credentials to an encrypted file
-func (s *FileCredentialStore) Store(_ context.Context, creds *domain.Credentials) error {
+func (s *FileCredentialStore) Store(ctx context.Context, creds *domain.Credentials) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	unlock, err := lockDataDir(ctx, s.dataDir, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Ensure credentials directory exists
 	credsDir := filepath.Join(s.dataDir, "credentials")
-	if err := os.MkdirAll(credsDir, 0o700); err != nil { // More restrictive permissions for credentials
+	if err := os.MkdirAll(credsDir, credentialDirPerm); err != nil { // More restrictive permissions for credentials
 		return fmt.Errorf("failed to create credentials directory: %w", err)
 	}
 
+	// MkdirAll leaves an existing directory alone, so refuse to add secrets to a loose one
+	if err := checkPrivateDir(credsDir); err != nil {
+		return err
+	}
+
+	sealed, err := s.seal(creds)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt credentials: %w", err)
+	}
+
 	// Serialize credentials using domain's built-in encryption
-	data, err := creds.Serialize()
+	data, err := sealed.Serialize()
 	if err != nil {
 		return fmt.Errorf("failed to serialize credentials: %w", err)
 	}
 
 	// Write to file named by account ID
-	filename := fmt.Sprintf("%s.json", creds.AccountID())
-	filePath := filepath.Join(credsDir, filename)
+	filePath, err := s.credentialPath(creds.AccountID())
+	if err != nil {
+		return err
+	}
 
-	if err := os.WriteFile(filePath, data, 0o600); err != nil { // Restrictive permissions
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filePath, data, credentialFilePerm); err != nil { // Restrictive permissions
 		return fmt.Errorf("failed to write credentials file: %w", err)
 	}
 
+	// WriteFile applies the umask to new files and keeps the mode of existing ones
+	if err := os.Chmod(filePath, credentialFilePerm); err != nil {
+		return fmt.Errorf("failed to restrict credentials file permissions: %w", err)
+	}
+
+	return nil
+}
+
+// credentialPath returns the file holding accountID's credentials. IDs containing a
+// path separator are rejected so that they can't name a file outside the directory.
+func (s *FileCredentialStore) credentialPath(accountID domain.AccountID) (string, error) {
+	id := string(accountID)
+	if id == "" || id == ".." || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("%w: %q", domain.ErrInvalidAccountID, id)
+	}
+	return filepath.Join(s.dataDir, "credentials", id+".json"), nil
+}
+
+// checkPrivateDir returns ErrCredentialPermissions if group or other users can access dir.
+// Windows does not report Unix permission bits, so nothing is checked there.
+func checkPrivateDir(dir string) error {
+	issue, err := checkPermissions(dir, credentialDirPerm)
+	if err != nil {
+		return err
+	}
+	if issue != nil {
+		return fmt.Errorf("%w: %s has mode %o; run chmod 700 on it", ErrCredentialPermissions, dir, issue.Mode.Perm())
+	}
 	return nil
 }
 
+// checkPermissions reports path if group or other users have any access to it. Missing
+// paths and all paths on Windows are reported as fine.
+func checkPermissions(path string, want fs.FileMode) (*ports.PermissionIssue, error) {
+	if runtime.GOOS == "windows" {
+		return nil, nil
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check permissions of %s: %w", path, err)
+	}
+	if info.Mode().Perm()&0o077 == 0 {
+		return nil, nil
+	}
+	return &ports.PermissionIssue{Path: path, Mode: info.Mode(), Want: want}, nil
+}
+
 // Retrieve gets credentials for an account
-func (s *FileCredentialStore) Retrieve(_ context.Context, accountID domain.AccountID) (*domain.Credentials, error) {
+func (s *FileCredentialStore) Retrieve(ctx context.Context, accountID domain.AccountID) (*domain.Credentials, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	unlock, err := lockDataDir(ctx, s.dataDir, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	// Build file path
-	filename := fmt.Sprintf("%s.json", accountID)
-	filePath := filepath.Join(s.dataDir, "credentials", filename)
+	filePath, err := s.credentialPath(accountID)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check if file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return nil, errors.New("credentials not found")
+		return nil, domain.ErrCredentialsNotFound
 	}
 
 	// Read file
-	data, err := os.ReadFile(filePath) // #nosec G304 - controlled file path within app data directory
+	data, err := readFileContext(ctx, filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read credentials file: %w", err)
 	}
@@ -79,21 +214,42 @@ func (s *FileCredentialStore) Retrieve(_ context.Context, accountID domain.Accou
 		return nil, fmt.Errorf("failed to deserialize credentials: %w", err)
 	}
 
+	if creds.IsEnvelopeEncrypted() {
+		if s.masterKey == nil {
+			return nil, domain.ErrMasterKeyRequired
+		}
+		if err := creds.Unwrap(s.masterKey); err != nil {
+			return nil, fmt.Errorf("failed to unwrap credentials: %w", err)
+		}
+	}
+
 	return creds, nil
 }
 
 // Delete removes credentials for an account
-func (s *FileCredentialStore) Delete(_ context.Context, accountID domain.AccountID) error {
+func (s *FileCredentialStore) Delete(ctx context.Context, accountID domain.AccountID) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	unlock, err := lockDataDir(ctx, s.dataDir, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Build file path
-	filename := fmt.Sprintf("%s.json", accountID)
-	filePath := filepath.Join(s.dataDir, "credentials", filename)
+	filePath, err := s.credentialPath(accountID)
+	if err != nil {
+		return err
+	}
 
 	// Check if file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return errors.New("credentials not found")
+		return domain.ErrCredentialsNotFound
 	}
 
 	// Remove file
@@ -103,3 +259,88 @@ func (s *FileCredentialStore) Delete(_ context.Context, accountID domain.Account
 
 	return nil
 }
+
+// ListAccountIDs returns the IDs of all accounts with a credentials file, sorted.
+// A missing credentials directory yields an empty list.
+func (s *FileCredentialStore) ListAccountIDs(ctx context.Context) ([]domain.AccountID, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	unlock, err := lockDataDir(ctx, s.dataDir, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entries, err := os.ReadDir(filepath.Join(s.dataDir, "credentials"))
+	if os.IsNotExist(err) {
+		return []domain.AccountID{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials directory: %w", err)
+	}
+
+	// ReadDir sorts by filename, so IDs come back sorted
+	ids := make([]domain.AccountID, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		ids = append(ids, domain.AccountID(strings.TrimSuffix(name, ".json")))
+	}
+
+	return ids, nil
+}
+
+// VerifyPermissions reports the data directory, credentials directory, and credential
+// files that group or other users can access. Loose files are fixed by the next Store
+// of those credentials, and a loose credentials directory makes Store fail until it is
+// fixed.
+func (s *FileCredentialStore) VerifyPermissions(ctx context.Context) ([]ports.PermissionIssue, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	unlock, err := lockDataDir(ctx, s.dataDir, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	credsDir := filepath.Join(s.dataDir, "credentials")
+	paths := []string{s.dataDir, credsDir}
+	wants := []fs.FileMode{credentialDirPerm, credentialDirPerm}
+
+	entries, err := os.ReadDir(credsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read credentials directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		paths = append(paths, filepath.Join(credsDir, entry.Name()))
+		wants = append(wants, credentialFilePerm)
+	}
+
+	issues := []ports.PermissionIssue{}
+	for i, path := range paths {
+		issue, err := checkPermissions(path, wants[i])
+		if err != nil {
+			return nil, err
+		}
+		if issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+
+	return issues, nil
+}
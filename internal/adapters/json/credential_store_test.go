@@ -2,7 +2,11 @@ package json
 
 import (
 	"context"
+	"errors"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/evanschultz/ccx/internal/domain"
@@ -83,8 +87,8 @@ func TestFileCredentialStore_Retrieve(t *testing.T) {
 	// Test retrieving non-existent credentials
 	nonExistentID := domain.GenerateAccountID()
 	_, err = store.Retrieve(ctx, nonExistentID)
-	if err == nil {
-		t.Fatal("Expected error when retrieving non-existent credentials")
+	if !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Fatalf("Expected ErrCredentialsNotFound when retrieving non-existent credentials, got: %v", err)
 	}
 }
 
@@ -121,7 +125,178 @@ func TestFileCredentialStore_Delete(t *testing.T) {
 
 	// Verify credentials are gone
 	_, err = store.Retrieve(ctx, accountID)
-	if err == nil {
-		t.Fatal("Expected credentials to be deleted")
+	if !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Fatalf("Expected credentials to be deleted, got: %v", err)
+	}
+}
+
+func TestFileCredentialStore_RejectsPathIDs(t *testing.T) {
+	tmpDir := t.TempDir()
+	dataDir := filepath.Join(tmpDir, "data")
+	store := NewFileCredentialStore(dataDir)
+	ctx := context.Background()
+
+	for _, id := range []domain.AccountID{"../../escaped", "sub/abc12345", `..\escaped`, ".."} {
+		creds, _ := domain.NewCredentials(id, []byte(`{"sessionKey":"sk-test"}`))
+		if err := store.Store(ctx, creds); !errors.Is(err, domain.ErrInvalidAccountID) {
+			t.Errorf("Store(%q) error = %v, want ErrInvalidAccountID", id, err)
+		}
+		if _, err := store.Retrieve(ctx, id); !errors.Is(err, domain.ErrInvalidAccountID) {
+			t.Errorf("Retrieve(%q) error = %v, want ErrInvalidAccountID", id, err)
+		}
+		if err := store.Delete(ctx, id); !errors.Is(err, domain.ErrInvalidAccountID) {
+			t.Errorf("Delete(%q) error = %v, want ErrInvalidAccountID", id, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "escaped.json")); !os.IsNotExist(err) {
+		t.Errorf("escaped.json was written outside the data directory (stat error = %v)", err)
+	}
+}
+
+func TestFileCredentialStore_ListAccountIDs(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-creds-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	store := &FileCredentialStore{dataDir: tmpDir}
+	ctx := context.Background()
+
+	// Missing directory yields an empty list
+	ids, err := store.ListAccountIDs(ctx)
+	if err != nil {
+		t.Fatalf("ListAccountIDs() error = %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("ListAccountIDs() = %v, want empty", ids)
+	}
+
+	for _, id := range []domain.AccountID{"bbbb2222", "aaaa1111"} {
+		creds, _ := domain.NewCredentials(id, []byte("data"))
+		if err := store.Store(ctx, creds); err != nil {
+			t.Fatalf("Failed to store credentials: %v", err)
+		}
+	}
+	// Stray files are ignored
+	_ = os.WriteFile(filepath.Join(tmpDir, "credentials", "notes.txt"), []byte("x"), 0o600)
+
+	ids, err = store.ListAccountIDs(ctx)
+	if err != nil {
+		t.Fatalf("ListAccountIDs() error = %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "aaaa1111" || ids[1] != "bbbb2222" {
+		t.Errorf("ListAccountIDs() = %v, want [aaaa1111 bbbb2222]", ids)
+	}
+}
+
+func TestFileCredentialStore_MasterKey(t *testing.T) {
+	dataDir := t.TempDir()
+	ctx := context.Background()
+	masterKey, err := LoadOrCreateMasterKey(dataDir)
+	if err != nil {
+		t.Fatalf("LoadOrCreateMasterKey() error = %v", err)
+	}
+
+	// A file written before envelope encryption stays readable
+	accountID := domain.GenerateAccountID()
+	legacy, _ := domain.NewCredentials(accountID, []byte(`{"sessionKey":"legacy"}`))
+	if err := NewFileCredentialStore(dataDir).Store(ctx, legacy); err != nil {
+		t.Fatalf("Failed to store legacy credentials: %v", err)
+	}
+	store := NewFileCredentialStore(dataDir, WithMasterKey(masterKey))
+	retrieved, err := store.Retrieve(ctx, accountID)
+	if err != nil {
+		t.Fatalf("Retrieve() of legacy credentials error = %v", err)
+	}
+	if retrieved.IsEnvelopeEncrypted() {
+		t.Error("Legacy credentials should not be converted on read")
+	}
+
+	// Storing them again seals them under the master key
+	if err := store.Store(ctx, retrieved); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	path := filepath.Join(dataDir, "credentials", string(accountID)+".json")
+	data, _ := os.ReadFile(path) // #nosec G304 - test file with controlled path
+	if !strings.Contains(string(data), `"wrappedKey"`) {
+		t.Errorf("Stored credentials are not envelope-encrypted: %s", data)
+	}
+
+	retrieved, err = store.Retrieve(ctx, accountID)
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if plaintext, err := retrieved.Decrypt(); err != nil || string(plaintext) != `{"sessionKey":"legacy"}` {
+		t.Errorf("Decrypt() = %s, %v; want the original session", plaintext, err)
+	}
+
+	// Without the master key the credentials cannot be used
+	if _, err := NewFileCredentialStore(dataDir).Retrieve(ctx, accountID); !errors.Is(err, domain.ErrMasterKeyRequired) {
+		t.Errorf("Retrieve() without master key error = %v, want ErrMasterKeyRequired", err)
+	}
+	otherKey, _ := domain.GenerateMasterKey()
+	if _, err := NewFileCredentialStore(dataDir, WithMasterKey(otherKey)).Retrieve(ctx, accountID); err == nil {
+		t.Error("Retrieve() with the wrong master key error = nil, want error")
+	}
+
+	// Passphrase-protected credentials are stored as they are
+	protectedID := domain.GenerateAccountID()
+	protected, _ := domain.NewCredentialsWithPassphrase(protectedID, []byte(`{"sessionKey":"p"}`), []byte("passphrase"))
+	if err := store.Store(ctx, protected); err != nil {
+		t.Fatalf("Store() of protected credentials error = %v", err)
+	}
+	if found, err := store.Retrieve(ctx, protectedID); err != nil || !found.IsPassphraseProtected() || found.IsEnvelopeEncrypted() {
+		t.Errorf("Retrieve() of protected credentials = %v, %v; want passphrase protection kept", found, err)
+	}
+}
+
+func TestFileCredentialStore_Permissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows does not report Unix permission bits")
+	}
+
+	tmpDir := t.TempDir()
+	// TempDir is subject to the umask
+	// #nosec G302 - directories need the execute bit
+	if err := os.Chmod(tmpDir, 0o700); err != nil {
+		t.Fatalf("Failed to restrict temp dir: %v", err)
+	}
+	store, _ := NewFileCredentialStore(tmpDir).(*FileCredentialStore)
+	ctx := context.Background()
+
+	creds, _ := domain.NewCredentials("abc12345", []byte(`{"sessionKey": "k"}`))
+	if err := store.Store(ctx, creds); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	issues, err := store.VerifyPermissions(ctx)
+	if err != nil || len(issues) != 0 {
+		t.Fatalf("VerifyPermissions() = %v, %v; want no issues", issues, err)
+	}
+
+	// A file restored with a loose mode is reported, then tightened by the next store
+	credsDir := filepath.Join(tmpDir, "credentials")
+	filePath := filepath.Join(credsDir, "abc12345.json")
+	_ = os.Chmod(filePath, 0o644) // #nosec G302 - test loosens the file on purpose
+	issues, err = store.VerifyPermissions(ctx)
+	if err != nil || len(issues) != 1 || issues[0].Path != filePath || issues[0].Want != 0o600 {
+		t.Fatalf("VerifyPermissions() = %+v, %v; want the credentials file", issues, err)
+	}
+	if err := store.Store(ctx, creds); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	if info, _ := os.Stat(filePath); info.Mode().Perm() != 0o600 {
+		t.Errorf("credentials file mode = %o, want 600", info.Mode().Perm())
+	}
+
+	// A loose directory is reported and refused by Store
+	_ = os.Chmod(credsDir, 0o755) // #nosec G302 - test loosens the directory on purpose
+	issues, err = store.VerifyPermissions(ctx)
+	if err != nil || len(issues) != 1 || issues[0].Path != credsDir || !issues[0].Mode.IsDir() {
+		t.Fatalf("VerifyPermissions() = %+v, %v; want the credentials directory", issues, err)
+	}
+	if err := store.Store(ctx, creds); !errors.Is(err, ErrCredentialPermissions) {
+		t.Errorf("Store() error = %v, want ErrCredentialPermissions", err)
 	}
 }
@@ -0,0 +1,47 @@
+package json
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// checkContext returns an error wrapping ctx.Err() once ctx is done, so adapters can
+// return before touching the filesystem
+func checkContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context cancelled: %w", err)
+	}
+	return nil
+}
+
+// readFileContext reads path like os.ReadFile but returns as soon as ctx is done.
+// The read runs in its own goroutine; on a stuck filesystem it is left to finish on
+// its own and its result is discarded.
+func readFileContext(ctx context.Context, path string) ([]byte, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// A context that can never be cancelled gains nothing from the goroutine
+	if ctx.Done() == nil {
+		return os.ReadFile(path) // #nosec G304 - callers pass controlled paths
+	}
+
+	type readResult struct {
+		data []byte
+		err  error
+	}
+	done := make(chan readResult, 1) // Buffered so an abandoned read does not leak blocked
+	go func() {
+		data, err := os.ReadFile(path) // #nosec G304 - callers pass controlled paths
+		done <- readResult{data: data, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.data, result.err
+	case <-ctx.Done():
+		return nil, checkContext(ctx)
+	}
+}
@@ -0,0 +1,99 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestReadFileContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := os.WriteFile(path, []byte(`{"ok":true}`), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	// Both a plain and a cancellable context read the file
+	cancellable, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, ctx := range []context.Context{context.Background(), cancellable} {
+		data, err := readFileContext(ctx, path)
+		if err != nil {
+			t.Fatalf("readFileContext() error = %v", err)
+		}
+		if string(data) != `{"ok":true}` {
+			t.Errorf("readFileContext() = %s", data)
+		}
+	}
+
+	// Missing files still report fs.ErrNotExist
+	if _, err := readFileContext(cancellable, filepath.Join(t.TempDir(), "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("readFileContext() error = %v, want not exist", err)
+	}
+
+	cancel()
+	if _, err := readFileContext(cancellable, path); !errors.Is(err, context.Canceled) {
+		t.Errorf("readFileContext() error = %v, want context.Canceled", err)
+	}
+}
+
+// TestAdapters_CancelledContext tests that every file-based adapter returns the context
+// error without touching the filesystem
+func TestAdapters_CancelledContext(t *testing.T) {
+	dataDir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	accounts := NewFileAccountRepository(dataDir)
+	credentials := NewFileCredentialStore(dataDir)
+	settings := NewFileSettingsRepository(dataDir)
+	config := NewBasicConfigManager(dataDir)
+	history := NewFileHistoryRepository(dataDir)
+
+	account, _ := domain.NewAccount("cancel@example.com", "cancel", "uuid-cancel")
+	creds, _ := domain.NewCredentials(account.ID(), []byte(`{"sessionKey":"k"}`))
+	lister, ok := credentials.(*FileCredentialStore)
+	if !ok {
+		t.Fatal("NewFileCredentialStore() should return *FileCredentialStore")
+	}
+
+	calls := map[string]func() error{
+		"accounts.Save":         func() error { return accounts.Save(ctx, account) },
+		"accounts.FindByID":     func() error { _, err := accounts.FindByID(ctx, account.ID()); return err },
+		"accounts.FindByEmail":  func() error { _, err := accounts.FindByEmail(ctx, account.Email()); return err },
+		"accounts.FindByAlias":  func() error { _, err := accounts.FindByAlias(ctx, "cancel"); return err },
+		"accounts.FindByUUID":   func() error { _, err := accounts.FindByUUID(ctx, "uuid-cancel"); return err },
+		"accounts.List":         func() error { _, err := accounts.List(ctx); return err },
+		"accounts.Delete":       func() error { return accounts.Delete(ctx, account.ID()) },
+		"credentials.Store":     func() error { return credentials.Store(ctx, creds) },
+		"credentials.Retrieve":  func() error { _, err := credentials.Retrieve(ctx, account.ID()); return err },
+		"credentials.Delete":    func() error { return credentials.Delete(ctx, account.ID()) },
+		"credentials.List":      func() error { _, err := lister.ListAccountIDs(ctx); return err },
+		"settings.Load":         func() error { _, err := settings.LoadSettings(ctx); return err },
+		"settings.Save":         func() error { return settings.SaveSettings(ctx, domain.NewSettings()) },
+		"history.Load":          func() error { _, err := history.LoadHistory(ctx); return err },
+		"history.Save":          func() error { return history.SaveHistory(ctx, domain.NewHistory(5)) },
+		"config.Get":            func() error { _, err := config.GetCurrentAccount(ctx); return err },
+		"config.Set":            func() error { return config.SetCurrentAccount(ctx, account) },
+		"config.SetCredentials": func() error { return config.SetCredentials(ctx, creds) },
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			if err := call(); !errors.Is(err, context.Canceled) {
+				t.Errorf("%s error = %v, want context.Canceled", name, err)
+			}
+		})
+	}
+
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		t.Fatalf("Failed to read data dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Cancelled calls created %d entries in the data directory", len(entries))
+	}
+}
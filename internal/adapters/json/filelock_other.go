@@ -0,0 +1,15 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package json
+
+import "os"
+
+// tryLockFile always succeeds on platforms without a supported advisory locking primitive
+func tryLockFile(_ *os.File, _ bool) (bool, error) {
+	return true, nil
+}
+
+// unlockFile is a no-op on platforms without a supported advisory locking primitive
+func unlockFile(_ *os.File) error {
+	return nil
+}
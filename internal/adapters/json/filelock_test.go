@@ -0,0 +1,147 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestLockDataDir_ExclusiveBlocks(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-lock-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	unlock, err := lockDataDir(context.Background(), tmpDir, true)
+	if err != nil {
+		t.Fatalf("lockDataDir() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, lockFileName)); err != nil {
+		t.Fatalf("Expected lock file to exist: %v", err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		unlockShared, err := lockDataDir(context.Background(), tmpDir, false)
+		if err == nil {
+			unlockShared()
+		}
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Shared lock acquired while exclusive lock was held")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shared lock not acquired after exclusive lock was released")
+	}
+}
+
+func TestLockDataDir_SharedOnMissingDir(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-lock-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	missing := filepath.Join(tmpDir, "missing")
+	unlock, err := lockDataDir(context.Background(), missing, false)
+	if err != nil {
+		t.Fatalf("lockDataDir() error = %v", err)
+	}
+	unlock()
+
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Error("Shared lock should not create the data directory")
+	}
+}
+
+func TestLockDataDir_Timeout(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	unlock, err := lockDataDir(context.Background(), tmpDir, true)
+	if err != nil {
+		t.Fatalf("lockDataDir() error = %v", err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = lockDataDir(ctx, tmpDir, false)
+	if !errors.Is(err, ErrLockTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("lockDataDir() error = %v, want ErrLockTimeout wrapping DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("lockDataDir() gave up after %v, want about the deadline", elapsed)
+	}
+	if !strings.Contains(err.Error(), fmt.Sprintf("pid %d", os.Getpid())) {
+		t.Errorf("Error should name the holder's PID, got %v", err)
+	}
+}
+
+func TestFileAccountRepository_ConcurrentInstances(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	// Separate instances do not share a mutex, mimicking separate processes
+	repos := []*FileAccountRepository{
+		{dataDir: tmpDir},
+		{dataDir: tmpDir},
+	}
+	const perRepo = 15
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(repos)*perRepo)
+	for r, repo := range repos {
+		for i := 0; i < perRepo; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				account, err := domain.NewAccount(fmt.Sprintf("user%d-%d@example.com", r, i), "", "uuid")
+				if err != nil {
+					errs <- err
+					return
+				}
+				errs <- repo.Save(context.Background(), account)
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	accounts, err := repos[0].List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(accounts) != len(repos)*perRepo {
+		t.Errorf("Expected %d accounts, got %d (writes were lost)", len(repos)*perRepo, len(accounts))
+	}
+}
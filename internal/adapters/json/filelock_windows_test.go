@@ -0,0 +1,36 @@
+//go:build windows
+
+package json
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLockDataDir_HolderPIDReadable tests that the PID the exclusive holder writes can
+// be read through another handle while the lock is held
+func TestLockDataDir_HolderPIDReadable(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	unlock, err := lockDataDir(context.Background(), tmpDir, true)
+	if err != nil {
+		t.Fatalf("lockDataDir() error = %v", err)
+	}
+	defer unlock()
+
+	// #nosec G304 - test file path
+	f, err := os.OpenFile(filepath.Join(tmpDir, lockFileName), os.O_RDWR, 0o600)
+	if err != nil {
+		t.Fatalf("Failed to open lock file: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if locked, err := tryLockFile(f, false); err != nil || locked {
+		t.Fatalf("tryLockFile() = %v, %v; want the held lock to conflict", locked, err)
+	}
+	if pid := lockHolderPID(f); pid != os.Getpid() {
+		t.Errorf("lockHolderPID() = %d, want %d", pid, os.Getpid())
+	}
+}
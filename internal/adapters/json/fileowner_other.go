@@ -0,0 +1,13 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package json
+
+import (
+	"io/fs"
+	"os"
+)
+
+// chownLike is a no-op on platforms without Unix file ownership
+func chownLike(_ *os.File, _ fs.FileInfo) error {
+	return nil
+}
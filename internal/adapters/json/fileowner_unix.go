@@ -0,0 +1,25 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package json
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// chownLike gives f the owner and group recorded in like. Only root can give a file
+// away, so a permission error is ignored: the file then keeps the writing user's
+// ownership, which is what a plain write by that user would have produced anyway.
+func chownLike(f *os.File, like fs.FileInfo) error {
+	stat, ok := like.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	err := f.Chown(int(stat.Uid), int(stat.Gid)) // #nosec G115 - uids and gids fit in int
+	if errors.Is(err, fs.ErrPermission) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,145 @@
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// FileHistoryRepository implements HistoryRepository using a JSON file
+type FileHistoryRepository struct {
+	dataDir string
+	mu      sync.RWMutex
+}
+
+// Ensure FileHistoryRepository implements HistoryRepository at compile time
+var _ ports.HistoryRepository = (*FileHistoryRepository)(nil)
+
+// historyData represents the JSON structure for persistence
+type historyData struct {
+	MaxEntries int               `json:"max_entries"`
+	Entries    []switchEntryData `json:"entries"` // Most recent first
+}
+
+// switchEntryData represents a single switch entry in history.json
+type switchEntryData struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Timestamp string `json:"timestamp"` // UTC, formatted with historyTimeLayout
+}
+
+// historyTimeLayout is RFC3339 in UTC with fixed-width nanoseconds, so stored
+// timestamps compare the same across machines and sort correctly as strings
+const historyTimeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// NewFileHistoryRepository creates a new file-based history repository
+func NewFileHistoryRepository(dataDir string) ports.HistoryRepository {
+	return &FileHistoryRepository{
+		dataDir: dataDir,
+	}
+}
+
+// LoadHistory reads the switch history, returning an empty history of
+// domain.DefaultHistorySize entries if none has been saved
+func (r *FileHistoryRepository) LoadHistory(ctx context.Context) (*domain.History, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	unlock, err := lockDataDir(ctx, r.dataDir, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	data, err := readFileContext(ctx, filepath.Join(r.dataDir, "history.json"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return domain.NewHistory(domain.DefaultHistorySize), nil
+		}
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	var stored historyData
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to parse history: %w", err)
+	}
+
+	// A hand-edited size could otherwise allocate an enormous buffer
+	history := domain.NewHistory(min(stored.MaxEntries, domain.MaxHistorySize))
+	// AddEntry keeps the most recent first, so add oldest to newest
+	for i := len(stored.Entries) - 1; i >= 0; i-- {
+		// RFC3339 parsing also accepts the variable-width local times written by older versions
+		timestamp, err := time.Parse(time.RFC3339, stored.Entries[i].Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp in history entry %d: %w", i, err)
+		}
+		entry, err := domain.ReconstructSwitchEntry(
+			domain.Email(stored.Entries[i].From),
+			domain.Email(stored.Entries[i].To),
+			timestamp,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid history entry %d: %w", i, err)
+		}
+		history.AddEntry(entry)
+	}
+
+	return history, nil
+}
+
+// SaveHistory atomically replaces history.json with the given history
+func (r *FileHistoryRepository) SaveHistory(ctx context.Context, history *domain.History) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	unlock, err := lockDataDir(ctx, r.dataDir, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries := history.Entries()
+	stored := historyData{
+		MaxEntries: history.MaxEntries(),
+		Entries:    make([]switchEntryData, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		stored.Entries = append(stored.Entries, switchEntryData{
+			From:      string(entry.From()),
+			To:        string(entry.To()),
+			Timestamp: entry.Timestamp().UTC().Format(historyTimeLayout),
+		})
+	}
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal history: %w", err)
+	}
+
+	// Last chance to back out; once the write starts it runs to completion
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(filepath.Join(r.dataDir, "history.json"), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestFileHistoryRepository_LoadMissing(t *testing.T) {
+	repo := NewFileHistoryRepository(filepath.Join(t.TempDir(), "missing"))
+
+	history, err := repo.LoadHistory(context.Background())
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if len(history.Entries()) != 0 {
+		t.Errorf("Expected empty history, got %d entries", len(history.Entries()))
+	}
+	if history.MaxEntries() != domain.DefaultHistorySize {
+		t.Errorf("MaxEntries() = %d, want %d", history.MaxEntries(), domain.DefaultHistorySize)
+	}
+}
+
+func TestFileHistoryRepository_SaveAndLoad(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+
+	history := domain.NewHistory(7)
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, sw := range [][2]domain.Email{
+		{"a@example.com", "b@example.com"},
+		{"b@example.com", "c@example.com"},
+		{"c@example.com", "a@example.com"},
+	} {
+		entry, err := domain.ReconstructSwitchEntry(sw[0], sw[1], base.Add(time.Duration(i)*time.Minute))
+		if err != nil {
+			t.Fatalf("Failed to create entry: %v", err)
+		}
+		history.AddEntry(entry)
+	}
+
+	if err := NewFileHistoryRepository(tmpDir).SaveHistory(ctx, history); err != nil {
+		t.Fatalf("SaveHistory() error = %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(tmpDir, "history.json"))
+	if err != nil {
+		t.Fatalf("history.json was not created: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected permissions 0600, got %o", info.Mode().Perm())
+	}
+
+	// Reload through a fresh repository
+	loaded, err := NewFileHistoryRepository(tmpDir).LoadHistory(ctx)
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if loaded.MaxEntries() != 7 {
+		t.Errorf("MaxEntries() = %d, want 7", loaded.MaxEntries())
+	}
+
+	want := history.Entries()
+	got := loaded.Entries()
+	if len(got) != len(want) {
+		t.Fatalf("Loaded %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].From() != want[i].From() || got[i].To() != want[i].To() || !got[i].Timestamp().Equal(want[i].Timestamp()) {
+			t.Errorf("entry %d = %s -> %s at %v, want %s -> %s at %v", i,
+				got[i].From(), got[i].To(), got[i].Timestamp(), want[i].From(), want[i].To(), want[i].Timestamp())
+		}
+	}
+}
+
+func TestFileHistoryRepository_UTCTimestamps(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+
+	// A legacy file with a local offset still loads, normalized to UTC
+	legacy := `{"max_entries": 5, "entries": [{"from": "a@example.com", "to": "b@example.com", "timestamp": "2025-01-02T03:04:05-05:00"}]}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "history.json"), []byte(legacy), 0o600); err != nil {
+		t.Fatalf("Failed to write history: %v", err)
+	}
+	repo := NewFileHistoryRepository(tmpDir)
+	history, err := repo.LoadHistory(ctx)
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	want := time.Date(2025, 1, 2, 8, 4, 5, 0, time.UTC)
+	if ts := history.Entries()[0].Timestamp(); !ts.Equal(want) || ts.Location() != time.UTC {
+		t.Errorf("Timestamp() = %v, want %v", ts, want)
+	}
+
+	// Entries from other zones are written as fixed-width UTC, so string order is time order
+	east := time.FixedZone("JST", 9*3600)
+	later, _ := domain.ReconstructSwitchEntry("b@example.com", "a@example.com", time.Date(2025, 1, 2, 10, 0, 0, 500, east))
+	history.AddEntry(later)
+	if err := repo.SaveHistory(ctx, history); err != nil {
+		t.Fatalf("SaveHistory() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "history.json")) // #nosec G304 - test file in temp dir
+	if err != nil {
+		t.Fatalf("Failed to read history: %v", err)
+	}
+	var stored historyData
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("Failed to parse history: %v", err)
+	}
+	wantStamps := []string{"2025-01-02T01:00:00.000000500Z", "2025-01-02T08:04:05.000000000Z"}
+	for i, entry := range stored.Entries {
+		if entry.Timestamp != wantStamps[i] {
+			t.Errorf("entry %d timestamp = %q, want %q", i, entry.Timestamp, wantStamps[i])
+		}
+	}
+}
+
+func TestFileHistoryRepository_InvalidFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	for name, content := range map[string]string{
+		"not JSON":       "not json",
+		"empty email":    `{"max_entries": 5, "entries": [{"from": "", "to": "a@example.com", "timestamp": "2025-01-02T03:04:05Z"}]}`,
+		"bad timestamp":  `{"max_entries": 5, "entries": [{"from": "a@example.com", "to": "b@example.com", "timestamp": "yesterday"}]}`,
+		"zero timestamp": `{"max_entries": 5, "entries": [{"from": "a@example.com", "to": "b@example.com", "timestamp": "0001-01-01T00:00:00Z"}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := os.WriteFile(filepath.Join(tmpDir, "history.json"), []byte(content), 0o600); err != nil {
+				t.Fatalf("Failed to write history: %v", err)
+			}
+			if _, err := NewFileHistoryRepository(tmpDir).LoadHistory(context.Background()); err == nil {
+				t.Error("LoadHistory() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestFileHistoryRepository_OversizedMaxEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `{"max_entries": 9223372036854775807, "entries": [{"from": "a@example.com", "to": "b@example.com", "timestamp": "2025-01-02T03:04:05Z"}]}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "history.json"), []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write history: %v", err)
+	}
+
+	history, err := NewFileHistoryRepository(tmpDir).LoadHistory(context.Background())
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if history.MaxEntries() != domain.MaxHistorySize || len(history.Entries()) != 1 {
+		t.Errorf("LoadHistory() = %d entries of at most %d, want 1 of at most %d",
+			len(history.Entries()), history.MaxEntries(), domain.MaxHistorySize)
+	}
+}
+
+// TestFileHistoryRepository_Contract runs the HistoryRepository scenarios the use cases rely on
+func TestFileHistoryRepository_Contract(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("switch appends to loaded history", func(t *testing.T) {
+		repo := NewFileHistoryRepository(t.TempDir())
+
+		for _, sw := range [][2]domain.Email{{"a@example.com", "b@example.com"}, {"b@example.com", "c@example.com"}} {
+			history, err := repo.LoadHistory(ctx)
+			if err != nil {
+				t.Fatalf("LoadHistory() error = %v", err)
+			}
+			entry, _ := domain.NewSwitchEntry(sw[0], sw[1])
+			history.AddEntry(entry)
+			if err := repo.SaveHistory(ctx, history); err != nil {
+				t.Fatalf("SaveHistory() error = %v", err)
+			}
+		}
+
+		loaded, _ := repo.LoadHistory(ctx)
+		entries := loaded.Entries()
+		if len(entries) != 2 {
+			t.Fatalf("history has %d entries, want 2", len(entries))
+		}
+		// Previous resolves to the From of the most recent entry
+		if last := loaded.GetLastSwitch(); last.From() != "b@example.com" || last.To() != "c@example.com" {
+			t.Errorf("last switch = %s -> %s, want b -> c", last.From(), last.To())
+		}
+	})
+
+	t.Run("max entries limit survives a round trip", func(t *testing.T) {
+		repo := NewFileHistoryRepository(t.TempDir())
+		history := domain.NewHistory(3)
+		for i := 0; i < 5; i++ {
+			from := domain.Email(string(rune('a'+i)) + "@example.com")
+			to := domain.Email(string(rune('a'+i+1)) + "@example.com")
+			entry, _ := domain.NewSwitchEntry(from, to)
+			history.AddEntry(entry)
+		}
+		if err := repo.SaveHistory(ctx, history); err != nil {
+			t.Fatalf("SaveHistory() error = %v", err)
+		}
+
+		loaded, _ := repo.LoadHistory(ctx)
+		entries := loaded.Entries()
+		if len(entries) != 3 || entries[0].From() != "e@example.com" {
+			t.Errorf("expected the 3 most recent entries, got %d starting from %s", len(entries), entries[0].From())
+		}
+
+		// Adding to the loaded history still honors the persisted limit
+		entry, _ := domain.NewSwitchEntry("f@example.com", "a@example.com")
+		loaded.AddEntry(entry)
+		if len(loaded.Entries()) != 3 {
+			t.Errorf("loaded history grew to %d entries, want 3", len(loaded.Entries()))
+		}
+	})
+
+	t.Run("cleared history saves as empty", func(t *testing.T) {
+		repo := NewFileHistoryRepository(t.TempDir())
+		history := domain.NewHistory(5)
+		entry, _ := domain.NewSwitchEntry("a@example.com", "b@example.com")
+		history.AddEntry(entry)
+		_ = repo.SaveHistory(ctx, history)
+
+		history.Clear()
+		if err := repo.SaveHistory(ctx, history); err != nil {
+			t.Fatalf("SaveHistory() error = %v", err)
+		}
+		loaded, err := repo.LoadHistory(ctx)
+		if err != nil {
+			t.Fatalf("LoadHistory() error = %v", err)
+		}
+		if len(loaded.Entries()) != 0 || loaded.MaxEntries() != 5 {
+			t.Errorf("loaded %d entries with max %d, want 0 with max 5", len(loaded.Entries()), loaded.MaxEntries())
+		}
+	})
+}
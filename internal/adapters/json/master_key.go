@@ -0,0 +1,100 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+// MasterKeyFile is the name of the file in the data directory holding the master key
+// used for envelope encryption of credentials
+const MasterKeyFile = "master.key"
+
+// ErrMasterKeyPermissions is returned when master.key can be read by other users
+var ErrMasterKeyPermissions = errors.New("master key file is readable by other users")
+
+// masterKeyWait is how long readMasterKey waits for a key file still being written by
+// the process that created it; tests shorten it
+var masterKeyWait = time.Second
+
+// LoadOrCreateMasterKey returns the master key stored in dataDir, generating it and
+// writing it with 0600 permissions on first use. A key file that group or other users
+// can access is refused rather than used, since it protects every stored credential.
+func LoadOrCreateMasterKey(dataDir string) ([]byte, error) {
+	path := filepath.Join(dataDir, MasterKeyFile)
+
+	key, err := readMasterKey(path)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return key, err
+	}
+
+	if err := os.MkdirAll(dataDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	key, err = domain.GenerateMasterKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate master key: %w", err)
+	}
+
+	// O_EXCL so two processes starting at once cannot each write a different key; the
+	// loser reads the winner's key once it is fully written
+	// #nosec G304 - controlled file path within app data directory
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return readMasterKey(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create master key file: %w", err)
+	}
+	if _, err := f.Write(key); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("failed to write master key file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("failed to write master key file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("failed to write master key file: %w", err)
+	}
+
+	return key, nil
+}
+
+// readMasterKey reads and checks an existing master key file. A file shorter than a key
+// may have just been created by another process, so it is read again until it holds a
+// whole key or masterKeyWait runs out.
+func readMasterKey(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	deadline := time.Now().Add(masterKeyWait)
+	for err == nil && info.Size() < domain.MasterKeySize && time.Now().Before(deadline) {
+		time.Sleep(lockRetryInterval)
+		info, err = os.Stat(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Windows does not report Unix permission bits
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf("%w: %s has mode %o; run chmod 600 on it", ErrMasterKeyPermissions, path, info.Mode().Perm())
+	}
+
+	key, err := os.ReadFile(path) // #nosec G304 - controlled file path within app data directory
+	if err != nil {
+		return nil, fmt.Errorf("failed to read master key file: %w", err)
+	}
+	if len(key) != domain.MasterKeySize {
+		return nil, fmt.Errorf("master key file %s is corrupt: expected %d bytes, got %d", path, domain.MasterKeySize, len(key))
+	}
+	return key, nil
+}
@@ -0,0 +1,97 @@
+package json
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestLoadOrCreateMasterKey(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), "data")
+
+	key, err := LoadOrCreateMasterKey(dataDir)
+	if err != nil {
+		t.Fatalf("LoadOrCreateMasterKey() error = %v", err)
+	}
+	if len(key) != domain.MasterKeySize {
+		t.Fatalf("len(key) = %d, want %d", len(key), domain.MasterKeySize)
+	}
+
+	info, err := os.Stat(filepath.Join(dataDir, MasterKeyFile))
+	if err != nil {
+		t.Fatalf("Failed to stat master key: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("master key permissions = %o, want 600", info.Mode().Perm())
+	}
+
+	again, err := LoadOrCreateMasterKey(dataDir)
+	if err != nil {
+		t.Fatalf("Second LoadOrCreateMasterKey() error = %v", err)
+	}
+	if !bytes.Equal(again, key) {
+		t.Error("Second LoadOrCreateMasterKey() returned a different key")
+	}
+}
+
+func TestLoadOrCreateMasterKey_RefusesBadFiles(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		dataDir := t.TempDir()
+		if _, err := LoadOrCreateMasterKey(dataDir); err != nil {
+			t.Fatalf("LoadOrCreateMasterKey() error = %v", err)
+		}
+		path := filepath.Join(dataDir, MasterKeyFile)
+		// #nosec G302 - test makes the file world-readable on purpose
+		if err := os.Chmod(path, 0o644); err != nil {
+			t.Fatalf("Failed to chmod master key: %v", err)
+		}
+		if _, err := LoadOrCreateMasterKey(dataDir); !errors.Is(err, ErrMasterKeyPermissions) {
+			t.Errorf("LoadOrCreateMasterKey() error = %v, want ErrMasterKeyPermissions", err)
+		}
+	}
+
+	defer func(wait time.Duration) { masterKeyWait = wait }(masterKeyWait)
+	masterKeyWait = 0
+
+	dataDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dataDir, MasterKeyFile), []byte("short"), 0o600); err != nil {
+		t.Fatalf("Failed to write master key: %v", err)
+	}
+	if _, err := LoadOrCreateMasterKey(dataDir); err == nil {
+		t.Error("LoadOrCreateMasterKey() with a truncated key error = nil, want error")
+	}
+}
+
+// TestLoadOrCreateMasterKey_WaitsForKeyBeingWritten tests that a process finding the key
+// file created but not yet written reads the key once it is, rather than failing
+func TestLoadOrCreateMasterKey_WaitsForKeyBeingWritten(t *testing.T) {
+	dataDir := t.TempDir()
+	path := filepath.Join(dataDir, MasterKeyFile)
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("Failed to create master key: %v", err)
+	}
+
+	want, _ := domain.GenerateMasterKey()
+	written := make(chan error, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		written <- os.WriteFile(path, want, 0o600)
+	}()
+
+	key, err := LoadOrCreateMasterKey(dataDir)
+	if werr := <-written; werr != nil {
+		t.Fatalf("Failed to write master key: %v", werr)
+	}
+	if err != nil {
+		t.Fatalf("LoadOrCreateMasterKey() error = %v", err)
+	}
+	if !bytes.Equal(key, want) {
+		t.Error("LoadOrCreateMasterKey() returned a different key than the one written")
+	}
+}
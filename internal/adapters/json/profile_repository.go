@@ -0,0 +1,216 @@
+package json
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// FileProfileRepository implements ProfileRepository using a JSON file
+type FileProfileRepository struct {
+	dataDir string
+	mu      sync.RWMutex
+}
+
+// Ensure FileProfileRepository implements ProfileRepository at compile time
+var _ ports.ProfileRepository = (*FileProfileRepository)(nil)
+
+// profilesData represents the JSON structure for persistence
+type profilesData struct {
+	Profiles []profileData `json:"profiles"` // Sorted by name
+}
+
+// profileData represents a single profile in profiles.json
+type profileData struct {
+	Name      string `json:"name"`
+	AccountID string `json:"account_id"`
+}
+
+// NewFileProfileRepository creates a new file-based profile repository
+func NewFileProfileRepository(dataDir string) ports.ProfileRepository {
+	return &FileProfileRepository{
+		dataDir: dataDir,
+	}
+}
+
+// Save persists a profile, replacing any profile with the same name
+func (r *FileProfileRepository) Save(ctx context.Context, profile *domain.Profile) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	unlock, err := lockDataDir(ctx, r.dataDir, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	profiles, err := r.loadProfiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	data := profileData{
+		Name:      profile.Name(),
+		AccountID: string(profile.AccountID()),
+	}
+	i, found := slices.BinarySearchFunc(profiles, data.Name, func(p profileData, name string) int {
+		return cmp.Compare(p.Name, name)
+	})
+	if found {
+		profiles[i] = data
+	} else {
+		profiles = slices.Insert(profiles, i, data)
+	}
+
+	return r.saveProfiles(ctx, profiles)
+}
+
+// FindByName retrieves a profile by name
+func (r *FileProfileRepository) FindByName(ctx context.Context, name string) (*domain.Profile, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	unlock, err := lockDataDir(ctx, r.dataDir, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	profiles, err := r.loadProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range profiles {
+		if p.Name == name {
+			return convertToProfile(p)
+		}
+	}
+
+	return nil, domain.ErrProfileNotFound
+}
+
+// List returns all profiles sorted by name
+func (r *FileProfileRepository) List(ctx context.Context) ([]*domain.Profile, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	unlock, err := lockDataDir(ctx, r.dataDir, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	profiles, err := r.loadProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Profile, 0, len(profiles))
+	for _, p := range profiles {
+		profile, err := convertToProfile(p)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, profile)
+	}
+
+	return result, nil
+}
+
+// Delete removes a profile
+func (r *FileProfileRepository) Delete(ctx context.Context, name string) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	unlock, err := lockDataDir(ctx, r.dataDir, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	profiles, err := r.loadProfiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(profiles, func(p profileData) bool { return p.Name == name })
+	if i < 0 {
+		return domain.ErrProfileNotFound
+	}
+
+	return r.saveProfiles(ctx, slices.Delete(profiles, i, i+1))
+}
+
+// loadProfiles reads profiles.json sorted by name, returning no profiles if it does not exist
+func (r *FileProfileRepository) loadProfiles(ctx context.Context) ([]profileData, error) {
+	data, err := readFileContext(ctx, filepath.Join(r.dataDir, "profiles.json"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []profileData{}, nil
+		}
+		return nil, fmt.Errorf("failed to read profiles file: %w", err)
+	}
+
+	var stored profilesData
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles: %w", err)
+	}
+
+	// Hand-edited files may be out of order
+	slices.SortFunc(stored.Profiles, func(a, b profileData) int { return cmp.Compare(a.Name, b.Name) })
+	return stored.Profiles, nil
+}
+
+// saveProfiles atomically replaces profiles.json
+func (r *FileProfileRepository) saveProfiles(ctx context.Context, profiles []profileData) error {
+	data, err := json.MarshalIndent(profilesData{Profiles: profiles}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal profiles: %w", err)
+	}
+
+	// Last chance to back out; once the write starts it runs to completion
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(filepath.Join(r.dataDir, "profiles.json"), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write profiles file: %w", err)
+	}
+
+	return nil
+}
+
+// convertToProfile validates a stored profile
+func convertToProfile(p profileData) (*domain.Profile, error) {
+	profile, err := domain.NewProfile(p.Name, domain.AccountID(p.AccountID))
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile %q: %w", p.Name, err)
+	}
+	return profile, nil
+}
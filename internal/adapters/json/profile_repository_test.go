@@ -0,0 +1,92 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestFileProfileRepository_LoadMissing(t *testing.T) {
+	repo := NewFileProfileRepository(filepath.Join(t.TempDir(), "missing"))
+
+	profiles, err := repo.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(profiles) != 0 {
+		t.Errorf("List() returned %d profiles, want 0", len(profiles))
+	}
+	if _, err := repo.FindByName(context.Background(), "personal"); !errors.Is(err, domain.ErrProfileNotFound) {
+		t.Errorf("FindByName() error = %v, want ErrProfileNotFound", err)
+	}
+}
+
+func TestFileProfileRepository_SaveFindDelete(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	repo := NewFileProfileRepository(tmpDir)
+
+	for _, p := range []struct {
+		name string
+		id   domain.AccountID
+	}{{"personal", "aaaa1111"}, {"client-a", "bbbb2222"}, {"personal", "cccc3333"}} {
+		profile, _ := domain.NewProfile(p.name, p.id)
+		if err := repo.Save(ctx, profile); err != nil {
+			t.Fatalf("Save(%s) error = %v", p.name, err)
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(tmpDir, "profiles.json"))
+	if err != nil {
+		t.Fatalf("profiles.json was not created: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected permissions 0600, got %o", info.Mode().Perm())
+	}
+
+	// Reload through a fresh repository; saving an existing name replaced it
+	repo = NewFileProfileRepository(tmpDir)
+	profiles, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(profiles) != 2 || profiles[0].Name() != "client-a" || profiles[1].Name() != "personal" {
+		t.Fatalf("List() = %v, want client-a and personal", profiles)
+	}
+	personal, err := repo.FindByName(ctx, "personal")
+	if err != nil || personal.AccountID() != "cccc3333" {
+		t.Errorf("FindByName(personal) = %v, %v; want account cccc3333", personal, err)
+	}
+
+	if err := repo.Delete(ctx, "client-a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := repo.Delete(ctx, "client-a"); !errors.Is(err, domain.ErrProfileNotFound) {
+		t.Errorf("Delete() of missing profile error = %v, want ErrProfileNotFound", err)
+	}
+	if profiles, _ := repo.List(ctx); len(profiles) != 1 {
+		t.Errorf("List() after delete returned %d profiles, want 1", len(profiles))
+	}
+}
+
+func TestFileProfileRepository_InvalidFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	for name, content := range map[string]string{
+		"not JSON":     "not json",
+		"invalid name": `{"profiles": [{"name": "client a", "account_id": "aaaa1111"}]}`,
+		"no account":   `{"profiles": [{"name": "client-a", "account_id": ""}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := os.WriteFile(filepath.Join(tmpDir, "profiles.json"), []byte(content), 0o600); err != nil {
+				t.Fatalf("Failed to write profiles: %v", err)
+			}
+			if _, err := NewFileProfileRepository(tmpDir).List(context.Background()); err == nil {
+				t.Error("List() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package json_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ccxjson "github.com/evanschultz/ccx/internal/adapters/json"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// TestServices_SwitchWithFileConfig tests switching against the real Claude config
+// manager, whose current account carries no ccx ID and must be matched by UUID or email
+func TestServices_SwitchWithFileConfig(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	accounts := ccxjson.NewFileAccountRepository(dataDir)
+	credentials := ccxjson.NewFileCredentialStore(dataDir)
+	config := ccxjson.NewBasicConfigManager(t.TempDir())
+	history := ccxjson.NewFileHistoryRepository(dataDir)
+
+	add := usecases.NewAddAccountService(accounts, credentials, config)
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		if err := add.Execute(ctx, usecases.AddAccountInput{Email: email, Credentials: []byte(`{"sessionKey":"key"}`)}); err != nil {
+			t.Fatalf("AddAccount(%s) error = %v", email, err)
+		}
+	}
+
+	// Leave a as both the current and the most recently used account
+	switchAccount := usecases.NewSwitchAccountService(accounts, credentials, config, history)
+	for _, email := range []string{"b@example.com", "a@example.com"} {
+		if _, err := switchAccount.Execute(ctx, usecases.SwitchAccountInput{Email: email}); err != nil {
+			t.Fatalf("SwitchAccount(%s) error = %v", email, err)
+		}
+	}
+	before, _ := history.LoadHistory(ctx)
+
+	// Switching to the account already in use is a no-op
+	result, err := switchAccount.Execute(ctx, usecases.SwitchAccountInput{Email: "a@example.com"})
+	if err != nil {
+		t.Fatalf("SwitchAccount(a) again error = %v", err)
+	}
+	if result.From == nil || result.From.Email != "a@example.com" || result.To.Email != "a@example.com" {
+		t.Errorf("Repeated switch = %+v, want a no-op on a@example.com", result)
+	}
+	if after, _ := history.LoadHistory(ctx); len(after.Entries()) != len(before.Entries()) {
+		t.Errorf("History has %d entries after a no-op switch, want %d", len(after.Entries()), len(before.Entries()))
+	}
+
+	// MostRecent skips the current account even though its config copy has no ccx ID
+	result, err = switchAccount.Execute(ctx, usecases.SwitchAccountInput{MostRecent: true})
+	if err != nil {
+		t.Fatalf("SwitchAccount(MostRecent) error = %v", err)
+	}
+	if result.To.Email != "b@example.com" {
+		t.Errorf("MostRecent switched to %s, want b@example.com", result.To.Email)
+	}
+}
+
+// TestServices_RemoveCurrentWithFileConfig tests that the account in Claude config is
+// recognized as current when removing it, and that removing it clears the config
+func TestServices_RemoveCurrentWithFileConfig(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	accounts := ccxjson.NewFileAccountRepository(dataDir)
+	credentials := ccxjson.NewFileCredentialStore(dataDir)
+	config := ccxjson.NewBasicConfigManager(t.TempDir())
+	history := ccxjson.NewFileHistoryRepository(dataDir)
+
+	add := usecases.NewAddAccountService(accounts, credentials, config)
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		if err := add.Execute(ctx, usecases.AddAccountInput{Email: email, Credentials: []byte(`{"sessionKey":"key"}`)}); err != nil {
+			t.Fatalf("AddAccount(%s) error = %v", email, err)
+		}
+	}
+	switchAccount := usecases.NewSwitchAccountService(accounts, credentials, config, history)
+	if _, err := switchAccount.Execute(ctx, usecases.SwitchAccountInput{Email: "b@example.com"}); err != nil {
+		t.Fatalf("SwitchAccount(b) error = %v", err)
+	}
+
+	remove := usecases.NewRemoveAccountService(accounts, credentials, config, history)
+	if _, err := remove.Execute(ctx, usecases.RemoveAccountInput{Email: "b@example.com"}); !errors.Is(err, usecases.ErrRemovingCurrentAccount) {
+		t.Fatalf("RemoveAccount(b) error = %v, want ErrRemovingCurrentAccount", err)
+	}
+
+	result, err := remove.Execute(ctx, usecases.RemoveAccountInput{Email: "b@example.com", ForceRemoveCurrent: true})
+	if err != nil {
+		t.Fatalf("RemoveAccount(b, force) error = %v", err)
+	}
+	if !result.WasCurrentAccount {
+		t.Error("WasCurrentAccount = false, want true")
+	}
+	if current, _ := config.GetCurrentAccount(ctx); current != nil {
+		t.Errorf("Current account after removal = %s, want none", current.Email())
+	}
+}
@@ -0,0 +1,100 @@
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// FileSettingsRepository implements SettingsRepository using a JSON file
+type FileSettingsRepository struct {
+	dataDir string
+	mu      sync.RWMutex
+}
+
+// settingsData represents the JSON structure for persistence
+type settingsData struct {
+	DefaultAccountID string `json:"default_account_id,omitempty"`
+	CurrentAccountID string `json:"current_account_id,omitempty"`
+	HistorySize      int    `json:"history_size,omitempty"`
+}
+
+// NewFileSettingsRepository creates a new file-based settings repository
+func NewFileSettingsRepository(dataDir string) ports.SettingsRepository {
+	return &FileSettingsRepository{
+		dataDir: dataDir,
+	}
+}
+
+// LoadSettings reads settings from the JSON file, returning empty settings if none are saved
+func (r *FileSettingsRepository) LoadSettings(ctx context.Context) (*domain.Settings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	filePath := filepath.Join(r.dataDir, "settings.json")
+
+	data, err := readFileContext(ctx, filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return domain.NewSettings(), nil
+		}
+		return nil, fmt.Errorf("failed to read settings file: %w", err)
+	}
+
+	var stored settingsData
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to parse settings: %w", err)
+	}
+
+	settings := domain.NewSettings()
+	settings.SetDefaultAccountID(domain.AccountID(stored.DefaultAccountID))
+	settings.SetCurrentAccountID(domain.AccountID(stored.CurrentAccountID))
+	if err := settings.SetHistorySize(min(stored.HistorySize, domain.MaxHistorySize)); err != nil {
+		return nil, fmt.Errorf("failed to parse settings: %w", err)
+	}
+	return settings, nil
+}
+
+// SaveSettings writes the settings to the JSON file
+func (r *FileSettingsRepository) SaveSettings(ctx context.Context, settings *domain.Settings) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Ensure data directory exists
+	if err := os.MkdirAll(r.dataDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	stored := settingsData{
+		DefaultAccountID: string(settings.DefaultAccountID()),
+		CurrentAccountID: string(settings.CurrentAccountID()),
+		HistorySize:      settings.HistorySize(),
+	}
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal settings: %w", err)
+	}
+
+	filePath := filepath.Join(r.dataDir, "settings.json")
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filePath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write settings file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package json
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestFileSettingsRepository_LoadMissing(t *testing.T) {
+	// Setup temporary directory
+	tmpDir, err := os.MkdirTemp("", "ccx-settings-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	repo := NewFileSettingsRepository(tmpDir)
+
+	settings, err := repo.LoadSettings(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error when no settings exist, got: %v", err)
+	}
+	if settings.HasDefaultAccount() {
+		t.Error("Expected no default account when no settings exist")
+	}
+}
+
+func TestFileSettingsRepository_SaveAndLoad(t *testing.T) {
+	// Setup temporary directory
+	tmpDir, err := os.MkdirTemp("", "ccx-settings-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	repo := NewFileSettingsRepository(tmpDir)
+	ctx := context.Background()
+
+	settings, _ := repo.LoadSettings(ctx)
+	settings.SetDefaultAccountID("abc12345")
+	settings.SetCurrentAccountID("def67890")
+
+	if err := repo.SaveSettings(ctx, settings); err != nil {
+		t.Fatalf("Failed to save settings: %v", err)
+	}
+
+	// Verify file permissions are owner-only
+	info, err := os.Stat(filepath.Join(tmpDir, "settings.json"))
+	if err != nil {
+		t.Fatalf("settings.json was not created: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected permissions 0600, got %o", info.Mode().Perm())
+	}
+
+	// Reload through a fresh repository
+	loaded, err := NewFileSettingsRepository(tmpDir).LoadSettings(ctx)
+	if err != nil {
+		t.Fatalf("Failed to load settings: %v", err)
+	}
+	if loaded.DefaultAccountID() != "abc12345" {
+		t.Errorf("Expected default account abc12345, got %v", loaded.DefaultAccountID())
+	}
+	if loaded.CurrentAccountID() != "def67890" {
+		t.Errorf("Expected current account def67890, got %v", loaded.CurrentAccountID())
+	}
+}
+
+func TestFileSettingsRepository_HistorySize(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+
+	settings := domain.NewSettings()
+	if err := settings.SetHistorySize(120); err != nil {
+		t.Fatalf("SetHistorySize() error = %v", err)
+	}
+	if err := NewFileSettingsRepository(tmpDir).SaveSettings(ctx, settings); err != nil {
+		t.Fatalf("Failed to save settings: %v", err)
+	}
+
+	loaded, err := NewFileSettingsRepository(tmpDir).LoadSettings(ctx)
+	if err != nil {
+		t.Fatalf("Failed to load settings: %v", err)
+	}
+	if loaded.HistorySize() != 120 {
+		t.Errorf("HistorySize() = %d, want 120", loaded.HistorySize())
+	}
+}
@@ -0,0 +1,162 @@
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// FileTombstoneRepository implements TombstoneRepository using a JSON file
+type FileTombstoneRepository struct {
+	dataDir string
+	mu      sync.RWMutex
+}
+
+// Ensure FileTombstoneRepository implements TombstoneRepository at compile time
+var _ ports.TombstoneRepository = (*FileTombstoneRepository)(nil)
+
+// tombstonesData represents the JSON structure for persistence
+type tombstonesData struct {
+	Tombstones []tombstoneData `json:"tombstones"` // Most recently removed first
+}
+
+// tombstoneData represents a single removed account in tombstones.json. It holds no
+// credentials.
+type tombstoneData struct {
+	AccountID string `json:"account_id"`
+	Email     string `json:"email"`
+	Alias     string `json:"alias,omitempty"`
+	UUID      string `json:"uuid"`
+	RemovedAt string `json:"removed_at"` // UTC, formatted with historyTimeLayout
+	Reason    string `json:"reason,omitempty"`
+}
+
+// NewFileTombstoneRepository creates a new file-based tombstone repository
+func NewFileTombstoneRepository(dataDir string) ports.TombstoneRepository {
+	return &FileTombstoneRepository{
+		dataDir: dataDir,
+	}
+}
+
+// Add records a tombstone, dropping the oldest beyond domain.MaxTombstones
+func (r *FileTombstoneRepository) Add(ctx context.Context, tombstone *domain.Tombstone) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	unlock, err := lockDataDir(ctx, r.dataDir, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tombstones, err := r.loadTombstones(ctx)
+	if err != nil {
+		return err
+	}
+
+	tombstones = slices.Insert(tombstones, 0, tombstoneData{
+		AccountID: string(tombstone.AccountID()),
+		Email:     string(tombstone.Email()),
+		Alias:     tombstone.Alias(),
+		UUID:      tombstone.UUID(),
+		RemovedAt: tombstone.RemovedAt().UTC().Format(historyTimeLayout),
+		Reason:    tombstone.Reason(),
+	})
+	if len(tombstones) > domain.MaxTombstones {
+		tombstones = tombstones[:domain.MaxTombstones]
+	}
+
+	return r.saveTombstones(ctx, tombstones)
+}
+
+// List returns the tombstones, most recently removed first
+func (r *FileTombstoneRepository) List(ctx context.Context) ([]*domain.Tombstone, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	unlock, err := lockDataDir(ctx, r.dataDir, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	tombstones, err := r.loadTombstones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Tombstone, 0, len(tombstones))
+	for i, data := range tombstones {
+		removedAt, err := time.Parse(time.RFC3339, data.RemovedAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid removal time in tombstone %d: %w", i, err)
+		}
+		tombstone, err := domain.ReconstructTombstone(
+			domain.AccountID(data.AccountID),
+			domain.Email(data.Email),
+			data.Alias,
+			data.UUID,
+			removedAt,
+			data.Reason,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tombstone %d: %w", i, err)
+		}
+		result = append(result, tombstone)
+	}
+
+	return result, nil
+}
+
+// loadTombstones reads tombstones.json, returning none if it does not exist
+func (r *FileTombstoneRepository) loadTombstones(ctx context.Context) ([]tombstoneData, error) {
+	data, err := readFileContext(ctx, filepath.Join(r.dataDir, "tombstones.json"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []tombstoneData{}, nil
+		}
+		return nil, fmt.Errorf("failed to read tombstones file: %w", err)
+	}
+
+	var stored tombstonesData
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to parse tombstones: %w", err)
+	}
+	return stored.Tombstones, nil
+}
+
+// saveTombstones atomically replaces tombstones.json
+func (r *FileTombstoneRepository) saveTombstones(ctx context.Context, tombstones []tombstoneData) error {
+	data, err := json.MarshalIndent(tombstonesData{Tombstones: tombstones}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tombstones: %w", err)
+	}
+
+	// Last chance to back out; once the write starts it runs to completion
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(filepath.Join(r.dataDir, "tombstones.json"), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write tombstones file: %w", err)
+	}
+
+	return nil
+}
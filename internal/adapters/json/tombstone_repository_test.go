@@ -0,0 +1,69 @@
+package json
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestFileTombstoneRepository_LoadMissing(t *testing.T) {
+	repo := NewFileTombstoneRepository(filepath.Join(t.TempDir(), "missing"))
+
+	tombstones, err := repo.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(tombstones) != 0 {
+		t.Errorf("List() returned %d tombstones, want 0", len(tombstones))
+	}
+}
+
+func TestFileTombstoneRepository_AddList(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	repo := NewFileTombstoneRepository(tmpDir)
+
+	removedAt := time.Date(2025, 3, 4, 5, 6, 7, 8, time.UTC)
+	for i := range domain.MaxTombstones + 2 {
+		account, _ := domain.NewAccount(fmt.Sprintf("user%d@example.com", i), "", fmt.Sprintf("uuid-%d", i))
+		tombstone, err := domain.NewTombstone(account, removedAt.Add(time.Duration(i)*time.Minute), "rotated out")
+		if err != nil {
+			t.Fatalf("NewTombstone() error = %v", err)
+		}
+		if err := repo.Add(ctx, tombstone); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	tombstones, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(tombstones) != domain.MaxTombstones {
+		t.Fatalf("List() returned %d tombstones, want the cap of %d", len(tombstones), domain.MaxTombstones)
+	}
+	newest := tombstones[0]
+	if newest.Email() != "user101@example.com" || newest.UUID() != "uuid-101" || newest.Reason() != "rotated out" {
+		t.Errorf("Newest tombstone = %s/%s/%q, want user101", newest.Email(), newest.UUID(), newest.Reason())
+	}
+	if !newest.RemovedAt().Equal(removedAt.Add(101 * time.Minute)) {
+		t.Errorf("RemovedAt() = %v, want the recorded time", newest.RemovedAt())
+	}
+	if oldest := tombstones[len(tombstones)-1]; oldest.Email() != "user2@example.com" {
+		t.Errorf("Oldest kept tombstone = %s, want user2@example.com", oldest.Email())
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "tombstones.json")) // #nosec G304 - test file path
+	if err != nil {
+		t.Fatalf("Failed to read tombstones file: %v", err)
+	}
+	if strings.Contains(string(data), "credentials") {
+		t.Error("tombstones.json should not mention credentials")
+	}
+}
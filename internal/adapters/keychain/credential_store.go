@@ -0,0 +1,147 @@
+// Package keychain provides a CredentialStore backed by the operating system keychain.
+package keychain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/99designs/keyring"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// DefaultServiceName is the keychain service under which ccx stores credentials
+const DefaultServiceName = "ccx"
+
+// keyPrefix namespaces ccx items within the keychain service
+const keyPrefix = "ccx:"
+
+// KeychainCredentialStore implements CredentialStore using the system keychain.
+// Items are keyed by "ccx:<accountID>" and hold the serialized domain credentials.
+type KeychainCredentialStore struct { //nolint:revive // keeps the backend in the name like FileCredentialStore
+	ring keyring.Keyring
+	mu   sync.RWMutex
+}
+
+// Ensure KeychainCredentialStore can enumerate its credentials at compile time
+var _ ports.CredentialLister = (*KeychainCredentialStore)(nil)
+
+// NewKeychainCredentialStore opens the macOS Keychain and returns a credential store using it.
+// Fails on platforms where the Keychain backend is unavailable.
+func NewKeychainCredentialStore(serviceName string) (ports.CredentialStore, error) {
+	ring, err := keyring.Open(keyring.Config{
+		ServiceName:              serviceName,
+		AllowedBackends:          []keyring.BackendType{keyring.KeychainBackend},
+		KeychainTrustApplication: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open keychain: %w", err)
+	}
+	return NewKeychainCredentialStoreWithKeyring(ring), nil
+}
+
+// NewKeychainCredentialStoreWithKeyring creates a credential store on top of an already opened keyring
+func NewKeychainCredentialStoreWithKeyring(ring keyring.Keyring) ports.CredentialStore {
+	return &KeychainCredentialStore{
+		ring: ring,
+	}
+}
+
+// Store saves credentials to the keychain, replacing any existing item for the account
+func (s *KeychainCredentialStore) Store(_ context.Context, creds *domain.Credentials) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Serialize credentials using domain's built-in encryption
+	data, err := creds.Serialize()
+	if err != nil {
+		return fmt.Errorf("failed to serialize credentials: %w", err)
+	}
+
+	item := keyring.Item{
+		Key:         itemKey(creds.AccountID()),
+		Data:        data,
+		Label:       fmt.Sprintf("ccx credentials (%s)", creds.AccountID()),
+		Description: "Claude Code session credentials",
+	}
+	if err := s.ring.Set(item); err != nil {
+		return fmt.Errorf("failed to write keychain item: %w", err)
+	}
+
+	return nil
+}
+
+// Retrieve gets credentials for an account from the keychain
+func (s *KeychainCredentialStore) Retrieve(_ context.Context, accountID domain.AccountID) (*domain.Credentials, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	item, err := s.ring.Get(itemKey(accountID))
+	if errors.Is(err, keyring.ErrKeyNotFound) {
+		return nil, domain.ErrCredentialsNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keychain item: %w", err)
+	}
+
+	// Deserialize credentials using domain's built-in decryption
+	creds, err := domain.DeserializeCredentials(item.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize credentials: %w", err)
+	}
+
+	return creds, nil
+}
+
+// Delete removes credentials for an account from the keychain
+func (s *KeychainCredentialStore) Delete(_ context.Context, accountID domain.AccountID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := itemKey(accountID)
+
+	// Some backends silently ignore removal of missing items, so check first
+	if _, err := s.ring.Get(key); errors.Is(err, keyring.ErrKeyNotFound) {
+		return domain.ErrCredentialsNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to read keychain item: %w", err)
+	}
+
+	if err := s.ring.Remove(key); errors.Is(err, keyring.ErrKeyNotFound) {
+		return domain.ErrCredentialsNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to delete keychain item: %w", err)
+	}
+
+	return nil
+}
+
+// ListAccountIDs returns the IDs of all accounts with a ccx keychain item, sorted
+func (s *KeychainCredentialStore) ListAccountIDs(_ context.Context) ([]domain.AccountID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys, err := s.ring.Keys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keychain items: %w", err)
+	}
+
+	ids := make([]domain.AccountID, 0, len(keys))
+	for _, key := range keys {
+		if id, ok := strings.CutPrefix(key, keyPrefix); ok && id != "" {
+			ids = append(ids, domain.AccountID(id))
+		}
+	}
+	slices.Sort(ids)
+
+	return ids, nil
+}
+
+func itemKey(accountID domain.AccountID) string {
+	return keyPrefix + string(accountID)
+}
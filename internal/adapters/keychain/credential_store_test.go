@@ -0,0 +1,163 @@
+package keychain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/99designs/keyring"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+// failingKeyring is a keyring whose operations always fail
+type failingKeyring struct {
+	*keyring.ArrayKeyring
+	err error
+}
+
+func (k *failingKeyring) Get(_ string) (keyring.Item, error) {
+	return keyring.Item{}, k.err
+}
+
+func (k *failingKeyring) Set(_ keyring.Item) error {
+	return k.err
+}
+
+func TestKeychainCredentialStore_StoreAndRetrieve(t *testing.T) {
+	ring := keyring.NewArrayKeyring(nil)
+	store := NewKeychainCredentialStoreWithKeyring(ring)
+	ctx := context.Background()
+
+	accountID := domain.AccountID("abc12345")
+	testData := []byte(`{"sessionKey":"secret-key"}`)
+	creds, err := domain.NewCredentials(accountID, testData)
+	if err != nil {
+		t.Fatalf("Failed to create credentials: %v", err)
+	}
+
+	if err := store.Store(ctx, creds); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	// Verify item is keyed by ccx:<accountID>
+	item, err := ring.Get("ccx:abc12345")
+	if err != nil {
+		t.Fatalf("Expected keychain item ccx:abc12345, got error %v", err)
+	}
+	if len(item.Data) == 0 {
+		t.Error("Keychain item should contain serialized credentials")
+	}
+
+	retrieved, err := store.Retrieve(ctx, accountID)
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	decrypted, err := retrieved.Decrypt()
+	if err != nil {
+		t.Fatalf("Failed to decrypt retrieved credentials: %v", err)
+	}
+	if string(decrypted) != string(testData) {
+		t.Errorf("Decrypted data = %s, want %s", decrypted, testData)
+	}
+}
+
+func TestKeychainCredentialStore_StoreUpserts(t *testing.T) {
+	store := NewKeychainCredentialStoreWithKeyring(keyring.NewArrayKeyring(nil))
+	ctx := context.Background()
+
+	accountID := domain.AccountID("abc12345")
+	first, _ := domain.NewCredentials(accountID, []byte(`{"sessionKey":"old"}`))
+	second, _ := domain.NewCredentials(accountID, []byte(`{"sessionKey":"new"}`))
+
+	if err := store.Store(ctx, first); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	if err := store.Store(ctx, second); err != nil {
+		t.Fatalf("Store() over existing item error = %v", err)
+	}
+
+	retrieved, err := store.Retrieve(ctx, accountID)
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	decrypted, _ := retrieved.Decrypt()
+	if string(decrypted) != `{"sessionKey":"new"}` {
+		t.Errorf("Decrypted data = %s, want updated credentials", decrypted)
+	}
+}
+
+func TestKeychainCredentialStore_NotFound(t *testing.T) {
+	store := NewKeychainCredentialStoreWithKeyring(keyring.NewArrayKeyring(nil))
+	ctx := context.Background()
+
+	if _, err := store.Retrieve(ctx, "missing"); !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Retrieve() error = %v, want ErrCredentialsNotFound", err)
+	}
+	if err := store.Delete(ctx, "missing"); !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Delete() error = %v, want ErrCredentialsNotFound", err)
+	}
+}
+
+func TestKeychainCredentialStore_Delete(t *testing.T) {
+	ring := keyring.NewArrayKeyring(nil)
+	store := NewKeychainCredentialStoreWithKeyring(ring)
+	ctx := context.Background()
+
+	accountID := domain.AccountID("abc12345")
+	creds, _ := domain.NewCredentials(accountID, []byte(`{"sessionKey":"secret"}`))
+	_ = store.Store(ctx, creds)
+
+	if err := store.Delete(ctx, accountID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := ring.Get("ccx:abc12345"); !errors.Is(err, keyring.ErrKeyNotFound) {
+		t.Errorf("Expected keychain item to be removed, got %v", err)
+	}
+
+	// Second delete reports that nothing was removed
+	if err := store.Delete(ctx, accountID); err == nil {
+		t.Error("Delete() of already removed credentials should return not found")
+	}
+}
+
+func TestKeychainCredentialStore_ListAccountIDs(t *testing.T) {
+	ring := keyring.NewArrayKeyring(nil)
+	store := &KeychainCredentialStore{ring: ring}
+	ctx := context.Background()
+
+	for _, id := range []domain.AccountID{"bbbb2222", "aaaa1111"} {
+		creds, _ := domain.NewCredentials(id, []byte(`{"sessionKey":"secret"}`))
+		_ = store.Store(ctx, creds)
+	}
+	// Items outside the ccx namespace are ignored
+	_ = ring.Set(keyring.Item{Key: "other-app", Data: []byte("x")})
+
+	ids, err := store.ListAccountIDs(ctx)
+	if err != nil {
+		t.Fatalf("ListAccountIDs() error = %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "aaaa1111" || ids[1] != "bbbb2222" {
+		t.Errorf("ListAccountIDs() = %v, want [aaaa1111 bbbb2222]", ids)
+	}
+}
+
+func TestKeychainCredentialStore_BackendErrors(t *testing.T) {
+	backendErr := errors.New("keychain locked")
+	store := NewKeychainCredentialStoreWithKeyring(&failingKeyring{
+		ArrayKeyring: keyring.NewArrayKeyring(nil),
+		err:          backendErr,
+	})
+	ctx := context.Background()
+
+	creds, _ := domain.NewCredentials("abc12345", []byte(`{"sessionKey":"secret"}`))
+	if err := store.Store(ctx, creds); !errors.Is(err, backendErr) {
+		t.Errorf("Store() error = %v, want wrapped %v", err, backendErr)
+	}
+	if _, err := store.Retrieve(ctx, "abc12345"); !errors.Is(err, backendErr) {
+		t.Errorf("Retrieve() error = %v, want wrapped %v", err, backendErr)
+	}
+	if err := store.Delete(ctx, "abc12345"); !errors.Is(err, backendErr) {
+		t.Errorf("Delete() error = %v, want wrapped %v", err, backendErr)
+	}
+}
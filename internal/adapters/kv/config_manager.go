@@ -0,0 +1,99 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// Keys used to persist the current account
+const (
+	keyCurrentID    = "current_account_id"
+	keyCurrentEmail = "current_account_email"
+	keyCurrentUUID  = "current_account_uuid"
+	keySession      = "current_session" // Decrypted session JSON of the current account
+)
+
+// KVConfigManager implements ConfigManager over a key-value store, tracking only
+// the current account's ID, email, UUID, and session
+type KVConfigManager struct { //nolint:revive // keeps the backend in the name like BasicConfigManager
+	store Store
+}
+
+// NewKVConfigManager creates a config manager backed by the given store
+func NewKVConfigManager(store Store) ports.ConfigManager {
+	return &KVConfigManager{
+		store: store,
+	}
+}
+
+// GetCurrentAccount reads the current account from the store.
+// Returns nil if no account is recorded.
+func (m *KVConfigManager) GetCurrentAccount(ctx context.Context) (*domain.Account, error) {
+	email, ok, err := m.store.Get(ctx, keyCurrentEmail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current account: %w", err)
+	}
+	if !ok || email == "" {
+		return nil, nil
+	}
+
+	id, _, err := m.store.Get(ctx, keyCurrentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current account: %w", err)
+	}
+
+	uuid, _, err := m.store.Get(ctx, keyCurrentUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current account: %w", err)
+	}
+
+	// Only identity is tracked, so timestamps are left zero
+	account, err := domain.ReconstructAccount(domain.AccountID(id), email, "", uuid, nil, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create account from store: %w", err)
+	}
+
+	return account, nil
+}
+
+// SetCurrentAccount records account as current; a nil account clears it
+func (m *KVConfigManager) SetCurrentAccount(ctx context.Context, account *domain.Account) error {
+	if account == nil {
+		for _, key := range []string{keyCurrentEmail, keyCurrentID, keyCurrentUUID} {
+			if err := m.store.Delete(ctx, key); err != nil {
+				return fmt.Errorf("failed to clear current account: %w", err)
+			}
+		}
+		return nil
+	}
+
+	values := []struct{ key, value string }{
+		{keyCurrentID, string(account.ID())},
+		{keyCurrentUUID, account.UUID()},
+		// Email is written last since its presence marks an account as current
+		{keyCurrentEmail, string(account.Email())},
+	}
+	for _, v := range values {
+		if err := m.store.Set(ctx, v.key, v.value); err != nil {
+			return fmt.Errorf("failed to write current account: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SetCredentials decrypts creds and stores the session JSON for the current account
+func (m *KVConfigManager) SetCredentials(ctx context.Context, creds *domain.Credentials) error {
+	plaintext, err := creds.Decrypt()
+	if err != nil {
+		return fmt.Errorf("failed to decrypt credentials: %w", err)
+	}
+	if err := m.store.Set(ctx, keySession, string(plaintext)); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package kv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestKVConfigManager_RoundTrip(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-kv-config-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"file":   NewFileStore(filepath.Join(tmpDir, "current.json")),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			manager := NewKVConfigManager(store)
+			ctx := context.Background()
+
+			// No current account initially
+			current, err := manager.GetCurrentAccount(ctx)
+			if err != nil {
+				t.Fatalf("GetCurrentAccount() error = %v", err)
+			}
+			if current != nil {
+				t.Fatal("Expected nil account when nothing is stored")
+			}
+
+			account, err := domain.NewAccount("kv@example.com", "kv", "uuid-kv")
+			if err != nil {
+				t.Fatalf("Failed to create account: %v", err)
+			}
+
+			if err := manager.SetCurrentAccount(ctx, account); err != nil {
+				t.Fatalf("SetCurrentAccount() error = %v", err)
+			}
+
+			current, err = manager.GetCurrentAccount(ctx)
+			if err != nil {
+				t.Fatalf("GetCurrentAccount() error = %v", err)
+			}
+			if current == nil {
+				t.Fatal("Expected current account after setting")
+			}
+
+			// Unlike the Claude config, the ccx account ID survives the round trip
+			if current.ID() != account.ID() {
+				t.Errorf("Expected ID %v, got %v", account.ID(), current.ID())
+			}
+			if current.Email() != account.Email() {
+				t.Errorf("Expected email %v, got %v", account.Email(), current.Email())
+			}
+			if current.UUID() != account.UUID() {
+				t.Errorf("Expected UUID %v, got %v", account.UUID(), current.UUID())
+			}
+
+			creds, _ := domain.NewCredentials(account.ID(), []byte(`{"sessionKey":"kv-key"}`))
+			if err := manager.SetCredentials(ctx, creds); err != nil {
+				t.Fatalf("SetCredentials() error = %v", err)
+			}
+			if session, ok, _ := store.Get(ctx, keySession); !ok || session != `{"sessionKey":"kv-key"}` {
+				t.Errorf("Stored session = %q, %v; want the decrypted credentials", session, ok)
+			}
+
+			// Setting nil clears the current account
+			if err := manager.SetCurrentAccount(ctx, nil); err != nil {
+				t.Fatalf("SetCurrentAccount(nil) error = %v", err)
+			}
+			current, err = manager.GetCurrentAccount(ctx)
+			if err != nil {
+				t.Fatalf("GetCurrentAccount() error = %v", err)
+			}
+			if current != nil {
+				t.Error("Expected nil account after clearing")
+			}
+		})
+	}
+}
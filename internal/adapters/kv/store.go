@@ -0,0 +1,161 @@
+// Package kv provides adapters backed by a plain key-value store, decoupled from
+// Claude's configuration file format.
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store is a minimal string key-value store
+type Store interface {
+	// Get returns the value for key and whether it was present
+	Get(ctx context.Context, key string) (string, bool, error)
+
+	// Set stores value under key
+	Set(ctx context.Context, key, value string) error
+
+	// Delete removes key; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryStore implements Store in memory
+type MemoryStore struct {
+	values map[string]string
+	mu     sync.RWMutex
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		values: make(map[string]string),
+	}
+}
+
+// Get returns the value for key
+func (s *MemoryStore) Get(_ context.Context, key string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.values[key]
+	return value, ok, nil
+}
+
+// Set stores value under key
+func (s *MemoryStore) Set(_ context.Context, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+	return nil
+}
+
+// Delete removes key
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, key)
+	return nil
+}
+
+// FileStore implements Store as a flat JSON object in a single file
+type FileStore struct {
+	path string
+	mu   sync.RWMutex
+}
+
+// NewFileStore creates a store persisted at path
+func NewFileStore(path string) *FileStore {
+	return &FileStore{
+		path: path,
+	}
+}
+
+// Get returns the value for key
+func (s *FileStore) Get(_ context.Context, key string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	values, err := s.load()
+	if err != nil {
+		return "", false, err
+	}
+
+	value, ok := values[key]
+	return value, ok, nil
+}
+
+// Set stores value under key
+func (s *FileStore) Set(_ context.Context, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	values[key] = value
+	return s.save(values)
+}
+
+// Delete removes key
+func (s *FileStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := values[key]; !ok {
+		return nil
+	}
+
+	delete(values, key)
+	return s.save(values)
+}
+
+// load reads all values, returning an empty map if the file doesn't exist
+func (s *FileStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(s.path) // #nosec G304 - controlled file path within app data directory
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return make(map[string]string), nil
+		}
+		return nil, fmt.Errorf("failed to read store file: %w", err)
+	}
+
+	values := make(map[string]string)
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse store file: %w", err)
+	}
+
+	return values, nil
+}
+
+// save writes all values to the file
+func (s *FileStore) save(values map[string]string) error {
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write store file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package kv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStores_RoundTrip(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-kv-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"file":   NewFileStore(filepath.Join(tmpDir, "nested", "kv.json")),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			if _, ok, err := store.Get(ctx, "missing"); err != nil || ok {
+				t.Errorf("Get(missing) = ok %v, err %v; want not found", ok, err)
+			}
+
+			if err := store.Set(ctx, "key", "value"); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+
+			value, ok, err := store.Get(ctx, "key")
+			if err != nil || !ok || value != "value" {
+				t.Errorf("Get(key) = %q, %v, %v; want value, true, nil", value, ok, err)
+			}
+
+			if err := store.Delete(ctx, "key"); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if _, ok, _ := store.Get(ctx, "key"); ok {
+				t.Error("Expected key to be deleted")
+			}
+
+			// Deleting a missing key is not an error
+			if err := store.Delete(ctx, "key"); err != nil {
+				t.Errorf("Delete(missing) error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestFileStore_Permissions(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccx-kv-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	path := filepath.Join(tmpDir, "kv.json")
+	if err := NewFileStore(path).Set(context.Background(), "key", "value"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("store file was not created: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected permissions 0600, got %o", info.Mode().Perm())
+	}
+
+	// A fresh store over the same file sees the persisted value
+	value, ok, err := NewFileStore(path).Get(context.Background(), "key")
+	if err != nil || !ok || value != "value" {
+		t.Errorf("Get(key) = %q, %v, %v; want value, true, nil", value, ok, err)
+	}
+}
@@ -0,0 +1,101 @@
+// Package memory provides in-memory adapters for every port, for embedding ccx as a
+// library or running it without touching the filesystem. Values are copied on the way
+// in and out, so callers never share state with the store, and every adapter is safe
+// for concurrent use.
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// AccountRepository implements ports.AccountRepository in memory
+type AccountRepository struct {
+	accounts []*domain.Account // In save order, like the file repository
+	mu       sync.RWMutex
+}
+
+// Ensure AccountRepository implements ports.AccountRepository at compile time
+var _ ports.AccountRepository = (*AccountRepository)(nil)
+
+// NewAccountRepository creates an empty in-memory account repository
+func NewAccountRepository() *AccountRepository {
+	return &AccountRepository{}
+}
+
+// Save stores a copy of the account, replacing any account with the same ID
+func (r *AccountRepository) Save(_ context.Context, account *domain.Account) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.accounts, func(a *domain.Account) bool { return a.ID() == account.ID() })
+	if i >= 0 {
+		r.accounts[i] = account.Clone()
+		return nil
+	}
+	r.accounts = append(r.accounts, account.Clone())
+	return nil
+}
+
+// FindByID retrieves a copy of the account with the given ID
+func (r *AccountRepository) FindByID(_ context.Context, id domain.AccountID) (*domain.Account, error) {
+	return r.find(func(a *domain.Account) bool { return a.ID() == id })
+}
+
+// FindByEmail retrieves a copy of the account with the given email, ignoring case and
+// surrounding whitespace
+func (r *AccountRepository) FindByEmail(_ context.Context, email domain.Email) (*domain.Account, error) {
+	email = domain.NormalizeEmail(string(email))
+	return r.find(func(a *domain.Account) bool { return a.Email() == email })
+}
+
+// FindByAlias retrieves a copy of the account with the given alias
+func (r *AccountRepository) FindByAlias(_ context.Context, alias string) (*domain.Account, error) {
+	return r.find(func(a *domain.Account) bool { return a.Alias() == alias })
+}
+
+// FindByUUID retrieves a copy of the account with the given Claude account UUID
+func (r *AccountRepository) FindByUUID(_ context.Context, uuid string) (*domain.Account, error) {
+	return r.find(func(a *domain.Account) bool { return a.UUID() == uuid })
+}
+
+// List returns copies of all accounts in save order
+func (r *AccountRepository) List(_ context.Context) ([]*domain.Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	accounts := make([]*domain.Account, len(r.accounts))
+	for i, account := range r.accounts {
+		accounts[i] = account.Clone()
+	}
+	return accounts, nil
+}
+
+// Delete removes the account with the given ID
+func (r *AccountRepository) Delete(_ context.Context, id domain.AccountID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.accounts, func(a *domain.Account) bool { return a.ID() == id })
+	if i < 0 {
+		return domain.ErrAccountNotFound
+	}
+	r.accounts = slices.Delete(r.accounts, i, i+1)
+	return nil
+}
+
+// find returns a copy of the first account matching match
+func (r *AccountRepository) find(match func(*domain.Account) bool) (*domain.Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := slices.IndexFunc(r.accounts, match)
+	if i < 0 {
+		return nil, domain.ErrAccountNotFound
+	}
+	return r.accounts[i].Clone(), nil
+}
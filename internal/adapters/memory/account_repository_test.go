@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestAccountRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAccountRepository()
+
+	work, _ := domain.NewAccount("work@example.com", "work", "uuid-work")
+	personal, _ := domain.NewAccount("personal@example.com", "personal", "uuid-personal")
+	for _, account := range []*domain.Account{work, personal} {
+		if err := repo.Save(ctx, account); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	lookups := map[string]func() (*domain.Account, error){
+		"FindByID":    func() (*domain.Account, error) { return repo.FindByID(ctx, work.ID()) },
+		"FindByEmail": func() (*domain.Account, error) { return repo.FindByEmail(ctx, " WORK@example.com") },
+		"FindByAlias": func() (*domain.Account, error) { return repo.FindByAlias(ctx, "work") },
+		"FindByUUID":  func() (*domain.Account, error) { return repo.FindByUUID(ctx, "uuid-work") },
+	}
+	for name, lookup := range lookups {
+		found, err := lookup()
+		if err != nil || found.ID() != work.ID() {
+			t.Errorf("%s() = %v, %v; want %s", name, found, err, work.ID())
+		}
+	}
+
+	// Saving an existing ID updates it in place
+	_ = work.UpdateAlias("job")
+	_ = repo.Save(ctx, work)
+	accounts, _ := repo.List(ctx)
+	if len(accounts) != 2 || accounts[0].Alias() != "job" || accounts[1].ID() != personal.ID() {
+		t.Errorf("List() = %v, want [job personal] in save order", accounts)
+	}
+
+	if err := repo.Delete(ctx, work.ID()); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.FindByID(ctx, work.ID()); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("FindByID() after Delete error = %v, want ErrAccountNotFound", err)
+	}
+	if err := repo.Delete(ctx, work.ID()); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("Delete() twice error = %v, want ErrAccountNotFound", err)
+	}
+}
+
+// TestAccountRepository_CopySemantics tests that callers never share accounts with the repository
+func TestAccountRepository_CopySemantics(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAccountRepository()
+
+	account, _ := domain.NewAccount("work@example.com", "work", "uuid-work")
+	_ = repo.Save(ctx, account)
+
+	// Changing the saved value doesn't reach the repository
+	_ = account.UpdateAlias("changed")
+	found, _ := repo.FindByID(ctx, account.ID())
+	if found.Alias() != "work" {
+		t.Errorf("Alias() = %s after changing the saved account, want work", found.Alias())
+	}
+
+	// Nor does changing a returned value
+	_ = found.AddTag("client")
+	again, _ := repo.FindByID(ctx, account.ID())
+	if again.HasTag("client") || again == found {
+		t.Error("FindByID() returned an account aliased to the repository")
+	}
+}
+
+// TestAccountRepository_Concurrent tests parallel reads and writes. Run with -race.
+func TestAccountRepository_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAccountRepository()
+	account, _ := domain.NewAccount("work@example.com", "work", "uuid-work")
+	_ = repo.Save(ctx, account)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			found, err := repo.FindByID(ctx, account.ID())
+			if err != nil {
+				t.Errorf("FindByID() error = %v", err)
+				return
+			}
+			found.MarkUsed()
+			_ = repo.Save(ctx, found)
+		}()
+		go func() {
+			defer wg.Done()
+			accounts, _ := repo.List(ctx)
+			for _, a := range accounts {
+				_ = a.LastUsed()
+			}
+		}()
+	}
+	wg.Wait()
+}
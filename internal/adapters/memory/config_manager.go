@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// ConfigManager implements ports.ConfigManager in memory, standing in for Claude's config
+type ConfigManager struct {
+	current     *domain.Account
+	credentials *domain.Credentials
+	mu          sync.RWMutex
+}
+
+// Ensure ConfigManager implements ports.ConfigManager at compile time
+var _ ports.ConfigManager = (*ConfigManager)(nil)
+
+// NewConfigManager creates an in-memory config manager with no current account
+func NewConfigManager() *ConfigManager {
+	return &ConfigManager{}
+}
+
+// GetCurrentAccount returns a copy of the current account, or nil if there is none
+func (m *ConfigManager) GetCurrentAccount(_ context.Context) (*domain.Account, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.current == nil {
+		return nil, nil
+	}
+	return m.current.Clone(), nil
+}
+
+// SetCurrentAccount records a copy of account as current; a nil account clears it
+func (m *ConfigManager) SetCurrentAccount(_ context.Context, account *domain.Account) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if account == nil {
+		m.current = nil
+		return nil
+	}
+	m.current = account.Clone()
+	return nil
+}
+
+// SetCredentials records a copy of the credentials Claude would authenticate with.
+// Like the file-based manager, it fails if they cannot be decrypted.
+func (m *ConfigManager) SetCredentials(_ context.Context, creds *domain.Credentials) error {
+	if _, err := creds.Decrypt(); err != nil {
+		return fmt.Errorf("failed to decrypt credentials: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.credentials = creds.Clone()
+	return nil
+}
+
+// CurrentCredentials returns a copy of the credentials last set, or nil if none were
+func (m *ConfigManager) CurrentCredentials() *domain.Credentials {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.credentials == nil {
+		return nil
+	}
+	return m.credentials.Clone()
+}
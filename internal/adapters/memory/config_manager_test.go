@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestConfigManager(t *testing.T) {
+	ctx := context.Background()
+	config := NewConfigManager()
+
+	if current, err := config.GetCurrentAccount(ctx); current != nil || err != nil {
+		t.Errorf("GetCurrentAccount() = %v, %v; want nil, nil", current, err)
+	}
+
+	account, _ := domain.NewAccount("work@example.com", "work", "uuid-work")
+	if err := config.SetCurrentAccount(ctx, account); err != nil {
+		t.Fatalf("SetCurrentAccount() error = %v", err)
+	}
+	_ = account.UpdateAlias("changed")
+
+	current, _ := config.GetCurrentAccount(ctx)
+	if current.ID() != account.ID() || current.Alias() != "work" {
+		t.Errorf("GetCurrentAccount() = %s/%s, want %s/work", current.ID(), current.Alias(), account.ID())
+	}
+
+	if creds := config.CurrentCredentials(); creds != nil {
+		t.Errorf("CurrentCredentials() = %v before setting, want nil", creds)
+	}
+	creds, _ := domain.NewCredentials(account.ID(), []byte(`{"sessionKey": "key"}`))
+	if err := config.SetCredentials(ctx, creds); err != nil {
+		t.Fatalf("SetCredentials() error = %v", err)
+	}
+	if got := config.CurrentCredentials(); got == nil || got.AccountID() != account.ID() || got == creds {
+		t.Errorf("CurrentCredentials() = %v, want a copy of the credentials", got)
+	}
+
+	if err := config.SetCurrentAccount(ctx, nil); err != nil {
+		t.Fatalf("SetCurrentAccount(nil) error = %v", err)
+	}
+	if current, _ := config.GetCurrentAccount(ctx); current != nil {
+		t.Errorf("GetCurrentAccount() = %v after clearing, want nil", current)
+	}
+}
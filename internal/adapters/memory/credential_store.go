@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// CredentialStore implements ports.CredentialStore and ports.CredentialLister in memory
+type CredentialStore struct {
+	credentials map[domain.AccountID]*domain.Credentials
+	mu          sync.RWMutex
+}
+
+// Ensure CredentialStore implements the credential ports at compile time
+var (
+	_ ports.CredentialStore  = (*CredentialStore)(nil)
+	_ ports.CredentialLister = (*CredentialStore)(nil)
+)
+
+// NewCredentialStore creates an empty in-memory credential store
+func NewCredentialStore() *CredentialStore {
+	return &CredentialStore{
+		credentials: make(map[domain.AccountID]*domain.Credentials),
+	}
+}
+
+// Store saves a copy of the credentials, replacing any stored for the same account
+func (s *CredentialStore) Store(_ context.Context, creds *domain.Credentials) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.credentials[creds.AccountID()] = creds.Clone()
+	return nil
+}
+
+// Retrieve returns a copy of the credentials for an account
+func (s *CredentialStore) Retrieve(_ context.Context, accountID domain.AccountID) (*domain.Credentials, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	creds, ok := s.credentials[accountID]
+	if !ok {
+		return nil, domain.ErrCredentialsNotFound
+	}
+	return creds.Clone(), nil
+}
+
+// Delete removes the credentials for an account
+func (s *CredentialStore) Delete(_ context.Context, accountID domain.AccountID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.credentials[accountID]; !ok {
+		return domain.ErrCredentialsNotFound
+	}
+	delete(s.credentials, accountID)
+	return nil
+}
+
+// ListAccountIDs returns the sorted IDs of every account with stored credentials
+func (s *CredentialStore) ListAccountIDs(_ context.Context) ([]domain.AccountID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]domain.AccountID, 0, len(s.credentials))
+	for id := range s.credentials {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
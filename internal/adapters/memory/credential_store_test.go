@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestCredentialStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewCredentialStore()
+
+	creds, _ := domain.NewCredentials("bbbb2222", []byte(`{"sessionKey":"original"}`))
+	other, _ := domain.NewCredentials("aaaa1111", []byte(`{"sessionKey":"other"}`))
+	for _, c := range []*domain.Credentials{creds, other} {
+		if err := store.Store(ctx, c); err != nil {
+			t.Fatalf("Store() error = %v", err)
+		}
+	}
+
+	// Rotating the stored value leaves the store's copy alone
+	_ = creds.UpdateData([]byte(`{"sessionKey":"rotated"}`))
+	retrieved, err := store.Retrieve(ctx, "bbbb2222")
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if data, _ := retrieved.Decrypt(); string(data) != `{"sessionKey":"original"}` {
+		t.Errorf("Retrieve() data = %s, want the original", data)
+	}
+
+	ids, _ := store.ListAccountIDs(ctx)
+	if len(ids) != 2 || ids[0] != "aaaa1111" || ids[1] != "bbbb2222" {
+		t.Errorf("ListAccountIDs() = %v, want sorted IDs", ids)
+	}
+
+	if err := store.Delete(ctx, "bbbb2222"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Retrieve(ctx, "bbbb2222"); !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Retrieve() after Delete error = %v, want ErrCredentialsNotFound", err)
+	}
+	if err := store.Delete(ctx, "bbbb2222"); !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Delete() twice error = %v, want ErrCredentialsNotFound", err)
+	}
+}
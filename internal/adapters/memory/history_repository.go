@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// HistoryRepository implements ports.HistoryRepository in memory
+type HistoryRepository struct {
+	history *domain.History
+	mu      sync.RWMutex
+}
+
+// Ensure HistoryRepository implements ports.HistoryRepository at compile time
+var _ ports.HistoryRepository = (*HistoryRepository)(nil)
+
+// NewHistoryRepository creates an in-memory history repository holding an empty
+// history of domain.DefaultHistorySize entries
+func NewHistoryRepository() *HistoryRepository {
+	return &HistoryRepository{
+		history: domain.NewHistory(domain.DefaultHistorySize),
+	}
+}
+
+// SaveHistory stores a copy of the history
+func (r *HistoryRepository) SaveHistory(_ context.Context, history *domain.History) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history = history.Clone()
+	return nil
+}
+
+// LoadHistory returns a copy of the stored history
+func (r *HistoryRepository) LoadHistory(_ context.Context) (*domain.History, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.history.Clone(), nil
+}
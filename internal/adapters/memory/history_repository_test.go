@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestHistoryRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewHistoryRepository()
+
+	history, _ := repo.LoadHistory(ctx)
+	if history.MaxEntries() != domain.DefaultHistorySize || len(history.Entries()) != 0 {
+		t.Errorf("LoadHistory() = max %d with %d entries, want empty default", history.MaxEntries(), len(history.Entries()))
+	}
+
+	entry, _ := domain.NewSwitchEntry("a@example.com", "b@example.com")
+	history.AddEntry(entry)
+	if loaded, _ := repo.LoadHistory(ctx); len(loaded.Entries()) != 0 {
+		t.Error("Changing a loaded history changed the stored one")
+	}
+
+	if err := repo.SaveHistory(ctx, history); err != nil {
+		t.Fatalf("SaveHistory() error = %v", err)
+	}
+	history.Clear()
+	loaded, _ := repo.LoadHistory(ctx)
+	if entries := loaded.Entries(); len(entries) != 1 || entries[0].To() != "b@example.com" {
+		t.Errorf("LoadHistory() entries = %v, want the saved switch", entries)
+	}
+}
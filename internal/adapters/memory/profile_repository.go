@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// ProfileRepository implements ports.ProfileRepository in memory
+type ProfileRepository struct {
+	profiles map[string]*domain.Profile
+	mu       sync.RWMutex
+}
+
+// Ensure ProfileRepository implements ports.ProfileRepository at compile time
+var _ ports.ProfileRepository = (*ProfileRepository)(nil)
+
+// NewProfileRepository creates an empty in-memory profile repository
+func NewProfileRepository() *ProfileRepository {
+	return &ProfileRepository{
+		profiles: make(map[string]*domain.Profile),
+	}
+}
+
+// Save stores a copy of the profile, replacing any profile with the same name
+func (r *ProfileRepository) Save(_ context.Context, profile *domain.Profile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.profiles[profile.Name()] = profile.Clone()
+	return nil
+}
+
+// FindByName returns a copy of the named profile
+func (r *ProfileRepository) FindByName(_ context.Context, name string) (*domain.Profile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	profile, ok := r.profiles[name]
+	if !ok {
+		return nil, domain.ErrProfileNotFound
+	}
+	return profile.Clone(), nil
+}
+
+// List returns copies of all profiles sorted by name
+func (r *ProfileRepository) List(_ context.Context) ([]*domain.Profile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*domain.Profile, 0, len(r.profiles))
+	for _, profile := range r.profiles {
+		result = append(result, profile.Clone())
+	}
+	slices.SortFunc(result, func(a, b *domain.Profile) int { return cmp.Compare(a.Name(), b.Name()) })
+	return result, nil
+}
+
+// Delete removes the named profile
+func (r *ProfileRepository) Delete(_ context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.profiles[name]; !ok {
+		return domain.ErrProfileNotFound
+	}
+	delete(r.profiles, name)
+	return nil
+}
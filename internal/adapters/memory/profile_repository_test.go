@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestProfileRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewProfileRepository()
+
+	for _, name := range []string{"personal", "client-a"} {
+		profile, _ := domain.NewProfile(name, "abc12345")
+		if err := repo.Save(ctx, profile); err != nil {
+			t.Fatalf("Save(%s) error = %v", name, err)
+		}
+	}
+
+	profiles, _ := repo.List(ctx)
+	if len(profiles) != 2 || profiles[0].Name() != "client-a" {
+		t.Errorf("List() = %v, want client-a first of 2", profiles)
+	}
+
+	if err := repo.Delete(ctx, "client-a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.FindByName(ctx, "client-a"); !errors.Is(err, domain.ErrProfileNotFound) {
+		t.Errorf("FindByName() error = %v, want ErrProfileNotFound", err)
+	}
+	if err := repo.Delete(ctx, "client-a"); !errors.Is(err, domain.ErrProfileNotFound) {
+		t.Errorf("Delete() of missing profile error = %v, want ErrProfileNotFound", err)
+	}
+}
@@ -0,0 +1,42 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/adapters/memory"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// TestServices_WithMemoryAdapters tests that the use cases run end to end on the
+// in-memory adapters
+func TestServices_WithMemoryAdapters(t *testing.T) {
+	ctx := context.Background()
+	accounts := memory.NewAccountRepository()
+	credentials := memory.NewCredentialStore()
+	config := memory.NewConfigManager()
+	history := memory.NewHistoryRepository()
+
+	add := usecases.NewAddAccountService(accounts, credentials, config)
+	for _, email := range []string{"personal@example.com", "work@example.com"} {
+		if err := add.Execute(ctx, usecases.AddAccountInput{Email: email, Credentials: []byte(`{"sessionKey":"key"}`)}); err != nil {
+			t.Fatalf("AddAccount(%s) error = %v", email, err)
+		}
+	}
+
+	switchAccount := usecases.NewSwitchAccountService(accounts, credentials, config, history)
+	for _, email := range []string{"personal@example.com", "work@example.com"} {
+		if _, err := switchAccount.Execute(ctx, usecases.SwitchAccountInput{Email: email}); err != nil {
+			t.Fatalf("SwitchAccount(%s) error = %v", email, err)
+		}
+	}
+
+	current, _ := config.GetCurrentAccount(ctx)
+	if current == nil || current.Email() != "work@example.com" {
+		t.Errorf("Current account = %v, want work@example.com", current)
+	}
+	loaded, _ := history.LoadHistory(ctx)
+	if last := loaded.GetLastSwitch(); last == nil || last.From() != "personal@example.com" {
+		t.Errorf("Last switch = %v, want from personal@example.com", last)
+	}
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// SettingsRepository implements ports.SettingsRepository in memory
+type SettingsRepository struct {
+	settings *domain.Settings
+	mu       sync.RWMutex
+}
+
+// Ensure SettingsRepository implements ports.SettingsRepository at compile time
+var _ ports.SettingsRepository = (*SettingsRepository)(nil)
+
+// NewSettingsRepository creates an in-memory settings repository with no preferences set
+func NewSettingsRepository() *SettingsRepository {
+	return &SettingsRepository{
+		settings: domain.NewSettings(),
+	}
+}
+
+// LoadSettings returns a copy of the stored settings
+func (r *SettingsRepository) LoadSettings(_ context.Context) (*domain.Settings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.settings.Clone(), nil
+}
+
+// SaveSettings stores a copy of the settings
+func (r *SettingsRepository) SaveSettings(_ context.Context, settings *domain.Settings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.settings = settings.Clone()
+	return nil
+}
@@ -0,0 +1,29 @@
+package memory
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSettingsRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSettingsRepository()
+
+	settings, _ := repo.LoadSettings(ctx)
+	if settings.HasDefaultAccount() {
+		t.Errorf("LoadSettings() default = %s, want none", settings.DefaultAccountID())
+	}
+
+	settings.SetDefaultAccountID("abc12345")
+	if loaded, _ := repo.LoadSettings(ctx); loaded.HasDefaultAccount() {
+		t.Error("Changing loaded settings changed the stored ones")
+	}
+
+	if err := repo.SaveSettings(ctx, settings); err != nil {
+		t.Fatalf("SaveSettings() error = %v", err)
+	}
+	settings.SetDefaultAccountID("")
+	if loaded, _ := repo.LoadSettings(ctx); loaded.DefaultAccountID() != "abc12345" {
+		t.Errorf("LoadSettings() default = %s, want abc12345", loaded.DefaultAccountID())
+	}
+}
@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// TombstoneRepository implements ports.TombstoneRepository in memory
+type TombstoneRepository struct {
+	tombstones []*domain.Tombstone // Most recent first
+	mu         sync.RWMutex
+}
+
+// Ensure TombstoneRepository implements ports.TombstoneRepository at compile time
+var _ ports.TombstoneRepository = (*TombstoneRepository)(nil)
+
+// NewTombstoneRepository creates an empty in-memory tombstone repository
+func NewTombstoneRepository() *TombstoneRepository {
+	return &TombstoneRepository{}
+}
+
+// Add records a tombstone, dropping the oldest beyond domain.MaxTombstones
+func (r *TombstoneRepository) Add(_ context.Context, tombstone *domain.Tombstone) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tombstones = slices.Insert(r.tombstones, 0, tombstone)
+	if len(r.tombstones) > domain.MaxTombstones {
+		r.tombstones = r.tombstones[:domain.MaxTombstones]
+	}
+	return nil
+}
+
+// List returns the tombstones, most recently removed first. Tombstones are immutable,
+// so they are shared rather than copied.
+func (r *TombstoneRepository) List(_ context.Context) ([]*domain.Tombstone, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]*domain.Tombstone{}, r.tombstones...), nil
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestTombstoneRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewTombstoneRepository()
+
+	if tombstones, _ := repo.List(ctx); len(tombstones) != 0 {
+		t.Errorf("List() = %v, want empty", tombstones)
+	}
+
+	for i := range domain.MaxTombstones + 1 {
+		account, _ := domain.NewAccount(fmt.Sprintf("user%d@example.com", i), "", "uuid")
+		tombstone, _ := domain.NewTombstone(account, time.Now(), "")
+		if err := repo.Add(ctx, tombstone); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	tombstones, _ := repo.List(ctx)
+	if len(tombstones) != domain.MaxTombstones {
+		t.Fatalf("List() returned %d tombstones, want %d", len(tombstones), domain.MaxTombstones)
+	}
+	if tombstones[0].Email() != "user100@example.com" || tombstones[len(tombstones)-1].Email() != "user1@example.com" {
+		t.Errorf("List() = %s..%s, want newest first with the oldest dropped", tombstones[0].Email(), tombstones[len(tombstones)-1].Email())
+	}
+}
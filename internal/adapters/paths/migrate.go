@@ -0,0 +1,157 @@
+package paths
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// dataEntries are the files and directories that make up ccx data. settings.json is
+// included so a migration keeps the default account, profiles.json so it keeps profiles,
+// accounts.enc so encrypted accounts move too, master.key so envelope-encrypted
+// credentials and accounts stay readable, tombstones.json so the record of removed
+// accounts survives, and claude_session.json so ccx still knows which session keys it
+// wrote into Claude config. accounts.db moves with its write-ahead log and shared-memory
+// files, which may hold changes not yet checkpointed into it.
+var dataEntries = []string{
+	"accounts.json", "accounts.enc", "accounts.db", "accounts.db-wal", "accounts.db-shm",
+	"credentials", "history.json", "settings.json", "profiles.json", "master.key", "tombstones.json",
+	"claude_session.json",
+}
+
+// moveEntry moves one data entry; tests replace it to simulate failures
+var moveEntry = move
+
+// MigrateData moves ccx data from one directory to another. Entries missing from
+// from are skipped; an entry that already exists in to aborts the migration before
+// anything is moved. If a move fails, entries already moved are moved back.
+func MigrateData(from, to string) error {
+	from, to = filepath.Clean(from), filepath.Clean(to)
+	if from == to {
+		return nil
+	}
+
+	var pending []string
+	for _, name := range dataEntries {
+		if _, err := os.Lstat(filepath.Join(from, name)); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("failed to check %s: %w", name, err)
+		}
+		if _, err := os.Lstat(filepath.Join(to, name)); err == nil {
+			return fmt.Errorf("%s already exists in %s; refusing to overwrite it", name, to)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to check %s in %s: %w", name, to, err)
+		}
+		pending = append(pending, name)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(to, 0o700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	moved := make([]string, 0, len(pending))
+	for _, name := range pending {
+		if err := moveEntry(filepath.Join(from, name), filepath.Join(to, name)); err != nil {
+			err = fmt.Errorf("failed to move %s: %w", name, err)
+			if rbErr := rollback(from, to, moved); rbErr != nil {
+				return errors.Join(err, rbErr)
+			}
+			return err
+		}
+		moved = append(moved, name)
+	}
+
+	// Leave nothing behind but the lock file; a non-empty directory is kept
+	_ = os.Remove(filepath.Join(from, ".lock"))
+	_ = os.Remove(from)
+
+	return nil
+}
+
+// rollback moves the named entries back from to into from, newest first
+func rollback(from, to string, moved []string) error {
+	var errs []error
+	for i := len(moved) - 1; i >= 0; i-- {
+		if err := moveEntry(filepath.Join(to, moved[i]), filepath.Join(from, moved[i])); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", moved[i], err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("rollback incomplete: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// move renames src to dst, copying and then removing src when they are on
+// different filesystems
+func move(src, dst string) error {
+	err := os.Rename(src, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	if err := copyTree(src, dst); err != nil {
+		_ = os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// copyTree copies a file or directory tree, preserving permissions
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.Mkdir(target, info.Mode().Perm())
+		}
+		if !d.Type().IsRegular() {
+			return fmt.Errorf("cannot copy %s: not a regular file", path)
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+// copyFile copies a single regular file and syncs it to disk
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src) // #nosec G304 - path comes from walking the ccx data directory
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	// #nosec G304 - path is inside the destination data directory
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
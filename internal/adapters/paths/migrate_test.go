@@ -0,0 +1,139 @@
+package paths
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupLegacyData writes a full set of ccx data into a temp directory
+func setupLegacyData(t *testing.T) string {
+	t.Helper()
+	from := filepath.Join(t.TempDir(), ".ccx")
+	writeFile(t, filepath.Join(from, "accounts.json"), `{"accounts":[]}`)
+	writeFile(t, filepath.Join(from, "credentials", "abc12345.json"), `{"accountId":"abc12345"}`)
+	writeFile(t, filepath.Join(from, "history.json"), `{"max_entries":50,"entries":[]}`)
+	writeFile(t, filepath.Join(from, "settings.json"), `{}`)
+	writeFile(t, filepath.Join(from, "tombstones.json"), `[]`)
+	writeFile(t, filepath.Join(from, "claude_session.json"), `[]`)
+	writeFile(t, filepath.Join(from, ".lock"), "")
+	return from
+}
+
+func TestMigrateData(t *testing.T) {
+	from := setupLegacyData(t)
+	to := filepath.Join(t.TempDir(), "share", "ccx")
+
+	if err := MigrateData(from, to); err != nil {
+		t.Fatalf("MigrateData() error = %v", err)
+	}
+
+	for _, name := range []string{"accounts.json", "credentials/abc12345.json", "history.json", "settings.json", "tombstones.json", "claude_session.json"} {
+		if _, err := os.Stat(filepath.Join(to, name)); err != nil {
+			t.Errorf("%s not migrated: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(from); !os.IsNotExist(err) {
+		t.Errorf("Old data directory still exists: %v", err)
+	}
+}
+
+func TestMigrateData_SQLite(t *testing.T) {
+	from := t.TempDir()
+	for _, name := range []string{"accounts.db", "accounts.db-wal", "accounts.db-shm"} {
+		writeFile(t, filepath.Join(from, name), "sqlite")
+	}
+	to := t.TempDir()
+
+	if err := MigrateData(from, to); err != nil {
+		t.Fatalf("MigrateData() error = %v", err)
+	}
+	for _, name := range []string{"accounts.db", "accounts.db-wal", "accounts.db-shm"} {
+		if _, err := os.Stat(filepath.Join(to, name)); err != nil {
+			t.Errorf("%s not migrated: %v", name, err)
+		}
+	}
+}
+
+func TestMigrateData_PartialSource(t *testing.T) {
+	from := t.TempDir()
+	writeFile(t, filepath.Join(from, "accounts.json"), `{"accounts":[]}`)
+	writeFile(t, filepath.Join(from, "notes.txt"), "not ccx data")
+	to := t.TempDir()
+
+	if err := MigrateData(from, to); err != nil {
+		t.Fatalf("MigrateData() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(to, "accounts.json")); err != nil {
+		t.Errorf("accounts.json not migrated: %v", err)
+	}
+	// Unrelated files keep the old directory around
+	if _, err := os.Stat(filepath.Join(from, "notes.txt")); err != nil {
+		t.Errorf("Unrelated file removed: %v", err)
+	}
+}
+
+func TestMigrateData_RefusesOverwrite(t *testing.T) {
+	from := setupLegacyData(t)
+	to := t.TempDir()
+	writeFile(t, filepath.Join(to, "history.json"), `{"max_entries":10,"entries":[]}`)
+
+	if err := MigrateData(from, to); err == nil {
+		t.Fatal("MigrateData() error = nil, want error")
+	}
+	// Nothing moved
+	if _, err := os.Stat(filepath.Join(from, "accounts.json")); err != nil {
+		t.Errorf("accounts.json moved despite the conflict: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(to, "accounts.json")); !os.IsNotExist(err) {
+		t.Errorf("accounts.json exists in destination: %v", err)
+	}
+}
+
+func TestMigrateData_RollbackOnFailure(t *testing.T) {
+	from := setupLegacyData(t)
+	to := t.TempDir()
+
+	moveErr := errors.New("disk full")
+	moveEntry = func(src, dst string) error {
+		if filepath.Base(src) == "history.json" {
+			return moveErr
+		}
+		return move(src, dst)
+	}
+	t.Cleanup(func() { moveEntry = move })
+
+	if err := MigrateData(from, to); !errors.Is(err, moveErr) {
+		t.Fatalf("MigrateData() error = %v, want %v", err, moveErr)
+	}
+
+	for _, name := range []string{"accounts.json", "credentials/abc12345.json", "history.json", "settings.json", "tombstones.json"} {
+		if _, err := os.Stat(filepath.Join(from, name)); err != nil {
+			t.Errorf("%s not restored: %v", name, err)
+		}
+		if _, err := os.Stat(filepath.Join(to, name)); !os.IsNotExist(err) {
+			t.Errorf("%s left in destination: %v", name, err)
+		}
+	}
+}
+
+func TestCopyTree(t *testing.T) {
+	from := setupLegacyData(t)
+	dst := filepath.Join(t.TempDir(), "credentials")
+
+	if err := copyTree(filepath.Join(from, "credentials"), dst); err != nil {
+		t.Fatalf("copyTree() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dst, "abc12345.json")) // #nosec G304 - test file with controlled path
+	if err != nil || string(data) != `{"accountId":"abc12345"}` {
+		t.Errorf("Copied file = %s, %v", data, err)
+	}
+	info, err := os.Stat(filepath.Join(dst, "abc12345.json"))
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Copied file mode = %v, want 0600", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,97 @@
+// Package paths resolves where ccx keeps its data and where Claude keeps its config,
+// and migrates ccx data between locations.
+package paths
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Environment variables that override the default locations
+const (
+	EnvDataDir         = "CCX_DATA_DIR"      // ccx data directory
+	EnvXDGDataHome     = "XDG_DATA_HOME"     // Base for the XDG data directory
+	EnvClaudeConfigDir = "CLAUDE_CONFIG_DIR" // Directory holding .claude.json
+)
+
+// legacyDirName is the data directory ccx used before honoring XDG
+const legacyDirName = ".ccx"
+
+// DataDir returns the ccx data directory: $CCX_DATA_DIR, then $XDG_DATA_HOME/ccx,
+// then ~/.ccx. The directory is not created.
+func DataDir() (string, error) {
+	if dir := os.Getenv(EnvDataDir); dir != "" {
+		return filepath.Clean(dir), nil
+	}
+	if xdg := os.Getenv(EnvXDGDataHome); xdg != "" {
+		// The XDG spec says relative paths are invalid and should be ignored
+		if filepath.IsAbs(xdg) {
+			return filepath.Join(xdg, "ccx"), nil
+		}
+	}
+	return LegacyDataDir()
+}
+
+// LegacyDataDir returns ~/.ccx, where ccx kept its data before honoring XDG
+func LegacyDataDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(home, legacyDirName), nil
+}
+
+// ClaudeConfigDir returns the directory holding Claude's .claude.json:
+// $CLAUDE_CONFIG_DIR, then the home directory
+func ClaudeConfigDir() (string, error) {
+	if dir := os.Getenv(EnvClaudeConfigDir); dir != "" {
+		return filepath.Clean(dir), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return home, nil
+}
+
+// PendingMigration reports whether ccx data should move from ~/.ccx to dataDir:
+// ~/.ccx holds ccx data, dataDir is a different location, and dataDir holds none yet.
+// It returns the directory to migrate from.
+func PendingMigration(dataDir string) (string, bool, error) {
+	legacy, err := LegacyDataDir()
+	if err != nil {
+		return "", false, err
+	}
+	if filepath.Clean(dataDir) == legacy {
+		return "", false, nil
+	}
+
+	hasLegacy, err := hasData(legacy)
+	if err != nil {
+		return "", false, err
+	}
+	if !hasLegacy {
+		return "", false, nil
+	}
+	hasCurrent, err := hasData(dataDir)
+	if err != nil {
+		return "", false, err
+	}
+	return legacy, !hasCurrent, nil
+}
+
+// hasData reports whether dir contains any of the ccx data files
+func hasData(dir string) (bool, error) {
+	for _, name := range dataEntries {
+		_, err := os.Lstat(filepath.Join(dir, name))
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return false, fmt.Errorf("failed to check %s: %w", name, err)
+		}
+	}
+	return false, nil
+}
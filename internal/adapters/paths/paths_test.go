@@ -0,0 +1,108 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setHome points the home directory at a temp dir and clears the overrides
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv(EnvDataDir, "")
+	t.Setenv(EnvXDGDataHome, "")
+	t.Setenv(EnvClaudeConfigDir, "")
+	return home
+}
+
+func TestDataDir(t *testing.T) {
+	home := setHome(t)
+
+	tests := []struct {
+		name    string
+		dataDir string
+		xdgHome string
+		want    string
+	}{
+		{"default", "", "", filepath.Join(home, ".ccx")},
+		{"xdg", "", filepath.Join(home, "share"), filepath.Join(home, "share", "ccx")},
+		{"relative xdg ignored", "", "share", filepath.Join(home, ".ccx")},
+		{"explicit wins", filepath.Join(home, "custom") + "/", filepath.Join(home, "share"), filepath.Join(home, "custom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvDataDir, tt.dataDir)
+			t.Setenv(EnvXDGDataHome, tt.xdgHome)
+
+			got, err := DataDir()
+			if err != nil {
+				t.Fatalf("DataDir() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DataDir() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaudeConfigDir(t *testing.T) {
+	home := setHome(t)
+
+	got, err := ClaudeConfigDir()
+	if err != nil || got != home {
+		t.Errorf("ClaudeConfigDir() = %s, %v; want %s", got, err, home)
+	}
+
+	custom := filepath.Join(home, "claude")
+	t.Setenv(EnvClaudeConfigDir, custom)
+	got, err = ClaudeConfigDir()
+	if err != nil || got != custom {
+		t.Errorf("ClaudeConfigDir() = %s, %v; want %s", got, err, custom)
+	}
+}
+
+func TestPendingMigration(t *testing.T) {
+	home := setHome(t)
+	legacy := filepath.Join(home, ".ccx")
+	xdg := filepath.Join(home, "share", "ccx")
+
+	check := func(dataDir string, wantNeeded bool) {
+		t.Helper()
+		from, needed, err := PendingMigration(dataDir)
+		if err != nil {
+			t.Fatalf("PendingMigration(%s) error = %v", dataDir, err)
+		}
+		if needed != wantNeeded {
+			t.Errorf("PendingMigration(%s) needed = %v, want %v", dataDir, needed, wantNeeded)
+		}
+		if needed && from != legacy {
+			t.Errorf("PendingMigration(%s) from = %s, want %s", dataDir, from, legacy)
+		}
+	}
+
+	// Nothing in ~/.ccx yet
+	check(xdg, false)
+
+	writeFile(t, filepath.Join(legacy, "accounts.json"), "[]")
+	check(xdg, true)
+	// Already using ~/.ccx
+	check(legacy, false)
+
+	// The new location already has data, so migrating would clobber it
+	writeFile(t, filepath.Join(xdg, "history.json"), "{}")
+	check(xdg, false)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,225 @@
+// Package readonly provides decorators that refuse every write to the stores they wrap,
+// so a shared or production machine can list and inspect accounts without risk of
+// changing them. Because the lock sits under the use cases, it holds for all of them.
+package readonly
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// EnvReadOnly is the environment variable choosing the mode: 1 or true locks every
+// store, switch locks all but what switching writes, and 0, false, or unset locks nothing
+const EnvReadOnly = "CCX_READONLY"
+
+// Mode selects which stores are locked
+type Mode int
+
+const (
+	// Off leaves every store writable
+	Off Mode = iota
+	// Locked refuses every write, including the ones switching makes
+	Locked
+	// SwitchOnly refuses writes to accounts, credentials, settings, and profiles but
+	// leaves Claude config and history writable, so switching still works. Recording
+	// the switched-to account's last use fails and is only warned about.
+	SwitchOnly
+)
+
+// ModeFromEnv reads the mode from EnvReadOnly using getenv, such as os.Getenv
+func ModeFromEnv(getenv func(string) string) (Mode, error) {
+	value := strings.ToLower(strings.TrimSpace(getenv(EnvReadOnly)))
+	switch value {
+	case "", "0", "false":
+		return Off, nil
+	case "1", "true":
+		return Locked, nil
+	case "switch":
+		return SwitchOnly, nil
+	default:
+		return Off, fmt.Errorf("invalid %s value %q; use 1, switch, or 0", EnvReadOnly, value)
+	}
+}
+
+// Stores groups the stores a Mode can lock. Nil stores are left nil.
+type Stores struct {
+	Accounts    ports.AccountRepository
+	Credentials ports.CredentialStore
+	Settings    ports.SettingsRepository
+	Profiles    ports.ProfileRepository
+	History     ports.HistoryRepository
+	Config      ports.ConfigManager
+}
+
+// Wrap returns stores with the ones mode locks wrapped in read-only decorators
+func (m Mode) Wrap(stores Stores) Stores {
+	if m == Off {
+		return stores
+	}
+
+	if stores.Accounts != nil {
+		stores.Accounts = NewAccountRepository(stores.Accounts)
+	}
+	if stores.Credentials != nil {
+		stores.Credentials = NewCredentialStore(stores.Credentials)
+	}
+	if stores.Settings != nil {
+		stores.Settings = NewSettingsRepository(stores.Settings)
+	}
+	if stores.Profiles != nil {
+		stores.Profiles = NewProfileRepository(stores.Profiles)
+	}
+	if m == SwitchOnly {
+		return stores
+	}
+	if stores.History != nil {
+		stores.History = NewHistoryRepository(stores.History)
+	}
+	if stores.Config != nil {
+		stores.Config = NewConfigManager(stores.Config)
+	}
+	return stores
+}
+
+// refuse returns domain.ErrStoreReadOnly naming the refused operation
+func refuse(op string) error {
+	return fmt.Errorf("%w: cannot %s", domain.ErrStoreReadOnly, op)
+}
+
+// AccountRepository decorates an AccountRepository, refusing Save and Delete
+type AccountRepository struct {
+	ports.AccountRepository
+}
+
+// NewAccountRepository wraps next so it can only be read. If next can page accounts,
+// so can the returned repository.
+func NewAccountRepository(next ports.AccountRepository) ports.AccountRepository {
+	repo := &AccountRepository{AccountRepository: next}
+	if pager, ok := next.(ports.AccountPager); ok {
+		return &accountPager{AccountRepository: repo, AccountPager: pager}
+	}
+	return repo
+}
+
+// Save returns domain.ErrStoreReadOnly
+func (r *AccountRepository) Save(_ context.Context, _ *domain.Account) error {
+	return refuse("save account")
+}
+
+// Delete returns domain.ErrStoreReadOnly
+func (r *AccountRepository) Delete(_ context.Context, _ domain.AccountID) error {
+	return refuse("delete account")
+}
+
+// accountPager is returned for repositories that implement ports.AccountPager, so
+// locking a repository does not slow down listing
+type accountPager struct {
+	*AccountRepository
+	ports.AccountPager
+}
+
+// CredentialStore decorates a CredentialStore, refusing Store and Delete
+type CredentialStore struct {
+	ports.CredentialStore
+}
+
+// NewCredentialStore wraps next so it can only be read. If next can list its
+// credentials, so can the returned store.
+func NewCredentialStore(next ports.CredentialStore) ports.CredentialStore {
+	store := &CredentialStore{CredentialStore: next}
+	if lister, ok := next.(ports.CredentialLister); ok {
+		return &credentialLister{CredentialStore: store, CredentialLister: lister}
+	}
+	return store
+}
+
+// Store returns domain.ErrStoreReadOnly
+func (s *CredentialStore) Store(_ context.Context, _ *domain.Credentials) error {
+	return refuse("store credentials")
+}
+
+// Delete returns domain.ErrStoreReadOnly
+func (s *CredentialStore) Delete(_ context.Context, _ domain.AccountID) error {
+	return refuse("delete credentials")
+}
+
+// credentialLister is returned for stores that implement ports.CredentialLister, so
+// locking a store does not hide its contents from the Doctor use case
+type credentialLister struct {
+	*CredentialStore
+	ports.CredentialLister
+}
+
+// SettingsRepository decorates a SettingsRepository, refusing SaveSettings
+type SettingsRepository struct {
+	ports.SettingsRepository
+}
+
+// NewSettingsRepository wraps next so it can only be read
+func NewSettingsRepository(next ports.SettingsRepository) ports.SettingsRepository {
+	return &SettingsRepository{SettingsRepository: next}
+}
+
+// SaveSettings returns domain.ErrStoreReadOnly
+func (r *SettingsRepository) SaveSettings(_ context.Context, _ *domain.Settings) error {
+	return refuse("save settings")
+}
+
+// ProfileRepository decorates a ProfileRepository, refusing Save and Delete
+type ProfileRepository struct {
+	ports.ProfileRepository
+}
+
+// NewProfileRepository wraps next so it can only be read
+func NewProfileRepository(next ports.ProfileRepository) ports.ProfileRepository {
+	return &ProfileRepository{ProfileRepository: next}
+}
+
+// Save returns domain.ErrStoreReadOnly
+func (r *ProfileRepository) Save(_ context.Context, _ *domain.Profile) error {
+	return refuse("save profile")
+}
+
+// Delete returns domain.ErrStoreReadOnly
+func (r *ProfileRepository) Delete(_ context.Context, _ string) error {
+	return refuse("delete profile")
+}
+
+// HistoryRepository decorates a HistoryRepository, refusing SaveHistory
+type HistoryRepository struct {
+	ports.HistoryRepository
+}
+
+// NewHistoryRepository wraps next so it can only be read
+func NewHistoryRepository(next ports.HistoryRepository) ports.HistoryRepository {
+	return &HistoryRepository{HistoryRepository: next}
+}
+
+// SaveHistory returns domain.ErrStoreReadOnly
+func (r *HistoryRepository) SaveHistory(_ context.Context, _ *domain.History) error {
+	return refuse("save history")
+}
+
+// ConfigManager decorates a ConfigManager, refusing every change to Claude config
+type ConfigManager struct {
+	ports.ConfigManager
+}
+
+// NewConfigManager wraps next so Claude config can only be read
+func NewConfigManager(next ports.ConfigManager) ports.ConfigManager {
+	return &ConfigManager{ConfigManager: next}
+}
+
+// SetCurrentAccount returns domain.ErrStoreReadOnly
+func (m *ConfigManager) SetCurrentAccount(_ context.Context, _ *domain.Account) error {
+	return refuse("change the current Claude account")
+}
+
+// SetCredentials returns domain.ErrStoreReadOnly
+func (m *ConfigManager) SetCredentials(_ context.Context, _ *domain.Credentials) error {
+	return refuse("write Claude credentials")
+}
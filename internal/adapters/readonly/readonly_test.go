@@ -0,0 +1,132 @@
+package readonly
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/adapters/memory"
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// newStores returns memory stores holding work and personal accounts with credentials,
+// with personal current
+func newStores(t *testing.T) (Stores, *domain.Account) {
+	t.Helper()
+	ctx := context.Background()
+	stores := Stores{
+		Accounts:    memory.NewAccountRepository(),
+		Credentials: memory.NewCredentialStore(),
+		Settings:    memory.NewSettingsRepository(),
+		Profiles:    memory.NewProfileRepository(),
+		History:     memory.NewHistoryRepository(),
+		Config:      memory.NewConfigManager(),
+	}
+
+	var work *domain.Account
+	for _, email := range []string{"personal@example.com", "work@example.com"} {
+		account, _ := domain.NewAccount(email, "", "uuid-"+email)
+		creds, _ := domain.NewCredentials(account.ID(), []byte(`{"sessionKey":"key"}`))
+		if err := stores.Accounts.Save(ctx, account); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if err := stores.Credentials.Store(ctx, creds); err != nil {
+			t.Fatalf("Store() error = %v", err)
+		}
+		if work == nil {
+			_ = stores.Config.SetCurrentAccount(ctx, account)
+		}
+		work = account
+	}
+	return stores, work
+}
+
+func TestModeFromEnv(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Mode
+		wantErr bool
+	}{
+		{value: "", want: Off},
+		{value: "0", want: Off},
+		{value: "1", want: Locked},
+		{value: " TRUE ", want: Locked},
+		{value: "switch", want: SwitchOnly},
+		{value: "maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ModeFromEnv(func(string) string { return tt.value })
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ModeFromEnv(%q) = %v, %v; want %v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// TestLocked_RefusesMutations tests that adding and removing fail without changing
+// anything, while listing still works
+func TestLocked_RefusesMutations(t *testing.T) {
+	ctx := context.Background()
+	stores, work := newStores(t)
+	locked := Locked.Wrap(stores)
+
+	add := usecases.NewAddAccountService(locked.Accounts, locked.Credentials, locked.Config)
+	err := add.Execute(ctx, usecases.AddAccountInput{Email: "new@example.com", Credentials: []byte(`{"sessionKey":"key"}`)})
+	if !errors.Is(err, domain.ErrStoreReadOnly) {
+		t.Errorf("AddAccount error = %v, want ErrStoreReadOnly", err)
+	}
+
+	remove := usecases.NewRemoveAccountService(locked.Accounts, locked.Credentials, locked.Config, locked.History)
+	_, err = remove.Execute(ctx, usecases.RemoveAccountInput{AccountID: string(work.ID())})
+	if !errors.Is(err, domain.ErrStoreReadOnly) {
+		t.Errorf("RemoveAccount error = %v, want ErrStoreReadOnly", err)
+	}
+
+	switcher := usecases.NewSwitchAccountService(locked.Accounts, locked.Credentials, locked.Config, locked.History)
+	_, err = switcher.Execute(ctx, usecases.SwitchAccountInput{AccountID: string(work.ID())})
+	if !errors.Is(err, domain.ErrStoreReadOnly) {
+		t.Errorf("SwitchAccount error = %v, want ErrStoreReadOnly", err)
+	}
+
+	accounts, err := locked.Accounts.List(ctx)
+	if err != nil || len(accounts) != 2 {
+		t.Errorf("List() = %d accounts, %v; want the 2 original accounts", len(accounts), err)
+	}
+	if _, err := stores.Credentials.Retrieve(ctx, work.ID()); err != nil {
+		t.Errorf("credentials changed behind the lock: %v", err)
+	}
+}
+
+// TestSwitchOnly_AllowsSwitching tests that switching works while account changes are refused
+func TestSwitchOnly_AllowsSwitching(t *testing.T) {
+	ctx := context.Background()
+	stores, work := newStores(t)
+	locked := SwitchOnly.Wrap(stores)
+
+	switcher := usecases.NewSwitchAccountService(locked.Accounts, locked.Credentials, locked.Config, locked.History)
+	if _, err := switcher.Execute(ctx, usecases.SwitchAccountInput{AccountID: string(work.ID())}); err != nil {
+		t.Fatalf("SwitchAccount error = %v, want nil", err)
+	}
+	if current, _ := stores.Config.GetCurrentAccount(ctx); current == nil || current.ID() != work.ID() {
+		t.Errorf("current account = %v, want %s", current, work.Email())
+	}
+
+	remove := usecases.NewRemoveAccountService(locked.Accounts, locked.Credentials, locked.Config, locked.History)
+	_, err := remove.Execute(ctx, usecases.RemoveAccountInput{AccountID: string(work.ID()), ForceRemoveCurrent: true})
+	if !errors.Is(err, domain.ErrStoreReadOnly) {
+		t.Errorf("RemoveAccount error = %v, want ErrStoreReadOnly", err)
+	}
+}
+
+// TestWrap_KeepsOptionalInterfaces tests that wrapped stores still list their contents
+func TestWrap_KeepsOptionalInterfaces(t *testing.T) {
+	stores, _ := newStores(t)
+	if _, ok := NewCredentialStore(stores.Credentials).(ports.CredentialLister); !ok {
+		t.Error("wrapped credential store lost ListAccountIDs")
+	}
+	if Off.Wrap(stores) != stores {
+		t.Error("Off.Wrap() changed the stores")
+	}
+}
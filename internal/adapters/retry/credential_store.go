@@ -0,0 +1,245 @@
+// Package retry provides decorators that retry transient failures of other adapters.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// Default retry policy: up to three attempts, waiting 50ms and then 100ms between them
+const (
+	DefaultMaxAttempts = 3
+	DefaultBaseDelay   = 50 * time.Millisecond
+	DefaultMaxDelay    = time.Second
+)
+
+// ErrTransient marks an error as worth retrying. Stores can wrap it into errors that
+// ShouldRetry would not otherwise recognize.
+var ErrTransient = errors.New("transient failure")
+
+// transientMessages are fragments of error messages that backends such as the macOS
+// Keychain report for conditions that clear up on their own
+var transientMessages = []string{"resource busy", "temporarily unavailable", "try again"}
+
+// ShouldRetry is the default predicate deciding whether a failed call is retried.
+// Missing credentials, context cancellation, and credential data errors are final;
+// ErrTransient, EBUSY, EAGAIN, EINTR, timeouts, and busy or unavailable messages are
+// transient. Anything else is treated as final.
+func ShouldRetry(err error) bool {
+	switch {
+	case err == nil,
+		errors.Is(err, domain.ErrCredentialsNotFound),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, domain.ErrInvalidCredentialData),
+		errors.Is(err, domain.ErrMasterKeyRequired),
+		errors.Is(err, domain.ErrPassphraseRequired):
+		return false
+	case errors.Is(err, ErrTransient),
+		errors.Is(err, syscall.EBUSY),
+		errors.Is(err, syscall.EAGAIN),
+		errors.Is(err, syscall.EINTR):
+		return true
+	}
+
+	var timeout interface{ Timeout() bool }
+	if errors.As(err, &timeout) && timeout.Timeout() {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, fragment := range transientMessages {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryingCredentialStore decorates a CredentialStore, retrying calls that fail with a
+// transient error using exponential backoff. It changes nothing else about the store,
+// so it composes with any implementation without the use cases knowing.
+type RetryingCredentialStore struct {
+	next        ports.CredentialStore
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	shouldRetry func(error) bool
+}
+
+// Option configures optional RetryingCredentialStore behavior
+type Option func(*RetryingCredentialStore)
+
+// WithMaxAttempts sets how many times a call is made in total, including the first.
+// Values below one are treated as one, which disables retries.
+func WithMaxAttempts(attempts int) Option {
+	return func(s *RetryingCredentialStore) {
+		s.maxAttempts = max(attempts, 1)
+	}
+}
+
+// WithBackoff sets the delay before the first retry, doubling for each later retry up to maxDelay
+func WithBackoff(baseDelay, maxDelay time.Duration) Option {
+	return func(s *RetryingCredentialStore) {
+		s.baseDelay = baseDelay
+		s.maxDelay = maxDelay
+	}
+}
+
+// WithShouldRetry replaces ShouldRetry as the predicate deciding which errors are
+// transient. A nil predicate keeps ShouldRetry.
+func WithShouldRetry(shouldRetry func(err error) bool) Option {
+	return func(s *RetryingCredentialStore) {
+		if shouldRetry != nil {
+			s.shouldRetry = shouldRetry
+		}
+	}
+}
+
+// NewRetryingCredentialStore wraps next with retries. If next can list its credentials
+// or verify their permissions, so can the returned store.
+func NewRetryingCredentialStore(next ports.CredentialStore, opts ...Option) ports.CredentialStore {
+	s := &RetryingCredentialStore{
+		next:        next,
+		maxAttempts: DefaultMaxAttempts,
+		baseDelay:   DefaultBaseDelay,
+		maxDelay:    DefaultMaxDelay,
+		shouldRetry: ShouldRetry,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	lister, canList := next.(ports.CredentialLister)
+	verifier, canVerify := next.(ports.PermissionVerifier)
+	switch {
+	case canList && canVerify:
+		return &retryingListerVerifier{
+			retryingCredentialLister: &retryingCredentialLister{RetryingCredentialStore: s, lister: lister},
+			verifier:                 verifier,
+		}
+	case canList:
+		return &retryingCredentialLister{RetryingCredentialStore: s, lister: lister}
+	case canVerify:
+		return &retryingCredentialVerifier{RetryingCredentialStore: s, verifier: verifier}
+	}
+	return s
+}
+
+// Store saves credentials, retrying transient failures
+func (s *RetryingCredentialStore) Store(ctx context.Context, creds *domain.Credentials) error {
+	return s.do(ctx, func() error { return s.next.Store(ctx, creds) })
+}
+
+// Retrieve gets credentials, retrying transient failures. domain.ErrCredentialsNotFound
+// is returned at once.
+func (s *RetryingCredentialStore) Retrieve(ctx context.Context, accountID domain.AccountID) (*domain.Credentials, error) {
+	var creds *domain.Credentials
+	err := s.do(ctx, func() error {
+		var err error
+		creds, err = s.next.Retrieve(ctx, accountID)
+		return err
+	})
+	return creds, err
+}
+
+// Delete removes credentials, retrying transient failures
+func (s *RetryingCredentialStore) Delete(ctx context.Context, accountID domain.AccountID) error {
+	return s.do(ctx, func() error { return s.next.Delete(ctx, accountID) })
+}
+
+// do calls op until it succeeds, fails with an error that is not transient, or runs out
+// of attempts. Waiting between attempts stops early if ctx is done.
+func (s *RetryingCredentialStore) do(ctx context.Context, op func() error) error {
+	delay := s.baseDelay
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= s.maxAttempts || !s.shouldRetry(err) {
+			if err != nil && attempt > 1 {
+				return fmt.Errorf("after %d attempts: %w", attempt, err)
+			}
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("context cancelled while retrying: %w (last error: %v)", ctx.Err(), err)
+		case <-timer.C:
+		}
+		delay = min(delay*2, s.maxDelay)
+	}
+}
+
+// retryingCredentialLister is returned for stores that implement ports.CredentialLister,
+// so wrapping a store does not hide its contents from the Doctor use case
+type retryingCredentialLister struct {
+	*RetryingCredentialStore
+	lister ports.CredentialLister
+}
+
+// Ensure retryingCredentialLister can enumerate its credentials at compile time
+var _ ports.CredentialLister = (*retryingCredentialLister)(nil)
+
+// ListAccountIDs lists the wrapped store's credentials, retrying transient failures
+func (s *retryingCredentialLister) ListAccountIDs(ctx context.Context) ([]domain.AccountID, error) {
+	var ids []domain.AccountID
+	err := s.do(ctx, func() error {
+		var err error
+		ids, err = s.lister.ListAccountIDs(ctx)
+		return err
+	})
+	return ids, err
+}
+
+// retryingCredentialVerifier is returned for stores that implement
+// ports.PermissionVerifier, so wrapping a store does not hide its files from the Doctor
+// use case
+type retryingCredentialVerifier struct {
+	*RetryingCredentialStore
+	verifier ports.PermissionVerifier
+}
+
+// Ensure retryingCredentialVerifier can verify its permissions at compile time
+var _ ports.PermissionVerifier = (*retryingCredentialVerifier)(nil)
+
+// VerifyPermissions checks the wrapped store's permissions, retrying transient failures
+func (s *retryingCredentialVerifier) VerifyPermissions(ctx context.Context) ([]ports.PermissionIssue, error) {
+	return verifyPermissions(ctx, s.RetryingCredentialStore, s.verifier)
+}
+
+// retryingListerVerifier is returned for stores that implement both optional
+// interfaces, such as the file store
+type retryingListerVerifier struct {
+	*retryingCredentialLister
+	verifier ports.PermissionVerifier
+}
+
+// Ensure retryingListerVerifier keeps both optional interfaces at compile time
+var (
+	_ ports.CredentialLister   = (*retryingListerVerifier)(nil)
+	_ ports.PermissionVerifier = (*retryingListerVerifier)(nil)
+)
+
+// VerifyPermissions checks the wrapped store's permissions, retrying transient failures
+func (s *retryingListerVerifier) VerifyPermissions(ctx context.Context) ([]ports.PermissionIssue, error) {
+	return verifyPermissions(ctx, s.RetryingCredentialStore, s.verifier)
+}
+
+func verifyPermissions(ctx context.Context, s *RetryingCredentialStore, verifier ports.PermissionVerifier) ([]ports.PermissionIssue, error) {
+	var issues []ports.PermissionIssue
+	err := s.do(ctx, func() error {
+		var err error
+		issues, err = verifier.VerifyPermissions(ctx)
+		return err
+	})
+	return issues, err
+}
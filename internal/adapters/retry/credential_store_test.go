@@ -0,0 +1,229 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/adapters/memory"
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// flakyStore fails the first failures calls with err, then delegates to a memory store
+type flakyStore struct {
+	*memory.CredentialStore
+	err      error
+	failures int
+	calls    int
+}
+
+func (s *flakyStore) fail() error {
+	s.calls++
+	if s.calls <= s.failures {
+		return s.err
+	}
+	return nil
+}
+
+func (s *flakyStore) Store(ctx context.Context, creds *domain.Credentials) error {
+	if err := s.fail(); err != nil {
+		return err
+	}
+	return s.CredentialStore.Store(ctx, creds)
+}
+
+func (s *flakyStore) Retrieve(ctx context.Context, id domain.AccountID) (*domain.Credentials, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return s.CredentialStore.Retrieve(ctx, id)
+}
+
+func (s *flakyStore) Delete(ctx context.Context, id domain.AccountID) error {
+	if err := s.fail(); err != nil {
+		return err
+	}
+	return s.CredentialStore.Delete(ctx, id)
+}
+
+// fastBackoff keeps tests quick
+var fastBackoff = WithBackoff(time.Millisecond, 2*time.Millisecond)
+
+func TestShouldRetry(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"not found", fmt.Errorf("lookup: %w", domain.ErrCredentialsNotFound), false},
+		{"cancelled", context.Canceled, false},
+		{"invalid data", domain.ErrInvalidCredentialData, false},
+		{"marked transient", fmt.Errorf("keychain: %w", ErrTransient), true},
+		{"EBUSY", &fs.PathError{Op: "open", Path: "creds", Err: syscall.EBUSY}, true},
+		{"EAGAIN", syscall.EAGAIN, true},
+		{"timeout", os.ErrDeadlineExceeded, true},
+		{"keychain busy message", errors.New("The specified resource busy (-25240)"), true},
+		{"permission denied", fs.ErrPermission, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShouldRetry(tt.err); got != tt.want {
+				t.Errorf("ShouldRetry(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryingCredentialStore_RetriesTransientErrors(t *testing.T) {
+	ctx := context.Background()
+	inner := &flakyStore{CredentialStore: memory.NewCredentialStore(), err: syscall.EBUSY, failures: 2}
+	store := NewRetryingCredentialStore(inner, fastBackoff)
+
+	creds, _ := domain.NewCredentials("abc12345", []byte(`{"sessionKey": "key"}`))
+	if err := store.Store(ctx, creds); err != nil {
+		t.Fatalf("Store() error = %v, want nil after retries", err)
+	}
+	if inner.calls != 3 {
+		t.Errorf("Store() made %d calls, want 3", inner.calls)
+	}
+
+	// Out of attempts: the last error is returned
+	inner.calls, inner.failures = 0, 5
+	if _, err := store.Retrieve(ctx, "abc12345"); !errors.Is(err, syscall.EBUSY) {
+		t.Errorf("Retrieve() error = %v, want EBUSY", err)
+	}
+	if inner.calls != DefaultMaxAttempts {
+		t.Errorf("Retrieve() made %d calls, want %d", inner.calls, DefaultMaxAttempts)
+	}
+}
+
+func TestRetryingCredentialStore_FinalErrors(t *testing.T) {
+	ctx := context.Background()
+	inner := &flakyStore{CredentialStore: memory.NewCredentialStore()}
+	store := NewRetryingCredentialStore(inner, fastBackoff)
+
+	// Missing credentials are not retried
+	if _, err := store.Retrieve(ctx, "missing1"); !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Retrieve() error = %v, want ErrCredentialsNotFound", err)
+	}
+	if inner.calls != 1 {
+		t.Errorf("Retrieve() made %d calls, want 1", inner.calls)
+	}
+
+	// A custom predicate decides what is transient
+	inner.calls, inner.failures, inner.err = 0, 1, errors.New("flaky backend")
+	store = NewRetryingCredentialStore(inner, fastBackoff, WithShouldRetry(func(err error) bool {
+		return err.Error() == "flaky backend"
+	}))
+	if err := store.Delete(ctx, "missing1"); !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Delete() error = %v, want ErrCredentialsNotFound after retry", err)
+	}
+	if inner.calls != 2 {
+		t.Errorf("Delete() made %d calls, want 2", inner.calls)
+	}
+}
+
+func TestRetryingCredentialStore_ContextCancelledBetweenRetries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	inner := &flakyStore{CredentialStore: memory.NewCredentialStore(), err: ErrTransient, failures: 10}
+	store := NewRetryingCredentialStore(inner, WithMaxAttempts(10), WithBackoff(time.Hour, time.Hour))
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := store.Retrieve(ctx, "abc12345")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Retrieve() error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Retrieve() waited %v after cancellation", elapsed)
+	}
+	if inner.calls != 1 {
+		t.Errorf("Retrieve() made %d calls, want 1", inner.calls)
+	}
+}
+
+func TestRetryingCredentialStore_KeepsLister(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.NewCredentialStore()
+	creds, _ := domain.NewCredentials("abc12345", []byte(`{"sessionKey": "key"}`))
+	_ = inner.Store(ctx, creds)
+
+	lister, ok := NewRetryingCredentialStore(inner).(ports.CredentialLister)
+	if !ok {
+		t.Fatal("Wrapping a lister should keep ListAccountIDs")
+	}
+	ids, err := lister.ListAccountIDs(ctx)
+	if err != nil || len(ids) != 1 || ids[0] != "abc12345" {
+		t.Errorf("ListAccountIDs() = %v, %v; want [abc12345]", ids, err)
+	}
+
+	// Embedding only the interface hides the memory store's ListAccountIDs
+	var plain ports.CredentialStore = struct{ ports.CredentialStore }{inner}
+	if _, ok := NewRetryingCredentialStore(plain).(ports.CredentialLister); ok {
+		t.Error("Wrapping a store that cannot list should not add ListAccountIDs")
+	}
+}
+
+// verifyingStore adds ports.PermissionVerifier to a memory store
+type verifyingStore struct {
+	*memory.CredentialStore
+	issues []ports.PermissionIssue
+}
+
+func (s *verifyingStore) VerifyPermissions(_ context.Context) ([]ports.PermissionIssue, error) {
+	return s.issues, nil
+}
+
+func TestRetryingCredentialStore_KeepsPermissionVerifier(t *testing.T) {
+	ctx := context.Background()
+	inner := &verifyingStore{
+		CredentialStore: memory.NewCredentialStore(),
+		issues:          []ports.PermissionIssue{{Path: "credentials", Mode: 0o755, Want: 0o700}},
+	}
+
+	store := NewRetryingCredentialStore(inner)
+	verifier, ok := store.(ports.PermissionVerifier)
+	if !ok {
+		t.Fatal("Wrapping a verifier should keep VerifyPermissions")
+	}
+	if _, ok := store.(ports.CredentialLister); !ok {
+		t.Error("Wrapping a verifier that can list should keep ListAccountIDs too")
+	}
+	issues, err := verifier.VerifyPermissions(ctx)
+	if err != nil || len(issues) != 1 || issues[0].Path != "credentials" {
+		t.Errorf("VerifyPermissions() = %v, %v; want the wrapped store's issue", issues, err)
+	}
+
+	// A verifier that cannot list keeps only VerifyPermissions
+	onlyVerifier := struct {
+		ports.CredentialStore
+		ports.PermissionVerifier
+	}{inner, inner}
+	store = NewRetryingCredentialStore(onlyVerifier)
+	if _, ok := store.(ports.PermissionVerifier); !ok {
+		t.Error("Wrapping a verifier should keep VerifyPermissions")
+	}
+	if _, ok := store.(ports.CredentialLister); ok {
+		t.Error("Wrapping a store that cannot list should not add ListAccountIDs")
+	}
+}
+
+func TestWithShouldRetry_Nil(t *testing.T) {
+	inner := &flakyStore{CredentialStore: memory.NewCredentialStore(), err: ErrTransient, failures: 1}
+	store := NewRetryingCredentialStore(inner, fastBackoff, WithShouldRetry(nil))
+
+	if _, err := store.Retrieve(context.Background(), "missing1"); !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Retrieve() error = %v, want ErrCredentialsNotFound after the default retry", err)
+	}
+	if inner.calls != 2 {
+		t.Errorf("Retrieve() made %d calls, want 2", inner.calls)
+	}
+}
@@ -0,0 +1,126 @@
+// Package secretservice provides a CredentialStore backed by the freedesktop Secret
+// Service (GNOME Keyring, KWallet) over D-Bus. The D-Bus client only builds on Linux;
+// the store logic on top of it is platform independent so it can be tested everywhere.
+package secretservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// Attributes ccx items are stored and looked up by
+const (
+	attrApplication = "application"
+	attrAccountID   = "accountID"
+	application     = "ccx"
+)
+
+// ErrUnavailable is returned when no Secret Service can be reached, as on headless
+// servers without a session bus or keyring daemon. Callers can detect it with errors.Is
+// and fall back to the file credential store.
+var ErrUnavailable = errors.New("secret service is not available")
+
+// errItemNotFound is returned by a collection when no item has the attributes
+var errItemNotFound = errors.New("secret item not found")
+
+// collection stores secrets identified by their attributes, as a Secret Service
+// collection does
+type collection interface {
+	get(ctx context.Context, attrs map[string]string) ([]byte, error)
+	set(ctx context.Context, label string, attrs map[string]string, secret []byte) error
+	remove(ctx context.Context, attrs map[string]string) error
+	search(ctx context.Context, attrs map[string]string) ([]map[string]string, error)
+}
+
+// SecretServiceCredentialStore implements CredentialStore using the Secret Service.
+// Items live in the default collection with the attributes
+// {application: ccx, accountID: <accountID>} and hold the serialized domain credentials.
+type SecretServiceCredentialStore struct { //nolint:revive // keeps the backend in the name like FileCredentialStore
+	collection collection
+}
+
+// Ensure SecretServiceCredentialStore can enumerate its credentials at compile time
+var _ ports.CredentialLister = (*SecretServiceCredentialStore)(nil)
+
+// newSecretServiceCredentialStore creates a credential store on top of collection
+func newSecretServiceCredentialStore(collection collection) *SecretServiceCredentialStore {
+	return &SecretServiceCredentialStore{
+		collection: collection,
+	}
+}
+
+// Store saves credentials to the Secret Service, replacing any existing item for the account
+func (s *SecretServiceCredentialStore) Store(ctx context.Context, creds *domain.Credentials) error {
+	// Serialize credentials using domain's built-in encryption
+	data, err := creds.Serialize()
+	if err != nil {
+		return fmt.Errorf("failed to serialize credentials: %w", err)
+	}
+
+	label := fmt.Sprintf("ccx credentials (%s)", creds.AccountID())
+	if err := s.collection.set(ctx, label, itemAttributes(creds.AccountID()), data); err != nil {
+		return fmt.Errorf("failed to write secret item: %w", err)
+	}
+
+	return nil
+}
+
+// Retrieve gets credentials for an account from the Secret Service
+func (s *SecretServiceCredentialStore) Retrieve(ctx context.Context, accountID domain.AccountID) (*domain.Credentials, error) {
+	data, err := s.collection.get(ctx, itemAttributes(accountID))
+	if errors.Is(err, errItemNotFound) {
+		return nil, domain.ErrCredentialsNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret item: %w", err)
+	}
+
+	// Deserialize credentials using domain's built-in decryption
+	creds, err := domain.DeserializeCredentials(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize credentials: %w", err)
+	}
+
+	return creds, nil
+}
+
+// Delete removes credentials for an account from the Secret Service
+func (s *SecretServiceCredentialStore) Delete(ctx context.Context, accountID domain.AccountID) error {
+	if err := s.collection.remove(ctx, itemAttributes(accountID)); errors.Is(err, errItemNotFound) {
+		return domain.ErrCredentialsNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to delete secret item: %w", err)
+	}
+
+	return nil
+}
+
+// ListAccountIDs returns the IDs of all accounts with a ccx secret item, sorted
+func (s *SecretServiceCredentialStore) ListAccountIDs(ctx context.Context) ([]domain.AccountID, error) {
+	items, err := s.collection.search(ctx, map[string]string{attrApplication: application})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secret items: %w", err)
+	}
+
+	ids := make([]domain.AccountID, 0, len(items))
+	for _, attrs := range items {
+		if id := attrs[attrAccountID]; id != "" {
+			ids = append(ids, domain.AccountID(id))
+		}
+	}
+	slices.Sort(ids)
+
+	return slices.Compact(ids), nil
+}
+
+func itemAttributes(accountID domain.AccountID) map[string]string {
+	return map[string]string{
+		attrApplication: application,
+		attrAccountID:   string(accountID),
+	}
+}
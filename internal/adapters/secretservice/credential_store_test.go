@@ -0,0 +1,180 @@
+package secretservice
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+// memoryItem is a secret held by memoryCollection
+type memoryItem struct {
+	attrs  map[string]string
+	secret []byte
+}
+
+// memoryCollection is an in-memory collection matching items by attribute subset, as
+// SearchItems does
+type memoryCollection struct {
+	items []memoryItem
+	err   error
+}
+
+func (c *memoryCollection) find(attrs map[string]string) []int {
+	var found []int
+	for i, item := range c.items {
+		matches := true
+		for k, v := range attrs {
+			if item.attrs[k] != v {
+				matches = false
+			}
+		}
+		if matches {
+			found = append(found, i)
+		}
+	}
+	return found
+}
+
+func (c *memoryCollection) get(_ context.Context, attrs map[string]string) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	found := c.find(attrs)
+	if len(found) == 0 {
+		return nil, errItemNotFound
+	}
+	return slices.Clone(c.items[found[0]].secret), nil
+}
+
+func (c *memoryCollection) set(_ context.Context, _ string, attrs map[string]string, secret []byte) error {
+	if c.err != nil {
+		return c.err
+	}
+	item := memoryItem{attrs: maps.Clone(attrs), secret: slices.Clone(secret)}
+	if found := c.find(attrs); len(found) > 0 {
+		c.items[found[0]] = item
+		return nil
+	}
+	c.items = append(c.items, item)
+	return nil
+}
+
+func (c *memoryCollection) remove(_ context.Context, attrs map[string]string) error {
+	if c.err != nil {
+		return c.err
+	}
+	found := c.find(attrs)
+	if len(found) == 0 {
+		return errItemNotFound
+	}
+	for _, i := range slices.Backward(found) {
+		c.items = slices.Delete(c.items, i, i+1)
+	}
+	return nil
+}
+
+func (c *memoryCollection) search(_ context.Context, attrs map[string]string) ([]map[string]string, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	var result []map[string]string
+	for _, i := range c.find(attrs) {
+		result = append(result, maps.Clone(c.items[i].attrs))
+	}
+	return result, nil
+}
+
+func TestSecretServiceCredentialStore_StoreAndRetrieve(t *testing.T) {
+	collection := &memoryCollection{}
+	store := newSecretServiceCredentialStore(collection)
+	ctx := context.Background()
+
+	testData := []byte(`{"sessionKey":"secret-key"}`)
+	creds, _ := domain.NewCredentials("abc12345", testData)
+	if err := store.Store(ctx, creds); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	// Verify the item carries the lookup attributes
+	want := map[string]string{"application": "ccx", "accountID": "abc12345"}
+	if len(collection.items) != 1 || !maps.Equal(collection.items[0].attrs, want) {
+		t.Fatalf("Stored items = %+v, want one with attributes %v", collection.items, want)
+	}
+
+	// Storing again replaces the item
+	if err := store.Store(ctx, creds); err != nil {
+		t.Fatalf("Store() again error = %v", err)
+	}
+	if len(collection.items) != 1 {
+		t.Errorf("Store() again left %d items, want 1", len(collection.items))
+	}
+
+	retrieved, err := store.Retrieve(ctx, "abc12345")
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if decrypted, _ := retrieved.Decrypt(); string(decrypted) != string(testData) {
+		t.Errorf("Retrieve() returned different credentials")
+	}
+}
+
+func TestSecretServiceCredentialStore_NotFound(t *testing.T) {
+	store := newSecretServiceCredentialStore(&memoryCollection{})
+	ctx := context.Background()
+
+	if _, err := store.Retrieve(ctx, "missing1"); !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Retrieve() error = %v, want ErrCredentialsNotFound", err)
+	}
+	if err := store.Delete(ctx, "missing1"); !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Delete() error = %v, want ErrCredentialsNotFound", err)
+	}
+}
+
+func TestSecretServiceCredentialStore_DeleteAndList(t *testing.T) {
+	collection := &memoryCollection{}
+	store := newSecretServiceCredentialStore(collection)
+	ctx := context.Background()
+
+	for _, id := range []domain.AccountID{"def67890", "abc12345", "aaa00000"} {
+		creds, _ := domain.NewCredentials(id, []byte(`{"sessionKey":"k"}`))
+		_ = store.Store(ctx, creds)
+	}
+	// Items of other applications are never listed
+	collection.items = append(collection.items, memoryItem{attrs: map[string]string{"application": "other", "accountID": "zzz99999"}})
+
+	if err := store.Delete(ctx, "aaa00000"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	ids, err := store.ListAccountIDs(ctx)
+	if err != nil {
+		t.Fatalf("ListAccountIDs() error = %v", err)
+	}
+	if want := []domain.AccountID{"abc12345", "def67890"}; !slices.Equal(ids, want) {
+		t.Errorf("ListAccountIDs() = %v, want %v", ids, want)
+	}
+}
+
+func TestSecretServiceCredentialStore_BackendErrors(t *testing.T) {
+	backendErr := errors.New("org.freedesktop.DBus.Error.NoReply")
+	store := newSecretServiceCredentialStore(&memoryCollection{err: backendErr})
+	ctx := context.Background()
+	creds, _ := domain.NewCredentials("abc12345", []byte(`{"sessionKey":"k"}`))
+
+	if err := store.Store(ctx, creds); !errors.Is(err, backendErr) {
+		t.Errorf("Store() error = %v, want backend error", err)
+	}
+	if _, err := store.Retrieve(ctx, "abc12345"); !errors.Is(err, backendErr) || errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Retrieve() error = %v, want backend error", err)
+	}
+	if err := store.Delete(ctx, "abc12345"); !errors.Is(err, backendErr) {
+		t.Errorf("Delete() error = %v, want backend error", err)
+	}
+	if _, err := store.ListAccountIDs(ctx); !errors.Is(err, backendErr) {
+		t.Errorf("ListAccountIDs() error = %v, want backend error", err)
+	}
+}
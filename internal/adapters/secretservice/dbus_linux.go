@@ -0,0 +1,221 @@
+//go:build linux
+
+package secretservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// Secret Service D-Bus names, from the freedesktop Secret Service API
+const (
+	serviceName                       = "org.freedesktop.secrets"
+	servicePath       dbus.ObjectPath = "/org/freedesktop/secrets"
+	defaultCollection dbus.ObjectPath = "/org/freedesktop/secrets/aliases/default"
+	serviceIface                      = "org.freedesktop.Secret.Service"
+	collectionIface                   = "org.freedesktop.Secret.Collection"
+	itemIface                         = "org.freedesktop.Secret.Item"
+	promptIface                       = "org.freedesktop.Secret.Prompt"
+	noPrompt          dbus.ObjectPath = "/"
+)
+
+// errPromptDismissed is returned when the user dismisses an unlock or confirmation prompt
+var errPromptDismissed = errors.New("secret service prompt was dismissed")
+
+// secret is the Secret struct of the Secret Service API
+type secret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// NewSecretServiceCredentialStore connects to the Secret Service on the session bus and
+// returns a credential store using its default collection. It returns an error wrapping
+// ErrUnavailable if there is no session bus or nothing provides the Secret Service.
+func NewSecretServiceCredentialStore() (ports.CredentialStore, error) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	// Secrets are sent unencrypted over the session bus, which only this user can
+	// reach; they are serialized credentials, already encrypted by the domain
+	var output dbus.Variant
+	var session dbus.ObjectPath
+	err = conn.Object(serviceName, servicePath).
+		Call(serviceIface+".OpenSession", 0, "plain", dbus.MakeVariant("")).
+		Store(&output, &session)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	return newSecretServiceCredentialStore(&dbusCollection{conn: conn, session: session}), nil
+}
+
+// dbusCollection implements collection with the default Secret Service collection
+type dbusCollection struct {
+	conn    *dbus.Conn
+	session dbus.ObjectPath
+}
+
+func (c *dbusCollection) get(ctx context.Context, attrs map[string]string) ([]byte, error) {
+	items, err := c.searchItems(ctx, attrs)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errItemNotFound
+	}
+
+	var secrets map[dbus.ObjectPath]secret
+	err = c.service().CallWithContext(ctx, serviceIface+".GetSecrets", 0, items[:1], c.session).Store(&secrets)
+	if err != nil {
+		return nil, err
+	}
+	found, ok := secrets[items[0]]
+	if !ok {
+		return nil, errItemNotFound
+	}
+	return found.Value, nil
+}
+
+func (c *dbusCollection) set(ctx context.Context, label string, attrs map[string]string, value []byte) error {
+	if err := c.unlock(ctx, []dbus.ObjectPath{defaultCollection}); err != nil {
+		return err
+	}
+
+	properties := map[string]dbus.Variant{
+		itemIface + ".Label":      dbus.MakeVariant(label),
+		itemIface + ".Attributes": dbus.MakeVariant(attrs),
+	}
+	item := secret{Session: c.session, Parameters: []byte{}, Value: value, ContentType: "application/json"}
+
+	var created, prompt dbus.ObjectPath
+	err := c.conn.Object(serviceName, defaultCollection).
+		CallWithContext(ctx, collectionIface+".CreateItem", 0, properties, item, true).
+		Store(&created, &prompt)
+	if err != nil {
+		return err
+	}
+	return c.prompt(ctx, prompt)
+}
+
+func (c *dbusCollection) remove(ctx context.Context, attrs map[string]string) error {
+	items, err := c.searchItems(ctx, attrs)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return errItemNotFound
+	}
+
+	for _, item := range items {
+		var prompt dbus.ObjectPath
+		if err := c.conn.Object(serviceName, item).CallWithContext(ctx, itemIface+".Delete", 0).Store(&prompt); err != nil {
+			return err
+		}
+		if err := c.prompt(ctx, prompt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *dbusCollection) search(ctx context.Context, attrs map[string]string) ([]map[string]string, error) {
+	items, err := c.searchItems(ctx, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		variant, err := c.conn.Object(serviceName, item).GetProperty(itemIface + ".Attributes")
+		if err != nil {
+			return nil, err
+		}
+		if itemAttrs, ok := variant.Value().(map[string]string); ok {
+			result = append(result, itemAttrs)
+		}
+	}
+	return result, nil
+}
+
+// searchItems returns the items matching attrs, unlocking any that are locked
+func (c *dbusCollection) searchItems(ctx context.Context, attrs map[string]string) ([]dbus.ObjectPath, error) {
+	var unlocked, locked []dbus.ObjectPath
+	if err := c.service().CallWithContext(ctx, serviceIface+".SearchItems", 0, attrs).Store(&unlocked, &locked); err != nil {
+		return nil, err
+	}
+	if len(locked) > 0 {
+		if err := c.unlock(ctx, locked); err != nil {
+			return nil, err
+		}
+	}
+	return append(unlocked, locked...), nil
+}
+
+// unlock unlocks objects, prompting the user if the Secret Service asks to
+func (c *dbusCollection) unlock(ctx context.Context, objects []dbus.ObjectPath) error {
+	var unlocked []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+	if err := c.service().CallWithContext(ctx, serviceIface+".Unlock", 0, objects).Store(&unlocked, &prompt); err != nil {
+		return err
+	}
+	return c.prompt(ctx, prompt)
+}
+
+// prompt shows a Secret Service prompt and waits for the user to complete it. The path
+// "/" means no prompt is needed.
+func (c *dbusCollection) prompt(ctx context.Context, path dbus.ObjectPath) error {
+	if path == noPrompt || path == "" {
+		return nil
+	}
+
+	match := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(path),
+		dbus.WithMatchInterface(promptIface),
+		dbus.WithMatchMember("Completed"),
+	}
+	if err := c.conn.AddMatchSignal(match...); err != nil {
+		return err
+	}
+	defer func() { _ = c.conn.RemoveMatchSignal(match...) }()
+
+	signals := make(chan *dbus.Signal, 1)
+	c.conn.Signal(signals)
+	defer c.conn.RemoveSignal(signals)
+
+	if err := c.conn.Object(serviceName, path).CallWithContext(ctx, promptIface+".Prompt", 0, "").Err; err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled: %w", ctx.Err())
+		case signal, ok := <-signals:
+			if !ok {
+				return errors.New("session bus closed while waiting for a secret service prompt")
+			}
+			if signal.Path != path || signal.Name != promptIface+".Completed" {
+				continue
+			}
+			if len(signal.Body) > 0 {
+				if dismissed, _ := signal.Body[0].(bool); dismissed {
+					return errPromptDismissed
+				}
+			}
+			return nil
+		}
+	}
+}
+
+func (c *dbusCollection) service() dbus.BusObject {
+	return c.conn.Object(serviceName, servicePath)
+}
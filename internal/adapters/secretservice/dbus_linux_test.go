@@ -0,0 +1,18 @@
+//go:build linux
+
+package secretservice
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewSecretServiceCredentialStore_Unavailable(t *testing.T) {
+	// A session bus address nobody listens on, as on a headless server
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:path="+filepath.Join(t.TempDir(), "no-bus"))
+
+	if _, err := NewSecretServiceCredentialStore(); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("NewSecretServiceCredentialStore() error = %v, want ErrUnavailable", err)
+	}
+}
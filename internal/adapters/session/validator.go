@@ -0,0 +1,76 @@
+// Package session provides a CredentialValidator that checks stored Claude sessions
+// offline: it decrypts the credentials and inspects the payload, but never contacts
+// Claude, so a session revoked on the server still passes.
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// ErrSessionExpired is returned when the session's expiry has passed
+var ErrSessionExpired = errors.New("session expired")
+
+// Validator implements ports.CredentialValidator by checking the decrypted session's
+// shape and expiry
+type Validator struct {
+	now func() time.Time
+}
+
+// Ensure Validator implements ports.CredentialValidator at compile time
+var _ ports.CredentialValidator = (*Validator)(nil)
+
+// Option configures optional Validator behavior
+type Option func(*Validator)
+
+// WithClock overrides the time source expiry is checked against
+func WithClock(now func() time.Time) Option {
+	return func(v *Validator) {
+		v.now = now
+	}
+}
+
+// NewValidator creates a Validator that checks expiry against the current time
+func NewValidator(opts ...Option) *Validator {
+	v := &Validator{now: time.Now}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Validate decrypts creds and checks they hold a session in a format Claude writes,
+// with a non-blank session key, that has not expired. A session with no expiry is
+// accepted. Passphrase-protected credentials can't be decrypted here and are accepted
+// unchecked.
+func (v *Validator) Validate(ctx context.Context, creds *domain.Credentials) error {
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context cancelled: %w", err)
+	}
+
+	if creds.IsPassphraseProtected() {
+		return nil
+	}
+
+	data, err := creds.Decrypt()
+	if err != nil {
+		return fmt.Errorf("failed to decrypt credentials: %w", err)
+	}
+
+	payload, err := domain.ParseCredentialPayload(data)
+	if err != nil {
+		return err
+	}
+
+	if expiresAt, ok := payload.ExpiresAt(); ok && !expiresAt.After(v.now()) {
+		return fmt.Errorf("%w at %s", ErrSessionExpired, expiresAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
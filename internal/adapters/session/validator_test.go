@@ -0,0 +1,68 @@
+package session_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/adapters/session"
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestValidator_Validate(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	validator := session.NewValidator(session.WithClock(func() time.Time { return now }))
+	millis := func(at time.Time) string { return strconv.FormatInt(at.UnixMilli(), 10) }
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{"flat without expiry", `{"sessionKey":"sk-1"}`, nil},
+		{"flat unexpired", `{"sessionKey":"sk-1","sessionKeyExpiresAt":` + millis(now.Add(time.Hour)) + `}`, nil},
+		{"nested unexpired", `{"claudeAiOauth":{"accessToken":"sk-ant-oat","expiresAt":` + millis(now.Add(time.Hour)) + `}}`, nil},
+		{"flat expired", `{"sessionKey":"sk-1","sessionKeyExpiresAt":` + millis(now.Add(-time.Hour)) + `}`, session.ErrSessionExpired},
+		{"nested expiring now", `{"claudeAiOauth":{"accessToken":"sk-ant-oat","expiresAt":` + millis(now) + `}}`, session.ErrSessionExpired},
+		{"blank session key", `{"sessionKey":" "}`, domain.ErrUnknownCredentialFormat},
+		{"unknown shape", `{"token":"sk-1"}`, domain.ErrUnknownCredentialFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds, err := domain.NewCredentials("abc12345", []byte(tt.data))
+			if err != nil {
+				t.Fatalf("NewCredentials() error = %v", err)
+			}
+			err = validator.Validate(context.Background(), creds)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_PassphraseProtected(t *testing.T) {
+	creds, err := domain.NewCredentialsWithPassphrase("abc12345", []byte(`{"token":"unknown"}`), []byte("passphrase"))
+	if err != nil {
+		t.Fatalf("NewCredentialsWithPassphrase() error = %v", err)
+	}
+	if err := session.NewValidator().Validate(context.Background(), creds); err != nil {
+		t.Errorf("Validate() error = %v, want nil for credentials it cannot decrypt", err)
+	}
+}
+
+func TestValidator_Validate_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	creds, _ := domain.NewCredentials("abc12345", []byte(`{"sessionKey":"sk-1"}`))
+	if err := session.NewValidator().Validate(ctx, creds); !errors.Is(err, context.Canceled) {
+		t.Errorf("Validate() error = %v, want context.Canceled", err)
+	}
+}
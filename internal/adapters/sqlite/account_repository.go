@@ -0,0 +1,436 @@
+// Package sqlite provides an AccountRepository backed by a SQLite database, for installs
+// with enough accounts that rewriting a JSON file on every change becomes wasteful.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	// Pure Go SQLite driver, registered as "sqlite"
+	_ "modernc.org/sqlite"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// DefaultFileName is the database file created inside the ccx data directory
+const DefaultFileName = "accounts.db"
+
+// timeLayout is the timestamp format stored in the database
+const timeLayout = time.RFC3339Nano
+
+// schema creates the accounts table and the indexes backing the FindBy lookups
+const schema = `
+CREATE TABLE IF NOT EXISTS accounts (
+	id         TEXT PRIMARY KEY,
+	email      TEXT NOT NULL,
+	alias      TEXT NOT NULL DEFAULT '',
+	uuid       TEXT NOT NULL,
+	tags       TEXT NOT NULL DEFAULT '[]',
+	color      TEXT NOT NULL DEFAULT '',
+	label      TEXT NOT NULL DEFAULT '',
+	description TEXT NOT NULL DEFAULT '',
+	env        TEXT NOT NULL DEFAULT '{}',
+	raw_oauth   BLOB,
+	created_at  TEXT NOT NULL,
+	last_used   TEXT NOT NULL,
+	archived_at TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_accounts_email ON accounts (email);
+CREATE INDEX IF NOT EXISTS idx_accounts_alias ON accounts (alias);
+CREATE INDEX IF NOT EXISTS idx_accounts_uuid ON accounts (uuid);
+`
+
+// addedColumns are columns added after the accounts table was first released, with
+// their definitions. Databases created before them are upgraded when opened.
+var addedColumns = []struct{ name, definition string }{
+	{"color", "TEXT NOT NULL DEFAULT ''"},
+	{"label", "TEXT NOT NULL DEFAULT ''"},
+	{"archived_at", "TEXT NOT NULL DEFAULT ''"},
+	{"description", "TEXT NOT NULL DEFAULT ''"},
+	{"env", "TEXT NOT NULL DEFAULT '{}'"},
+}
+
+// accountColumns lists the columns scanned by scanAccount, in order
+const accountColumns = "id, email, alias, uuid, tags, color, label, description, env, raw_oauth, created_at, last_used, archived_at"
+
+// SQLiteAccountRepository implements AccountRepository using a SQLite database.
+// It holds a single connection in WAL mode; lookups by email, alias, and uuid are indexed.
+type SQLiteAccountRepository struct { //nolint:revive // keeps the backend in the name like FileAccountRepository
+	db *sql.DB
+}
+
+// Ensure SQLiteAccountRepository implements AccountRepository and AccountPager at compile time
+var (
+	_ ports.AccountRepository = (*SQLiteAccountRepository)(nil)
+	_ ports.AccountPager      = (*SQLiteAccountRepository)(nil)
+)
+
+// NewSQLiteAccountRepository opens (creating if needed) the database at path and prepares
+// its schema. Callers must Close the repository when done.
+func NewSQLiteAccountRepository(path string) (*SQLiteAccountRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	// Create the file up front so it gets restrictive permissions
+	// #nosec G304 - controlled file path within app data directory
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to create database file: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	// A single connection keeps writes serialized and pragmas in effect
+	db.SetMaxOpenConns(1)
+
+	for _, stmt := range []string{
+		"PRAGMA journal_mode = WAL",
+		"PRAGMA synchronous = FULL",
+		"PRAGMA busy_timeout = 5000",
+		schema,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to initialize database: %w", err)
+		}
+	}
+	if err := upgradeSchema(db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to upgrade database: %w", err)
+	}
+
+	return &SQLiteAccountRepository{db: db}, nil
+}
+
+// upgradeSchema adds any of addedColumns missing from an older accounts table
+func upgradeSchema(db *sql.DB) error {
+	rows, err := db.Query("SELECT name FROM pragma_table_info('accounts')")
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		existing[name] = true
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, column := range addedColumns {
+		if existing[column.name] {
+			continue
+		}
+		// #nosec G202 - column names and definitions are constants
+		if _, err := db.Exec("ALTER TABLE accounts ADD COLUMN " + column.name + " " + column.definition); err != nil {
+			return fmt.Errorf("failed to add %s column: %w", column.name, err)
+		}
+	}
+	return nil
+}
+
+// Close releases the database connection
+func (r *SQLiteAccountRepository) Close() error {
+	return r.db.Close()
+}
+
+// Save inserts the account or updates it in place if the ID already exists
+func (r *SQLiteAccountRepository) Save(ctx context.Context, account *domain.Account) error {
+	tags, err := json.Marshal(account.Tags())
+	if err != nil {
+		return fmt.Errorf("failed to encode tags: %w", err)
+	}
+	env, err := json.Marshal(account.EnvOverrides())
+	if err != nil {
+		return fmt.Errorf("failed to encode env: %w", err)
+	}
+
+	var rawOAuth []byte
+	if raw := account.RawOAuth(); len(raw) > 0 {
+		rawOAuth = raw
+	}
+
+	// Unarchived accounts store an empty string, like the column default
+	var archivedAt string
+	if account.Archived() {
+		archivedAt = account.ArchivedAt().Format(timeLayout)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+INSERT INTO accounts (`+accountColumns+`)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+	email = excluded.email,
+	alias = excluded.alias,
+	uuid = excluded.uuid,
+	tags = excluded.tags,
+	color = excluded.color,
+	label = excluded.label,
+	description = excluded.description,
+	env = excluded.env,
+	raw_oauth = excluded.raw_oauth,
+	created_at = excluded.created_at,
+	last_used = excluded.last_used,
+	archived_at = excluded.archived_at`,
+		string(account.ID()),
+		string(account.Email()),
+		account.Alias(),
+		account.UUID(),
+		string(tags),
+		account.Color(),
+		account.Label(),
+		account.Description(),
+		string(env),
+		rawOAuth,
+		account.CreatedAt().Format(timeLayout),
+		account.LastUsed().Format(timeLayout),
+		archivedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save account: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves an account by ID
+func (r *SQLiteAccountRepository) FindByID(ctx context.Context, id domain.AccountID) (*domain.Account, error) {
+	return r.findOne(ctx, "id = ?", string(id))
+}
+
+// FindByEmail retrieves an account by email, ignoring case and surrounding whitespace
+func (r *SQLiteAccountRepository) FindByEmail(ctx context.Context, email domain.Email) (*domain.Account, error) {
+	// Stored emails are always normalized, so only the argument needs normalizing
+	return r.findOne(ctx, "email = ?", string(domain.NormalizeEmail(string(email))))
+}
+
+// FindByAlias retrieves an account by alias
+func (r *SQLiteAccountRepository) FindByAlias(ctx context.Context, alias string) (*domain.Account, error) {
+	return r.findOne(ctx, "alias = ?", alias)
+}
+
+// FindByUUID retrieves an account by its Claude account UUID
+func (r *SQLiteAccountRepository) FindByUUID(ctx context.Context, uuid string) (*domain.Account, error) {
+	return r.findOne(ctx, "uuid = ?", uuid)
+}
+
+// List returns all accounts in the order they were first saved
+func (r *SQLiteAccountRepository) List(ctx context.Context) ([]*domain.Account, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+accountColumns+" FROM accounts ORDER BY rowid")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	accounts := []*domain.Account{}
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// ListPage returns one page of the accounts matching query, and how many match in total.
+// Filtering, ordering, and paging all happen in the database.
+func (r *SQLiteAccountRepository) ListPage(ctx context.Context, query ports.AccountPageQuery) ([]*domain.Account, int, error) {
+	var orderBy string
+	switch query.SortBy {
+	case ports.AccountSortEmail:
+		orderBy = "email"
+	case ports.AccountSortAlias:
+		orderBy = "alias"
+	default:
+		return nil, 0, fmt.Errorf("unsupported sort field %q", query.SortBy)
+	}
+	if query.Descending {
+		orderBy += " DESC, email DESC"
+	} else {
+		orderBy += ", email"
+	}
+
+	where := "1 = 1"
+	var args []any
+	if !query.IncludeArchived {
+		where += " AND archived_at = ''"
+	}
+	if query.AliasContains != "" {
+		where += " AND instr(lower(alias), ?) > 0"
+		args = append(args, strings.ToLower(query.AliasContains))
+	}
+	if query.EmailContains != "" {
+		where += " AND instr(lower(email), ?) > 0"
+		args = append(args, strings.ToLower(query.EmailContains))
+	}
+
+	limit := -1 // No limit
+	if query.Limit > 0 {
+		limit = query.Limit
+	}
+
+	// Count and select in one read transaction so the total matches the page
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var total int
+	// #nosec G202 - where is built from constants in this function
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM accounts WHERE "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count accounts: %w", err)
+	}
+
+	// #nosec G202 - where and orderBy are built from constants in this function
+	rows, err := tx.QueryContext(ctx,
+		"SELECT "+accountColumns+" FROM accounts WHERE "+where+" ORDER BY "+orderBy+", rowid LIMIT ? OFFSET ?",
+		append(args, limit, query.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	accounts := []*domain.Account{}
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		accounts = append(accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	return accounts, total, nil
+}
+
+// Delete removes an account
+func (r *SQLiteAccountRepository) Delete(ctx context.Context, id domain.AccountID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM accounts WHERE id = ?", string(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	if affected == 0 {
+		return domain.ErrAccountNotFound
+	}
+
+	return nil
+}
+
+// findOne returns the first account matching the where clause, in save order
+func (r *SQLiteAccountRepository) findOne(ctx context.Context, where string, arg any) (*domain.Account, error) {
+	// #nosec G202 - where clauses are constants supplied by this package
+	row := r.db.QueryRowContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE "+where+" ORDER BY rowid LIMIT 1", arg)
+
+	account, err := scanAccount(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAccountNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// scanner is satisfied by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanAccount converts a row selected with accountColumns to a domain.Account
+func scanAccount(row scanner) (*domain.Account, error) {
+	var (
+		id, email, alias, uuid, tags string
+		color, label, description    string
+		env                          string
+		rawOAuth                     []byte
+		createdAt, lastUsed          string
+		archivedAt                   string
+	)
+	if err := row.Scan(&id, &email, &alias, &uuid, &tags, &color, &label, &description, &env, &rawOAuth, &createdAt, &lastUsed, &archivedAt); err != nil {
+		return nil, fmt.Errorf("failed to read account: %w", err)
+	}
+
+	created, err := time.Parse(timeLayout, createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid created_at for account %s: %w", id, err)
+	}
+	used, err := time.Parse(timeLayout, lastUsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid last_used for account %s: %w", id, err)
+	}
+
+	account, err := domain.ReconstructAccount(domain.AccountID(id), email, alias, uuid, rawOAuth, created, used)
+	if err != nil {
+		return nil, err
+	}
+
+	var tagList []string
+	if err := json.Unmarshal([]byte(tags), &tagList); err != nil {
+		return nil, fmt.Errorf("invalid tags for account %s: %w", id, err)
+	}
+	for _, tag := range tagList {
+		if err := account.AddTag(tag); err != nil {
+			return nil, err
+		}
+	}
+	if err := account.SetColor(color); err != nil {
+		return nil, err
+	}
+	if err := account.SetLabel(label); err != nil {
+		return nil, err
+	}
+	if err := account.SetDescription(description); err != nil {
+		return nil, err
+	}
+	var envOverrides map[string]string
+	if err := json.Unmarshal([]byte(env), &envOverrides); err != nil {
+		return nil, fmt.Errorf("invalid env for account %s: %w", id, err)
+	}
+	if err := account.SetEnvOverrides(envOverrides); err != nil {
+		return nil, err
+	}
+	if archivedAt != "" {
+		archived, err := time.Parse(timeLayout, archivedAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid archived_at for account %s: %w", id, err)
+		}
+		account.Archive(archived)
+	}
+
+	return account, nil
+}
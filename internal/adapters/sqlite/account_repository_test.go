@@ -0,0 +1,310 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// newTestRepository opens a repository in a fresh temp directory
+func newTestRepository(t *testing.T) (*SQLiteAccountRepository, string) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "ccx-sqlite-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(tmpDir) })
+
+	path := filepath.Join(tmpDir, DefaultFileName)
+	repo, err := NewSQLiteAccountRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	t.Cleanup(func() { _ = repo.Close() })
+
+	return repo, path
+}
+
+// TestSQLiteAccountRepository_Contract mirrors the AccountRepository interface contract
+func TestSQLiteAccountRepository_Contract(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	account, err := domain.NewAccount("test@example.com", "test-alias", "uuid-123")
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+
+	if err := repo.Save(ctx, account); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, account.ID())
+	if err != nil || found.ID() != account.ID() {
+		t.Errorf("FindByID() = %v, %v; want %s", found, err, account.ID())
+	}
+
+	found, err = repo.FindByEmail(ctx, " TEST@example.com")
+	if err != nil || found.ID() != account.ID() {
+		t.Errorf("FindByEmail() = %v, %v; want %s", found, err, account.ID())
+	}
+
+	found, err = repo.FindByAlias(ctx, "test-alias")
+	if err != nil || found.ID() != account.ID() {
+		t.Errorf("FindByAlias() = %v, %v; want %s", found, err, account.ID())
+	}
+
+	found, err = repo.FindByUUID(ctx, "uuid-123")
+	if err != nil || found.ID() != account.ID() {
+		t.Errorf("FindByUUID() = %v, %v; want %s", found, err, account.ID())
+	}
+
+	accounts, err := repo.List(ctx)
+	if err != nil || len(accounts) != 1 {
+		t.Errorf("List() = %d accounts, %v; want 1", len(accounts), err)
+	}
+
+	if err := repo.Delete(ctx, account.ID()); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+
+	// Every lookup reports the same not found error as the other repositories
+	notFound := []struct {
+		name string
+		err  error
+	}{
+		{"FindByID", func() error { _, err := repo.FindByID(ctx, account.ID()); return err }()},
+		{"FindByEmail", func() error { _, err := repo.FindByEmail(ctx, account.Email()); return err }()},
+		{"FindByAlias", func() error { _, err := repo.FindByAlias(ctx, "test-alias"); return err }()},
+		{"FindByUUID", func() error { _, err := repo.FindByUUID(ctx, "uuid-123"); return err }()},
+		{"Delete", repo.Delete(ctx, account.ID())},
+	}
+	for _, tt := range notFound {
+		if !errors.Is(tt.err, domain.ErrAccountNotFound) {
+			t.Errorf("%s() after deletion error = %v, want ErrAccountNotFound", tt.name, tt.err)
+		}
+	}
+
+	accounts, err = repo.List(ctx)
+	if err != nil || len(accounts) != 0 {
+		t.Errorf("List() = %d accounts, %v; want empty", len(accounts), err)
+	}
+}
+
+// TestSQLiteAccountRepository_RoundTrip tests that every account field survives storage
+// and that saving an existing ID updates it in place
+func TestSQLiteAccountRepository_RoundTrip(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	account, _ := domain.ReconstructAccount("abc12345", "work@example.com", "work", "uuid-work",
+		json.RawMessage(`{"organizationUuid":"org-1"}`), created, created)
+	_ = account.AddTag("client")
+	_ = account.SetColor("red")
+	_ = account.SetLabel("prod")
+	_ = account.SetDescription("2FA via Yubikey, billing owner is Jane")
+	_ = account.SetEnvOverride("ANTHROPIC_BASE_URL", "https://gateway.example.com")
+	archivedAt := created.Add(time.Hour)
+	account.Archive(archivedAt)
+	first, _ := domain.NewAccount("first@example.com", "first", "uuid-first")
+	_ = repo.Save(ctx, first)
+	if err := repo.Save(ctx, account); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	account.MarkUsed()
+	_ = account.UpdateAlias("office")
+	if err := repo.Save(ctx, account); err != nil {
+		t.Fatalf("Save() update error = %v", err)
+	}
+
+	accounts, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(accounts) != 2 || accounts[0].ID() != first.ID() {
+		t.Fatalf("List() should keep save order and not duplicate updated accounts, got %d", len(accounts))
+	}
+
+	found := accounts[1]
+	if found.Alias() != "office" || found.UUID() != "uuid-work" {
+		t.Errorf("alias/uuid = %s/%s, want office/uuid-work", found.Alias(), found.UUID())
+	}
+	if !found.CreatedAt().Equal(created) {
+		t.Errorf("CreatedAt() = %v, want %v", found.CreatedAt(), created)
+	}
+	if !found.LastUsed().Equal(account.LastUsed()) {
+		t.Errorf("LastUsed() = %v, want %v", found.LastUsed(), account.LastUsed())
+	}
+	if tags := found.Tags(); len(tags) != 1 || tags[0] != "client" {
+		t.Errorf("Tags() = %v, want [client]", tags)
+	}
+	if found.Color() != "red" || found.Label() != "prod" {
+		t.Errorf("Color/Label = %q/%q, want red/prod", found.Color(), found.Label())
+	}
+	if found.Description() != "2FA via Yubikey, billing owner is Jane" {
+		t.Errorf("Description() = %q, want the saved description", found.Description())
+	}
+	if env := found.EnvOverrides(); len(env) != 1 || env["ANTHROPIC_BASE_URL"] != "https://gateway.example.com" {
+		t.Errorf("EnvOverrides() = %v, want the saved override", env)
+	}
+	if !found.ArchivedAt().Equal(archivedAt) {
+		t.Errorf("ArchivedAt() = %v, want %v", found.ArchivedAt(), archivedAt)
+	}
+	if string(found.RawOAuth()) != `{"organizationUuid":"org-1"}` {
+		t.Errorf("RawOAuth() = %s", found.RawOAuth())
+	}
+	if first, _ := repo.FindByID(ctx, first.ID()); first.RawOAuth() != nil || first.Archived() {
+		t.Errorf("RawOAuth() = %s, Archived() = %v; want nil and false for a plain account", first.RawOAuth(), first.Archived())
+	}
+}
+
+func TestSQLiteAccountRepository_ListPage(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := context.Background()
+
+	for _, f := range []struct{ email, alias string }{
+		{"carol@corp.com", "Work"},
+		{"alice@home.org", "personal"},
+		{"bob@corp.com", "client-work"},
+		{"dave@corp.com", "old-work"},
+	} {
+		account, _ := domain.NewAccount(f.email, f.alias, "uuid-"+f.email)
+		if f.alias == "old-work" {
+			account.Archive(time.Now())
+		}
+		_ = repo.Save(ctx, account)
+	}
+
+	tests := []struct {
+		name      string
+		query     ports.AccountPageQuery
+		want      []string
+		wantTotal int
+	}{
+		{"email", ports.AccountPageQuery{SortBy: ports.AccountSortEmail}, []string{"alice@home.org", "bob@corp.com", "carol@corp.com"}, 3},
+		{"alias descending", ports.AccountPageQuery{SortBy: ports.AccountSortAlias, Descending: true}, []string{"alice@home.org", "bob@corp.com", "carol@corp.com"}, 3},
+		{"window", ports.AccountPageQuery{SortBy: ports.AccountSortEmail, Offset: 1, Limit: 1}, []string{"bob@corp.com"}, 3},
+		{"offset past end", ports.AccountPageQuery{SortBy: ports.AccountSortEmail, Offset: 5}, []string{}, 3},
+		{"filters ignore case", ports.AccountPageQuery{SortBy: ports.AccountSortEmail, AliasContains: "WORK", EmailContains: "Corp", Limit: 1}, []string{"bob@corp.com"}, 2},
+		{"archived", ports.AccountPageQuery{SortBy: ports.AccountSortEmail, IncludeArchived: true, Offset: 3}, []string{"dave@corp.com"}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accounts, total, err := repo.ListPage(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("ListPage() error = %v", err)
+			}
+			got := make([]string, len(accounts))
+			for i, account := range accounts {
+				got[i] = string(account.Email())
+			}
+			if !slices.Equal(got, tt.want) || total != tt.wantTotal {
+				t.Errorf("ListPage() = %v, %d; want %v, %d", got, total, tt.want, tt.wantTotal)
+			}
+		})
+	}
+
+	if _, _, err := repo.ListPage(ctx, ports.AccountPageQuery{SortBy: "created"}); err == nil {
+		t.Error("ListPage() with unsupported sort field error = nil, want error")
+	}
+}
+
+// TestSQLiteAccountRepository_Persistence tests reopening the database and its setup
+func TestSQLiteAccountRepository_Persistence(t *testing.T) {
+	repo, path := newTestRepository(t)
+	ctx := context.Background()
+
+	account, _ := domain.NewAccount("test@example.com", "test", "uuid-test")
+	if err := repo.Save(ctx, account); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat database: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("database permissions = %o, want 600", info.Mode().Perm())
+	}
+
+	reopened, err := NewSQLiteAccountRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen repository: %v", err)
+	}
+	defer func() { _ = reopened.Close() }()
+
+	if _, err := reopened.FindByEmail(ctx, "test@example.com"); err != nil {
+		t.Errorf("FindByEmail() after reopen error = %v", err)
+	}
+
+	var mode string
+	if err := reopened.db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil || mode != "wal" {
+		t.Errorf("journal_mode = %q, %v; want wal", mode, err)
+	}
+}
+
+// TestSQLiteAccountRepository_UpgradesSchema tests opening a database created before the
+// color and label columns existed
+func TestSQLiteAccountRepository_UpgradesSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFileName)
+	old, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	_, err = old.Exec(`
+CREATE TABLE accounts (
+	id         TEXT PRIMARY KEY,
+	email      TEXT NOT NULL,
+	alias      TEXT NOT NULL DEFAULT '',
+	uuid       TEXT NOT NULL,
+	tags       TEXT NOT NULL DEFAULT '[]',
+	raw_oauth  BLOB,
+	created_at TEXT NOT NULL,
+	last_used  TEXT NOT NULL
+);
+INSERT INTO accounts (id, email, uuid, created_at, last_used)
+VALUES ('abc12345', 'old@example.com', 'uuid-old', '2024-01-02T03:04:05Z', '2024-01-02T03:04:05Z');`)
+	_ = old.Close()
+	if err != nil {
+		t.Fatalf("Failed to create old schema: %v", err)
+	}
+
+	repo, err := NewSQLiteAccountRepository(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteAccountRepository() error = %v", err)
+	}
+	defer func() { _ = repo.Close() }()
+
+	ctx := context.Background()
+	account, err := repo.FindByID(ctx, "abc12345")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if account.Color() != "" || account.Label() != "" || account.Description() != "" || account.EnvOverrides() != nil || account.Archived() {
+		t.Errorf("Color/Label/Description/Archived = %q/%q/%q/%v, want empty and unarchived", account.Color(), account.Label(), account.Description(), account.Archived())
+	}
+
+	_ = account.SetColor("blue")
+	if err := repo.Save(ctx, account); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if found, _ := repo.FindByID(ctx, "abc12345"); found.Color() != "blue" {
+		t.Errorf("Color() = %q after save, want blue", found.Color())
+	}
+}
@@ -0,0 +1,68 @@
+//go:build windows
+
+package wincred
+
+import (
+	"errors"
+
+	"github.com/danieljoos/wincred"
+
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// NewWinCredCredentialStore returns a credential store using the current user's Windows
+// Credential Manager
+func NewWinCredCredentialStore() ports.CredentialStore {
+	return newWinCredCredentialStore(credentialManager{})
+}
+
+// credentialManager implements vault with generic credentials, through CredRead,
+// CredWrite, CredDelete, and CredEnumerate
+type credentialManager struct{}
+
+func (credentialManager) read(target string) ([]byte, error) {
+	cred, err := wincred.GetGenericCredential(target)
+	if errors.Is(err, wincred.ErrElementNotFound) {
+		return nil, errItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cred.CredentialBlob, nil
+}
+
+func (credentialManager) write(target string, blob []byte) error {
+	cred := wincred.NewGenericCredential(target)
+	cred.CredentialBlob = blob
+	cred.Comment = "Claude Code session credentials"
+	cred.Persist = wincred.PersistLocalMachine
+	return cred.Write()
+}
+
+func (credentialManager) remove(target string) error {
+	cred, err := wincred.GetGenericCredential(target)
+	if errors.Is(err, wincred.ErrElementNotFound) {
+		return errItemNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := cred.Delete(); errors.Is(err, wincred.ErrElementNotFound) {
+		return errItemNotFound
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (credentialManager) targets(prefix string) ([]string, error) {
+	creds, err := wincred.FilteredList(prefix + "*")
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]string, len(creds))
+	for i, cred := range creds {
+		targets[i] = cred.TargetName
+	}
+	return targets, nil
+}
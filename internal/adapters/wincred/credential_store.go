@@ -0,0 +1,270 @@
+// Package wincred provides a CredentialStore backed by the Windows Credential Manager.
+// The Credential Manager binding only builds on Windows; the store logic on top of it,
+// including splitting credentials too large for one item, is platform independent so it
+// can be tested everywhere.
+package wincred
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// targetPrefix namespaces ccx items among the user's Windows credentials
+const targetPrefix = "ccx:"
+
+// maxBlobSize is the largest blob a generic credential can hold
+// (CRED_MAX_CREDENTIAL_BLOB_SIZE)
+const maxBlobSize = 5 * 512
+
+// chunkSeparator joins an account's target name and a chunk index. Account IDs never
+// contain it, so chunk items are not mistaken for accounts.
+const chunkSeparator = "#"
+
+// manifestPrefix starts the blob of an account item whose credentials are split into
+// chunks. Serialized credentials are JSON objects, so they never start with it. The
+// prefix is followed by the chunk count and, since chunks are written under fresh names
+// on every store, their generation: "ccx-chunks:<n>:<generation>". Items written before
+// generations were introduced hold only the count and use generation 0.
+const manifestPrefix = "ccx-chunks:"
+
+// manifest describes the chunks an account item refers to
+type manifest struct {
+	count      int // Number of chunks; 0 if the item holds the credentials itself
+	generation int // Distinguishes the chunk names of successive stores
+}
+
+// errItemNotFound is returned by a vault when no item has the target name
+var errItemNotFound = errors.New("credential item not found")
+
+// vault stores blobs under target names, as the Windows Credential Manager does
+type vault interface {
+	read(target string) ([]byte, error)
+	write(target string, blob []byte) error
+	remove(target string) error
+	targets(prefix string) ([]string, error)
+}
+
+// WinCredCredentialStore implements CredentialStore using the Windows Credential Manager.
+// Items are generic credentials named "ccx:<accountID>" holding the serialized domain
+// credentials. Credentials larger than one item can hold are split across items named
+// "ccx:<accountID>#<generation>.<n>", and the account's item lists how many there are.
+type WinCredCredentialStore struct { //nolint:revive // keeps the backend in the name like FileCredentialStore
+	vault vault
+	mu    sync.RWMutex
+}
+
+// Ensure WinCredCredentialStore can enumerate its credentials at compile time
+var _ ports.CredentialLister = (*WinCredCredentialStore)(nil)
+
+// newWinCredCredentialStore creates a credential store on top of vault
+func newWinCredCredentialStore(vault vault) *WinCredCredentialStore {
+	return &WinCredCredentialStore{
+		vault: vault,
+	}
+}
+
+// Store saves credentials to the Credential Manager, replacing any existing items for
+// the account. Chunks are written under fresh names before the account's item is
+// switched to them, and the previous chunks are only deleted afterwards, so a failed
+// write leaves the previous credentials readable.
+func (s *WinCredCredentialStore) Store(_ context.Context, creds *domain.Credentials) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Serialize credentials using domain's built-in encryption
+	data, err := creds.Serialize()
+	if err != nil {
+		return fmt.Errorf("failed to serialize credentials: %w", err)
+	}
+
+	target := targetName(creds.AccountID())
+	old, err := s.readManifest(target)
+	if err != nil && !errors.Is(err, errItemNotFound) {
+		return err
+	}
+
+	blob := data
+	next := manifest{generation: old.generation + 1}
+	if len(data) > maxBlobSize {
+		chunks := slices.Collect(slices.Chunk(data, maxBlobSize))
+		for i, chunk := range chunks {
+			if err := s.vault.write(chunkTarget(target, next.generation, i), chunk); err != nil {
+				_ = s.removeChunks(target, manifest{count: i, generation: next.generation})
+				return fmt.Errorf("failed to write credential item: %w", err)
+			}
+		}
+		next.count = len(chunks)
+		blob = next.blob()
+	}
+
+	if err := s.vault.write(target, blob); err != nil {
+		_ = s.removeChunks(target, next)
+		return fmt.Errorf("failed to write credential item: %w", err)
+	}
+
+	// The previous chunks are no longer referenced
+	if err := s.removeChunks(target, old); err != nil {
+		return fmt.Errorf("failed to delete stale credential item: %w", err)
+	}
+
+	return nil
+}
+
+// Retrieve gets credentials for an account from the Credential Manager
+func (s *WinCredCredentialStore) Retrieve(_ context.Context, accountID domain.AccountID) (*domain.Credentials, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	target := targetName(accountID)
+	data, err := s.vault.read(target)
+	if errors.Is(err, errItemNotFound) {
+		return nil, domain.ErrCredentialsNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credential item: %w", err)
+	}
+
+	if m, err := parseManifest(data); err != nil {
+		return nil, fmt.Errorf("invalid credential item for account %s: %w", accountID, err)
+	} else if m.count > 0 {
+		data = nil
+		for i := range m.count {
+			chunk, err := s.vault.read(chunkTarget(target, m.generation, i))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read credential item %d of %d for account %s: %w", i+1, m.count, accountID, err)
+			}
+			data = append(data, chunk...)
+		}
+	}
+
+	// Deserialize credentials using domain's built-in decryption
+	creds, err := domain.DeserializeCredentials(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize credentials: %w", err)
+	}
+
+	return creds, nil
+}
+
+// Delete removes credentials for an account, including any chunks, from the Credential Manager
+func (s *WinCredCredentialStore) Delete(_ context.Context, accountID domain.AccountID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target := targetName(accountID)
+	m, err := s.readManifest(target)
+	if errors.Is(err, errItemNotFound) {
+		return domain.ErrCredentialsNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.removeChunks(target, m); err != nil {
+		return fmt.Errorf("failed to delete credential item: %w", err)
+	}
+
+	if err := s.vault.remove(target); errors.Is(err, errItemNotFound) {
+		return domain.ErrCredentialsNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to delete credential item: %w", err)
+	}
+
+	return nil
+}
+
+// ListAccountIDs returns the IDs of all accounts with a ccx credential item, sorted
+func (s *WinCredCredentialStore) ListAccountIDs(_ context.Context) ([]domain.AccountID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	targets, err := s.vault.targets(targetPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credential items: %w", err)
+	}
+
+	ids := make([]domain.AccountID, 0, len(targets))
+	for _, target := range targets {
+		if id, ok := strings.CutPrefix(target, targetPrefix); ok && id != "" && !strings.Contains(id, chunkSeparator) {
+			ids = append(ids, domain.AccountID(id))
+		}
+	}
+	slices.Sort(ids)
+
+	return ids, nil
+}
+
+// readManifest returns the chunks the account item at target refers to, a zero count if
+// its credentials fit in the item itself
+func (s *WinCredCredentialStore) readManifest(target string) (manifest, error) {
+	data, err := s.vault.read(target)
+	if errors.Is(err, errItemNotFound) {
+		return manifest{}, err
+	}
+	if err != nil {
+		return manifest{}, fmt.Errorf("failed to read credential item: %w", err)
+	}
+
+	m, err := parseManifest(data)
+	if err != nil {
+		return manifest{}, fmt.Errorf("invalid credential item %s: %w", target, err)
+	}
+	return m, nil
+}
+
+// removeChunks deletes the chunks m names under target. Chunks already gone are skipped.
+func (s *WinCredCredentialStore) removeChunks(target string, m manifest) error {
+	for i := range m.count {
+		if err := s.vault.remove(chunkTarget(target, m.generation, i)); err != nil && !errors.Is(err, errItemNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseManifest returns the chunks an account item's blob refers to. The count is 0 if
+// the blob holds the credentials themselves.
+func parseManifest(blob []byte) (manifest, error) {
+	fields, ok := strings.CutPrefix(string(blob), manifestPrefix)
+	if !ok {
+		return manifest{}, nil
+	}
+	count, generation, versioned := strings.Cut(fields, ":")
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return manifest{}, fmt.Errorf("bad chunk count %q", count)
+	}
+	m := manifest{count: n}
+	if versioned {
+		if m.generation, err = strconv.Atoi(generation); err != nil || m.generation < 1 {
+			return manifest{}, fmt.Errorf("bad chunk generation %q", generation)
+		}
+	}
+	return m, nil
+}
+
+// blob returns the account item contents naming m's chunks
+func (m manifest) blob() []byte {
+	return []byte(manifestPrefix + strconv.Itoa(m.count) + ":" + strconv.Itoa(m.generation))
+}
+
+func targetName(accountID domain.AccountID) string {
+	return targetPrefix + string(accountID)
+}
+
+// chunkTarget names chunk index of the given generation. Generation 0 is the unversioned
+// naming used before generations were introduced.
+func chunkTarget(target string, generation, index int) string {
+	if generation == 0 {
+		return target + chunkSeparator + strconv.Itoa(index)
+	}
+	return target + chunkSeparator + strconv.Itoa(generation) + "." + strconv.Itoa(index)
+}
@@ -0,0 +1,250 @@
+package wincred
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+// memoryVault is an in-memory vault enforcing the Credential Manager's blob size limit
+type memoryVault struct {
+	items     map[string][]byte
+	writeErr  error
+	failAfter int // Writes that succeed before writeErr is returned
+}
+
+func newMemoryVault() *memoryVault {
+	return &memoryVault{items: make(map[string][]byte)}
+}
+
+func (v *memoryVault) read(target string) ([]byte, error) {
+	blob, ok := v.items[target]
+	if !ok {
+		return nil, errItemNotFound
+	}
+	return slices.Clone(blob), nil
+}
+
+func (v *memoryVault) write(target string, blob []byte) error {
+	if v.writeErr != nil {
+		if v.failAfter == 0 {
+			return v.writeErr
+		}
+		v.failAfter--
+	}
+	if len(blob) > maxBlobSize {
+		return errors.New("blob too large")
+	}
+	v.items[target] = slices.Clone(blob)
+	return nil
+}
+
+func (v *memoryVault) remove(target string) error {
+	if _, ok := v.items[target]; !ok {
+		return errItemNotFound
+	}
+	delete(v.items, target)
+	return nil
+}
+
+func (v *memoryVault) targets(prefix string) ([]string, error) {
+	var targets []string
+	for target := range v.items {
+		if strings.HasPrefix(target, prefix) {
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+// sessionCredentials returns credentials whose payload is about size bytes
+func sessionCredentials(t *testing.T, accountID domain.AccountID, size int) *domain.Credentials {
+	t.Helper()
+	creds, err := domain.NewCredentials(accountID, []byte(`{"sessionKey":"`+strings.Repeat("k", size)+`"}`))
+	if err != nil {
+		t.Fatalf("Failed to create credentials: %v", err)
+	}
+	return creds
+}
+
+func TestWinCredCredentialStore_StoreAndRetrieve(t *testing.T) {
+	ctx := context.Background()
+
+	for _, size := range []int{16, maxBlobSize, 3 * maxBlobSize} {
+		vault := newMemoryVault()
+		store := newWinCredCredentialStore(vault)
+		creds := sessionCredentials(t, "abc12345", size)
+		want, _ := creds.Decrypt()
+
+		if err := store.Store(ctx, creds); err != nil {
+			t.Fatalf("Store() of %d bytes error = %v", size, err)
+		}
+		if _, ok := vault.items["ccx:abc12345"]; !ok {
+			t.Fatalf("Expected credential item ccx:abc12345, got %v", slices.Sorted(maps.Keys(vault.items)))
+		}
+
+		retrieved, err := store.Retrieve(ctx, "abc12345")
+		if err != nil {
+			t.Fatalf("Retrieve() of %d bytes error = %v", size, err)
+		}
+		if got, _ := retrieved.Decrypt(); string(got) != string(want) {
+			t.Errorf("Retrieve() of %d bytes returned different credentials", size)
+		}
+	}
+}
+
+func TestWinCredCredentialStore_StoreReplacesChunks(t *testing.T) {
+	ctx := context.Background()
+	vault := newMemoryVault()
+	store := newWinCredCredentialStore(vault)
+
+	if err := store.Store(ctx, sessionCredentials(t, "abc12345", 3*maxBlobSize)); err != nil {
+		t.Fatalf("Store() large error = %v", err)
+	}
+	if len(vault.items) < 4 {
+		t.Fatalf("Large credentials stored in %d items, want them chunked", len(vault.items))
+	}
+
+	small := sessionCredentials(t, "abc12345", 16)
+	if err := store.Store(ctx, small); err != nil {
+		t.Fatalf("Store() small error = %v", err)
+	}
+	if len(vault.items) != 1 {
+		t.Errorf("Stale chunks left behind: %v", slices.Sorted(maps.Keys(vault.items)))
+	}
+
+	// A failed write keeps the previous credentials readable
+	vault.writeErr = errors.New("access denied")
+	if err := store.Store(ctx, sessionCredentials(t, "abc12345", 3*maxBlobSize)); err == nil {
+		t.Fatal("Store() with failing vault error = nil, want error")
+	}
+	if _, err := store.Retrieve(ctx, "abc12345"); err != nil {
+		t.Errorf("Retrieve() after failed Store error = %v", err)
+	}
+}
+
+func TestWinCredCredentialStore_StoreFailsMidway(t *testing.T) {
+	ctx := context.Background()
+	vault := newMemoryVault()
+	store := newWinCredCredentialStore(vault)
+
+	old := sessionCredentials(t, "abc12345", 3*maxBlobSize)
+	if err := store.Store(ctx, old); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	before := slices.Sorted(maps.Keys(vault.items))
+
+	// Replacing large credentials fails after some chunks are written
+	vault.writeErr, vault.failAfter = errors.New("access denied"), 2
+	if err := store.Store(ctx, sessionCredentials(t, "abc12345", 4*maxBlobSize)); err == nil {
+		t.Fatal("Store() with failing vault error = nil, want error")
+	}
+
+	retrieved, err := store.Retrieve(ctx, "abc12345")
+	if err != nil {
+		t.Fatalf("Retrieve() after failed Store error = %v", err)
+	}
+	want, _ := old.Decrypt()
+	if got, _ := retrieved.Decrypt(); string(got) != string(want) {
+		t.Error("Retrieve() after failed Store returned a mix of old and new chunks")
+	}
+	if after := slices.Sorted(maps.Keys(vault.items)); !slices.Equal(after, before) {
+		t.Errorf("Items after failed Store() = %v, want %v", after, before)
+	}
+}
+
+func TestWinCredCredentialStore_UnversionedChunks(t *testing.T) {
+	ctx := context.Background()
+	vault := newMemoryVault()
+	store := newWinCredCredentialStore(vault)
+
+	// Chunks named without a generation, as written by earlier versions
+	creds := sessionCredentials(t, "abc12345", 3*maxBlobSize)
+	data, _ := creds.Serialize()
+	chunks := slices.Collect(slices.Chunk(data, maxBlobSize))
+	for i, chunk := range chunks {
+		vault.items[chunkTarget("ccx:abc12345", 0, i)] = chunk
+	}
+	vault.items["ccx:abc12345"] = []byte(manifestPrefix + strconv.Itoa(len(chunks)))
+
+	if _, err := store.Retrieve(ctx, "abc12345"); err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if err := store.Store(ctx, sessionCredentials(t, "abc12345", 3*maxBlobSize)); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	for i := range chunks {
+		if _, ok := vault.items[chunkTarget("ccx:abc12345", 0, i)]; ok {
+			t.Errorf("Unversioned chunk %d left behind after Store()", i)
+		}
+	}
+	if _, err := store.Retrieve(ctx, "abc12345"); err != nil {
+		t.Errorf("Retrieve() after Store error = %v", err)
+	}
+}
+
+func TestWinCredCredentialStore_NotFound(t *testing.T) {
+	ctx := context.Background()
+	store := newWinCredCredentialStore(newMemoryVault())
+
+	if _, err := store.Retrieve(ctx, "missing1"); !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Retrieve() error = %v, want ErrCredentialsNotFound", err)
+	}
+	if err := store.Delete(ctx, "missing1"); !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Delete() error = %v, want ErrCredentialsNotFound", err)
+	}
+}
+
+func TestWinCredCredentialStore_MissingChunk(t *testing.T) {
+	ctx := context.Background()
+	vault := newMemoryVault()
+	store := newWinCredCredentialStore(vault)
+
+	_ = store.Store(ctx, sessionCredentials(t, "abc12345", 3*maxBlobSize))
+	delete(vault.items, chunkTarget("ccx:abc12345", 1, 1))
+
+	_, err := store.Retrieve(ctx, "abc12345")
+	if err == nil || errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Retrieve() error = %v, want a corruption error rather than not found", err)
+	}
+}
+
+func TestWinCredCredentialStore_Delete(t *testing.T) {
+	ctx := context.Background()
+	vault := newMemoryVault()
+	store := newWinCredCredentialStore(vault)
+
+	_ = store.Store(ctx, sessionCredentials(t, "abc12345", 3*maxBlobSize))
+	_ = store.Store(ctx, sessionCredentials(t, "def67890", 16))
+
+	if err := store.Delete(ctx, "abc12345"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if keys := slices.Sorted(maps.Keys(vault.items)); !slices.Equal(keys, []string{"ccx:def67890"}) {
+		t.Errorf("Items after Delete() = %v, want only ccx:def67890", keys)
+	}
+}
+
+func TestWinCredCredentialStore_ListAccountIDs(t *testing.T) {
+	ctx := context.Background()
+	vault := newMemoryVault()
+	store := newWinCredCredentialStore(vault)
+
+	_ = store.Store(ctx, sessionCredentials(t, "def67890", 3*maxBlobSize))
+	_ = store.Store(ctx, sessionCredentials(t, "abc12345", 16))
+	vault.items["other:app"] = []byte("not ours")
+
+	ids, err := store.ListAccountIDs(ctx)
+	if err != nil {
+		t.Fatalf("ListAccountIDs() error = %v", err)
+	}
+	if want := []domain.AccountID{"abc12345", "def67890"}; !slices.Equal(ids, want) {
+		t.Errorf("ListAccountIDs() = %v, want %v", ids, want)
+	}
+}
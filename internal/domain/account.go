@@ -4,11 +4,17 @@ package domain
 
 import (
 	"crypto/rand"
-	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // AccountID represents a unique identifier for an account
@@ -19,22 +25,53 @@ type Email string
 
 // Account represents a Claude Code account
 type Account struct {
-	id        AccountID
-	email     Email
-	alias     string
-	uuid      string
-	createdAt time.Time
-	lastUsed  time.Time
+	id          AccountID
+	email       Email
+	alias       string
+	uuid        string
+	tags        []string
+	color       string
+	label       string
+	description string
+	env         map[string]string // Environment variable overrides applied on switch
+	rawOAuth    json.RawMessage
+	createdAt   time.Time
+	lastUsed    time.Time
+	archivedAt  time.Time // Zero unless the account is archived
 }
 
-// Email validation regex
-var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+// Email validation regexes. The local part is either a run of RFC 5322 atext
+// characters and dots, which may include Unicode letters (RFC 6531), or a quoted
+// string. The domain is checked in its ASCII form and must end in an alphabetic or
+// punycode top-level domain.
+var (
+	dotAtomLocalRegex = regexp.MustCompile("^[\\p{L}\\p{M}\\p{N}!#$%&'*+/=?^_`{|}~.-]+$")
+	quotedLocalRegex  = regexp.MustCompile(`^"([\x20\x21\x23-\x5b\x5d-\x7e]|\\[\x20-\x7e])+"$`)
+	emailDomainRegex  = regexp.MustCompile(`^[a-zA-Z0-9.-]+\.([a-zA-Z]{2,}|[xX][nN]--[a-zA-Z0-9-]+)$`)
+)
 
 // Alias validation regex (letters, numbers, hyphens, underscores)
 var aliasRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 
-// NewAccount creates a new Account with validation
+// Environment variable name validation regex (uppercase letters, digits, underscores)
+var envNameRegex = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+// Hex color validation regex (#rgb or #rrggbb)
+var hexColorRegex = regexp.MustCompile(`^#([0-9a-f]{3}|[0-9a-f]{6})$`)
+
+// AccountColors is the palette of named colors an account can be displayed in.
+// They match the basic terminal colors so any CLI can render them.
+var AccountColors = []string{"red", "green", "yellow", "blue", "magenta", "cyan", "white", "gray"}
+
+// maxLabelLength is the longest label, in characters, an account can carry
+const maxLabelLength = 64
+
+// maxDescriptionLength is the longest description, in characters, an account can carry
+const maxDescriptionLength = 500
+
+// NewAccount creates a new Account with validation. The email is stored normalized.
 func NewAccount(email, alias, uuid string) (*Account, error) {
+	email = string(NormalizeEmail(email))
 	if err := ValidateEmail(email); err != nil {
 		return nil, err
 	}
@@ -60,9 +97,12 @@ func NewAccount(email, alias, uuid string) (*Account, error) {
 	}, nil
 }
 
-// ReconstructAccount recreates an account with specific ID and timestamps.
-// Used by adapters to recreate accounts from persistence layer.
-func ReconstructAccount(id AccountID, email, alias, uuid string, createdAt, lastUsed time.Time) (*Account, error) {
+// ReconstructAccount recreates an account with specific ID, timestamps and captured
+// oauthAccount blob (nil if none). Used by adapters to recreate accounts from persistence layer.
+// The ID is not validated, so accounts saved under IDs from before ValidateAccountID
+// still load; new and user-supplied IDs are checked where they enter ccx.
+func ReconstructAccount(id AccountID, email, alias, uuid string, rawOAuth json.RawMessage, createdAt, lastUsed time.Time) (*Account, error) {
+	email = string(NormalizeEmail(email))
 	if err := ValidateEmail(email); err != nil {
 		return nil, err
 	}
@@ -82,24 +122,64 @@ func ReconstructAccount(id AccountID, email, alias, uuid string, createdAt, last
 		email:     Email(email),
 		alias:     alias,
 		uuid:      uuid,
+		rawOAuth:  slices.Clone(rawOAuth),
 		createdAt: createdAt,
 		lastUsed:  lastUsed,
 	}, nil
 }
 
-// ValidateEmail validates an email address
+// NormalizeEmail returns the canonical form of an email for storage: trimmed of
+// surrounding whitespace and lowercased, with an internationalized domain converted
+// to its ASCII punycode form, so user@例え.jp becomes user@xn--r8jz45g.jp. It does not
+// validate; a domain that cannot be encoded is left as is for ValidateEmail to reject.
+func NormalizeEmail(email string) Email {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return Email(email)
+	}
+	domain, err := toASCIIDomain(email[at+1:])
+	if err != nil {
+		return Email(email)
+	}
+	return Email(email[:at+1] + domain)
+}
+
+// ValidateEmail validates an email address. Internationalized domains are accepted
+// in Unicode or punycode form, and the local part may be quoted or contain Unicode
+// letters.
 func ValidateEmail(email string) error {
 	if email == "" {
 		return errors.New("email cannot be empty")
 	}
 
-	if !emailRegex.MatchString(email) {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return errors.New("invalid email format")
+	}
+	local, domain := email[:at], email[at+1:]
+
+	if !dotAtomLocalRegex.MatchString(local) && !quotedLocalRegex.MatchString(local) {
+		return errors.New("invalid email format")
+	}
+
+	domain, err := toASCIIDomain(domain)
+	if err != nil || !emailDomainRegex.MatchString(domain) {
 		return errors.New("invalid email format")
 	}
 
 	return nil
 }
 
+// Domain returns the lowercased part of the email after the @ sign
+func (e Email) Domain() string {
+	at := strings.LastIndex(string(e), "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(string(e)[at+1:])
+}
+
 // validateAlias validates an alias
 func validateAlias(alias string) error {
 	if strings.Contains(alias, " ") {
@@ -113,14 +193,101 @@ func validateAlias(alias string) error {
 	return nil
 }
 
-// GenerateAccountID generates a unique 8-character account ID
+// IDAlphabet selects the character set used for generated account IDs
+type IDAlphabet int
+
+const (
+	// IDAlphabetHex generates lowercase hexadecimal IDs (the default)
+	IDAlphabetHex IDAlphabet = iota
+	// IDAlphabetBase32 generates lowercase Crockford base32 IDs, which pack more
+	// entropy per character and avoid the ambiguous letters i, l, o and u
+	IDAlphabetBase32
+)
+
+const (
+	hexAlphabet    = "0123456789abcdef"
+	base32Alphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+	minIDLength = 4
+	maxIDLength = 32
+)
+
+// IDConfig controls the format of newly generated account IDs.
+// It only affects generation; IDs already stored remain valid under any config.
+type IDConfig struct {
+	Length   int        // Number of characters in a generated ID
+	Alphabet IDAlphabet // Character set used for generated IDs
+}
+
+// DefaultIDConfig returns the default ID format of 8 hex characters
+func DefaultIDConfig() IDConfig {
+	return IDConfig{Length: 8, Alphabet: IDAlphabetHex}
+}
+
+var (
+	idConfigMu sync.RWMutex
+	idConfig   = DefaultIDConfig()
+)
+
+// SetIDConfig changes the format used by GenerateAccountID
+func SetIDConfig(cfg IDConfig) error {
+	if cfg.Length < minIDLength || cfg.Length > maxIDLength {
+		return fmt.Errorf("account ID length must be between %d and %d", minIDLength, maxIDLength)
+	}
+	if cfg.Alphabet != IDAlphabetHex && cfg.Alphabet != IDAlphabetBase32 {
+		return errors.New("unknown account ID alphabet")
+	}
+
+	idConfigMu.Lock()
+	defer idConfigMu.Unlock()
+	idConfig = cfg
+	return nil
+}
+
+// CurrentIDConfig returns the format currently used by GenerateAccountID
+func CurrentIDConfig() IDConfig {
+	idConfigMu.RLock()
+	defer idConfigMu.RUnlock()
+	return idConfig
+}
+
+// GenerateAccountID generates a unique account ID using the configured format
+// (8 hex characters by default)
 func GenerateAccountID() AccountID {
-	bytes := make([]byte, 4)
+	cfg := CurrentIDConfig()
+
+	alphabet := hexAlphabet
+	if cfg.Alphabet == IDAlphabetBase32 {
+		alphabet = base32Alphabet
+	}
+
+	bytes := make([]byte, cfg.Length)
 	if _, err := rand.Read(bytes); err != nil {
 		// Fallback to timestamp-based ID if random fails
 		return AccountID(time.Now().Format("20060102"))
 	}
-	return AccountID(hex.EncodeToString(bytes))
+
+	// Alphabet sizes are powers of two, so masking keeps the distribution uniform
+	mask := byte(len(alphabet) - 1)
+	for i, b := range bytes {
+		bytes[i] = alphabet[b&mask]
+	}
+	return AccountID(bytes)
+}
+
+// ValidateAccountID checks that id has the form GenerateAccountID produces: 4 to 32
+// lowercase hex or Crockford base32 characters. IDs name credential files and are passed
+// to external commands, so anything else, such as a path separator, is rejected.
+func ValidateAccountID(id AccountID) error {
+	if len(id) < minIDLength || len(id) > maxIDLength {
+		return fmt.Errorf("%w: must be %d to %d characters", ErrInvalidAccountID, minIDLength, maxIDLength)
+	}
+	for _, r := range string(id) {
+		if !strings.ContainsRune(base32Alphabet, r) && !strings.ContainsRune(hexAlphabet, r) {
+			return fmt.Errorf("%w: %q contains %q", ErrInvalidAccountID, id, r)
+		}
+	}
+	return nil
 }
 
 // ID returns the account ID
@@ -143,6 +310,26 @@ func (a *Account) UUID() string {
 	return a.uuid
 }
 
+// RawOAuth returns a copy of the oauthAccount blob captured from Claude's config,
+// or nil if none was captured
+func (a *Account) RawOAuth() json.RawMessage {
+	return slices.Clone(a.rawOAuth)
+}
+
+// SetRawOAuth records the account's oauthAccount blob so switching back can restore
+// Claude's full account context. An empty blob clears it; invalid JSON is rejected.
+func (a *Account) SetRawOAuth(raw json.RawMessage) error {
+	if len(raw) == 0 {
+		a.rawOAuth = nil
+		return nil
+	}
+	if !json.Valid(raw) {
+		return errors.New("oauth account data must be valid JSON")
+	}
+	a.rawOAuth = slices.Clone(raw)
+	return nil
+}
+
 // CreatedAt returns when the account was created
 func (a *Account) CreatedAt() time.Time {
 	return a.createdAt
@@ -153,6 +340,156 @@ func (a *Account) LastUsed() time.Time {
 	return a.lastUsed
 }
 
+// Tags returns a sorted copy of the account's tags
+func (a *Account) Tags() []string {
+	return slices.Clone(a.tags)
+}
+
+// HasTag reports whether the account carries the given tag
+func (a *Account) HasTag(tag string) bool {
+	_, found := slices.BinarySearch(a.tags, strings.ToLower(tag))
+	return found
+}
+
+// AddTag adds a tag to the account. Tags are lowercased and follow the alias
+// character rules; adding an existing tag is a no-op.
+func (a *Account) AddTag(tag string) error {
+	if tag == "" {
+		return errors.New("tag cannot be empty")
+	}
+	if err := validateAlias(tag); err != nil {
+		return fmt.Errorf("invalid tag: %w", err)
+	}
+
+	tag = strings.ToLower(tag)
+	i, found := slices.BinarySearch(a.tags, tag)
+	if !found {
+		a.tags = slices.Insert(a.tags, i, tag)
+	}
+	return nil
+}
+
+// RemoveTag removes a tag from the account, reporting whether it was present
+func (a *Account) RemoveTag(tag string) bool {
+	i, found := slices.BinarySearch(a.tags, strings.ToLower(tag))
+	if found {
+		a.tags = slices.Delete(a.tags, i, i+1)
+	}
+	return found
+}
+
+// Color returns the account's display color: a name from AccountColors, a
+// lowercase hex code, or "" if none is set
+func (a *Account) Color() string {
+	return a.color
+}
+
+// SetColor sets the account's display color. The color must be one of
+// AccountColors or a hex code like #f80 or #ff8800; it is stored lowercased.
+// An empty color clears it.
+func (a *Account) SetColor(color string) error {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if color != "" && !slices.Contains(AccountColors, color) && !hexColorRegex.MatchString(color) {
+		return fmt.Errorf("invalid color %q: use one of %s or a hex code like #ff8800",
+			color, strings.Join(AccountColors, ", "))
+	}
+	a.color = color
+	return nil
+}
+
+// Label returns the account's free-text display label, or "" if none is set
+func (a *Account) Label() string {
+	return a.label
+}
+
+// SetLabel sets a free-text label shown next to the account, such as
+// "prod, be careful!". Surrounding whitespace is trimmed; control characters
+// and labels over 64 characters are rejected. An empty label clears it.
+func (a *Account) SetLabel(label string) error {
+	label = strings.TrimSpace(label)
+	if !utf8.ValidString(label) {
+		return errors.New("label must be valid UTF-8")
+	}
+	if strings.IndexFunc(label, unicode.IsControl) >= 0 {
+		return errors.New("label cannot contain control characters")
+	}
+	if utf8.RuneCountInString(label) > maxLabelLength {
+		return fmt.Errorf("label cannot be longer than %d characters", maxLabelLength)
+	}
+	a.label = label
+	return nil
+}
+
+// Description returns the account's free-text notes, or "" if none are set
+func (a *Account) Description() string {
+	return a.description
+}
+
+// SetDescription sets free-text notes about the account, such as "2FA via Yubikey,
+// billing owner is Jane". Unlike a label it can run to 500 characters; surrounding
+// whitespace is trimmed and control characters are rejected. An empty description
+// clears it.
+func (a *Account) SetDescription(description string) error {
+	description = strings.TrimSpace(description)
+	if !utf8.ValidString(description) {
+		return errors.New("description must be valid UTF-8")
+	}
+	if strings.IndexFunc(description, unicode.IsControl) >= 0 {
+		return errors.New("description cannot contain control characters")
+	}
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return fmt.Errorf("description cannot be longer than %d characters", maxDescriptionLength)
+	}
+	a.description = description
+	return nil
+}
+
+// EnvOverrides returns a copy of the environment variables to set when switching to
+// the account, or nil if there are none
+func (a *Account) EnvOverrides() map[string]string {
+	if len(a.env) == 0 {
+		return nil
+	}
+	return maps.Clone(a.env)
+}
+
+// SetEnvOverride sets an environment variable to export when switching to the account,
+// such as HTTPS_PROXY for an account behind a proxy. Names are uppercase letters,
+// digits, and underscores and cannot start with a digit; values cannot contain NUL.
+func (a *Account) SetEnvOverride(name, value string) error {
+	if !envNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid environment variable name %q: must match [A-Z_][A-Z0-9_]*", name)
+	}
+	if strings.ContainsRune(value, 0) {
+		return fmt.Errorf("environment variable %s cannot contain NUL", name)
+	}
+	if a.env == nil {
+		a.env = make(map[string]string)
+	}
+	a.env[name] = value
+	return nil
+}
+
+// SetEnvOverrides replaces all of the account's environment variable overrides with
+// env. Nothing changes if any entry is invalid.
+func (a *Account) SetEnvOverrides(env map[string]string) error {
+	replaced := &Account{}
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		if err := replaced.SetEnvOverride(name, env[name]); err != nil {
+			return err
+		}
+	}
+	a.env = replaced.env
+	return nil
+}
+
+// UnsetEnvOverride removes an environment variable override, reporting whether it was set
+func (a *Account) UnsetEnvOverride(name string) bool {
+	_, found := a.env[name]
+	delete(a.env, name)
+	return found
+}
+
 // UpdateAlias updates the account alias with validation
 func (a *Account) UpdateAlias(newAlias string) error {
 	if newAlias != "" {
@@ -164,6 +501,61 @@ func (a *Account) UpdateAlias(newAlias string) error {
 	return nil
 }
 
+// UpdateEmail changes the account email, for when the Claude login email changes.
+// The email is validated and stored normalized.
+func (a *Account) UpdateEmail(newEmail string) error {
+	email := NormalizeEmail(newEmail)
+	if err := ValidateEmail(string(email)); err != nil {
+		return err
+	}
+	a.email = email
+	return nil
+}
+
+// Clone creates a deep copy of the account. Accounts are mutable and not safe for
+// concurrent use, so callers that may share an account with other goroutines
+// should modify a clone and save that instead.
+func (a *Account) Clone() *Account {
+	clone := *a
+	clone.tags = slices.Clone(a.tags)
+	clone.env = maps.Clone(a.env)
+	clone.rawOAuth = slices.Clone(a.rawOAuth)
+	return &clone
+}
+
+// Rekey returns a copy of the account under a new ID, for when its ID collides with
+// another account's, such as one imported from another machine. The account itself
+// is left unchanged.
+func (a *Account) Rekey(newID AccountID) (*Account, error) {
+	if err := ValidateAccountID(newID); err != nil {
+		return nil, err
+	}
+	rekeyed := a.Clone()
+	rekeyed.id = newID
+	return rekeyed, nil
+}
+
+// Archived reports whether the account has been archived. Archived accounts keep their
+// credentials but are hidden from listings and cannot be switched to.
+func (a *Account) Archived() bool {
+	return !a.archivedAt.IsZero()
+}
+
+// ArchivedAt returns when the account was archived, or the zero time if it is not
+func (a *Account) ArchivedAt() time.Time {
+	return a.archivedAt
+}
+
+// Archive marks the account as archived at the given time. A zero time unarchives it.
+func (a *Account) Archive(at time.Time) {
+	a.archivedAt = at
+}
+
+// Unarchive restores an archived account
+func (a *Account) Unarchive() {
+	a.archivedAt = time.Time{}
+}
+
 // MarkUsed updates the last used timestamp
 func (a *Account) MarkUsed() {
 	a.lastUsed = time.Now()
@@ -1,6 +1,9 @@
 package domain_test
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -226,12 +229,25 @@ func TestEmail_Validation(t *testing.T) {
 		{"valid email with subdomain", "user@mail.example.com", false},
 		{"valid email with plus", "user+tag@example.com", false},
 		{"valid email with dots", "first.last@example.com", false},
+		{"long TLD", "curator@collection.museum", false},
+		{"new gTLD", "dev@example.photography", false},
+		{"IDN domain", "user@例え.jp", false},
+		{"IDN domain in punycode", "user@xn--r8jz45g.jp", false},
+		{"IDN TLD", "user@例え.テスト", false},
+		{"quoted local part", `"john doe"@example.com`, false},
+		{"quoted local part with at sign", `"a@b"@example.com`, false},
+		{"unicode local part", "jürgen@example.de", false},
+		{"RFC special characters", "o'brien!x#y@example.com", false},
+		{"numeric TLD", "user@example.123", true},
+		{"empty quoted local part", `""@example.com`, true},
+		{"unterminated quote", `"john@example.com`, true},
 		{"empty email", "", true},
 		{"no at sign", "userexample.com", true},
 		{"no domain", "user@", true},
 		{"no local part", "@example.com", true},
 		{"multiple at signs", "user@@example.com", true},
 		{"spaces in email", "user @example.com", true},
+		{"surrounding whitespace", " user@example.com ", true},
 	}
 
 	for _, tt := range tests {
@@ -244,6 +260,62 @@ func TestEmail_Validation(t *testing.T) {
 	}
 }
 
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		want  domain.Email
+	}{
+		{"user@example.com", "user@example.com"},
+		{"Work@Example.COM", "work@example.com"},
+		{"  user@example.com\t\n", "user@example.com"},
+		{"   ", ""},
+		{"user@例え.jp", "user@xn--r8jz45g.jp"},
+		{"User@Bücher.Example", "user@xn--bcher-kva.example"},
+		{"user@münchen.de", "user@xn--mnchen-3ya.de"},
+		{"user@例え.テスト", "user@xn--r8jz45g.xn--zckzah"},
+		{"user@xn--r8jz45g.jp", "user@xn--r8jz45g.jp"},
+		{"not-an-email", "not-an-email"},
+	}
+
+	for _, tt := range tests {
+		if got := domain.NormalizeEmail(tt.email); got != tt.want {
+			t.Errorf("NormalizeEmail(%q) = %q, want %q", tt.email, got, tt.want)
+		}
+	}
+
+	// Accounts store the normalized form
+	account, err := domain.NewAccount(" Work@Example.com ", "", "uuid-123")
+	if err != nil {
+		t.Fatalf("NewAccount() error = %v", err)
+	}
+	if account.Email() != "work@example.com" {
+		t.Errorf("NewAccount() email = %q, want work@example.com", account.Email())
+	}
+	reconstructed, err := domain.ReconstructAccount("abc12345", "USER@example.com", "", "uuid-123", nil, time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("ReconstructAccount() error = %v", err)
+	}
+	if reconstructed.Email() != "user@example.com" {
+		t.Errorf("ReconstructAccount() email = %q, want user@example.com", reconstructed.Email())
+	}
+
+	// IDN accounts are stored and found in punycode form
+	idn, err := domain.NewAccount("user@例え.jp", "", "uuid-idn")
+	if err != nil {
+		t.Fatalf("NewAccount() with IDN domain error = %v", err)
+	}
+	if idn.Email() != "user@xn--r8jz45g.jp" {
+		t.Errorf("NewAccount() email = %q, want user@xn--r8jz45g.jp", idn.Email())
+	}
+
+	// Normalization does not make invalid input valid
+	for _, invalid := range []string{"  ", " user @example.com", "USER@"} {
+		if _, err := domain.NewAccount(invalid, "", "uuid-123"); err == nil {
+			t.Errorf("NewAccount(%q) error = nil, want error", invalid)
+		}
+	}
+}
+
 func TestAccountID_Generation(t *testing.T) {
 	// Test that account IDs are unique
 	seen := make(map[domain.AccountID]bool)
@@ -265,3 +337,452 @@ func TestAccountID_Generation(t *testing.T) {
 		}
 	}
 }
+
+func TestAccountID_GenerationWithConfig(t *testing.T) {
+	defer func() { _ = domain.SetIDConfig(domain.DefaultIDConfig()) }()
+
+	// Uniqueness is only asserted where the ID space makes collisions among 100 IDs negligible
+	tests := []struct {
+		name        string
+		config      domain.IDConfig
+		alphabet    string
+		checkUnique bool
+	}{
+		{"short hex", domain.IDConfig{Length: 4, Alphabet: domain.IDAlphabetHex}, "0123456789abcdef", false},
+		{"long hex", domain.IDConfig{Length: 32, Alphabet: domain.IDAlphabetHex}, "0123456789abcdef", true},
+		{"short base32", domain.IDConfig{Length: 6, Alphabet: domain.IDAlphabetBase32}, "0123456789abcdefghjkmnpqrstvwxyz", false},
+		{"long base32", domain.IDConfig{Length: 20, Alphabet: domain.IDAlphabetBase32}, "0123456789abcdefghjkmnpqrstvwxyz", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := domain.SetIDConfig(tt.config); err != nil {
+				t.Fatalf("SetIDConfig() error = %v", err)
+			}
+
+			seen := make(map[domain.AccountID]bool)
+			for i := 0; i < 100; i++ {
+				id := domain.GenerateAccountID()
+				if len(id) != tt.config.Length {
+					t.Fatalf("GenerateAccountID() = %s, want %d characters", id, tt.config.Length)
+				}
+				for _, c := range string(id) {
+					if !strings.ContainsRune(tt.alphabet, c) {
+						t.Fatalf("GenerateAccountID() = %s, contains %q outside alphabet", id, c)
+					}
+				}
+				if tt.checkUnique && seen[id] {
+					t.Fatalf("GenerateAccountID() returned duplicate ID: %s", id)
+				}
+				seen[id] = true
+			}
+		})
+	}
+}
+
+func TestSetIDConfig_Validation(t *testing.T) {
+	defer func() { _ = domain.SetIDConfig(domain.DefaultIDConfig()) }()
+
+	tests := []struct {
+		name    string
+		config  domain.IDConfig
+		wantErr bool
+	}{
+		{"default", domain.DefaultIDConfig(), false},
+		{"too short", domain.IDConfig{Length: 3, Alphabet: domain.IDAlphabetHex}, true},
+		{"too long", domain.IDConfig{Length: 33, Alphabet: domain.IDAlphabetBase32}, true},
+		{"unknown alphabet", domain.IDConfig{Length: 8, Alphabet: domain.IDAlphabet(99)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := domain.CurrentIDConfig()
+			err := domain.SetIDConfig(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetIDConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && domain.CurrentIDConfig() != before {
+				t.Error("SetIDConfig() changed config despite error")
+			}
+		})
+	}
+}
+
+func TestAccountID_ExistingIDsSurviveConfigChange(t *testing.T) {
+	defer func() { _ = domain.SetIDConfig(domain.DefaultIDConfig()) }()
+
+	legacy := domain.GenerateAccountID()
+	if err := domain.SetIDConfig(domain.IDConfig{Length: 12, Alphabet: domain.IDAlphabetBase32}); err != nil {
+		t.Fatalf("SetIDConfig() error = %v", err)
+	}
+
+	account, err := domain.ReconstructAccount(legacy, "user@example.com", "", "uuid-123", nil, time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("ReconstructAccount() with legacy ID error = %v", err)
+	}
+	if account.ID() != legacy {
+		t.Errorf("ID() = %s, want %s", account.ID(), legacy)
+	}
+}
+
+func TestValidateAccountID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      domain.AccountID
+		wantErr bool
+	}{
+		{"hex", "abc12345", false},
+		{"base32", "0hjkmnpqrstv", false},
+		{"generated", domain.GenerateAccountID(), false},
+		{"empty", "", true},
+		{"too short", "abc", true},
+		{"too long", domain.AccountID(strings.Repeat("a", 33)), true},
+		{"uppercase", "ABC12345", true},
+		{"path traversal", "../../escaped", true},
+		{"separator", "abc/1234", true},
+		{"backslash", `abc\1234`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := domain.ValidateAccountID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAccountID(%q) error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, domain.ErrInvalidAccountID) {
+				t.Errorf("ValidateAccountID(%q) error = %v, want ErrInvalidAccountID", tt.id, err)
+			}
+		})
+	}
+
+	// Stored accounts keep loading under IDs that predate validation
+	if account, err := domain.ReconstructAccount("legacy-id", "user@example.com", "", "uuid-123", nil, time.Now(), time.Now()); err != nil || account.ID() != "legacy-id" {
+		t.Errorf("ReconstructAccount() with a legacy ID = %v, %v; want it loaded", account, err)
+	}
+}
+
+func TestAccount_Tags(t *testing.T) {
+	account, _ := domain.NewAccount("user@example.com", "", "uuid-123")
+
+	if len(account.Tags()) != 0 {
+		t.Errorf("New account should have no tags, got %v", account.Tags())
+	}
+
+	for _, tag := range []string{"work", "Client", "work"} {
+		if err := account.AddTag(tag); err != nil {
+			t.Fatalf("AddTag(%q) error = %v", tag, err)
+		}
+	}
+
+	tags := account.Tags()
+	if len(tags) != 2 || tags[0] != "client" || tags[1] != "work" {
+		t.Errorf("Tags() = %v, want [client work]", tags)
+	}
+	if !account.HasTag("CLIENT") {
+		t.Error("HasTag() should match case-insensitively")
+	}
+
+	// Returned slice is a copy
+	tags[0] = "mutated"
+	if account.Tags()[0] != "client" {
+		t.Error("Tags() should return a copy")
+	}
+
+	if !account.RemoveTag("client") {
+		t.Error("RemoveTag() should report removal of existing tag")
+	}
+	if account.RemoveTag("client") {
+		t.Error("RemoveTag() should report false for missing tag")
+	}
+
+	for _, invalid := range []string{"", "has space", "bad!"} {
+		if err := account.AddTag(invalid); err == nil {
+			t.Errorf("AddTag(%q) error = nil, want error", invalid)
+		}
+	}
+}
+
+func TestAccount_Color(t *testing.T) {
+	account, _ := domain.NewAccount("user@example.com", "", "uuid-123")
+
+	if account.Color() != "" {
+		t.Errorf("New account should have no color, got %q", account.Color())
+	}
+
+	valid := map[string]string{
+		"red":     "red",
+		" Blue ":  "blue",
+		"#F80":    "#f80",
+		"#ff8800": "#ff8800",
+		"":        "",
+	}
+	for color, want := range valid {
+		if err := account.SetColor(color); err != nil {
+			t.Errorf("SetColor(%q) error = %v", color, err)
+			continue
+		}
+		if account.Color() != want {
+			t.Errorf("SetColor(%q) stored %q, want %q", color, account.Color(), want)
+		}
+	}
+
+	_ = account.SetColor("green")
+	for _, invalid := range []string{"purple", "#ff88", "ff8800", "#gggggg"} {
+		if err := account.SetColor(invalid); err == nil {
+			t.Errorf("SetColor(%q) error = nil, want error", invalid)
+		}
+	}
+	if account.Color() != "green" {
+		t.Errorf("Invalid SetColor changed color to %q", account.Color())
+	}
+}
+
+func TestAccount_Label(t *testing.T) {
+	account, _ := domain.NewAccount("user@example.com", "", "uuid-123")
+
+	if err := account.SetLabel("  prod, be careful! 🚨 "); err != nil {
+		t.Fatalf("SetLabel() error = %v", err)
+	}
+	if account.Label() != "prod, be careful! 🚨" {
+		t.Errorf("Label() = %q, want trimmed label", account.Label())
+	}
+
+	for _, invalid := range []string{"line\nbreak", "bell\a", "esc\x1b[31m", "bad\xff", strings.Repeat("x", 65)} {
+		if err := account.SetLabel(invalid); err == nil {
+			t.Errorf("SetLabel(%q) error = nil, want error", invalid)
+		}
+	}
+	if account.Label() != "prod, be careful! 🚨" {
+		t.Errorf("Invalid SetLabel changed label to %q", account.Label())
+	}
+
+	if err := account.SetLabel(""); err != nil || account.Label() != "" {
+		t.Errorf("SetLabel(\"\") = %v, label %q; want cleared", err, account.Label())
+	}
+}
+
+func TestAccount_Description(t *testing.T) {
+	account, _ := domain.NewAccount("user@example.com", "", "uuid-123")
+
+	if account.Description() != "" {
+		t.Errorf("Description() = %q, want empty for a new account", account.Description())
+	}
+	if err := account.SetDescription("  2FA via Yubikey; billing owner is Jane (finance). "); err != nil {
+		t.Fatalf("SetDescription() error = %v", err)
+	}
+	if account.Description() != "2FA via Yubikey; billing owner is Jane (finance)." {
+		t.Errorf("Description() = %q, want trimmed description", account.Description())
+	}
+	if err := account.SetDescription(strings.Repeat("é", 500)); err != nil {
+		t.Errorf("SetDescription(500 chars) error = %v, want nil", err)
+	}
+
+	for _, invalid := range []string{"line\nbreak", "tab\there", "esc\x1b[31m", "bad\xff", strings.Repeat("x", 501)} {
+		if err := account.SetDescription(invalid); err == nil {
+			t.Errorf("SetDescription(%q) error = nil, want error", invalid)
+		}
+	}
+	if account.Description() != strings.Repeat("é", 500) {
+		t.Errorf("Invalid SetDescription changed description to %q", account.Description())
+	}
+
+	if err := account.SetDescription(""); err != nil || account.Description() != "" {
+		t.Errorf("SetDescription(\"\") = %v, description %q; want cleared", err, account.Description())
+	}
+}
+
+func TestAccount_EnvOverrides(t *testing.T) {
+	account, _ := domain.NewAccount("user@example.com", "", "uuid-123")
+
+	if account.EnvOverrides() != nil {
+		t.Errorf("EnvOverrides() = %v, want nil for a new account", account.EnvOverrides())
+	}
+	if err := account.SetEnvOverride("HTTPS_PROXY", "http://proxy:3128"); err != nil {
+		t.Fatalf("SetEnvOverride() error = %v", err)
+	}
+	if err := account.SetEnvOverride("_PRIVATE_2", "a value with spaces"); err != nil {
+		t.Fatalf("SetEnvOverride() error = %v", err)
+	}
+	for _, name := range []string{"", "lower", "2START", "HAS-DASH", "HAS SPACE"} {
+		if err := account.SetEnvOverride(name, "x"); err == nil {
+			t.Errorf("SetEnvOverride(%q) error = nil, want error", name)
+		}
+	}
+	if err := account.SetEnvOverride("NUL", "a\x00b"); err == nil {
+		t.Error("SetEnvOverride() with NUL in value error = nil, want error")
+	}
+
+	env := account.EnvOverrides()
+	if len(env) != 2 || env["HTTPS_PROXY"] != "http://proxy:3128" || env["_PRIVATE_2"] != "a value with spaces" {
+		t.Errorf("EnvOverrides() = %v, want the two valid overrides", env)
+	}
+
+	// Returned maps and clones are copies
+	env["HTTPS_PROXY"] = "changed"
+	clone := account.Clone()
+	_ = clone.SetEnvOverride("EXTRA", "1")
+	if got := account.EnvOverrides(); got["HTTPS_PROXY"] != "http://proxy:3128" || len(got) != 2 {
+		t.Errorf("EnvOverrides() = %v after changing copies, want unchanged", got)
+	}
+
+	if err := account.SetEnvOverrides(map[string]string{"GOOD": "1", "bad": "2"}); err == nil {
+		t.Error("SetEnvOverrides() with an invalid name error = nil, want error")
+	}
+	if len(account.EnvOverrides()) != 2 {
+		t.Errorf("Invalid SetEnvOverrides changed overrides to %v", account.EnvOverrides())
+	}
+	if !account.UnsetEnvOverride("HTTPS_PROXY") || account.UnsetEnvOverride("HTTPS_PROXY") {
+		t.Error("UnsetEnvOverride() should report true once, then false")
+	}
+	if err := account.SetEnvOverrides(nil); err != nil || account.EnvOverrides() != nil {
+		t.Errorf("SetEnvOverrides(nil) = %v, overrides %v; want cleared", err, account.EnvOverrides())
+	}
+}
+
+func TestAccount_RawOAuth(t *testing.T) {
+	account, _ := domain.NewAccount("user@example.com", "", "uuid-123")
+
+	if account.RawOAuth() != nil {
+		t.Errorf("New account should have no oauth blob, got %s", account.RawOAuth())
+	}
+
+	blob := json.RawMessage(`{"emailAddress":"user@example.com","organizationName":"Acme"}`)
+	if err := account.SetRawOAuth(blob); err != nil {
+		t.Fatalf("SetRawOAuth() error = %v", err)
+	}
+	if string(account.RawOAuth()) != string(blob) {
+		t.Errorf("RawOAuth() = %s, want %s", account.RawOAuth(), blob)
+	}
+
+	// Returned blob is a copy
+	raw := account.RawOAuth()
+	raw[0] = 'x'
+	if string(account.RawOAuth()) != string(blob) {
+		t.Error("RawOAuth() should return a copy")
+	}
+
+	if err := account.SetRawOAuth(json.RawMessage(`{not json`)); err == nil {
+		t.Error("SetRawOAuth() with invalid JSON error = nil, want error")
+	}
+
+	if err := account.SetRawOAuth(nil); err != nil {
+		t.Fatalf("SetRawOAuth(nil) error = %v", err)
+	}
+	if account.RawOAuth() != nil {
+		t.Error("SetRawOAuth(nil) should clear the blob")
+	}
+}
+
+func TestEmail_Domain(t *testing.T) {
+	tests := []struct {
+		email domain.Email
+		want  string
+	}{
+		{"user@example.com", "example.com"},
+		{"User@Example.COM", "example.com"},
+		{"no-at-sign", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.email), func(t *testing.T) {
+			if got := tt.email.Domain(); got != tt.want {
+				t.Errorf("Domain() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccount_UpdateEmail(t *testing.T) {
+	account, err := domain.NewAccount("user@example.com", "work", "550e8400-e29b-41d4-a716-446655440000")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	if err := account.UpdateEmail("  New.User@Example.com "); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account.Email() != "new.user@example.com" {
+		t.Errorf("Email() = %v, want new.user@example.com", account.Email())
+	}
+
+	for _, invalid := range []string{"", "not-an-email"} {
+		if err := account.UpdateEmail(invalid); err == nil {
+			t.Errorf("UpdateEmail(%q) expected error", invalid)
+		}
+	}
+	if account.Email() != "new.user@example.com" {
+		t.Errorf("Email() = %v after invalid updates, want it unchanged", account.Email())
+	}
+}
+
+func TestAccount_Clone(t *testing.T) {
+	original, err := domain.NewAccount("user@example.com", "work", "550e8400-e29b-41d4-a716-446655440000")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	_ = original.AddTag("client")
+	_ = original.SetRawOAuth(json.RawMessage(`{"emailAddress":"user@example.com"}`))
+
+	lastUsed := original.LastUsed()
+	cloned := original.Clone()
+	if cloned.ID() != original.ID() || cloned.Email() != original.Email() || cloned.Alias() != original.Alias() {
+		t.Errorf("cloned = %s/%s/%s, want %s/%s/%s",
+			cloned.ID(), cloned.Email(), cloned.Alias(), original.ID(), original.Email(), original.Alias())
+	}
+
+	// Modifying the clone doesn't affect the original
+	cloned.MarkUsed()
+	_ = cloned.UpdateAlias("personal")
+	_ = cloned.AddTag("another")
+	if !original.LastUsed().Equal(lastUsed) {
+		t.Error("MarkUsed on clone changed original LastUsed")
+	}
+	if original.Alias() != "work" {
+		t.Errorf("original Alias() = %s, want work", original.Alias())
+	}
+	if original.HasTag("another") || len(original.Tags()) != 1 {
+		t.Errorf("original Tags() = %v, want [client]", original.Tags())
+	}
+}
+
+func TestAccount_Rekey(t *testing.T) {
+	original, _ := domain.NewAccount("user@example.com", "work", "550e8400-e29b-41d4-a716-446655440000")
+	_ = original.AddTag("client")
+
+	rekeyed, err := original.Rekey("new0d123")
+	if err != nil {
+		t.Fatalf("Rekey() error = %v", err)
+	}
+	if rekeyed.ID() != "new0d123" {
+		t.Errorf("rekeyed ID() = %s, want new0d123", rekeyed.ID())
+	}
+	if rekeyed.Email() != original.Email() || rekeyed.Alias() != original.Alias() || !rekeyed.HasTag("client") {
+		t.Errorf("rekeyed = %s/%s/%v, want the original's fields", rekeyed.Email(), rekeyed.Alias(), rekeyed.Tags())
+	}
+	if original.ID() == "new0d123" {
+		t.Error("Rekey() changed the original's ID")
+	}
+
+	if _, err := original.Rekey(""); err == nil {
+		t.Error("Rekey(\"\") error = nil, want error")
+	}
+}
+
+func TestAccount_Archive(t *testing.T) {
+	account, _ := domain.NewAccount("test@example.com", "test", "uuid-test")
+	if account.Archived() || !account.ArchivedAt().IsZero() {
+		t.Fatal("New account should not be archived")
+	}
+
+	at := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	account.Archive(at)
+	clone := account.Clone()
+	if !account.Archived() || !account.ArchivedAt().Equal(at) || !clone.Archived() {
+		t.Errorf("ArchivedAt() = %v, want %v on the account and its clone", account.ArchivedAt(), at)
+	}
+
+	account.Unarchive()
+	if account.Archived() || !clone.Archived() {
+		t.Error("Unarchive() should restore the account without touching its clone")
+	}
+}
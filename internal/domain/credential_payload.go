@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrUnknownCredentialFormat is returned when a decrypted credential payload matches none
+// of the session shapes written by known Claude versions
+var ErrUnknownCredentialFormat = errors.New("unknown credential format")
+
+// CredentialPayload is a decrypted credential payload read in whichever shape the Claude
+// version that wrote it used. Storage never needs it, since credentials stay opaque
+// bytes; it is for features that look inside the session, such as expiry checks.
+type CredentialPayload struct {
+	sessionKey  string
+	expiresAt   time.Time // Zero when the payload carries no expiry
+	accountUUID string
+}
+
+// flatPayload is the session shape of older Claude versions, with the session fields at
+// the top level next to the OAuth account
+type flatPayload struct {
+	SessionKey          *string         `json:"sessionKey"`
+	SessionKeySnake     *string         `json:"session_key"`
+	SessionKeyExpiresAt json.RawMessage `json:"sessionKeyExpiresAt"`
+	AccountUUID         string          `json:"accountUuid"`
+	OAuthAccount        *struct {
+		AccountUUID string `json:"accountUuid"`
+	} `json:"oauthAccount"`
+}
+
+// oauthPayload is the session shape of newer Claude versions, nested under claudeAiOauth
+type oauthPayload struct {
+	ClaudeAiOauth *struct {
+		AccessToken string          `json:"accessToken"`
+		ExpiresAt   json.RawMessage `json:"expiresAt"`
+		AccountUUID string          `json:"accountUuid"`
+	} `json:"claudeAiOauth"`
+}
+
+// ParseCredentialPayload reads a decrypted credential payload. It understands the flat
+// shape (sessionKey or session_key, with sessionKeyExpiresAt) and the nested
+// claudeAiOauth shape (accessToken, with expiresAt). Anything else, including a payload
+// whose session key is missing or blank, returns ErrUnknownCredentialFormat. Errors
+// never quote the payload.
+func ParseCredentialPayload(data []byte) (*CredentialPayload, error) {
+	var nested oauthPayload
+	if err := json.Unmarshal(data, &nested); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnknownCredentialFormat, RedactError(err))
+	}
+	if oauth := nested.ClaudeAiOauth; oauth != nil {
+		if strings.TrimSpace(oauth.AccessToken) == "" {
+			return nil, fmt.Errorf("%w: claudeAiOauth has no accessToken", ErrUnknownCredentialFormat)
+		}
+		payload := &CredentialPayload{sessionKey: oauth.AccessToken, accountUUID: oauth.AccountUUID}
+		if len(oauth.ExpiresAt) > 0 {
+			payload.expiresAt, _ = parseExpiry(oauth.ExpiresAt)
+		}
+		return payload, nil
+	}
+
+	var flat flatPayload
+	if err := json.Unmarshal(data, &flat); err != nil {
+		// Fields of the known shape hold values of the wrong type
+		return nil, fmt.Errorf("%w: %w", ErrUnknownCredentialFormat, RedactError(err))
+	}
+	sessionKey := flat.SessionKey
+	if sessionKey == nil {
+		sessionKey = flat.SessionKeySnake
+	}
+	if sessionKey == nil || strings.TrimSpace(*sessionKey) == "" {
+		return nil, fmt.Errorf("%w: no session key", ErrUnknownCredentialFormat)
+	}
+
+	payload := &CredentialPayload{sessionKey: *sessionKey, accountUUID: flat.AccountUUID}
+	if payload.accountUUID == "" && flat.OAuthAccount != nil {
+		payload.accountUUID = flat.OAuthAccount.AccountUUID
+	}
+	if len(flat.SessionKeyExpiresAt) > 0 {
+		payload.expiresAt, _ = parseExpiry(flat.SessionKeyExpiresAt)
+	}
+	return payload, nil
+}
+
+// SessionKey returns the session key or OAuth access token
+func (p *CredentialPayload) SessionKey() string {
+	return p.sessionKey
+}
+
+// ExpiresAt returns when the session expires. The bool is false when the payload
+// carries no expiry.
+func (p *CredentialPayload) ExpiresAt() (time.Time, bool) {
+	return p.expiresAt, !p.expiresAt.IsZero()
+}
+
+// AccountUUID returns the Claude account UUID recorded in the payload, or an empty
+// string if it has none
+func (p *CredentialPayload) AccountUUID() string {
+	return p.accountUUID
+}
@@ -0,0 +1,102 @@
+package domain_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestParseCredentialPayload(t *testing.T) {
+	expiry := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	millis := strconv.FormatInt(expiry.UnixMilli(), 10)
+
+	tests := []struct {
+		name       string
+		data       string
+		wantKey    string
+		wantExpiry time.Time
+		wantUUID   string
+	}{
+		{
+			name:       "flat with expiry and OAuth account",
+			data:       `{"sessionKey":"sk-1","sessionKeyExpiresAt":` + millis + `,"oauthAccount":{"accountUuid":"uuid-1"}}`,
+			wantKey:    "sk-1",
+			wantExpiry: expiry,
+			wantUUID:   "uuid-1",
+		},
+		{
+			name:     "flat snake case with top-level UUID",
+			data:     `{"session_key":"sk-2","accountUuid":"uuid-2"}`,
+			wantKey:  "sk-2",
+			wantUUID: "uuid-2",
+		},
+		{
+			name:       "nested claudeAiOauth",
+			data:       `{"claudeAiOauth":{"accessToken":"sk-ant-oat","refreshToken":"r","expiresAt":` + millis + `,"scopes":["user:inference"]}}`,
+			wantKey:    "sk-ant-oat",
+			wantExpiry: expiry,
+		},
+		{
+			name:    "nested without expiry",
+			data:    `{"claudeAiOauth":{"accessToken":"sk-ant-oat","expiresAt":0}}`,
+			wantKey: "sk-ant-oat",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := domain.ParseCredentialPayload([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseCredentialPayload() error = %v", err)
+			}
+			if payload.SessionKey() != tt.wantKey {
+				t.Errorf("SessionKey() = %q, want %q", payload.SessionKey(), tt.wantKey)
+			}
+			expiresAt, ok := payload.ExpiresAt()
+			if ok != !tt.wantExpiry.IsZero() || !expiresAt.Equal(tt.wantExpiry) {
+				t.Errorf("ExpiresAt() = %v, %v; want %v", expiresAt, ok, tt.wantExpiry)
+			}
+			if payload.AccountUUID() != tt.wantUUID {
+				t.Errorf("AccountUUID() = %q, want %q", payload.AccountUUID(), tt.wantUUID)
+			}
+		})
+	}
+}
+
+func TestParseCredentialPayload_UnknownFormat(t *testing.T) {
+	for _, data := range []string{
+		`sk-ant-secret`,
+		`["sk-ant-secret"]`,
+		`{"account_id":"uuid-1"}`,
+		`{"sessionKey":"  "}`,
+		`{"sessionKey":123}`,
+		`{"claudeAiOauth":{"refreshToken":"sk-ant-secret"}}`,
+		`{"claudeAiOauth":"sk-ant-secret"}`,
+	} {
+		_, err := domain.ParseCredentialPayload([]byte(data))
+		if !errors.Is(err, domain.ErrUnknownCredentialFormat) {
+			t.Errorf("ParseCredentialPayload(%s) error = %v, want ErrUnknownCredentialFormat", data, err)
+		}
+		if err != nil && strings.Contains(err.Error(), "sk-ant-secret") {
+			t.Errorf("ParseCredentialPayload(%s) error %q leaks the payload", data, err)
+		}
+	}
+}
+
+func TestCredentials_ExpiresAtNestedFormat(t *testing.T) {
+	expiry := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	data := `{"claudeAiOauth":{"accessToken":"sk-ant-oat","expiresAt":` + strconv.FormatInt(expiry.UnixMilli(), 10) + `}}`
+	creds, err := domain.NewCredentials("abc12345", []byte(data))
+	if err != nil {
+		t.Fatalf("NewCredentials() error = %v", err)
+	}
+
+	got, ok := creds.ExpiresAt()
+	if !ok || !got.Equal(expiry) {
+		t.Errorf("ExpiresAt() = %v, %v; want %v", got, ok, expiry)
+	}
+}
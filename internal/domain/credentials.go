@@ -3,34 +3,162 @@ package domain
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"io/fs"
+	"time"
 )
 
+// KDF parameters for passphrase-protected credentials
+const (
+	kdfPBKDF2SHA256  = "pbkdf2-sha256"
+	pbkdf2Iterations = 600_000
+	saltSize         = 16
+
+	// maxKDFIterations bounds the iteration count read from disk, so a crafted file
+	// can't make deriving its key hang
+	maxKDFIterations = 10_000_000
+)
+
+// Envelope encryption parameters. Each credential's payload is encrypted with its own
+// random data key, and the data key is stored wrapped (AES-GCM encrypted) by the
+// master key.
+const (
+	keyWrapAESGCM = "aes-gcm"
+	// MasterKeySize is the size in bytes of a master key (AES-256)
+	MasterKeySize = 32
+	dataKeySize   = 32
+)
+
+// ErrMasterKeyRequired is returned when envelope-encrypted credentials are used before
+// their data key has been unwrapped with the master key
+var ErrMasterKeyRequired = errors.New("credentials are envelope-encrypted and need the master key")
+
+// ErrPassphraseRequired is returned when passphrase-protected credentials are
+// decrypted without a passphrase
+var ErrPassphraseRequired = errors.New("credentials are passphrase-protected")
+
+// credentialsVersion is the serialization version written by Serialize. Version 1 adds
+// a MAC; files without a version are legacy and carry none.
+const credentialsVersion = 1
+
+// ErrCredentialsTampered is returned when serialized credentials fail their integrity
+// check, meaning the encrypted data or account ID was edited, truncated, or swapped in
+// from another file
+var ErrCredentialsTampered = errors.New("credentials file has been tampered with")
+
+// ErrInvalidCredentialData is returned when a credential payload has no usable session key
+var ErrInvalidCredentialData = errors.New("invalid credential data")
+
+// RedactError hides the message of err, which may quote credential bytes, behind a fixed
+// description of its kind, such as "invalid JSON". The original error stays reachable
+// with errors.Is and errors.As, so callers can still branch on it without it ever
+// reaching a log. Errors about credentials should
+// name the account ID or alias, never the payload; wrap anything that saw the payload
+// with RedactError before returning it.
+func RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &redactedError{err: err}
+}
+
+// redactedError is returned by RedactError
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return redactedKind(e.err) + " (details redacted)"
+}
+
+// redactedKind describes what kind of error err is without quoting any of it
+func redactedKind(err error) string {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var pathErr *fs.PathError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return "invalid JSON"
+	case errors.As(err, &typeErr):
+		return "unexpected JSON value"
+	case errors.As(err, &pathErr):
+		return "file error"
+	default:
+		return "error"
+	}
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
 // Credentials represents encrypted account credentials
 type Credentials struct {
 	accountID     AccountID
 	encryptedData []byte
 	encryptionKey []byte
+	salt          []byte // Non-empty only for passphrase-protected credentials
+	kdfIterations int
+	wrappedKey    []byte // Data key wrapped by the master key; non-empty only for envelope encryption
+	mac           []byte // MAC read from storage, used when the key is unavailable; nil for legacy files
 }
 
 // credentialsJSON is used for serialization
 type credentialsJSON struct {
 	AccountID     string `json:"accountId"`
 	EncryptedData string `json:"encryptedData"`
+	KDF           string `json:"kdf,omitempty"`
+	KDFIterations int    `json:"kdfIterations,omitempty"`
+	Salt          string `json:"salt,omitempty"`
+	KeyWrap       string `json:"keyWrap,omitempty"`
+	WrappedKey    string `json:"wrappedKey,omitempty"`
+	Version       int    `json:"version,omitempty"`
+	MAC           string `json:"mac,omitempty"`
 }
 
-// deriveKey derives an encryption key from the account ID
-// In production, this should use a proper KDF and possibly a master key
+// deriveKey derives an encryption key from the account ID. Anyone who knows the
+// scheme can derive it, so it is only kept for credentials written before envelope
+// encryption and for callers without a master key.
 func deriveKey(accountID AccountID) []byte {
 	hash := sha256.Sum256([]byte("ccx-encryption-" + string(accountID)))
 	return hash[:]
 }
 
+// computeMAC returns an HMAC-SHA256 over the account ID and encrypted data, keyed by a
+// subkey of the encryption key so the key is not used directly by two algorithms. The
+// ID is length-prefixed so no two ID and data pairs share an input.
+func computeMAC(key []byte, accountID AccountID, encryptedData []byte) []byte {
+	subkey := hmac.New(sha256.New, key)
+	subkey.Write([]byte("ccx-credentials-mac"))
+
+	mac := hmac.New(sha256.New, subkey.Sum(nil))
+	_ = binary.Write(mac, binary.BigEndian, uint32(len(accountID))) // #nosec G115 - IDs are short
+	mac.Write([]byte(accountID))
+	mac.Write(encryptedData)
+	return mac.Sum(nil)
+}
+
+// verifyMAC checks the MAC read from storage against key. Legacy credentials without a
+// MAC always pass.
+func (c *Credentials) verifyMAC(key []byte) error {
+	if c.mac == nil {
+		return nil
+	}
+	if !hmac.Equal(c.mac, computeMAC(key, c.accountID, c.encryptedData)) {
+		return fmt.Errorf("%w: integrity check failed for account %s", ErrCredentialsTampered, c.accountID)
+	}
+	return nil
+}
+
 // NewCredentials creates new encrypted credentials
 func NewCredentials(accountID AccountID, data []byte) (*Credentials, error) {
 	if accountID == "" {
@@ -54,11 +182,129 @@ func NewCredentials(accountID AccountID, data []byte) (*Credentials, error) {
 	}, nil
 }
 
+// GenerateMasterKey returns a new random master key for NewCredentialsEnvelope
+func GenerateMasterKey() ([]byte, error) {
+	key := make([]byte, MasterKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// NewCredentialsEnvelope creates credentials encrypted with a random per-credential data
+// key, which is itself wrapped by masterKey. Only the wrapped data key is serialized, so
+// stored credentials can be decrypted only by holders of the master key, and rotating
+// the master key only requires Rewrap, not re-encrypting the payload.
+func NewCredentialsEnvelope(accountID AccountID, data, masterKey []byte) (*Credentials, error) {
+	if accountID == "" {
+		return nil, errors.New("account ID cannot be empty")
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("credentials data cannot be empty")
+	}
+
+	if len(masterKey) != MasterKeySize {
+		return nil, fmt.Errorf("master key must be %d bytes", MasterKeySize)
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+
+	encryptedData, err := encrypt(data, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedKey, err := encrypt(dataKey, masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Credentials{
+		accountID:     accountID,
+		encryptedData: encryptedData,
+		encryptionKey: dataKey,
+		wrappedKey:    wrappedKey,
+	}, nil
+}
+
+// ValidateCredentialData checks that data is a session payload ParseCredentialPayload
+// understands, so that a valid payload has one definition. Errors wrap
+// ErrInvalidCredentialData and never quote data.
+func ValidateCredentialData(data []byte) error {
+	if _, err := ParseCredentialPayload(data); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCredentialData, err)
+	}
+	return nil
+}
+
+// NewCredentialsWithPassphrase creates credentials encrypted under a key derived from
+// the passphrase and account ID with PBKDF2 and a random per-credential salt
+func NewCredentialsWithPassphrase(accountID AccountID, data, passphrase []byte) (*Credentials, error) {
+	if accountID == "" {
+		return nil, errors.New("account ID cannot be empty")
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("credentials data cannot be empty")
+	}
+
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase cannot be empty")
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+
+	key, err := derivePassphraseKey(accountID, passphrase, salt, pbkdf2Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedData, err := encrypt(data, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Credentials{
+		accountID:     accountID,
+		encryptedData: encryptedData,
+		encryptionKey: key,
+		salt:          salt,
+		kdfIterations: pbkdf2Iterations,
+	}, nil
+}
+
+// derivePassphraseKey derives an AES-256 key from the passphrase, salted with the
+// random salt and the account ID
+func derivePassphraseKey(accountID AccountID, passphrase, salt []byte, iterations int) ([]byte, error) {
+	accountSalt := make([]byte, 0, len(salt)+len(accountID))
+	accountSalt = append(accountSalt, salt...)
+	accountSalt = append(accountSalt, accountID...)
+	return pbkdf2.Key(sha256.New, string(passphrase), accountSalt, iterations, 32)
+}
+
 // AccountID returns the account ID associated with these credentials
 func (c *Credentials) AccountID() AccountID {
 	return c.accountID
 }
 
+// String describes the credentials by account ID only, so formatting them with %v or
+// %+v never prints key material or ciphertext
+func (c *Credentials) String() string {
+	return fmt.Sprintf("Credentials{account: %s}", c.accountID)
+}
+
+// GoString is like String for the %#v verb
+func (c *Credentials) GoString() string {
+	return c.String()
+}
+
 // EncryptedData returns the encrypted credential data
 func (c *Credentials) EncryptedData() []byte {
 	// Return a copy to prevent external modification
@@ -67,17 +313,181 @@ func (c *Credentials) EncryptedData() []byte {
 	return data
 }
 
-// Decrypt decrypts and returns the credential data
+// Decrypt decrypts and returns the credential data.
+// Returns ErrPassphraseRequired for passphrase-protected credentials loaded from storage,
+// and ErrMasterKeyRequired for envelope-encrypted credentials not yet unwrapped.
 func (c *Credentials) Decrypt() ([]byte, error) {
+	if c.encryptionKey == nil {
+		return nil, c.missingKeyError()
+	}
 	return decrypt(c.encryptedData, c.encryptionKey)
 }
 
+// missingKeyError explains why the encryption key is not available
+func (c *Credentials) missingKeyError() error {
+	if c.IsEnvelopeEncrypted() {
+		return ErrMasterKeyRequired
+	}
+	return ErrPassphraseRequired
+}
+
+// DecryptWithPassphrase decrypts passphrase-protected credentials.
+// Legacy credentials without a salt fall back to the account-ID-derived key, and
+// envelope-encrypted credentials must already be unwrapped.
+func (c *Credentials) DecryptWithPassphrase(passphrase []byte) ([]byte, error) {
+	if c.IsEnvelopeEncrypted() {
+		return c.Decrypt()
+	}
+	if !c.IsPassphraseProtected() {
+		return decrypt(c.encryptedData, deriveKey(c.accountID))
+	}
+
+	key, err := derivePassphraseKey(c.accountID, passphrase, c.salt, c.kdfIterations)
+	if err != nil {
+		return nil, err
+	}
+
+	// A wrong passphrase fails the MAC just like tampering does, so the two stay
+	// reported together
+	if err := c.verifyMAC(key); err != nil {
+		return nil, errors.New("invalid passphrase or corrupted credentials")
+	}
+	plaintext, err := decrypt(c.encryptedData, key)
+	if err != nil {
+		return nil, errors.New("invalid passphrase or corrupted credentials")
+	}
+	return plaintext, nil
+}
+
+// IsPassphraseProtected reports whether the credentials use a passphrase-derived key
+func (c *Credentials) IsPassphraseProtected() bool {
+	return len(c.salt) > 0
+}
+
+// IsEnvelopeEncrypted reports whether the credentials use a data key wrapped by a master key
+func (c *Credentials) IsEnvelopeEncrypted() bool {
+	return len(c.wrappedKey) > 0
+}
+
+// CredentialScheme names how the key protecting credentials is obtained
+type CredentialScheme string
+
+// Credential schemes, from the legacy default to the ones that keep the key off disk
+const (
+	SchemeAccountKey CredentialScheme = "account-key" // Derived from the account ID; anyone can derive it
+	SchemeEnvelope   CredentialScheme = "envelope"    // Random data key wrapped by a master key
+	SchemePassphrase CredentialScheme = "passphrase"  // Derived from a user passphrase
+)
+
+// Scheme reports which scheme protects the credentials. It is read from the stored
+// fields, so it is known without the key.
+func (c *Credentials) Scheme() CredentialScheme {
+	switch {
+	case c.IsEnvelopeEncrypted():
+		return SchemeEnvelope
+	case c.IsPassphraseProtected():
+		return SchemePassphrase
+	default:
+		return SchemeAccountKey
+	}
+}
+
+// Unwrap recovers the data key of envelope-encrypted credentials loaded from storage,
+// so they can be decrypted. It fails if masterKey is not the key they were wrapped with,
+// and returns ErrCredentialsTampered if the payload fails its integrity check.
+func (c *Credentials) Unwrap(masterKey []byte) error {
+	if !c.IsEnvelopeEncrypted() {
+		return errors.New("credentials are not envelope-encrypted")
+	}
+	if len(masterKey) != MasterKeySize {
+		return fmt.Errorf("master key must be %d bytes", MasterKeySize)
+	}
+
+	dataKey, err := decrypt(c.wrappedKey, masterKey)
+	if err != nil {
+		return errors.New("invalid master key or corrupted credentials")
+	}
+	// The master key is known to be right now, so a failed MAC means tampering
+	if err := c.verifyMAC(dataKey); err != nil {
+		return err
+	}
+	c.encryptionKey = dataKey
+	return nil
+}
+
+// Rewrap wraps the data key of envelope-encrypted credentials with a new master key,
+// leaving the encrypted payload untouched. The credentials must have been created or
+// unwrapped in this process, so the data key is available.
+func (c *Credentials) Rewrap(newMasterKey []byte) error {
+	if !c.IsEnvelopeEncrypted() {
+		return errors.New("credentials are not envelope-encrypted")
+	}
+	if c.encryptionKey == nil {
+		return ErrMasterKeyRequired
+	}
+	if len(newMasterKey) != MasterKeySize {
+		return fmt.Errorf("master key must be %d bytes", MasterKeySize)
+	}
+
+	wrappedKey, err := encrypt(c.encryptionKey, newMasterKey)
+	if err != nil {
+		return err
+	}
+	c.wrappedKey = wrappedKey
+	return nil
+}
+
+// ExpiresAt returns when the session key expires, read from the decrypted payload by
+// ParseCredentialPayload. Numeric values are Unix seconds, or milliseconds when too
+// large to be seconds; RFC3339 strings are also accepted. The bool is false when the
+// payload can't be read or carries no expiry (missing or 0).
+func (c *Credentials) ExpiresAt() (time.Time, bool) {
+	data, err := c.Decrypt()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	payload, err := ParseCredentialPayload(data)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return payload.ExpiresAt()
+}
+
+// parseExpiry interprets an expiry value as a Unix timestamp or RFC3339 string
+func parseExpiry(raw json.RawMessage) (time.Time, bool) {
+	var epoch int64
+	if err := json.Unmarshal(raw, &epoch); err == nil {
+		if epoch <= 0 {
+			return time.Time{}, false
+		}
+		// Seconds past year 2286 are implausible, so treat them as milliseconds
+		if epoch > 1e10 {
+			return time.UnixMilli(epoch).UTC(), true
+		}
+		return time.Unix(epoch, 0).UTC(), true
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		if expiresAt, err := time.Parse(time.RFC3339, text); err == nil {
+			return expiresAt.UTC(), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
 // UpdateData updates the encrypted credentials with new data
 func (c *Credentials) UpdateData(newData []byte) error {
 	if len(newData) == 0 {
 		return errors.New("credentials data cannot be empty")
 	}
 
+	if c.encryptionKey == nil {
+		return c.missingKeyError()
+	}
+
 	encryptedData, err := encrypt(newData, c.encryptionKey)
 	if err != nil {
 		return err
@@ -92,26 +502,103 @@ func (c *Credentials) Clone() *Credentials {
 	encryptedData := make([]byte, len(c.encryptedData))
 	copy(encryptedData, c.encryptedData)
 
-	key := make([]byte, len(c.encryptionKey))
-	copy(key, c.encryptionKey)
+	var key []byte
+	if c.encryptionKey != nil {
+		key = make([]byte, len(c.encryptionKey))
+		copy(key, c.encryptionKey)
+	}
+
+	var salt []byte
+	if c.salt != nil {
+		salt = make([]byte, len(c.salt))
+		copy(salt, c.salt)
+	}
+
+	var wrappedKey []byte
+	if c.wrappedKey != nil {
+		wrappedKey = make([]byte, len(c.wrappedKey))
+		copy(wrappedKey, c.wrappedKey)
+	}
+
+	var mac []byte
+	if c.mac != nil {
+		mac = make([]byte, len(c.mac))
+		copy(mac, c.mac)
+	}
 
 	return &Credentials{
 		accountID:     c.accountID,
 		encryptedData: encryptedData,
 		encryptionKey: key,
+		salt:          salt,
+		kdfIterations: c.kdfIterations,
+		wrappedKey:    wrappedKey,
+		mac:           mac,
 	}
 }
 
-// Serialize converts credentials to JSON for storage
+// Rekey returns a copy of the credentials bound to a new account ID, leaving these
+// unchanged. The ID is covered by the MAC under every scheme, and keys the encryption
+// of credentials without a passphrase or master key, so those are re-encrypted;
+// envelope-encrypted credentials keep their data key and must be unwrapped first.
+// Passphrase-protected credentials can't be re-keyed without the passphrase and
+// return ErrPassphraseRequired.
+func (c *Credentials) Rekey(newID AccountID) (*Credentials, error) {
+	if newID == "" {
+		return nil, errors.New("account ID cannot be empty")
+	}
+	if c.IsPassphraseProtected() {
+		return nil, ErrPassphraseRequired
+	}
+	if c.encryptionKey == nil {
+		return nil, c.missingKeyError()
+	}
+
+	if c.IsEnvelopeEncrypted() {
+		rekeyed := c.Clone()
+		rekeyed.accountID = newID
+		rekeyed.mac = nil // Recomputed from the data key on Serialize
+		return rekeyed, nil
+	}
+
+	data, err := c.Decrypt()
+	if err != nil {
+		return nil, err
+	}
+	return NewCredentials(newID, data)
+}
+
+// Serialize converts credentials to JSON for storage, with a MAC over the account ID
+// and encrypted data. Credentials loaded without their key, such as passphrase-protected
+// ones, keep the MAC they were stored with, and legacy ones without a MAC stay without.
 func (c *Credentials) Serialize() ([]byte, error) {
 	data := credentialsJSON{
 		AccountID:     string(c.accountID),
 		EncryptedData: base64.StdEncoding.EncodeToString(c.encryptedData),
 	}
+	mac := c.mac
+	if c.encryptionKey != nil {
+		mac = computeMAC(c.encryptionKey, c.accountID, c.encryptedData)
+	}
+	if mac != nil {
+		data.Version = credentialsVersion
+		data.MAC = base64.StdEncoding.EncodeToString(mac)
+	}
+	if c.IsPassphraseProtected() {
+		data.KDF = kdfPBKDF2SHA256
+		data.KDFIterations = c.kdfIterations
+		data.Salt = base64.StdEncoding.EncodeToString(c.salt)
+	}
+	if c.IsEnvelopeEncrypted() {
+		data.KeyWrap = keyWrapAESGCM
+		data.WrappedKey = base64.StdEncoding.EncodeToString(c.wrappedKey)
+	}
 	return json.Marshal(data)
 }
 
-// DeserializeCredentials recreates credentials from JSON
+// DeserializeCredentials recreates credentials from JSON. Credentials keyed by the account
+// ID have their MAC checked here and return ErrCredentialsTampered if it fails; the
+// others are checked once their key is supplied.
 func DeserializeCredentials(data []byte) (*Credentials, error) {
 	if len(data) == 0 {
 		return nil, errors.New("empty serialization data")
@@ -135,11 +622,77 @@ func DeserializeCredentials(data []byte) (*Credentials, error) {
 		return nil, err
 	}
 
+	if jsonData.Version > credentialsVersion {
+		return nil, fmt.Errorf("unsupported credentials version %d", jsonData.Version)
+	}
+	// Files written before the MAC was added have no version and no MAC, and skip the
+	// check. A versioned file without one has had it stripped.
+	if jsonData.Version > 0 && jsonData.MAC == "" {
+		return nil, fmt.Errorf("%w: MAC missing from version %d credentials", ErrCredentialsTampered, jsonData.Version)
+	}
+	var mac []byte
+	if jsonData.MAC != "" {
+		mac, err = base64.StdEncoding.DecodeString(jsonData.MAC)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid MAC encoding", ErrCredentialsTampered)
+		}
+	}
+
 	accountID := AccountID(jsonData.AccountID)
+
+	// The data key can only be unwrapped once the master key is supplied
+	if jsonData.WrappedKey != "" {
+		if jsonData.KeyWrap != keyWrapAESGCM {
+			return nil, errors.New("unsupported key wrap algorithm: " + jsonData.KeyWrap)
+		}
+		wrappedKey, err := base64.StdEncoding.DecodeString(jsonData.WrappedKey)
+		if err != nil {
+			return nil, err
+		}
+		return &Credentials{
+			accountID:     accountID,
+			encryptedData: encryptedData,
+			wrappedKey:    wrappedKey,
+			mac:           mac,
+		}, nil
+	}
+
+	// Without a salt these are legacy credentials keyed by the account ID, so the MAC
+	// can be checked before anything is decrypted
+	if jsonData.Salt == "" {
+		creds := &Credentials{
+			accountID:     accountID,
+			encryptedData: encryptedData,
+			mac:           mac,
+		}
+		key := deriveKey(accountID)
+		if err := creds.verifyMAC(key); err != nil {
+			return nil, err
+		}
+		creds.encryptionKey = key
+		return creds, nil
+	}
+
+	if jsonData.KDF != kdfPBKDF2SHA256 {
+		return nil, errors.New("unsupported key derivation function: " + jsonData.KDF)
+	}
+
+	if jsonData.KDFIterations <= 0 || jsonData.KDFIterations > maxKDFIterations {
+		return nil, errors.New("invalid key derivation iterations in serialized data")
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(jsonData.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key can only be derived once the passphrase is supplied
 	return &Credentials{
 		accountID:     accountID,
 		encryptedData: encryptedData,
-		encryptionKey: deriveKey(accountID),
+		salt:          salt,
+		kdfIterations: jsonData.KDFIterations,
+		mac:           mac,
 	}, nil
 }
 
@@ -2,7 +2,14 @@ package domain_test
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/evanschultz/ccx/internal/domain"
 )
@@ -253,6 +260,109 @@ func TestCredentials_Serialization(t *testing.T) {
 	}
 }
 
+// tamper decodes serialized credentials, applies edit to the JSON fields, and re-encodes them
+func tamper(t *testing.T, serialized []byte, edit func(fields map[string]any)) []byte {
+	t.Helper()
+	var fields map[string]any
+	if err := json.Unmarshal(serialized, &fields); err != nil {
+		t.Fatalf("failed to decode serialized credentials: %v", err)
+	}
+	edit(fields)
+	data, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatalf("failed to encode serialized credentials: %v", err)
+	}
+	return data
+}
+
+// flipBit flips one bit in the middle of the base64 encrypted data
+func flipBit(fields map[string]any) {
+	encoded, _ := fields["encryptedData"].(string)
+	data, _ := base64.StdEncoding.DecodeString(encoded)
+	data[len(data)/2] ^= 0x01
+	fields["encryptedData"] = base64.StdEncoding.EncodeToString(data)
+}
+
+func TestCredentials_Tampering(t *testing.T) {
+	creds, _ := domain.NewCredentials("abc12345", []byte(`{"sessionKey":"k"}`))
+	serialized, _ := creds.Serialize()
+
+	tests := []struct {
+		name string
+		edit func(fields map[string]any)
+	}{
+		{"bit flipped in encrypted data", flipBit},
+		{"truncated encrypted data", func(fields map[string]any) {
+			encoded, _ := fields["encryptedData"].(string)
+			fields["encryptedData"] = encoded[:len(encoded)/2]
+		}},
+		{"swapped account ID", func(fields map[string]any) { fields["accountId"] = "def67890" }},
+		// A versioned file is authenticated, so dropping its MAC doesn't make it legacy
+		{"MAC removed", func(fields map[string]any) { delete(fields, "mac") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := domain.DeserializeCredentials(tamper(t, serialized, tt.edit))
+			if !errors.Is(err, domain.ErrCredentialsTampered) {
+				t.Errorf("DeserializeCredentials() error = %v, want ErrCredentialsTampered", err)
+			}
+		})
+	}
+
+	// Files written before the MAC existed are still accepted
+	legacy := tamper(t, serialized, func(fields map[string]any) {
+		delete(fields, "mac")
+		delete(fields, "version")
+	})
+	if restored, err := domain.DeserializeCredentials(legacy); err != nil {
+		t.Errorf("DeserializeCredentials() legacy error = %v, want nil", err)
+	} else if data, err := restored.Decrypt(); err != nil || string(data) != `{"sessionKey":"k"}` {
+		t.Errorf("Decrypt() legacy = %s, %v", data, err)
+	}
+
+	future := tamper(t, serialized, func(fields map[string]any) { fields["version"] = 99 })
+	if _, err := domain.DeserializeCredentials(future); err == nil {
+		t.Error("DeserializeCredentials() with unknown version error = nil, want error")
+	}
+}
+
+func TestCredentials_TamperingEnvelope(t *testing.T) {
+	masterKey, _ := domain.GenerateMasterKey()
+	creds, _ := domain.NewCredentialsEnvelope("abc12345", []byte(`{"sessionKey":"k"}`), masterKey)
+	serialized, _ := creds.Serialize()
+
+	// The data key is only available once unwrapped, so that is where tampering shows
+	restored, err := domain.DeserializeCredentials(tamper(t, serialized, flipBit))
+	if err != nil {
+		t.Fatalf("DeserializeCredentials() error = %v", err)
+	}
+	if err := restored.Unwrap(masterKey); !errors.Is(err, domain.ErrCredentialsTampered) {
+		t.Errorf("Unwrap() error = %v, want ErrCredentialsTampered", err)
+	}
+
+	// A wrong master key is reported as such, not as tampering
+	otherKey, _ := domain.GenerateMasterKey()
+	restored, _ = domain.DeserializeCredentials(serialized)
+	if err := restored.Unwrap(otherKey); err == nil || errors.Is(err, domain.ErrCredentialsTampered) {
+		t.Errorf("Unwrap() with wrong key error = %v, want a key error", err)
+	}
+
+	// Serializing credentials loaded without their key keeps the stored MAC
+	protected, _ := domain.NewCredentialsWithPassphrase("abc12345", []byte(`{"sessionKey":"k"}`), []byte("passphrase"))
+	stored, _ := protected.Serialize()
+	loaded, _ := domain.DeserializeCredentials(stored)
+	again, _ := loaded.Serialize()
+	reloaded, _ := domain.DeserializeCredentials(again)
+	if data, err := reloaded.DecryptWithPassphrase([]byte("passphrase")); err != nil || string(data) != `{"sessionKey":"k"}` {
+		t.Errorf("DecryptWithPassphrase() after re-serializing = %s, %v", data, err)
+	}
+	flipped, _ := domain.DeserializeCredentials(tamper(t, stored, flipBit))
+	if _, err := flipped.DecryptWithPassphrase([]byte("passphrase")); err == nil {
+		t.Error("DecryptWithPassphrase() of tampered credentials error = nil, want error")
+	}
+}
+
 func TestCredentials_InvalidSerialization(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -295,3 +405,495 @@ func TestCredentials_InvalidSerialization(t *testing.T) {
 		})
 	}
 }
+
+func TestCredentials_ExpiresAt(t *testing.T) {
+	expiry := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		data   string
+		want   time.Time
+		wantOK bool
+	}{
+		{
+			name:   "unix seconds",
+			data:   `{"sessionKey":"k","sessionKeyExpiresAt":` + strconv.FormatInt(expiry.Unix(), 10) + `}`,
+			want:   expiry,
+			wantOK: true,
+		},
+		{
+			name:   "unix milliseconds",
+			data:   `{"sessionKey":"k","sessionKeyExpiresAt":` + strconv.FormatInt(expiry.UnixMilli(), 10) + `}`,
+			want:   expiry,
+			wantOK: true,
+		},
+		{
+			name:   "RFC3339 string",
+			data:   `{"sessionKey":"k","sessionKeyExpiresAt":"2030-01-02T03:04:05Z"}`,
+			want:   expiry,
+			wantOK: true,
+		},
+		{
+			name: "zero means no expiry",
+			data: `{"sessionKey":"k","sessionKeyExpiresAt":0}`,
+		},
+		{
+			name: "missing field",
+			data: `{"sessionKey":"k"}`,
+		},
+		{
+			name: "not JSON",
+			data: `opaque-token`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds, err := domain.NewCredentials("abc12345", []byte(tt.data))
+			if err != nil {
+				t.Fatalf("failed to create credentials: %v", err)
+			}
+
+			got, ok := creds.ExpiresAt()
+			if ok != tt.wantOK {
+				t.Fatalf("ExpiresAt() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ExpiresAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentials_Passphrase(t *testing.T) {
+	accountID := domain.AccountID("abc12345")
+	data := []byte(`{"sessionKey":"secret"}`)
+	passphrase := []byte("correct horse battery staple")
+
+	creds, err := domain.NewCredentialsWithPassphrase(accountID, data, passphrase)
+	if err != nil {
+		t.Fatalf("failed to create credentials: %v", err)
+	}
+
+	if !creds.IsPassphraseProtected() {
+		t.Error("IsPassphraseProtected() should be true")
+	}
+
+	serialized, err := creds.Serialize()
+	if err != nil {
+		t.Fatalf("failed to serialize: %v", err)
+	}
+
+	// Salt and KDF parameters are persisted alongside the ciphertext
+	var stored map[string]any
+	if err := json.Unmarshal(serialized, &stored); err != nil {
+		t.Fatalf("failed to parse serialized credentials: %v", err)
+	}
+	for _, field := range []string{"salt", "kdf", "kdfIterations"} {
+		if _, ok := stored[field]; !ok {
+			t.Errorf("serialized credentials missing %q", field)
+		}
+	}
+
+	restored, err := domain.DeserializeCredentials(serialized)
+	if err != nil {
+		t.Fatalf("failed to deserialize: %v", err)
+	}
+
+	// The account-ID-derived key no longer works once loaded from storage
+	if _, err := restored.Decrypt(); !errors.Is(err, domain.ErrPassphraseRequired) {
+		t.Errorf("Decrypt() error = %v, want %v", err, domain.ErrPassphraseRequired)
+	}
+
+	decrypted, err := restored.DecryptWithPassphrase(passphrase)
+	if err != nil {
+		t.Fatalf("DecryptWithPassphrase() error = %v", err)
+	}
+	if !bytes.Equal(decrypted, data) {
+		t.Errorf("DecryptWithPassphrase() = %s, want %s", decrypted, data)
+	}
+
+	if _, err := restored.DecryptWithPassphrase([]byte("wrong")); err == nil {
+		t.Error("DecryptWithPassphrase() should fail with the wrong passphrase")
+	}
+
+	// An iteration count no real file uses would make deriving the key hang
+	for _, iterations := range []float64{0, -1, 1e12} {
+		crafted := tamper(t, serialized, func(fields map[string]any) { fields["kdfIterations"] = iterations })
+		if _, err := domain.DeserializeCredentials(crafted); err == nil {
+			t.Errorf("DeserializeCredentials() with %v iterations error = nil, want error", iterations)
+		}
+	}
+}
+
+func TestCredentials_PassphraseUsesRandomSalt(t *testing.T) {
+	data := []byte(`{"sessionKey":"secret"}`)
+	passphrase := []byte("passphrase")
+
+	first, _ := domain.NewCredentialsWithPassphrase("abc12345", data, passphrase)
+	second, _ := domain.NewCredentialsWithPassphrase("abc12345", data, passphrase)
+
+	firstSerialized, _ := first.Serialize()
+	secondSerialized, _ := second.Serialize()
+
+	var a, b map[string]any
+	_ = json.Unmarshal(firstSerialized, &a)
+	_ = json.Unmarshal(secondSerialized, &b)
+
+	if a["salt"] == b["salt"] {
+		t.Error("expected each credential to get its own salt")
+	}
+}
+
+func TestCredentials_PassphraseLegacyFallback(t *testing.T) {
+	data := []byte(`{"sessionKey":"secret"}`)
+
+	legacy, err := domain.NewCredentials("abc12345", data)
+	if err != nil {
+		t.Fatalf("failed to create credentials: %v", err)
+	}
+
+	serialized, _ := legacy.Serialize()
+	restored, err := domain.DeserializeCredentials(serialized)
+	if err != nil {
+		t.Fatalf("failed to deserialize: %v", err)
+	}
+
+	if restored.IsPassphraseProtected() {
+		t.Error("legacy credentials should not be passphrase-protected")
+	}
+
+	decrypted, err := restored.DecryptWithPassphrase([]byte("any passphrase"))
+	if err != nil {
+		t.Fatalf("DecryptWithPassphrase() error = %v", err)
+	}
+	if !bytes.Equal(decrypted, data) {
+		t.Errorf("DecryptWithPassphrase() = %s, want %s", decrypted, data)
+	}
+}
+
+func TestCredentials_PassphraseValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		accountID  domain.AccountID
+		data       []byte
+		passphrase []byte
+		errMsg     string
+	}{
+		{"empty account ID", "", []byte("data"), []byte("pass"), "account ID cannot be empty"},
+		{"empty data", "abc12345", nil, []byte("pass"), "credentials data cannot be empty"},
+		{"empty passphrase", "abc12345", []byte("data"), nil, "passphrase cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := domain.NewCredentialsWithPassphrase(tt.accountID, tt.data, tt.passphrase)
+			if err == nil || err.Error() != tt.errMsg {
+				t.Errorf("error = %v, want %v", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestCredentials_Envelope(t *testing.T) {
+	masterKey, err := domain.GenerateMasterKey()
+	if err != nil {
+		t.Fatalf("GenerateMasterKey() error = %v", err)
+	}
+	data := []byte(`{"sessionKey":"secret"}`)
+
+	creds, err := domain.NewCredentialsEnvelope("abc12345", data, masterKey)
+	if err != nil {
+		t.Fatalf("NewCredentialsEnvelope() error = %v", err)
+	}
+	if !creds.IsEnvelopeEncrypted() || creds.IsPassphraseProtected() {
+		t.Error("Envelope credentials should be envelope-encrypted and not passphrase-protected")
+	}
+	if got, err := creds.Decrypt(); err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("Decrypt() = %s, %v; want original data", got, err)
+	}
+
+	serialized, err := creds.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize() error = %v", err)
+	}
+	if bytes.Contains(serialized, data) {
+		t.Error("Serialized credentials contain the plaintext")
+	}
+
+	// Stored credentials need the master key before they can be decrypted
+	loaded, err := domain.DeserializeCredentials(serialized)
+	if err != nil {
+		t.Fatalf("DeserializeCredentials() error = %v", err)
+	}
+	if _, err := loaded.Decrypt(); !errors.Is(err, domain.ErrMasterKeyRequired) {
+		t.Errorf("Decrypt() before Unwrap error = %v, want ErrMasterKeyRequired", err)
+	}
+	if err := loaded.UpdateData([]byte("new")); !errors.Is(err, domain.ErrMasterKeyRequired) {
+		t.Errorf("UpdateData() before Unwrap error = %v, want ErrMasterKeyRequired", err)
+	}
+	wrongKey, _ := domain.GenerateMasterKey()
+	if err := loaded.Unwrap(wrongKey); err == nil {
+		t.Error("Unwrap() with the wrong master key error = nil, want error")
+	}
+	if err := loaded.Unwrap(masterKey); err != nil {
+		t.Fatalf("Unwrap() error = %v", err)
+	}
+	if got, err := loaded.Decrypt(); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("Decrypt() after Unwrap = %s, %v; want original data", got, err)
+	}
+
+	// Each credential gets its own data key
+	other, _ := domain.NewCredentialsEnvelope("abc12345", data, masterKey)
+	otherSerialized, _ := other.Serialize()
+	var first, second map[string]any
+	_ = json.Unmarshal(serialized, &first)
+	_ = json.Unmarshal(otherSerialized, &second)
+	if first["wrappedKey"] == nil || first["wrappedKey"] == second["wrappedKey"] {
+		t.Errorf("wrappedKey = %v and %v, want distinct wrapped data keys", first["wrappedKey"], second["wrappedKey"])
+	}
+}
+
+func TestCredentials_EnvelopeRewrap(t *testing.T) {
+	oldKey, _ := domain.GenerateMasterKey()
+	newKey, _ := domain.GenerateMasterKey()
+	creds, _ := domain.NewCredentialsEnvelope("abc12345", []byte(`{"sessionKey":"secret"}`), oldKey)
+	payload := creds.EncryptedData()
+	clone := creds.Clone()
+
+	if err := creds.Rewrap(newKey); err != nil {
+		t.Fatalf("Rewrap() error = %v", err)
+	}
+
+	// A clone keeps its own wrapped key
+	cloneSerialized, _ := clone.Serialize()
+	if cloneLoaded, _ := domain.DeserializeCredentials(cloneSerialized); cloneLoaded.Unwrap(oldKey) != nil {
+		t.Error("Rewrap() changed the wrapped key of a clone")
+	}
+	if !bytes.Equal(creds.EncryptedData(), payload) {
+		t.Error("Rewrap() should not re-encrypt the payload")
+	}
+
+	serialized, _ := creds.Serialize()
+	loaded, _ := domain.DeserializeCredentials(serialized)
+	if err := loaded.Unwrap(oldKey); err == nil {
+		t.Error("Unwrap() with the old master key error = nil, want error")
+	}
+	if err := loaded.Unwrap(newKey); err != nil {
+		t.Errorf("Unwrap() with the new master key error = %v", err)
+	}
+
+	// Rewrapping needs the data key
+	locked, _ := domain.DeserializeCredentials(serialized)
+	if err := locked.Rewrap(oldKey); !errors.Is(err, domain.ErrMasterKeyRequired) {
+		t.Errorf("Rewrap() before Unwrap error = %v, want ErrMasterKeyRequired", err)
+	}
+	legacy, _ := domain.NewCredentials("abc12345", []byte("data"))
+	if err := legacy.Rewrap(newKey); err == nil {
+		t.Error("Rewrap() on legacy credentials error = nil, want error")
+	}
+}
+
+func TestCredentials_Rekey(t *testing.T) {
+	data := []byte(`{"sessionKey":"secret"}`)
+	masterKey, _ := domain.GenerateMasterKey()
+	plain, _ := domain.NewCredentials("abc12345", data)
+	envelope, _ := domain.NewCredentialsEnvelope("abc12345", data, masterKey)
+
+	for name, creds := range map[string]*domain.Credentials{"derived key": plain, "envelope": envelope} {
+		t.Run(name, func(t *testing.T) {
+			rekeyed, err := creds.Rekey("def67890")
+			if err != nil {
+				t.Fatalf("Rekey() error = %v", err)
+			}
+			if rekeyed.AccountID() != "def67890" || creds.AccountID() != "abc12345" {
+				t.Errorf("account IDs = %s and %s, want def67890 and abc12345", rekeyed.AccountID(), creds.AccountID())
+			}
+			if rekeyed.IsEnvelopeEncrypted() != creds.IsEnvelopeEncrypted() {
+				t.Error("Rekey() changed the encryption scheme")
+			}
+
+			// The re-keyed credentials pass their integrity check under the new ID
+			serialized, _ := rekeyed.Serialize()
+			loaded, err := domain.DeserializeCredentials(serialized)
+			if err != nil {
+				t.Fatalf("DeserializeCredentials() error = %v", err)
+			}
+			if loaded.IsEnvelopeEncrypted() {
+				if err := loaded.Unwrap(masterKey); err != nil {
+					t.Fatalf("Unwrap() error = %v", err)
+				}
+			}
+			if got, err := loaded.Decrypt(); err != nil || !bytes.Equal(got, data) {
+				t.Errorf("Decrypt() = %s, %v; want original data", got, err)
+			}
+		})
+	}
+
+	passphrase, _ := domain.NewCredentialsWithPassphrase("abc12345", data, []byte("hunter2"))
+	if _, err := passphrase.Rekey("def67890"); !errors.Is(err, domain.ErrPassphraseRequired) {
+		t.Errorf("Rekey() passphrase-protected error = %v, want ErrPassphraseRequired", err)
+	}
+
+	serialized, _ := envelope.Serialize()
+	wrapped, _ := domain.DeserializeCredentials(serialized)
+	if _, err := wrapped.Rekey("def67890"); !errors.Is(err, domain.ErrMasterKeyRequired) {
+		t.Errorf("Rekey() before Unwrap error = %v, want ErrMasterKeyRequired", err)
+	}
+
+	if _, err := plain.Rekey(""); err == nil {
+		t.Error("Rekey(\"\") error = nil, want error")
+	}
+}
+
+func TestCredentials_EnvelopeValidation(t *testing.T) {
+	masterKey, _ := domain.GenerateMasterKey()
+	tests := []struct {
+		name      string
+		accountID domain.AccountID
+		data      []byte
+		masterKey []byte
+	}{
+		{"empty account ID", "", []byte("data"), masterKey},
+		{"empty data", "abc12345", nil, masterKey},
+		{"short master key", "abc12345", []byte("data"), masterKey[:16]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := domain.NewCredentialsEnvelope(tt.accountID, tt.data, tt.masterKey); err == nil {
+				t.Error("NewCredentialsEnvelope() error = nil, want error")
+			}
+		})
+	}
+
+	if _, err := domain.DeserializeCredentials([]byte(`{"accountId":"abc12345","encryptedData":"AAAA","keyWrap":"rot13","wrappedKey":"AAAA"}`)); err == nil {
+		t.Error("DeserializeCredentials() with unknown key wrap error = nil, want error")
+	}
+}
+
+func TestValidateCredentialData(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"camel case session key", `{"sessionKey": "sk-ant-123"}`, false},
+		{"snake case session key", `{"session_key": "sk-ant-123", "expires_at": 0}`, false},
+		{"oauth access token", `{"claudeAiOauth": {"accessToken": "sk-ant-oat-123", "expiresAt": 1750000000000}}`, false},
+		{"oauth without access token", `{"claudeAiOauth": {"refreshToken": "sk-ant-ort-123"}}`, true},
+		{"not JSON", "sk-ant-123", true},
+		{"JSON array", `["sk-ant-123"]`, true},
+		{"missing session key", `{"account_id": "uuid-123"}`, true},
+		{"empty session key", `{"sessionKey": ""}`, true},
+		{"blank session key", `{"sessionKey": "   "}`, true},
+		{"non-string session key", `{"sessionKey": 123}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := domain.ValidateCredentialData([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateCredentialData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, domain.ErrInvalidCredentialData) {
+				t.Errorf("ValidateCredentialData() error = %v, want ErrInvalidCredentialData", err)
+			}
+		})
+	}
+}
+
+// TestCredentials_ErrorsRedactSecret tests that errors raised while handling a known
+// secret never quote it, while the underlying error stays inspectable
+func TestCredentials_ErrorsRedactSecret(t *testing.T) {
+	const secret = "sk-ant-do-not-log"
+	creds, err := domain.NewCredentials("abc12345", []byte(secret+`"}`))
+	if err != nil {
+		t.Fatalf("NewCredentials() error = %v", err)
+	}
+	plaintext, err := creds.Decrypt()
+	if err != nil || string(plaintext) != secret+`"}` {
+		t.Fatalf("Decrypt() = %q, %v; want the secret back", plaintext, err)
+	}
+
+	err = domain.ValidateCredentialData(plaintext)
+	if err == nil {
+		t.Fatal("ValidateCredentialData() error = nil, want error")
+	}
+	// Syntax errors quote the first bad character, so even a single byte must not leak
+	if strings.Contains(err.Error(), secret) || strings.Contains(err.Error(), "'s'") {
+		t.Errorf("error %q leaks the secret", err)
+	}
+	var syntaxErr *json.SyntaxError
+	if !errors.Is(err, domain.ErrInvalidCredentialData) || !errors.As(err, &syntaxErr) {
+		t.Errorf("error %v should match ErrInvalidCredentialData and *json.SyntaxError", err)
+	}
+
+	// Formatting the credentials themselves prints only the account ID
+	for _, formatted := range []string{fmt.Sprintf("%v", creds), fmt.Sprintf("%+v", creds), fmt.Sprintf("%#v", creds)} {
+		if strings.Contains(formatted, base64.StdEncoding.EncodeToString(creds.EncryptedData())) ||
+			!strings.Contains(formatted, "abc12345") {
+			t.Errorf("formatted credentials = %q, want only the account ID", formatted)
+		}
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	if domain.RedactError(nil) != nil {
+		t.Error("RedactError(nil) != nil")
+	}
+
+	inner := fmt.Errorf("%w: sk-ant-secret", domain.ErrInvalidCredentialData)
+	err := domain.RedactError(inner)
+	if strings.Contains(err.Error(), "sk-ant-secret") {
+		t.Errorf("RedactError() message = %q, want it redacted", err)
+	}
+	if !errors.Is(err, domain.ErrInvalidCredentialData) {
+		t.Error("RedactError() should keep the wrapped sentinel reachable")
+	}
+
+	// The message names the kind of failure, never Go types
+	var syntaxErr *json.SyntaxError
+	jsonErr := json.Unmarshal([]byte(`{"sessionKey": sk-ant-secret}`), &struct{}{})
+	redacted := domain.RedactError(jsonErr)
+	if redacted.Error() != "invalid JSON (details redacted)" {
+		t.Errorf("RedactError() message = %q, want %q", redacted, "invalid JSON (details redacted)")
+	}
+	if !errors.As(redacted, &syntaxErr) {
+		t.Error("RedactError() should keep the wrapped error reachable with errors.As")
+	}
+	if msg := domain.RedactError(inner).Error(); msg != "error (details redacted)" {
+		t.Errorf("RedactError() message = %q, want %q", msg, "error (details redacted)")
+	}
+}
+
+func TestCredentials_Scheme(t *testing.T) {
+	data := []byte(`{"sessionKey":"key"}`)
+	masterKey, _ := domain.GenerateMasterKey()
+
+	legacy, _ := domain.NewCredentials("abc12345", data)
+	envelope, _ := domain.NewCredentialsEnvelope("abc12345", data, masterKey)
+	passphrase, _ := domain.NewCredentialsWithPassphrase("abc12345", data, []byte("hunter2"))
+
+	for _, tt := range []struct {
+		creds *domain.Credentials
+		want  domain.CredentialScheme
+	}{
+		{legacy, domain.SchemeAccountKey},
+		{envelope, domain.SchemeEnvelope},
+		{passphrase, domain.SchemePassphrase},
+	} {
+		if got := tt.creds.Scheme(); got != tt.want {
+			t.Errorf("Scheme() = %s, want %s", got, tt.want)
+		}
+
+		// The scheme survives storage without the key
+		serialized, _ := tt.creds.Serialize()
+		loaded, err := domain.DeserializeCredentials(serialized)
+		if err != nil {
+			t.Fatalf("DeserializeCredentials() error = %v", err)
+		}
+		if got := loaded.Scheme(); got != tt.want {
+			t.Errorf("Scheme() after round trip = %s, want %s", got, tt.want)
+		}
+	}
+}
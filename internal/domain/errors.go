@@ -0,0 +1,37 @@
+package domain
+
+import "errors"
+
+// Sentinel errors shared by adapters and use cases. Callers should test for them
+// with errors.Is, since they are usually wrapped with more context.
+var (
+	// ErrAccountNotFound is returned when no account matches an ID, email, or alias
+	ErrAccountNotFound = errors.New("account not found")
+
+	// ErrCredentialsNotFound is returned when no credentials are stored for an account
+	ErrCredentialsNotFound = errors.New("credentials not found")
+
+	// ErrDuplicateAccount is returned when an account would collide with an existing one
+	ErrDuplicateAccount = errors.New("account already exists")
+
+	// ErrDuplicateAlias is returned when an alias is already used by another account
+	ErrDuplicateAlias = errors.New("alias already in use")
+
+	// ErrDuplicateUUID is returned when a Claude account UUID is already used by another account
+	ErrDuplicateUUID = errors.New("account UUID already in use")
+
+	// ErrNoCurrentAccount is returned when Claude config has no active account
+	ErrNoCurrentAccount = errors.New("no current Claude account")
+
+	// ErrProfileNotFound is returned when no profile has the requested name
+	ErrProfileNotFound = errors.New("profile not found")
+
+	// ErrDuplicateProfile is returned when a profile would reuse an existing name
+	ErrDuplicateProfile = errors.New("profile already exists")
+
+	// ErrStoreReadOnly is returned when a write is attempted while ccx is read-only
+	ErrStoreReadOnly = errors.New("store is read-only")
+
+	// ErrInvalidAccountID is returned when an account ID is not in the generated format
+	ErrInvalidAccountID = errors.New("invalid account ID")
+)
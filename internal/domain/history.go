@@ -12,20 +12,18 @@ type SwitchEntry struct {
 	timestamp time.Time
 }
 
-// History manages account switch history
+// History manages account switch history. Entries are kept in a ring buffer sized to
+// the maximum, so adding one never reallocates.
 type History struct {
-	entries    []*SwitchEntry
-	maxEntries int
+	ring  []*SwitchEntry // Fixed-size buffer; its length is the maximum number of entries
+	start int            // Index of the oldest entry in ring
+	count int            // Number of entries held
 }
 
-// NewSwitchEntry creates a new switch entry with validation
+// NewSwitchEntry creates a new switch entry with validation, timestamped now in UTC
 func NewSwitchEntry(from, to Email) (*SwitchEntry, error) {
-	if from == "" {
-		return nil, errors.New("from email cannot be empty")
-	}
-
-	if to == "" {
-		return nil, errors.New("to email cannot be empty")
+	if err := validateSwitchEmails(from, to); err != nil {
+		return nil, err
 	}
 
 	if from == to {
@@ -35,10 +33,43 @@ func NewSwitchEntry(from, to Email) (*SwitchEntry, error) {
 	return &SwitchEntry{
 		from:      from,
 		to:        to,
-		timestamp: time.Now(),
+		timestamp: time.Now().UTC(),
+	}, nil
+}
+
+// ReconstructSwitchEntry recreates a switch entry with a specific timestamp, which is
+// converted to UTC. Used by adapters to recreate entries from persistence layer, so it
+// trusts the stored data enough to accept an entry from an account to itself, but still
+// rejects empty emails and a zero timestamp.
+func ReconstructSwitchEntry(from, to Email, timestamp time.Time) (*SwitchEntry, error) {
+	if err := validateSwitchEmails(from, to); err != nil {
+		return nil, err
+	}
+
+	if timestamp.IsZero() {
+		return nil, errors.New("timestamp cannot be zero")
+	}
+
+	return &SwitchEntry{
+		from:      from,
+		to:        to,
+		timestamp: timestamp.UTC(),
 	}, nil
 }
 
+// validateSwitchEmails checks that both ends of a switch are set
+func validateSwitchEmails(from, to Email) error {
+	if from == "" {
+		return errors.New("from email cannot be empty")
+	}
+
+	if to == "" {
+		return errors.New("to email cannot be empty")
+	}
+
+	return nil
+}
+
 // From returns the source account email
 func (s *SwitchEntry) From() Email {
 	return s.from
@@ -54,78 +85,225 @@ func (s *SwitchEntry) Timestamp() time.Time {
 	return s.timestamp
 }
 
-// NewHistory creates a new history tracker with a maximum number of entries
+// clone returns an independent copy of the entry
+func (s *SwitchEntry) clone() *SwitchEntry {
+	entry := *s
+	return &entry
+}
+
+// NewHistory creates a new history tracker with a maximum number of entries, which is
+// capped at MaxHistorySize
 func NewHistory(maxEntries int) *History {
 	if maxEntries <= 0 {
 		maxEntries = 10 // Default to 10 entries
 	}
+	maxEntries = min(maxEntries, MaxHistorySize)
 
 	return &History{
-		entries:    make([]*SwitchEntry, 0, maxEntries),
-		maxEntries: maxEntries,
+		ring: make([]*SwitchEntry, maxEntries),
 	}
 }
 
 // MaxEntries returns the maximum number of entries this history will keep
 func (h *History) MaxEntries() int {
-	return h.maxEntries
+	return len(h.ring)
+}
+
+// SetMaxEntries changes how many entries the history keeps, up to MaxHistorySize,
+// dropping the oldest entries if it now holds too many. Non-positive values are ignored.
+func (h *History) SetMaxEntries(maxEntries int) {
+	maxEntries = min(maxEntries, MaxHistorySize)
+	if maxEntries <= 0 || maxEntries == len(h.ring) {
+		return
+	}
+	h.reset(maxEntries, h.oldestFirst())
 }
 
-// AddEntry adds a new switch entry to the history
-// Most recent entries are kept at the beginning of the slice
+// AddEntry adds a new switch entry to the history, overwriting the oldest entry if the
+// history is full
 func (h *History) AddEntry(entry *SwitchEntry) {
-	if entry == nil {
+	if entry == nil || len(h.ring) == 0 {
 		return
 	}
 
-	// Add to the beginning
-	h.entries = append([]*SwitchEntry{entry}, h.entries...)
+	if h.count < len(h.ring) {
+		h.ring[(h.start+h.count)%len(h.ring)] = entry
+		h.count++
+		return
+	}
+	h.ring[h.start] = entry
+	h.start = (h.start + 1) % len(h.ring)
+}
+
+// at returns the i-th entry, counting from the oldest
+func (h *History) at(i int) *SwitchEntry {
+	return h.ring[(h.start+i)%len(h.ring)]
+}
+
+// oldestFirst returns the entries from the oldest to the most recent
+func (h *History) oldestFirst() []*SwitchEntry {
+	entries := make([]*SwitchEntry, h.count)
+	for i := range entries {
+		entries[i] = h.at(i)
+	}
+	return entries
+}
 
-	// Trim if we exceed max entries
-	if len(h.entries) > h.maxEntries {
-		h.entries = h.entries[:h.maxEntries]
+// reset replaces the buffer with one of size maxEntries holding the most recent of
+// entries, which are ordered oldest first
+func (h *History) reset(maxEntries int, entries []*SwitchEntry) {
+	if len(entries) > maxEntries {
+		entries = entries[len(entries)-maxEntries:]
 	}
+	h.ring = make([]*SwitchEntry, maxEntries)
+	h.start = 0
+	h.count = copy(h.ring, entries)
 }
 
 // Entries returns a copy of all history entries (most recent first)
 func (h *History) Entries() []*SwitchEntry {
 	// Return a copy to prevent external modification
-	result := make([]*SwitchEntry, len(h.entries))
-	copy(result, h.entries)
+	result := make([]*SwitchEntry, 0, h.count)
+	for i := h.count - 1; i >= 0; i-- {
+		result = append(result, h.at(i))
+	}
 	return result
 }
 
+// Clone creates a copy of the history that can be changed independently
+func (h *History) Clone() *History {
+	cloned := &History{}
+	cloned.CopyFrom(h)
+	return cloned
+}
+
+// CopyFrom replaces the entries and size of h with copies of those in other, so h
+// can be changed independently afterwards
+func (h *History) CopyFrom(other *History) {
+	if h == other {
+		return
+	}
+	entries := other.oldestFirst()
+	for i, entry := range entries {
+		entries[i] = entry.clone()
+	}
+	h.reset(len(other.ring), entries)
+}
+
 // Clear removes all entries from the history
 func (h *History) Clear() {
-	h.entries = h.entries[:0]
+	clear(h.ring)
+	h.start = 0
+	h.count = 0
 }
 
 // GetLastSwitch returns the most recent switch entry, or nil if history is empty
 func (h *History) GetLastSwitch() *SwitchEntry {
-	if len(h.entries) == 0 {
+	if h.count == 0 {
 		return nil
 	}
-	return h.entries[0]
+	return h.at(h.count - 1)
+}
+
+// newestFirst iterates over the entries from the most recent to the oldest
+func (h *History) newestFirst(yield func(*SwitchEntry) bool) {
+	for i := h.count - 1; i >= 0; i-- {
+		if !yield(h.at(i)) {
+			return
+		}
+	}
 }
 
-// FindSwitchesFrom returns all switches from a specific email address
+// Reconcile brings history in line with the account Claude is currently using. If the
+// most recent switch went to a different account, Claude was switched outside ccx, so a
+// switch from that account to currentEmail is recorded, timestamped now since the real
+// time is unknown. It reports whether an entry was added. An empty currentEmail or an
+// empty history leaves nothing to compare against.
+func (h *History) Reconcile(currentEmail Email) bool {
+	last := h.GetLastSwitch()
+	if last == nil || currentEmail == "" || last.to == currentEmail {
+		return false
+	}
+
+	entry, err := NewSwitchEntry(last.to, currentEmail)
+	if err != nil {
+		return false
+	}
+	h.AddEntry(entry)
+	return true
+}
+
+// FindSwitchesFrom returns copies of all switches from a specific email address,
+// ordered most recent first
 func (h *History) FindSwitchesFrom(email Email) []*SwitchEntry {
 	var result []*SwitchEntry
-	for _, entry := range h.entries {
+	for entry := range h.newestFirst {
 		if entry.from == email {
-			result = append(result, entry)
+			result = append(result, entry.clone())
 		}
 	}
 	return result
 }
 
-// FindSwitchesTo returns all switches to a specific email address
+// FindSwitchesTo returns copies of all switches to a specific email address,
+// ordered most recent first
 func (h *History) FindSwitchesTo(email Email) []*SwitchEntry {
 	var result []*SwitchEntry
-	for _, entry := range h.entries {
+	for entry := range h.newestFirst {
 		if entry.to == email {
-			result = append(result, entry)
+			result = append(result, entry.clone())
 		}
 	}
 	return result
 }
+
+// FindInRange returns copies of all switches at or after since and before until,
+// ordered most recent first. A zero since or until leaves that end of the range open.
+func (h *History) FindInRange(since, until time.Time) []*SwitchEntry {
+	var result []*SwitchEntry
+	for entry := range h.newestFirst {
+		if !since.IsZero() && entry.timestamp.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !entry.timestamp.Before(until) {
+			continue
+		}
+		result = append(result, entry.clone())
+	}
+	return result
+}
+
+// RewriteEmail replaces oldEmail with newEmail in the from and to of every entry,
+// returning how many entries changed. An entry that would end up switching from an
+// account to itself is dropped instead; dropped entries are included in the count.
+func (h *History) RewriteEmail(oldEmail, newEmail Email) int {
+	if oldEmail == newEmail {
+		return 0
+	}
+
+	changed := 0
+	var kept []*SwitchEntry
+	for _, entry := range h.oldestFirst() {
+		if entry.from != oldEmail && entry.to != oldEmail {
+			kept = append(kept, entry)
+			continue
+		}
+		changed++
+
+		// Replace rather than mutate, so entries handed out earlier are unaffected
+		rewritten := entry.clone()
+		if rewritten.from == oldEmail {
+			rewritten.from = newEmail
+		}
+		if rewritten.to == oldEmail {
+			rewritten.to = newEmail
+		}
+		if rewritten.from == rewritten.to {
+			continue
+		}
+		kept = append(kept, rewritten)
+	}
+	h.reset(len(h.ring), kept)
+
+	return changed
+}
@@ -1,6 +1,8 @@
 package domain_test
 
 import (
+	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -74,6 +76,10 @@ func TestSwitchEntry_Creation(t *testing.T) {
 				t.Error("Timestamp() should not be zero")
 			}
 
+			if entry.Timestamp().Location() != time.UTC {
+				t.Errorf("Timestamp() location = %v, want UTC", entry.Timestamp().Location())
+			}
+
 			// Timestamp should be recent (within last second)
 			if time.Since(entry.Timestamp()) > time.Second {
 				t.Error("Timestamp() should be recent")
@@ -82,6 +88,47 @@ func TestSwitchEntry_Creation(t *testing.T) {
 	}
 }
 
+func TestSwitchEntry_Reconstruct(t *testing.T) {
+	timestamp := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		from      domain.Email
+		to        domain.Email
+		timestamp time.Time
+		wantErr   bool
+	}{
+		{"valid entry", "user1@example.com", "user2@example.com", timestamp, false},
+		{"zero timestamp", "user1@example.com", "user2@example.com", time.Time{}, true},
+		{"empty from", "", "user2@example.com", timestamp, true},
+		{"empty to", "user1@example.com", "", timestamp, true},
+		// Loaded data is trusted, so a self switch is kept rather than rejected
+		{"same from and to", "user@example.com", "user@example.com", timestamp, false},
+		{"non-UTC timestamp", "user1@example.com", "user2@example.com", timestamp.In(time.FixedZone("EST", -5*3600)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := domain.ReconstructSwitchEntry(tt.from, tt.to, tt.timestamp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReconstructSwitchEntry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !entry.Timestamp().Equal(tt.timestamp) {
+				t.Errorf("Timestamp() = %v, want %v", entry.Timestamp(), tt.timestamp)
+			}
+			if entry.Timestamp().Location() != time.UTC {
+				t.Errorf("Timestamp() location = %v, want UTC", entry.Timestamp().Location())
+			}
+			if entry.From() != tt.from || entry.To() != tt.to {
+				t.Errorf("Entry = %s -> %s, want %s -> %s", entry.From(), entry.To(), tt.from, tt.to)
+			}
+		})
+	}
+}
+
 func TestHistory_Creation(t *testing.T) {
 	history := domain.NewHistory(10)
 
@@ -94,6 +141,19 @@ func TestHistory_Creation(t *testing.T) {
 	}
 }
 
+func TestHistory_SizeIsCapped(t *testing.T) {
+	history := domain.NewHistory(1 << 40)
+	if history.MaxEntries() != domain.MaxHistorySize {
+		t.Errorf("MaxEntries() = %d, want it capped at %d", history.MaxEntries(), domain.MaxHistorySize)
+	}
+
+	history = domain.NewHistory(10)
+	history.SetMaxEntries(domain.MaxHistorySize + 1)
+	if history.MaxEntries() != domain.MaxHistorySize {
+		t.Errorf("MaxEntries() after SetMaxEntries = %d, want it capped at %d", history.MaxEntries(), domain.MaxHistorySize)
+	}
+}
+
 func TestHistory_AddEntry(t *testing.T) {
 	history := domain.NewHistory(5)
 
@@ -297,3 +357,307 @@ func TestHistory_FindSwitchesTo(t *testing.T) {
 		t.Error("FindSwitchesTo did not return expected entries")
 	}
 }
+
+func TestHistory_FindSwitchesOrderingAndCopies(t *testing.T) {
+	history := domain.NewHistory(10)
+
+	oldest, _ := domain.NewSwitchEntry("user1@example.com", "user2@example.com")
+	middle, _ := domain.NewSwitchEntry("user1@example.com", "user3@example.com")
+	newest, _ := domain.NewSwitchEntry("user2@example.com", "user3@example.com")
+	history.AddEntry(oldest)
+	history.AddEntry(middle)
+	history.AddEntry(newest)
+
+	tests := []struct {
+		name    string
+		results []*domain.SwitchEntry
+		want    []*domain.SwitchEntry
+	}{
+		{"FindSwitchesFrom", history.FindSwitchesFrom("user1@example.com"), []*domain.SwitchEntry{middle, oldest}},
+		{"FindSwitchesTo", history.FindSwitchesTo("user3@example.com"), []*domain.SwitchEntry{newest, middle}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.results) != len(tt.want) {
+				t.Fatalf("expected %d switches, got %d", len(tt.want), len(tt.results))
+			}
+
+			for i, got := range tt.results {
+				want := tt.want[i]
+
+				// Most recent first, matching the stored entry's values
+				if got.From() != want.From() || got.To() != want.To() || !got.Timestamp().Equal(want.Timestamp()) {
+					t.Errorf("result[%d] = %v->%v, want %v->%v", i, got.From(), got.To(), want.From(), want.To())
+				}
+
+				// Returned entries are copies, not the stored pointers
+				if got == want {
+					t.Errorf("result[%d] should be an independent copy", i)
+				}
+			}
+		})
+	}
+}
+
+func TestHistory_FindInRange(t *testing.T) {
+	history := domain.NewHistory(10)
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for day := range 4 {
+		entry, _ := domain.ReconstructSwitchEntry("user1@example.com", "user2@example.com", base.AddDate(0, 0, day))
+		history.AddEntry(entry)
+	}
+
+	tests := []struct {
+		name         string
+		since, until time.Time
+		wantDays     []int
+	}{
+		{"open range", time.Time{}, time.Time{}, []int{3, 2, 1, 0}},
+		{"since is inclusive", base.AddDate(0, 0, 2), time.Time{}, []int{3, 2}},
+		{"until is exclusive", time.Time{}, base.AddDate(0, 0, 2), []int{1, 0}},
+		{"closed range", base.AddDate(0, 0, 1), base.AddDate(0, 0, 3), []int{2, 1}},
+		{"empty range", base.AddDate(0, 0, 5), time.Time{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := history.FindInRange(tt.since, tt.until)
+			if len(found) != len(tt.wantDays) {
+				t.Fatalf("FindInRange() returned %d entries, want %d", len(found), len(tt.wantDays))
+			}
+			for i, day := range tt.wantDays {
+				if want := base.AddDate(0, 0, day); !found[i].Timestamp().Equal(want) {
+					t.Errorf("entry %d timestamp = %v, want %v", i, found[i].Timestamp(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestHistory_RewriteEmail(t *testing.T) {
+	history := domain.NewHistory(10)
+
+	// Added oldest first, so Entries() returns them in reverse
+	switches := []struct {
+		from string
+		to   string
+	}{
+		{"old@example.com", "user2@example.com"},
+		{"user2@example.com", "new@example.com"},
+		{"user2@example.com", "user3@example.com"},
+		{"new@example.com", "old@example.com"},
+		{"user3@example.com", "old@example.com"},
+	}
+	for _, sw := range switches {
+		entry, _ := domain.NewSwitchEntry(domain.Email(sw.from), domain.Email(sw.to))
+		history.AddEntry(entry)
+	}
+	before := history.Entries()
+
+	changed := history.RewriteEmail("old@example.com", "new@example.com")
+	if changed != 3 {
+		t.Errorf("RewriteEmail() = %d, want 3", changed)
+	}
+
+	// The new -> old entry would become new -> new and is dropped
+	entries := history.Entries()
+	want := []struct{ from, to domain.Email }{
+		{"user3@example.com", "new@example.com"},
+		{"user2@example.com", "user3@example.com"},
+		{"user2@example.com", "new@example.com"},
+		{"new@example.com", "user2@example.com"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(entries))
+	}
+	for i, w := range want {
+		if entries[i].From() != w.from || entries[i].To() != w.to {
+			t.Errorf("entry %d = %s -> %s, want %s -> %s", i, entries[i].From(), entries[i].To(), w.from, w.to)
+		}
+	}
+
+	// Entries handed out before the rewrite are unchanged
+	if before[0].To() != "old@example.com" {
+		t.Errorf("previously returned entry was mutated: %s -> %s", before[0].From(), before[0].To())
+	}
+
+	if changed := history.RewriteEmail("missing@example.com", "other@example.com"); changed != 0 {
+		t.Errorf("RewriteEmail() for unknown email = %d, want 0", changed)
+	}
+}
+
+func TestHistory_Reconcile(t *testing.T) {
+	history := domain.NewHistory(10)
+	if history.Reconcile("user1@example.com") {
+		t.Error("Reconcile() on empty history = true, want false")
+	}
+
+	entry, _ := domain.NewSwitchEntry("user1@example.com", "user2@example.com")
+	history.AddEntry(entry)
+
+	if history.Reconcile("user2@example.com") {
+		t.Error("Reconcile() with matching current account = true, want false")
+	}
+	if history.Reconcile("") {
+		t.Error("Reconcile() with no current account = true, want false")
+	}
+	if len(history.Entries()) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(history.Entries()))
+	}
+
+	// Claude was switched to user3 outside ccx
+	if !history.Reconcile("user3@example.com") {
+		t.Fatal("Reconcile() with diverged current account = false, want true")
+	}
+	last := history.GetLastSwitch()
+	if last.From() != "user2@example.com" || last.To() != "user3@example.com" {
+		t.Errorf("recorded entry = %s -> %s, want user2@example.com -> user3@example.com", last.From(), last.To())
+	}
+	if history.Reconcile("user3@example.com") {
+		t.Error("Reconcile() after recording = true, want false")
+	}
+}
+
+func TestHistory_SetMaxEntries(t *testing.T) {
+	history := domain.NewHistory(5)
+	for i := 0; i < 5; i++ {
+		from := domain.Email(fmt.Sprintf("user%d@example.com", i))
+		to := domain.Email(fmt.Sprintf("user%d@example.com", i+1))
+		entry, _ := domain.NewSwitchEntry(from, to)
+		history.AddEntry(entry)
+	}
+
+	// Shrinking keeps the most recent entries
+	history.SetMaxEntries(2)
+	if history.MaxEntries() != 2 {
+		t.Errorf("MaxEntries() = %d, want 2", history.MaxEntries())
+	}
+	entries := history.Entries()
+	if len(entries) != 2 || entries[0].To() != "user5@example.com" {
+		t.Fatalf("expected the 2 most recent entries, got %d", len(entries))
+	}
+
+	// Non-positive sizes are ignored
+	history.SetMaxEntries(0)
+	if history.MaxEntries() != 2 {
+		t.Errorf("MaxEntries() = %d after SetMaxEntries(0), want 2", history.MaxEntries())
+	}
+
+	// Growing keeps every entry and allows more
+	history.SetMaxEntries(10)
+	if history.MaxEntries() != 10 || len(history.Entries()) != 2 {
+		t.Errorf("MaxEntries() = %d with %d entries, want 10 with 2", history.MaxEntries(), len(history.Entries()))
+	}
+}
+
+func TestHistory_Clone(t *testing.T) {
+	history := domain.NewHistory(3)
+	entry, _ := domain.NewSwitchEntry("a@example.com", "b@example.com")
+	history.AddEntry(entry)
+
+	cloned := history.Clone()
+	if cloned.MaxEntries() != 3 || len(cloned.Entries()) != 1 {
+		t.Fatalf("cloned has max %d with %d entries, want 3 with 1", cloned.MaxEntries(), len(cloned.Entries()))
+	}
+
+	// Changing the clone leaves the original alone
+	next, _ := domain.NewSwitchEntry("b@example.com", "c@example.com")
+	cloned.AddEntry(next)
+	cloned.SetMaxEntries(5)
+	if len(history.Entries()) != 1 || history.MaxEntries() != 3 {
+		t.Errorf("original has max %d with %d entries, want 3 with 1", history.MaxEntries(), len(history.Entries()))
+	}
+}
+
+func TestHistory_AddEntryManyKeepsOrder(t *testing.T) {
+	history := domain.NewHistory(5)
+	for i := 0; i < 100; i++ {
+		from := domain.Email(fmt.Sprintf("user%d@example.com", i))
+		to := domain.Email(fmt.Sprintf("user%d@example.com", i+1))
+		entry, _ := domain.NewSwitchEntry(from, to)
+		history.AddEntry(entry)
+	}
+
+	entries := history.Entries()
+	if len(entries) != 5 {
+		t.Fatalf("expected 5 entries, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		want := domain.Email(fmt.Sprintf("user%d@example.com", 100-i))
+		if entry.To() != want {
+			t.Errorf("entries[%d].To() = %s, want %s", i, entry.To(), want)
+		}
+	}
+	if last := history.GetLastSwitch(); last.To() != "user100@example.com" {
+		t.Errorf("GetLastSwitch().To() = %s, want user100@example.com", last.To())
+	}
+}
+
+func TestHistory_CopyFrom(t *testing.T) {
+	source := domain.NewHistory(3)
+	first, _ := domain.NewSwitchEntry("a@example.com", "b@example.com")
+	second, _ := domain.NewSwitchEntry("b@example.com", "c@example.com")
+	source.AddEntry(first)
+	source.AddEntry(second)
+
+	history := domain.NewHistory(10)
+	stale, _ := domain.NewSwitchEntry("x@example.com", "y@example.com")
+	history.AddEntry(stale)
+
+	history.CopyFrom(source)
+	entries := history.Entries()
+	if history.MaxEntries() != 3 || len(entries) != 2 || entries[0].To() != "c@example.com" {
+		t.Fatalf("copied history has max %d with %d entries, want 3 with 2, newest first", history.MaxEntries(), len(entries))
+	}
+
+	// The copy and the source change independently
+	third, _ := domain.NewSwitchEntry("c@example.com", "d@example.com")
+	history.AddEntry(third)
+	source.Clear()
+	if len(history.Entries()) != 3 {
+		t.Errorf("copied history has %d entries after clearing the source, want 3", len(history.Entries()))
+	}
+	if len(source.Entries()) != 0 {
+		t.Errorf("source has %d entries after Clear(), want 0", len(source.Entries()))
+	}
+}
+
+func TestHistory_RingWrapsAcrossResize(t *testing.T) {
+	history := domain.NewHistory(3)
+	for i := 0; i < 5; i++ {
+		from := domain.Email(fmt.Sprintf("user%d@example.com", i))
+		to := domain.Email(fmt.Sprintf("user%d@example.com", i+1))
+		entry, _ := domain.NewSwitchEntry(from, to)
+		history.AddEntry(entry)
+	}
+
+	// Growing a wrapped buffer keeps the order and makes room for more
+	history.SetMaxEntries(4)
+	extra, _ := domain.NewSwitchEntry("user5@example.com", "user6@example.com")
+	history.AddEntry(extra)
+
+	var got []domain.Email
+	for _, entry := range history.Entries() {
+		got = append(got, entry.To())
+	}
+	want := []domain.Email{"user6@example.com", "user5@example.com", "user4@example.com", "user3@example.com"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Entries() = %v, want %v", got, want)
+	}
+}
+
+// BenchmarkHistory_AddEntry measures adding to a full history, the case hit on every
+// switch once history has filled up
+func BenchmarkHistory_AddEntry(b *testing.B) {
+	history := domain.NewHistory(domain.DefaultHistorySize)
+	entry, _ := domain.NewSwitchEntry("a@example.com", "b@example.com")
+	for range domain.DefaultHistorySize {
+		history.AddEntry(entry)
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		history.AddEntry(entry)
+	}
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Profile is a named working context, such as a client or "personal", that selects
+// the account to use. Switching a profile switches to its account.
+type Profile struct {
+	name      string
+	accountID AccountID
+}
+
+// NewProfile creates a profile. Names follow the same rules as account aliases.
+func NewProfile(name string, accountID AccountID) (*Profile, error) {
+	if name == "" {
+		return nil, errors.New("profile name cannot be empty")
+	}
+	if !aliasRegex.MatchString(name) {
+		return nil, fmt.Errorf("invalid profile name %q: can only contain letters, numbers, hyphens, and underscores", name)
+	}
+	if accountID == "" {
+		return nil, errors.New("profile account ID cannot be empty")
+	}
+
+	return &Profile{
+		name:      name,
+		accountID: accountID,
+	}, nil
+}
+
+// Name returns the profile name
+func (p *Profile) Name() string {
+	return p.name
+}
+
+// AccountID returns the account the profile switches to
+func (p *Profile) AccountID() AccountID {
+	return p.accountID
+}
+
+// Clone creates a copy of the profile
+func (p *Profile) Clone() *Profile {
+	clone := *p
+	return &clone
+}
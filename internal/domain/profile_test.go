@@ -0,0 +1,46 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestProfile_New(t *testing.T) {
+	tests := []struct {
+		name      string
+		profile   string
+		accountID domain.AccountID
+		wantErr   bool
+	}{
+		{"valid", "client-a", "abc12345", false},
+		{"underscore and digits", "client_2", "abc12345", false},
+		{"empty name", "", "abc12345", true},
+		{"name with space", "client a", "abc12345", true},
+		{"name with punctuation", "client.a", "abc12345", true},
+		{"empty account ID", "client-a", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := domain.NewProfile(tt.profile, tt.accountID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if profile.Name() != tt.profile || profile.AccountID() != tt.accountID {
+				t.Errorf("Profile = %s -> %s, want %s -> %s", profile.Name(), profile.AccountID(), tt.profile, tt.accountID)
+			}
+		})
+	}
+}
+
+func TestProfile_Clone(t *testing.T) {
+	profile, _ := domain.NewProfile("personal", "abc12345")
+	clone := profile.Clone()
+	if clone == profile || clone.Name() != profile.Name() || clone.AccountID() != profile.AccountID() {
+		t.Errorf("Clone() = %+v, want an equal copy of %+v", clone, profile)
+	}
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// Punycode parameters from RFC 3492
+const (
+	punycodeBase        = 36
+	punycodeTMin        = 1
+	punycodeTMax        = 26
+	punycodeSkew        = 38
+	punycodeDamp        = 700
+	punycodeInitialBias = 72
+	punycodeInitialN    = 128
+
+	// acePrefix marks a domain label that holds punycode
+	acePrefix = "xn--"
+)
+
+// errPunycodeOverflow is returned for labels too long to encode
+var errPunycodeOverflow = errors.New("domain label is too long to encode")
+
+// toASCIIDomain converts a domain to its ASCII form by punycode-encoding every label
+// that contains non-ASCII characters. ASCII labels are returned unchanged. Only the
+// encoding step of IDNA is applied: the domain is expected to be lowercased already.
+func toASCIIDomain(domain string) (string, error) {
+	labels := strings.Split(domain, ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+		encoded, err := punycodeEncode(label)
+		if err != nil {
+			return "", err
+		}
+		labels[i] = acePrefix + encoded
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// isASCII reports whether s contains only ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// punycodeEncode encodes a single label as described in RFC 3492 section 6.3
+func punycodeEncode(label string) (string, error) {
+	runes := []rune(label)
+
+	var out strings.Builder
+	for _, r := range runes {
+		if r < 0x80 {
+			out.WriteRune(r)
+		}
+	}
+	basic := out.Len()
+	handled := basic
+	if basic > 0 {
+		out.WriteByte('-')
+	}
+
+	n, delta, bias := rune(punycodeInitialN), 0, punycodeInitialBias
+	for handled < len(runes) {
+		// The smallest code point not yet handled
+		next := rune(math.MaxInt32)
+		for _, r := range runes {
+			if r >= n && r < next {
+				next = r
+			}
+		}
+
+		if int(next-n) > (math.MaxInt32-delta)/(handled+1) {
+			return "", errPunycodeOverflow
+		}
+		delta += int(next-n) * (handled + 1)
+		n = next
+
+		for _, r := range runes {
+			if r < n {
+				delta++
+				if delta == math.MaxInt32 {
+					return "", errPunycodeOverflow
+				}
+			}
+			if r != n {
+				continue
+			}
+
+			q := delta
+			for k := punycodeBase; ; k += punycodeBase {
+				t := min(max(k-bias, punycodeTMin), punycodeTMax)
+				if q < t {
+					break
+				}
+				out.WriteByte(punycodeDigit(t + (q-t)%(punycodeBase-t)))
+				q = (q - t) / (punycodeBase - t)
+			}
+			out.WriteByte(punycodeDigit(q))
+
+			bias = punycodeAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+		delta++
+		n++
+	}
+
+	return out.String(), nil
+}
+
+// punycodeAdapt is the bias adaptation function from RFC 3492 section 6.1
+func punycodeAdapt(delta, points int, first bool) int {
+	if first {
+		delta /= punycodeDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / points
+
+	k := 0
+	for delta > ((punycodeBase-punycodeTMin)*punycodeTMax)/2 {
+		delta /= punycodeBase - punycodeTMin
+		k += punycodeBase
+	}
+	return k + (punycodeBase-punycodeTMin+1)*delta/(delta+punycodeSkew)
+}
+
+// punycodeDigit returns the lowercase character for a digit value from 0 to 35
+func punycodeDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
@@ -0,0 +1,75 @@
+package domain
+
+import "fmt"
+
+// DefaultHistorySize is how many switches are kept when no history size is configured
+const DefaultHistorySize = 50
+
+// MaxHistorySize is the most switches history can keep. The buffer is allocated up
+// front, so sizes read from disk are clamped to it.
+const MaxHistorySize = 10000
+
+// Settings holds ccx's own preferences, independent of Claude's configuration
+type Settings struct {
+	defaultAccountID AccountID
+	currentAccountID AccountID
+	historySize      int
+}
+
+// NewSettings creates settings with no preferences configured
+func NewSettings() *Settings {
+	return &Settings{}
+}
+
+// Clone creates a copy of the settings
+func (s *Settings) Clone() *Settings {
+	clone := *s
+	return &clone
+}
+
+// DefaultAccountID returns the account to activate when none is current, or empty if unset
+func (s *Settings) DefaultAccountID() AccountID {
+	return s.defaultAccountID
+}
+
+// SetDefaultAccountID designates the default account; an empty ID clears it
+func (s *Settings) SetDefaultAccountID(id AccountID) {
+	s.defaultAccountID = id
+}
+
+// HasDefaultAccount reports whether a default account is configured
+func (s *Settings) HasDefaultAccount() bool {
+	return s.defaultAccountID != ""
+}
+
+// CurrentAccountID returns the account ccx last switched to, or empty if unknown. ccx
+// keeps it itself so it knows what is active even when Claude's config is missing.
+func (s *Settings) CurrentAccountID() AccountID {
+	return s.currentAccountID
+}
+
+// SetCurrentAccountID records the account ccx last switched to; an empty ID clears it
+func (s *Settings) SetCurrentAccountID(id AccountID) {
+	s.currentAccountID = id
+}
+
+// HistorySize returns how many switches history should keep, DefaultHistorySize if unset
+func (s *Settings) HistorySize() int {
+	if s.historySize == 0 {
+		return DefaultHistorySize
+	}
+	return s.historySize
+}
+
+// SetHistorySize sets how many switches history keeps, up to MaxHistorySize; zero
+// restores the default
+func (s *Settings) SetHistorySize(size int) error {
+	if size < 0 {
+		return fmt.Errorf("invalid history size %d: must not be negative", size)
+	}
+	if size > MaxHistorySize {
+		return fmt.Errorf("invalid history size %d: must be at most %d", size, MaxHistorySize)
+	}
+	s.historySize = size
+	return nil
+}
@@ -0,0 +1,96 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestSettings_DefaultAccount(t *testing.T) {
+	settings := domain.NewSettings()
+
+	if settings.HasDefaultAccount() {
+		t.Error("new settings should not have a default account")
+	}
+	if settings.DefaultAccountID() != "" {
+		t.Errorf("DefaultAccountID() = %v, want empty", settings.DefaultAccountID())
+	}
+
+	settings.SetDefaultAccountID("abc12345")
+	if !settings.HasDefaultAccount() {
+		t.Error("HasDefaultAccount() should be true after setting a default")
+	}
+	if settings.DefaultAccountID() != "abc12345" {
+		t.Errorf("DefaultAccountID() = %v, want abc12345", settings.DefaultAccountID())
+	}
+
+	// Setting an empty ID clears the default
+	settings.SetDefaultAccountID("")
+	if settings.HasDefaultAccount() {
+		t.Error("HasDefaultAccount() should be false after clearing")
+	}
+}
+
+func TestSettings_CurrentAccount(t *testing.T) {
+	settings := domain.NewSettings()
+	if settings.CurrentAccountID() != "" {
+		t.Errorf("CurrentAccountID() = %v, want empty", settings.CurrentAccountID())
+	}
+
+	settings.SetCurrentAccountID("abc12345")
+	if settings.CurrentAccountID() != "abc12345" {
+		t.Errorf("CurrentAccountID() = %v, want abc12345", settings.CurrentAccountID())
+	}
+	if settings.HasDefaultAccount() {
+		t.Error("setting the current account should not set a default")
+	}
+}
+
+func TestSettings_HistorySize(t *testing.T) {
+	settings := domain.NewSettings()
+
+	if settings.HistorySize() != domain.DefaultHistorySize {
+		t.Errorf("HistorySize() = %d, want %d", settings.HistorySize(), domain.DefaultHistorySize)
+	}
+
+	if err := settings.SetHistorySize(200); err != nil {
+		t.Fatalf("SetHistorySize() error = %v", err)
+	}
+	if settings.HistorySize() != 200 {
+		t.Errorf("HistorySize() = %d, want 200", settings.HistorySize())
+	}
+
+	if err := settings.SetHistorySize(-1); err == nil {
+		t.Error("SetHistorySize(-1) should fail")
+	}
+	if err := settings.SetHistorySize(domain.MaxHistorySize + 1); err == nil {
+		t.Error("SetHistorySize() above MaxHistorySize should fail")
+	}
+	if settings.HistorySize() != 200 {
+		t.Errorf("HistorySize() = %d after rejected update, want 200", settings.HistorySize())
+	}
+
+	// Zero restores the default
+	if err := settings.SetHistorySize(0); err != nil {
+		t.Fatalf("SetHistorySize(0) error = %v", err)
+	}
+	if settings.HistorySize() != domain.DefaultHistorySize {
+		t.Errorf("HistorySize() = %d, want default %d", settings.HistorySize(), domain.DefaultHistorySize)
+	}
+}
+
+func TestSettings_Clone(t *testing.T) {
+	settings := domain.NewSettings()
+	settings.SetDefaultAccountID("abc12345")
+	_ = settings.SetHistorySize(20)
+
+	cloned := settings.Clone()
+	if cloned.DefaultAccountID() != "abc12345" || cloned.HistorySize() != 20 {
+		t.Errorf("cloned = %s/%d, want abc12345/20", cloned.DefaultAccountID(), cloned.HistorySize())
+	}
+
+	cloned.SetDefaultAccountID("")
+	if settings.DefaultAccountID() != "abc12345" {
+		t.Error("changing the clone changed the original")
+	}
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxTombstones is how many tombstones are kept; recording more drops the oldest
+const MaxTombstones = 100
+
+// maxReasonLength is the longest removal reason, in characters, a tombstone can carry
+const maxReasonLength = 200
+
+// Tombstone records an account that was removed from ccx. Unlike an archived account,
+// the account and its credentials are gone; only this metadata remains, and it never
+// includes credentials.
+type Tombstone struct {
+	accountID AccountID
+	email     Email
+	alias     string
+	uuid      string
+	removedAt time.Time
+	reason    string
+}
+
+// NewTombstone records the removal of account at removedAt. The optional reason is
+// trimmed and, like a description, cannot contain control characters.
+func NewTombstone(account *Account, removedAt time.Time, reason string) (*Tombstone, error) {
+	return ReconstructTombstone(account.ID(), account.Email(), account.Alias(), account.UUID(), removedAt, reason)
+}
+
+// ReconstructTombstone rebuilds a tombstone from persisted data
+func ReconstructTombstone(id AccountID, email Email, alias, uuid string, removedAt time.Time, reason string) (*Tombstone, error) {
+	if id == "" {
+		return nil, errors.New("tombstone account ID cannot be empty")
+	}
+	if email == "" {
+		return nil, errors.New("tombstone email cannot be empty")
+	}
+	if removedAt.IsZero() {
+		return nil, errors.New("tombstone removal time cannot be zero")
+	}
+
+	reason = strings.TrimSpace(reason)
+	if !utf8.ValidString(reason) {
+		return nil, errors.New("removal reason must be valid UTF-8")
+	}
+	if strings.IndexFunc(reason, unicode.IsControl) >= 0 {
+		return nil, errors.New("removal reason cannot contain control characters")
+	}
+	if utf8.RuneCountInString(reason) > maxReasonLength {
+		return nil, fmt.Errorf("removal reason cannot be longer than %d characters", maxReasonLength)
+	}
+
+	return &Tombstone{
+		accountID: id,
+		email:     email,
+		alias:     alias,
+		uuid:      uuid,
+		removedAt: removedAt,
+		reason:    reason,
+	}, nil
+}
+
+// AccountID returns the ID the removed account had
+func (t *Tombstone) AccountID() AccountID {
+	return t.accountID
+}
+
+// Email returns the removed account's email
+func (t *Tombstone) Email() Email {
+	return t.email
+}
+
+// Alias returns the removed account's alias, or "" if it had none
+func (t *Tombstone) Alias() string {
+	return t.alias
+}
+
+// UUID returns the removed account's Claude UUID
+func (t *Tombstone) UUID() string {
+	return t.uuid
+}
+
+// RemovedAt returns when the account was removed
+func (t *Tombstone) RemovedAt() time.Time {
+	return t.removedAt
+}
+
+// Reason returns why the account was removed, or "" if no reason was given
+func (t *Tombstone) Reason() string {
+	return t.reason
+}
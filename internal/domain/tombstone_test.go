@@ -0,0 +1,36 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+func TestNewTombstone(t *testing.T) {
+	account, _ := domain.NewAccount("user@example.com", "work", "uuid-123")
+	removedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tombstone, err := domain.NewTombstone(account, removedAt, "  left the company ")
+	if err != nil {
+		t.Fatalf("NewTombstone() error = %v", err)
+	}
+	if tombstone.AccountID() != account.ID() || tombstone.Email() != "user@example.com" ||
+		tombstone.Alias() != "work" || tombstone.UUID() != "uuid-123" {
+		t.Errorf("Tombstone = %s/%s/%s/%s, want the account's metadata",
+			tombstone.AccountID(), tombstone.Email(), tombstone.Alias(), tombstone.UUID())
+	}
+	if !tombstone.RemovedAt().Equal(removedAt) || tombstone.Reason() != "left the company" {
+		t.Errorf("RemovedAt/Reason = %v/%q, want %v/trimmed reason", tombstone.RemovedAt(), tombstone.Reason(), removedAt)
+	}
+
+	for _, reason := range []string{"line\nbreak", "bad\xff", strings.Repeat("x", 201)} {
+		if _, err := domain.NewTombstone(account, removedAt, reason); err == nil {
+			t.Errorf("NewTombstone(reason %q) error = nil, want error", reason)
+		}
+	}
+	if _, err := domain.NewTombstone(account, time.Time{}, ""); err == nil {
+		t.Error("NewTombstone() with zero time error = nil, want error")
+	}
+}
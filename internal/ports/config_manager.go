@@ -16,4 +16,9 @@ type ConfigManager interface {
 	// SetCurrentAccount updates Claude config with the new account.
 	// Used by SwitchAccount and AddAccount use cases.
 	SetCurrentAccount(ctx context.Context, account *domain.Account) error
+
+	// SetCredentials decrypts creds and writes the session into Claude config so
+	// Claude authenticates as the account, replacing the previous account's session.
+	// Used by SwitchAccount use case.
+	SetCredentials(ctx context.Context, creds *domain.Credentials) error
 }
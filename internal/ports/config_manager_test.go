@@ -11,6 +11,7 @@ import (
 // mockConfigManager is a test implementation of ConfigManager
 type mockConfigManager struct {
 	currentAccount *domain.Account
+	credentials    *domain.Credentials
 	err            error
 }
 
@@ -33,6 +34,14 @@ func (m *mockConfigManager) SetCurrentAccount(_ context.Context, account *domain
 	return nil
 }
 
+func (m *mockConfigManager) SetCredentials(_ context.Context, creds *domain.Credentials) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.credentials = creds
+	return nil
+}
+
 // TestConfigManagerInterface validates the ConfigManager interface contract
 func TestConfigManagerInterface(t *testing.T) {
 	ctx := context.Background()
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"io/fs"
 
 	"github.com/evanschultz/ccx/internal/domain"
 )
@@ -13,8 +14,32 @@ type CredentialStore interface {
 	Store(ctx context.Context, creds *domain.Credentials) error
 
 	// Retrieve gets credentials for an account. Used by SwitchAccount use case.
+	// Returns domain.ErrCredentialsNotFound if none are stored.
 	Retrieve(ctx context.Context, accountID domain.AccountID) (*domain.Credentials, error)
 
 	// Delete removes credentials. Used by RemoveAccount use case.
 	Delete(ctx context.Context, accountID domain.AccountID) error
 }
+
+// CredentialLister is implemented by credential stores that can enumerate what they hold.
+// It is optional; use cases that need it check for it with a type assertion.
+type CredentialLister interface {
+	// ListAccountIDs returns the IDs of all accounts with stored credentials.
+	// Used by the Doctor use case to find orphaned credentials.
+	ListAccountIDs(ctx context.Context) ([]domain.AccountID, error)
+}
+
+// PermissionIssue describes a stored file or directory that other users can access
+type PermissionIssue struct {
+	Path string      // File or directory with loose permissions
+	Mode fs.FileMode // Mode found, including the directory bit for directories
+	Want fs.FileMode // Permissions it should have
+}
+
+// PermissionVerifier is implemented by credential stores kept on the filesystem.
+// It is optional; use cases that need it check for it with a type assertion.
+type PermissionVerifier interface {
+	// VerifyPermissions reports every credential file or directory readable, writable,
+	// or searchable by group or other users. Used by the Doctor use case.
+	VerifyPermissions(ctx context.Context) ([]PermissionIssue, error)
+}
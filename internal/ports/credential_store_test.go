@@ -2,7 +2,6 @@ package ports_test
 
 import (
 	"context"
-	"errors"
 	"testing"
 
 	"github.com/evanschultz/ccx/internal/domain"
@@ -35,7 +34,7 @@ func (m *mockCredentialStore) Retrieve(_ context.Context, accountID domain.Accou
 	}
 	creds, ok := m.credentials[accountID]
 	if !ok {
-		return nil, errors.New("credentials not found")
+		return nil, domain.ErrCredentialsNotFound
 	}
 	return creds, nil
 }
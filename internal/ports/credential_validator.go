@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+// CredentialValidator defines the interface for checking that Claude will accept stored
+// credentials. Implementations inspect the credentials offline, without calling Claude.
+type CredentialValidator interface {
+	// Validate returns an error if creds hold a session Claude would reject, such as a
+	// malformed or expired one. Used by SwitchAccount use case before switching.
+	Validate(ctx context.Context, creds *domain.Credentials) error
+}
@@ -0,0 +1,41 @@
+package ports_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// mockCredentialValidator is a test implementation of CredentialValidator
+type mockCredentialValidator struct {
+	rejected map[domain.AccountID]bool
+}
+
+func (m *mockCredentialValidator) Validate(_ context.Context, creds *domain.Credentials) error {
+	if m.rejected[creds.AccountID()] {
+		return errors.New("session expired")
+	}
+	return nil
+}
+
+// TestCredentialValidatorInterface validates the CredentialValidator interface contract
+func TestCredentialValidatorInterface(t *testing.T) {
+	ctx := context.Background()
+	validator := &mockCredentialValidator{rejected: map[domain.AccountID]bool{"bad12345": true}}
+
+	// Ensure it implements the interface
+	var _ ports.CredentialValidator = validator
+
+	good, _ := domain.NewCredentials("abc12345", []byte(`{"sessionKey":"sk-1"}`))
+	if err := validator.Validate(ctx, good); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+
+	bad, _ := domain.NewCredentials("bad12345", []byte(`{"sessionKey":"sk-2"}`))
+	if err := validator.Validate(ctx, bad); err == nil {
+		t.Error("Validate() error = nil, want error for rejected credentials")
+	}
+}
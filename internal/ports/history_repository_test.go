@@ -32,7 +32,7 @@ func (m *mockHistoryRepository) LoadHistory(_ context.Context) (*domain.History,
 	}
 	if m.history == nil {
 		// Return empty history if none exists
-		return domain.NewHistory(10), nil
+		return domain.NewHistory(domain.DefaultHistorySize), nil
 	}
 	return m.history, nil
 }
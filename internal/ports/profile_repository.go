@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+// ProfileRepository defines the interface for persisting named profiles.
+// FindByName and Delete return domain.ErrProfileNotFound when no profile matches.
+type ProfileRepository interface {
+	// Save persists a profile, replacing any profile with the same name.
+	// Used by CreateProfile use case.
+	Save(ctx context.Context, profile *domain.Profile) error
+
+	// FindByName retrieves a profile by name. Used by SwitchProfile use case.
+	FindByName(ctx context.Context, name string) (*domain.Profile, error)
+
+	// List returns all profiles sorted by name. Used by ListProfiles use case.
+	List(ctx context.Context) ([]*domain.Profile, error)
+
+	// Delete removes a profile. Used by DeleteProfile and RemoveAccount use cases.
+	Delete(ctx context.Context, name string) error
+}
@@ -0,0 +1,102 @@
+package ports_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// mockProfileRepository is a test implementation of ProfileRepository
+type mockProfileRepository struct {
+	profiles map[string]*domain.Profile
+	err      error
+}
+
+func newMockProfileRepository() *mockProfileRepository {
+	return &mockProfileRepository{
+		profiles: make(map[string]*domain.Profile),
+	}
+}
+
+func (m *mockProfileRepository) Save(_ context.Context, profile *domain.Profile) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.profiles[profile.Name()] = profile
+	return nil
+}
+
+func (m *mockProfileRepository) FindByName(_ context.Context, name string) (*domain.Profile, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	profile, ok := m.profiles[name]
+	if !ok {
+		return nil, domain.ErrProfileNotFound
+	}
+	return profile, nil
+}
+
+func (m *mockProfileRepository) List(_ context.Context) ([]*domain.Profile, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := make([]*domain.Profile, 0, len(m.profiles))
+	for _, profile := range m.profiles {
+		result = append(result, profile)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name() < result[j].Name() })
+	return result, nil
+}
+
+func (m *mockProfileRepository) Delete(_ context.Context, name string) error {
+	if m.err != nil {
+		return m.err
+	}
+	if _, ok := m.profiles[name]; !ok {
+		return domain.ErrProfileNotFound
+	}
+	delete(m.profiles, name)
+	return nil
+}
+
+// TestProfileRepositoryInterface validates the ProfileRepository interface contract
+func TestProfileRepositoryInterface(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockProfileRepository()
+
+	// Ensure it implements the interface
+	var _ ports.ProfileRepository = repo
+
+	// Test missing profile
+	if _, err := repo.FindByName(ctx, "client-a"); !errors.Is(err, domain.ErrProfileNotFound) {
+		t.Errorf("FindByName() error = %v, want ErrProfileNotFound", err)
+	}
+
+	// Test Save and List ordering
+	for _, name := range []string{"personal", "client-a"} {
+		profile, _ := domain.NewProfile(name, "abc12345")
+		if err := repo.Save(ctx, profile); err != nil {
+			t.Errorf("Save() error = %v", err)
+		}
+	}
+	profiles, err := repo.List(ctx)
+	if err != nil {
+		t.Errorf("List() error = %v", err)
+	}
+	if len(profiles) != 2 || profiles[0].Name() != "client-a" {
+		t.Errorf("List() = %v, want client-a first of 2", profiles)
+	}
+
+	// Test Delete
+	if err := repo.Delete(ctx, "client-a"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	if err := repo.Delete(ctx, "client-a"); !errors.Is(err, domain.ErrProfileNotFound) {
+		t.Errorf("Delete() of missing profile error = %v, want ErrProfileNotFound", err)
+	}
+}
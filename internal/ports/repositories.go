@@ -11,6 +11,7 @@ import (
 
 // AccountRepository defines the interface for account persistence.
 // Each method is designed to support specific use case needs.
+// Finders and Delete return domain.ErrAccountNotFound when no account matches.
 type AccountRepository interface {
 	// Save persists an account. Used by AddAccount use case.
 	Save(ctx context.Context, account *domain.Account) error
@@ -24,9 +25,59 @@ type AccountRepository interface {
 	// FindByAlias retrieves an account by alias. Used by quick-switch feature.
 	FindByAlias(ctx context.Context, alias string) (*domain.Account, error)
 
+	// FindByUUID retrieves an account by its Claude account UUID. Used by AddAccount
+	// use case to detect the same Claude account added under another email.
+	FindByUUID(ctx context.Context, uuid string) (*domain.Account, error)
+
 	// List returns all accounts. Used by ListAccounts use case.
 	List(ctx context.Context) ([]*domain.Account, error)
 
 	// Delete removes an account. Used by RemoveAccount use case.
 	Delete(ctx context.Context, id domain.AccountID) error
 }
+
+// AccountSortField selects the column an AccountPager orders accounts by
+type AccountSortField string
+
+// Sort fields an AccountPager must support
+const (
+	AccountSortEmail AccountSortField = "email"
+	AccountSortAlias AccountSortField = "alias"
+)
+
+// AccountPageQuery selects a filtered, ordered window of accounts
+type AccountPageQuery struct {
+	SortBy          AccountSortField // Field to order by; ties are broken by email
+	Descending      bool             // Reverse the order, including the tie-break
+	AliasContains   string           // Keep only aliases containing this, case-insensitively
+	EmailContains   string           // Keep only emails containing this, case-insensitively
+	IncludeArchived bool             // Also match archived accounts
+	Offset          int              // Matching accounts to skip
+	Limit           int              // Most accounts to return, or 0 for no limit
+}
+
+// AccountPager is implemented by account repositories that can filter, order, and page
+// accounts in storage instead of loading them all. Strings are compared byte-wise.
+// It is optional; use cases that need it check for it with a type assertion.
+type AccountPager interface {
+	// ListPage returns the accounts in the query's window along with how many accounts
+	// match it across all pages. Used by the ListAccounts use case.
+	ListPage(ctx context.Context, query AccountPageQuery) ([]*domain.Account, int, error)
+}
+
+// AccountRecordIssue describes a stored account record that is malformed or repeats
+// another record's ID, such as after a hand-edit
+type AccountRecordIssue struct {
+	Index     int              // Position of the record in storage, starting at 0
+	AccountID domain.AccountID // ID of the record, empty if it has none
+	Problems  []string         // Every problem found in the record
+}
+
+// AccountRecordValidator is implemented by account repositories that skip malformed or
+// duplicated records instead of failing outright.
+// It is optional; use cases that need it check for it with a type assertion.
+type AccountRecordValidator interface {
+	// LoadWarnings checks every stored record and reports those List skips.
+	// Used by the Doctor use case.
+	LoadWarnings(ctx context.Context) ([]AccountRecordIssue, error)
+}
@@ -2,7 +2,6 @@ package ports_test
 
 import (
 	"context"
-	"errors"
 	"testing"
 
 	"github.com/evanschultz/ccx/internal/domain"
@@ -35,7 +34,7 @@ func (m *mockAccountRepository) FindByID(_ context.Context, id domain.AccountID)
 	}
 	account, ok := m.accounts[id]
 	if !ok {
-		return nil, errors.New("account not found")
+		return nil, domain.ErrAccountNotFound
 	}
 	return account, nil
 }
@@ -44,12 +43,13 @@ func (m *mockAccountRepository) FindByEmail(_ context.Context, email domain.Emai
 	if m.err != nil {
 		return nil, m.err
 	}
+	email = domain.NormalizeEmail(string(email))
 	for _, account := range m.accounts {
 		if account.Email() == email {
 			return account, nil
 		}
 	}
-	return nil, errors.New("account not found")
+	return nil, domain.ErrAccountNotFound
 }
 
 func (m *mockAccountRepository) FindByAlias(_ context.Context, alias string) (*domain.Account, error) {
@@ -61,7 +61,19 @@ func (m *mockAccountRepository) FindByAlias(_ context.Context, alias string) (*d
 			return account, nil
 		}
 	}
-	return nil, errors.New("account not found")
+	return nil, domain.ErrAccountNotFound
+}
+
+func (m *mockAccountRepository) FindByUUID(_ context.Context, uuid string) (*domain.Account, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, account := range m.accounts {
+		if account.UUID() == uuid {
+			return account, nil
+		}
+	}
+	return nil, domain.ErrAccountNotFound
 }
 
 func (m *mockAccountRepository) List(_ context.Context) ([]*domain.Account, error) {
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+// SettingsRepository defines the interface for persisting ccx's own settings.
+// Unlike ConfigManager, this never touches Claude's configuration.
+type SettingsRepository interface {
+	// LoadSettings retrieves the settings. Returns empty settings if none are saved.
+	LoadSettings(ctx context.Context) (*domain.Settings, error)
+
+	// SaveSettings persists the complete settings. Used by SetDefaultAccount use case.
+	SaveSettings(ctx context.Context, settings *domain.Settings) error
+}
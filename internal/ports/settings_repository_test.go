@@ -0,0 +1,74 @@
+package ports_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// mockSettingsRepository is a test implementation of SettingsRepository
+type mockSettingsRepository struct {
+	settings *domain.Settings
+	err      error
+}
+
+func newMockSettingsRepository() *mockSettingsRepository {
+	return &mockSettingsRepository{}
+}
+
+func (m *mockSettingsRepository) LoadSettings(_ context.Context) (*domain.Settings, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.settings == nil {
+		// Return empty settings if none exist
+		return domain.NewSettings(), nil
+	}
+	return m.settings, nil
+}
+
+func (m *mockSettingsRepository) SaveSettings(_ context.Context, settings *domain.Settings) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.settings = settings
+	return nil
+}
+
+// TestSettingsRepositoryInterface validates the SettingsRepository interface contract
+func TestSettingsRepositoryInterface(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockSettingsRepository()
+
+	// Ensure it implements the interface
+	var _ ports.SettingsRepository = repo
+
+	// Test loading empty settings
+	settings, err := repo.LoadSettings(ctx)
+	if err != nil {
+		t.Errorf("LoadSettings() error = %v", err)
+	}
+	if settings == nil {
+		t.Fatal("LoadSettings() should return empty settings, not nil")
+	}
+	if settings.HasDefaultAccount() {
+		t.Error("LoadSettings() should return settings without a default initially")
+	}
+
+	// Test SaveSettings
+	settings.SetDefaultAccountID("abc12345")
+	if err := repo.SaveSettings(ctx, settings); err != nil {
+		t.Errorf("SaveSettings() error = %v", err)
+	}
+
+	// Test LoadSettings after saving
+	loaded, err := repo.LoadSettings(ctx)
+	if err != nil {
+		t.Errorf("LoadSettings() error = %v", err)
+	}
+	if loaded.DefaultAccountID() != "abc12345" {
+		t.Errorf("LoadSettings() default = %v, want abc12345", loaded.DefaultAccountID())
+	}
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+// TombstoneRepository defines the interface for persisting records of removed accounts.
+type TombstoneRepository interface {
+	// Add records a tombstone, dropping the oldest once more than domain.MaxTombstones
+	// are kept. Used by RemoveAccount use case.
+	Add(ctx context.Context, tombstone *domain.Tombstone) error
+
+	// List returns the kept tombstones, most recently removed first. Returns an empty
+	// slice if there are none.
+	List(ctx context.Context) ([]*domain.Tombstone, error)
+}
@@ -0,0 +1,51 @@
+package ports_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// mockTombstoneRepository is a test implementation of TombstoneRepository
+type mockTombstoneRepository struct {
+	tombstones []*domain.Tombstone // Most recent first
+}
+
+func (m *mockTombstoneRepository) Add(_ context.Context, tombstone *domain.Tombstone) error {
+	m.tombstones = append([]*domain.Tombstone{tombstone}, m.tombstones...)
+	if len(m.tombstones) > domain.MaxTombstones {
+		m.tombstones = m.tombstones[:domain.MaxTombstones]
+	}
+	return nil
+}
+
+func (m *mockTombstoneRepository) List(_ context.Context) ([]*domain.Tombstone, error) {
+	return append([]*domain.Tombstone{}, m.tombstones...), nil
+}
+
+// TestTombstoneRepositoryInterface validates the TombstoneRepository interface contract
+func TestTombstoneRepositoryInterface(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockTombstoneRepository{}
+
+	// Ensure it implements the interface
+	var _ ports.TombstoneRepository = repo
+
+	tombstones, err := repo.List(ctx)
+	if err != nil || tombstones == nil || len(tombstones) != 0 {
+		t.Errorf("List() = %v, %v; want an empty slice", tombstones, err)
+	}
+
+	account, _ := domain.NewAccount("user@example.com", "work", "uuid-1")
+	tombstone, _ := domain.NewTombstone(account, time.Now(), "left the company")
+	if err := repo.Add(ctx, tombstone); err != nil {
+		t.Errorf("Add() error = %v", err)
+	}
+	tombstones, _ = repo.List(ctx)
+	if len(tombstones) != 1 || tombstones[0].Email() != "user@example.com" {
+		t.Errorf("List() = %v, want the added tombstone", tombstones)
+	}
+}
@@ -0,0 +1,284 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// AccountSelector names an account by exactly one of its identifiers. Index and Prefix
+// only consider unarchived accounts.
+type AccountSelector struct {
+	AccountID string // Direct ID lookup
+	Email     string // Email lookup
+	Alias     string // Alias lookup
+	Index     int    // Position in the account list (1-based)
+	Prefix    string // Unambiguous prefix of an alias or email
+}
+
+// count returns how many identifiers the selector sets
+func (sel AccountSelector) count() int {
+	n := 0
+	if sel.AccountID != "" {
+		n++
+	}
+	if sel.Email != "" {
+		n++
+	}
+	if sel.Alias != "" {
+		n++
+	}
+	if sel.Index > 0 {
+		n++
+	}
+	if sel.Prefix != "" {
+		n++
+	}
+	return n
+}
+
+// Validate checks that exactly one identifier is set
+func (sel AccountSelector) Validate() error {
+	switch sel.count() {
+	case 0:
+		return errors.New("no account identifier provided")
+	case 1:
+		return nil
+	default:
+		return errors.New("multiple account identifiers provided; use only one")
+	}
+}
+
+// AmbiguousPrefixError is returned when a Prefix matches more than one account
+type AmbiguousPrefixError struct {
+	Prefix     string        // Prefix that was looked up
+	Candidates []AccountInfo // Every account that matched, sorted by email
+}
+
+func (e *AmbiguousPrefixError) Error() string {
+	names := make([]string, len(e.Candidates))
+	for i, candidate := range e.Candidates {
+		names[i] = candidate.Email
+		if candidate.Alias != "" {
+			names[i] = candidate.Alias + " <" + candidate.Email + ">"
+		}
+	}
+	return fmt.Sprintf("prefix %q matches %d accounts: %s", e.Prefix, len(e.Candidates), strings.Join(names, ", "))
+}
+
+// maxSuggestions is the most near-miss names an AccountNotFoundError carries
+const maxSuggestions = 3
+
+// AccountNotFoundError is returned when an alias or email names no account. It wraps
+// domain.ErrAccountNotFound and carries the closest names, if any, so the presentation
+// layer can offer them.
+type AccountNotFoundError struct {
+	Target      string   // Alias or email that was looked up
+	Suggestions []string // Closest aliases or emails, best first; empty if none are close
+}
+
+func (e *AccountNotFoundError) Error() string {
+	msg := fmt.Sprintf("%v: %q", domain.ErrAccountNotFound, e.Target)
+	if len(e.Suggestions) == 0 {
+		return msg
+	}
+	quoted := make([]string, len(e.Suggestions))
+	for i, suggestion := range e.Suggestions {
+		quoted[i] = "'" + suggestion + "'"
+	}
+	return msg + "; did you mean " + strings.Join(quoted, ", ") + "?"
+}
+
+func (e *AccountNotFoundError) Unwrap() error {
+	return domain.ErrAccountNotFound
+}
+
+// AccountResolver finds the account an AccountSelector names. Use cases that let the
+// user pick an account share it so every command accepts the same identifiers.
+type AccountResolver struct {
+	accounts ports.AccountRepository
+}
+
+// NewAccountResolver creates a new AccountResolver
+func NewAccountResolver(accounts ports.AccountRepository) *AccountResolver {
+	return &AccountResolver{
+		accounts: accounts,
+	}
+}
+
+// Resolve validates sel and returns the account it names, archived or not. Lookups
+// that find nothing return domain.ErrAccountNotFound; for an alias or email it comes as
+// an *AccountNotFoundError with suggestions. A Prefix matching several accounts returns
+// an *AmbiguousPrefixError.
+func (r *AccountResolver) Resolve(ctx context.Context, sel AccountSelector) (*domain.Account, error) {
+	if err := sel.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case sel.AccountID != "":
+		account, err := r.accounts.FindByID(ctx, domain.AccountID(sel.AccountID))
+		if err != nil && !errors.Is(err, domain.ErrAccountNotFound) {
+			return nil, fmt.Errorf("failed to find account: %w", err)
+		}
+		return account, err
+	case sel.Email != "":
+		account, err := r.accounts.FindByEmail(ctx, domain.Email(sel.Email))
+		return r.withSuggestions(ctx, sel.Email, account, err)
+	case sel.Alias != "":
+		account, err := r.accounts.FindByAlias(ctx, sel.Alias)
+		return r.withSuggestions(ctx, sel.Alias, account, err)
+	case sel.Index > 0:
+		return r.findByIndex(ctx, sel.Index)
+	default:
+		return r.findByPrefix(ctx, sel.Prefix)
+	}
+}
+
+// withSuggestions turns a not-found lookup of target into an *AccountNotFoundError
+// naming the closest accounts. Other failures are wrapped as storage errors.
+func (r *AccountResolver) withSuggestions(ctx context.Context, target string, account *domain.Account, err error) (*domain.Account, error) {
+	if err == nil {
+		return account, nil
+	}
+	if !errors.Is(err, domain.ErrAccountNotFound) {
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+	accounts, listErr := r.accounts.List(ctx)
+	if listErr != nil {
+		// Suggestions are a courtesy; report the lookup failure itself
+		return nil, err
+	}
+	return nil, &AccountNotFoundError{Target: target, Suggestions: suggestAccounts(target, accounts)}
+}
+
+// suggestAccounts returns up to maxSuggestions aliases or emails closest to target by
+// edit distance, ignoring case, best first. An account is compared by its alias, its
+// email, and the email's local part, and suggested by whichever of its alias or email
+// is closer. Names further than a third of target's length, or 2 edits for short
+// targets, are not suggested.
+func suggestAccounts(target string, accounts []*domain.Account) []string {
+	target = strings.ToLower(target)
+	threshold := max(2, utf8.RuneCountInString(target)/3)
+
+	type suggestion struct {
+		name     string
+		distance int
+	}
+	var nearby []suggestion
+	for _, account := range accounts {
+		email := string(account.Email())
+		local, _, _ := strings.Cut(email, "@")
+		best := suggestion{name: email, distance: min(levenshtein(target, email), levenshtein(target, local))}
+		if alias := account.Alias(); alias != "" {
+			if d := levenshtein(target, strings.ToLower(alias)); d <= best.distance {
+				best = suggestion{name: alias, distance: d}
+			}
+		}
+		if best.distance <= threshold {
+			nearby = append(nearby, best)
+		}
+	}
+
+	slices.SortFunc(nearby, func(a, b suggestion) int {
+		return cmp.Or(cmp.Compare(a.distance, b.distance), cmp.Compare(a.name, b.name))
+	})
+	names := make([]string, 0, min(len(nearby), maxSuggestions))
+	for _, s := range nearby[:min(len(nearby), maxSuggestions)] {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// levenshtein returns the number of single-rune insertions, deletions, and
+// substitutions that turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// findByIndex finds an account by its position (1-based) in the default account listing:
+// unarchived accounts sorted by email
+func (r *AccountResolver) findByIndex(ctx context.Context, index int) (*domain.Account, error) {
+	if index <= 0 {
+		return nil, fmt.Errorf("invalid index %d: must be positive", index)
+	}
+
+	accounts, err := r.accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	// Number accounts as the default listing does, so an index names the row shown
+	accounts = unarchived(accounts)
+	compare, _ := accountComparator("")
+	sortAccounts(accounts, compare, false)
+
+	if index > len(accounts) {
+		return nil, fmt.Errorf("%w: index %d out of range (have %d accounts)", domain.ErrAccountNotFound, index, len(accounts))
+	}
+
+	// Convert 1-based to 0-based index
+	return accounts[index-1], nil
+}
+
+// findByPrefix resolves the single account whose alias or email starts with prefix,
+// ignoring case
+func (r *AccountResolver) findByPrefix(ctx context.Context, prefix string) (*domain.Account, error) {
+	accounts, err := r.accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	lowered := strings.ToLower(prefix)
+	var matches []*domain.Account
+	for _, account := range unarchived(accounts) {
+		if strings.HasPrefix(strings.ToLower(account.Alias()), lowered) ||
+			strings.HasPrefix(string(account.Email()), lowered) {
+			matches = append(matches, account)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: no account matches prefix %q", domain.ErrAccountNotFound, prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		slices.SortFunc(matches, func(a, b *domain.Account) int { return cmp.Compare(a.Email(), b.Email()) })
+		candidates := make([]AccountInfo, len(matches))
+		for i, account := range matches {
+			candidates[i] = newAccountInfo(account)
+		}
+		return nil, &AmbiguousPrefixError{Prefix: prefix, Candidates: candidates}
+	}
+}
+
+// unarchived returns the accounts that are not archived, keeping their order
+func unarchived(accounts []*domain.Account) []*domain.Account {
+	return slices.DeleteFunc(accounts, (*domain.Account).Archived)
+}
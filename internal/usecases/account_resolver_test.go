@@ -0,0 +1,147 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// TestAccountResolver_Resolve tests each way of selecting an account
+func TestAccountResolver_Resolve(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	resolver := usecases.NewAccountResolver(setup.accountRepo)
+	work := setup.testAccounts["work"]
+
+	tests := []struct {
+		name     string
+		selector usecases.AccountSelector
+	}{
+		{name: "ID", selector: usecases.AccountSelector{AccountID: string(work.ID())}},
+		{name: "email", selector: usecases.AccountSelector{Email: testEmailWork}},
+		{name: "alias", selector: usecases.AccountSelector{Alias: "work"}},
+		{name: "prefix", selector: usecases.AccountSelector{Prefix: "wo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, err := resolver.Resolve(context.Background(), tt.selector)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v, want nil", err)
+			}
+			if account.ID() != work.ID() {
+				t.Errorf("Resolve() = %s, want %s", account.Email(), work.Email())
+			}
+		})
+	}
+}
+
+// TestAccountResolver_Resolve_Errors tests selector validation and failed lookups
+func TestAccountResolver_Resolve_Errors(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	resolver := usecases.NewAccountResolver(setup.accountRepo)
+	ctx := context.Background()
+
+	_, err := resolver.Resolve(ctx, usecases.AccountSelector{})
+	if err == nil || err.Error() != "no account identifier provided" {
+		t.Errorf("Resolve() with no identifier error = %v", err)
+	}
+
+	_, err = resolver.Resolve(ctx, usecases.AccountSelector{Alias: "work", Index: 1})
+	if err == nil || err.Error() != "multiple account identifiers provided; use only one" {
+		t.Errorf("Resolve() with two identifiers error = %v", err)
+	}
+
+	_, err = resolver.Resolve(ctx, usecases.AccountSelector{Alias: "missing"})
+	if !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("Resolve() unknown alias error = %v, want ErrAccountNotFound", err)
+	}
+}
+
+// TestAccountResolver_Resolve_Suggestions tests that a mistyped alias or email names the
+// closest accounts
+func TestAccountResolver_Resolve_Suggestions(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	resolver := usecases.NewAccountResolver(setup.accountRepo)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		selector usecases.AccountSelector
+		want     []string
+	}{
+		{name: "dropped letter", selector: usecases.AccountSelector{Alias: "wrk"}, want: []string{"work"}},
+		{name: "swapped letters", selector: usecases.AccountSelector{Alias: "Persnoal"}, want: []string{"personal"}},
+		{name: "email local part", selector: usecases.AccountSelector{Alias: "tests"}, want: []string{"test"}},
+		{name: "email typo", selector: usecases.AccountSelector{Email: "work@exmaple.com"}, want: []string{testEmailWork}},
+		{name: "nothing close", selector: usecases.AccountSelector{Alias: "completely-different"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolver.Resolve(ctx, tt.selector)
+			if !errors.Is(err, domain.ErrAccountNotFound) {
+				t.Fatalf("Resolve() error = %v, want ErrAccountNotFound", err)
+			}
+			var notFound *usecases.AccountNotFoundError
+			if !errors.As(err, &notFound) {
+				t.Fatalf("Resolve() error = %T, want *AccountNotFoundError", err)
+			}
+			if !slices.Equal(notFound.Suggestions, tt.want) {
+				t.Errorf("Suggestions = %v, want %v", notFound.Suggestions, tt.want)
+			}
+		})
+	}
+
+	_, err := resolver.Resolve(ctx, usecases.AccountSelector{Alias: "wrk"})
+	if err == nil || err.Error() != `account not found: "wrk"; did you mean 'work'?` {
+		t.Errorf("Resolve() error message = %v", err)
+	}
+}
+
+// orderedAccountRepository lists accounts in a fixed storage order
+type orderedAccountRepository struct {
+	*mockAccountRepository
+	order []*domain.Account
+}
+
+func (m *orderedAccountRepository) List(_ context.Context) ([]*domain.Account, error) {
+	return slices.Clone(m.order), nil
+}
+
+// TestAccountResolver_Resolve_IndexFollowsListing tests that an index names the same
+// account as that row of the default listing, whatever the storage order
+func TestAccountResolver_Resolve_IndexFollowsListing(t *testing.T) {
+	ctx := context.Background()
+	zed, _ := domain.NewAccount("zed@example.com", "zed", "uuid-zed")
+	amy, _ := domain.NewAccount("amy@example.com", "amy", "uuid-amy")
+	old, _ := domain.NewAccount("bob@example.com", "bob", "uuid-bob")
+	old.Archive(time.Now())
+	mike, _ := domain.NewAccount("mike@example.com", "mike", "uuid-mike")
+	repo := &orderedAccountRepository{
+		mockAccountRepository: newMockAccountRepository(),
+		order:                 []*domain.Account{zed, old, mike, amy},
+	}
+
+	listed, err := usecases.NewListAccountsService(repo).Execute(ctx)
+	if err != nil {
+		t.Fatalf("ListAccounts() error = %v", err)
+	}
+	resolver := usecases.NewAccountResolver(repo)
+	for i, info := range listed {
+		account, err := resolver.Resolve(ctx, usecases.AccountSelector{Index: i + 1})
+		if err != nil {
+			t.Fatalf("Resolve(Index: %d) error = %v", i+1, err)
+		}
+		if string(account.Email()) != info.Email {
+			t.Errorf("Resolve(Index: %d) = %s, want %s as listed", i+1, account.Email(), info.Email)
+		}
+	}
+	if len(listed) != 3 {
+		t.Errorf("Listed %d accounts, want 3 unarchived", len(listed))
+	}
+}
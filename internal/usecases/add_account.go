@@ -4,6 +4,7 @@ package usecases
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -21,10 +22,14 @@ type AddAccountUseCase interface {
 type AddAccountInput struct {
 	// If Email is provided, use it; otherwise read from Claude config
 	Email string
-	// If Alias is provided, use it; otherwise generate from email
+	// If Alias is provided, use it; otherwise one is generated from the email by the
+	// service's AliasGenerator and made unique with a numeric suffix
 	Alias string
-	// If Credentials is provided, use it; otherwise read from Claude config
+	// Credentials is the session JSON to store; it must contain a non-empty sessionKey
 	Credentials []byte
+	// AllowDuplicateUUID adds the account even if another account has the same Claude
+	// account UUID, reporting the collision as a warning instead of failing
+	AllowDuplicateUUID bool
 }
 
 // AddAccountService implements the AddAccountUseCase
@@ -32,6 +37,35 @@ type AddAccountService struct {
 	accounts    ports.AccountRepository
 	credentials ports.CredentialStore
 	config      ports.ConfigManager
+	aliases     AliasGenerator
+	events      EventSink
+	masterKey   []byte // Seals new credentials under envelope encryption when set
+}
+
+// AddAccountOption configures optional AddAccountService behavior
+type AddAccountOption func(*AddAccountService)
+
+// WithAddEvents reports added accounts and warnings to sink
+func WithAddEvents(sink EventSink) AddAccountOption {
+	return func(s *AddAccountService) {
+		s.events = sink
+	}
+}
+
+// WithAliasGenerator generates aliases for accounts added without one using gen
+// instead of AliasFromLocalPart
+func WithAliasGenerator(gen AliasGenerator) AddAccountOption {
+	return func(s *AddAccountService) {
+		s.aliases = gen
+	}
+}
+
+// WithAddMasterKey stores the credentials of new accounts under envelope encryption,
+// with a data key wrapped by masterKey, instead of the legacy account-derived key
+func WithAddMasterKey(masterKey []byte) AddAccountOption {
+	return func(s *AddAccountService) {
+		s.masterKey = append([]byte(nil), masterKey...)
+	}
 }
 
 // NewAddAccountService creates a new AddAccountService
@@ -39,116 +73,195 @@ func NewAddAccountService(
 	accounts ports.AccountRepository,
 	credentials ports.CredentialStore,
 	config ports.ConfigManager,
+	opts ...AddAccountOption,
 ) AddAccountUseCase {
-	return &AddAccountService{
+	return newAddAccountService(accounts, credentials, config, opts...)
+}
+
+// newAddAccountService builds the concrete service, for use cases that add accounts themselves
+func newAddAccountService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+	config ports.ConfigManager,
+	opts ...AddAccountOption,
+) *AddAccountService {
+	s := &AddAccountService{
 		accounts:    accounts,
 		credentials: credentials,
 		config:      config,
+		aliases:     AliasFromLocalPart,
+		events:      NopEventSink{},
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // Execute adds a new account to ccx
 func (s *AddAccountService) Execute(ctx context.Context, input AddAccountInput) error {
 	// Step 1: Determine account details
-	email, uuid, credentialData, err := s.determineAccountDetails(ctx, input)
+	email, uuid, rawOAuth, credentialData, err := s.determineAccountDetails(ctx, input)
 	if err != nil {
 		return err
 	}
 
-	// Step 2: Check if account already exists
+	// Refuse credentials that would leave Claude without a working session after a switch
+	if err := domain.ValidateCredentialData(credentialData); err != nil {
+		return err
+	}
+
+	// Step 2: Check if account already exists, under this email or another one
 	if err := s.checkAccountExists(ctx, email); err != nil {
 		return err
 	}
+	if err := s.checkUUIDAvailable(ctx, uuid); err != nil {
+		if !input.AllowDuplicateUUID {
+			return err
+		}
+		s.events.OnWarning(err)
+	}
 
-	// Step 3: Generate alias if not provided
-	alias := s.generateAlias(input.Alias, email)
+	// Step 3: Generate alias if not provided, and make sure no other account has it,
+	// since alias-based switching needs aliases to be unique
+	alias := input.Alias
+	if alias == "" {
+		alias, err = s.generateAlias(ctx, domain.NormalizeEmail(email))
+	} else {
+		err = checkAliasAvailable(ctx, s.accounts, "", alias)
+	}
+	if err != nil {
+		return err
+	}
 
 	// Step 4: Create and save account with credentials
-	return s.createAndSaveAccount(ctx, email, alias, uuid, credentialData)
+	account, err := s.createAndSaveAccount(ctx, email, alias, uuid, rawOAuth, credentialData)
+	if err != nil {
+		return err
+	}
+
+	s.events.OnAccountAdded(newAccountInfo(account))
+	return nil
 }
 
-// determineAccountDetails resolves email, uuid, and credentials from input or Claude config
-func (s *AddAccountService) determineAccountDetails(ctx context.Context, input AddAccountInput) (string, string, []byte, error) {
+// determineAccountDetails resolves email, uuid, the oauthAccount blob, and credentials
+// from input or Claude config
+func (s *AddAccountService) determineAccountDetails(ctx context.Context, input AddAccountInput) (string, string, json.RawMessage, []byte, error) {
 	if input.Email != "" {
 		// Use explicit input
 		if len(input.Credentials) == 0 {
-			return "", "", nil, errors.New("credentials must be provided when email is specified explicitly")
+			return "", "", nil, nil, errors.New("credentials must be provided when email is specified explicitly")
 		}
 		uuid := "explicit-" + strings.ReplaceAll(input.Email, "@", "-")
-		return input.Email, uuid, input.Credentials, nil
+		return input.Email, uuid, nil, input.Credentials, nil
 	}
 
 	// Get from Claude config
 	currentAccount, err := s.config.GetCurrentAccount(ctx)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to get current Claude account: %w", err)
+		return "", "", nil, nil, fmt.Errorf("failed to get current Claude account: %w", err)
 	}
 	if currentAccount == nil {
-		return "", "", nil, errors.New("no current Claude account found - please configure Claude or provide explicit email")
+		return "", "", nil, nil, fmt.Errorf("%w - please configure Claude or provide explicit email", domain.ErrNoCurrentAccount)
 	}
 
 	email := string(currentAccount.Email())
 	uuid := currentAccount.UUID()
 
-	// Use provided credentials or generate placeholder
-	var credentialData []byte
-	if len(input.Credentials) > 0 {
-		credentialData = input.Credentials
-	} else {
-		credentialData = []byte(fmt.Sprintf(`{"account_id": "%s", "session_key": "placeholder"}`, uuid))
+	// Claude config carries no session credentials, so they must always be provided
+	if len(input.Credentials) == 0 {
+		return "", "", nil, nil, fmt.Errorf("credentials must be provided for %s: Claude config does not include a session key", email)
 	}
 
-	return email, uuid, credentialData, nil
+	return email, uuid, currentAccount.RawOAuth(), input.Credentials, nil
 }
 
 // checkAccountExists verifies the account doesn't already exist
 func (s *AddAccountService) checkAccountExists(ctx context.Context, email string) error {
-	_, err := s.accounts.FindByEmail(ctx, domain.Email(email))
-	if err == nil {
-		return fmt.Errorf("account with email %s already exists", email)
+	existing, err := s.accounts.FindByEmail(ctx, domain.Email(email))
+	switch {
+	case err == nil && existing.Archived():
+		return fmt.Errorf("%w: %s is archived; unarchive it instead", domain.ErrDuplicateAccount, email)
+	case err == nil:
+		return fmt.Errorf("%w: %s", domain.ErrDuplicateAccount, email)
+	case errors.Is(err, domain.ErrAccountNotFound):
+		return nil
+	default:
+		return fmt.Errorf("failed to check for existing account: %w", err)
 	}
-	return nil
 }
 
-// generateAlias creates an alias from email if not provided
-func (s *AddAccountService) generateAlias(inputAlias, email string) string {
-	if inputAlias != "" {
-		return inputAlias
+// checkUUIDAvailable returns domain.ErrDuplicateUUID if an account already has the
+// Claude account UUID, such as one re-added under a new email after a rename
+func (s *AddAccountService) checkUUIDAvailable(ctx context.Context, uuid string) error {
+	existing, err := s.accounts.FindByUUID(ctx, uuid)
+	switch {
+	case err == nil:
+		return fmt.Errorf("%w: %s is used by %s", domain.ErrDuplicateUUID, uuid, existing.Email())
+	case errors.Is(err, domain.ErrAccountNotFound):
+		return nil
+	default:
+		return fmt.Errorf("failed to check for existing account UUID: %w", err)
 	}
-	// Generate alias from email (part before @)
-	parts := strings.Split(email, "@")
-	if len(parts) > 0 {
-		return parts[0]
+}
+
+// generateAlias proposes an alias for email and, if another account already has it,
+// appends -2, -3, and so on until it is unique
+func (s *AddAccountService) generateAlias(ctx context.Context, email domain.Email) (string, error) {
+	base := s.aliases.GenerateAlias(email)
+	if base == "" {
+		return "", nil
+	}
+
+	alias := base
+	for n := 2; ; n++ {
+		err := checkAliasAvailable(ctx, s.accounts, "", alias)
+		if !errors.Is(err, domain.ErrDuplicateAlias) {
+			return alias, err
+		}
+		if n > maxAliasSuffix {
+			return "", fmt.Errorf("%w; provide a different alias", err)
+		}
+		alias = fmt.Sprintf("%s-%d", base, n)
 	}
-	return ""
 }
 
 // createAndSaveAccount creates the account and credentials, saving them with cleanup on failure
-func (s *AddAccountService) createAndSaveAccount(ctx context.Context, email, alias, uuid string, credentialData []byte) error {
+func (s *AddAccountService) createAndSaveAccount(ctx context.Context, email, alias, uuid string, rawOAuth json.RawMessage, credentialData []byte) (*domain.Account, error) {
 	// Create account entity
 	account, err := domain.NewAccount(email, alias, uuid)
 	if err != nil {
-		return fmt.Errorf("failed to create account: %w", err)
+		return nil, fmt.Errorf("failed to create account: %w", err)
+	}
+	if err := account.SetRawOAuth(rawOAuth); err != nil {
+		return nil, fmt.Errorf("failed to create account: %w", err)
 	}
 
 	// Create and store credentials
-	credentials, err := domain.NewCredentials(account.ID(), credentialData)
+	var credentials *domain.Credentials
+	if s.masterKey != nil {
+		credentials, err = domain.NewCredentialsEnvelope(account.ID(), credentialData, s.masterKey)
+	} else {
+		credentials, err = domain.NewCredentials(account.ID(), credentialData)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create credentials: %w", err)
+		return nil, fmt.Errorf("failed to create credentials: %w", err)
 	}
 
 	err = s.credentials.Store(ctx, credentials)
 	if err != nil {
-		return fmt.Errorf("failed to store credentials: %w", err)
+		return nil, fmt.Errorf("failed to store credentials: %w", err)
 	}
 
 	// Save account (after credentials to ensure atomic-like behavior)
 	err = s.accounts.Save(ctx, account)
 	if err != nil {
 		// Clean up credentials on account save failure
-		_ = s.credentials.Delete(ctx, account.ID())
-		return fmt.Errorf("failed to save account: %w", err)
+		if delErr := s.credentials.Delete(ctx, account.ID()); delErr != nil {
+			s.events.OnWarning(fmt.Errorf("failed to clean up credentials for %s: %w", email, delErr))
+		}
+		return nil, fmt.Errorf("failed to save account: %w", err)
 	}
 
-	return nil
+	return account, nil
 }
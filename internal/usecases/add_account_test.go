@@ -2,7 +2,9 @@ package usecases_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/evanschultz/ccx/internal/domain"
@@ -36,7 +38,7 @@ func (m *mockAccountRepository) FindByID(_ context.Context, id domain.AccountID)
 	}
 	account, ok := m.accounts[id]
 	if !ok {
-		return nil, errors.New("account not found")
+		return nil, domain.ErrAccountNotFound
 	}
 	return account, nil
 }
@@ -45,12 +47,13 @@ func (m *mockAccountRepository) FindByEmail(_ context.Context, email domain.Emai
 	if m.findErr != nil {
 		return nil, m.findErr
 	}
+	email = domain.NormalizeEmail(string(email))
 	for _, account := range m.accounts {
 		if account.Email() == email {
 			return account, nil
 		}
 	}
-	return nil, errors.New("account not found")
+	return nil, domain.ErrAccountNotFound
 }
 
 func (m *mockAccountRepository) FindByAlias(_ context.Context, alias string) (*domain.Account, error) {
@@ -62,7 +65,19 @@ func (m *mockAccountRepository) FindByAlias(_ context.Context, alias string) (*d
 			return account, nil
 		}
 	}
-	return nil, errors.New("account not found")
+	return nil, domain.ErrAccountNotFound
+}
+
+func (m *mockAccountRepository) FindByUUID(_ context.Context, uuid string) (*domain.Account, error) {
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	for _, account := range m.accounts {
+		if account.UUID() == uuid {
+			return account, nil
+		}
+	}
+	return nil, domain.ErrAccountNotFound
 }
 
 func (m *mockAccountRepository) List(_ context.Context) ([]*domain.Account, error) {
@@ -107,7 +122,7 @@ func (m *mockCredentialStore) Retrieve(_ context.Context, accountID domain.Accou
 	}
 	creds, ok := m.credentials[accountID]
 	if !ok {
-		return nil, errors.New("credentials not found")
+		return nil, domain.ErrCredentialsNotFound
 	}
 	return creds, nil
 }
@@ -119,8 +134,10 @@ func (m *mockCredentialStore) Delete(_ context.Context, accountID domain.Account
 
 type mockConfigManager struct {
 	currentAccount *domain.Account
+	credentials    *domain.Credentials // Last credentials written to Claude config
 	getErr         error
 	setErr         error
+	credsErr       error
 }
 
 func newMockConfigManager() *mockConfigManager {
@@ -142,6 +159,14 @@ func (m *mockConfigManager) SetCurrentAccount(_ context.Context, account *domain
 	return nil
 }
 
+func (m *mockConfigManager) SetCredentials(_ context.Context, creds *domain.Credentials) error {
+	if m.credsErr != nil {
+		return m.credsErr
+	}
+	m.credentials = creds
+	return nil
+}
+
 // Test setup helper
 type testSetup struct {
 	accountRepo     *mockAccountRepository
@@ -185,7 +210,8 @@ func TestAddAccountUseCase_Execute_SuccessfulAdd(t *testing.T) {
 
 	// Test input with explicit alias
 	input := usecases.AddAccountInput{
-		Alias: "work",
+		Alias:       "work",
+		Credentials: []byte(`{"sessionKey": "test-key"}`),
 	}
 
 	// Execute
@@ -218,6 +244,32 @@ func TestAddAccountUseCase_Execute_SuccessfulAdd(t *testing.T) {
 	}
 }
 
+// TestAddAccountUseCase_Execute_CapturesOAuthAccount tests that the current oauthAccount
+// blob is stored with the new account
+func TestAddAccountUseCase_Execute_CapturesOAuthAccount(t *testing.T) {
+	setup := setupTest()
+	ctx := context.Background()
+
+	blob := json.RawMessage(`{"emailAddress":"test@example.com","accountUuid":"uuid-123","organizationUuid":"org-123"}`)
+	claudeAccount, _ := domain.NewAccount("test@example.com", "", "uuid-123")
+	if err := claudeAccount.SetRawOAuth(blob); err != nil {
+		t.Fatalf("failed to set oauth blob: %v", err)
+	}
+	setup.configManager.currentAccount = claudeAccount
+
+	if err := setup.useCase.Execute(ctx, usecases.AddAccountInput{Credentials: []byte(`{"sessionKey": "test-key"}`)}); err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	saved, err := setup.accountRepo.FindByEmail(ctx, "test@example.com")
+	if err != nil {
+		t.Fatalf("expected account to be saved: %v", err)
+	}
+	if string(saved.RawOAuth()) != string(blob) {
+		t.Errorf("RawOAuth() = %s, want %s", saved.RawOAuth(), blob)
+	}
+}
+
 // TestAddAccountUseCase_Execute_NoClaudeConfig tests when Claude config is missing
 func TestAddAccountUseCase_Execute_NoClaudeConfig(t *testing.T) {
 	setup := setupTest()
@@ -232,8 +284,8 @@ func TestAddAccountUseCase_Execute_NoClaudeConfig(t *testing.T) {
 	err := setup.useCase.Execute(ctx, input)
 
 	// Verify
-	if err == nil {
-		t.Error("Expected error when no Claude config exists, got nil")
+	if !errors.Is(err, domain.ErrNoCurrentAccount) {
+		t.Errorf("Expected ErrNoCurrentAccount when no Claude config exists, got: %v", err)
 	}
 }
 
@@ -250,14 +302,69 @@ func TestAddAccountUseCase_Execute_DuplicateAccount(t *testing.T) {
 	claudeAccount, _ := domain.NewAccount("test@example.com", "", "uuid-123")
 	setup.configManager.currentAccount = claudeAccount
 
-	input := usecases.AddAccountInput{}
+	input := usecases.AddAccountInput{Credentials: []byte(`{"sessionKey": "test-key"}`)}
 
 	// Execute
 	err := setup.useCase.Execute(ctx, input)
 
 	// Verify
-	if err == nil {
-		t.Error("Expected error when adding duplicate account, got nil")
+	if !errors.Is(err, domain.ErrDuplicateAccount) {
+		t.Errorf("Expected ErrDuplicateAccount when adding duplicate account, got: %v", err)
+	}
+}
+
+// TestAddAccountUseCase_Execute_DuplicateUUID tests that the same Claude account under
+// a new email is refused, or only warned about when allowed
+func TestAddAccountUseCase_Execute_DuplicateUUID(t *testing.T) {
+	ctx := context.Background()
+	setup := setupTest()
+
+	existingAccount, _ := domain.NewAccount("old@example.com", "old", "uuid-123")
+	_ = setup.accountRepo.Save(ctx, existingAccount)
+
+	// Claude config now reports the same account under a new email
+	claudeAccount, _ := domain.NewAccount("new@example.com", "", "uuid-123")
+	setup.configManager.currentAccount = claudeAccount
+
+	input := usecases.AddAccountInput{Credentials: []byte(`{"sessionKey": "test-key"}`)}
+	if err := setup.useCase.Execute(ctx, input); !errors.Is(err, domain.ErrDuplicateUUID) {
+		t.Errorf("Execute() error = %v, want ErrDuplicateUUID", err)
+	}
+	if len(setup.accountRepo.accounts) != 1 {
+		t.Errorf("accounts = %d, want 1", len(setup.accountRepo.accounts))
+	}
+
+	sink := &recordingEventSink{}
+	useCase := usecases.NewAddAccountService(setup.accountRepo, setup.credentialStore, setup.configManager,
+		usecases.WithAddEvents(sink))
+	input.AllowDuplicateUUID = true
+	if err := useCase.Execute(ctx, input); err != nil {
+		t.Fatalf("Execute() with AllowDuplicateUUID error = %v, want nil", err)
+	}
+	if len(setup.accountRepo.accounts) != 2 {
+		t.Errorf("accounts = %d, want 2", len(setup.accountRepo.accounts))
+	}
+	if len(sink.warnings) != 1 || !errors.Is(sink.warnings[0], domain.ErrDuplicateUUID) {
+		t.Errorf("warnings = %v, want one ErrDuplicateUUID", sink.warnings)
+	}
+}
+
+// TestAddAccountUseCase_Execute_LookupFailure tests that a failing repository is not
+// mistaken for a missing account
+func TestAddAccountUseCase_Execute_LookupFailure(t *testing.T) {
+	setup := setupTest()
+	ctx := context.Background()
+
+	claudeAccount, _ := domain.NewAccount("test@example.com", "", "uuid-123")
+	setup.configManager.currentAccount = claudeAccount
+	setup.accountRepo.findErr = errors.New("database locked")
+
+	err := setup.useCase.Execute(ctx, usecases.AddAccountInput{Credentials: []byte(`{"sessionKey": "test-key"}`)})
+	if !errors.Is(err, setup.accountRepo.findErr) {
+		t.Errorf("Expected lookup error, got: %v", err)
+	}
+	if len(setup.accountRepo.accounts) != 0 {
+		t.Error("Account should not be saved when the lookup fails")
 	}
 }
 
@@ -273,7 +380,7 @@ func TestAddAccountUseCase_Execute_CredentialStoreFailure(t *testing.T) {
 	// Setup: Force credential store to fail
 	setup.credentialStore.storeErr = errors.New("keychain unavailable")
 
-	input := usecases.AddAccountInput{}
+	input := usecases.AddAccountInput{Credentials: []byte(`{"sessionKey": "test-key"}`)}
 
 	// Execute
 	err := setup.useCase.Execute(ctx, input)
@@ -327,3 +434,211 @@ func TestAddAccountUseCase_Execute_ExplicitInput(t *testing.T) {
 		}
 	}
 }
+
+// TestAddAccountUseCase_Execute_DuplicateAlias tests that a given alias already used by
+// another account is refused, while a generated one gets a numeric suffix
+func TestAddAccountUseCase_Execute_DuplicateAlias(t *testing.T) {
+	setup := setupTest()
+	ctx := context.Background()
+	existing, _ := domain.NewAccount(testEmailWork, "work", "uuid-work")
+	_ = setup.accountRepo.Save(ctx, existing)
+
+	creds := []byte(`{"sessionKey": "test-key"}`)
+	err := setup.useCase.Execute(ctx, usecases.AddAccountInput{Email: testEmailPersonal, Alias: "work", Credentials: creds})
+	if !errors.Is(err, domain.ErrDuplicateAlias) {
+		t.Errorf("Execute() with a taken alias error = %v, want ErrDuplicateAlias", err)
+	}
+	if len(setup.accountRepo.accounts) != 1 || len(setup.credentialStore.credentials) != 0 {
+		t.Errorf("Stored %d accounts and %d credentials, want only the existing account",
+			len(setup.accountRepo.accounts), len(setup.credentialStore.credentials))
+	}
+
+	for i, email := range []string{"work@other.example.com", "work@third.example.com"} {
+		if err := setup.useCase.Execute(ctx, usecases.AddAccountInput{Email: email, Credentials: creds}); err != nil {
+			t.Fatalf("Execute(%s) error = %v, want nil", email, err)
+		}
+		account, err := setup.accountRepo.FindByEmail(ctx, domain.Email(email))
+		if err != nil {
+			t.Fatalf("FindByEmail(%s) error = %v", email, err)
+		}
+		if want := fmt.Sprintf("work-%d", i+2); account.Alias() != want {
+			t.Errorf("Generated alias for %s = %q, want %q", email, account.Alias(), want)
+		}
+	}
+}
+
+// TestAddAccountUseCase_Execute_AliasGenerator tests that the configured strategy
+// generates aliases for accounts added without one
+func TestAddAccountUseCase_Execute_AliasGenerator(t *testing.T) {
+	tests := []struct {
+		strategy usecases.AliasStrategy
+		want     string
+	}{
+		{usecases.AliasFromLocalPart, "john-doe"},
+		{usecases.AliasFromLocalPartDomain, "john-doe-work"},
+		{usecases.AliasFromDomain, "work"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			setup := setupTest()
+			ctx := context.Background()
+			useCase := usecases.NewAddAccountService(setup.accountRepo, setup.credentialStore, setup.configManager,
+				usecases.WithAliasGenerator(tt.strategy))
+
+			input := usecases.AddAccountInput{Email: "John.Doe@work.com", Credentials: []byte(`{"sessionKey": "test-key"}`)}
+			if err := useCase.Execute(ctx, input); err != nil {
+				t.Fatalf("Execute() error = %v, want nil", err)
+			}
+			account, err := setup.accountRepo.FindByEmail(ctx, "john.doe@work.com")
+			if err != nil {
+				t.Fatalf("FindByEmail() error = %v", err)
+			}
+			if account.Alias() != tt.want {
+				t.Errorf("Alias() = %q, want %q", account.Alias(), tt.want)
+			}
+		})
+	}
+}
+
+// TestAliasStrategy_GenerateAlias tests alias generation and sanitizing
+func TestAliasStrategy_GenerateAlias(t *testing.T) {
+	tests := []struct {
+		strategy usecases.AliasStrategy
+		email    domain.Email
+		want     string
+	}{
+		{usecases.AliasFromLocalPart, "work@example.com", "work"},
+		{usecases.AliasFromLocalPart, "first.last+ccx@example.com", "first-last-ccx"},
+		{usecases.AliasFromLocalPart, "under_score-dash@example.com", "under_score-dash"},
+		{usecases.AliasFromLocalPart, "!#$@example.com", ""},
+		{usecases.AliasFromLocalPartDomain, "me@mail.corp.example", "me-mail-corp"},
+		{usecases.AliasFromDomain, "me@localhost", "localhost"},
+		{"", "fallback@example.com", "fallback"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.strategy.GenerateAlias(tt.email); got != tt.want {
+			t.Errorf("%q.GenerateAlias(%s) = %q, want %q", tt.strategy, tt.email, got, tt.want)
+		}
+	}
+
+	if _, err := usecases.ParseAliasStrategy("localpart-domain"); err != nil {
+		t.Errorf("ParseAliasStrategy(localpart-domain) error = %v", err)
+	}
+	if _, err := usecases.ParseAliasStrategy("random"); err == nil {
+		t.Error("ParseAliasStrategy(random) error = nil, want error")
+	}
+}
+
+// TestAddAccountUseCase_Execute_EmptyAliasesNotUnique tests that accounts without an
+// alias never block others
+func TestAddAccountUseCase_Execute_EmptyAliasesNotUnique(t *testing.T) {
+	setup := setupTest()
+	ctx := context.Background()
+	for _, email := range []string{"first@example.com", "second@example.com"} {
+		account, _ := domain.NewAccount(email, "", "uuid-"+email)
+		_ = setup.accountRepo.Save(ctx, account)
+	}
+
+	// Neither input gives an alias, so each gets one from its email
+	creds := []byte(`{"sessionKey": "test-key"}`)
+	for _, email := range []string{testEmailPersonal, testEmailWork} {
+		if err := setup.useCase.Execute(ctx, usecases.AddAccountInput{Email: email, Credentials: creds}); err != nil {
+			t.Errorf("Execute(%s) error = %v, want nil", email, err)
+		}
+	}
+	if len(setup.accountRepo.accounts) != 4 {
+		t.Errorf("Stored %d accounts, want 4", len(setup.accountRepo.accounts))
+	}
+}
+
+// TestAddAccountUseCase_Execute_OAuthCredentials tests that credentials in the nested
+// claudeAiOauth shape of newer Claude versions are accepted
+func TestAddAccountUseCase_Execute_OAuthCredentials(t *testing.T) {
+	setup := setupTest()
+	ctx := context.Background()
+
+	creds := []byte(`{"claudeAiOauth": {"accessToken": "sk-ant-oat-123", "expiresAt": 1750000000000}}`)
+	err := setup.useCase.Execute(ctx, usecases.AddAccountInput{Email: testEmailWork, Credentials: creds})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if len(setup.credentialStore.credentials) != 1 {
+		t.Errorf("Stored %d credentials, want 1", len(setup.credentialStore.credentials))
+	}
+}
+
+// TestAddAccountUseCase_Execute_MasterKey tests that a service given a master key stores
+// new credentials under envelope encryption
+func TestAddAccountUseCase_Execute_MasterKey(t *testing.T) {
+	setup := setupTest()
+	ctx := context.Background()
+
+	masterKey, err := domain.GenerateMasterKey()
+	if err != nil {
+		t.Fatalf("GenerateMasterKey() error = %v", err)
+	}
+	useCase := usecases.NewAddAccountService(setup.accountRepo, setup.credentialStore, setup.configManager,
+		usecases.WithAddMasterKey(masterKey))
+
+	creds := []byte(`{"sessionKey": "test-key"}`)
+	if err := useCase.Execute(ctx, usecases.AddAccountInput{Email: testEmailWork, Credentials: creds}); err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	account, err := setup.accountRepo.FindByEmail(ctx, testEmailWork)
+	if err != nil {
+		t.Fatalf("FindByEmail() error = %v", err)
+	}
+	stored := setup.credentialStore.credentials[account.ID()]
+	if stored == nil || stored.Scheme() != domain.SchemeEnvelope {
+		t.Fatalf("Stored credentials = %v, want envelope-encrypted", stored)
+	}
+	if err := stored.Unwrap(masterKey); err != nil {
+		t.Fatalf("Unwrap() error = %v", err)
+	}
+	if plaintext, err := stored.Decrypt(); err != nil || string(plaintext) != string(creds) {
+		t.Errorf("Decrypt() = %s, %v; want the added credentials", plaintext, err)
+	}
+}
+
+// TestAddAccountUseCase_Execute_InvalidCredentials tests that credentials without a usable
+// session key are refused instead of stored
+func TestAddAccountUseCase_Execute_InvalidCredentials(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials string
+	}{
+		{"missing", ""},
+		{"not JSON", "session-key"},
+		{"no session key", `{"account_id": "uuid-123"}`},
+		{"empty session key", `{"sessionKey": "  "}`},
+		{"non-string session key", `{"session_key": 42}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupTest()
+			ctx := context.Background()
+
+			claudeAccount, _ := domain.NewAccount("test@example.com", "", "uuid-123")
+			setup.configManager.currentAccount = claudeAccount
+
+			err := setup.useCase.Execute(ctx, usecases.AddAccountInput{Credentials: []byte(tt.credentials)})
+			if err == nil {
+				t.Fatal("Execute() error = nil, want error")
+			}
+			if tt.credentials != "" && !errors.Is(err, domain.ErrInvalidCredentialData) {
+				t.Errorf("Execute() error = %v, want ErrInvalidCredentialData", err)
+			}
+
+			if savedAccounts, _ := setup.accountRepo.List(ctx); len(savedAccounts) != 0 {
+				t.Errorf("Expected no accounts to be saved, got %d", len(savedAccounts))
+			}
+			if len(setup.credentialStore.credentials) != 0 {
+				t.Error("Expected no credentials to be stored")
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evanschultz/ccx/internal/domain"
+)
+
+// AliasGenerator proposes an alias for an account added without one. The proposal may
+// be taken; AddAccountService appends a numeric suffix until it is unique.
+type AliasGenerator interface {
+	// GenerateAlias returns an alias for email, or "" to add the account without one
+	GenerateAlias(email domain.Email) string
+}
+
+// AliasStrategy is a built-in AliasGenerator, named so it can be chosen in settings or
+// on the command line. Generated aliases keep only letters, numbers, hyphens, and
+// underscores; runs of anything else become a single hyphen.
+type AliasStrategy string
+
+// Supported alias strategies
+const (
+	// AliasFromLocalPart uses the part before the @: john.doe@work.com becomes john-doe.
+	// It is the default.
+	AliasFromLocalPart AliasStrategy = "localpart"
+	// AliasFromLocalPartDomain adds the domain name: john.doe@work.com becomes
+	// john-doe-work
+	AliasFromLocalPartDomain AliasStrategy = "localpart-domain"
+	// AliasFromDomain uses only the domain name: john.doe@work.com becomes work
+	AliasFromDomain AliasStrategy = "domain"
+)
+
+// Ensure AliasStrategy implements AliasGenerator at compile time
+var _ AliasGenerator = AliasStrategy("")
+
+// maxAliasSuffix is the highest numeric suffix tried when de-duplicating a generated alias
+const maxAliasSuffix = 99
+
+// ParseAliasStrategy returns the strategy with the given name
+func ParseAliasStrategy(name string) (AliasStrategy, error) {
+	switch strategy := AliasStrategy(name); strategy {
+	case AliasFromLocalPart, AliasFromLocalPartDomain, AliasFromDomain:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown alias strategy %q: want %s, %s, or %s",
+			name, AliasFromLocalPart, AliasFromLocalPartDomain, AliasFromDomain)
+	}
+}
+
+// GenerateAlias builds an alias from email using the strategy. Unknown strategies
+// behave like AliasFromLocalPart.
+func (s AliasStrategy) GenerateAlias(email domain.Email) string {
+	local := string(email)
+	if at := strings.LastIndex(local, "@"); at >= 0 {
+		local = local[:at]
+	}
+	// The domain name without its top-level domain: work.com becomes work
+	name := email.Domain()
+	if dot := strings.LastIndex(name, "."); dot > 0 {
+		name = name[:dot]
+	}
+
+	switch s {
+	case AliasFromLocalPartDomain:
+		return sanitizeAlias(local + "-" + name)
+	case AliasFromDomain:
+		return sanitizeAlias(name)
+	default:
+		return sanitizeAlias(local)
+	}
+}
+
+// sanitizeAlias replaces each run of characters an alias can't contain with a hyphen
+// and trims hyphens from the ends
+func sanitizeAlias(alias string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range alias {
+		if r < 0x80 && (r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			if pendingHyphen && r != '-' {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingHyphen = b.Len() > 0
+	}
+	return strings.Trim(b.String(), "-")
+}
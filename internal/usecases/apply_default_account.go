@@ -0,0 +1,109 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// ApplyDefaultAccountUseCase defines the interface for activating the default account
+// when Claude has no current account (fresh machine or after a reset)
+type ApplyDefaultAccountUseCase interface {
+	Execute(ctx context.Context) (*ApplyDefaultAccountResult, error)
+}
+
+// ApplyDefaultAccountResult contains the result of reconciling the current account
+type ApplyDefaultAccountResult struct {
+	Applied bool         `json:"applied"` // True if the default account was activated
+	Current *AccountInfo `json:"current"` // Current account after reconciliation (nil if none)
+}
+
+// ApplyDefaultAccountService implements the ApplyDefaultAccountUseCase
+type ApplyDefaultAccountService struct {
+	accounts    ports.AccountRepository
+	credentials ports.CredentialStore
+	config      ports.ConfigManager
+	settings    ports.SettingsRepository
+}
+
+// Ensure ApplyDefaultAccountService implements ApplyDefaultAccountUseCase at compile time
+var _ ApplyDefaultAccountUseCase = (*ApplyDefaultAccountService)(nil)
+
+// NewApplyDefaultAccountService creates a new ApplyDefaultAccountService
+func NewApplyDefaultAccountService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+	config ports.ConfigManager,
+	settings ports.SettingsRepository,
+) ApplyDefaultAccountUseCase {
+	return &ApplyDefaultAccountService{
+		accounts:    accounts,
+		credentials: credentials,
+		config:      config,
+		settings:    settings,
+	}
+}
+
+// Execute activates the default account if no account is current.
+// An existing current account is never replaced.
+func (s *ApplyDefaultAccountService) Execute(ctx context.Context) (*ApplyDefaultAccountResult, error) {
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	currentAccount, err := s.config.GetCurrentAccount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current account: %w", err)
+	}
+	if currentAccount != nil {
+		currentInfo := newAccountInfo(currentAccount)
+		return &ApplyDefaultAccountResult{Current: &currentInfo}, nil
+	}
+
+	settings, err := s.settings.LoadSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+	if !settings.HasDefaultAccount() {
+		return &ApplyDefaultAccountResult{}, nil
+	}
+
+	defaultAccount, err := s.accounts.FindByID(ctx, settings.DefaultAccountID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find default account %s: %w", settings.DefaultAccountID(), err)
+	}
+
+	// Verify credentials exist before activating, as a switch would
+	creds, err := s.credentials.Retrieve(ctx, defaultAccount.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credentials for account %s: %w", defaultAccount.Alias(), err)
+	}
+	if err := checkCredentialsDecrypt(creds); err != nil {
+		return nil, fmt.Errorf("cannot use credentials for account %s: %w", defaultAccount.Alias(), err)
+	}
+
+	// No account was current, so a failed session write clears the account again
+	tx := NewTransaction()
+	err = tx.Do(ctx,
+		func(ctx context.Context) error { return s.config.SetCurrentAccount(ctx, defaultAccount) },
+		func(ctx context.Context) error { return s.config.SetCurrentAccount(ctx, nil) },
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set current account: %w", err)
+	}
+	err = tx.Do(ctx, func(ctx context.Context) error { return s.config.SetCredentials(ctx, creds) }, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write credentials for account %s: %w", defaultAccount.Alias(), err)
+	}
+	tx.Commit()
+
+	currentInfo := newAccountInfo(defaultAccount)
+	return &ApplyDefaultAccountResult{
+		Applied: true,
+		Current: &currentInfo,
+	}, nil
+}
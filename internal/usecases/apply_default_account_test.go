@@ -0,0 +1,158 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// Test setup helper for ApplyDefaultAccountUseCase
+type applyDefaultAccountTestSetup struct {
+	accountRepo     *mockAccountRepository
+	credentialStore *mockCredentialStore
+	configManager   *mockConfigManager
+	settingsRepo    *mockSettingsRepository
+	useCase         usecases.ApplyDefaultAccountUseCase
+	personal        *domain.Account
+	work            *domain.Account
+}
+
+func setupApplyDefaultAccountTest() *applyDefaultAccountTestSetup {
+	accountRepo := newMockAccountRepository()
+	credentialStore := newMockCredentialStore()
+	configManager := newMockConfigManager()
+	settingsRepo := newMockSettingsRepository()
+
+	personal, _ := domain.NewAccount(testEmailPersonal, "personal", "uuid-personal")
+	work, _ := domain.NewAccount(testEmailWork, "work", "uuid-work")
+
+	for _, account := range []*domain.Account{personal, work} {
+		_ = accountRepo.Save(context.Background(), account)
+		creds, _ := domain.NewCredentials(account.ID(), []byte(`{"sessionKey": "key-`+account.Alias()+`"}`))
+		_ = credentialStore.Store(context.Background(), creds)
+	}
+
+	useCase := usecases.NewApplyDefaultAccountService(accountRepo, credentialStore, configManager, settingsRepo)
+
+	return &applyDefaultAccountTestSetup{
+		accountRepo:     accountRepo,
+		credentialStore: credentialStore,
+		configManager:   configManager,
+		settingsRepo:    settingsRepo,
+		useCase:         useCase,
+		personal:        personal,
+		work:            work,
+	}
+}
+
+// TestApplyDefaultAccountUseCase_Execute_AppliesWhenNoCurrent tests activation on a fresh machine
+func TestApplyDefaultAccountUseCase_Execute_AppliesWhenNoCurrent(t *testing.T) {
+	setup := setupApplyDefaultAccountTest()
+	setup.settingsRepo.settings.SetDefaultAccountID(setup.work.ID())
+
+	result, err := setup.useCase.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if !result.Applied {
+		t.Error("Expected default account to be applied")
+	}
+	if result.Current == nil || result.Current.Email != testEmailWork {
+		t.Errorf("Expected current account %s, got %+v", testEmailWork, result.Current)
+	}
+	if setup.configManager.currentAccount == nil || setup.configManager.currentAccount.ID() != setup.work.ID() {
+		t.Error("Config was not updated to the default account")
+	}
+	if creds := setup.configManager.credentials; creds == nil || creds.AccountID() != setup.work.ID() {
+		t.Errorf("Config credentials = %v, want the default account's", creds)
+	}
+}
+
+// TestApplyDefaultAccountUseCase_Execute_CredentialsWriteFailure tests that the account is
+// cleared again when its session cannot be written
+func TestApplyDefaultAccountUseCase_Execute_CredentialsWriteFailure(t *testing.T) {
+	setup := setupApplyDefaultAccountTest()
+	setup.settingsRepo.settings.SetDefaultAccountID(setup.work.ID())
+	setup.configManager.credsErr = errors.New("config file locked")
+
+	if _, err := setup.useCase.Execute(context.Background()); !errors.Is(err, setup.configManager.credsErr) {
+		t.Fatalf("Execute() error = %v, want credentials write error", err)
+	}
+	if setup.configManager.currentAccount != nil {
+		t.Error("Current account should be cleared when the session write fails")
+	}
+}
+
+// TestApplyDefaultAccountUseCase_Execute_KeepsExistingCurrent tests that a current account is never replaced
+func TestApplyDefaultAccountUseCase_Execute_KeepsExistingCurrent(t *testing.T) {
+	setup := setupApplyDefaultAccountTest()
+	setup.settingsRepo.settings.SetDefaultAccountID(setup.work.ID())
+	setup.configManager.currentAccount = setup.personal
+
+	result, err := setup.useCase.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if result.Applied {
+		t.Error("Default should not be applied when an account is already current")
+	}
+	if result.Current == nil || result.Current.Email != testEmailPersonal {
+		t.Errorf("Expected current account %s, got %+v", testEmailPersonal, result.Current)
+	}
+	if setup.configManager.currentAccount.ID() != setup.personal.ID() {
+		t.Error("Current account should not change")
+	}
+}
+
+// TestApplyDefaultAccountUseCase_Execute_NoDefault tests when no default is configured
+func TestApplyDefaultAccountUseCase_Execute_NoDefault(t *testing.T) {
+	setup := setupApplyDefaultAccountTest()
+
+	result, err := setup.useCase.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if result.Applied || result.Current != nil {
+		t.Errorf("Expected nothing to be applied, got %+v", result)
+	}
+	if setup.configManager.currentAccount != nil {
+		t.Error("Config should not be updated without a default")
+	}
+}
+
+// TestApplyDefaultAccountUseCase_Execute_DefaultRemoved tests a default pointing at a removed account
+func TestApplyDefaultAccountUseCase_Execute_DefaultRemoved(t *testing.T) {
+	setup := setupApplyDefaultAccountTest()
+	setup.settingsRepo.settings.SetDefaultAccountID("removed-id")
+
+	result, err := setup.useCase.Execute(context.Background())
+	if err == nil {
+		t.Error("Expected error when default account no longer exists, got nil")
+	}
+	if result != nil {
+		t.Errorf("Expected nil result on error, got %+v", result)
+	}
+	if setup.configManager.currentAccount != nil {
+		t.Error("Config should not be updated when default is missing")
+	}
+}
+
+// TestApplyDefaultAccountUseCase_Execute_MissingCredentials tests that credentials are required
+func TestApplyDefaultAccountUseCase_Execute_MissingCredentials(t *testing.T) {
+	setup := setupApplyDefaultAccountTest()
+	setup.settingsRepo.settings.SetDefaultAccountID(setup.work.ID())
+	_ = setup.credentialStore.Delete(context.Background(), setup.work.ID())
+
+	if _, err := setup.useCase.Execute(context.Background()); err == nil {
+		t.Error("Expected error when default account has no credentials, got nil")
+	}
+	if setup.configManager.currentAccount != nil {
+		t.Error("Config should not be updated when credentials are missing")
+	}
+}
@@ -0,0 +1,47 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BackupVersion is the bundle format version written by ExportUseCase and accepted by ImportUseCase
+const BackupVersion = 1
+
+// backupBundle is the portable JSON document holding the complete ccx state.
+// Credentials are re-encrypted under the user's passphrase so the bundle does not
+// depend on the account-ID-derived keys of the exporting machine.
+type backupBundle struct {
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Accounts   []backupAccount `json:"accounts"`
+	History    []backupSwitch  `json:"history"`
+	MaxHistory int             `json:"max_history"`
+}
+
+// backupAccount is an account together with its passphrase-protected credentials
+type backupAccount struct {
+	ID          string            `json:"id"`
+	Email       string            `json:"email"`
+	Alias       string            `json:"alias,omitempty"`
+	UUID        string            `json:"uuid"`
+	Tags        []string          `json:"tags,omitempty"`
+	Color       string            `json:"color,omitempty"`
+	Label       string            `json:"label,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+	RawOAuth    json.RawMessage   `json:"raw_oauth,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	LastUsed    time.Time         `json:"last_used"`
+	ArchivedAt  time.Time         `json:"archived_at,omitzero"` // Zero unless the account is archived
+	Credentials json.RawMessage   `json:"credentials"`
+}
+
+// backupSwitch is a single history entry
+type backupSwitch struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -0,0 +1,149 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// BulkAddUseCase defines the interface for adding many accounts in one pass
+type BulkAddUseCase interface {
+	Execute(ctx context.Context, records []AddAccountInput) (*BulkAddResult, error)
+}
+
+// BulkAddStatus is the outcome of adding a single record
+type BulkAddStatus string
+
+const (
+	// BulkAddAdded means the account and its credentials were saved
+	BulkAddAdded BulkAddStatus = "added"
+	// BulkAddSkipped means the email was already managed or appeared earlier in the batch
+	BulkAddSkipped BulkAddStatus = "skipped"
+	// BulkAddFailed means the record was rejected or could not be saved
+	BulkAddFailed BulkAddStatus = "failed"
+)
+
+// BulkAddRecordResult reports what happened to a single record
+type BulkAddRecordResult struct {
+	Index  int           `json:"index"`  // Position of the record in the input
+	Email  string        `json:"email"`  // Email from the record, empty if it was read from Claude config
+	Status BulkAddStatus `json:"status"` // Outcome of the record
+	Err    error         `json:"-"`      // Reason the record was skipped or failed, nil if added
+}
+
+// bulkAddRecordJSON is the JSON shape of BulkAddRecordResult, with Err as its message
+type bulkAddRecordJSON struct {
+	Index  int           `json:"index"`
+	Email  string        `json:"email"`
+	Status BulkAddStatus `json:"status"`
+	Error  *string       `json:"error"` // null if added
+}
+
+// MarshalJSON encodes Err as its message under "error", or null if the record was added
+func (r BulkAddRecordResult) MarshalJSON() ([]byte, error) {
+	out := bulkAddRecordJSON{Index: r.Index, Email: r.Email, Status: r.Status}
+	if r.Err != nil {
+		msg := r.Err.Error()
+		out.Error = &msg
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes the shape written by MarshalJSON. Err only keeps the message,
+// so errors.Is no longer matches the original sentinel.
+func (r *BulkAddRecordResult) UnmarshalJSON(data []byte) error {
+	var in bulkAddRecordJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*r = BulkAddRecordResult{Index: in.Index, Email: in.Email, Status: in.Status}
+	if in.Error != nil {
+		r.Err = errors.New(*in.Error)
+	}
+	return nil
+}
+
+// BulkAddResult contains the per-record report of a bulk add
+type BulkAddResult struct {
+	Records []BulkAddRecordResult `json:"records"` // One entry per processed record, in input order
+	Added   int                   `json:"added"`   // Records whose account was saved
+	Skipped int                   `json:"skipped"` // Records that duplicated an existing or earlier account
+	Failed  int                   `json:"failed"`  // Records that could not be added
+}
+
+// BulkAddService implements the BulkAddUseCase
+type BulkAddService struct {
+	add *AddAccountService
+}
+
+// Ensure BulkAddService implements BulkAddUseCase at compile time
+var _ BulkAddUseCase = (*BulkAddService)(nil)
+
+// NewBulkAddService creates a new BulkAddService. Options apply to the single add
+// performed for each record.
+func NewBulkAddService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+	config ports.ConfigManager,
+	opts ...AddAccountOption,
+) BulkAddUseCase {
+	return &BulkAddService{
+		add: newAddAccountService(accounts, credentials, config, opts...),
+	}
+}
+
+// Execute adds each record the way AddAccountUseCase does, so every added account is saved
+// together with its credentials. A record that fails does not stop the batch; records
+// repeating the email of an account added earlier in the batch are skipped without
+// touching the repository.
+// An error is returned only if ctx is cancelled, along with the records processed so far.
+func (s *BulkAddService) Execute(ctx context.Context, records []AddAccountInput) (*BulkAddResult, error) {
+	result := &BulkAddResult{
+		Records: make([]BulkAddRecordResult, 0, len(records)),
+	}
+	seen := make(map[domain.Email]int, len(records))
+
+	for i, record := range records {
+		// Check context before proceeding
+		if err := ctx.Err(); err != nil {
+			return result, fmt.Errorf("context cancelled: %w", err)
+		}
+
+		outcome := BulkAddRecordResult{Index: i, Email: record.Email}
+		email := domain.NormalizeEmail(record.Email)
+		if first, ok := seen[email]; ok && email != "" {
+			outcome.Status = BulkAddSkipped
+			outcome.Err = fmt.Errorf("%w: %s repeats record %d", domain.ErrDuplicateAccount, record.Email, first)
+		} else {
+			outcome.Err = s.add.Execute(ctx, record)
+			switch {
+			case outcome.Err == nil:
+				outcome.Status = BulkAddAdded
+				// Only added records claim their email, so a corrected record can follow a failed one
+				seen[email] = i
+			case errors.Is(outcome.Err, domain.ErrDuplicateAccount):
+				outcome.Status = BulkAddSkipped
+			default:
+				outcome.Status = BulkAddFailed
+			}
+		}
+
+		switch outcome.Status {
+		case BulkAddAdded:
+			result.Added++
+		case BulkAddSkipped:
+			result.Skipped++
+		case BulkAddFailed:
+			result.Failed++
+		}
+		result.Records = append(result.Records, outcome)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,112 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// TestBulkAddUseCase_Execute tests a batch mixing new, duplicate, and invalid records
+func TestBulkAddUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository()
+	credentialStore := newMockCredentialStore()
+	existing, _ := domain.NewAccount(testEmailTest, "test", "uuid-test")
+	_ = accountRepo.Save(ctx, existing)
+
+	creds := []byte(`{"sessionKey": "test-key"}`)
+	records := []usecases.AddAccountInput{
+		{Email: testEmailPersonal, Credentials: creds},
+		{Email: " PERSONAL@example.com ", Alias: "again", Credentials: creds}, // repeats record 0
+		{Email: testEmailTest, Credentials: creds},                            // already managed
+		{Email: "not-an-email", Credentials: creds},
+		{Email: testEmailWork, Credentials: []byte(`{}`)},
+		{Email: testEmailWork, Alias: "work", Credentials: creds}, // earlier record failed, so this is not a repeat
+	}
+
+	useCase := usecases.NewBulkAddService(accountRepo, credentialStore, newMockConfigManager())
+	result, err := useCase.Execute(ctx, records)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if result.Added != 2 || result.Skipped != 2 || result.Failed != 2 {
+		t.Errorf("Added/Skipped/Failed = %d/%d/%d, want 2/2/2", result.Added, result.Skipped, result.Failed)
+	}
+
+	want := []usecases.BulkAddStatus{
+		usecases.BulkAddAdded,
+		usecases.BulkAddSkipped,
+		usecases.BulkAddSkipped,
+		usecases.BulkAddFailed,
+		usecases.BulkAddFailed,
+		usecases.BulkAddAdded,
+	}
+	if len(result.Records) != len(want) {
+		t.Fatalf("len(Records) = %d, want %d", len(result.Records), len(want))
+	}
+	for i, record := range result.Records {
+		if record.Index != i || record.Status != want[i] {
+			t.Errorf("record %d = index %d, status %s; want index %d, status %s", i, record.Index, record.Status, i, want[i])
+		}
+		if (record.Err == nil) != (want[i] == usecases.BulkAddAdded) {
+			t.Errorf("record %d error = %v, want error only when not added", i, record.Err)
+		}
+	}
+	if !errors.Is(result.Records[1].Err, domain.ErrDuplicateAccount) {
+		t.Errorf("record 1 error = %v, want ErrDuplicateAccount", result.Records[1].Err)
+	}
+
+	// Each added account is stored with its credentials
+	if len(accountRepo.accounts) != 3 || len(credentialStore.credentials) != 2 {
+		t.Errorf("Stored %d accounts and %d credentials, want 3 and 2", len(accountRepo.accounts), len(credentialStore.credentials))
+	}
+	for _, email := range []domain.Email{testEmailPersonal, testEmailWork} {
+		account, err := accountRepo.FindByEmail(ctx, email)
+		if err != nil {
+			t.Fatalf("FindByEmail(%s) error = %v", email, err)
+		}
+		if _, ok := credentialStore.credentials[account.ID()]; !ok {
+			t.Errorf("No credentials stored for %s", email)
+		}
+	}
+}
+
+// TestBulkAddUseCase_Execute_SaveFailure tests that a failed save leaves no credentials behind
+func TestBulkAddUseCase_Execute_SaveFailure(t *testing.T) {
+	accountRepo := newMockAccountRepository()
+	accountRepo.saveErr = errors.New("disk full")
+	credentialStore := newMockCredentialStore()
+
+	useCase := usecases.NewBulkAddService(accountRepo, credentialStore, newMockConfigManager())
+	result, err := useCase.Execute(context.Background(), []usecases.AddAccountInput{
+		{Email: testEmailPersonal, Credentials: []byte(`{"sessionKey": "test-key"}`)},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.Failed != 1 || !errors.Is(result.Records[0].Err, accountRepo.saveErr) {
+		t.Errorf("Result = %+v, want one failure wrapping the save error", result)
+	}
+	if len(credentialStore.credentials) != 0 {
+		t.Errorf("Credentials left behind for %d accounts, want 0", len(credentialStore.credentials))
+	}
+}
+
+// TestBulkAddUseCase_Execute_Cancelled tests that cancellation stops the batch
+func TestBulkAddUseCase_Execute_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	useCase := usecases.NewBulkAddService(newMockAccountRepository(), newMockCredentialStore(), newMockConfigManager())
+	result, err := useCase.Execute(ctx, []usecases.AddAccountInput{{Email: testEmailPersonal}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want context.Canceled", err)
+	}
+	if result == nil || len(result.Records) != 0 {
+		t.Errorf("Result = %+v, want no processed records", result)
+	}
+}
@@ -0,0 +1,78 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// CreateProfileUseCase defines the interface for creating a named profile
+type CreateProfileUseCase interface {
+	Execute(ctx context.Context, input CreateProfileInput) (*ProfileInfo, error)
+}
+
+// CreateProfileInput contains the input data for creating a profile
+type CreateProfileInput struct {
+	Name      string // Profile name, following the rules for account aliases
+	AccountID string // Account the profile switches to
+}
+
+// CreateProfileService implements the CreateProfileUseCase
+type CreateProfileService struct {
+	profiles ports.ProfileRepository
+	accounts ports.AccountRepository
+}
+
+// Ensure CreateProfileService implements CreateProfileUseCase at compile time
+var _ CreateProfileUseCase = (*CreateProfileService)(nil)
+
+// NewCreateProfileService creates a new CreateProfileService
+func NewCreateProfileService(profiles ports.ProfileRepository, accounts ports.AccountRepository) CreateProfileUseCase {
+	return &CreateProfileService{
+		profiles: profiles,
+		accounts: accounts,
+	}
+}
+
+// Execute validates the account exists and saves a new profile for it. An existing
+// profile with the same name is not replaced.
+func (s *CreateProfileService) Execute(ctx context.Context, input CreateProfileInput) (*ProfileInfo, error) {
+	if input.AccountID == "" {
+		return nil, errors.New("account ID is required")
+	}
+
+	profile, err := domain.NewProfile(input.Name, domain.AccountID(input.AccountID))
+	if err != nil {
+		return nil, err
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	account, err := s.accounts.FindByID(ctx, profile.AccountID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+
+	_, err = s.profiles.FindByName(ctx, profile.Name())
+	switch {
+	case err == nil:
+		return nil, fmt.Errorf("%w: %s", domain.ErrDuplicateProfile, profile.Name())
+	case !errors.Is(err, domain.ErrProfileNotFound):
+		return nil, fmt.Errorf("failed to check for existing profile: %w", err)
+	}
+
+	if err := s.profiles.Save(ctx, profile); err != nil {
+		return nil, fmt.Errorf("failed to save profile: %w", err)
+	}
+
+	info := newProfileInfo(profile, account)
+	return &info, nil
+}
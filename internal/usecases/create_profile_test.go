@@ -0,0 +1,114 @@
+package usecases_test
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// mockProfileRepository is a test implementation of ProfileRepository
+type mockProfileRepository struct {
+	profiles  map[string]*domain.Profile
+	listErr   error
+	deleteErr error
+}
+
+func newMockProfileRepository() *mockProfileRepository {
+	return &mockProfileRepository{
+		profiles: make(map[string]*domain.Profile),
+	}
+}
+
+func (m *mockProfileRepository) Save(_ context.Context, profile *domain.Profile) error {
+	m.profiles[profile.Name()] = profile
+	return nil
+}
+
+func (m *mockProfileRepository) FindByName(_ context.Context, name string) (*domain.Profile, error) {
+	profile, ok := m.profiles[name]
+	if !ok {
+		return nil, domain.ErrProfileNotFound
+	}
+	return profile, nil
+}
+
+func (m *mockProfileRepository) List(_ context.Context) ([]*domain.Profile, error) {
+	if m.listErr != nil {
+		return nil, m.listErr
+	}
+	result := make([]*domain.Profile, 0, len(m.profiles))
+	for _, profile := range m.profiles {
+		result = append(result, profile)
+	}
+	slices.SortFunc(result, func(a, b *domain.Profile) int { return cmp.Compare(a.Name(), b.Name()) })
+	return result, nil
+}
+
+func (m *mockProfileRepository) Delete(_ context.Context, name string) error {
+	if m.deleteErr != nil {
+		return m.deleteErr
+	}
+	if _, ok := m.profiles[name]; !ok {
+		return domain.ErrProfileNotFound
+	}
+	delete(m.profiles, name)
+	return nil
+}
+
+// seedProfile stores a profile pointing at account
+func seedProfile(repo *mockProfileRepository, name string, account *domain.Account) {
+	profile, _ := domain.NewProfile(name, account.ID())
+	repo.profiles[name] = profile
+}
+
+// TestCreateProfileUseCase_Execute tests creating profiles and the validation around it
+func TestCreateProfileUseCase_Execute(t *testing.T) {
+	setup := setupTest()
+	ctx := context.Background()
+	account, _ := domain.NewAccount(testEmailWork, "work", "uuid-work")
+	_ = setup.accountRepo.Save(ctx, account)
+
+	profileRepo := newMockProfileRepository()
+	useCase := usecases.NewCreateProfileService(profileRepo, setup.accountRepo)
+
+	info, err := useCase.Execute(ctx, usecases.CreateProfileInput{Name: "client-a", AccountID: string(account.ID())})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if info.Name != "client-a" || info.Orphaned() || info.Account.Email != testEmailWork {
+		t.Errorf("Execute() = %+v, want client-a for %s", info, testEmailWork)
+	}
+	if _, ok := profileRepo.profiles["client-a"]; !ok {
+		t.Error("Profile was not saved")
+	}
+
+	tests := []struct {
+		name    string
+		input   usecases.CreateProfileInput
+		wantErr error
+	}{
+		{"duplicate name", usecases.CreateProfileInput{Name: "client-a", AccountID: string(account.ID())}, domain.ErrDuplicateProfile},
+		{"unknown account", usecases.CreateProfileInput{Name: "client-b", AccountID: "missing1"}, domain.ErrAccountNotFound},
+		{"invalid name", usecases.CreateProfileInput{Name: "client b", AccountID: string(account.ID())}, nil},
+		{"no account", usecases.CreateProfileInput{Name: "client-b"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := useCase.Execute(ctx, tt.input)
+			if err == nil {
+				t.Fatal("Execute() error = nil, want error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if len(profileRepo.profiles) != 1 {
+		t.Errorf("Stored %d profiles, want 1", len(profileRepo.profiles))
+	}
+}
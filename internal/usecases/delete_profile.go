@@ -0,0 +1,53 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// DeleteProfileUseCase defines the interface for deleting a named profile
+type DeleteProfileUseCase interface {
+	Execute(ctx context.Context, input DeleteProfileInput) error
+}
+
+// DeleteProfileInput contains the input data for deleting a profile
+type DeleteProfileInput struct {
+	Name string // Profile to delete
+}
+
+// DeleteProfileService implements the DeleteProfileUseCase
+type DeleteProfileService struct {
+	profiles ports.ProfileRepository
+}
+
+// Ensure DeleteProfileService implements DeleteProfileUseCase at compile time
+var _ DeleteProfileUseCase = (*DeleteProfileService)(nil)
+
+// NewDeleteProfileService creates a new DeleteProfileService
+func NewDeleteProfileService(profiles ports.ProfileRepository) DeleteProfileUseCase {
+	return &DeleteProfileService{
+		profiles: profiles,
+	}
+}
+
+// Execute deletes the profile; its account is left untouched
+func (s *DeleteProfileService) Execute(ctx context.Context, input DeleteProfileInput) error {
+	if input.Name == "" {
+		return errors.New("profile name is required")
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context cancelled: %w", err)
+	}
+
+	if err := s.profiles.Delete(ctx, input.Name); err != nil {
+		return fmt.Errorf("failed to delete profile %s: %w", input.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// TestDeleteProfileUseCase_Execute tests deleting a profile leaves its account alone
+func TestDeleteProfileUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	account, _ := domain.NewAccount(testEmailWork, "work", "uuid-work")
+	profileRepo := newMockProfileRepository()
+	seedProfile(profileRepo, "client-a", account)
+
+	useCase := usecases.NewDeleteProfileService(profileRepo)
+	if err := useCase.Execute(ctx, usecases.DeleteProfileInput{Name: "client-a"}); err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if len(profileRepo.profiles) != 0 {
+		t.Error("Profile was not deleted")
+	}
+
+	if err := useCase.Execute(ctx, usecases.DeleteProfileInput{Name: "client-a"}); !errors.Is(err, domain.ErrProfileNotFound) {
+		t.Errorf("Execute() error = %v, want ErrProfileNotFound", err)
+	}
+	if err := useCase.Execute(ctx, usecases.DeleteProfileInput{}); err == nil {
+		t.Error("Expected error for empty name, got nil")
+	}
+}
@@ -0,0 +1,201 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// DiscoverySource identifies where a discovered account was found
+type DiscoverySource string
+
+const (
+	// SourceClaudeConfig is the account currently active in Claude's .claude.json
+	SourceClaudeConfig DiscoverySource = "claude-config"
+	// SourceBackup is an account inside a ccx backup bundle
+	SourceBackup DiscoverySource = "backup"
+)
+
+// DiscoverAccountsUseCase defines the interface for finding Claude accounts that ccx
+// does not manage yet
+type DiscoverAccountsUseCase interface {
+	Execute(ctx context.Context, input DiscoverAccountsInput) (*DiscoverAccountsResult, error)
+}
+
+// DiscoverAccountsInput contains the locations to scan in addition to Claude config
+type DiscoverAccountsInput struct {
+	Backups [][]byte // Backup bundles written by ExportUseCase
+	// Passphrase, if set, is used to check that backup credentials decrypt to a usable
+	// session; without it, credentials present in a backup are assumed usable
+	Passphrase []byte
+}
+
+// DiscoveredAccount is a Claude account found during discovery. Nothing is persisted;
+// import it with AddAccountUseCase, or ImportUseCase for backup accounts.
+type DiscoveredAccount struct {
+	Email          string          `json:"email"`
+	UUID           string          `json:"uuid"`
+	Source         DiscoverySource `json:"source"`
+	HasCredentials bool            `json:"has_credentials"` // True if usable session credentials were found
+	MissingReason  string          `json:"missing_reason"`  // Why HasCredentials is false
+}
+
+// DiscoverAccountsResult contains the accounts found and how many were already managed
+type DiscoverAccountsResult struct {
+	Accounts []DiscoveredAccount `json:"accounts"`
+	Known    int                 `json:"known"` // Accounts skipped because ccx already manages them
+}
+
+// DiscoverAccountsService implements the DiscoverAccountsUseCase
+type DiscoverAccountsService struct {
+	accounts ports.AccountRepository
+	config   ports.ConfigManager
+}
+
+// Ensure DiscoverAccountsService implements DiscoverAccountsUseCase at compile time
+var _ DiscoverAccountsUseCase = (*DiscoverAccountsService)(nil)
+
+// NewDiscoverAccountsService creates a new DiscoverAccountsService
+func NewDiscoverAccountsService(accounts ports.AccountRepository, config ports.ConfigManager) DiscoverAccountsUseCase {
+	return &DiscoverAccountsService{
+		accounts: accounts,
+		config:   config,
+	}
+}
+
+// Execute scans Claude config and the given backups for accounts. It only reads, so
+// repeated calls return the same result until an account is imported. Accounts already
+// managed by ccx, matched by email or UUID, are skipped; an account found in several
+// places is reported once, preferring the location with usable credentials.
+func (s *DiscoverAccountsService) Execute(ctx context.Context, input DiscoverAccountsInput) (*DiscoverAccountsResult, error) {
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	found := make([]DiscoveredAccount, 0)
+
+	current, err := s.config.GetCurrentAccount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current account: %w", err)
+	}
+	if current != nil {
+		found = append(found, DiscoveredAccount{
+			Email:         string(current.Email()),
+			UUID:          current.UUID(),
+			Source:        SourceClaudeConfig,
+			MissingReason: "Claude config does not include a session key",
+		})
+	}
+
+	for i, data := range input.Backups {
+		accounts, err := discoverBackup(data, input.Passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup %d: %w", i+1, err)
+		}
+		found = append(found, accounts...)
+	}
+
+	managed, err := s.accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	return mergeDiscovered(found, managed), nil
+}
+
+// discoverBackup lists the accounts in a backup bundle without importing them
+func discoverBackup(data []byte, passphrase []byte) ([]DiscoveredAccount, error) {
+	var bundle backupBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle: %w", err)
+	}
+	if bundle.Version != BackupVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d (expected %d)", bundle.Version, BackupVersion)
+	}
+
+	accounts := make([]DiscoveredAccount, 0, len(bundle.Accounts))
+	for _, entry := range bundle.Accounts {
+		email := domain.NormalizeEmail(entry.Email)
+		if err := domain.ValidateEmail(string(email)); err != nil {
+			return nil, fmt.Errorf("invalid account %s in bundle: %w", entry.Email, err)
+		}
+		discovered := DiscoveredAccount{
+			Email:  string(email),
+			UUID:   entry.UUID,
+			Source: SourceBackup,
+		}
+		discovered.MissingReason = backupCredentialsProblem(entry, passphrase)
+		discovered.HasCredentials = discovered.MissingReason == ""
+		accounts = append(accounts, discovered)
+	}
+	return accounts, nil
+}
+
+// backupCredentialsProblem explains why a backup entry's credentials are unusable,
+// or returns "" if they are usable
+func backupCredentialsProblem(entry backupAccount, passphrase []byte) string {
+	if len(entry.Credentials) == 0 || string(entry.Credentials) == "null" {
+		return "backup has no credentials for this account"
+	}
+	creds, err := domain.DeserializeCredentials(entry.Credentials)
+	if err != nil {
+		return "backup credentials are malformed"
+	}
+	if creds.AccountID() != domain.AccountID(entry.ID) {
+		return "backup credentials belong to a different account"
+	}
+	if len(passphrase) == 0 {
+		return ""
+	}
+	plaintext, err := creds.DecryptWithPassphrase(passphrase)
+	if err != nil {
+		return "backup credentials do not decrypt with the given passphrase"
+	}
+	if err := domain.ValidateCredentialData(plaintext); err != nil {
+		return "backup credentials have no session key"
+	}
+	return ""
+}
+
+// mergeDiscovered drops accounts ccx already manages and collapses duplicates by email
+func mergeDiscovered(found []DiscoveredAccount, managed []*domain.Account) *DiscoverAccountsResult {
+	emails := make(map[domain.Email]struct{}, len(managed))
+	uuids := make(map[string]struct{}, len(managed))
+	for _, account := range managed {
+		emails[account.Email()] = struct{}{}
+		if account.UUID() != "" {
+			uuids[account.UUID()] = struct{}{}
+		}
+	}
+
+	result := &DiscoverAccountsResult{Accounts: make([]DiscoveredAccount, 0, len(found))}
+	known := make(map[domain.Email]struct{})
+	index := make(map[domain.Email]int, len(found))
+	for _, account := range found {
+		email := domain.NormalizeEmail(account.Email)
+		_, byEmail := emails[email]
+		_, byUUID := uuids[account.UUID]
+		if byEmail || (account.UUID != "" && byUUID) {
+			known[email] = struct{}{}
+			continue
+		}
+
+		if i, dup := index[email]; dup {
+			if account.HasCredentials && !result.Accounts[i].HasCredentials {
+				result.Accounts[i] = account
+			}
+			continue
+		}
+		index[email] = len(result.Accounts)
+		result.Accounts = append(result.Accounts, account)
+	}
+	result.Known = len(known)
+
+	return result
+}
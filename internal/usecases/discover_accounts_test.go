@@ -0,0 +1,131 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// TestDiscoverAccountsUseCase_Execute_ClaudeConfig tests discovering the active Claude account
+func TestDiscoverAccountsUseCase_Execute_ClaudeConfig(t *testing.T) {
+	ctx := context.Background()
+	accountRepo, configManager := newMockAccountRepository(), newMockConfigManager()
+	configManager.currentAccount, _ = domain.NewAccount("New@Example.com", "", "uuid-new")
+	useCase := usecases.NewDiscoverAccountsService(accountRepo, configManager)
+
+	result, err := useCase.Execute(ctx, usecases.DiscoverAccountsInput{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if len(result.Accounts) != 1 {
+		t.Fatalf("len(Accounts) = %d, want 1", len(result.Accounts))
+	}
+	found := result.Accounts[0]
+	if found.Email != "new@example.com" || found.UUID != "uuid-new" || found.Source != usecases.SourceClaudeConfig {
+		t.Errorf("Accounts[0] = %+v", found)
+	}
+	// Claude config never carries a session key
+	if found.HasCredentials || found.MissingReason == "" {
+		t.Errorf("Accounts[0] HasCredentials = %v, MissingReason = %q", found.HasCredentials, found.MissingReason)
+	}
+
+	// Discovery is read-only, so a second call sees the same account
+	again, err := useCase.Execute(ctx, usecases.DiscoverAccountsInput{})
+	if err != nil || len(again.Accounts) != 1 {
+		t.Errorf("Second Execute() = %+v, %v", again, err)
+	}
+	if accounts, _ := accountRepo.List(ctx); len(accounts) != 0 {
+		t.Errorf("Discovery saved %d accounts", len(accounts))
+	}
+}
+
+// TestDiscoverAccountsUseCase_Execute_SkipsManaged tests that accounts ccx already has,
+// by email or UUID, are not reported
+func TestDiscoverAccountsUseCase_Execute_SkipsManaged(t *testing.T) {
+	ctx := context.Background()
+	accountRepo, configManager := newMockAccountRepository(), newMockConfigManager()
+	managed, _ := domain.NewAccount(testEmailPersonal, "personal", "uuid-personal")
+	_ = accountRepo.Save(ctx, managed)
+	useCase := usecases.NewDiscoverAccountsService(accountRepo, configManager)
+
+	for _, current := range []struct{ email, uuid string }{
+		{testEmailPersonal, "uuid-other"},
+		{"renamed@example.com", "uuid-personal"},
+	} {
+		configManager.currentAccount, _ = domain.NewAccount(current.email, "", current.uuid)
+		result, err := useCase.Execute(ctx, usecases.DiscoverAccountsInput{})
+		if err != nil {
+			t.Fatalf("Execute() error = %v, want nil", err)
+		}
+		if len(result.Accounts) != 0 || result.Known != 1 {
+			t.Errorf("Execute() for %s = %+v, want only a known account", current.email, result)
+		}
+	}
+}
+
+// TestDiscoverAccountsUseCase_Execute_Backups tests discovering accounts in backup bundles
+func TestDiscoverAccountsUseCase_Execute_Backups(t *testing.T) {
+	ctx := context.Background()
+	bundle := exportBundle(t, setupExportSource())
+
+	accountRepo, configManager := newMockAccountRepository(), newMockConfigManager()
+	// The work account is both active in Claude and in the backup
+	configManager.currentAccount, _ = domain.NewAccount(testEmailWork, "", "uuid-work")
+	useCase := usecases.NewDiscoverAccountsService(accountRepo, configManager)
+
+	result, err := useCase.Execute(ctx, usecases.DiscoverAccountsInput{
+		Backups:    [][]byte{bundle},
+		Passphrase: []byte(testBackupPassphrase),
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if len(result.Accounts) != 2 {
+		t.Fatalf("len(Accounts) = %d, want 2: %+v", len(result.Accounts), result.Accounts)
+	}
+	for _, found := range result.Accounts {
+		if !found.HasCredentials || found.Source != usecases.SourceBackup {
+			t.Errorf("Account %s = %+v, want usable backup credentials", found.Email, found)
+		}
+	}
+
+	// A wrong passphrase marks the backup credentials unusable
+	result, err = useCase.Execute(ctx, usecases.DiscoverAccountsInput{
+		Backups:    [][]byte{bundle},
+		Passphrase: []byte("wrong"),
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	for _, found := range result.Accounts {
+		if found.HasCredentials || found.MissingReason == "" {
+			t.Errorf("Account %s = %+v, want unusable credentials", found.Email, found)
+		}
+	}
+}
+
+// TestDiscoverAccountsUseCase_Execute_Errors tests failures while scanning
+func TestDiscoverAccountsUseCase_Execute_Errors(t *testing.T) {
+	accountRepo, configManager := newMockAccountRepository(), newMockConfigManager()
+	useCase := usecases.NewDiscoverAccountsService(accountRepo, configManager)
+
+	if _, err := useCase.Execute(context.Background(), usecases.DiscoverAccountsInput{
+		Backups: [][]byte{[]byte("not json")},
+	}); err == nil {
+		t.Error("Execute() with malformed backup error = nil, want error")
+	}
+
+	configManager.getErr = errors.New("config unreadable")
+	if _, err := useCase.Execute(context.Background(), usecases.DiscoverAccountsInput{}); !errors.Is(err, configManager.getErr) {
+		t.Errorf("Execute() error = %v, want config error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := useCase.Execute(ctx, usecases.DiscoverAccountsInput{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,276 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// DoctorUseCase defines the interface for detecting inconsistent ccx state
+type DoctorUseCase interface {
+	Execute(ctx context.Context) (*DoctorReport, error)
+}
+
+// DoctorFindingKind classifies an inconsistency found by the doctor
+type DoctorFindingKind string
+
+// Doctor finding kinds
+const (
+	FindingOrphanedCredentials   DoctorFindingKind = "orphaned_credentials"
+	FindingMissingCredentials    DoctorFindingKind = "missing_credentials"
+	FindingUnknownCurrentAccount DoctorFindingKind = "unknown_current_account"
+	FindingConfigUnreadable      DoctorFindingKind = "config_unreadable"
+	FindingLoosePermissions      DoctorFindingKind = "loose_permissions"
+	FindingInvalidAccountRecord  DoctorFindingKind = "invalid_account_record"
+)
+
+// DoctorSeverity ranks how much a finding affects ccx
+type DoctorSeverity string
+
+// Doctor severities
+const (
+	SeverityError   DoctorSeverity = "error"   // Breaks an operation such as switching
+	SeverityWarning DoctorSeverity = "warning" // Harmless leftovers or drift worth cleaning up
+)
+
+// DoctorFinding describes a single inconsistency
+type DoctorFinding struct {
+	Kind         DoctorFindingKind `json:"kind"`          // Machine-readable classification
+	Severity     DoctorSeverity    `json:"severity"`      // How much the finding matters
+	AccountID    domain.AccountID  `json:"account_id"`    // Affected account ID, empty if not tied to one
+	Email        string            `json:"email"`         // Affected email, empty if unknown
+	Message      string            `json:"message"`       // Human-readable explanation
+	SuggestedFix string            `json:"suggested_fix"` // What the user can do about it
+	Repaired     bool              `json:"repaired"`      // True if auto-repair fixed the finding
+}
+
+// DoctorReport contains every finding of a doctor run
+type DoctorReport struct {
+	Findings []DoctorFinding `json:"findings"` // Inconsistencies found; empty if the state is healthy
+	// OrphanCheckSkipped is true when the credential store cannot enumerate its
+	// contents, so orphaned credentials could not be looked for
+	OrphanCheckSkipped bool `json:"orphan_check_skipped"`
+}
+
+// Healthy reports whether no unrepaired findings remain
+func (r *DoctorReport) Healthy() bool {
+	for _, finding := range r.Findings {
+		if !finding.Repaired {
+			return false
+		}
+	}
+	return true
+}
+
+// DoctorOption configures optional DoctorService behavior
+type DoctorOption func(*DoctorService)
+
+// WithAutoRepair makes the doctor fix findings that need no user input, which
+// currently means deleting orphaned credentials
+func WithAutoRepair() DoctorOption {
+	return func(s *DoctorService) {
+		s.repair = true
+	}
+}
+
+// DoctorService implements the DoctorUseCase
+type DoctorService struct {
+	accounts    ports.AccountRepository
+	credentials ports.CredentialStore
+	config      ports.ConfigManager
+	repair      bool
+}
+
+// Ensure DoctorService implements DoctorUseCase at compile time
+var _ DoctorUseCase = (*DoctorService)(nil)
+
+// NewDoctorService creates a new DoctorService
+func NewDoctorService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+	config ports.ConfigManager,
+	opts ...DoctorOption,
+) DoctorUseCase {
+	s := &DoctorService{
+		accounts:    accounts,
+		credentials: credentials,
+		config:      config,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Execute cross-references accounts, stored credentials, and Claude config and reports
+// every inconsistency. Nothing is changed unless auto-repair is enabled.
+func (s *DoctorService) Execute(ctx context.Context) (*DoctorReport, error) {
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	accounts, err := s.accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	report := &DoctorReport{Findings: []DoctorFinding{}}
+
+	// Malformed account records, which List skipped
+	if err := s.checkAccountRecords(ctx, report); err != nil {
+		return nil, err
+	}
+
+	// Accounts whose credentials are gone
+	known := make(map[domain.AccountID]bool, len(accounts))
+	for _, account := range accounts {
+		known[account.ID()] = true
+		if _, err := s.credentials.Retrieve(ctx, account.ID()); err != nil {
+			report.Findings = append(report.Findings, DoctorFinding{
+				Kind:         FindingMissingCredentials,
+				Severity:     SeverityError,
+				AccountID:    account.ID(),
+				Email:        string(account.Email()),
+				Message:      fmt.Sprintf("credentials unavailable for %s: %v", account.Email(), err),
+				SuggestedFix: "repair the account's credentials or remove and re-add it",
+			})
+		}
+	}
+
+	// Credentials that no account refers to
+	if err := s.checkOrphanedCredentials(ctx, known, report); err != nil {
+		return nil, err
+	}
+
+	// Credential files other users could read
+	if err := s.checkPermissions(ctx, report); err != nil {
+		return nil, err
+	}
+
+	// Current Claude account that ccx does not manage
+	s.checkCurrentAccount(ctx, report)
+
+	return report, nil
+}
+
+// checkAccountRecords reports stored account records that are malformed or duplicated. Repositories
+// that cannot skip such records fail List instead, so there is nothing to report.
+func (s *DoctorService) checkAccountRecords(ctx context.Context, report *DoctorReport) error {
+	validator, ok := s.accounts.(ports.AccountRecordValidator)
+	if !ok {
+		return nil
+	}
+
+	issues, err := validator.LoadWarnings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to validate account records: %w", err)
+	}
+
+	for _, issue := range issues {
+		report.Findings = append(report.Findings, DoctorFinding{
+			Kind:         FindingInvalidAccountRecord,
+			Severity:     SeverityError,
+			AccountID:    issue.AccountID,
+			Message:      fmt.Sprintf("account record %d was skipped: %s", issue.Index, strings.Join(issue.Problems, "; ")),
+			SuggestedFix: "fix or remove the record in the accounts file",
+		})
+	}
+
+	return nil
+}
+
+// checkOrphanedCredentials reports (and optionally deletes) credentials without an account
+func (s *DoctorService) checkOrphanedCredentials(ctx context.Context, known map[domain.AccountID]bool, report *DoctorReport) error {
+	lister, ok := s.credentials.(ports.CredentialLister)
+	if !ok {
+		report.OrphanCheckSkipped = true
+		return nil
+	}
+
+	ids, err := lister.ListAccountIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list stored credentials: %w", err)
+	}
+
+	for _, id := range ids {
+		if known[id] {
+			continue
+		}
+		finding := DoctorFinding{
+			Kind:         FindingOrphanedCredentials,
+			Severity:     SeverityWarning,
+			AccountID:    id,
+			Message:      fmt.Sprintf("credentials stored for unknown account %s", id),
+			SuggestedFix: "delete the orphaned credentials",
+		}
+		if s.repair {
+			if err := s.credentials.Delete(ctx, id); err != nil {
+				finding.Message += fmt.Sprintf(" (repair failed: %v)", err)
+			} else {
+				finding.Repaired = true
+			}
+		}
+		report.Findings = append(report.Findings, finding)
+	}
+
+	return nil
+}
+
+// checkPermissions reports stored credentials and their directories that other users can
+// access. Stores that do not live on the filesystem are skipped.
+func (s *DoctorService) checkPermissions(ctx context.Context, report *DoctorReport) error {
+	verifier, ok := s.credentials.(ports.PermissionVerifier)
+	if !ok {
+		return nil
+	}
+
+	issues, err := verifier.VerifyPermissions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to verify credential permissions: %w", err)
+	}
+
+	for _, issue := range issues {
+		report.Findings = append(report.Findings, DoctorFinding{
+			Kind:         FindingLoosePermissions,
+			Severity:     SeverityWarning,
+			Message:      fmt.Sprintf("%s has mode %o and is accessible by other users", issue.Path, issue.Mode.Perm()),
+			SuggestedFix: fmt.Sprintf("run chmod %o on %s", issue.Want.Perm(), issue.Path),
+		})
+	}
+
+	return nil
+}
+
+// checkCurrentAccount reports an unreadable config or a current account ccx does not manage
+func (s *DoctorService) checkCurrentAccount(ctx context.Context, report *DoctorReport) {
+	current, err := s.config.GetCurrentAccount(ctx)
+	if err != nil {
+		report.Findings = append(report.Findings, DoctorFinding{
+			Kind:         FindingConfigUnreadable,
+			Severity:     SeverityError,
+			Message:      fmt.Sprintf("cannot read Claude config: %v", err),
+			SuggestedFix: "check that the Claude config file exists and is valid JSON",
+		})
+		return
+	}
+	if current == nil {
+		return
+	}
+
+	if _, err := s.accounts.FindByEmail(ctx, current.Email()); errors.Is(err, domain.ErrAccountNotFound) {
+		report.Findings = append(report.Findings, DoctorFinding{
+			Kind:         FindingUnknownCurrentAccount,
+			Severity:     SeverityWarning,
+			Email:        string(current.Email()),
+			Message:      fmt.Sprintf("current Claude account %s is not managed by ccx", current.Email()),
+			SuggestedFix: "add the current account to ccx so it can be switched back to",
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// listingCredentialStore is a mock credential store that can enumerate its contents
+type listingCredentialStore struct {
+	*mockCredentialStore
+}
+
+func (m *listingCredentialStore) ListAccountIDs(_ context.Context) ([]domain.AccountID, error) {
+	ids := make([]domain.AccountID, 0, len(m.credentials))
+	for id := range m.credentials {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// permissionCredentialStore is a mock credential store that reports permission issues
+type permissionCredentialStore struct {
+	*mockCredentialStore
+	issues []ports.PermissionIssue
+}
+
+func (m *permissionCredentialStore) VerifyPermissions(_ context.Context) ([]ports.PermissionIssue, error) {
+	return m.issues, nil
+}
+
+// validatingAccountRepository is a mock account repository that reports malformed records
+type validatingAccountRepository struct {
+	*mockAccountRepository
+	issues []ports.AccountRecordIssue
+}
+
+func (m *validatingAccountRepository) LoadWarnings(_ context.Context) ([]ports.AccountRecordIssue, error) {
+	return m.issues, nil
+}
+
+// findingKinds returns the kinds of the given findings, in order
+func findingKinds(findings []usecases.DoctorFinding) []usecases.DoctorFindingKind {
+	kinds := make([]usecases.DoctorFindingKind, 0, len(findings))
+	for _, finding := range findings {
+		kinds = append(kinds, finding.Kind)
+	}
+	return kinds
+}
+
+// setupDoctorTest creates a store with one healthy account, one account missing
+// credentials, orphaned credentials, and an unmanaged current Claude account
+func setupDoctorTest(t *testing.T) (*mockAccountRepository, *listingCredentialStore, *mockConfigManager) {
+	t.Helper()
+	ctx := context.Background()
+
+	accountRepo := newMockAccountRepository()
+	credentialStore := &listingCredentialStore{newMockCredentialStore()}
+	configManager := newMockConfigManager()
+
+	healthy, _ := domain.NewAccount("healthy@example.com", "healthy", "uuid-healthy")
+	missing, _ := domain.NewAccount("missing@example.com", "missing", "uuid-missing")
+	_ = accountRepo.Save(ctx, healthy)
+	_ = accountRepo.Save(ctx, missing)
+
+	healthyCreds, _ := domain.NewCredentials(healthy.ID(), []byte(`{"sessionKey":"k"}`))
+	orphanCreds, _ := domain.NewCredentials("orphan01", []byte(`{"sessionKey":"k"}`))
+	_ = credentialStore.Store(ctx, healthyCreds)
+	_ = credentialStore.Store(ctx, orphanCreds)
+
+	unmanaged, _ := domain.NewAccount("stranger@example.com", "", "uuid-stranger")
+	configManager.currentAccount = unmanaged
+
+	return accountRepo, credentialStore, configManager
+}
+
+// TestDoctorUseCase_Execute_Report tests that every kind of drift is reported without changes
+func TestDoctorUseCase_Execute_Report(t *testing.T) {
+	ctx := context.Background()
+	accountRepo, credentialStore, configManager := setupDoctorTest(t)
+
+	useCase := usecases.NewDoctorService(accountRepo, credentialStore, configManager)
+	report, err := useCase.Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	want := []usecases.DoctorFindingKind{
+		usecases.FindingMissingCredentials,
+		usecases.FindingOrphanedCredentials,
+		usecases.FindingUnknownCurrentAccount,
+	}
+	if got := findingKinds(report.Findings); !slices.Equal(got, want) {
+		t.Fatalf("finding kinds = %v, want %v", got, want)
+	}
+	if report.Healthy() {
+		t.Error("Healthy() = true, want false")
+	}
+	if report.OrphanCheckSkipped {
+		t.Error("OrphanCheckSkipped = true, want false for a listing store")
+	}
+
+	missing := report.Findings[0]
+	if missing.Severity != usecases.SeverityError || missing.Email != "missing@example.com" {
+		t.Errorf("missing credentials finding = %+v", missing)
+	}
+	orphan := report.Findings[1]
+	if orphan.Severity != usecases.SeverityWarning || orphan.AccountID != "orphan01" || orphan.Repaired {
+		t.Errorf("orphaned credentials finding = %+v", orphan)
+	}
+	for _, finding := range report.Findings {
+		if finding.SuggestedFix == "" {
+			t.Errorf("finding %s has no suggested fix", finding.Kind)
+		}
+	}
+
+	// Nothing was mutated
+	if _, ok := credentialStore.credentials["orphan01"]; !ok {
+		t.Error("Orphaned credentials should not be deleted without auto-repair")
+	}
+}
+
+// TestDoctorUseCase_Execute_AutoRepair tests that auto-repair deletes orphaned credentials
+func TestDoctorUseCase_Execute_AutoRepair(t *testing.T) {
+	ctx := context.Background()
+	accountRepo, credentialStore, configManager := setupDoctorTest(t)
+
+	useCase := usecases.NewDoctorService(accountRepo, credentialStore, configManager, usecases.WithAutoRepair())
+	report, err := useCase.Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	for _, finding := range report.Findings {
+		wantRepaired := finding.Kind == usecases.FindingOrphanedCredentials
+		if finding.Repaired != wantRepaired {
+			t.Errorf("finding %s Repaired = %v, want %v", finding.Kind, finding.Repaired, wantRepaired)
+		}
+	}
+	if _, ok := credentialStore.credentials["orphan01"]; ok {
+		t.Error("Orphaned credentials should be deleted by auto-repair")
+	}
+}
+
+// TestDoctorUseCase_Execute_Healthy tests a consistent store
+func TestDoctorUseCase_Execute_Healthy(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository()
+	credentialStore := &listingCredentialStore{newMockCredentialStore()}
+	configManager := newMockConfigManager()
+
+	account, _ := domain.NewAccount("user@example.com", "user", "uuid-user")
+	_ = accountRepo.Save(ctx, account)
+	creds, _ := domain.NewCredentials(account.ID(), []byte(`{"sessionKey":"k"}`))
+	_ = credentialStore.Store(ctx, creds)
+	configManager.currentAccount = account
+
+	report, err := usecases.NewDoctorService(accountRepo, credentialStore, configManager).Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if !report.Healthy() || len(report.Findings) != 0 {
+		t.Errorf("Expected healthy report, got %+v", report.Findings)
+	}
+}
+
+// TestDoctorUseCase_Execute_NonListingStore tests that the orphan check is skipped when
+// the credential store cannot enumerate its contents
+func TestDoctorUseCase_Execute_NonListingStore(t *testing.T) {
+	ctx := context.Background()
+	configManager := newMockConfigManager()
+	configManager.getErr = errors.New("config corrupted")
+
+	report, err := usecases.NewDoctorService(newMockAccountRepository(), newMockCredentialStore(), configManager).Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if !report.OrphanCheckSkipped {
+		t.Error("OrphanCheckSkipped = false, want true")
+	}
+	want := []usecases.DoctorFindingKind{usecases.FindingConfigUnreadable}
+	if got := findingKinds(report.Findings); !slices.Equal(got, want) {
+		t.Errorf("finding kinds = %v, want %v", got, want)
+	}
+}
+
+// TestDoctorUseCase_Execute_LoosePermissions tests that credential files and directories
+// other users can access are reported with a fix
+func TestDoctorUseCase_Execute_LoosePermissions(t *testing.T) {
+	ctx := context.Background()
+	credentialStore := &permissionCredentialStore{
+		mockCredentialStore: newMockCredentialStore(),
+		issues: []ports.PermissionIssue{
+			{Path: "/data/credentials", Mode: fs.ModeDir | 0o755, Want: 0o700},
+			{Path: "/data/credentials/abc12345.json", Mode: 0o644, Want: 0o600},
+		},
+	}
+
+	report, err := usecases.NewDoctorService(newMockAccountRepository(), credentialStore, newMockConfigManager()).Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	want := []usecases.DoctorFindingKind{usecases.FindingLoosePermissions, usecases.FindingLoosePermissions}
+	if got := findingKinds(report.Findings); !slices.Equal(got, want) {
+		t.Fatalf("finding kinds = %v, want %v", got, want)
+	}
+	if !strings.Contains(report.Findings[0].SuggestedFix, "chmod 700") || !strings.Contains(report.Findings[1].SuggestedFix, "chmod 600") {
+		t.Errorf("SuggestedFix = %q, want chmod 600", report.Findings[1].SuggestedFix)
+	}
+}
+
+// TestDoctorUseCase_Execute_InvalidAccountRecords tests that account records skipped
+// by the repository are reported by position and ID
+func TestDoctorUseCase_Execute_InvalidAccountRecords(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := &validatingAccountRepository{
+		mockAccountRepository: newMockAccountRepository(),
+		issues: []ports.AccountRecordIssue{
+			{Index: 1, AccountID: "bad12345", Problems: []string{"missing uuid", "missing created_at"}},
+		},
+	}
+
+	report, err := usecases.NewDoctorService(accountRepo, newMockCredentialStore(), newMockConfigManager()).Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	want := []usecases.DoctorFindingKind{usecases.FindingInvalidAccountRecord}
+	if got := findingKinds(report.Findings); !slices.Equal(got, want) {
+		t.Fatalf("finding kinds = %v, want %v", got, want)
+	}
+	finding := report.Findings[0]
+	if finding.AccountID != "bad12345" || !strings.Contains(finding.Message, "missing uuid; missing created_at") {
+		t.Errorf("finding = %+v, want record bad12345 with its problems", finding)
+	}
+}
+
+// TestDoctorUseCase_Execute_ContextCancellation tests context cancellation
+func TestDoctorUseCase_Execute_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	useCase := usecases.NewDoctorService(newMockAccountRepository(), newMockCredentialStore(), newMockConfigManager())
+	if _, err := useCase.Execute(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want context.Canceled", err)
+	}
+}
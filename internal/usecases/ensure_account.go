@@ -0,0 +1,159 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// EnsureAccountUseCase defines the interface for adding an account only if ccx doesn't
+// already manage its email, for scripts that provision accounts and may run repeatedly
+type EnsureAccountUseCase interface {
+	Execute(ctx context.Context, input EnsureAccountInput) (*EnsureResult, error)
+}
+
+// EnsureAccountInput contains the input data for ensuring an account exists
+type EnsureAccountInput struct {
+	AddAccountInput
+	// UpdateIfExists brings an existing account in line with the input: a given Alias
+	// replaces its alias and Credentials replace its stored credentials
+	UpdateIfExists bool
+}
+
+// EnsureResult contains the result of ensuring an account exists
+type EnsureResult struct {
+	Account AccountInfo `json:"account"` // Account as it is now
+	Created bool        `json:"created"` // True if the account was added
+	Updated bool        `json:"updated"` // True if an existing account's alias or credentials changed
+}
+
+// EnsureAccountService implements the EnsureAccountUseCase
+type EnsureAccountService struct {
+	add         *AddAccountService
+	accounts    ports.AccountRepository
+	credentials ports.CredentialStore
+}
+
+// Ensure EnsureAccountService implements EnsureAccountUseCase at compile time
+var _ EnsureAccountUseCase = (*EnsureAccountService)(nil)
+
+// NewEnsureAccountService creates a new EnsureAccountService. Options apply to the add
+// performed when the account is missing.
+func NewEnsureAccountService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+	config ports.ConfigManager,
+	opts ...AddAccountOption,
+) EnsureAccountUseCase {
+	return &EnsureAccountService{
+		add:         newAddAccountService(accounts, credentials, config, opts...),
+		accounts:    accounts,
+		credentials: credentials,
+	}
+}
+
+// Execute adds the account the way AddAccountUseCase does unless an account with its
+// email already exists, in which case the account is left alone, or updated with
+// UpdateIfExists, and Created is false. Only an existing email counts as success:
+// an archived account, a UUID used by another email, and every storage failure are
+// returned as errors.
+func (s *EnsureAccountService) Execute(ctx context.Context, input EnsureAccountInput) (*EnsureResult, error) {
+	err := s.add.Execute(ctx, input.AddAccountInput)
+	if err != nil && !errors.Is(err, domain.ErrDuplicateAccount) {
+		return nil, err
+	}
+
+	// Add got far enough to check for duplicates, so the details are valid
+	email, _, _, credentialData, detailsErr := s.add.determineAccountDetails(ctx, input.AddAccountInput)
+	if detailsErr != nil {
+		return nil, detailsErr
+	}
+	account, findErr := s.accounts.FindByEmail(ctx, domain.NormalizeEmail(email))
+	if findErr != nil {
+		return nil, fmt.Errorf("failed to find account: %w", findErr)
+	}
+
+	if err == nil {
+		return &EnsureResult{Account: newAccountInfo(account), Created: true}, nil
+	}
+	if account.Archived() {
+		return nil, err
+	}
+
+	result := &EnsureResult{Account: newAccountInfo(account)}
+	if !input.UpdateIfExists {
+		return result, nil
+	}
+
+	updated, err := s.update(ctx, account, input.Alias, credentialData)
+	if err != nil {
+		return nil, err
+	}
+	if updated != nil {
+		result.Account = newAccountInfo(updated)
+		result.Updated = true
+	}
+	return result, nil
+}
+
+// update gives account the alias, if set, and the credentials as one unit of work. It
+// returns the updated account, or nil if both already matched.
+func (s *EnsureAccountService) update(ctx context.Context, account *domain.Account, alias string, credentialData []byte) (*domain.Account, error) {
+	renamed := account
+	if alias != "" && alias != account.Alias() {
+		if err := checkAliasAvailable(ctx, s.accounts, account.ID(), alias); err != nil {
+			return nil, err
+		}
+		// The repository may hand the same account to other callers, so rename a copy
+		renamed = account.Clone()
+		if err := renamed.UpdateAlias(alias); err != nil {
+			return nil, fmt.Errorf("invalid alias: %w", err)
+		}
+	}
+
+	oldCreds, err := s.credentials.Retrieve(ctx, account.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credentials for %s: %w", account.Email(), err)
+	}
+	var newCreds *domain.Credentials
+	if current, err := oldCreds.Decrypt(); err != nil || !bytes.Equal(current, credentialData) {
+		// Keep the existing encryption scheme, as a credential rotation does
+		newCreds = oldCreds.Clone()
+		if err := newCreds.UpdateData(credentialData); err != nil {
+			return nil, fmt.Errorf("failed to update credentials for %s: %w", account.Email(), err)
+		}
+	}
+
+	if renamed == account && newCreds == nil {
+		return nil, nil
+	}
+
+	tx := NewTransaction()
+	if newCreds != nil {
+		err = tx.Do(ctx,
+			func(ctx context.Context) error { return s.credentials.Store(ctx, newCreds) },
+			func(ctx context.Context) error { return s.credentials.Store(ctx, oldCreds) },
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store credentials: %w", err)
+		}
+	}
+	if renamed != account {
+		err = tx.Do(ctx,
+			func(ctx context.Context) error { return s.accounts.Save(ctx, renamed) },
+			func(ctx context.Context) error { return s.accounts.Save(ctx, account) },
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save account: %w", err)
+		}
+	}
+	tx.Commit()
+
+	return renamed, nil
+}
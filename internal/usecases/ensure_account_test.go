@@ -0,0 +1,148 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// ensureSessionKey decrypts the credentials stored for an account
+func ensureSessionKey(t *testing.T, store *mockCredentialStore, id domain.AccountID) string {
+	t.Helper()
+
+	creds, err := store.Retrieve(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	data, err := creds.Decrypt()
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	return string(data)
+}
+
+// TestEnsureAccountUseCase_Execute tests that the account is added once and later calls
+// leave it alone
+func TestEnsureAccountUseCase_Execute(t *testing.T) {
+	setup := setupTest()
+	ctx := context.Background()
+	useCase := usecases.NewEnsureAccountService(setup.accountRepo, setup.credentialStore, setup.configManager)
+
+	input := usecases.EnsureAccountInput{AddAccountInput: usecases.AddAccountInput{
+		Email: testEmailWork, Alias: "work", Credentials: []byte(`{"sessionKey": "first"}`),
+	}}
+	result, err := useCase.Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if !result.Created || result.Updated || result.Account.Email != testEmailWork {
+		t.Errorf("Execute() = %+v, want the work account created", result)
+	}
+
+	// Again, with different details that are ignored without UpdateIfExists
+	input.Alias = "job"
+	input.Credentials = []byte(`{"sessionKey": "second"}`)
+	result, err = useCase.Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() again error = %v, want nil", err)
+	}
+	if result.Created || result.Updated || result.Account.Alias != "work" {
+		t.Errorf("Execute() again = %+v, want the existing account untouched", result)
+	}
+	if len(setup.accountRepo.accounts) != 1 {
+		t.Errorf("Stored %d accounts, want 1", len(setup.accountRepo.accounts))
+	}
+	if key := ensureSessionKey(t, setup.credentialStore, domain.AccountID(result.Account.ID)); key != `{"sessionKey": "first"}` {
+		t.Errorf("Stored credentials = %s, want the first ones", key)
+	}
+}
+
+// TestEnsureAccountUseCase_Execute_UpdateIfExists tests that an existing account takes
+// the input's alias and credentials
+func TestEnsureAccountUseCase_Execute_UpdateIfExists(t *testing.T) {
+	setup := setupTest()
+	ctx := context.Background()
+	useCase := usecases.NewEnsureAccountService(setup.accountRepo, setup.credentialStore, setup.configManager)
+
+	add := usecases.AddAccountInput{Email: testEmailWork, Alias: "work", Credentials: []byte(`{"sessionKey": "first"}`)}
+	if _, err := useCase.Execute(ctx, usecases.EnsureAccountInput{AddAccountInput: add}); err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	// Matching details change nothing
+	result, err := useCase.Execute(ctx, usecases.EnsureAccountInput{AddAccountInput: add, UpdateIfExists: true})
+	if err != nil || result.Created || result.Updated {
+		t.Fatalf("Execute() with matching details = %+v, %v; want no change", result, err)
+	}
+
+	add.Alias = "job"
+	add.Credentials = []byte(`{"sessionKey": "second"}`)
+	result, err = useCase.Execute(ctx, usecases.EnsureAccountInput{AddAccountInput: add, UpdateIfExists: true})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.Created || !result.Updated || result.Account.Alias != "job" {
+		t.Errorf("Execute() = %+v, want the account updated to alias job", result)
+	}
+	if key := ensureSessionKey(t, setup.credentialStore, domain.AccountID(result.Account.ID)); key != `{"sessionKey": "second"}` {
+		t.Errorf("Stored credentials = %s, want the second ones", key)
+	}
+
+	// A failed credential write leaves the alias alone too
+	setup.credentialStore.storeErr = errors.New("keychain locked")
+	add.Alias = "other"
+	add.Credentials = []byte(`{"sessionKey": "third"}`)
+	if _, err := useCase.Execute(ctx, usecases.EnsureAccountInput{AddAccountInput: add, UpdateIfExists: true}); !errors.Is(err, setup.credentialStore.storeErr) {
+		t.Fatalf("Execute() error = %v, want %v", err, setup.credentialStore.storeErr)
+	}
+	if account, _ := setup.accountRepo.FindByEmail(ctx, testEmailWork); account.Alias() != "job" {
+		t.Errorf("Alias() = %q after failed update, want job", account.Alias())
+	}
+}
+
+// TestEnsureAccountUseCase_Execute_Errors tests that genuine failures are not mistaken
+// for an existing account
+func TestEnsureAccountUseCase_Execute_Errors(t *testing.T) {
+	ctx := context.Background()
+	creds := []byte(`{"sessionKey": "key"}`)
+
+	t.Run("credential store failure", func(t *testing.T) {
+		setup := setupTest()
+		setup.credentialStore.storeErr = errors.New("keychain locked")
+		useCase := usecases.NewEnsureAccountService(setup.accountRepo, setup.credentialStore, setup.configManager)
+
+		_, err := useCase.Execute(ctx, usecases.EnsureAccountInput{AddAccountInput: usecases.AddAccountInput{Email: testEmailWork, Credentials: creds}})
+		if !errors.Is(err, setup.credentialStore.storeErr) {
+			t.Errorf("Execute() error = %v, want %v", err, setup.credentialStore.storeErr)
+		}
+	})
+
+	t.Run("archived account", func(t *testing.T) {
+		setup := setupTest()
+		archived, _ := domain.NewAccount(testEmailWork, "work", "uuid-work")
+		archived.Archive(archived.CreatedAt())
+		_ = setup.accountRepo.Save(ctx, archived)
+		useCase := usecases.NewEnsureAccountService(setup.accountRepo, setup.credentialStore, setup.configManager)
+
+		_, err := useCase.Execute(ctx, usecases.EnsureAccountInput{AddAccountInput: usecases.AddAccountInput{Email: testEmailWork, Credentials: creds}})
+		if !errors.Is(err, domain.ErrDuplicateAccount) {
+			t.Errorf("Execute() error = %v, want ErrDuplicateAccount for an archived account", err)
+		}
+	})
+
+	t.Run("UUID used by another email", func(t *testing.T) {
+		setup := setupTest()
+		other, _ := domain.NewAccount(testEmailPersonal, "personal", "uuid-shared")
+		_ = setup.accountRepo.Save(ctx, other)
+		setup.configManager.currentAccount, _ = domain.NewAccount(testEmailWork, "", "uuid-shared")
+		useCase := usecases.NewEnsureAccountService(setup.accountRepo, setup.credentialStore, setup.configManager)
+
+		_, err := useCase.Execute(ctx, usecases.EnsureAccountInput{AddAccountInput: usecases.AddAccountInput{Credentials: creds}})
+		if !errors.Is(err, domain.ErrDuplicateUUID) {
+			t.Errorf("Execute() error = %v, want ErrDuplicateUUID", err)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+// EnvelopeStatus reports whether the wrapped operation succeeded
+type EnvelopeStatus string
+
+// Envelope statuses
+const (
+	EnvelopeOK    EnvelopeStatus = "ok"
+	EnvelopeError EnvelopeStatus = "error"
+)
+
+// Envelope is the top-level JSON document for machine-readable output, so every use
+// case result can be marshaled the same way.
+//
+// Result types form a stable contract for scripts: fields are snake_case, times are
+// RFC3339 (zero times are "0001-01-01T00:00:00Z"), and every key is always present,
+// with absent optional values such as SwitchAccountResult.From encoded as null.
+type Envelope struct {
+	Status EnvelopeStatus `json:"status"` // EnvelopeOK or EnvelopeError
+	Data   any            `json:"data"`   // The use case result, null on error
+	Error  string         `json:"error"`  // Error message, empty on success
+}
+
+// NewEnvelope wraps a successful result
+func NewEnvelope(data any) Envelope {
+	return Envelope{Status: EnvelopeOK, Data: data}
+}
+
+// NewErrorEnvelope wraps a failure. Results returned alongside an error, such as
+// RemoveAccountResult with ErrRemovingCurrentAccount, can be passed as data.
+func NewErrorEnvelope(err error, data any) Envelope {
+	return Envelope{Status: EnvelopeError, Data: data, Error: err.Error()}
+}
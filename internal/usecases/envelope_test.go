@@ -0,0 +1,170 @@
+package usecases_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// assertRoundTrip marshals v and unmarshals it into a fresh T, failing if it changed
+func assertRoundTrip[T any](t *testing.T, v T) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var got T
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("round trip changed value:\n got  %+v\n want %+v\n json %s", got, v, data)
+	}
+}
+
+// TestResults_JSONRoundTrip tests that every result type survives a JSON round trip
+func TestResults_JSONRoundTrip(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	account := usecases.AccountInfo{
+		ID: "abc12345", Email: testEmailWork, Alias: "work", UUID: "uuid-work",
+		Tags: []string{"client", "prod"}, Color: "blue", Label: "Work",
+		CreatedAt: created, LastUsed: created.Add(time.Hour),
+	}
+	other := account
+	other.ID, other.Email, other.Alias, other.Tags = "def67890", testEmailPersonal, "personal", nil
+	usage := usecases.AccountUsage{Account: account, SwitchesTo: 3, SwitchesFrom: 1, SinceLastUsed: 90 * time.Minute}
+
+	tests := []struct {
+		name  string
+		check func(t *testing.T)
+	}{
+		{"AccountInfo", func(t *testing.T) { assertRoundTrip(t, account) }},
+		{"SwitchAccountResult", func(t *testing.T) {
+			assertRoundTrip(t, usecases.SwitchAccountResult{From: &other, To: account, ExpiresAt: created, Expired: true, CurrentDivergedFromHistory: true})
+		}},
+		{"SwitchAccountResult first switch", func(t *testing.T) {
+			assertRoundTrip(t, usecases.SwitchAccountResult{To: account, DryRun: true})
+		}},
+		{"RemoveAccountResult", func(t *testing.T) {
+			assertRoundTrip(t, usecases.RemoveAccountResult{RemovedAccount: account, WasCurrentAccount: true, RemovedProfiles: []string{"client-a"}})
+		}},
+		{"ApplyDefaultAccountResult", func(t *testing.T) {
+			assertRoundTrip(t, usecases.ApplyDefaultAccountResult{Applied: true, Current: &account})
+		}},
+		{"DiscoverAccountsResult", func(t *testing.T) {
+			assertRoundTrip(t, usecases.DiscoverAccountsResult{
+				Accounts: []usecases.DiscoveredAccount{{Email: testEmailTest, UUID: "uuid-test", Source: usecases.SourceBackup, MissingReason: "no session"}},
+				Known:    2,
+			})
+		}},
+		{"DoctorReport", func(t *testing.T) {
+			assertRoundTrip(t, usecases.DoctorReport{Findings: []usecases.DoctorFinding{{
+				Kind: usecases.FindingMissingCredentials, Severity: usecases.SeverityError, AccountID: "abc12345",
+				Email: testEmailWork, Message: "missing", SuggestedFix: "repair", Repaired: true,
+			}}, OrphanCheckSkipped: true})
+		}},
+		{"ExportResult", func(t *testing.T) { assertRoundTrip(t, usecases.ExportResult{AccountCount: 2, HistoryLength: 5}) }},
+		{"SwitchInfo", func(t *testing.T) {
+			assertRoundTrip(t, usecases.SwitchInfo{From: testEmailPersonal, To: testEmailWork, Timestamp: created})
+		}},
+		{"ImportResult", func(t *testing.T) {
+			assertRoundTrip(t, usecases.ImportResult{Imported: []usecases.AccountInfo{account}, RemovedAccounts: 1, HistoryLength: 3})
+		}},
+		{"TagGroup", func(t *testing.T) {
+			assertRoundTrip(t, usecases.TagGroup{Tag: "client", Domains: []usecases.DomainGroup{{Domain: "example.com", Accounts: []usecases.AccountInfo{account}}}})
+		}},
+		{"ProfileInfo", func(t *testing.T) {
+			assertRoundTrip(t, usecases.ProfileInfo{Name: "client-a", AccountID: account.ID, Account: &account})
+		}},
+		{"PreflightResult", func(t *testing.T) {
+			assertRoundTrip(t, usecases.PreflightResult{Target: &account, Issues: []usecases.PreflightIssue{{Kind: usecases.IssueCredentialsExpired, Message: "expired"}}})
+		}},
+		{"RenameAccountResult", func(t *testing.T) {
+			assertRoundTrip(t, usecases.RenameAccountResult{Account: account, OldEmail: testEmailTest, NewEmail: testEmailWork, HistoryRewritten: 2, HistoryDropped: 1})
+		}},
+		{"RepairCredentialsResult", func(t *testing.T) {
+			assertRoundTrip(t, usecases.RepairCredentialsResult{Account: account, WasBroken: true, ForcedReplace: true})
+		}},
+		{"ResetResult", func(t *testing.T) {
+			assertRoundTrip(t, usecases.ResetResult{RemovedAccounts: []usecases.AccountInfo{account, other}, ClearedCurrent: true})
+		}},
+		{"UsageStats", func(t *testing.T) {
+			assertRoundTrip(t, usecases.UsageStats{Accounts: []usecases.AccountUsage{usage}, TotalSwitches: 4, UnknownSwitches: 1, MostSwitchedTo: &usage})
+		}},
+		{"UpdateAccountResult", func(t *testing.T) {
+			assertRoundTrip(t, usecases.UpdateAccountResult{Account: account, OldAlias: "old", NewAlias: "work"})
+		}},
+		{"UpdateCredentialsResult", func(t *testing.T) {
+			assertRoundTrip(t, usecases.UpdateCredentialsResult{Account: account, RotatedAt: created})
+		}},
+		{"AccountVerification", func(t *testing.T) {
+			assertRoundTrip(t, usecases.AccountVerification{Account: account, CredentialsFound: true, ExpiresAt: created, Expired: true, Problem: "expired"})
+		}},
+		{"WhoAmIResult", func(t *testing.T) { assertRoundTrip(t, usecases.WhoAmIResult{Account: account, KnownToCCX: true}) }},
+		{"BulkAddResult", func(t *testing.T) {
+			assertRoundTrip(t, usecases.BulkAddResult{
+				Records: []usecases.BulkAddRecordResult{
+					{Index: 0, Email: testEmailWork, Status: usecases.BulkAddAdded},
+					{Index: 1, Email: "bad", Status: usecases.BulkAddFailed, Err: errors.New("invalid email format")},
+				},
+				Added:  1,
+				Failed: 1,
+			})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, tt.check)
+	}
+}
+
+// TestResults_JSONShape tests the parts of the JSON contract scripts depend on
+func TestResults_JSONShape(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("EST", -5*3600))
+	result := usecases.SwitchAccountResult{To: usecases.AccountInfo{Email: testEmailWork, CreatedAt: created}}
+
+	data, err := json.Marshal(usecases.NewEnvelope(result))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if decoded["status"] != "ok" || decoded["error"] != "" {
+		t.Errorf("envelope = %s, want status ok and empty error", data)
+	}
+	switchData, _ := decoded["data"].(map[string]any)
+	from, ok := switchData["from"]
+	if !ok || from != nil {
+		t.Errorf("from = %v (present %v), want null", from, ok)
+	}
+	to, _ := switchData["to"].(map[string]any)
+	createdAt, _ := to["created_at"].(string)
+	if parsed, err := time.Parse(time.RFC3339, createdAt); err != nil || !parsed.Equal(created) {
+		t.Errorf("created_at = %q, want RFC3339 for %v", createdAt, created)
+	}
+
+	// A failure keeps the same keys, with data null unless a partial result is passed
+	data, _ = json.Marshal(usecases.NewErrorEnvelope(domain.ErrAccountNotFound, nil))
+	if want := `{"status":"error","data":null,"error":"account not found"}`; string(data) != want {
+		t.Errorf("error envelope = %s, want %s", data, want)
+	}
+
+	// Bulk add errors are reported by message, and added records carry null
+	data, _ = json.Marshal(usecases.BulkAddRecordResult{Index: 2, Status: usecases.BulkAddSkipped, Err: domain.ErrDuplicateAccount})
+	if !strings.Contains(string(data), `"error":"account already exists"`) {
+		t.Errorf("bulk add record = %s, want error message", data)
+	}
+	data, _ = json.Marshal(usecases.BulkAddRecordResult{Status: usecases.BulkAddAdded})
+	if !strings.Contains(string(data), `"error":null`) {
+		t.Errorf("bulk add record = %s, want null error", data)
+	}
+}
@@ -0,0 +1,41 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+// EventSink observes account lifecycle events emitted by the use cases, for audit
+// logging or hooks such as restarting a daemon after a switch. Methods are called
+// synchronously once the operation has finished, so they should return quickly.
+type EventSink interface {
+	// OnSwitch is called after the current account changed. from is the zero
+	// AccountInfo when there was no current account.
+	OnSwitch(from, to AccountInfo)
+	// OnSwitchFailed is called when a switch is attempted but does not happen
+	OnSwitchFailed(err error)
+	// OnAccountAdded is called after an account and its credentials were saved
+	OnAccountAdded(account AccountInfo)
+	// OnAccountRemoved is called after an account and its credentials were deleted
+	OnAccountRemoved(account AccountInfo)
+	// OnWarning reports a non-critical failure that did not fail the operation
+	OnWarning(err error)
+}
+
+// NopEventSink is an EventSink that ignores every event. It is the default sink.
+type NopEventSink struct{}
+
+// Ensure NopEventSink implements EventSink at compile time
+var _ EventSink = NopEventSink{}
+
+// OnSwitch does nothing
+func (NopEventSink) OnSwitch(_, _ AccountInfo) {}
+
+// OnSwitchFailed does nothing
+func (NopEventSink) OnSwitchFailed(_ error) {}
+
+// OnAccountAdded does nothing
+func (NopEventSink) OnAccountAdded(_ AccountInfo) {}
+
+// OnAccountRemoved does nothing
+func (NopEventSink) OnAccountRemoved(_ AccountInfo) {}
+
+// OnWarning does nothing
+func (NopEventSink) OnWarning(_ error) {}
@@ -0,0 +1,130 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// recordingEventSink is an EventSink that records every event it receives
+type recordingEventSink struct {
+	switches []struct{ from, to usecases.AccountInfo }
+	failures []error
+	added    []usecases.AccountInfo
+	removed  []usecases.AccountInfo
+	warnings []error
+}
+
+func (r *recordingEventSink) OnSwitch(from, to usecases.AccountInfo) {
+	r.switches = append(r.switches, struct{ from, to usecases.AccountInfo }{from, to})
+}
+
+func (r *recordingEventSink) OnSwitchFailed(err error) {
+	r.failures = append(r.failures, err)
+}
+
+func (r *recordingEventSink) OnAccountAdded(account usecases.AccountInfo) {
+	r.added = append(r.added, account)
+}
+
+func (r *recordingEventSink) OnAccountRemoved(account usecases.AccountInfo) {
+	r.removed = append(r.removed, account)
+}
+
+func (r *recordingEventSink) OnWarning(err error) {
+	r.warnings = append(r.warnings, err)
+}
+
+// TestEvents_Switch tests that a switch reports both accounts
+func TestEvents_Switch(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	sink := &recordingEventSink{}
+	useCase := usecases.NewSwitchAccountService(setup.accountRepo, setup.credentialStore, setup.configManager,
+		setup.historyRepo, usecases.WithSwitchEvents(sink))
+
+	if _, err := useCase.Execute(context.Background(), usecases.SwitchAccountInput{Alias: "work"}); err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if len(sink.switches) != 1 {
+		t.Fatalf("OnSwitch called %d times, want 1", len(sink.switches))
+	}
+	if got := sink.switches[0]; got.from.Email != testEmailPersonal || got.to.Email != testEmailWork {
+		t.Errorf("OnSwitch(%s, %s), want (%s, %s)", got.from.Email, got.to.Email, testEmailPersonal, testEmailWork)
+	}
+	if len(sink.failures) != 0 || len(sink.warnings) != 0 {
+		t.Errorf("Unexpected failures %v or warnings %v", sink.failures, sink.warnings)
+	}
+}
+
+// TestEvents_SwitchFailed tests that a failed switch is reported
+func TestEvents_SwitchFailed(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	sink := &recordingEventSink{}
+	useCase := usecases.NewSwitchAccountService(setup.accountRepo, setup.credentialStore, setup.configManager,
+		setup.historyRepo, usecases.WithSwitchEvents(sink))
+
+	_, err := useCase.Execute(context.Background(), usecases.SwitchAccountInput{Alias: "nonexistent"})
+	if err == nil {
+		t.Fatal("Execute() error = nil, want error")
+	}
+
+	if len(sink.failures) != 1 || !errors.Is(sink.failures[0], err) {
+		t.Errorf("OnSwitchFailed got %v, want [%v]", sink.failures, err)
+	}
+	if len(sink.switches) != 0 {
+		t.Error("OnSwitch should not be called for a failed switch")
+	}
+}
+
+// TestEvents_SwitchHistoryWarning tests that a failed history save is reported as a
+// warning without failing the switch
+func TestEvents_SwitchHistoryWarning(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	setup.historyRepo.saveErr = errors.New("disk full")
+	sink := &recordingEventSink{}
+	useCase := usecases.NewSwitchAccountService(setup.accountRepo, setup.credentialStore, setup.configManager,
+		setup.historyRepo, usecases.WithSwitchEvents(sink))
+
+	if _, err := useCase.Execute(context.Background(), usecases.SwitchAccountInput{Alias: "work"}); err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if len(sink.warnings) != 1 || !errors.Is(sink.warnings[0], setup.historyRepo.saveErr) {
+		t.Errorf("OnWarning got %v, want history save error", sink.warnings)
+	}
+	if len(sink.switches) != 1 {
+		t.Errorf("OnSwitch called %d times, want 1", len(sink.switches))
+	}
+}
+
+// TestEvents_AddAndRemove tests that adding and removing an account are reported
+func TestEvents_AddAndRemove(t *testing.T) {
+	ctx := context.Background()
+	setup := setupRemoveAccountTest()
+	sink := &recordingEventSink{}
+
+	addUseCase := usecases.NewAddAccountService(setup.accountRepo, setup.credentialStore, setup.configManager,
+		usecases.WithAddEvents(sink))
+	err := addUseCase.Execute(ctx, usecases.AddAccountInput{
+		Email:       "new@example.com",
+		Credentials: []byte(`{"sessionKey": "key-new"}`),
+	})
+	if err != nil {
+		t.Fatalf("Add Execute() error = %v, want nil", err)
+	}
+	if len(sink.added) != 1 || sink.added[0].Email != "new@example.com" {
+		t.Fatalf("OnAccountAdded got %+v, want new@example.com", sink.added)
+	}
+
+	removeUseCase := usecases.NewRemoveAccountService(setup.accountRepo, setup.credentialStore, setup.configManager,
+		setup.historyRepo, usecases.WithRemoveEvents(sink))
+	if _, err := removeUseCase.Execute(ctx, usecases.RemoveAccountInput{AccountID: sink.added[0].ID}); err != nil {
+		t.Fatalf("Remove Execute() error = %v, want nil", err)
+	}
+	if len(sink.removed) != 1 || sink.removed[0].ID != sink.added[0].ID {
+		t.Errorf("OnAccountRemoved got %+v, want %s", sink.removed, sink.added[0].ID)
+	}
+}
@@ -0,0 +1,151 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// ExportUseCase defines the interface for exporting the complete ccx state
+type ExportUseCase interface {
+	Execute(ctx context.Context, input ExportInput) (*ExportResult, error)
+}
+
+// ExportInput contains the input data for an export
+type ExportInput struct {
+	Passphrase []byte // Passphrase used to re-encrypt credentials in the bundle
+}
+
+// ExportResult contains the exported bundle
+type ExportResult struct {
+	Data          []byte `json:"-"`              // Versioned JSON bundle; left out of JSON output, write it to a file instead
+	AccountCount  int    `json:"account_count"`  // Number of accounts exported
+	HistoryLength int    `json:"history_length"` // Number of history entries exported
+}
+
+// ExportService implements the ExportUseCase
+type ExportService struct {
+	accounts    ports.AccountRepository
+	credentials ports.CredentialStore
+	history     ports.HistoryRepository
+}
+
+// Ensure ExportService implements ExportUseCase at compile time
+var _ ExportUseCase = (*ExportService)(nil)
+
+// NewExportService creates a new ExportService
+func NewExportService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+	history ports.HistoryRepository,
+) ExportUseCase {
+	return &ExportService{
+		accounts:    accounts,
+		credentials: credentials,
+		history:     history,
+	}
+}
+
+// Execute bundles all accounts, their credentials and the switch history into a single
+// versioned document. Credentials are re-keyed under the supplied passphrase.
+func (s *ExportService) Execute(ctx context.Context, input ExportInput) (*ExportResult, error) {
+	if len(input.Passphrase) == 0 {
+		return nil, errors.New("passphrase is required")
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	accounts, err := s.accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	bundle := backupBundle{
+		Version:    BackupVersion,
+		ExportedAt: time.Now().UTC(),
+		Accounts:   make([]backupAccount, 0, len(accounts)),
+		History:    []backupSwitch{},
+	}
+
+	for _, account := range accounts {
+		creds, err := exportCredentials(ctx, s.credentials, account, input.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Accounts = append(bundle.Accounts, backupAccount{
+			ID:          string(account.ID()),
+			Email:       string(account.Email()),
+			Alias:       account.Alias(),
+			UUID:        account.UUID(),
+			Tags:        account.Tags(),
+			Color:       account.Color(),
+			Label:       account.Label(),
+			Description: account.Description(),
+			Env:         account.EnvOverrides(),
+			RawOAuth:    account.RawOAuth(),
+			CreatedAt:   account.CreatedAt(),
+			LastUsed:    account.LastUsed(),
+			ArchivedAt:  account.ArchivedAt(),
+			Credentials: creds,
+		})
+	}
+
+	history, err := s.history.LoadHistory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load history: %w", err)
+	}
+	bundle.MaxHistory = history.MaxEntries()
+	for _, entry := range history.Entries() {
+		bundle.History = append(bundle.History, backupSwitch{
+			From:      string(entry.From()),
+			To:        string(entry.To()),
+			Timestamp: entry.Timestamp(),
+		})
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundle: %w", err)
+	}
+
+	return &ExportResult{
+		Data:          data,
+		AccountCount:  len(bundle.Accounts),
+		HistoryLength: len(bundle.History),
+	}, nil
+}
+
+// exportCredentials decrypts an account's credentials and re-encrypts them under the
+// passphrase. The plaintext only lives in memory between the two steps.
+func exportCredentials(ctx context.Context, credentials ports.CredentialStore, account *domain.Account, passphrase []byte) (json.RawMessage, error) {
+	creds, err := credentials.Retrieve(ctx, account.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credentials for %s: %w", account.Email(), err)
+	}
+
+	plaintext, err := creds.DecryptWithPassphrase(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials for %s: %w", account.Email(), err)
+	}
+
+	portable, err := domain.NewCredentialsWithPassphrase(account.ID(), plaintext, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-encrypt credentials for %s: %w", account.Email(), err)
+	}
+
+	serialized, err := portable.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize credentials for %s: %w", account.Email(), err)
+	}
+	return serialized, nil
+}
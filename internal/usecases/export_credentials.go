@@ -0,0 +1,99 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// CredentialsExportVersion is the format version written by ExportCredentialsUseCase and
+// accepted by ImportCredentialsUseCase
+const CredentialsExportVersion = 1
+
+// credentialsExport is the portable JSON document holding one account's credentials,
+// re-encrypted under the user's passphrase. The email identifies the account to a
+// person reading the file and to an import on a machine where the ID differs.
+type credentialsExport struct {
+	Version     int             `json:"version"`
+	ExportedAt  time.Time       `json:"exported_at"`
+	AccountID   string          `json:"account_id"`
+	Email       string          `json:"email"`
+	Credentials json.RawMessage `json:"credentials"`
+}
+
+// ExportCredentialsUseCase defines the interface for backing up a single account's credentials
+type ExportCredentialsUseCase interface {
+	Execute(ctx context.Context, input ExportCredentialsInput) ([]byte, error)
+}
+
+// ExportCredentialsInput contains the input data for a credentials export
+type ExportCredentialsInput struct {
+	AccountID  string // Account whose credentials are exported
+	Passphrase []byte // Passphrase used to re-encrypt the credentials
+}
+
+// ExportCredentialsService implements the ExportCredentialsUseCase
+type ExportCredentialsService struct {
+	accounts    ports.AccountRepository
+	credentials ports.CredentialStore
+}
+
+// Ensure ExportCredentialsService implements ExportCredentialsUseCase at compile time
+var _ ExportCredentialsUseCase = (*ExportCredentialsService)(nil)
+
+// NewExportCredentialsService creates a new ExportCredentialsService
+func NewExportCredentialsService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+) ExportCredentialsUseCase {
+	return &ExportCredentialsService{
+		accounts:    accounts,
+		credentials: credentials,
+	}
+}
+
+// Execute returns a self-describing JSON document holding the account's credentials
+// re-encrypted under the passphrase, so it can be restored on another machine with
+// ImportCredentialsUseCase. The decrypted credentials are never returned or written out.
+func (s *ExportCredentialsService) Execute(ctx context.Context, input ExportCredentialsInput) ([]byte, error) {
+	if input.AccountID == "" {
+		return nil, errors.New("account ID is required")
+	}
+	if len(input.Passphrase) == 0 {
+		return nil, errors.New("passphrase is required")
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	account, err := s.accounts.FindByID(ctx, domain.AccountID(input.AccountID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+
+	creds, err := exportCredentials(ctx, s.credentials, account, input.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(credentialsExport{
+		Version:     CredentialsExportVersion,
+		ExportedAt:  time.Now().UTC(),
+		AccountID:   string(account.ID()),
+		Email:       string(account.Email()),
+		Credentials: creds,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode credentials export: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,133 @@
+package usecases_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+func exportAccountCredentials(t *testing.T, store *backupTestStore, id domain.AccountID) []byte {
+	t.Helper()
+
+	useCase := usecases.NewExportCredentialsService(store.accountRepo, store.credentialStore)
+	data, err := useCase.Execute(context.Background(), usecases.ExportCredentialsInput{
+		AccountID:  string(id),
+		Passphrase: []byte(testBackupPassphrase),
+	})
+	if err != nil {
+		t.Fatalf("ExportCredentials Execute() error = %v", err)
+	}
+	return data
+}
+
+// TestExportCredentialsUseCase_Execute tests that the export names the account and keeps
+// the credentials encrypted
+func TestExportCredentialsUseCase_Execute(t *testing.T) {
+	store := setupExportSource()
+	work, _ := store.accountRepo.FindByAlias(context.Background(), "work")
+
+	data := exportAccountCredentials(t, store, work.ID())
+
+	if bytes.Contains(data, []byte("key-work")) {
+		t.Fatal("export contains the plaintext session key")
+	}
+	var doc struct {
+		Version     int             `json:"version"`
+		AccountID   string          `json:"account_id"`
+		Email       string          `json:"email"`
+		Credentials json.RawMessage `json:"credentials"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("export is not valid JSON: %v", err)
+	}
+	if doc.Version != usecases.CredentialsExportVersion || doc.AccountID != string(work.ID()) || doc.Email != testEmailWork {
+		t.Errorf("export header = %d/%s/%s, want version %d for the work account", doc.Version, doc.AccountID, doc.Email, usecases.CredentialsExportVersion)
+	}
+	creds, err := domain.DeserializeCredentials(doc.Credentials)
+	if err != nil || !creds.IsPassphraseProtected() {
+		t.Errorf("exported credentials should be passphrase-protected (err %v)", err)
+	}
+}
+
+// TestExportCredentialsUseCase_Execute_Errors tests input validation and lookup failures
+func TestExportCredentialsUseCase_Execute_Errors(t *testing.T) {
+	ctx := context.Background()
+	store := setupExportSource()
+	useCase := usecases.NewExportCredentialsService(store.accountRepo, store.credentialStore)
+
+	if _, err := useCase.Execute(ctx, usecases.ExportCredentialsInput{Passphrase: []byte("x")}); err == nil {
+		t.Error("Execute() without account ID error = nil, want error")
+	}
+	if _, err := useCase.Execute(ctx, usecases.ExportCredentialsInput{AccountID: "abc12345"}); err == nil {
+		t.Error("Execute() without passphrase error = nil, want error")
+	}
+	_, err := useCase.Execute(ctx, usecases.ExportCredentialsInput{AccountID: "missing1", Passphrase: []byte("x")})
+	if !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("Execute() for unknown account error = %v, want ErrAccountNotFound", err)
+	}
+}
+
+// TestImportCredentialsUseCase_Execute tests restoring credentials onto an account with
+// a different local ID, matched by email
+func TestImportCredentialsUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	source := setupExportSource()
+	work, _ := source.accountRepo.FindByAlias(ctx, "work")
+	data := exportAccountCredentials(t, source, work.ID())
+
+	target := newBackupTestStore()
+	local := target.addAccount(testEmailWork, "job", "stale-key")
+	useCase := usecases.NewImportCredentialsService(target.accountRepo, target.credentialStore)
+
+	result, err := useCase.Execute(ctx, usecases.ImportCredentialsInput{Data: data, Passphrase: []byte(testBackupPassphrase)})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Account.ID != string(local.ID()) {
+		t.Errorf("imported onto %s, want %s", result.Account.ID, local.ID())
+	}
+	if got := sessionKeyFor(t, target, local.ID()); got != `{"sessionKey":"key-work"}` {
+		t.Errorf("stored credentials = %s, want the exported session", got)
+	}
+}
+
+// TestImportCredentialsUseCase_Execute_Rejections tests that nothing is stored for a bad
+// passphrase, an unknown account, or a tampered document
+func TestImportCredentialsUseCase_Execute_Rejections(t *testing.T) {
+	ctx := context.Background()
+	source := setupExportSource()
+	work, _ := source.accountRepo.FindByAlias(ctx, "work")
+	data := exportAccountCredentials(t, source, work.ID())
+
+	target := newBackupTestStore()
+	local := target.addAccount(testEmailWork, "work", "stale-key")
+	useCase := usecases.NewImportCredentialsService(target.accountRepo, target.credentialStore)
+
+	if _, err := useCase.Execute(ctx, usecases.ImportCredentialsInput{Data: data, Passphrase: []byte("wrong")}); err == nil {
+		t.Error("Execute() with wrong passphrase error = nil, want error")
+	}
+
+	var doc map[string]any
+	_ = json.Unmarshal(data, &doc)
+	doc["account_id"] = "other123"
+	tampered, _ := json.Marshal(doc)
+	if _, err := useCase.Execute(ctx, usecases.ImportCredentialsInput{Data: tampered, Passphrase: []byte(testBackupPassphrase)}); err == nil {
+		t.Error("Execute() with mismatched account ID error = nil, want error")
+	}
+
+	empty := newBackupTestStore()
+	_, err := usecases.NewImportCredentialsService(empty.accountRepo, empty.credentialStore).
+		Execute(ctx, usecases.ImportCredentialsInput{Data: data, Passphrase: []byte(testBackupPassphrase)})
+	if !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("Execute() without a matching account error = %v, want ErrAccountNotFound", err)
+	}
+
+	if got := sessionKeyFor(t, target, local.ID()); got != `{"sessionKey":"stale-key"}` {
+		t.Errorf("stored credentials = %s after rejected imports, want them unchanged", got)
+	}
+}
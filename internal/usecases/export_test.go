@@ -0,0 +1,123 @@
+package usecases_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+const testBackupPassphrase = "correct horse battery staple"
+
+// backupTestStore groups the repositories used by export and import tests
+type backupTestStore struct {
+	accountRepo     *extendedMockAccountRepository
+	credentialStore *extendedMockCredentialStore
+	historyRepo     *mockHistoryRepository
+}
+
+func newBackupTestStore() *backupTestStore {
+	return &backupTestStore{
+		accountRepo:     newExtendedMockAccountRepository(),
+		credentialStore: newExtendedMockCredentialStore(),
+		historyRepo:     newMockHistoryRepository(),
+	}
+}
+
+// addAccount saves an account with credentials holding the given session key
+func (s *backupTestStore) addAccount(email, alias, sessionKey string) *domain.Account {
+	account, _ := domain.NewAccount(email, alias, "uuid-"+alias)
+	_ = s.accountRepo.Save(context.Background(), account)
+	creds, _ := domain.NewCredentials(account.ID(), []byte(`{"sessionKey":"`+sessionKey+`"}`))
+	_ = s.credentialStore.Store(context.Background(), creds)
+	return account
+}
+
+// setupExportSource creates a store with two accounts and one switch
+func setupExportSource() *backupTestStore {
+	store := newBackupTestStore()
+	personal := store.addAccount(testEmailPersonal, "personal", "key-personal")
+	_ = personal.AddTag("home")
+	_ = personal.SetColor("green")
+	_ = personal.SetLabel("home machine")
+	_ = personal.SetEnvOverride("HTTPS_PROXY", "http://proxy.home:3128")
+	_ = store.accountRepo.Save(context.Background(), personal)
+	store.addAccount(testEmailWork, "work", "key-work")
+
+	entry, _ := domain.NewSwitchEntry(testEmailPersonal, testEmailWork)
+	store.historyRepo.history.AddEntry(entry)
+	return store
+}
+
+func exportBundle(t *testing.T, store *backupTestStore) []byte {
+	t.Helper()
+
+	useCase := usecases.NewExportService(store.accountRepo, store.credentialStore, store.historyRepo)
+	result, err := useCase.Execute(context.Background(), usecases.ExportInput{Passphrase: []byte(testBackupPassphrase)})
+	if err != nil {
+		t.Fatalf("Export Execute() error = %v", err)
+	}
+	return result.Data
+}
+
+// TestExportUseCase_Execute_Bundle tests the exported document structure
+func TestExportUseCase_Execute_Bundle(t *testing.T) {
+	store := setupExportSource()
+	useCase := usecases.NewExportService(store.accountRepo, store.credentialStore, store.historyRepo)
+
+	result, err := useCase.Execute(context.Background(), usecases.ExportInput{Passphrase: []byte(testBackupPassphrase)})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.AccountCount != 2 || result.HistoryLength != 1 {
+		t.Errorf("Expected 2 accounts and 1 history entry, got %d and %d", result.AccountCount, result.HistoryLength)
+	}
+
+	var bundle struct {
+		Version  int `json:"version"`
+		Accounts []struct {
+			Email       string          `json:"email"`
+			Credentials json.RawMessage `json:"credentials"`
+		} `json:"accounts"`
+	}
+	if err := json.Unmarshal(result.Data, &bundle); err != nil {
+		t.Fatalf("Failed to parse bundle: %v", err)
+	}
+	if bundle.Version != usecases.BackupVersion {
+		t.Errorf("Bundle version = %d, want %d", bundle.Version, usecases.BackupVersion)
+	}
+
+	// Credentials must be keyed by the passphrase, not the account ID
+	for _, account := range bundle.Accounts {
+		creds, err := domain.DeserializeCredentials(account.Credentials)
+		if err != nil {
+			t.Fatalf("Failed to deserialize credentials for %s: %v", account.Email, err)
+		}
+		if !creds.IsPassphraseProtected() {
+			t.Errorf("Credentials for %s should be passphrase-protected", account.Email)
+		}
+		if _, err := creds.DecryptWithPassphrase([]byte("wrong")); err == nil {
+			t.Errorf("Credentials for %s should not decrypt with the wrong passphrase", account.Email)
+		}
+	}
+}
+
+// TestExportUseCase_Execute_Errors tests export validation and missing credentials
+func TestExportUseCase_Execute_Errors(t *testing.T) {
+	store := setupExportSource()
+	useCase := usecases.NewExportService(store.accountRepo, store.credentialStore, store.historyRepo)
+
+	if _, err := useCase.Execute(context.Background(), usecases.ExportInput{}); err == nil {
+		t.Error("Execute() without passphrase error = nil, want error")
+	}
+
+	for id := range store.credentialStore.credentials {
+		delete(store.credentialStore.credentials, id)
+		break
+	}
+	if _, err := useCase.Execute(context.Background(), usecases.ExportInput{Passphrase: []byte(testBackupPassphrase)}); err == nil {
+		t.Error("Execute() with missing credentials error = nil, want error")
+	}
+}
@@ -0,0 +1,82 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// GetHistoryUseCase defines the interface for retrieving account switch history
+type GetHistoryUseCase interface {
+	Execute(ctx context.Context, input GetHistoryInput) ([]SwitchInfo, error)
+}
+
+// GetHistoryInput contains the input data for retrieving history
+type GetHistoryInput struct {
+	Limit int // Maximum number of switches to return; zero returns all
+}
+
+// SwitchInfo represents a history entry returned to the presentation layer
+type SwitchInfo struct {
+	From      string    `json:"from"`      // Email switched away from
+	To        string    `json:"to"`        // Email switched to
+	Timestamp time.Time `json:"timestamp"` // When the switch occurred
+}
+
+// GetHistoryService implements the GetHistoryUseCase
+type GetHistoryService struct {
+	history ports.HistoryRepository
+}
+
+// Ensure GetHistoryService implements GetHistoryUseCase at compile time
+var _ GetHistoryUseCase = (*GetHistoryService)(nil)
+
+// NewGetHistoryService creates a new GetHistoryService
+func NewGetHistoryService(history ports.HistoryRepository) GetHistoryUseCase {
+	return &GetHistoryService{
+		history: history,
+	}
+}
+
+// Execute returns switch history, most recent first
+func (s *GetHistoryService) Execute(ctx context.Context, input GetHistoryInput) ([]SwitchInfo, error) {
+	if input.Limit < 0 {
+		return nil, errors.New("limit cannot be negative")
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	history, err := s.history.LoadHistory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load history: %w", err)
+	}
+
+	entries := history.Entries()
+	if input.Limit > 0 && len(entries) > input.Limit {
+		entries = entries[:input.Limit]
+	}
+
+	return newSwitchInfos(entries), nil
+}
+
+// newSwitchInfos converts domain switch entries to SwitchInfo DTOs, never returning nil
+func newSwitchInfos(entries []*domain.SwitchEntry) []SwitchInfo {
+	result := make([]SwitchInfo, len(entries))
+	for i, entry := range entries {
+		result[i] = SwitchInfo{
+			From:      string(entry.From()),
+			To:        string(entry.To()),
+			Timestamp: entry.Timestamp(),
+		}
+	}
+	return result
+}
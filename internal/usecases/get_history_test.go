@@ -0,0 +1,131 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// seedHistory records switches in order, so the last pair is the most recent
+func seedHistory(repo *mockHistoryRepository, pairs ...[2]domain.Email) {
+	for _, pair := range pairs {
+		entry, _ := domain.NewSwitchEntry(pair[0], pair[1])
+		repo.history.AddEntry(entry)
+	}
+}
+
+// TestGetHistoryUseCase_Execute_MostRecentFirst tests conversion and ordering
+func TestGetHistoryUseCase_Execute_MostRecentFirst(t *testing.T) {
+	historyRepo := newMockHistoryRepository()
+	seedHistory(historyRepo,
+		[2]domain.Email{testEmailPersonal, testEmailWork},
+		[2]domain.Email{testEmailWork, testEmailTest},
+	)
+	useCase := usecases.NewGetHistoryService(historyRepo)
+
+	switches, err := useCase.Execute(context.Background(), usecases.GetHistoryInput{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if len(switches) != 2 {
+		t.Fatalf("Expected 2 switches, got %d", len(switches))
+	}
+	if switches[0].From != testEmailWork || switches[0].To != testEmailTest {
+		t.Errorf("Expected most recent switch first, got %+v", switches[0])
+	}
+	if switches[1].From != testEmailPersonal || switches[1].To != testEmailWork {
+		t.Errorf("Expected oldest switch last, got %+v", switches[1])
+	}
+	if switches[0].Timestamp.IsZero() {
+		t.Error("SwitchInfo.Timestamp should not be zero")
+	}
+}
+
+// TestGetHistoryUseCase_Execute_Limit tests returning only the last N switches
+func TestGetHistoryUseCase_Execute_Limit(t *testing.T) {
+	historyRepo := newMockHistoryRepository()
+	seedHistory(historyRepo,
+		[2]domain.Email{testEmailPersonal, testEmailWork},
+		[2]domain.Email{testEmailWork, testEmailTest},
+		[2]domain.Email{testEmailTest, testEmailPersonal},
+	)
+	useCase := usecases.NewGetHistoryService(historyRepo)
+
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{"no limit", 0, 3},
+		{"limit below size", 2, 2},
+		{"limit above size", 10, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			switches, err := useCase.Execute(context.Background(), usecases.GetHistoryInput{Limit: tt.limit})
+			if err != nil {
+				t.Fatalf("Execute() error = %v, want nil", err)
+			}
+			if len(switches) != tt.want {
+				t.Errorf("Expected %d switches, got %d", tt.want, len(switches))
+			}
+			if len(switches) > 0 && switches[0].To != testEmailPersonal {
+				t.Errorf("Expected most recent switch first, got %+v", switches[0])
+			}
+		})
+	}
+
+	if _, err := useCase.Execute(context.Background(), usecases.GetHistoryInput{Limit: -1}); err == nil {
+		t.Error("Expected error for negative limit, got nil")
+	}
+}
+
+// TestGetHistoryUseCase_Execute_EmptyHistory tests that empty history yields an empty, non-nil slice
+func TestGetHistoryUseCase_Execute_EmptyHistory(t *testing.T) {
+	useCase := usecases.NewGetHistoryService(newMockHistoryRepository())
+
+	switches, err := useCase.Execute(context.Background(), usecases.GetHistoryInput{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if switches == nil {
+		t.Error("Expected empty slice, got nil")
+	}
+	if len(switches) != 0 {
+		t.Errorf("Expected no switches, got %d", len(switches))
+	}
+}
+
+// TestGetHistoryUseCase_Execute_RepositoryError tests repository failure handling
+func TestGetHistoryUseCase_Execute_RepositoryError(t *testing.T) {
+	historyRepo := newMockHistoryRepository()
+	loadErr := errors.New("history file corrupted")
+	historyRepo.loadErr = loadErr
+	useCase := usecases.NewGetHistoryService(historyRepo)
+
+	switches, err := useCase.Execute(context.Background(), usecases.GetHistoryInput{})
+	if !errors.Is(err, loadErr) {
+		t.Errorf("Execute() error = %v, want wrapped %v", err, loadErr)
+	}
+	if switches != nil {
+		t.Errorf("Expected nil switches on error, got %v", switches)
+	}
+}
+
+// TestGetHistoryUseCase_Execute_ContextCancellation tests context cancellation
+func TestGetHistoryUseCase_Execute_ContextCancellation(t *testing.T) {
+	useCase := usecases.NewGetHistoryService(newMockHistoryRepository())
+
+	// Create cancelled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := useCase.Execute(ctx, usecases.GetHistoryInput{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,328 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// ImportUseCase defines the interface for restoring ccx state from an exported bundle
+type ImportUseCase interface {
+	Execute(ctx context.Context, input ImportInput) (*ImportResult, error)
+}
+
+// ImportInput contains the input data for an import
+type ImportInput struct {
+	Data       []byte // Bundle produced by ExportUseCase
+	Passphrase []byte // Passphrase the bundle was exported with
+	Replace    bool   // Replace all existing state instead of merging into it
+}
+
+// ImportResult contains the result of an import
+type ImportResult struct {
+	Imported        []AccountInfo `json:"imported"`         // Accounts restored from the bundle
+	RemovedAccounts int           `json:"removed_accounts"` // Existing accounts removed because Replace was set
+	HistoryLength   int           `json:"history_length"`   // Number of history entries after the import
+}
+
+// ImportService implements the ImportUseCase
+type ImportService struct {
+	accounts    ports.AccountRepository
+	credentials ports.CredentialStore
+	history     ports.HistoryRepository
+}
+
+// Ensure ImportService implements ImportUseCase at compile time
+var _ ImportUseCase = (*ImportService)(nil)
+
+// NewImportService creates a new ImportService
+func NewImportService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+	history ports.HistoryRepository,
+) ImportUseCase {
+	return &ImportService{
+		accounts:    accounts,
+		credentials: credentials,
+		history:     history,
+	}
+}
+
+// importedAccount is a fully decoded bundle entry, ready to be written
+type importedAccount struct {
+	account     *domain.Account
+	credentials *domain.Credentials
+}
+
+// storeSnapshot captures existing state so a failed import can be rolled back
+type storeSnapshot struct {
+	accounts    []*domain.Account
+	credentials map[domain.AccountID]*domain.Credentials
+	history     *domain.History
+}
+
+// Execute validates and decrypts the whole bundle before writing anything, then restores
+// it. If a write fails midway, the previous state is restored on a best-effort basis.
+func (s *ImportService) Execute(ctx context.Context, input ImportInput) (*ImportResult, error) {
+	if len(input.Data) == 0 {
+		return nil, errors.New("bundle data is required")
+	}
+	if len(input.Passphrase) == 0 {
+		return nil, errors.New("passphrase is required")
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	var bundle backupBundle
+	if err := json.Unmarshal(input.Data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle: %w", err)
+	}
+	if bundle.Version != BackupVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d (expected %d)", bundle.Version, BackupVersion)
+	}
+
+	imported, err := decodeBundleAccounts(bundle.Accounts, input.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	importedHistory, err := decodeBundleHistory(bundle.History)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.takeSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !input.Replace {
+		if err := checkMergeConflicts(snapshot.accounts, imported); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := s.apply(ctx, input.Replace, snapshot, imported, importedHistory, bundle.MaxHistory)
+	if err != nil {
+		s.rollback(ctx, snapshot, imported)
+		return nil, err
+	}
+	return result, nil
+}
+
+// decodeBundleAccounts rebuilds domain accounts and re-keys their credentials for local storage
+func decodeBundleAccounts(entries []backupAccount, passphrase []byte) ([]importedAccount, error) {
+	imported := make([]importedAccount, 0, len(entries))
+	seen := make(map[string]struct{}, len(entries))
+
+	for _, entry := range entries {
+		if entry.ID == "" {
+			return nil, fmt.Errorf("bundle account %s has no ID", entry.Email)
+		}
+		if err := domain.ValidateAccountID(domain.AccountID(entry.ID)); err != nil {
+			return nil, fmt.Errorf("invalid account %s in bundle: %w", entry.Email, err)
+		}
+		if _, dup := seen[entry.ID]; dup {
+			return nil, fmt.Errorf("bundle contains account %s more than once", entry.ID)
+		}
+		seen[entry.ID] = struct{}{}
+		account, err := domain.ReconstructAccount(
+			domain.AccountID(entry.ID),
+			entry.Email,
+			entry.Alias,
+			entry.UUID,
+			entry.RawOAuth,
+			entry.CreatedAt,
+			entry.LastUsed,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid account %s in bundle: %w", entry.Email, err)
+		}
+		for _, tag := range entry.Tags {
+			if err := account.AddTag(tag); err != nil {
+				return nil, fmt.Errorf("invalid account %s in bundle: %w", entry.Email, err)
+			}
+		}
+		if err := account.SetColor(entry.Color); err != nil {
+			return nil, fmt.Errorf("invalid account %s in bundle: %w", entry.Email, err)
+		}
+		if err := account.SetLabel(entry.Label); err != nil {
+			return nil, fmt.Errorf("invalid account %s in bundle: %w", entry.Email, err)
+		}
+		if err := account.SetDescription(entry.Description); err != nil {
+			return nil, fmt.Errorf("invalid account %s in bundle: %w", entry.Email, err)
+		}
+		if err := account.SetEnvOverrides(entry.Env); err != nil {
+			return nil, fmt.Errorf("invalid account %s in bundle: %w", entry.Email, err)
+		}
+		account.Archive(entry.ArchivedAt)
+
+		portable, err := domain.DeserializeCredentials(entry.Credentials)
+		if err != nil {
+			return nil, fmt.Errorf("invalid credentials for %s in bundle: %w", entry.Email, err)
+		}
+		if portable.AccountID() != account.ID() {
+			return nil, fmt.Errorf("credentials for %s belong to a different account", entry.Email)
+		}
+		plaintext, err := portable.DecryptWithPassphrase(passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt credentials for %s: %w", entry.Email, err)
+		}
+		creds, err := domain.NewCredentials(account.ID(), plaintext)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-encrypt credentials for %s: %w", entry.Email, err)
+		}
+
+		imported = append(imported, importedAccount{account: account, credentials: creds})
+	}
+
+	return imported, nil
+}
+
+// decodeBundleHistory rebuilds history entries from the bundle
+func decodeBundleHistory(entries []backupSwitch) ([]*domain.SwitchEntry, error) {
+	result := make([]*domain.SwitchEntry, 0, len(entries))
+	for _, entry := range entries {
+		sw, err := domain.ReconstructSwitchEntry(domain.Email(entry.From), domain.Email(entry.To), entry.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid history entry in bundle: %w", err)
+		}
+		result = append(result, sw)
+	}
+	return result, nil
+}
+
+// checkMergeConflicts rejects merges where an imported account's email is already
+// used by a different local account
+func checkMergeConflicts(existing []*domain.Account, imported []importedAccount) error {
+	byEmail := make(map[domain.Email]domain.AccountID, len(existing))
+	for _, account := range existing {
+		byEmail[account.Email()] = account.ID()
+	}
+	for _, entry := range imported {
+		if id, ok := byEmail[entry.account.Email()]; ok && id != entry.account.ID() {
+			return fmt.Errorf("%w: %s has a different ID; import with replace instead", domain.ErrDuplicateAccount, entry.account.Email())
+		}
+	}
+	return nil
+}
+
+func (s *ImportService) takeSnapshot(ctx context.Context) (*storeSnapshot, error) {
+	accounts, err := s.accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	snapshot := &storeSnapshot{
+		accounts:    accounts,
+		credentials: make(map[domain.AccountID]*domain.Credentials, len(accounts)),
+	}
+	for _, account := range accounts {
+		if creds, err := s.credentials.Retrieve(ctx, account.ID()); err == nil {
+			snapshot.credentials[account.ID()] = creds
+		}
+	}
+
+	history, err := s.history.LoadHistory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load history: %w", err)
+	}
+	snapshot.history = rebuildHistory(history.MaxEntries(), history.Entries())
+
+	return snapshot, nil
+}
+
+func (s *ImportService) apply(
+	ctx context.Context,
+	replace bool,
+	snapshot *storeSnapshot,
+	imported []importedAccount,
+	importedHistory []*domain.SwitchEntry,
+	maxHistory int,
+) (*ImportResult, error) {
+	result := &ImportResult{Imported: make([]AccountInfo, 0, len(imported))}
+
+	if replace {
+		for _, account := range snapshot.accounts {
+			if _, ok := snapshot.credentials[account.ID()]; ok {
+				if err := s.credentials.Delete(ctx, account.ID()); err != nil {
+					return nil, fmt.Errorf("failed to delete credentials for %s: %w", account.Email(), err)
+				}
+			}
+			if err := s.accounts.Delete(ctx, account.ID()); err != nil {
+				return nil, fmt.Errorf("failed to delete account %s: %w", account.Email(), err)
+			}
+		}
+		result.RemovedAccounts = len(snapshot.accounts)
+	}
+
+	for _, entry := range imported {
+		if err := s.accounts.Save(ctx, entry.account); err != nil {
+			return nil, fmt.Errorf("failed to save account %s: %w", entry.account.Email(), err)
+		}
+		if err := s.credentials.Store(ctx, entry.credentials); err != nil {
+			return nil, fmt.Errorf("failed to store credentials for %s: %w", entry.account.Email(), err)
+		}
+		result.Imported = append(result.Imported, newAccountInfo(entry.account))
+	}
+
+	entries := importedHistory
+	if !replace {
+		entries = append(snapshot.history.Entries(), importedHistory...)
+	}
+	if maxHistory < snapshot.history.MaxEntries() {
+		maxHistory = snapshot.history.MaxEntries()
+	}
+	history := rebuildHistory(maxHistory, entries)
+	if err := s.history.SaveHistory(ctx, history); err != nil {
+		return nil, fmt.Errorf("failed to save history: %w", err)
+	}
+	result.HistoryLength = len(history.Entries())
+
+	return result, nil
+}
+
+// rollback restores the snapshot after a failed import (best effort)
+func (s *ImportService) rollback(ctx context.Context, snapshot *storeSnapshot, imported []importedAccount) {
+	for _, entry := range imported {
+		_ = s.credentials.Delete(ctx, entry.account.ID())
+		_ = s.accounts.Delete(ctx, entry.account.ID())
+	}
+	for _, account := range snapshot.accounts {
+		_ = s.accounts.Save(ctx, account)
+		if creds, ok := snapshot.credentials[account.ID()]; ok {
+			_ = s.credentials.Store(ctx, creds)
+		}
+	}
+	_ = s.history.SaveHistory(ctx, snapshot.history)
+}
+
+// rebuildHistory returns a new history holding the given entries ordered by time,
+// with exact duplicates removed
+func rebuildHistory(maxEntries int, entries []*domain.SwitchEntry) *domain.History {
+	sorted := make([]*domain.SwitchEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp().Before(sorted[j].Timestamp())
+	})
+
+	history := domain.NewHistory(maxEntries)
+	var last *domain.SwitchEntry
+	for _, entry := range sorted {
+		if last != nil && last.From() == entry.From() && last.To() == entry.To() && last.Timestamp().Equal(entry.Timestamp()) {
+			continue
+		}
+		// AddEntry keeps the most recent first, so add oldest to newest
+		history.AddEntry(entry)
+		last = entry
+	}
+	return history
+}
@@ -0,0 +1,119 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// ImportCredentialsUseCase defines the interface for restoring a single account's
+// credentials from a credentials export
+type ImportCredentialsUseCase interface {
+	Execute(ctx context.Context, input ImportCredentialsInput) (*ImportCredentialsResult, error)
+}
+
+// ImportCredentialsInput contains the input data for a credentials import
+type ImportCredentialsInput struct {
+	Data       []byte // Document produced by ExportCredentialsUseCase
+	Passphrase []byte // Passphrase the credentials were exported with
+}
+
+// ImportCredentialsResult contains the result of a credentials import
+type ImportCredentialsResult struct {
+	Account AccountInfo `json:"account"` // Account whose credentials were replaced
+}
+
+// ImportCredentialsService implements the ImportCredentialsUseCase
+type ImportCredentialsService struct {
+	accounts    ports.AccountRepository
+	credentials ports.CredentialStore
+}
+
+// Ensure ImportCredentialsService implements ImportCredentialsUseCase at compile time
+var _ ImportCredentialsUseCase = (*ImportCredentialsService)(nil)
+
+// NewImportCredentialsService creates a new ImportCredentialsService
+func NewImportCredentialsService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+) ImportCredentialsUseCase {
+	return &ImportCredentialsService{
+		accounts:    accounts,
+		credentials: credentials,
+	}
+}
+
+// Execute decrypts the exported credentials and stores them for the matching local
+// account, found by ID or else by email since IDs differ between machines. The account
+// must already exist; credentials without an account would be orphaned.
+func (s *ImportCredentialsService) Execute(ctx context.Context, input ImportCredentialsInput) (*ImportCredentialsResult, error) {
+	if len(input.Data) == 0 {
+		return nil, errors.New("credentials export data is required")
+	}
+	if len(input.Passphrase) == 0 {
+		return nil, errors.New("passphrase is required")
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	var export credentialsExport
+	if err := json.Unmarshal(input.Data, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials export: %w", err)
+	}
+	if export.Version != CredentialsExportVersion {
+		return nil, fmt.Errorf("unsupported credentials export version %d (expected %d)", export.Version, CredentialsExportVersion)
+	}
+
+	portable, err := domain.DeserializeCredentials(export.Credentials)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials for %s in export: %w", export.Email, err)
+	}
+	if string(portable.AccountID()) != export.AccountID {
+		return nil, fmt.Errorf("credentials for %s belong to a different account", export.Email)
+	}
+	plaintext, err := portable.DecryptWithPassphrase(input.Passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials for %s: %w", export.Email, err)
+	}
+
+	account, err := s.findAccount(ctx, export)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := domain.NewCredentials(account.ID(), plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-encrypt credentials for %s: %w", account.Email(), err)
+	}
+	if err := s.credentials.Store(ctx, creds); err != nil {
+		return nil, fmt.Errorf("failed to store credentials: %w", err)
+	}
+
+	return &ImportCredentialsResult{Account: newAccountInfo(account)}, nil
+}
+
+// findAccount returns the local account the export belongs to
+func (s *ImportCredentialsService) findAccount(ctx context.Context, export credentialsExport) (*domain.Account, error) {
+	account, err := s.accounts.FindByID(ctx, domain.AccountID(export.AccountID))
+	if err == nil {
+		return account, nil
+	}
+	if !errors.Is(err, domain.ErrAccountNotFound) {
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+
+	account, err = s.accounts.FindByEmail(ctx, domain.Email(export.Email))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account %s: %w", export.Email, err)
+	}
+	return account, nil
+}
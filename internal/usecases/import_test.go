@@ -0,0 +1,232 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// failingCredentialStore fails Store for one specific account
+type failingCredentialStore struct {
+	*extendedMockCredentialStore
+	failFor domain.AccountID
+}
+
+func (m *failingCredentialStore) Store(ctx context.Context, creds *domain.Credentials) error {
+	if creds.AccountID() == m.failFor {
+		return errors.New("disk full")
+	}
+	return m.extendedMockCredentialStore.Store(ctx, creds)
+}
+
+func newImportUseCase(store *backupTestStore) usecases.ImportUseCase {
+	return usecases.NewImportService(store.accountRepo, store.credentialStore, store.historyRepo)
+}
+
+// sessionKeyFor decrypts the stored credentials of an account
+func sessionKeyFor(t *testing.T, store *backupTestStore, id domain.AccountID) string {
+	t.Helper()
+
+	creds, err := store.credentialStore.Retrieve(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Retrieve(%s) error = %v", id, err)
+	}
+	data, err := creds.Decrypt()
+	if err != nil {
+		t.Fatalf("Imported credentials for %s should decrypt locally: %v", id, err)
+	}
+	return string(data)
+}
+
+// TestImportUseCase_Execute_RoundTrip tests restoring an export into an empty store
+func TestImportUseCase_Execute_RoundTrip(t *testing.T) {
+	source := setupExportSource()
+	work, _ := source.accountRepo.FindByEmail(context.Background(), testEmailWork)
+	work.Archive(time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC))
+	_ = source.accountRepo.Save(context.Background(), work)
+	data := exportBundle(t, source)
+	target := newBackupTestStore()
+
+	result, err := newImportUseCase(target).Execute(context.Background(), usecases.ImportInput{
+		Data:       data,
+		Passphrase: []byte(testBackupPassphrase),
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if len(result.Imported) != 2 || result.HistoryLength != 1 {
+		t.Errorf("Expected 2 accounts and 1 history entry, got %d and %d", len(result.Imported), result.HistoryLength)
+	}
+
+	for id, original := range source.accountRepo.accounts {
+		restored, err := target.accountRepo.FindByID(context.Background(), id)
+		if err != nil {
+			t.Fatalf("Account %s not restored: %v", id, err)
+		}
+		if restored.Email() != original.Email() || restored.Alias() != original.Alias() || !restored.CreatedAt().Equal(original.CreatedAt()) {
+			t.Errorf("Restored account %+v does not match original %+v", restored, original)
+		}
+		if len(restored.Tags()) != len(original.Tags()) {
+			t.Errorf("Restored tags %v, want %v", restored.Tags(), original.Tags())
+		}
+		if restored.Color() != original.Color() || restored.Label() != original.Label() {
+			t.Errorf("Restored color/label %q/%q, want %q/%q", restored.Color(), restored.Label(), original.Color(), original.Label())
+		}
+		if !maps.Equal(restored.EnvOverrides(), original.EnvOverrides()) {
+			t.Errorf("Restored env %v, want %v", restored.EnvOverrides(), original.EnvOverrides())
+		}
+		if !restored.ArchivedAt().Equal(original.ArchivedAt()) {
+			t.Errorf("Restored archived at %v, want %v", restored.ArchivedAt(), original.ArchivedAt())
+		}
+		if got, want := sessionKeyFor(t, target, id), `{"sessionKey":"key-`+original.Alias()+`"}`; got != want {
+			t.Errorf("Restored credentials = %s, want %s", got, want)
+		}
+	}
+
+	restoredSwitch := target.historyRepo.history.GetLastSwitch()
+	originalSwitch := source.historyRepo.history.GetLastSwitch()
+	if restoredSwitch == nil || !restoredSwitch.Timestamp().Equal(originalSwitch.Timestamp()) {
+		t.Errorf("History not restored: got %v, want %v", restoredSwitch, originalSwitch)
+	}
+}
+
+// TestImportUseCase_Execute_MergeAndReplace tests both import modes against existing state
+func TestImportUseCase_Execute_MergeAndReplace(t *testing.T) {
+	data := exportBundle(t, setupExportSource())
+
+	tests := []struct {
+		name         string
+		replace      bool
+		wantAccounts int
+		keepExisting bool
+	}{
+		{"merge keeps existing accounts", false, 3, true},
+		{"replace removes existing accounts", true, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := newBackupTestStore()
+			existing := target.addAccount(testEmailTest, "test", "key-test")
+
+			result, err := newImportUseCase(target).Execute(context.Background(), usecases.ImportInput{
+				Data:       data,
+				Passphrase: []byte(testBackupPassphrase),
+				Replace:    tt.replace,
+			})
+			if err != nil {
+				t.Fatalf("Execute() error = %v, want nil", err)
+			}
+
+			if len(target.accountRepo.accounts) != tt.wantAccounts {
+				t.Errorf("Expected %d accounts, got %d", tt.wantAccounts, len(target.accountRepo.accounts))
+			}
+			_, found := target.accountRepo.accounts[existing.ID()]
+			if found != tt.keepExisting {
+				t.Errorf("Existing account present = %v, want %v", found, tt.keepExisting)
+			}
+			if tt.replace && result.RemovedAccounts != 1 {
+				t.Errorf("RemovedAccounts = %d, want 1", result.RemovedAccounts)
+			}
+		})
+	}
+}
+
+// TestImportUseCase_Execute_Rejections tests bundles that must be rejected without changes
+func TestImportUseCase_Execute_Rejections(t *testing.T) {
+	data := exportBundle(t, setupExportSource())
+
+	tests := []struct {
+		name  string
+		input usecases.ImportInput
+		setup func(target *backupTestStore)
+	}{
+		{"wrong passphrase", usecases.ImportInput{Data: data, Passphrase: []byte("wrong")}, nil},
+		{"unsupported version", usecases.ImportInput{Data: []byte(`{"version": 99, "accounts": []}`), Passphrase: []byte("x")}, nil},
+		{"malformed bundle", usecases.ImportInput{Data: []byte(`not json`), Passphrase: []byte("x")}, nil},
+		{"missing passphrase", usecases.ImportInput{Data: data}, nil},
+		{
+			name: "path traversal ID",
+			input: usecases.ImportInput{
+				Data:       []byte(`{"version": 1, "accounts": [{"id": "../../escaped", "email": "evil@example.com", "uuid": "uuid-evil"}]}`),
+				Passphrase: []byte("x"),
+			},
+		},
+		{
+			name:  "merge conflict on email",
+			input: usecases.ImportInput{Data: data, Passphrase: []byte(testBackupPassphrase)},
+			setup: func(target *backupTestStore) { target.addAccount(testEmailWork, "other", "key-other") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := newBackupTestStore()
+			target.addAccount(testEmailTest, "test", "key-test")
+			if tt.setup != nil {
+				tt.setup(target)
+			}
+			before := len(target.accountRepo.accounts)
+
+			if _, err := newImportUseCase(target).Execute(context.Background(), tt.input); err == nil {
+				t.Fatal("Execute() error = nil, want error")
+			}
+			if len(target.accountRepo.accounts) != before {
+				t.Errorf("Accounts changed after rejected import: %d -> %d", before, len(target.accountRepo.accounts))
+			}
+			if target.historyRepo.saveCalls != 0 {
+				t.Error("History should not be written for a rejected import")
+			}
+		})
+	}
+}
+
+// TestImportUseCase_Execute_RollbackOnFailure tests that a failed write restores the previous state
+func TestImportUseCase_Execute_RollbackOnFailure(t *testing.T) {
+	source := setupExportSource()
+	data := exportBundle(t, source)
+
+	target := newBackupTestStore()
+	existing := target.addAccount(testEmailTest, "test", "key-test")
+	entry, _ := domain.NewSwitchEntry(testEmailTest, "someone@example.com")
+	target.historyRepo.history.AddEntry(entry)
+
+	var work *domain.Account
+	for _, account := range source.accountRepo.accounts {
+		if account.Alias() == "work" {
+			work = account
+		}
+	}
+	failing := &failingCredentialStore{extendedMockCredentialStore: target.credentialStore, failFor: work.ID()}
+	useCase := usecases.NewImportService(target.accountRepo, failing, target.historyRepo)
+
+	_, err := useCase.Execute(context.Background(), usecases.ImportInput{
+		Data:       data,
+		Passphrase: []byte(testBackupPassphrase),
+		Replace:    true,
+	})
+	if err == nil {
+		t.Fatal("Execute() error = nil, want error")
+	}
+
+	if len(target.accountRepo.accounts) != 1 {
+		t.Errorf("Expected only the original account after rollback, got %d", len(target.accountRepo.accounts))
+	}
+	if _, ok := target.accountRepo.accounts[existing.ID()]; !ok {
+		t.Error("Original account should be restored after rollback")
+	}
+	if got := sessionKeyFor(t, target, existing.ID()); got != `{"sessionKey":"key-test"}` {
+		t.Errorf("Original credentials = %s after rollback", got)
+	}
+	if len(target.credentialStore.credentials) != 1 {
+		t.Errorf("Expected only the original credentials after rollback, got %d", len(target.credentialStore.credentials))
+	}
+	if last := target.historyRepo.history.GetLastSwitch(); last == nil || last.From() != testEmailTest {
+		t.Errorf("Original history should be restored, got %v", last)
+	}
+}
@@ -0,0 +1,112 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// UntaggedGroup is the tag group name used for accounts without tags
+const UntaggedGroup = "untagged"
+
+// ListAccountTreeUseCase defines the interface for listing accounts grouped by tag, then email domain
+type ListAccountTreeUseCase interface {
+	Execute(ctx context.Context) ([]TagGroup, error)
+}
+
+// TagGroup is the top level of the account tree
+type TagGroup struct {
+	Tag     string        `json:"tag"`     // Tag name, or UntaggedGroup for accounts without tags
+	Domains []DomainGroup `json:"domains"` // Accounts under this tag grouped by email domain, sorted by domain
+}
+
+// DomainGroup is the second level of the account tree
+type DomainGroup struct {
+	Domain   string        `json:"domain"`   // Email domain shared by the accounts
+	Accounts []AccountInfo `json:"accounts"` // Accounts in this domain, sorted by email
+}
+
+// ListAccountTreeService implements the ListAccountTreeUseCase
+type ListAccountTreeService struct {
+	accounts ports.AccountRepository
+}
+
+// Ensure ListAccountTreeService implements ListAccountTreeUseCase at compile time
+var _ ListAccountTreeUseCase = (*ListAccountTreeService)(nil)
+
+// NewListAccountTreeService creates a new ListAccountTreeService
+func NewListAccountTreeService(accounts ports.AccountRepository) ListAccountTreeUseCase {
+	return &ListAccountTreeService{
+		accounts: accounts,
+	}
+}
+
+// Execute builds the tree of unarchived accounts. Tags are sorted alphabetically with
+// the untagged bucket last; an account with several tags appears under each of them.
+func (s *ListAccountTreeService) Execute(ctx context.Context) ([]TagGroup, error) {
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	accounts, err := s.accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	tagged := make(map[string]map[string][]AccountInfo) // tag -> domain -> accounts
+	untagged := make(map[string][]AccountInfo)          // domain -> accounts
+	for _, account := range accounts {
+		if account.Archived() {
+			continue
+		}
+		info := newAccountInfo(account)
+		domain := account.Email().Domain()
+
+		if len(info.Tags) == 0 {
+			untagged[domain] = append(untagged[domain], info)
+			continue
+		}
+		for _, tag := range info.Tags {
+			if tagged[tag] == nil {
+				tagged[tag] = make(map[string][]AccountInfo)
+			}
+			tagged[tag][domain] = append(tagged[tag][domain], info)
+		}
+	}
+
+	tags := make([]string, 0, len(tagged))
+	for tag := range tagged {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	result := make([]TagGroup, 0, len(tags)+1)
+	for _, tag := range tags {
+		result = append(result, TagGroup{Tag: tag, Domains: newDomainGroups(tagged[tag])})
+	}
+	if len(untagged) > 0 {
+		result = append(result, TagGroup{Tag: UntaggedGroup, Domains: newDomainGroups(untagged)})
+	}
+
+	return result, nil
+}
+
+// newDomainGroups converts a domain-to-accounts map into sorted DomainGroups
+func newDomainGroups(byDomain map[string][]AccountInfo) []DomainGroup {
+	groups := make([]DomainGroup, 0, len(byDomain))
+	for domain, accounts := range byDomain {
+		sort.Slice(accounts, func(i, j int) bool {
+			return accounts[i].Email < accounts[j].Email
+		})
+		groups = append(groups, DomainGroup{Domain: domain, Accounts: accounts})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Domain < groups[j].Domain
+	})
+	return groups
+}
@@ -0,0 +1,106 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// TestListAccountTreeUseCase_Execute_GroupsByTagThenDomain tests the nested grouping and ordering
+func TestListAccountTreeUseCase_Execute_GroupsByTagThenDomain(t *testing.T) {
+	accountRepo := newMockAccountRepository()
+
+	shared, _ := domain.NewAccount("shared@acme.com", "shared", "uuid-shared")
+	_ = shared.AddTag("work")
+	_ = shared.AddTag("client")
+	contractor, _ := domain.NewAccount("contractor@beta.io", "contractor", "uuid-contractor")
+	_ = contractor.AddTag("work")
+	colleague, _ := domain.NewAccount("colleague@acme.com", "colleague", "uuid-colleague")
+	_ = colleague.AddTag("work")
+	personal, _ := domain.NewAccount("me@gmail.com", "personal", "uuid-personal")
+
+	for _, account := range []*domain.Account{shared, contractor, colleague, personal} {
+		_ = accountRepo.Save(context.Background(), account)
+	}
+
+	useCase := usecases.NewListAccountTreeService(accountRepo)
+	tree, err := useCase.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	// Expected shape: tag -> domain -> emails
+	want := []struct {
+		tag     string
+		domains map[string][]string
+		order   []string
+	}{
+		{"client", map[string][]string{"acme.com": {"shared@acme.com"}}, []string{"acme.com"}},
+		{"work", map[string][]string{
+			"acme.com": {"colleague@acme.com", "shared@acme.com"},
+			"beta.io":  {"contractor@beta.io"},
+		}, []string{"acme.com", "beta.io"}},
+		{usecases.UntaggedGroup, map[string][]string{"gmail.com": {"me@gmail.com"}}, []string{"gmail.com"}},
+	}
+
+	if len(tree) != len(want) {
+		t.Fatalf("Expected %d tag groups, got %d: %+v", len(want), len(tree), tree)
+	}
+	for i, w := range want {
+		group := tree[i]
+		if group.Tag != w.tag {
+			t.Errorf("tree[%d].Tag = %s, want %s", i, group.Tag, w.tag)
+			continue
+		}
+		if len(group.Domains) != len(w.order) {
+			t.Errorf("Tag %s: expected %d domains, got %d", w.tag, len(w.order), len(group.Domains))
+			continue
+		}
+		for j, domainName := range w.order {
+			dg := group.Domains[j]
+			if dg.Domain != domainName {
+				t.Errorf("Tag %s: Domains[%d] = %s, want %s", w.tag, j, dg.Domain, domainName)
+				continue
+			}
+			emails := w.domains[domainName]
+			if len(dg.Accounts) != len(emails) {
+				t.Errorf("Tag %s/%s: expected %d accounts, got %d", w.tag, domainName, len(emails), len(dg.Accounts))
+				continue
+			}
+			for k, email := range emails {
+				if dg.Accounts[k].Email != email {
+					t.Errorf("Tag %s/%s: Accounts[%d] = %s, want %s", w.tag, domainName, k, dg.Accounts[k].Email, email)
+				}
+			}
+		}
+	}
+}
+
+// TestListAccountTreeUseCase_Execute_Empty tests an empty repository
+func TestListAccountTreeUseCase_Execute_Empty(t *testing.T) {
+	useCase := usecases.NewListAccountTreeService(newMockAccountRepository())
+
+	tree, err := useCase.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if len(tree) != 0 {
+		t.Errorf("Expected empty tree, got %+v", tree)
+	}
+}
+
+// TestListAccountTreeUseCase_Execute_ContextCancellation tests context cancellation
+func TestListAccountTreeUseCase_Execute_ContextCancellation(t *testing.T) {
+	useCase := usecases.NewListAccountTreeService(newMockAccountRepository())
+
+	// Create cancelled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := useCase.Execute(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -3,66 +3,285 @@
 package usecases
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/evanschultz/ccx/internal/domain"
 	"github.com/evanschultz/ccx/internal/ports"
 )
 
 // ListAccountsUseCase defines the interface for listing all accounts in ccx
 type ListAccountsUseCase interface {
 	Execute(ctx context.Context) ([]AccountInfo, error)
+	ExecuteWithOptions(ctx context.Context, opts ListOptions) ([]AccountInfo, error)
+	ExecutePage(ctx context.Context, opts ListOptions, page Page) (*PagedAccounts, error)
+}
+
+// ListSortField selects the field accounts are ordered by
+type ListSortField string
+
+// Supported sort fields
+const (
+	SortByEmail    ListSortField = "email"
+	SortByAlias    ListSortField = "alias"
+	SortByCreated  ListSortField = "created"
+	SortByLastUsed ListSortField = "lastUsed"
+)
+
+// ListOptions controls ordering and filtering of listed accounts.
+// The zero value sorts by email ascending and lists every unarchived account.
+type ListOptions struct {
+	SortBy              ListSortField // Field to sort by, email if empty
+	Descending          bool          // Reverse the sort order
+	FilterAlias         string        // Keep only aliases containing this, case-insensitively
+	FilterEmailContains string        // Keep only emails containing this, case-insensitively
+	IncludeArchived     bool          // Also list archived accounts
+}
+
+// Page selects a window of the listed accounts. The zero value selects every account.
+type Page struct {
+	Offset int // Accounts to skip
+	Limit  int // Most accounts to return, or 0 for no limit
+}
+
+// PagedAccounts is one page of listed accounts
+type PagedAccounts struct {
+	Items []AccountInfo `json:"items"` // Accounts on this page, in list order
+	Total int           `json:"total"` // Accounts matching the options across all pages
 }
 
 // AccountInfo represents account information returned to the presentation layer
 type AccountInfo struct {
-	ID        string    // Account ID as string for presentation
-	Email     string    // Account email
-	Alias     string    // Account alias
-	UUID      string    // Claude UUID
-	CreatedAt time.Time // When the account was added to ccx
+	ID          string    `json:"id"`          // Account ID as string for presentation
+	Email       string    `json:"email"`       // Account email
+	Alias       string    `json:"alias"`       // Account alias
+	UUID        string    `json:"uuid"`        // Claude UUID
+	Tags        []string  `json:"tags"`        // Sorted tags assigned to the account
+	Color       string    `json:"color"`       // Display color: a domain.AccountColors name, a hex code, or ""
+	Label       string    `json:"label"`       // Free-text label shown next to the account, or ""
+	Description string    `json:"description"` // Free-text notes about the account, or ""
+	CreatedAt   time.Time `json:"created_at"`  // When the account was added to ccx
+	LastUsed    time.Time `json:"last_used"`   // When the account was last switched to
+	Archived    bool      `json:"archived"`    // True if the account is archived
+	ArchivedAt  time.Time `json:"archived_at"` // When the account was archived, zero if it is not
+	// IsCurrent is true for the account Claude is signed in as. Only set by a
+	// ListAccountsService built WithListCurrentAccount.
+	IsCurrent bool `json:"is_current"`
 }
 
 // ListAccountsService implements the ListAccountsUseCase
 type ListAccountsService struct {
 	accounts ports.AccountRepository
+	config   ports.ConfigManager
 }
 
 // Ensure ListAccountsService implements ListAccountsUseCase at compile time
 var _ ListAccountsUseCase = (*ListAccountsService)(nil)
 
+// ListAccountsOption configures optional ListAccountsService behavior
+type ListAccountsOption func(*ListAccountsService)
+
+// WithListCurrentAccount gives ListAccountsService access to Claude config, so the
+// account Claude is signed in as is marked IsCurrent in every listing
+func WithListCurrentAccount(config ports.ConfigManager) ListAccountsOption {
+	return func(s *ListAccountsService) {
+		s.config = config
+	}
+}
+
 // NewListAccountsService creates a new ListAccountsService
-func NewListAccountsService(accounts ports.AccountRepository) ListAccountsUseCase {
-	return &ListAccountsService{
+func NewListAccountsService(accounts ports.AccountRepository, opts ...ListAccountsOption) ListAccountsUseCase {
+	s := &ListAccountsService{
 		accounts: accounts,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// Execute lists all accounts in ccx
+// Execute lists all unarchived accounts in ccx, sorted by email
 func (s *ListAccountsService) Execute(ctx context.Context) ([]AccountInfo, error) {
+	return s.ExecuteWithOptions(ctx, ListOptions{})
+}
+
+// ExecuteWithOptions lists the accounts matching the filters in the requested order.
+// Ties are broken by email so output is deterministic.
+func (s *ListAccountsService) ExecuteWithOptions(ctx context.Context, opts ListOptions) ([]AccountInfo, error) {
+	paged, err := s.ExecutePage(ctx, opts, Page{})
+	if err != nil {
+		return nil, err
+	}
+	return paged.Items, nil
+}
+
+// ExecutePage lists one page of the accounts ExecuteWithOptions would return, along with
+// how many there are in total. An offset past the end returns an empty page. Repositories
+// implementing ports.AccountPager do the paging in storage when they can sort by the
+// requested field; otherwise every account is loaded and the page sliced out.
+func (s *ListAccountsService) ExecutePage(ctx context.Context, opts ListOptions, page Page) (*PagedAccounts, error) {
 	// Check context before proceeding
 	if err := ctx.Err(); err != nil {
 		return nil, fmt.Errorf("context cancelled: %w", err)
 	}
 
+	if page.Offset < 0 || page.Limit < 0 {
+		return nil, fmt.Errorf("invalid page: offset %d and limit %d must not be negative", page.Offset, page.Limit)
+	}
+
+	compare, err := accountComparator(opts.SortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if pager, ok := s.accounts.(ports.AccountPager); ok {
+		if field, ok := pagerSortField(opts.SortBy); ok {
+			accounts, total, err := pager.ListPage(ctx, ports.AccountPageQuery{
+				SortBy:          field,
+				Descending:      opts.Descending,
+				AliasContains:   opts.FilterAlias,
+				EmailContains:   opts.FilterEmailContains,
+				IncludeArchived: opts.IncludeArchived,
+				Offset:          page.Offset,
+				Limit:           page.Limit,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list accounts: %w", err)
+			}
+			return s.annotateCurrent(ctx, newPagedAccounts(accounts, total))
+		}
+	}
+
 	// Retrieve all accounts from repository
 	accounts, err := s.accounts.List(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list accounts: %w", err)
 	}
 
-	// Convert domain entities to DTOs
-	result := make([]AccountInfo, len(accounts))
+	aliasFilter := strings.ToLower(opts.FilterAlias)
+	emailFilter := strings.ToLower(opts.FilterEmailContains)
+	accounts = slices.DeleteFunc(accounts, func(account *domain.Account) bool {
+		if account.Archived() && !opts.IncludeArchived {
+			return true
+		}
+		return !strings.Contains(strings.ToLower(account.Alias()), aliasFilter) ||
+			!strings.Contains(strings.ToLower(string(account.Email())), emailFilter)
+	})
+
+	sortAccounts(accounts, compare, opts.Descending)
+
+	total := len(accounts)
+	start := min(page.Offset, total)
+	end := total
+	if page.Limit > 0 && page.Limit < end-start {
+		end = start + page.Limit
+	}
+
+	return s.annotateCurrent(ctx, newPagedAccounts(accounts[start:end], total))
+}
+
+// annotateCurrent marks the listed account Claude is signed in as, matching by UUID and
+// falling back to email like WhoAmI does. Nothing is marked when the service has no
+// config manager, Claude names no account, or that account isn't on the page.
+func (s *ListAccountsService) annotateCurrent(ctx context.Context, paged *PagedAccounts) (*PagedAccounts, error) {
+	if s.config == nil || len(paged.Items) == 0 {
+		return paged, nil
+	}
+
+	current, err := s.config.GetCurrentAccount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current account: %w", err)
+	}
+	if current == nil {
+		return paged, nil
+	}
+
+	// The UUID survives an email change, so it is the better match
+	i := slices.IndexFunc(paged.Items, func(info AccountInfo) bool {
+		return current.UUID() != "" && info.UUID == current.UUID()
+	})
+	if i < 0 {
+		i = slices.IndexFunc(paged.Items, func(info AccountInfo) bool {
+			return info.Email == string(current.Email())
+		})
+	}
+	if i >= 0 {
+		paged.Items[i].IsCurrent = true
+	}
+	return paged, nil
+}
+
+// pagerSortField maps a sort field to the one an AccountPager orders by. The bool is
+// false for fields pagers don't support.
+func pagerSortField(field ListSortField) (ports.AccountSortField, bool) {
+	switch field {
+	case "", SortByEmail:
+		return ports.AccountSortEmail, true
+	case SortByAlias:
+		return ports.AccountSortAlias, true
+	default:
+		return "", false
+	}
+}
+
+// newPagedAccounts converts a page of domain Accounts to the PagedAccounts DTO
+func newPagedAccounts(accounts []*domain.Account, total int) *PagedAccounts {
+	items := make([]AccountInfo, len(accounts))
 	for i, account := range accounts {
-		result[i] = AccountInfo{
-			ID:        string(account.ID()),
-			Email:     string(account.Email()),
-			Alias:     account.Alias(),
-			UUID:      account.UUID(),
-			CreatedAt: account.CreatedAt(),
+		items[i] = newAccountInfo(account)
+	}
+	return &PagedAccounts{Items: items, Total: total}
+}
+
+// accountComparator returns the ordering for a sort field
+// sortAccounts orders accounts by compare, breaking ties by email so the order is
+// deterministic
+func sortAccounts(accounts []*domain.Account, compare func(a, b *domain.Account) int, descending bool) {
+	slices.SortFunc(accounts, func(a, b *domain.Account) int {
+		c := compare(a, b)
+		if c == 0 {
+			c = cmp.Compare(a.Email(), b.Email())
 		}
+		if descending {
+			return -c
+		}
+		return c
+	})
+}
+
+func accountComparator(field ListSortField) (func(a, b *domain.Account) int, error) {
+	switch field {
+	case "", SortByEmail:
+		return func(a, b *domain.Account) int { return cmp.Compare(a.Email(), b.Email()) }, nil
+	case SortByAlias:
+		return func(a, b *domain.Account) int { return cmp.Compare(a.Alias(), b.Alias()) }, nil
+	case SortByCreated:
+		return func(a, b *domain.Account) int { return a.CreatedAt().Compare(b.CreatedAt()) }, nil
+	case SortByLastUsed:
+		return func(a, b *domain.Account) int { return a.LastUsed().Compare(b.LastUsed()) }, nil
+	default:
+		return nil, fmt.Errorf("unsupported sort field %q", field)
 	}
+}
 
-	return result, nil
+// newAccountInfo converts a domain Account to the AccountInfo DTO
+func newAccountInfo(account *domain.Account) AccountInfo {
+	return AccountInfo{
+		ID:          string(account.ID()),
+		Email:       string(account.Email()),
+		Alias:       account.Alias(),
+		UUID:        account.UUID(),
+		Tags:        account.Tags(),
+		Color:       account.Color(),
+		Label:       account.Label(),
+		Description: account.Description(),
+		CreatedAt:   account.CreatedAt(),
+		LastUsed:    account.LastUsed(),
+		Archived:    account.Archived(),
+		ArchivedAt:  account.ArchivedAt(),
+	}
 }
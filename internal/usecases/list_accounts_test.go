@@ -3,9 +3,12 @@ package usecases_test
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
 	"github.com/evanschultz/ccx/internal/usecases"
 )
 
@@ -93,6 +96,164 @@ func TestListAccountsUseCase_Execute_MultipleAccounts(t *testing.T) {
 	}
 }
 
+// TestListAccountsUseCase_ExecuteWithOptions tests sorting and filtering
+func TestListAccountsUseCase_ExecuteWithOptions(t *testing.T) {
+	setup := setupListAccountsTest()
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fixtures := []struct {
+		id, email, alias  string
+		created, lastUsed int // Days after base
+	}{
+		{"aaaa0001", "carol@corp.com", "Work", 0, 5},
+		{"aaaa0002", "alice@home.org", "personal", 2, 1},
+		{"aaaa0003", "bob@corp.com", "client-work", 1, 9},
+	}
+	for _, f := range fixtures {
+		account, err := domain.ReconstructAccount(domain.AccountID(f.id), f.email, f.alias, "uuid-"+f.id, nil,
+			base.AddDate(0, 0, f.created), base.AddDate(0, 0, f.lastUsed))
+		if err != nil {
+			t.Fatalf("failed to create account: %v", err)
+		}
+		_ = setup.accountRepo.Save(ctx, account)
+	}
+
+	tests := []struct {
+		name string
+		opts usecases.ListOptions
+		want []string // Expected emails in order
+	}{
+		{"zero value sorts by email", usecases.ListOptions{}, []string{"alice@home.org", "bob@corp.com", "carol@corp.com"}},
+		{"email descending", usecases.ListOptions{SortBy: usecases.SortByEmail, Descending: true}, []string{"carol@corp.com", "bob@corp.com", "alice@home.org"}},
+		{"alias", usecases.ListOptions{SortBy: usecases.SortByAlias}, []string{"carol@corp.com", "bob@corp.com", "alice@home.org"}},
+		{"created", usecases.ListOptions{SortBy: usecases.SortByCreated}, []string{"carol@corp.com", "bob@corp.com", "alice@home.org"}},
+		{"last used descending", usecases.ListOptions{SortBy: usecases.SortByLastUsed, Descending: true}, []string{"bob@corp.com", "carol@corp.com", "alice@home.org"}},
+		{"filter alias ignores case", usecases.ListOptions{FilterAlias: "WORK"}, []string{"bob@corp.com", "carol@corp.com"}},
+		{"filter email", usecases.ListOptions{FilterEmailContains: "corp"}, []string{"bob@corp.com", "carol@corp.com"}},
+		{"filters combine", usecases.ListOptions{FilterAlias: "work", FilterEmailContains: "carol"}, []string{"carol@corp.com"}},
+		{"no match", usecases.ListOptions{FilterEmailContains: "nobody"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accounts, err := setup.useCase.ExecuteWithOptions(ctx, tt.opts)
+			if err != nil {
+				t.Fatalf("ExecuteWithOptions() error = %v, want nil", err)
+			}
+			got := make([]string, len(accounts))
+			for i, account := range accounts {
+				got[i] = account.Email
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ExecuteWithOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := setup.useCase.ExecuteWithOptions(ctx, usecases.ListOptions{SortBy: "size"}); err == nil {
+		t.Error("ExecuteWithOptions() with unknown sort field error = nil, want error")
+	}
+}
+
+func TestListAccountsUseCase_ExecutePage(t *testing.T) {
+	setup := setupListAccountsTest()
+	ctx := context.Background()
+
+	for _, email := range []string{"d@example.com", "b@example.com", "e@example.com", "a@example.com", "c@example.com"} {
+		account, _ := domain.NewAccount(email, "", "uuid-"+email)
+		_ = setup.accountRepo.Save(ctx, account)
+	}
+
+	tests := []struct {
+		name string
+		page usecases.Page
+		want []string
+	}{
+		{"zero page lists all", usecases.Page{}, []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com"}},
+		{"first page", usecases.Page{Limit: 2}, []string{"a@example.com", "b@example.com"}},
+		{"middle page", usecases.Page{Offset: 2, Limit: 2}, []string{"c@example.com", "d@example.com"}},
+		{"short last page", usecases.Page{Offset: 4, Limit: 2}, []string{"e@example.com"}},
+		{"offset without limit", usecases.Page{Offset: 3}, []string{"d@example.com", "e@example.com"}},
+		{"offset past end", usecases.Page{Offset: 9, Limit: 2}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paged, err := setup.useCase.ExecutePage(ctx, usecases.ListOptions{}, tt.page)
+			if err != nil {
+				t.Fatalf("ExecutePage() error = %v, want nil", err)
+			}
+			got := make([]string, len(paged.Items))
+			for i, account := range paged.Items {
+				got[i] = account.Email
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ExecutePage() items = %v, want %v", got, tt.want)
+			}
+			if paged.Total != 5 {
+				t.Errorf("ExecutePage() total = %d, want 5", paged.Total)
+			}
+		})
+	}
+
+	// Total counts only accounts matching the filters
+	paged, err := setup.useCase.ExecutePage(ctx, usecases.ListOptions{FilterEmailContains: "a@"}, usecases.Page{Limit: 1})
+	if err != nil || paged.Total != 1 || len(paged.Items) != 1 {
+		t.Errorf("ExecutePage() with filter = %+v, %v; want one item of one", paged, err)
+	}
+
+	for _, page := range []usecases.Page{{Offset: -1}, {Limit: -1}} {
+		if _, err := setup.useCase.ExecutePage(ctx, usecases.ListOptions{}, page); err == nil {
+			t.Errorf("ExecutePage(%+v) error = nil, want error", page)
+		}
+	}
+}
+
+// pagingAccountRepository records the query passed to ListPage
+type pagingAccountRepository struct {
+	*mockAccountRepository
+	query ports.AccountPageQuery
+	calls int
+}
+
+func (r *pagingAccountRepository) ListPage(_ context.Context, query ports.AccountPageQuery) ([]*domain.Account, int, error) {
+	r.calls++
+	r.query = query
+	account, _ := domain.NewAccount("paged@example.com", "", "uuid-paged")
+	return []*domain.Account{account}, 42, nil
+}
+
+func TestListAccountsUseCase_ExecutePage_UsesPager(t *testing.T) {
+	repo := &pagingAccountRepository{mockAccountRepository: newMockAccountRepository()}
+	useCase := usecases.NewListAccountsService(repo)
+	ctx := context.Background()
+
+	opts := usecases.ListOptions{SortBy: usecases.SortByAlias, Descending: true, FilterAlias: "Work", IncludeArchived: true}
+	paged, err := useCase.ExecutePage(ctx, opts, usecases.Page{Offset: 20, Limit: 10})
+	if err != nil {
+		t.Fatalf("ExecutePage() error = %v", err)
+	}
+	if paged.Total != 42 || len(paged.Items) != 1 || paged.Items[0].Email != "paged@example.com" {
+		t.Errorf("ExecutePage() = %+v, want the pager's page", paged)
+	}
+	want := ports.AccountPageQuery{
+		SortBy: ports.AccountSortAlias, Descending: true, AliasContains: "Work",
+		IncludeArchived: true, Offset: 20, Limit: 10,
+	}
+	if repo.query != want {
+		t.Errorf("ListPage() query = %+v, want %+v", repo.query, want)
+	}
+
+	// Pagers don't sort by timestamps, so those pages are sliced from the full list
+	if _, err := useCase.ExecutePage(ctx, usecases.ListOptions{SortBy: usecases.SortByCreated}, usecases.Page{Limit: 1}); err != nil {
+		t.Fatalf("ExecutePage() by created error = %v", err)
+	}
+	if repo.calls != 1 {
+		t.Errorf("ListPage() called %d times, want 1", repo.calls)
+	}
+}
+
 // TestListAccountsUseCase_Execute_RepositoryError tests repository failure handling
 func TestListAccountsUseCase_Execute_RepositoryError(t *testing.T) {
 	setup := setupListAccountsTest()
@@ -127,6 +288,8 @@ func TestListAccountsUseCase_Execute_AccountInfo(t *testing.T) {
 
 	// Setup: Add a test account
 	account, _ := domain.NewAccount("test@example.com", "myalias", "uuid-test")
+	_ = account.SetColor("cyan")
+	_ = account.SetLabel("staging")
 	_ = setup.accountRepo.Save(ctx, account)
 
 	// Execute
@@ -153,11 +316,71 @@ func TestListAccountsUseCase_Execute_AccountInfo(t *testing.T) {
 	if info.UUID != "uuid-test" {
 		t.Errorf("Expected UUID uuid-test, got %s", info.UUID)
 	}
+	if info.Color != "cyan" || info.Label != "staging" {
+		t.Errorf("Expected color/label cyan/staging, got %s/%s", info.Color, info.Label)
+	}
 	if info.CreatedAt.IsZero() {
 		t.Error("AccountInfo.CreatedAt should not be zero")
 	}
 }
 
+// TestListAccountsUseCase_Execute_MarksCurrent tests that the account Claude is signed in
+// as is marked when the service can read Claude config
+func TestListAccountsUseCase_Execute_MarksCurrent(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository()
+	config := newMockConfigManager()
+	useCase := usecases.NewListAccountsService(accountRepo, usecases.WithListCurrentAccount(config))
+
+	work, _ := domain.NewAccount(testEmailWork, "work", "uuid-work")
+	personal, _ := domain.NewAccount(testEmailPersonal, "personal", "uuid-personal")
+	_ = accountRepo.Save(ctx, work)
+	_ = accountRepo.Save(ctx, personal)
+
+	currentEmails := func() []string {
+		t.Helper()
+		accounts, err := useCase.Execute(ctx)
+		if err != nil {
+			t.Fatalf("Execute() error = %v, want nil", err)
+		}
+		var emails []string
+		for _, info := range accounts {
+			if info.IsCurrent {
+				emails = append(emails, info.Email)
+			}
+		}
+		return emails
+	}
+
+	// No account configured in Claude: nothing is current
+	if got := currentEmails(); len(got) != 0 {
+		t.Errorf("Current accounts = %v, want none", got)
+	}
+
+	// Matched by UUID even after the email changed in Claude config
+	config.currentAccount, _ = domain.NewAccount("renamed@example.com", "", "uuid-work")
+	if got := currentEmails(); !slices.Equal(got, []string{testEmailWork}) {
+		t.Errorf("Current accounts = %v, want [%s]", got, testEmailWork)
+	}
+
+	// Matched by email when the UUID is unknown
+	config.currentAccount, _ = domain.NewAccount(testEmailPersonal, "", "uuid-other")
+	if got := currentEmails(); !slices.Equal(got, []string{testEmailPersonal}) {
+		t.Errorf("Current accounts = %v, want [%s]", got, testEmailPersonal)
+	}
+
+	// A Claude account ccx doesn't manage marks nothing
+	config.currentAccount, _ = domain.NewAccount("stranger@example.com", "", "uuid-stranger")
+	if got := currentEmails(); len(got) != 0 {
+		t.Errorf("Current accounts = %v, want none", got)
+	}
+
+	config.getErr = errors.New("config unreadable")
+	if _, err := useCase.Execute(ctx); !errors.Is(err, config.getErr) {
+		t.Errorf("Execute() error = %v, want %v", err, config.getErr)
+	}
+}
+
 // TestListAccountsUseCase_Execute_ContextCancellation tests context cancellation handling
 func TestListAccountsUseCase_Execute_ContextCancellation(t *testing.T) {
 	setup := setupListAccountsTest()
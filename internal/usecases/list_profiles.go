@@ -0,0 +1,84 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// ListProfilesUseCase defines the interface for listing all profiles
+type ListProfilesUseCase interface {
+	Execute(ctx context.Context) ([]ProfileInfo, error)
+}
+
+// ProfileInfo represents profile information returned to the presentation layer
+type ProfileInfo struct {
+	Name      string       `json:"name"`       // Profile name
+	AccountID string       `json:"account_id"` // Account the profile switches to
+	Account   *AccountInfo `json:"account"`    // The account, or nil if it no longer exists
+}
+
+// Orphaned reports whether the profile's account has been removed
+func (p ProfileInfo) Orphaned() bool {
+	return p.Account == nil
+}
+
+// newProfileInfo describes profile; account is nil if the profile is orphaned
+func newProfileInfo(profile *domain.Profile, account *domain.Account) ProfileInfo {
+	info := ProfileInfo{
+		Name:      profile.Name(),
+		AccountID: string(profile.AccountID()),
+	}
+	if account != nil {
+		accountInfo := newAccountInfo(account)
+		info.Account = &accountInfo
+	}
+	return info
+}
+
+// ListProfilesService implements the ListProfilesUseCase
+type ListProfilesService struct {
+	profiles ports.ProfileRepository
+	accounts ports.AccountRepository
+}
+
+// Ensure ListProfilesService implements ListProfilesUseCase at compile time
+var _ ListProfilesUseCase = (*ListProfilesService)(nil)
+
+// NewListProfilesService creates a new ListProfilesService
+func NewListProfilesService(profiles ports.ProfileRepository, accounts ports.AccountRepository) ListProfilesUseCase {
+	return &ListProfilesService{
+		profiles: profiles,
+		accounts: accounts,
+	}
+}
+
+// Execute returns every profile sorted by name, with its account resolved. Profiles
+// whose account was removed are included as orphaned rather than hidden.
+func (s *ListProfilesService) Execute(ctx context.Context) ([]ProfileInfo, error) {
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	profiles, err := s.profiles.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	result := make([]ProfileInfo, 0, len(profiles))
+	for _, profile := range profiles {
+		account, err := s.accounts.FindByID(ctx, profile.AccountID())
+		if err != nil && !errors.Is(err, domain.ErrAccountNotFound) {
+			return nil, fmt.Errorf("failed to find account for profile %s: %w", profile.Name(), err)
+		}
+		result = append(result, newProfileInfo(profile, account))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,50 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// TestListProfilesUseCase_Execute tests listing profiles, including orphaned ones
+func TestListProfilesUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository()
+	work, _ := domain.NewAccount(testEmailWork, "work", "uuid-work")
+	removed, _ := domain.NewAccount(testEmailTest, "test", "uuid-test")
+	_ = accountRepo.Save(ctx, work)
+
+	profileRepo := newMockProfileRepository()
+	seedProfile(profileRepo, "work", work)
+	seedProfile(profileRepo, "client-a", work)
+	seedProfile(profileRepo, "old", removed)
+
+	profiles, err := usecases.NewListProfilesService(profileRepo, accountRepo).Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	want := []struct {
+		name     string
+		orphaned bool
+	}{{"client-a", false}, {"old", true}, {"work", false}}
+	if len(profiles) != len(want) {
+		t.Fatalf("Execute() returned %d profiles, want %d", len(profiles), len(want))
+	}
+	for i, w := range want {
+		if profiles[i].Name != w.name || profiles[i].Orphaned() != w.orphaned {
+			t.Errorf("profile %d = %s (orphaned %v), want %s (orphaned %v)", i, profiles[i].Name, profiles[i].Orphaned(), w.name, w.orphaned)
+		}
+	}
+	if profiles[1].AccountID != string(removed.ID()) {
+		t.Errorf("Orphaned profile AccountID = %s, want %s", profiles[1].AccountID, removed.ID())
+	}
+
+	profileRepo.listErr = errors.New("profiles file corrupted")
+	if _, err := usecases.NewListProfilesService(profileRepo, accountRepo).Execute(ctx); !errors.Is(err, profileRepo.listErr) {
+		t.Errorf("Execute() error = %v, want %v", err, profileRepo.listErr)
+	}
+}
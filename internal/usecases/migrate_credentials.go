@@ -0,0 +1,197 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// MigrateCredentialsUseCase defines the interface for moving every account's credentials
+// from one credential store to another, such as from the file store to the keychain
+type MigrateCredentialsUseCase interface {
+	Execute(ctx context.Context, from, to ports.CredentialStore) (*MigrationResult, error)
+}
+
+// MigrationStatus is the outcome of migrating a single account's credentials
+type MigrationStatus string
+
+const (
+	// MigrationMigrated means the credentials were copied, read back intact, and deleted
+	// from the old store
+	MigrationMigrated MigrationStatus = "migrated"
+	// MigrationSkipped means the new store already had credentials for the account, so
+	// neither store was changed
+	MigrationSkipped MigrationStatus = "skipped"
+	// MigrationMissing means the old store had no credentials for the account
+	MigrationMissing MigrationStatus = "missing"
+	// MigrationFailed means the credentials could not be moved; they are still in the
+	// old store
+	MigrationFailed MigrationStatus = "failed"
+)
+
+// AccountMigration reports what happened to a single account's credentials
+type AccountMigration struct {
+	Account AccountInfo     `json:"account"` // Account whose credentials were migrated
+	Status  MigrationStatus `json:"status"`  // Outcome for the account
+	Err     error           `json:"-"`       // Reason the migration failed, nil otherwise
+}
+
+// accountMigrationJSON is the JSON shape of AccountMigration, with Err as its message
+type accountMigrationJSON struct {
+	Account AccountInfo     `json:"account"`
+	Status  MigrationStatus `json:"status"`
+	Error   *string         `json:"error"` // null unless failed
+}
+
+// MarshalJSON encodes Err as its message under "error", or null if there is none
+func (m AccountMigration) MarshalJSON() ([]byte, error) {
+	out := accountMigrationJSON{Account: m.Account, Status: m.Status}
+	if m.Err != nil {
+		msg := m.Err.Error()
+		out.Error = &msg
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes the shape written by MarshalJSON. Err only keeps the message,
+// so errors.Is no longer matches the original sentinel.
+func (m *AccountMigration) UnmarshalJSON(data []byte) error {
+	var in accountMigrationJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*m = AccountMigration{Account: in.Account, Status: in.Status}
+	if in.Error != nil {
+		m.Err = errors.New(*in.Error)
+	}
+	return nil
+}
+
+// MigrationResult contains the per-account report of a credential migration
+type MigrationResult struct {
+	Accounts []AccountMigration `json:"accounts"` // One entry per account, in repository order
+	Migrated int                `json:"migrated"` // Accounts whose credentials were moved
+	Skipped  int                `json:"skipped"`  // Accounts already in the new store
+	Missing  int                `json:"missing"`  // Accounts with no credentials to move
+	Failed   int                `json:"failed"`   // Accounts whose credentials could not be moved
+}
+
+// MigrateCredentialsService implements the MigrateCredentialsUseCase
+type MigrateCredentialsService struct {
+	accounts ports.AccountRepository
+}
+
+// Ensure MigrateCredentialsService implements MigrateCredentialsUseCase at compile time
+var _ MigrateCredentialsUseCase = (*MigrateCredentialsService)(nil)
+
+// NewMigrateCredentialsService creates a new MigrateCredentialsService
+func NewMigrateCredentialsService(accounts ports.AccountRepository) MigrateCredentialsUseCase {
+	return &MigrateCredentialsService{
+		accounts: accounts,
+	}
+}
+
+// Execute moves each account's credentials from one store to the other, one account at a
+// time: they are written to the new store and read back, and only deleted from the old
+// store once the copy matches. A failing account does not stop the others, so an
+// interrupted migration leaves each account's credentials whole in one store or the other.
+// Running it again skips accounts already in the new store, which makes it safe to repeat.
+// An error is returned only if the accounts can't be listed or ctx is cancelled, along
+// with the accounts processed so far.
+func (s *MigrateCredentialsService) Execute(ctx context.Context, from, to ports.CredentialStore) (*MigrationResult, error) {
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	accounts, err := s.accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	result := &MigrationResult{
+		Accounts: make([]AccountMigration, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		// Check context before proceeding
+		if err := ctx.Err(); err != nil {
+			return result, fmt.Errorf("context cancelled: %w", err)
+		}
+
+		outcome := AccountMigration{Account: newAccountInfo(account)}
+		outcome.Status, outcome.Err = migrateCredentials(ctx, account.ID(), from, to)
+
+		switch outcome.Status {
+		case MigrationMigrated:
+			result.Migrated++
+		case MigrationSkipped:
+			result.Skipped++
+		case MigrationMissing:
+			result.Missing++
+		case MigrationFailed:
+			result.Failed++
+		}
+		result.Accounts = append(result.Accounts, outcome)
+	}
+
+	return result, nil
+}
+
+// migrateCredentials moves one account's credentials from one store to the other
+func migrateCredentials(ctx context.Context, id domain.AccountID, from, to ports.CredentialStore) (MigrationStatus, error) {
+	if _, err := to.Retrieve(ctx, id); err == nil {
+		return MigrationSkipped, nil
+	} else if !errors.Is(err, domain.ErrCredentialsNotFound) {
+		return MigrationFailed, fmt.Errorf("failed to check the new store: %w", err)
+	}
+
+	creds, err := from.Retrieve(ctx, id)
+	if errors.Is(err, domain.ErrCredentialsNotFound) {
+		return MigrationMissing, nil
+	}
+	if err != nil {
+		return MigrationFailed, fmt.Errorf("failed to read credentials: %w", err)
+	}
+
+	if err := to.Store(ctx, creds); err != nil {
+		return MigrationFailed, fmt.Errorf("failed to write credentials: %w", err)
+	}
+
+	// A copy that doesn't read back must not stay behind, or the next run would skip it
+	copied, err := to.Retrieve(ctx, id)
+	if err == nil && !sameCredentials(creds, copied) {
+		err = errors.New("credentials read back differ from the original")
+	}
+	if err != nil {
+		if delErr := to.Delete(ctx, id); delErr != nil && !errors.Is(delErr, domain.ErrCredentialsNotFound) {
+			err = errors.Join(err, fmt.Errorf("failed to remove the bad copy: %w", delErr))
+		}
+		return MigrationFailed, fmt.Errorf("failed to verify copied credentials: %w", err)
+	}
+
+	if err := from.Delete(ctx, id); err != nil {
+		return MigrationFailed, fmt.Errorf("credentials were copied but not removed from the old store: %w", err)
+	}
+	return MigrationMigrated, nil
+}
+
+// sameCredentials reports whether b holds the same session as a. Credentials that can't
+// be decrypted here, such as passphrase-protected ones, are compared by ciphertext.
+func sameCredentials(a, b *domain.Credentials) bool {
+	if a.AccountID() != b.AccountID() {
+		return false
+	}
+	plainA, err := a.Decrypt()
+	if err != nil {
+		return bytes.Equal(a.EncryptedData(), b.EncryptedData())
+	}
+	plainB, err := b.Decrypt()
+	return err == nil && bytes.Equal(plainA, plainB)
+}
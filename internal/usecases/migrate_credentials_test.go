@@ -0,0 +1,186 @@
+package usecases_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// garblingCredentialStore stores different credentials than it is given, like a backend
+// that truncates large items
+type garblingCredentialStore struct {
+	*mockCredentialStore
+}
+
+func (s *garblingCredentialStore) Store(ctx context.Context, creds *domain.Credentials) error {
+	garbled, _ := domain.NewCredentials(creds.AccountID(), []byte(`{"sessionKey":"truncated"}`))
+	return s.mockCredentialStore.Store(ctx, garbled)
+}
+
+// setupMigrateCredentialsTest creates accounts personal, work, and test. Personal and
+// work have credentials in from; work also has them in to already.
+func setupMigrateCredentialsTest(t *testing.T) (repo *mockAccountRepository, accounts map[string]*domain.Account, from, to *mockCredentialStore) {
+	t.Helper()
+	ctx := context.Background()
+	repo = newMockAccountRepository()
+	from, to = newMockCredentialStore(), newMockCredentialStore()
+	accounts = make(map[string]*domain.Account)
+
+	for alias, email := range map[string]string{"personal": testEmailPersonal, "work": testEmailWork, "test": testEmailTest} {
+		account, _ := domain.NewAccount(email, alias, "uuid-"+alias)
+		_ = repo.Save(ctx, account)
+		accounts[alias] = account
+	}
+	for _, alias := range []string{"personal", "work"} {
+		creds, _ := domain.NewCredentials(accounts[alias].ID(), []byte(`{"sessionKey":"sk-`+alias+`"}`))
+		_ = from.Store(ctx, creds)
+	}
+	_ = to.Store(ctx, from.credentials[accounts["work"].ID()])
+
+	return repo, accounts, from, to
+}
+
+// migrationStatuses maps each account's alias to its migration status
+func migrationStatuses(result *usecases.MigrationResult) map[string]usecases.MigrationStatus {
+	statuses := make(map[string]usecases.MigrationStatus, len(result.Accounts))
+	for _, migration := range result.Accounts {
+		statuses[migration.Account.Alias] = migration.Status
+	}
+	return statuses
+}
+
+func TestMigrateCredentialsUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	repo, accounts, from, to := setupMigrateCredentialsTest(t)
+	useCase := usecases.NewMigrateCredentialsService(repo)
+
+	result, err := useCase.Execute(ctx, from, to)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	statuses := migrationStatuses(result)
+	want := map[string]usecases.MigrationStatus{
+		"personal": usecases.MigrationMigrated,
+		"work":     usecases.MigrationSkipped,
+		"test":     usecases.MigrationMissing,
+	}
+	for alias, status := range want {
+		if statuses[alias] != status {
+			t.Errorf("%s status = %s, want %s", alias, statuses[alias], status)
+		}
+	}
+	if result.Migrated != 1 || result.Skipped != 1 || result.Missing != 1 || result.Failed != 0 {
+		t.Errorf("Counts = %+v, want one migrated, skipped, and missing", result)
+	}
+
+	personal := accounts["personal"].ID()
+	if _, ok := from.credentials[personal]; ok {
+		t.Error("Migrated credentials should be deleted from the old store")
+	}
+	if data, err := to.credentials[personal].Decrypt(); err != nil || string(data) != `{"sessionKey":"sk-personal"}` {
+		t.Errorf("New store holds %s, %v; want the personal session", data, err)
+	}
+	if _, ok := from.credentials[accounts["work"].ID()]; !ok {
+		t.Error("Skipped credentials should be left in the old store")
+	}
+
+	// Running again changes nothing
+	result, err = useCase.Execute(ctx, from, to)
+	if err != nil {
+		t.Fatalf("Execute() again error = %v", err)
+	}
+	if result.Migrated != 0 || result.Failed != 0 || result.Skipped != 2 {
+		t.Errorf("Second run counts = %+v, want everything present skipped", result)
+	}
+}
+
+func TestMigrateCredentialsUseCase_Execute_PassphraseProtected(t *testing.T) {
+	ctx := context.Background()
+	repo, accounts, from, to := setupMigrateCredentialsTest(t)
+	test := accounts["test"].ID()
+	creds, _ := domain.NewCredentialsWithPassphrase(test, []byte(`{"sessionKey":"sk-test"}`), []byte("passphrase"))
+	_ = from.Store(ctx, creds)
+
+	result, err := usecases.NewMigrateCredentialsService(repo).Execute(ctx, from, to)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if status := migrationStatuses(result)["test"]; status != usecases.MigrationMigrated {
+		t.Errorf("Passphrase-protected status = %s, want migrated", status)
+	}
+	if moved := to.credentials[test]; moved == nil || !moved.IsPassphraseProtected() {
+		t.Error("Passphrase-protected credentials should be moved as is")
+	}
+}
+
+func TestMigrateCredentialsUseCase_Execute_Failures(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("write fails", func(t *testing.T) {
+		repo, accounts, from, to := setupMigrateCredentialsTest(t)
+		delete(to.credentials, accounts["work"].ID())
+		to.storeErr = errors.New("keychain is locked")
+
+		result, err := usecases.NewMigrateCredentialsService(repo).Execute(ctx, from, to)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.Failed != 2 || result.Missing != 1 {
+			t.Errorf("Counts = %+v, want two failed and one missing", result)
+		}
+		if len(from.credentials) != 2 {
+			t.Errorf("Old store has %d credentials, want both kept", len(from.credentials))
+		}
+	})
+
+	t.Run("copy reads back different", func(t *testing.T) {
+		repo, accounts, from, _ := setupMigrateCredentialsTest(t)
+		to := &garblingCredentialStore{mockCredentialStore: newMockCredentialStore()}
+
+		result, err := usecases.NewMigrateCredentialsService(repo).Execute(ctx, from, to)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.Failed != 2 {
+			t.Errorf("Counts = %+v, want two failed", result)
+		}
+		if len(to.credentials) != 0 {
+			t.Error("Bad copies should be removed from the new store so a rerun retries them")
+		}
+		if _, ok := from.credentials[accounts["personal"].ID()]; !ok {
+			t.Error("Credentials should stay in the old store when the copy is bad")
+		}
+
+		for _, migration := range result.Accounts {
+			data, _ := json.Marshal(migration)
+			if migration.Status == usecases.MigrationFailed && !strings.Contains(string(data), `"error":"failed to verify`) {
+				t.Errorf("JSON = %s, want the failure reason", data)
+			}
+		}
+	})
+
+	t.Run("listing fails", func(t *testing.T) {
+		repo, _, from, to := setupMigrateCredentialsTest(t)
+		repo.findErr = errors.New("disk error")
+
+		if _, err := usecases.NewMigrateCredentialsService(repo).Execute(ctx, from, to); err == nil {
+			t.Error("Execute() error = nil, want error")
+		}
+	})
+}
+
+func TestMigrateCredentialsUseCase_Execute_ContextCancellation(t *testing.T) {
+	repo, _, from, to := setupMigrateCredentialsTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := usecases.NewMigrateCredentialsService(repo).Execute(ctx, from, to); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,143 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// PreflightSwitchUseCase defines the interface for checking whether a switch would succeed
+type PreflightSwitchUseCase interface {
+	Execute(ctx context.Context, input SwitchAccountInput) (*PreflightResult, error)
+}
+
+// PreflightIssueKind classifies a condition that would block a switch
+type PreflightIssueKind string
+
+// Preflight issue kinds
+const (
+	IssueInvalidInput             PreflightIssueKind = "invalid_input"
+	IssueAccountNotFound          PreflightIssueKind = "account_not_found"
+	IssueConfigUnreadable         PreflightIssueKind = "config_unreadable"
+	IssueStorageUnreadable        PreflightIssueKind = "storage_unreadable"
+	IssueCredentialsMissing       PreflightIssueKind = "credentials_missing"
+	IssueCredentialsUndecryptable PreflightIssueKind = "credentials_undecryptable"
+	IssueCredentialsExpired       PreflightIssueKind = "credentials_expired"
+)
+
+// PreflightIssue describes a single blocking condition
+type PreflightIssue struct {
+	Kind    PreflightIssueKind `json:"kind"`    // Machine-readable classification
+	Message string             `json:"message"` // Human-readable explanation
+}
+
+// PreflightResult contains every issue found for a prospective switch
+type PreflightResult struct {
+	Target *AccountInfo     `json:"target"` // Resolved target account (nil if it could not be resolved)
+	Issues []PreflightIssue `json:"issues"` // Blocking conditions; empty if the switch would succeed
+}
+
+// OK reports whether the switch would succeed
+func (r *PreflightResult) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// PreflightSwitchService implements the PreflightSwitchUseCase
+type PreflightSwitchService struct {
+	resolver    *SwitchAccountService
+	credentials ports.CredentialStore
+	config      ports.ConfigManager
+}
+
+// Ensure PreflightSwitchService implements PreflightSwitchUseCase at compile time
+var _ PreflightSwitchUseCase = (*PreflightSwitchService)(nil)
+
+// NewPreflightSwitchService creates a new PreflightSwitchService. Options are the same as
+// for SwitchAccountService so both resolve targets identically.
+func NewPreflightSwitchService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+	config ports.ConfigManager,
+	history ports.HistoryRepository,
+	opts ...SwitchAccountOption,
+) PreflightSwitchUseCase {
+	return &PreflightSwitchService{
+		resolver:    newSwitchAccountService(accounts, credentials, config, history, opts...),
+		credentials: credentials,
+		config:      config,
+	}
+}
+
+// Execute checks a prospective switch without changing anything, collecting every
+// blocking condition rather than stopping at the first
+func (s *PreflightSwitchService) Execute(ctx context.Context, input SwitchAccountInput) (*PreflightResult, error) {
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	result := &PreflightResult{Issues: []PreflightIssue{}}
+
+	if err := validateSwitchInput(input); err != nil {
+		result.addIssue(IssueInvalidInput, err.Error())
+		return result, nil
+	}
+
+	if _, err := s.config.GetCurrentAccount(ctx); err != nil {
+		result.addIssue(IssueConfigUnreadable, fmt.Sprintf("cannot read current account: %v", err))
+	}
+
+	target, err := s.resolver.determineTargetAccount(ctx, input)
+	if err != nil {
+		result.addIssue(targetIssueKind(err), err.Error())
+		return result, nil
+	}
+	info := newAccountInfo(target)
+	result.Target = &info
+
+	creds, err := s.credentials.Retrieve(ctx, target.ID())
+	if err != nil {
+		result.addIssue(IssueCredentialsMissing, fmt.Sprintf("no credentials stored for %s: %v", target.Email(), err))
+		return result, nil
+	}
+
+	if err := checkCredentialsDecrypt(creds); errors.Is(err, domain.ErrPassphraseRequired) {
+		result.addIssue(IssueCredentialsUndecryptable, fmt.Sprintf("credentials for %s are passphrase-protected and cannot be written to Claude config", target.Email()))
+		return result, nil
+	} else if err != nil {
+		result.addIssue(IssueCredentialsUndecryptable, fmt.Sprintf("credentials for %s cannot be decrypted", target.Email()))
+		return result, nil
+	}
+
+	if expiresAt, ok := creds.ExpiresAt(); ok && !expiresAt.After(s.resolver.now()) {
+		result.addIssue(IssueCredentialsExpired, fmt.Sprintf("session for %s expired at %s", target.Email(), expiresAt.Format(time.RFC3339)))
+	}
+
+	return result, nil
+}
+
+// targetIssueKind classifies a failure to resolve the target. Missing accounts and
+// requests that cannot be satisfied are reported as such; any other wrapped error is a
+// failure to read ccx storage.
+func targetIssueKind(err error) PreflightIssueKind {
+	switch {
+	case errors.Is(err, domain.ErrAccountNotFound), errors.Is(err, ErrDefaultAccountMissing):
+		return IssueAccountNotFound
+	case errors.Is(err, ErrNoRecentSwitch), errors.Is(err, ErrNoDefaultAccount),
+		errors.Is(err, ErrNoOtherAccount), errors.Is(err, ErrAccountArchived),
+		errors.Unwrap(err) == nil:
+		return IssueInvalidInput
+	default:
+		return IssueStorageUnreadable
+	}
+}
+
+func (r *PreflightResult) addIssue(kind PreflightIssueKind, message string) {
+	r.Issues = append(r.Issues, PreflightIssue{Kind: kind, Message: message})
+}
@@ -0,0 +1,176 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// issueKinds extracts the kinds of a preflight result for comparison
+func issueKinds(result *usecases.PreflightResult) []usecases.PreflightIssueKind {
+	kinds := make([]usecases.PreflightIssueKind, len(result.Issues))
+	for i, issue := range result.Issues {
+		kinds[i] = issue.Kind
+	}
+	return kinds
+}
+
+// TestPreflightSwitchUseCase_Execute_AllClear tests a switch that would succeed
+func TestPreflightSwitchUseCase_Execute_AllClear(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	useCase := usecases.NewPreflightSwitchService(
+		setup.accountRepo,
+		setup.credentialStore,
+		setup.configManager,
+		setup.historyRepo,
+	)
+
+	result, err := useCase.Execute(context.Background(), usecases.SwitchAccountInput{Alias: "work"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if !result.OK() {
+		t.Errorf("Expected no issues, got %+v", result.Issues)
+	}
+	if result.Issues == nil {
+		t.Error("Issues should be an empty slice, not nil")
+	}
+	if result.Target == nil || result.Target.Email != testEmailWork {
+		t.Errorf("Expected target %s, got %+v", testEmailWork, result.Target)
+	}
+	if setup.configManager.currentAccount != setup.testAccounts["personal"] {
+		t.Error("Preflight must not change the current account")
+	}
+}
+
+// TestPreflightSwitchUseCase_Execute_MultipleIssues tests that all blocking conditions are reported together
+func TestPreflightSwitchUseCase_Execute_MultipleIssues(t *testing.T) {
+	now := time.Now()
+	setup := setupSwitchAccountTest()
+	setup.configManager.getErr = errors.New("config corrupted")
+
+	work := setup.testAccounts["work"]
+	expired, _ := domain.NewCredentials(work.ID(), []byte(`{"sessionKey":"key","sessionKeyExpiresAt":`+
+		strconv.FormatInt(now.Add(-time.Hour).Unix(), 10)+`}`))
+	_ = setup.credentialStore.Store(context.Background(), expired)
+
+	useCase := usecases.NewPreflightSwitchService(
+		setup.accountRepo,
+		setup.credentialStore,
+		setup.configManager,
+		setup.historyRepo,
+		usecases.WithSwitchClock(func() time.Time { return now }),
+	)
+
+	result, err := useCase.Execute(context.Background(), usecases.SwitchAccountInput{Alias: "work"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	kinds := issueKinds(result)
+	want := []usecases.PreflightIssueKind{usecases.IssueConfigUnreadable, usecases.IssueCredentialsExpired}
+	if len(kinds) != len(want) {
+		t.Fatalf("Issues = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("Issues[%d] = %s, want %s", i, kinds[i], want[i])
+		}
+	}
+	if result.OK() {
+		t.Error("OK() should be false when issues are present")
+	}
+}
+
+// TestPreflightSwitchUseCase_Execute_SingleIssues tests each individual blocking condition
+func TestPreflightSwitchUseCase_Execute_SingleIssues(t *testing.T) {
+	tests := []struct {
+		name  string
+		input usecases.SwitchAccountInput
+		setup func(t *testing.T, setup *switchAccountTestSetup)
+		want  usecases.PreflightIssueKind
+	}{
+		{
+			name:  "invalid input",
+			input: usecases.SwitchAccountInput{Alias: "work", Email: testEmailWork},
+			want:  usecases.IssueInvalidInput,
+		},
+		{
+			name:  "account not found",
+			input: usecases.SwitchAccountInput{Alias: "missing"},
+			want:  usecases.IssueAccountNotFound,
+		},
+		{
+			name:  "no account matches prefix",
+			input: usecases.SwitchAccountInput{Prefix: "zz"},
+			want:  usecases.IssueAccountNotFound,
+		},
+		{
+			name:  "archived account",
+			input: usecases.SwitchAccountInput{Alias: "work"},
+			setup: func(_ *testing.T, setup *switchAccountTestSetup) {
+				setup.testAccounts["work"].Archive(time.Now())
+			},
+			want: usecases.IssueInvalidInput,
+		},
+		{
+			name:  "no default account",
+			input: usecases.SwitchAccountInput{UseDefault: true},
+			want:  usecases.IssueInvalidInput,
+		},
+		{
+			name:  "unreadable account storage",
+			input: usecases.SwitchAccountInput{Alias: "work"},
+			setup: func(_ *testing.T, setup *switchAccountTestSetup) {
+				setup.accountRepo.findErr = errors.New("disk failure")
+			},
+			want: usecases.IssueStorageUnreadable,
+		},
+		{
+			name:  "missing credentials",
+			input: usecases.SwitchAccountInput{Alias: "work"},
+			setup: func(_ *testing.T, setup *switchAccountTestSetup) {
+				delete(setup.credentialStore.credentials, setup.testAccounts["work"].ID())
+			},
+			want: usecases.IssueCredentialsMissing,
+		},
+		{
+			name:  "undecryptable credentials",
+			input: usecases.SwitchAccountInput{Alias: "work"},
+			setup: func(t *testing.T, setup *switchAccountTestSetup) {
+				work := setup.testAccounts["work"]
+				setup.credentialStore.credentials[work.ID()] = undecryptableCredentials(t, work.ID())
+			},
+			want: usecases.IssueCredentialsUndecryptable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupSwitchAccountTest()
+			if tt.setup != nil {
+				tt.setup(t, setup)
+			}
+			useCase := usecases.NewPreflightSwitchService(
+				setup.accountRepo,
+				setup.credentialStore,
+				setup.configManager,
+				setup.historyRepo,
+			)
+
+			result, err := useCase.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute() error = %v, want nil", err)
+			}
+			kinds := issueKinds(result)
+			if len(kinds) != 1 || kinds[0] != tt.want {
+				t.Errorf("Issues = %v, want [%s]", kinds, tt.want)
+			}
+		})
+	}
+}
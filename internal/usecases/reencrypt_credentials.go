@@ -0,0 +1,205 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// ReencryptAllUseCase defines the interface for moving every account's credentials to a
+// new encryption scheme, such as from the legacy account-ID-derived key to envelope
+// encryption
+type ReencryptAllUseCase interface {
+	Execute(ctx context.Context, input ReencryptAllInput) (*MigrationResult, error)
+}
+
+// ReencryptAllInput contains the input data for re-encrypting all credentials
+type ReencryptAllInput struct {
+	NewScheme  domain.CredentialScheme // Scheme to move every account's credentials to
+	MasterKey  []byte                  // Key wrapping the data keys; required for domain.SchemeEnvelope
+	Passphrase []byte                  // Required to read passphrase-protected credentials
+}
+
+// ReencryptAllService implements the ReencryptAllUseCase
+type ReencryptAllService struct {
+	accounts    ports.AccountRepository
+	credentials ports.CredentialStore
+}
+
+// Ensure ReencryptAllService implements ReencryptAllUseCase at compile time
+var _ ReencryptAllUseCase = (*ReencryptAllService)(nil)
+
+// NewReencryptAllService creates a new ReencryptAllService
+func NewReencryptAllService(accounts ports.AccountRepository, credentials ports.CredentialStore) ReencryptAllUseCase {
+	return &ReencryptAllService{
+		accounts:    accounts,
+		credentials: credentials,
+	}
+}
+
+// Execute re-encrypts each account's credentials under the new scheme, one account at a
+// time. The report uses the migration statuses: migrated means re-encrypted, and skipped
+// means the credentials already used the scheme, which makes it safe to run again. Each
+// account's new credentials are read back before they are kept; if they don't match, the
+// original credentials are restored. A failing account does not stop the others. An error
+// is returned only for invalid input, if the accounts can't be listed, or if ctx is
+// cancelled, along with the accounts processed so far.
+func (s *ReencryptAllService) Execute(ctx context.Context, input ReencryptAllInput) (*MigrationResult, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	accounts, err := s.accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	result := &MigrationResult{
+		Accounts: make([]AccountMigration, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		// Check context before proceeding
+		if err := ctx.Err(); err != nil {
+			return result, fmt.Errorf("context cancelled: %w", err)
+		}
+
+		outcome := AccountMigration{Account: newAccountInfo(account)}
+		outcome.Status, outcome.Err = s.reencrypt(ctx, account.ID(), input)
+
+		switch outcome.Status {
+		case MigrationMigrated:
+			result.Migrated++
+		case MigrationSkipped:
+			result.Skipped++
+		case MigrationMissing:
+			result.Missing++
+		case MigrationFailed:
+			result.Failed++
+		}
+		result.Accounts = append(result.Accounts, outcome)
+	}
+
+	return result, nil
+}
+
+// validate checks that the input names a scheme and carries the key it needs
+func (input ReencryptAllInput) validate() error {
+	switch input.NewScheme {
+	case domain.SchemeAccountKey:
+		return nil
+	case domain.SchemeEnvelope:
+		if len(input.MasterKey) != domain.MasterKeySize {
+			return fmt.Errorf("master key must be %d bytes", domain.MasterKeySize)
+		}
+		return nil
+	case domain.SchemePassphrase:
+		// Switching writes the plaintext session to Claude config, which it can't do
+		// without the passphrase, so every account would become impossible to switch to
+		return errors.New("passphrase protection cannot be used at rest: switching could not decrypt the credentials")
+	default:
+		return fmt.Errorf("unknown credential scheme %q", input.NewScheme)
+	}
+}
+
+// reencrypt moves one account's credentials to the new scheme as a unit of work
+func (s *ReencryptAllService) reencrypt(ctx context.Context, id domain.AccountID, input ReencryptAllInput) (MigrationStatus, error) {
+	creds, err := s.credentials.Retrieve(ctx, id)
+	if errors.Is(err, domain.ErrCredentialsNotFound) {
+		return MigrationMissing, nil
+	}
+	if err != nil {
+		return MigrationFailed, fmt.Errorf("failed to read credentials: %w", err)
+	}
+	if creds.Scheme() == input.NewScheme {
+		return MigrationSkipped, nil
+	}
+
+	plaintext, err := decryptWithInput(creds, input)
+	if err != nil {
+		return MigrationFailed, fmt.Errorf("failed to decrypt credentials: %w", err)
+	}
+
+	var upgraded *domain.Credentials
+	switch input.NewScheme {
+	case domain.SchemeEnvelope:
+		upgraded, err = domain.NewCredentialsEnvelope(id, plaintext, input.MasterKey)
+	default:
+		upgraded, err = domain.NewCredentials(id, plaintext)
+	}
+	if err != nil {
+		return MigrationFailed, fmt.Errorf("failed to re-encrypt credentials: %w", err)
+	}
+
+	tx := NewTransaction()
+	err = tx.Do(ctx,
+		func(ctx context.Context) error { return s.credentials.Store(ctx, upgraded) },
+		func(ctx context.Context) error { return s.credentials.Store(ctx, creds) },
+	)
+	if err != nil {
+		return MigrationFailed, fmt.Errorf("failed to write credentials: %w", err)
+	}
+
+	// The store may seal credentials its own way, so check what it actually kept
+	err = tx.Do(ctx, func(ctx context.Context) error { return s.verify(ctx, id, plaintext, input) }, nil)
+	if err != nil {
+		return MigrationFailed, fmt.Errorf("failed to verify re-encrypted credentials: %w", err)
+	}
+	tx.Commit()
+
+	return MigrationMigrated, nil
+}
+
+// verify reads back the account's credentials and checks that they use the new scheme
+// and decrypt to plaintext
+func (s *ReencryptAllService) verify(ctx context.Context, id domain.AccountID, plaintext []byte, input ReencryptAllInput) error {
+	stored, err := s.credentials.Retrieve(ctx, id)
+	if err != nil {
+		return err
+	}
+	if stored.Scheme() != input.NewScheme {
+		return fmt.Errorf("store kept the credentials under the %s scheme", stored.Scheme())
+	}
+	data, err := decryptWithInput(stored, input)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(data, plaintext) {
+		return errors.New("credentials read back differ from the original")
+	}
+	return nil
+}
+
+// decryptWithInput decrypts credentials under any scheme using the keys in input.
+// Envelope-encrypted credentials are unwrapped with the input's master key unless the
+// store already did.
+func decryptWithInput(creds *domain.Credentials, input ReencryptAllInput) ([]byte, error) {
+	switch creds.Scheme() {
+	case domain.SchemePassphrase:
+		if len(input.Passphrase) == 0 {
+			return nil, domain.ErrPassphraseRequired
+		}
+		return creds.DecryptWithPassphrase(input.Passphrase)
+	case domain.SchemeEnvelope:
+		data, err := creds.Decrypt()
+		if errors.Is(err, domain.ErrMasterKeyRequired) && input.MasterKey != nil {
+			if err := creds.Unwrap(input.MasterKey); err != nil {
+				return nil, err
+			}
+			return creds.Decrypt()
+		}
+		return data, err
+	default:
+		return creds.Decrypt()
+	}
+}
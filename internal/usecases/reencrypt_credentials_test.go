@@ -0,0 +1,115 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// TestReencryptAllUseCase_Execute tests upgrading legacy credentials to envelope
+// encryption and that a second run skips them
+func TestReencryptAllUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	store := newBackupTestStore()
+	personal := store.addAccount(testEmailPersonal, "personal", "key-personal")
+	store.addAccount(testEmailWork, "work", "key-work")
+	bare, _ := domain.NewAccount(testEmailTest, "test", "uuid-test")
+	_ = store.accountRepo.Save(ctx, bare)
+
+	masterKey, _ := domain.GenerateMasterKey()
+	input := usecases.ReencryptAllInput{NewScheme: domain.SchemeEnvelope, MasterKey: masterKey}
+	useCase := usecases.NewReencryptAllService(store.accountRepo, store.credentialStore)
+
+	result, err := useCase.Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.Migrated != 2 || result.Missing != 1 || result.Failed != 0 {
+		t.Fatalf("result = %+v, want 2 re-encrypted and 1 missing", result)
+	}
+	creds, _ := store.credentialStore.Retrieve(ctx, personal.ID())
+	if creds.Scheme() != domain.SchemeEnvelope {
+		t.Errorf("Scheme() = %s, want envelope", creds.Scheme())
+	}
+	if got := sessionKeyFor(t, store, personal.ID()); got != `{"sessionKey":"key-personal"}` {
+		t.Errorf("re-encrypted credentials = %s, want the original session", got)
+	}
+
+	again, err := useCase.Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("second Execute() error = %v, want nil", err)
+	}
+	if again.Skipped != 2 || again.Migrated != 0 {
+		t.Errorf("second result = %+v, want both accounts skipped", again)
+	}
+}
+
+// TestReencryptAllUseCase_Execute_FromPassphrase tests moving passphrase-protected
+// credentials, which can't be switched to, back to a scheme that can
+func TestReencryptAllUseCase_Execute_FromPassphrase(t *testing.T) {
+	ctx := context.Background()
+	store := newBackupTestStore()
+	work := store.addAccount(testEmailWork, "work", "key-work")
+	protected, _ := domain.NewCredentialsWithPassphrase(work.ID(), []byte(`{"sessionKey":"key-work"}`), []byte(testBackupPassphrase))
+	_ = store.credentialStore.Store(ctx, protected)
+
+	result, err := usecases.NewReencryptAllService(store.accountRepo, store.credentialStore).
+		Execute(ctx, usecases.ReencryptAllInput{NewScheme: domain.SchemeAccountKey, Passphrase: []byte(testBackupPassphrase)})
+	if err != nil || result.Migrated != 1 {
+		t.Fatalf("Execute() = %+v, %v, want 1 re-encrypted", result, err)
+	}
+
+	creds, _ := store.credentialStore.Retrieve(ctx, work.ID())
+	if creds.Scheme() != domain.SchemeAccountKey || sessionKeyFor(t, store, work.ID()) != `{"sessionKey":"key-work"}` {
+		t.Errorf("stored credentials use %s, want %s with the original session", creds.Scheme(), domain.SchemeAccountKey)
+	}
+}
+
+// TestReencryptAllUseCase_Execute_PartialFailure tests that a failing account keeps its
+// credentials and does not stop the others
+func TestReencryptAllUseCase_Execute_PartialFailure(t *testing.T) {
+	ctx := context.Background()
+	store := newBackupTestStore()
+	personal := store.addAccount(testEmailPersonal, "personal", "key-personal")
+	work := store.addAccount(testEmailWork, "work", "key-work")
+	failing := &failingCredentialStore{extendedMockCredentialStore: store.credentialStore, failFor: work.ID()}
+
+	masterKey, _ := domain.GenerateMasterKey()
+	result, err := usecases.NewReencryptAllService(store.accountRepo, failing).
+		Execute(ctx, usecases.ReencryptAllInput{NewScheme: domain.SchemeEnvelope, MasterKey: masterKey})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.Migrated != 1 || result.Failed != 1 {
+		t.Fatalf("result = %+v, want 1 re-encrypted and 1 failed", result)
+	}
+
+	creds, _ := store.credentialStore.Retrieve(ctx, work.ID())
+	if creds.Scheme() != domain.SchemeAccountKey || sessionKeyFor(t, store, work.ID()) != `{"sessionKey":"key-work"}` {
+		t.Error("failed account's credentials should be left as they were")
+	}
+	if creds, _ := store.credentialStore.Retrieve(ctx, personal.ID()); creds.Scheme() != domain.SchemeEnvelope {
+		t.Error("other account should still be re-encrypted")
+	}
+}
+
+// TestReencryptAllUseCase_Execute_InvalidInput tests that bad input changes nothing
+func TestReencryptAllUseCase_Execute_InvalidInput(t *testing.T) {
+	store := newBackupTestStore()
+	store.addAccount(testEmailWork, "work", "key-work")
+	useCase := usecases.NewReencryptAllService(store.accountRepo, store.credentialStore)
+
+	for _, input := range []usecases.ReencryptAllInput{
+		{NewScheme: "rot13"},
+		{NewScheme: domain.SchemeEnvelope, MasterKey: []byte("short")},
+		{NewScheme: domain.SchemePassphrase},
+		// Passphrase-protected credentials can't be switched to, so they are no target
+		{NewScheme: domain.SchemePassphrase, Passphrase: []byte(testBackupPassphrase)},
+	} {
+		if _, err := useCase.Execute(context.Background(), input); err == nil {
+			t.Errorf("Execute(%s) error = nil, want error", input.NewScheme)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// RekeyAccountUseCase defines the interface for giving an account a new ID
+type RekeyAccountUseCase interface {
+	Execute(ctx context.Context, input RekeyAccountInput) (*RekeyAccountResult, error)
+}
+
+// RekeyAccountInput contains the input data for re-keying an account
+type RekeyAccountInput struct {
+	AccountID    string // Account ID to replace
+	NewAccountID string // ID to move the account to; generated if empty
+}
+
+// RekeyAccountResult contains the result of a re-key operation
+type RekeyAccountResult struct {
+	Account AccountInfo `json:"account"` // Account under its new ID
+	OldID   string      `json:"old_id"`  // ID before the re-key
+	NewID   string      `json:"new_id"`  // ID after the re-key
+}
+
+// RekeyAccountService implements the RekeyAccountUseCase
+type RekeyAccountService struct {
+	accounts    ports.AccountRepository
+	credentials ports.CredentialStore
+	settings    ports.SettingsRepository
+	profiles    ports.ProfileRepository
+}
+
+// Ensure RekeyAccountService implements RekeyAccountUseCase at compile time
+var _ RekeyAccountUseCase = (*RekeyAccountService)(nil)
+
+// RekeyAccountOption configures optional RekeyAccountService behavior
+type RekeyAccountOption func(*RekeyAccountService)
+
+// WithRekeySettings gives RekeyAccountService access to ccx settings so the default and
+// current account pointers follow the account to its new ID
+func WithRekeySettings(settings ports.SettingsRepository) RekeyAccountOption {
+	return func(s *RekeyAccountService) {
+		s.settings = settings
+	}
+}
+
+// WithRekeyProfiles gives RekeyAccountService access to profiles so profiles pointing
+// at the old ID follow the account
+func WithRekeyProfiles(profiles ports.ProfileRepository) RekeyAccountOption {
+	return func(s *RekeyAccountService) {
+		s.profiles = profiles
+	}
+}
+
+// NewRekeyAccountService creates a new RekeyAccountService
+func NewRekeyAccountService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+	opts ...RekeyAccountOption,
+) RekeyAccountUseCase {
+	s := &RekeyAccountService{
+		accounts:    accounts,
+		credentials: credentials,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Execute moves the account to a new ID, such as to resolve a collision with an account
+// imported from another machine. Its credentials are copied under the new ID, the
+// account record is replaced, and the old credentials are deleted, as one unit of work:
+// if any step fails, the completed steps are undone. An account without stored
+// credentials is re-keyed on its own. Passphrase-protected credentials can't be
+// re-encrypted for the new ID and return domain.ErrPassphraseRequired.
+func (s *RekeyAccountService) Execute(ctx context.Context, input RekeyAccountInput) (*RekeyAccountResult, error) {
+	if input.AccountID == "" {
+		return nil, errors.New("account ID is required")
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	account, err := s.accounts.FindByID(ctx, domain.AccountID(input.AccountID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+
+	newID := domain.AccountID(input.NewAccountID)
+	if newID == "" {
+		newID = domain.GenerateAccountID()
+	}
+	if err := domain.ValidateAccountID(newID); err != nil {
+		return nil, err
+	}
+	if newID == account.ID() {
+		return nil, errors.New("new account ID must differ from the current one")
+	}
+	if err := s.checkIDFree(ctx, newID); err != nil {
+		return nil, err
+	}
+
+	rekeyed, err := account.Rekey(newID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldCreds, err := s.credentials.Retrieve(ctx, account.ID())
+	if err != nil && !errors.Is(err, domain.ErrCredentialsNotFound) {
+		return nil, fmt.Errorf("failed to read credentials: %w", err)
+	}
+	var newCreds *domain.Credentials
+	if oldCreds != nil {
+		newCreds, err = oldCreds.Rekey(newID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-key credentials: %w", err)
+		}
+	}
+
+	if err := s.performRekey(ctx, account, rekeyed, oldCreds, newCreds); err != nil {
+		return nil, err
+	}
+
+	return &RekeyAccountResult{
+		Account: newAccountInfo(rekeyed),
+		OldID:   string(account.ID()),
+		NewID:   string(newID),
+	}, nil
+}
+
+// checkIDFree returns domain.ErrDuplicateAccount if an account or credentials already
+// use id
+func (s *RekeyAccountService) checkIDFree(ctx context.Context, id domain.AccountID) error {
+	_, err := s.accounts.FindByID(ctx, id)
+	switch {
+	case err == nil:
+		return fmt.Errorf("%w: ID %s is already used by another account", domain.ErrDuplicateAccount, id)
+	case !errors.Is(err, domain.ErrAccountNotFound):
+		return fmt.Errorf("failed to check for existing account: %w", err)
+	}
+
+	_, err = s.credentials.Retrieve(ctx, id)
+	switch {
+	case err == nil:
+		return fmt.Errorf("%w: credentials are already stored under ID %s", domain.ErrDuplicateAccount, id)
+	case !errors.Is(err, domain.ErrCredentialsNotFound):
+		return fmt.Errorf("failed to check for existing credentials: %w", err)
+	}
+	return nil
+}
+
+// performRekey replaces account with rekeyed and moves the credentials and the pointers
+// to it as one unit of work. The old record is deleted before the new one is saved so
+// repositories that enforce unique emails and aliases never see both.
+func (s *RekeyAccountService) performRekey(ctx context.Context, account, rekeyed *domain.Account, oldCreds, newCreds *domain.Credentials) error {
+	tx := NewTransaction()
+
+	if newCreds != nil {
+		err := tx.Do(ctx,
+			func(ctx context.Context) error { return s.credentials.Store(ctx, newCreds) },
+			func(ctx context.Context) error { return s.credentials.Delete(ctx, rekeyed.ID()) },
+		)
+		if err != nil {
+			return fmt.Errorf("failed to store credentials under the new ID: %w", err)
+		}
+	}
+
+	err := tx.Do(ctx,
+		func(ctx context.Context) error { return s.accounts.Delete(ctx, account.ID()) },
+		func(ctx context.Context) error { return s.accounts.Save(ctx, account) },
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove the old account record: %w", err)
+	}
+
+	err = tx.Do(ctx,
+		func(ctx context.Context) error { return s.accounts.Save(ctx, rekeyed) },
+		func(ctx context.Context) error { return s.accounts.Delete(ctx, rekeyed.ID()) },
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save account: %w", err)
+	}
+
+	if err := s.repointSettings(ctx, tx, account.ID(), rekeyed.ID()); err != nil {
+		return err
+	}
+	if err := s.repointProfiles(ctx, tx, account.ID(), rekeyed.ID()); err != nil {
+		return err
+	}
+
+	if oldCreds != nil {
+		err = tx.Do(ctx,
+			func(ctx context.Context) error { return s.credentials.Delete(ctx, account.ID()) },
+			func(ctx context.Context) error { return s.credentials.Store(ctx, oldCreds) },
+		)
+		if err != nil {
+			return fmt.Errorf("failed to delete credentials under the old ID: %w", err)
+		}
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// repointSettings moves the default and current account pointers from oldID to newID
+// within tx
+func (s *RekeyAccountService) repointSettings(ctx context.Context, tx *Transaction, oldID, newID domain.AccountID) error {
+	if s.settings == nil {
+		return nil
+	}
+
+	settings, err := s.settings.LoadSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+	if settings.DefaultAccountID() != oldID && settings.CurrentAccountID() != oldID {
+		return nil
+	}
+
+	repointed := settings.Clone()
+	if repointed.DefaultAccountID() == oldID {
+		repointed.SetDefaultAccountID(newID)
+	}
+	if repointed.CurrentAccountID() == oldID {
+		repointed.SetCurrentAccountID(newID)
+	}
+	err = tx.Do(ctx,
+		func(ctx context.Context) error { return s.settings.SaveSettings(ctx, repointed) },
+		func(ctx context.Context) error { return s.settings.SaveSettings(ctx, settings) },
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update settings: %w", err)
+	}
+	return nil
+}
+
+// repointProfiles moves the profiles pointing at oldID to newID within tx
+func (s *RekeyAccountService) repointProfiles(ctx context.Context, tx *Transaction, oldID, newID domain.AccountID) error {
+	if s.profiles == nil {
+		return nil
+	}
+
+	profiles, err := s.profiles.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	for _, profile := range profiles {
+		if profile.AccountID() != oldID {
+			continue
+		}
+		repointed, err := domain.NewProfile(profile.Name(), newID)
+		if err != nil {
+			return err
+		}
+		err = tx.Do(ctx,
+			func(ctx context.Context) error { return s.profiles.Save(ctx, repointed) },
+			func(ctx context.Context) error { return s.profiles.Save(ctx, profile) },
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update profile %s: %w", profile.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// TestRekeyAccountUseCase_Execute tests that the account, its credentials, and the
+// pointers to it move to the new ID
+func TestRekeyAccountUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	settingsRepo := newMockSettingsRepository()
+	profileRepo := newMockProfileRepository()
+	work := setup.testAccounts["work"]
+	settingsRepo.settings.SetDefaultAccountID(work.ID())
+	seedProfile(profileRepo, "client", work)
+
+	useCase := usecases.NewRekeyAccountService(setup.accountRepo, setup.credentialStore,
+		usecases.WithRekeySettings(settingsRepo), usecases.WithRekeyProfiles(profileRepo))
+
+	result, err := useCase.Execute(ctx, usecases.RekeyAccountInput{
+		AccountID:    string(work.ID()),
+		NewAccountID: "rekeyed1",
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.OldID != string(work.ID()) || result.NewID != "rekeyed1" {
+		t.Errorf("result IDs = %s -> %s, want %s -> rekeyed1", result.OldID, result.NewID, work.ID())
+	}
+
+	if _, err := setup.accountRepo.FindByID(ctx, work.ID()); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("old account lookup error = %v, want ErrAccountNotFound", err)
+	}
+	saved, err := setup.accountRepo.FindByID(ctx, "rekeyed1")
+	if err != nil || saved.Email() != testEmailWork || saved.Alias() != "work" {
+		t.Errorf("new account = %v (err %v), want the work account", saved, err)
+	}
+
+	if _, ok := setup.credentialStore.credentials[work.ID()]; ok {
+		t.Error("credentials under the old ID were not deleted")
+	}
+	creds, ok := setup.credentialStore.credentials["rekeyed1"]
+	if !ok {
+		t.Fatal("credentials were not stored under the new ID")
+	}
+	if data, err := creds.Decrypt(); err != nil || string(data) != `{"sessionKey": "key-work"}` {
+		t.Errorf("new credentials decrypt to %s (err %v), want the work session", data, err)
+	}
+
+	if settingsRepo.settings.DefaultAccountID() != "rekeyed1" {
+		t.Errorf("default account = %s, want rekeyed1", settingsRepo.settings.DefaultAccountID())
+	}
+	if profile := profileRepo.profiles["client"]; profile.AccountID() != "rekeyed1" {
+		t.Errorf("profile account = %s, want rekeyed1", profile.AccountID())
+	}
+}
+
+// TestRekeyAccountUseCase_Execute_GeneratesID tests that an ID is generated when none is given
+func TestRekeyAccountUseCase_Execute_GeneratesID(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	work := setup.testAccounts["work"]
+	useCase := usecases.NewRekeyAccountService(setup.accountRepo, setup.credentialStore)
+
+	result, err := useCase.Execute(ctx, usecases.RekeyAccountInput{AccountID: string(work.ID())})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.NewID == "" || result.NewID == result.OldID {
+		t.Errorf("NewID = %q, want a fresh ID", result.NewID)
+	}
+	if _, err := setup.accountRepo.FindByID(ctx, domain.AccountID(result.NewID)); err != nil {
+		t.Errorf("new account lookup error = %v", err)
+	}
+}
+
+// TestRekeyAccountUseCase_Execute_InvalidID tests that a new ID outside the generated
+// format, such as one that would escape the credentials directory, is rejected
+func TestRekeyAccountUseCase_Execute_InvalidID(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	work := setup.testAccounts["work"]
+	useCase := usecases.NewRekeyAccountService(setup.accountRepo, setup.credentialStore)
+
+	_, err := useCase.Execute(ctx, usecases.RekeyAccountInput{
+		AccountID:    string(work.ID()),
+		NewAccountID: "../../escaped",
+	})
+	if !errors.Is(err, domain.ErrInvalidAccountID) {
+		t.Fatalf("Execute() error = %v, want ErrInvalidAccountID", err)
+	}
+	if _, err := setup.accountRepo.FindByID(ctx, work.ID()); err != nil {
+		t.Errorf("account lookup after rejected rekey error = %v, want it untouched", err)
+	}
+}
+
+// TestRekeyAccountUseCase_Execute_IDInUse tests that an ID held by another account or
+// by stray credentials is rejected
+func TestRekeyAccountUseCase_Execute_IDInUse(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	work := setup.testAccounts["work"]
+	useCase := usecases.NewRekeyAccountService(setup.accountRepo, setup.credentialStore)
+
+	input := usecases.RekeyAccountInput{
+		AccountID:    string(work.ID()),
+		NewAccountID: string(setup.testAccounts["personal"].ID()),
+	}
+	if _, err := useCase.Execute(ctx, input); !errors.Is(err, domain.ErrDuplicateAccount) {
+		t.Errorf("Execute() onto an account ID error = %v, want ErrDuplicateAccount", err)
+	}
+
+	stray, _ := domain.NewCredentials("stray123", []byte(`{"sessionKey":"stray"}`))
+	_ = setup.credentialStore.Store(ctx, stray)
+	input.NewAccountID = "stray123"
+	if _, err := useCase.Execute(ctx, input); !errors.Is(err, domain.ErrDuplicateAccount) {
+		t.Errorf("Execute() onto stored credentials error = %v, want ErrDuplicateAccount", err)
+	}
+
+	input.NewAccountID = string(work.ID())
+	if _, err := useCase.Execute(ctx, input); err == nil {
+		t.Error("Execute() onto its own ID error = nil, want error")
+	}
+}
+
+// TestRekeyAccountUseCase_Execute_RollsBack tests that a failure after the account has
+// moved leaves the account and its credentials under the old ID
+func TestRekeyAccountUseCase_Execute_RollsBack(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	settingsRepo := newMockSettingsRepository()
+	work := setup.testAccounts["work"]
+	settingsRepo.settings.SetDefaultAccountID(work.ID())
+	settingsRepo.saveErr = errors.New("disk full")
+	useCase := usecases.NewRekeyAccountService(setup.accountRepo, setup.credentialStore,
+		usecases.WithRekeySettings(settingsRepo))
+
+	_, err := useCase.Execute(ctx, usecases.RekeyAccountInput{
+		AccountID:    string(work.ID()),
+		NewAccountID: "rekeyed1",
+	})
+	if err == nil {
+		t.Fatal("Execute() error = nil, want error")
+	}
+
+	if _, ok := setup.accountRepo.accounts[work.ID()]; !ok {
+		t.Error("old account record was not restored")
+	}
+	if _, ok := setup.accountRepo.accounts["rekeyed1"]; ok {
+		t.Error("new account record was left behind")
+	}
+	if _, ok := setup.credentialStore.credentials[work.ID()]; !ok {
+		t.Error("credentials under the old ID were lost")
+	}
+	if _, ok := setup.credentialStore.credentials["rekeyed1"]; ok {
+		t.Error("credentials under the new ID were left behind")
+	}
+	if settingsRepo.settings.DefaultAccountID() != work.ID() {
+		t.Errorf("default account = %s, want %s", settingsRepo.settings.DefaultAccountID(), work.ID())
+	}
+}
+
+// TestRekeyAccountUseCase_Execute_PassphraseProtected tests that credentials that
+// can't be re-encrypted stop the re-key before anything changes
+func TestRekeyAccountUseCase_Execute_PassphraseProtected(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	work := setup.testAccounts["work"]
+	creds, _ := domain.NewCredentialsWithPassphrase(work.ID(), []byte(`{"sessionKey":"key"}`), []byte("hunter2"))
+	_ = setup.credentialStore.Store(ctx, creds)
+	useCase := usecases.NewRekeyAccountService(setup.accountRepo, setup.credentialStore)
+
+	_, err := useCase.Execute(ctx, usecases.RekeyAccountInput{AccountID: string(work.ID())})
+	if !errors.Is(err, domain.ErrPassphraseRequired) {
+		t.Errorf("Execute() error = %v, want ErrPassphraseRequired", err)
+	}
+	if _, err := setup.accountRepo.FindByID(ctx, work.ID()); err != nil {
+		t.Errorf("account lookup after failed re-key error = %v", err)
+	}
+}
@@ -6,11 +6,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/evanschultz/ccx/internal/domain"
 	"github.com/evanschultz/ccx/internal/ports"
 )
 
+// ErrRemovingCurrentAccount is returned, along with a populated result, when the
+// account to remove is the current account and ForceRemoveCurrent is not set
+var ErrRemovingCurrentAccount = errors.New("account is the current Claude account")
+
 // RemoveAccountUseCase defines the interface for removing an account from ccx
 type RemoveAccountUseCase interface {
 	Execute(ctx context.Context, input RemoveAccountInput) (*RemoveAccountResult, error)
@@ -18,14 +23,40 @@ type RemoveAccountUseCase interface {
 
 // RemoveAccountInput contains the input data for removing an account
 type RemoveAccountInput struct {
+	// Exactly one of these selects the account to remove
 	AccountID string // Account ID to remove
+	Email     string // Email of the account to remove
+	Alias     string // Alias of the account to remove
+	Index     int    // Position of the account in the list (1-based)
+
+	DryRun bool // Validate and report the removal without deleting anything
+	// Archive keeps the account and its credentials but hides them from listings and
+	// switching until UnarchiveUseCase restores them. Profiles pointing at the account
+	// are kept too.
+	Archive bool
+	// ForceRemoveCurrent allows removing the current account, which leaves Claude
+	// with no logged-in account
+	ForceRemoveCurrent bool
+	// ForceWithoutBackup allows deleting an account whose credentials can't be read,
+	// such as corrupt ones. They can't be backed up, so if a later step fails they are
+	// lost rather than restored. Ignored with Archive.
+	ForceWithoutBackup bool
+	// Reason optionally says why the account was removed; it is kept in the account's
+	// tombstone. Ignored with Archive.
+	Reason string
 }
 
 // RemoveAccountResult contains the result of a remove operation
 type RemoveAccountResult struct {
-	RemovedAccount    AccountInfo // Information about the removed account
-	WasCurrentAccount bool        // True if the removed account was the current account
-	WasLastAccount    bool        // True if this was the last account in the system
+	RemovedAccount    AccountInfo `json:"removed_account"`     // Information about the removed account
+	WasCurrentAccount bool        `json:"was_current_account"` // True if the removed account was the current account
+	WasLastAccount    bool        `json:"was_last_account"`    // True if this was the last account in the system
+	WasDefaultAccount bool        `json:"was_default_account"` // True if the removed account was the default, which is now cleared
+	DryRun            bool        `json:"dry_run"`             // True if nothing was removed because DryRun was requested
+	Archived          bool        `json:"archived"`            // True if the account was archived rather than deleted
+	// RemovedProfiles names the profiles that pointed at the account and were deleted
+	// with it, sorted by name. Empty unless profiles are configured.
+	RemovedProfiles []string `json:"removed_profiles"`
 }
 
 // RemoveAccountService implements the RemoveAccountUseCase
@@ -34,73 +65,187 @@ type RemoveAccountService struct {
 	credentials ports.CredentialStore
 	config      ports.ConfigManager
 	history     ports.HistoryRepository
+	settings    ports.SettingsRepository
+	profiles    ports.ProfileRepository
+	tombstones  ports.TombstoneRepository
+	resolver    *AccountResolver
+	events      EventSink
+	now         func() time.Time
 }
 
 // Ensure RemoveAccountService implements RemoveAccountUseCase at compile time
 var _ RemoveAccountUseCase = (*RemoveAccountService)(nil)
 
+// RemoveAccountOption configures optional RemoveAccountService behavior
+type RemoveAccountOption func(*RemoveAccountService)
+
+// WithRemoveSettings gives RemoveAccountService access to ccx settings so removing the
+// default account also clears the default, and removing the account ccx last switched
+// to clears its current-account pointer
+func WithRemoveSettings(settings ports.SettingsRepository) RemoveAccountOption {
+	return func(s *RemoveAccountService) {
+		s.settings = settings
+	}
+}
+
+// WithRemoveProfiles gives RemoveAccountService access to profiles so profiles pointing
+// at the removed account are deleted with it rather than left orphaned
+func WithRemoveProfiles(profiles ports.ProfileRepository) RemoveAccountOption {
+	return func(s *RemoveAccountService) {
+		s.profiles = profiles
+	}
+}
+
+// WithRemoveTombstones records a tombstone for every deleted account, keeping its
+// email, alias, UUID, removal time, and reason after the account itself is gone
+func WithRemoveTombstones(tombstones ports.TombstoneRepository) RemoveAccountOption {
+	return func(s *RemoveAccountService) {
+		s.tombstones = tombstones
+	}
+}
+
+// WithRemoveEvents reports removed accounts and warnings to sink
+func WithRemoveEvents(sink EventSink) RemoveAccountOption {
+	return func(s *RemoveAccountService) {
+		s.events = sink
+	}
+}
+
 // NewRemoveAccountService creates a new RemoveAccountService
 func NewRemoveAccountService(
 	accounts ports.AccountRepository,
 	credentials ports.CredentialStore,
 	config ports.ConfigManager,
 	history ports.HistoryRepository,
+	opts ...RemoveAccountOption,
 ) RemoveAccountUseCase {
-	return &RemoveAccountService{
+	s := &RemoveAccountService{
 		accounts:    accounts,
 		credentials: credentials,
 		config:      config,
 		history:     history,
+		resolver:    NewAccountResolver(accounts),
+		events:      NopEventSink{},
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// Execute removes an account from ccx, including its credentials and configuration
+// Execute removes an account from ccx, including its credentials and configuration.
+// With Archive set the account and its credentials are kept and only marked archived;
+// the current and default account pointers are still cleared. With DryRun set it
+// performs the same lookups and returns the same result, but deletes nothing;
+// WasCurrentAccount tells the caller whether the real removal needs confirming.
+// Removing the current account requires ForceRemoveCurrent; without it, nothing is
+// removed and the result is returned with ErrRemovingCurrentAccount so the caller can
+// ask for confirmation and retry. An unreadable Claude config stops the removal, since
+// the current account can't be ruled out. Deleting an account whose credentials can't
+// be read for backup requires ForceWithoutBackup.
 func (s *RemoveAccountService) Execute(ctx context.Context, input RemoveAccountInput) (*RemoveAccountResult, error) {
 	if err := s.validateInput(ctx, input); err != nil {
 		return nil, err
 	}
 
-	accountID := domain.AccountID(input.AccountID)
-	account, err := s.accounts.FindByID(ctx, accountID)
+	account, err := s.resolver.Resolve(ctx, input.selector())
 	if err != nil {
 		return nil, fmt.Errorf("failed to find account: %w", err)
 	}
 
-	metadata, err := s.getRemovalMetadata(ctx, account)
-	if err != nil {
-		return nil, err
+	if input.Archive && account.Archived() {
+		return nil, fmt.Errorf("%w: %s", ErrAccountArchived, account.Email())
 	}
 
-	if err := s.performRemoval(ctx, account, metadata); err != nil {
+	var tombstone *domain.Tombstone
+	if !input.Archive {
+		tombstone, err = domain.NewTombstone(account, s.now(), input.Reason)
+		if err != nil {
+			return nil, fmt.Errorf("invalid removal reason: %w", err)
+		}
+	}
+
+	metadata, err := s.getRemovalMetadata(ctx, account, input.Archive)
+	if err != nil {
 		return nil, err
 	}
 
-	return &RemoveAccountResult{
+	result := &RemoveAccountResult{
 		RemovedAccount:    metadata.accountInfo,
 		WasCurrentAccount: metadata.isCurrentAccount,
 		WasLastAccount:    metadata.isLastAccount,
-	}, nil
+		WasDefaultAccount: metadata.isDefaultAccount,
+		DryRun:            input.DryRun,
+		Archived:          input.Archive,
+		RemovedProfiles:   make([]string, 0, len(metadata.profiles)),
+	}
+	for _, profile := range metadata.profiles {
+		result.RemovedProfiles = append(result.RemovedProfiles, profile.Name())
+	}
+
+	// Credentials that can't be read can't be restored if a later step fails
+	if !input.Archive && !input.ForceWithoutBackup && metadata.credentialsErr != nil &&
+		!errors.Is(metadata.credentialsErr, domain.ErrCredentialsNotFound) {
+		return nil, fmt.Errorf("failed to back up credentials for %s: %w; force the removal to delete them anyway", account.Email(), metadata.credentialsErr)
+	}
+
+	if input.DryRun {
+		// Deleting missing credentials is the one step known to fail up front
+		if !input.Archive && errors.Is(metadata.credentialsErr, domain.ErrCredentialsNotFound) {
+			return nil, fmt.Errorf("failed to delete credentials: %w", metadata.credentialsErr)
+		}
+		return result, nil
+	}
+
+	if metadata.isCurrentAccount && !input.ForceRemoveCurrent {
+		return result, fmt.Errorf("%w: %s", ErrRemovingCurrentAccount, account.Email())
+	}
+
+	if err := s.performRemoval(ctx, account, metadata, input.Archive); err != nil {
+		return nil, err
+	}
+	if tombstone != nil {
+		s.recordTombstone(ctx, tombstone)
+	}
+
+	s.events.OnAccountRemoved(metadata.accountInfo)
+
+	return result, nil
 }
 
 type removalMetadata struct {
 	accountInfo       AccountInfo
+	currentAccount    *domain.Account
 	isCurrentAccount  bool
 	isLastAccount     bool
 	backupCredentials *domain.Credentials
+	credentialsErr    error // Why backupCredentials is nil
+	isDefaultAccount  bool
+	settings          *domain.Settings  // Loaded settings if they point at the account, else nil
+	profiles          []*domain.Profile // Profiles pointing at the account, sorted by name
 }
 
 func (s *RemoveAccountService) validateInput(ctx context.Context, input RemoveAccountInput) error {
-	if input.AccountID == "" {
-		return errors.New("account ID is required")
+	if err := input.selector().Validate(); err != nil {
+		return err
 	}
 	return ctx.Err()
 }
 
-func (s *RemoveAccountService) getRemovalMetadata(ctx context.Context, account *domain.Account) (*removalMetadata, error) {
-	// Get current account to check if we're removing it
-	currentAccount, _ := s.config.GetCurrentAccount(ctx)
-	isCurrentAccount := currentAccount != nil && currentAccount.ID() == account.ID()
+// selector returns the identifiers in input that name the account to remove
+func (input RemoveAccountInput) selector() AccountSelector {
+	return AccountSelector{
+		AccountID: input.AccountID,
+		Email:     input.Email,
+		Alias:     input.Alias,
+		Index:     input.Index,
+	}
+}
 
+// getRemovalMetadata gathers what removing account affects. Archiving keeps profiles,
+// so none are collected for it.
+func (s *RemoveAccountService) getRemovalMetadata(ctx context.Context, account *domain.Account, archive bool) (*removalMetadata, error) {
 	// Check if this is the last account
 	allAccounts, err := s.accounts.List(ctx)
 	if err != nil {
@@ -108,66 +253,186 @@ func (s *RemoveAccountService) getRemovalMetadata(ctx context.Context, account *
 	}
 	isLastAccount := len(allAccounts) == 1
 
-	// Store account info for result before deletion
-	accountInfo := AccountInfo{
-		ID:        string(account.ID()),
-		Email:     string(account.Email()),
-		Alias:     account.Alias(),
-		UUID:      account.UUID(),
-		CreatedAt: account.CreatedAt(),
+	// Get current account to check if we're removing it. Claude config carries no ccx
+	// ID, so it is matched to a managed account by UUID and email.
+	currentAccount, err := s.config.GetCurrentAccount(ctx)
+	if err != nil && !errors.Is(err, domain.ErrNoCurrentAccount) {
+		return nil, fmt.Errorf("failed to get current account: %w", err)
 	}
+	managed := matchCurrentAccount(currentAccount, allAccounts)
+	isCurrentAccount := managed != nil && managed.ID() == account.ID()
+
+	// Store account info for result before deletion
+	accountInfo := newAccountInfo(account)
 
 	// Backup credentials before deletion (for rollback)
-	var backupCredentials *domain.Credentials
-	if creds, err := s.credentials.Retrieve(ctx, account.ID()); err == nil {
-		backupCredentials = creds
+	backupCredentials, credentialsErr := s.credentials.Retrieve(ctx, account.ID())
+
+	// Load settings if they point at the account, so the pointers can be cleared with it
+	var settings *domain.Settings
+	isDefaultAccount := false
+	if s.settings != nil {
+		loaded, err := s.settings.LoadSettings(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load settings: %w", err)
+		}
+		isDefaultAccount = loaded.DefaultAccountID() == account.ID()
+		if isDefaultAccount || loaded.CurrentAccountID() == account.ID() {
+			settings = loaded
+		}
+	}
+
+	// Find the profiles that would be orphaned by the removal
+	var profiles []*domain.Profile
+	if s.profiles != nil && !archive {
+		all, err := s.profiles.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list profiles: %w", err)
+		}
+		for _, profile := range all {
+			if profile.AccountID() == account.ID() {
+				profiles = append(profiles, profile)
+			}
+		}
 	}
 
 	return &removalMetadata{
 		accountInfo:       accountInfo,
+		currentAccount:    currentAccount,
 		isCurrentAccount:  isCurrentAccount,
 		isLastAccount:     isLastAccount,
 		backupCredentials: backupCredentials,
+		credentialsErr:    credentialsErr,
+		isDefaultAccount:  isDefaultAccount,
+		settings:          settings,
+		profiles:          profiles,
 	}, nil
 }
 
-func (s *RemoveAccountService) performRemoval(ctx context.Context, account *domain.Account, metadata *removalMetadata) error {
-	// Delete credentials first (critical for security)
-	if err := s.credentials.Delete(ctx, account.ID()); err != nil {
-		return fmt.Errorf("failed to delete credentials: %w", err)
-	}
+// performRemoval deletes (or with archive set, archives) the account and clears the
+// pointers to it as one unit of work: if any step fails, the completed steps are undone
+// in reverse order.
+func (s *RemoveAccountService) performRemoval(ctx context.Context, account *domain.Account, metadata *removalMetadata, archive bool) error {
+	tx := NewTransaction()
 
-	// Delete account from repository
-	if err := s.accounts.Delete(ctx, account.ID()); err != nil {
-		s.rollbackCredentials(ctx, metadata.backupCredentials)
-		return fmt.Errorf("failed to delete account: %w", err)
+	remove := s.deleteAccount
+	if archive {
+		remove = s.archiveAccount
+	}
+	err := remove(ctx, tx, account, metadata)
+	if err != nil {
+		return err
 	}
 
 	// Clear current account if we're removing it
 	if metadata.isCurrentAccount {
-		if err := s.config.SetCurrentAccount(ctx, nil); err != nil {
-			s.rollbackFull(ctx, account, metadata.backupCredentials)
+		err = tx.Do(ctx,
+			func(ctx context.Context) error { return s.config.SetCurrentAccount(ctx, nil) },
+			func(ctx context.Context) error { return s.config.SetCurrentAccount(ctx, metadata.currentAccount) },
+		)
+		if err != nil {
 			return fmt.Errorf("failed to clear current account configuration: %w", err)
 		}
+	}
+
+	// Clear the default and current pointers that referenced the removed account
+	if metadata.settings != nil {
+		cleared := metadata.settings.Clone()
+		if cleared.DefaultAccountID() == account.ID() {
+			cleared.SetDefaultAccountID("")
+		}
+		if cleared.CurrentAccountID() == account.ID() {
+			cleared.SetCurrentAccountID("")
+		}
+		err = tx.Do(ctx,
+			func(ctx context.Context) error { return s.settings.SaveSettings(ctx, cleared) },
+			func(ctx context.Context) error { return s.settings.SaveSettings(ctx, metadata.settings) },
+		)
+		if err != nil {
+			return fmt.Errorf("failed to clear settings pointing at the account: %w", err)
+		}
+	}
+
+	// Delete profiles that would otherwise point at nothing
+	for _, profile := range metadata.profiles {
+		err = tx.Do(ctx,
+			func(ctx context.Context) error { return s.profiles.Delete(ctx, profile.Name()) },
+			func(ctx context.Context) error { return s.profiles.Save(ctx, profile) },
+		)
+		if err != nil {
+			return fmt.Errorf("failed to delete profile %s: %w", profile.Name(), err)
+		}
+	}
+
+	tx.Commit()
+
+	if metadata.isCurrentAccount {
 		s.updateHistory(ctx)
 	}
 
 	return nil
 }
 
-func (s *RemoveAccountService) rollbackCredentials(ctx context.Context, backupCredentials *domain.Credentials) {
-	if backupCredentials != nil {
-		_ = s.credentials.Store(ctx, backupCredentials) // Best effort restore
+// archiveAccount marks the account archived within tx, keeping its credentials
+func (s *RemoveAccountService) archiveAccount(ctx context.Context, tx *Transaction, account *domain.Account, _ *removalMetadata) error {
+	// The repository may hand the same account to other callers, so archive a copy
+	archived := account.Clone()
+	archived.Archive(s.now())
+	err := tx.Do(ctx,
+		func(ctx context.Context) error { return s.accounts.Save(ctx, archived) },
+		func(ctx context.Context) error { return s.accounts.Save(ctx, account) },
+	)
+	if err != nil {
+		return fmt.Errorf("failed to archive account: %w", err)
 	}
+	return nil
 }
 
-func (s *RemoveAccountService) rollbackFull(ctx context.Context, account *domain.Account, backupCredentials *domain.Credentials) {
-	_ = s.accounts.Save(ctx, account) // Best effort restore
-	s.rollbackCredentials(ctx, backupCredentials)
+// deleteAccount deletes the account's credentials and then the account within tx
+func (s *RemoveAccountService) deleteAccount(ctx context.Context, tx *Transaction, account *domain.Account, metadata *removalMetadata) error {
+	// Delete credentials first (critical for security)
+	err := tx.Do(ctx,
+		func(ctx context.Context) error { return s.credentials.Delete(ctx, account.ID()) },
+		func(ctx context.Context) error {
+			if metadata.backupCredentials == nil {
+				return nil
+			}
+			return s.credentials.Store(ctx, metadata.backupCredentials)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete credentials: %w", err)
+	}
+
+	// Delete account from repository
+	err = tx.Do(ctx,
+		func(ctx context.Context) error { return s.accounts.Delete(ctx, account.ID()) },
+		func(ctx context.Context) error { return s.accounts.Save(ctx, account) },
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	return nil
+}
+
+// recordTombstone keeps a record of the deleted account. The account is already gone,
+// so a failure only warns.
+func (s *RemoveAccountService) recordTombstone(ctx context.Context, tombstone *domain.Tombstone) {
+	if s.tombstones == nil {
+		return
+	}
+	if err := s.tombstones.Add(ctx, tombstone); err != nil {
+		s.events.OnWarning(fmt.Errorf("failed to record tombstone for %s: %w", tombstone.Email(), err))
+	}
 }
 
 func (s *RemoveAccountService) updateHistory(ctx context.Context) {
-	if currentHistory, err := s.history.LoadHistory(ctx); err == nil {
-		_ = s.history.SaveHistory(ctx, currentHistory) // Best effort, don't fail operation
+	currentHistory, err := s.history.LoadHistory(ctx)
+	if err != nil {
+		return
+	}
+	// Best effort, don't fail operation
+	if err := s.history.SaveHistory(ctx, currentHistory); err != nil {
+		s.events.OnWarning(fmt.Errorf("failed to save history: %w", err))
 	}
 }
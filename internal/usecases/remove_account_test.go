@@ -3,6 +3,7 @@ package usecases_test
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 
 	"github.com/evanschultz/ccx/internal/domain"
@@ -144,6 +145,48 @@ func TestRemoveAccountUseCase_Execute_RemoveExistingAccount(t *testing.T) {
 	}
 }
 
+// TestRemoveAccountUseCase_Execute_ByAlias tests selecting the account to remove by alias
+func TestRemoveAccountUseCase_Execute_ByAlias(t *testing.T) {
+	setup := setupRemoveAccountTest()
+	ctx := context.Background()
+	work := setup.testAccounts["work"]
+
+	result, err := setup.useCase.Execute(ctx, usecases.RemoveAccountInput{Alias: "work"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.RemovedAccount.ID != string(work.ID()) {
+		t.Errorf("removed %s, want %s", result.RemovedAccount.ID, work.ID())
+	}
+	if _, err := setup.accountRepo.FindByID(ctx, work.ID()); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("FindByID() after removal error = %v, want ErrAccountNotFound", err)
+	}
+	if _, err := setup.credentialStore.Retrieve(ctx, work.ID()); !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Retrieve() after removal error = %v, want ErrCredentialsNotFound", err)
+	}
+}
+
+// TestRemoveAccountUseCase_Execute_MultipleIdentifiers tests that only one way of
+// selecting the account is accepted
+func TestRemoveAccountUseCase_Execute_MultipleIdentifiers(t *testing.T) {
+	setup := setupRemoveAccountTest()
+	work := setup.testAccounts["work"]
+
+	result, err := setup.useCase.Execute(context.Background(), usecases.RemoveAccountInput{
+		AccountID: string(work.ID()),
+		Email:     testEmailWork,
+	})
+	if err == nil || err.Error() != "multiple account identifiers provided; use only one" {
+		t.Errorf("Execute() error = %v, want multiple identifiers error", err)
+	}
+	if result != nil {
+		t.Errorf("Execute() result = %+v, want nil", result)
+	}
+	if _, err := setup.accountRepo.FindByID(context.Background(), work.ID()); err != nil {
+		t.Errorf("account was removed despite the error: %v", err)
+	}
+}
+
 // TestRemoveAccountUseCase_Execute_RemoveCurrentAccount tests removing the currently active account
 func TestRemoveAccountUseCase_Execute_RemoveCurrentAccount(t *testing.T) {
 	setup := setupRemoveAccountTest()
@@ -152,7 +195,8 @@ func TestRemoveAccountUseCase_Execute_RemoveCurrentAccount(t *testing.T) {
 	// Remove the current account (personal)
 	currentAccount := setup.configManager.currentAccount
 	input := usecases.RemoveAccountInput{
-		AccountID: string(currentAccount.ID()),
+		AccountID:          string(currentAccount.ID()),
+		ForceRemoveCurrent: true,
 	}
 
 	// Execute
@@ -177,6 +221,52 @@ func TestRemoveAccountUseCase_Execute_RemoveCurrentAccount(t *testing.T) {
 	}
 }
 
+// TestRemoveAccountUseCase_Execute_CurrentAccountNeedsForce tests that the current
+// account is only removed once the caller confirms with ForceRemoveCurrent
+func TestRemoveAccountUseCase_Execute_CurrentAccountNeedsForce(t *testing.T) {
+	setup := setupRemoveAccountTest()
+	ctx := context.Background()
+	current := setup.configManager.currentAccount
+
+	// A dry run reports what would happen, including that confirmation is needed
+	result, err := setup.useCase.Execute(ctx, usecases.RemoveAccountInput{
+		AccountID: string(current.ID()),
+		DryRun:    true,
+	})
+	if err != nil {
+		t.Fatalf("Execute(DryRun) error = %v, want nil", err)
+	}
+	if !result.WasCurrentAccount || !result.DryRun {
+		t.Errorf("Execute(DryRun) result = %+v, want a dry run of the current account", result)
+	}
+
+	result, err = setup.useCase.Execute(ctx, usecases.RemoveAccountInput{
+		AccountID: string(current.ID()),
+	})
+	if !errors.Is(err, usecases.ErrRemovingCurrentAccount) {
+		t.Fatalf("Execute() error = %v, want ErrRemovingCurrentAccount", err)
+	}
+	// The result is populated so the caller can describe what it is confirming
+	if result == nil || !result.WasCurrentAccount || result.RemovedAccount.Email != testEmailPersonal {
+		t.Errorf("Execute() result = %+v, want the current account", result)
+	}
+
+	if setup.configManager.currentAccount == nil {
+		t.Error("Current account should not be cleared without ForceRemoveCurrent")
+	}
+	if _, err := setup.accountRepo.FindByID(ctx, current.ID()); err != nil {
+		t.Errorf("Account should not be removed without ForceRemoveCurrent: %v", err)
+	}
+	if _, err := setup.credentialStore.Retrieve(ctx, current.ID()); err != nil {
+		t.Errorf("Credentials should not be removed without ForceRemoveCurrent: %v", err)
+	}
+
+	// Other accounts need no confirmation
+	if _, err := setup.useCase.Execute(ctx, usecases.RemoveAccountInput{AccountID: string(setup.testAccounts["work"].ID())}); err != nil {
+		t.Errorf("Execute() for a non-current account error = %v, want nil", err)
+	}
+}
+
 // TestRemoveAccountUseCase_Execute_RemoveNonExistentAccount tests removing an account that doesn't exist
 func TestRemoveAccountUseCase_Execute_RemoveNonExistentAccount(t *testing.T) {
 	setup := setupRemoveAccountTest()
@@ -197,6 +287,13 @@ func TestRemoveAccountUseCase_Execute_RemoveNonExistentAccount(t *testing.T) {
 	if result != nil {
 		t.Errorf("Expected nil result on error, got %+v", result)
 	}
+
+	// A near miss carries suggestions
+	_, err = setup.useCase.Execute(ctx, usecases.RemoveAccountInput{Alias: "persnal"})
+	var notFound *usecases.AccountNotFoundError
+	if !errors.As(err, &notFound) || !slices.Equal(notFound.Suggestions, []string{"personal"}) {
+		t.Errorf("Execute() error = %v, want a suggestion of personal", err)
+	}
 }
 
 // TestRemoveAccountUseCase_Execute_CredentialDeletionFailure tests when credential deletion fails
@@ -274,7 +371,8 @@ func TestRemoveAccountUseCase_Execute_RemoveLastAccount(t *testing.T) {
 
 	// Now try to remove the last account
 	input := usecases.RemoveAccountInput{
-		AccountID: string(setup.testAccounts["personal"].ID()),
+		AccountID:          string(setup.testAccounts["personal"].ID()),
+		ForceRemoveCurrent: true,
 	}
 
 	// Execute
@@ -342,6 +440,42 @@ func TestRemoveAccountUseCase_Execute_ContextCancellation(t *testing.T) {
 	}
 }
 
+// cancellingCredentialStore cancels the operation's context once credentials are deleted,
+// as a Ctrl-C between the removal's steps would
+type cancellingCredentialStore struct {
+	*extendedMockCredentialStore
+	cancel context.CancelFunc
+}
+
+func (m *cancellingCredentialStore) Delete(ctx context.Context, id domain.AccountID) error {
+	err := m.extendedMockCredentialStore.Delete(ctx, id)
+	m.cancel()
+	return err
+}
+
+// TestRemoveAccountUseCase_Execute_CancelledMidRemoval tests that cancelling after the
+// credentials are deleted restores them and keeps the account
+func TestRemoveAccountUseCase_Execute_CancelledMidRemoval(t *testing.T) {
+	setup := setupRemoveAccountTest()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	work := setup.testAccounts["work"]
+
+	credentialStore := &cancellingCredentialStore{extendedMockCredentialStore: setup.credentialStore, cancel: cancel}
+	useCase := usecases.NewRemoveAccountService(setup.accountRepo, credentialStore, setup.configManager, setup.historyRepo)
+
+	_, err := useCase.Execute(ctx, usecases.RemoveAccountInput{AccountID: string(work.ID())})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Execute() error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := setup.accountRepo.FindByID(context.Background(), work.ID()); err != nil {
+		t.Errorf("account lookup after cancelled removal error = %v, want the account kept", err)
+	}
+	if _, err := setup.credentialStore.Retrieve(context.Background(), work.ID()); err != nil {
+		t.Errorf("credentials lookup after cancelled removal error = %v, want them restored", err)
+	}
+}
+
 // TestRemoveAccountUseCase_Execute_ConfigUpdateFailure tests when clearing current account fails
 func TestRemoveAccountUseCase_Execute_ConfigUpdateFailure(t *testing.T) {
 	setup := setupRemoveAccountTest()
@@ -353,7 +487,8 @@ func TestRemoveAccountUseCase_Execute_ConfigUpdateFailure(t *testing.T) {
 	// Remove the current account
 	currentAccount := setup.configManager.currentAccount
 	input := usecases.RemoveAccountInput{
-		AccountID: string(currentAccount.ID()),
+		AccountID:          string(currentAccount.ID()),
+		ForceRemoveCurrent: true,
 	}
 
 	// Execute
@@ -375,6 +510,151 @@ func TestRemoveAccountUseCase_Execute_ConfigUpdateFailure(t *testing.T) {
 	}
 }
 
+// TestRemoveAccountUseCase_Execute_ConfigFailureAfterAccountDelete tests that a config
+// failure after the account is deleted leaves credentials, account, and config exactly
+// as they were
+func TestRemoveAccountUseCase_Execute_ConfigFailureAfterAccountDelete(t *testing.T) {
+	setup := setupRemoveAccountTest()
+	ctx := context.Background()
+
+	current := setup.configManager.currentAccount
+	credsBefore, _ := setup.credentialStore.Retrieve(ctx, current.ID())
+	accountsBefore, _ := setup.accountRepo.List(ctx)
+
+	setup.configManager.setErr = errors.New("config file locked")
+
+	_, err := setup.useCase.Execute(ctx, usecases.RemoveAccountInput{AccountID: string(current.ID()), ForceRemoveCurrent: true})
+	if err == nil {
+		t.Fatal("Expected error when config update fails, got nil")
+	}
+
+	accountsAfter, _ := setup.accountRepo.List(ctx)
+	if len(accountsAfter) != len(accountsBefore) {
+		t.Errorf("Expected %d accounts after rollback, got %d", len(accountsBefore), len(accountsAfter))
+	}
+	restored, err := setup.accountRepo.FindByID(ctx, current.ID())
+	if err != nil || restored.Email() != current.Email() {
+		t.Errorf("Account should be restored, got %v, %v", restored, err)
+	}
+	credsAfter, err := setup.credentialStore.Retrieve(ctx, current.ID())
+	if err != nil || string(credsAfter.EncryptedData()) != string(credsBefore.EncryptedData()) {
+		t.Errorf("Credentials should be restored unchanged, got err %v", err)
+	}
+	if setup.configManager.currentAccount != current {
+		t.Error("Config should still point at the account")
+	}
+}
+
+// TestRemoveAccountUseCase_Execute_RollbackFailureReported tests that a failed undo is
+// reported alongside the original error
+func TestRemoveAccountUseCase_Execute_RollbackFailureReported(t *testing.T) {
+	setup := setupRemoveAccountTest()
+	ctx := context.Background()
+
+	// Account deletion fails after credentials are gone, and restoring them fails too
+	setup.accountRepo.deleteErr = errors.New("database locked")
+	setup.credentialStore.storeErr = errors.New("keychain locked")
+
+	_, err := setup.useCase.Execute(ctx, usecases.RemoveAccountInput{AccountID: string(setup.testAccounts["work"].ID())})
+	if !errors.Is(err, setup.accountRepo.deleteErr) || !errors.Is(err, setup.credentialStore.storeErr) {
+		t.Errorf("Execute() error = %v, want both the delete and the rollback failure", err)
+	}
+}
+
+// TestRemoveAccountUseCase_Execute_ClearsDefault tests that removing the default account
+// clears the default pointer, and that a settings failure rolls everything back
+func TestRemoveAccountUseCase_Execute_ClearsDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		saveErr error
+	}{
+		{"success", nil},
+		{"settings save failure", errors.New("settings file locked")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupRemoveAccountTest()
+			ctx := context.Background()
+
+			current := setup.configManager.currentAccount
+			settingsRepo := newMockSettingsRepository()
+			settingsRepo.settings.SetDefaultAccountID(current.ID())
+			settingsRepo.saveErr = tt.saveErr
+
+			useCase := usecases.NewRemoveAccountService(setup.accountRepo, setup.credentialStore,
+				setup.configManager, setup.historyRepo, usecases.WithRemoveSettings(settingsRepo))
+			result, err := useCase.Execute(ctx, usecases.RemoveAccountInput{AccountID: string(current.ID()), ForceRemoveCurrent: true})
+
+			if tt.saveErr == nil {
+				if err != nil {
+					t.Fatalf("Execute() error = %v, want nil", err)
+				}
+				if !result.WasDefaultAccount {
+					t.Error("Expected WasDefaultAccount to be true")
+				}
+				if settingsRepo.settings.HasDefaultAccount() {
+					t.Error("Default account should be cleared")
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.saveErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.saveErr)
+			}
+			if settingsRepo.settings.DefaultAccountID() != current.ID() {
+				t.Error("Default account should be unchanged after rollback")
+			}
+			if _, err := setup.accountRepo.FindByID(ctx, current.ID()); err != nil {
+				t.Error("Account should be restored after rollback")
+			}
+			if _, err := setup.credentialStore.Retrieve(ctx, current.ID()); err != nil {
+				t.Error("Credentials should be restored after rollback")
+			}
+			if setup.configManager.currentAccount != current {
+				t.Error("Config should point at the account again after rollback")
+			}
+		})
+	}
+
+	// Removing a non-default account leaves the default alone
+	setup := setupRemoveAccountTest()
+	settingsRepo := newMockSettingsRepository()
+	settingsRepo.settings.SetDefaultAccountID(setup.testAccounts["personal"].ID())
+	useCase := usecases.NewRemoveAccountService(setup.accountRepo, setup.credentialStore,
+		setup.configManager, setup.historyRepo, usecases.WithRemoveSettings(settingsRepo))
+	result, err := useCase.Execute(context.Background(), usecases.RemoveAccountInput{AccountID: string(setup.testAccounts["work"].ID())})
+	if err != nil || result.WasDefaultAccount || settingsRepo.saveCalls != 0 {
+		t.Errorf("non-default removal: err %v, result %+v, saveCalls %d", err, result, settingsRepo.saveCalls)
+	}
+}
+
+// TestRemoveAccountUseCase_Execute_ClearsCurrentPointer tests that removing the account
+// ccx last switched to clears its pointer but leaves the default alone
+func TestRemoveAccountUseCase_Execute_ClearsCurrentPointer(t *testing.T) {
+	setup := setupRemoveAccountTest()
+	work := setup.testAccounts["work"]
+	settingsRepo := newMockSettingsRepository()
+	settingsRepo.settings.SetCurrentAccountID(work.ID())
+	settingsRepo.settings.SetDefaultAccountID(setup.testAccounts["test"].ID())
+
+	useCase := usecases.NewRemoveAccountService(setup.accountRepo, setup.credentialStore,
+		setup.configManager, setup.historyRepo, usecases.WithRemoveSettings(settingsRepo))
+	result, err := useCase.Execute(context.Background(), usecases.RemoveAccountInput{AccountID: string(work.ID())})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.WasDefaultAccount {
+		t.Error("WasDefaultAccount = true, want false")
+	}
+	if settingsRepo.settings.CurrentAccountID() != "" {
+		t.Errorf("CurrentAccountID() = %s, want it cleared", settingsRepo.settings.CurrentAccountID())
+	}
+	if settingsRepo.settings.DefaultAccountID() != setup.testAccounts["test"].ID() {
+		t.Error("Default account should be unchanged")
+	}
+}
+
 // TestRemoveAccountUseCase_Execute_HistoryUpdateFailure tests when history update fails
 func TestRemoveAccountUseCase_Execute_HistoryUpdateFailure(t *testing.T) {
 	setup := setupRemoveAccountTest()
@@ -386,7 +666,8 @@ func TestRemoveAccountUseCase_Execute_HistoryUpdateFailure(t *testing.T) {
 	// Remove current account
 	currentAccount := setup.configManager.currentAccount
 	input := usecases.RemoveAccountInput{
-		AccountID: string(currentAccount.ID()),
+		AccountID:          string(currentAccount.ID()),
+		ForceRemoveCurrent: true,
 	}
 
 	// Execute
@@ -406,7 +687,297 @@ func TestRemoveAccountUseCase_Execute_HistoryUpdateFailure(t *testing.T) {
 	}
 }
 
+// TestRemoveAccountUseCase_Execute_DryRun tests that a dry run reports the removal
+// without deleting anything
+func TestRemoveAccountUseCase_Execute_DryRun(t *testing.T) {
+	setup := setupRemoveAccountTest()
+	ctx := context.Background()
+	current := setup.configManager.currentAccount
+
+	result, err := setup.useCase.Execute(ctx, usecases.RemoveAccountInput{
+		AccountID:          string(current.ID()),
+		DryRun:             true,
+		ForceRemoveCurrent: true,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if !result.DryRun || !result.WasCurrentAccount || result.RemovedAccount.Email != testEmailPersonal {
+		t.Errorf("Execute() result = %+v, want dry run removal of current account", result)
+	}
+
+	// Nothing was touched
+	if _, err := setup.accountRepo.FindByID(ctx, current.ID()); err != nil {
+		t.Errorf("Account was deleted by a dry run: %v", err)
+	}
+	if _, err := setup.credentialStore.Retrieve(ctx, current.ID()); err != nil {
+		t.Errorf("Credentials were deleted by a dry run: %v", err)
+	}
+	if setup.configManager.currentAccount != current {
+		t.Error("Current account was cleared by a dry run")
+	}
+	if setup.historyRepo.saveCalls != 0 {
+		t.Errorf("History saved %d times during a dry run", setup.historyRepo.saveCalls)
+	}
+}
+
+// TestRemoveAccountUseCase_Execute_ConfigReadError tests that an unreadable Claude
+// config stops the removal instead of skipping the current-account guard
+func TestRemoveAccountUseCase_Execute_ConfigReadError(t *testing.T) {
+	setup := setupRemoveAccountTest()
+	ctx := context.Background()
+	current := setup.configManager.currentAccount
+	setup.configManager.getErr = errors.New("config corrupt")
+
+	if _, err := setup.useCase.Execute(ctx, usecases.RemoveAccountInput{AccountID: string(current.ID())}); err == nil {
+		t.Fatal("Execute() error = nil, want error")
+	}
+	if _, ok := setup.credentialStore.credentials[current.ID()]; !ok {
+		t.Error("Credentials were deleted despite the unreadable config")
+	}
+}
+
+// TestRemoveAccountUseCase_Execute_DryRunMissingCredentials tests that a dry run reports
+// the failure a real removal would hit
+func TestRemoveAccountUseCase_Execute_DryRunMissingCredentials(t *testing.T) {
+	setup := setupRemoveAccountTest()
+	ctx := context.Background()
+	work := setup.testAccounts["work"]
+	_ = setup.credentialStore.Delete(ctx, work.ID())
+
+	_, err := setup.useCase.Execute(ctx, usecases.RemoveAccountInput{
+		AccountID: string(work.ID()),
+		DryRun:    true,
+	})
+	if !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Errorf("Execute() error = %v, want ErrCredentialsNotFound", err)
+	}
+}
+
+// TestRemoveAccountUseCase_Execute_UnreadableCredentials tests that credentials which
+// can't be backed up are only deleted when forced
+func TestRemoveAccountUseCase_Execute_UnreadableCredentials(t *testing.T) {
+	setup := setupRemoveAccountTest()
+	ctx := context.Background()
+	work := setup.testAccounts["work"]
+	setup.credentialStore.retrieveErr = domain.ErrCredentialsTampered
+
+	for _, dryRun := range []bool{true, false} {
+		_, err := setup.useCase.Execute(ctx, usecases.RemoveAccountInput{
+			AccountID: string(work.ID()),
+			DryRun:    dryRun,
+		})
+		if !errors.Is(err, domain.ErrCredentialsTampered) {
+			t.Errorf("Execute(DryRun: %v) error = %v, want ErrCredentialsTampered", dryRun, err)
+		}
+	}
+	if _, ok := setup.credentialStore.credentials[work.ID()]; !ok {
+		t.Error("Credentials were deleted without a backup")
+	}
+	if _, err := setup.accountRepo.FindByID(ctx, work.ID()); err != nil {
+		t.Errorf("Account was deleted without a credential backup: %v", err)
+	}
+
+	if _, err := setup.useCase.Execute(ctx, usecases.RemoveAccountInput{
+		AccountID:          string(work.ID()),
+		ForceWithoutBackup: true,
+	}); err != nil {
+		t.Fatalf("Execute(ForceWithoutBackup) error = %v, want nil", err)
+	}
+	if _, ok := setup.credentialStore.credentials[work.ID()]; ok {
+		t.Error("Credentials were kept by a forced removal")
+	}
+}
+
+// TestRemoveAccountUseCase_Execute_RemovesProfiles tests that profiles pointing at the
+// removed account are deleted with it and reported, and restored on rollback
+func TestRemoveAccountUseCase_Execute_RemovesProfiles(t *testing.T) {
+	ctx := context.Background()
+	setup := setupRemoveAccountTest()
+	work := setup.testAccounts["work"]
+
+	profileRepo := newMockProfileRepository()
+	seedProfile(profileRepo, "client-b", work)
+	seedProfile(profileRepo, "client-a", work)
+	seedProfile(profileRepo, "personal", setup.testAccounts["personal"])
+	useCase := usecases.NewRemoveAccountService(setup.accountRepo, setup.credentialStore,
+		setup.configManager, setup.historyRepo, usecases.WithRemoveProfiles(profileRepo))
+
+	result, err := useCase.Execute(ctx, usecases.RemoveAccountInput{AccountID: string(work.ID()), DryRun: true})
+	if err != nil {
+		t.Fatalf("Execute() dry run error = %v, want nil", err)
+	}
+	if len(result.RemovedProfiles) != 2 || len(profileRepo.profiles) != 3 {
+		t.Errorf("Dry run RemovedProfiles = %v with %d stored, want 2 reported and 3 stored", result.RemovedProfiles, len(profileRepo.profiles))
+	}
+
+	// A failed profile delete rolls the whole removal back
+	profileRepo.deleteErr = errors.New("profiles file locked")
+	if _, err := useCase.Execute(ctx, usecases.RemoveAccountInput{AccountID: string(work.ID())}); !errors.Is(err, profileRepo.deleteErr) {
+		t.Fatalf("Execute() error = %v, want %v", err, profileRepo.deleteErr)
+	}
+	if _, err := setup.accountRepo.FindByID(ctx, work.ID()); err != nil {
+		t.Error("Account should be restored after rollback")
+	}
+	profileRepo.deleteErr = nil
+
+	result, err = useCase.Execute(ctx, usecases.RemoveAccountInput{AccountID: string(work.ID())})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if len(result.RemovedProfiles) != 2 || result.RemovedProfiles[0] != "client-a" || result.RemovedProfiles[1] != "client-b" {
+		t.Errorf("RemovedProfiles = %v, want [client-a client-b]", result.RemovedProfiles)
+	}
+	if _, ok := profileRepo.profiles["personal"]; !ok || len(profileRepo.profiles) != 1 {
+		t.Errorf("Remaining profiles = %v, want only personal", profileRepo.profiles)
+	}
+}
+
 // Interface compliance test
 func TestRemoveAccountService_ImplementsInterface(_ *testing.T) {
 	var _ usecases.RemoveAccountUseCase = (*usecases.RemoveAccountService)(nil)
 }
+
+func TestRemoveAccountUseCase_Execute_Archive(t *testing.T) {
+	ctx := context.Background()
+	setup := setupRemoveAccountTest()
+	work := setup.testAccounts["work"]
+
+	profileRepo := newMockProfileRepository()
+	seedProfile(profileRepo, "client", work)
+	useCase := usecases.NewRemoveAccountService(setup.accountRepo, setup.credentialStore,
+		setup.configManager, setup.historyRepo, usecases.WithRemoveProfiles(profileRepo))
+
+	input := usecases.RemoveAccountInput{AccountID: string(work.ID()), Archive: true}
+	result, err := useCase.Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if !result.Archived || len(result.RemovedProfiles) != 0 {
+		t.Errorf("Result = %+v, want archived with no removed profiles", result)
+	}
+
+	// The account, its credentials, and its profiles are all kept
+	stored, err := setup.accountRepo.FindByID(ctx, work.ID())
+	if err != nil || !stored.Archived() {
+		t.Fatalf("FindByID() = %v, %v; want an archived account", stored, err)
+	}
+	if work.Archived() {
+		t.Error("Archiving modified the account held by the caller")
+	}
+	if _, ok := setup.credentialStore.credentials[work.ID()]; !ok {
+		t.Error("Credentials were deleted by archiving")
+	}
+	if _, ok := profileRepo.profiles["client"]; !ok {
+		t.Error("Profile was deleted by archiving")
+	}
+
+	// Listing leaves it out unless asked
+	list := usecases.NewListAccountsService(setup.accountRepo)
+	infos, _ := list.Execute(ctx)
+	if len(infos) != 2 {
+		t.Errorf("Execute() listed %d accounts, want 2", len(infos))
+	}
+	infos, _ = list.ExecuteWithOptions(ctx, usecases.ListOptions{IncludeArchived: true})
+	if len(infos) != 3 {
+		t.Errorf("ExecuteWithOptions(IncludeArchived) listed %d accounts, want 3", len(infos))
+	}
+
+	if _, err := useCase.Execute(ctx, input); !errors.Is(err, usecases.ErrAccountArchived) {
+		t.Errorf("Execute() on archived account error = %v, want ErrAccountArchived", err)
+	}
+
+	// Hard delete remains available for archived accounts
+	if _, err := useCase.Execute(ctx, usecases.RemoveAccountInput{AccountID: string(work.ID())}); err != nil {
+		t.Fatalf("Execute() hard delete error = %v, want nil", err)
+	}
+	if _, ok := setup.credentialStore.credentials[work.ID()]; ok {
+		t.Error("Credentials should be deleted by a hard delete")
+	}
+}
+
+// mockTombstoneRepository records tombstones for RemoveAccount tests
+type mockTombstoneRepository struct {
+	tombstones []*domain.Tombstone
+	addErr     error
+}
+
+func (m *mockTombstoneRepository) Add(_ context.Context, tombstone *domain.Tombstone) error {
+	if m.addErr != nil {
+		return m.addErr
+	}
+	m.tombstones = append([]*domain.Tombstone{tombstone}, m.tombstones...)
+	return nil
+}
+
+func (m *mockTombstoneRepository) List(_ context.Context) ([]*domain.Tombstone, error) {
+	return m.tombstones, nil
+}
+
+// TestRemoveAccountUseCase_Execute_RecordsTombstone tests that deleting an account
+// leaves a tombstone with its metadata and reason, and that failing to record it
+// doesn't undo the removal
+func TestRemoveAccountUseCase_Execute_RecordsTombstone(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("recorded", func(t *testing.T) {
+		setup := setupRemoveAccountTest()
+		work := setup.testAccounts["work"]
+		tombstones := &mockTombstoneRepository{}
+		useCase := usecases.NewRemoveAccountService(setup.accountRepo, setup.credentialStore,
+			setup.configManager, setup.historyRepo, usecases.WithRemoveTombstones(tombstones))
+
+		_, err := useCase.Execute(ctx, usecases.RemoveAccountInput{Alias: "work", Reason: "contract ended"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v, want nil", err)
+		}
+		if len(tombstones.tombstones) != 1 {
+			t.Fatalf("Recorded %d tombstones, want 1", len(tombstones.tombstones))
+		}
+		tombstone := tombstones.tombstones[0]
+		if tombstone.AccountID() != work.ID() || tombstone.Email() != testEmailWork ||
+			tombstone.Alias() != "work" || tombstone.UUID() != "uuid-work" {
+			t.Errorf("Tombstone = %s/%s/%s/%s, want the work account", tombstone.AccountID(), tombstone.Email(), tombstone.Alias(), tombstone.UUID())
+		}
+		if tombstone.Reason() != "contract ended" || tombstone.RemovedAt().IsZero() {
+			t.Errorf("Reason/RemovedAt = %q/%v, want the reason and a removal time", tombstone.Reason(), tombstone.RemovedAt())
+		}
+
+		// Archiving and dry runs leave no tombstone
+		_, _ = useCase.Execute(ctx, usecases.RemoveAccountInput{Alias: "test", Archive: true})
+		_, _ = useCase.Execute(ctx, usecases.RemoveAccountInput{Alias: "test", DryRun: true})
+		if len(tombstones.tombstones) != 1 {
+			t.Errorf("Recorded %d tombstones, want only the deletion's", len(tombstones.tombstones))
+		}
+	})
+
+	t.Run("failure only warns", func(t *testing.T) {
+		setup := setupRemoveAccountTest()
+		work := setup.testAccounts["work"]
+		sink := &recordingEventSink{}
+		tombstones := &mockTombstoneRepository{addErr: errors.New("disk full")}
+		useCase := usecases.NewRemoveAccountService(setup.accountRepo, setup.credentialStore,
+			setup.configManager, setup.historyRepo, usecases.WithRemoveTombstones(tombstones), usecases.WithRemoveEvents(sink))
+
+		if _, err := useCase.Execute(ctx, usecases.RemoveAccountInput{Alias: "work"}); err != nil {
+			t.Fatalf("Execute() error = %v, want nil", err)
+		}
+		if _, err := setup.accountRepo.FindByID(ctx, work.ID()); !errors.Is(err, domain.ErrAccountNotFound) {
+			t.Errorf("FindByID() error = %v, want the account removed", err)
+		}
+		if len(sink.warnings) != 1 || !errors.Is(sink.warnings[0], tombstones.addErr) {
+			t.Errorf("Warnings = %v, want the tombstone failure", sink.warnings)
+		}
+	})
+
+	t.Run("invalid reason", func(t *testing.T) {
+		setup := setupRemoveAccountTest()
+		_, err := setup.useCase.Execute(ctx, usecases.RemoveAccountInput{Alias: "work", Reason: "bad\nreason"})
+		if err == nil {
+			t.Fatal("Execute() error = nil, want an invalid reason error")
+		}
+		if _, err := setup.accountRepo.FindByID(ctx, setup.testAccounts["work"].ID()); err != nil {
+			t.Errorf("FindByID() error = %v, want the account kept", err)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// RenameAccountUseCase defines the interface for changing an account's email
+type RenameAccountUseCase interface {
+	Execute(ctx context.Context, input RenameAccountInput) (*RenameAccountResult, error)
+}
+
+// RenameAccountInput contains the input data for renaming an account
+type RenameAccountInput struct {
+	AccountID      string // Account ID to rename
+	NewEmail       string // New email; normalized before use
+	RewriteHistory bool   // Also replace the old email in switch history
+}
+
+// RenameAccountResult contains the result of a rename operation
+type RenameAccountResult struct {
+	Account          AccountInfo `json:"account"`           // Account after the rename
+	OldEmail         string      `json:"old_email"`         // Email before the rename
+	NewEmail         string      `json:"new_email"`         // Email after the rename
+	HistoryRewritten int         `json:"history_rewritten"` // History entries now referring to the new email
+	HistoryDropped   int         `json:"history_dropped"`   // History entries removed because they became a switch to self
+}
+
+// RenameAccountService implements the RenameAccountUseCase
+type RenameAccountService struct {
+	accounts ports.AccountRepository
+	history  ports.HistoryRepository
+}
+
+// Ensure RenameAccountService implements RenameAccountUseCase at compile time
+var _ RenameAccountUseCase = (*RenameAccountService)(nil)
+
+// NewRenameAccountService creates a new RenameAccountService
+func NewRenameAccountService(accounts ports.AccountRepository, history ports.HistoryRepository) RenameAccountUseCase {
+	return &RenameAccountService{
+		accounts: accounts,
+		history:  history,
+	}
+}
+
+// Execute changes the account's email, keeping its ID, credentials, and timestamps.
+// With RewriteHistory, switch history is updated too so Previous keeps resolving; if
+// saving the history fails the email change is undone.
+func (s *RenameAccountService) Execute(ctx context.Context, input RenameAccountInput) (*RenameAccountResult, error) {
+	if input.AccountID == "" {
+		return nil, errors.New("account ID is required")
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	account, err := s.accounts.FindByID(ctx, domain.AccountID(input.AccountID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+
+	oldEmail := account.Email()
+	newEmail := domain.NormalizeEmail(input.NewEmail)
+	if err := domain.ValidateEmail(string(newEmail)); err != nil {
+		return nil, fmt.Errorf("invalid email: %w", err)
+	}
+
+	result := &RenameAccountResult{
+		OldEmail: string(oldEmail),
+		NewEmail: string(newEmail),
+	}
+	if oldEmail == newEmail {
+		// Nothing to change
+		result.Account = newAccountInfo(account)
+		return result, nil
+	}
+
+	existing, err := s.accounts.FindByEmail(ctx, newEmail)
+	switch {
+	case err == nil && existing.ID() != account.ID():
+		return nil, fmt.Errorf("%w: email %s is already used by another account", domain.ErrDuplicateAccount, newEmail)
+	case err != nil && !errors.Is(err, domain.ErrAccountNotFound):
+		return nil, fmt.Errorf("failed to check for existing account: %w", err)
+	}
+
+	// The repository may hand the same account to other callers, so rename a copy
+	renamed := account.Clone()
+	if err := renamed.UpdateEmail(string(newEmail)); err != nil {
+		return nil, fmt.Errorf("invalid email: %w", err)
+	}
+
+	tx := NewTransaction()
+
+	err = tx.Do(ctx,
+		func(ctx context.Context) error { return s.accounts.Save(ctx, renamed) },
+		func(ctx context.Context) error { return s.accounts.Save(ctx, account) },
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save account: %w", err)
+	}
+
+	if input.RewriteHistory {
+		err = tx.Do(ctx, func(ctx context.Context) error {
+			return s.rewriteHistory(ctx, oldEmail, newEmail, result)
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewrite history: %w", err)
+		}
+	}
+
+	tx.Commit()
+
+	result.Account = newAccountInfo(renamed)
+	return result, nil
+}
+
+// rewriteHistory replaces oldEmail in switch history and records the counts in result.
+// History is only saved if an entry changed.
+func (s *RenameAccountService) rewriteHistory(ctx context.Context, oldEmail, newEmail domain.Email, result *RenameAccountResult) error {
+	history, err := s.history.LoadHistory(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+
+	before := len(history.Entries())
+	changed := history.RewriteEmail(oldEmail, newEmail)
+	if changed == 0 {
+		return nil
+	}
+
+	if err := s.history.SaveHistory(ctx, history); err != nil {
+		return err
+	}
+
+	result.HistoryDropped = before - len(history.Entries())
+	result.HistoryRewritten = changed - result.HistoryDropped
+	return nil
+}
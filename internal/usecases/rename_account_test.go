@@ -0,0 +1,203 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// setupRenameAccountTest creates the switch test accounts with history
+// personal -> work, then work -> personal
+func setupRenameAccountTest(t *testing.T) (*switchAccountTestSetup, usecases.RenameAccountUseCase) {
+	t.Helper()
+	setup := setupSwitchAccountTest()
+
+	for _, sw := range [][2]domain.Email{{testEmailPersonal, testEmailWork}, {testEmailWork, testEmailPersonal}} {
+		entry, err := domain.NewSwitchEntry(sw[0], sw[1])
+		if err != nil {
+			t.Fatalf("failed to create switch entry: %v", err)
+		}
+		setup.historyRepo.history.AddEntry(entry)
+	}
+
+	return setup, usecases.NewRenameAccountService(setup.accountRepo, setup.historyRepo)
+}
+
+// TestRenameAccountUseCase_Execute_RewritesHistory tests that the email changes and
+// history follows it
+func TestRenameAccountUseCase_Execute_RewritesHistory(t *testing.T) {
+	ctx := context.Background()
+	setup, useCase := setupRenameAccountTest(t)
+	work := setup.testAccounts["work"]
+
+	result, err := useCase.Execute(ctx, usecases.RenameAccountInput{
+		AccountID:      string(work.ID()),
+		NewEmail:       "Renamed@Example.com",
+		RewriteHistory: true,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if result.OldEmail != testEmailWork || result.NewEmail != "renamed@example.com" {
+		t.Errorf("result emails = %s -> %s", result.OldEmail, result.NewEmail)
+	}
+	if result.HistoryRewritten != 2 || result.HistoryDropped != 0 {
+		t.Errorf("HistoryRewritten = %d, HistoryDropped = %d, want 2 and 0", result.HistoryRewritten, result.HistoryDropped)
+	}
+
+	saved, err := setup.accountRepo.FindByID(ctx, work.ID())
+	if err != nil || saved.Email() != "renamed@example.com" {
+		t.Errorf("saved account email = %v (err %v), want renamed@example.com", saved, err)
+	}
+
+	// Previous now resolves against the new email
+	last := setup.historyRepo.history.GetLastSwitch()
+	if last.From() != "renamed@example.com" {
+		t.Errorf("last switch from = %s, want renamed@example.com", last.From())
+	}
+}
+
+// TestRenameAccountUseCase_Execute_DropsSelfSwitches tests that entries becoming a
+// switch to self are dropped and counted
+func TestRenameAccountUseCase_Execute_DropsSelfSwitches(t *testing.T) {
+	ctx := context.Background()
+	setup, useCase := setupRenameAccountTest(t)
+
+	// Remove the work account so personal can take over its email
+	work := setup.testAccounts["work"]
+	_ = setup.accountRepo.Delete(ctx, work.ID())
+
+	result, err := useCase.Execute(ctx, usecases.RenameAccountInput{
+		AccountID:      string(setup.testAccounts["personal"].ID()),
+		NewEmail:       testEmailWork,
+		RewriteHistory: true,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if result.HistoryRewritten != 0 || result.HistoryDropped != 2 {
+		t.Errorf("HistoryRewritten = %d, HistoryDropped = %d, want 0 and 2", result.HistoryRewritten, result.HistoryDropped)
+	}
+	if n := len(setup.historyRepo.history.Entries()); n != 0 {
+		t.Errorf("expected empty history, got %d entries", n)
+	}
+}
+
+// TestRenameAccountUseCase_Execute_WithoutHistory tests that history is untouched unless requested
+func TestRenameAccountUseCase_Execute_WithoutHistory(t *testing.T) {
+	ctx := context.Background()
+	setup, useCase := setupRenameAccountTest(t)
+
+	result, err := useCase.Execute(ctx, usecases.RenameAccountInput{
+		AccountID: string(setup.testAccounts["work"].ID()),
+		NewEmail:  "renamed@example.com",
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if result.HistoryRewritten != 0 || setup.historyRepo.saveCalls != 0 {
+		t.Errorf("history should not be touched, got %d rewritten and %d saves", result.HistoryRewritten, setup.historyRepo.saveCalls)
+	}
+	if from := setup.historyRepo.history.GetLastSwitch().From(); from != testEmailWork {
+		t.Errorf("last switch from = %s, want %s", from, testEmailWork)
+	}
+}
+
+// TestRenameAccountUseCase_Execute_LeavesLoadedAccountUnchanged tests that the rename is
+// saved from a copy, so an account the repository handed out keeps its email
+func TestRenameAccountUseCase_Execute_LeavesLoadedAccountUnchanged(t *testing.T) {
+	ctx := context.Background()
+	setup, useCase := setupRenameAccountTest(t)
+	work := setup.testAccounts["work"]
+
+	result, err := useCase.Execute(ctx, usecases.RenameAccountInput{
+		AccountID: string(work.ID()),
+		NewEmail:  "renamed@example.com",
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if work.Email() != testEmailWork {
+		t.Errorf("loaded account email = %s, want %s left unchanged", work.Email(), testEmailWork)
+	}
+	if result.Account.Email != "renamed@example.com" {
+		t.Errorf("result account email = %s, want renamed@example.com", result.Account.Email)
+	}
+	if saved, _ := setup.accountRepo.FindByID(ctx, work.ID()); saved == nil || saved.Email() != "renamed@example.com" {
+		t.Errorf("saved account = %v, want renamed@example.com", saved)
+	}
+}
+
+// TestRenameAccountUseCase_Execute_HistorySaveFailure tests that the email change is
+// undone when history cannot be saved
+func TestRenameAccountUseCase_Execute_HistorySaveFailure(t *testing.T) {
+	ctx := context.Background()
+	setup, useCase := setupRenameAccountTest(t)
+	setup.historyRepo.saveErr = errors.New("disk full")
+	work := setup.testAccounts["work"]
+
+	_, err := useCase.Execute(ctx, usecases.RenameAccountInput{
+		AccountID:      string(work.ID()),
+		NewEmail:       "renamed@example.com",
+		RewriteHistory: true,
+	})
+	if !errors.Is(err, setup.historyRepo.saveErr) {
+		t.Fatalf("Execute() error = %v, want history save error", err)
+	}
+
+	saved, _ := setup.accountRepo.FindByID(ctx, work.ID())
+	if saved.Email() != testEmailWork {
+		t.Errorf("account email = %s after failed rename, want %s", saved.Email(), testEmailWork)
+	}
+}
+
+// TestRenameAccountUseCase_Execute_Validation tests rejected renames
+func TestRenameAccountUseCase_Execute_Validation(t *testing.T) {
+	setup, useCase := setupRenameAccountTest(t)
+	workID := string(setup.testAccounts["work"].ID())
+
+	tests := []struct {
+		name  string
+		input usecases.RenameAccountInput
+		want  error // Sentinel the error must wrap, if any
+	}{
+		{"missing ID", usecases.RenameAccountInput{NewEmail: "renamed@example.com"}, nil},
+		{"unknown account", usecases.RenameAccountInput{AccountID: "nonexistent", NewEmail: "renamed@example.com"}, domain.ErrAccountNotFound},
+		{"invalid email", usecases.RenameAccountInput{AccountID: workID, NewEmail: "not-an-email"}, nil},
+		{"email taken", usecases.RenameAccountInput{AccountID: workID, NewEmail: testEmailPersonal}, domain.ErrDuplicateAccount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := useCase.Execute(context.Background(), tt.input)
+			if err == nil {
+				t.Fatal("Execute() error = nil, want error")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("Execute() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestRenameAccountUseCase_Execute_ContextCancellation tests context cancellation
+func TestRenameAccountUseCase_Execute_ContextCancellation(t *testing.T) {
+	setup, useCase := setupRenameAccountTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := useCase.Execute(ctx, usecases.RenameAccountInput{
+		AccountID: string(setup.testAccounts["work"].ID()),
+		NewEmail:  "renamed@example.com",
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want context.Canceled", err)
+	}
+}
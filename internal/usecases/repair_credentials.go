@@ -0,0 +1,125 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// RepairCredentialsUseCase defines the interface for re-creating credentials that can no longer be decrypted
+type RepairCredentialsUseCase interface {
+	Execute(ctx context.Context, input RepairCredentialsInput) (*RepairCredentialsResult, error)
+}
+
+// RepairCredentialsInput contains the input data for repairing credentials
+type RepairCredentialsInput struct {
+	AccountID string // Account whose credentials should be repaired
+	Plaintext []byte // Correct credential JSON supplied by the user
+	Force     bool   // Overwrite even if the existing credentials still decrypt
+}
+
+// RepairCredentialsResult contains the result of a repair operation
+type RepairCredentialsResult struct {
+	Account       AccountInfo `json:"account"`        // Account whose credentials were repaired
+	WasMissing    bool        `json:"was_missing"`    // True if no credentials were stored before the repair
+	WasBroken     bool        `json:"was_broken"`     // True if the previous credentials could not be decrypted
+	ForcedReplace bool        `json:"forced_replace"` // True if working credentials were overwritten because Force was set
+}
+
+// RepairCredentialsService implements the RepairCredentialsUseCase
+type RepairCredentialsService struct {
+	accounts    ports.AccountRepository
+	credentials ports.CredentialStore
+}
+
+// Ensure RepairCredentialsService implements RepairCredentialsUseCase at compile time
+var _ RepairCredentialsUseCase = (*RepairCredentialsService)(nil)
+
+// NewRepairCredentialsService creates a new RepairCredentialsService
+func NewRepairCredentialsService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+) RepairCredentialsUseCase {
+	return &RepairCredentialsService{
+		accounts:    accounts,
+		credentials: credentials,
+	}
+}
+
+// Execute re-encrypts the supplied plaintext under the current scheme for the account's
+// current ID. Existing credentials are only replaced if they fail to decrypt, unless Force is set.
+// If the store fails to read them for any other reason, nothing is written.
+func (s *RepairCredentialsService) Execute(ctx context.Context, input RepairCredentialsInput) (*RepairCredentialsResult, error) {
+	if input.AccountID == "" {
+		return nil, errors.New("account ID is required")
+	}
+	if len(input.Plaintext) == 0 {
+		return nil, errors.New("credentials data is required")
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	account, err := s.accounts.FindByID(ctx, domain.AccountID(input.AccountID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+
+	result := &RepairCredentialsResult{Account: newAccountInfo(account)}
+
+	existing, err := s.credentials.Retrieve(ctx, account.ID())
+	switch {
+	case errors.Is(err, domain.ErrCredentialsNotFound):
+		result.WasMissing = true
+	case undecodable(err):
+		// Stored credentials that can't be decoded are what repair is for
+		result.WasBroken = true
+	case err != nil:
+		// The store itself failed, such as a lock timeout or a missing master key, so
+		// working credentials may be stored and must not be overwritten
+		return nil, fmt.Errorf("failed to read existing credentials: %w", err)
+	case s.decrypts(existing):
+		if !input.Force {
+			return nil, fmt.Errorf("credentials for %s are not broken; use force to overwrite them", account.Email())
+		}
+		result.ForcedReplace = true
+	default:
+		result.WasBroken = true
+	}
+
+	repaired, err := domain.NewCredentials(account.ID(), input.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credentials: %w", err)
+	}
+
+	if err := s.credentials.Store(ctx, repaired); err != nil {
+		return nil, fmt.Errorf("failed to store credentials: %w", err)
+	}
+
+	return result, nil
+}
+
+// undecodable reports whether err from Retrieve means credentials are stored but are
+// corrupt, as opposed to the store failing to read them
+func undecodable(err error) bool {
+	var syntaxErr *json.SyntaxError
+	return errors.Is(err, domain.ErrCredentialsTampered) || errors.As(err, &syntaxErr)
+}
+
+// decrypts reports whether credentials should be treated as working. Passphrase-protected
+// credentials cannot be checked without the passphrase, so they are assumed to work.
+func (s *RepairCredentialsService) decrypts(creds *domain.Credentials) bool {
+	if creds.IsPassphraseProtected() {
+		return true
+	}
+	_, err := creds.Decrypt()
+	return err == nil
+}
@@ -0,0 +1,207 @@
+package usecases_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+const repairedPlaintext = `{"sessionKey":"repaired"}`
+
+func setupRepairCredentialsTest() (*mockAccountRepository, *mockCredentialStore, *domain.Account, usecases.RepairCredentialsUseCase) {
+	accountRepo := newMockAccountRepository()
+	credentialStore := newMockCredentialStore()
+	account, _ := domain.NewAccount(testEmailPersonal, "personal", "uuid-personal")
+	_ = accountRepo.Save(context.Background(), account)
+	return accountRepo, credentialStore, account, usecases.NewRepairCredentialsService(accountRepo, credentialStore)
+}
+
+// assertRepaired checks that stored credentials decrypt to the repaired plaintext
+func assertRepaired(t *testing.T, store *mockCredentialStore, accountID domain.AccountID) {
+	t.Helper()
+
+	creds, err := store.Retrieve(context.Background(), accountID)
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	data, err := creds.Decrypt()
+	if err != nil {
+		t.Fatalf("Repaired credentials should decrypt, got %v", err)
+	}
+	if string(data) != repairedPlaintext {
+		t.Errorf("Decrypted data = %s, want %s", data, repairedPlaintext)
+	}
+}
+
+// TestRepairCredentialsUseCase_Execute_RepairsBroken tests replacing undecryptable credentials
+func TestRepairCredentialsUseCase_Execute_RepairsBroken(t *testing.T) {
+	_, credentialStore, account, useCase := setupRepairCredentialsTest()
+	_ = credentialStore.Store(context.Background(), undecryptableCredentials(t, account.ID()))
+
+	result, err := useCase.Execute(context.Background(), usecases.RepairCredentialsInput{
+		AccountID: string(account.ID()),
+		Plaintext: []byte(repairedPlaintext),
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if !result.WasBroken || result.ForcedReplace || result.WasMissing {
+		t.Errorf("Unexpected result flags: %+v", result)
+	}
+	if result.Account.Email != testEmailPersonal {
+		t.Errorf("Account.Email = %s, want %s", result.Account.Email, testEmailPersonal)
+	}
+	assertRepaired(t, credentialStore, account.ID())
+}
+
+// TestRepairCredentialsUseCase_Execute_RefusesWorkingCredentials tests that working credentials need Force
+func TestRepairCredentialsUseCase_Execute_RefusesWorkingCredentials(t *testing.T) {
+	_, credentialStore, account, useCase := setupRepairCredentialsTest()
+	working, _ := domain.NewCredentials(account.ID(), []byte(`{"sessionKey":"working"}`))
+	_ = credentialStore.Store(context.Background(), working)
+
+	input := usecases.RepairCredentialsInput{
+		AccountID: string(account.ID()),
+		Plaintext: []byte(repairedPlaintext),
+	}
+
+	if _, err := useCase.Execute(context.Background(), input); err == nil {
+		t.Fatal("Execute() error = nil, want refusal without force")
+	}
+	stored, _ := credentialStore.Retrieve(context.Background(), account.ID())
+	if data, _ := stored.Decrypt(); string(data) != `{"sessionKey":"working"}` {
+		t.Errorf("Working credentials were overwritten: %s", data)
+	}
+
+	input.Force = true
+	result, err := useCase.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute() with force error = %v, want nil", err)
+	}
+	if !result.ForcedReplace || result.WasBroken {
+		t.Errorf("Unexpected result flags: %+v", result)
+	}
+	assertRepaired(t, credentialStore, account.ID())
+}
+
+// TestRepairCredentialsUseCase_Execute_MissingCredentials tests recreating credentials that were never stored
+func TestRepairCredentialsUseCase_Execute_MissingCredentials(t *testing.T) {
+	_, credentialStore, account, useCase := setupRepairCredentialsTest()
+
+	result, err := useCase.Execute(context.Background(), usecases.RepairCredentialsInput{
+		AccountID: string(account.ID()),
+		Plaintext: []byte(repairedPlaintext),
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if !result.WasMissing {
+		t.Errorf("Expected WasMissing, got %+v", result)
+	}
+	assertRepaired(t, credentialStore, account.ID())
+}
+
+// TestRepairCredentialsUseCase_Execute_RetrieveErrors tests that only missing or
+// corrupt credentials are replaced without force, and that other read failures stop the
+// repair before anything is written
+func TestRepairCredentialsUseCase_Execute_RetrieveErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantBroken bool
+	}{
+		{"tampered", fmt.Errorf("failed to deserialize credentials: %w", domain.ErrCredentialsTampered), true},
+		{"malformed file", fmt.Errorf("failed to deserialize credentials: %w", &json.SyntaxError{}), true},
+		{"lock timeout", errors.New("timed out waiting for data directory lock"), false},
+		{"master key missing", domain.ErrMasterKeyRequired, false},
+		{"cancelled", context.Canceled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, credentialStore, account, useCase := setupRepairCredentialsTest()
+			working, _ := domain.NewCredentials(account.ID(), []byte(`{"sessionKey":"working"}`))
+			_ = credentialStore.Store(context.Background(), working)
+			credentialStore.retrieveErr = tt.err
+
+			result, err := useCase.Execute(context.Background(), usecases.RepairCredentialsInput{
+				AccountID: string(account.ID()),
+				Plaintext: []byte(repairedPlaintext),
+			})
+			if tt.wantBroken {
+				if err != nil || !result.WasBroken {
+					t.Errorf("Execute() = %+v, %v; want the credentials repaired as broken", result, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Execute() error = %v, want wrapped %v", err, tt.err)
+			}
+			if data, _ := credentialStore.credentials[account.ID()].Decrypt(); string(data) != `{"sessionKey":"working"}` {
+				t.Errorf("Credentials were overwritten after a read failure: %s", data)
+			}
+		})
+	}
+}
+
+// TestRepairCredentialsUseCase_Execute_Errors tests validation and dependency failures
+func TestRepairCredentialsUseCase_Execute_Errors(t *testing.T) {
+	storeErr := errors.New("disk full")
+
+	tests := []struct {
+		name    string
+		input   func(account *domain.Account) usecases.RepairCredentialsInput
+		setup   func(store *mockCredentialStore)
+		wantErr error
+	}{
+		{
+			name: "missing account ID",
+			input: func(_ *domain.Account) usecases.RepairCredentialsInput {
+				return usecases.RepairCredentialsInput{Plaintext: []byte(repairedPlaintext)}
+			},
+		},
+		{
+			name: "missing plaintext",
+			input: func(account *domain.Account) usecases.RepairCredentialsInput {
+				return usecases.RepairCredentialsInput{AccountID: string(account.ID())}
+			},
+		},
+		{
+			name: "unknown account",
+			input: func(_ *domain.Account) usecases.RepairCredentialsInput {
+				return usecases.RepairCredentialsInput{AccountID: "unknown", Plaintext: []byte(repairedPlaintext)}
+			},
+		},
+		{
+			name: "store failure",
+			input: func(account *domain.Account) usecases.RepairCredentialsInput {
+				return usecases.RepairCredentialsInput{AccountID: string(account.ID()), Plaintext: []byte(repairedPlaintext)}
+			},
+			setup:   func(store *mockCredentialStore) { store.storeErr = storeErr },
+			wantErr: storeErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, credentialStore, account, useCase := setupRepairCredentialsTest()
+			if tt.setup != nil {
+				tt.setup(credentialStore)
+			}
+
+			_, err := useCase.Execute(context.Background(), tt.input(account))
+			if err == nil {
+				t.Fatal("Execute() error = nil, want error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Execute() error = %v, want wrapped %v", err, tt.wantErr)
+			}
+		})
+	}
+}
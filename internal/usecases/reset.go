@@ -0,0 +1,200 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// ResetUseCase defines the interface for wiping all ccx state
+type ResetUseCase interface {
+	Execute(ctx context.Context, input ResetInput) (*ResetResult, error)
+}
+
+// ResetInput contains the input data for a reset
+type ResetInput struct {
+	KeepHistory bool // Preserve switch history while removing accounts and credentials
+}
+
+// ResetResult contains the result of a reset
+type ResetResult struct {
+	RemovedAccounts []AccountInfo `json:"removed_accounts"` // Accounts that were removed
+	ClearedCurrent  bool          `json:"cleared_current"`  // True if the current account configuration was cleared
+	HistoryCleared  bool          `json:"history_cleared"`  // True if switch history was cleared
+	// RemovedProfiles names the profiles that were deleted along with the accounts they
+	// pointed at, sorted by name. Empty unless profiles are configured.
+	RemovedProfiles []string `json:"removed_profiles"`
+}
+
+// ResetService implements the ResetUseCase
+type ResetService struct {
+	accounts    ports.AccountRepository
+	credentials ports.CredentialStore
+	config      ports.ConfigManager
+	history     ports.HistoryRepository
+	settings    ports.SettingsRepository
+	profiles    ports.ProfileRepository
+}
+
+// Ensure ResetService implements ResetUseCase at compile time
+var _ ResetUseCase = (*ResetService)(nil)
+
+// ResetOption configures optional ResetService behavior
+type ResetOption func(*ResetService)
+
+// WithResetSettings gives ResetService access to ccx settings so the default and
+// current account pointers are cleared along with the accounts
+func WithResetSettings(settings ports.SettingsRepository) ResetOption {
+	return func(s *ResetService) {
+		s.settings = settings
+	}
+}
+
+// WithResetProfiles gives ResetService access to profiles so they are deleted along
+// with the accounts rather than left orphaned
+func WithResetProfiles(profiles ports.ProfileRepository) ResetOption {
+	return func(s *ResetService) {
+		s.profiles = profiles
+	}
+}
+
+// NewResetService creates a new ResetService
+func NewResetService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+	config ports.ConfigManager,
+	history ports.HistoryRepository,
+	opts ...ResetOption,
+) ResetUseCase {
+	s := &ResetService{
+		accounts:    accounts,
+		credentials: credentials,
+		config:      config,
+		history:     history,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Execute removes every account and its credentials, clears the current account
+// if it is managed by ccx, and clears history unless KeepHistory is set. The settings
+// pointers and profiles referring to the accounts are cleared too, when configured.
+func (s *ResetService) Execute(ctx context.Context, input ResetInput) (*ResetResult, error) {
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	accounts, err := s.accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	result := &ResetResult{
+		RemovedAccounts: make([]AccountInfo, 0, len(accounts)),
+		RemovedProfiles: []string{},
+	}
+
+	// Determine whether the current account belongs to ccx before deleting anything
+	current, err := s.config.GetCurrentAccount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current account: %w", err)
+	}
+	managesCurrent := matchCurrentAccount(current, accounts) != nil
+
+	for _, account := range accounts {
+		// Delete credentials first (critical for security)
+		if err := s.credentials.Delete(ctx, account.ID()); err != nil {
+			return nil, fmt.Errorf("failed to delete credentials for %s: %w", account.Email(), err)
+		}
+		if err := s.accounts.Delete(ctx, account.ID()); err != nil {
+			return nil, fmt.Errorf("failed to delete account %s: %w", account.Email(), err)
+		}
+		result.RemovedAccounts = append(result.RemovedAccounts, newAccountInfo(account))
+	}
+
+	if managesCurrent {
+		if err := s.config.SetCurrentAccount(ctx, nil); err != nil {
+			return nil, fmt.Errorf("failed to clear current account configuration: %w", err)
+		}
+		result.ClearedCurrent = true
+	}
+
+	if err := s.clearSettings(ctx); err != nil {
+		return nil, err
+	}
+	removed, err := s.deleteProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.RemovedProfiles = removed
+
+	if !input.KeepHistory {
+		if err := s.clearHistory(ctx); err != nil {
+			return nil, err
+		}
+		result.HistoryCleared = true
+	}
+
+	return result, nil
+}
+
+func (s *ResetService) clearHistory(ctx context.Context) error {
+	history, err := s.history.LoadHistory(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+	history.Clear()
+	if err := s.history.SaveHistory(ctx, history); err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	return nil
+}
+
+// clearSettings clears the default and current account pointers, which no longer name
+// an account
+func (s *ResetService) clearSettings(ctx context.Context) error {
+	if s.settings == nil {
+		return nil
+	}
+	settings, err := s.settings.LoadSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+	if !settings.HasDefaultAccount() && settings.CurrentAccountID() == "" {
+		return nil
+	}
+	settings.SetDefaultAccountID("")
+	settings.SetCurrentAccountID("")
+	if err := s.settings.SaveSettings(ctx, settings); err != nil {
+		return fmt.Errorf("failed to clear settings pointing at removed accounts: %w", err)
+	}
+	return nil
+}
+
+// deleteProfiles deletes every profile, since each one points at a removed account, and
+// returns their names sorted
+func (s *ResetService) deleteProfiles(ctx context.Context) ([]string, error) {
+	if s.profiles == nil {
+		return []string{}, nil
+	}
+	profiles, err := s.profiles.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+	names := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		if err := s.profiles.Delete(ctx, profile.Name()); err != nil {
+			return nil, fmt.Errorf("failed to delete profile %s: %w", profile.Name(), err)
+		}
+		names = append(names, profile.Name())
+	}
+	slices.Sort(names)
+	return names, nil
+}
@@ -0,0 +1,204 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// Test setup helper for ResetUseCase
+type resetTestSetup struct {
+	accountRepo     *extendedMockAccountRepository
+	credentialStore *extendedMockCredentialStore
+	configManager   *mockConfigManager
+	historyRepo     *mockHistoryRepository
+	useCase         usecases.ResetUseCase
+}
+
+func setupResetTest() *resetTestSetup {
+	accountRepo := newExtendedMockAccountRepository()
+	credentialStore := newExtendedMockCredentialStore()
+	configManager := newMockConfigManager()
+	historyRepo := newMockHistoryRepository()
+
+	personal, _ := domain.NewAccount(testEmailPersonal, "personal", "uuid-personal")
+	work, _ := domain.NewAccount(testEmailWork, "work", "uuid-work")
+	for _, account := range []*domain.Account{personal, work} {
+		_ = accountRepo.Save(context.Background(), account)
+		creds, _ := domain.NewCredentials(account.ID(), []byte(`{"sessionKey":"key"}`))
+		_ = credentialStore.Store(context.Background(), creds)
+	}
+	configManager.currentAccount = work
+
+	entry, _ := domain.NewSwitchEntry(testEmailPersonal, testEmailWork)
+	historyRepo.history.AddEntry(entry)
+
+	return &resetTestSetup{
+		accountRepo:     accountRepo,
+		credentialStore: credentialStore,
+		configManager:   configManager,
+		historyRepo:     historyRepo,
+		useCase: usecases.NewResetService(
+			accountRepo,
+			credentialStore,
+			configManager,
+			historyRepo,
+		),
+	}
+}
+
+// TestResetUseCase_Execute_ClearsEverything tests a full reset
+func TestResetUseCase_Execute_ClearsEverything(t *testing.T) {
+	setup := setupResetTest()
+
+	result, err := setup.useCase.Execute(context.Background(), usecases.ResetInput{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if len(result.RemovedAccounts) != 2 {
+		t.Errorf("Expected 2 removed accounts, got %d", len(result.RemovedAccounts))
+	}
+	if len(setup.accountRepo.accounts) != 0 {
+		t.Errorf("Expected no accounts left, got %d", len(setup.accountRepo.accounts))
+	}
+	if len(setup.credentialStore.credentials) != 0 {
+		t.Errorf("Expected no credentials left, got %d", len(setup.credentialStore.credentials))
+	}
+	if !result.ClearedCurrent || setup.configManager.currentAccount != nil {
+		t.Error("Expected current account to be cleared")
+	}
+	if !result.HistoryCleared || len(setup.historyRepo.history.Entries()) != 0 {
+		t.Error("Expected history to be cleared")
+	}
+}
+
+// TestResetUseCase_Execute_KeepHistory tests that history survives when requested
+func TestResetUseCase_Execute_KeepHistory(t *testing.T) {
+	setup := setupResetTest()
+
+	result, err := setup.useCase.Execute(context.Background(), usecases.ResetInput{KeepHistory: true})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if len(setup.accountRepo.accounts) != 0 {
+		t.Errorf("Expected no accounts left, got %d", len(setup.accountRepo.accounts))
+	}
+	if len(setup.credentialStore.credentials) != 0 {
+		t.Errorf("Expected no credentials left, got %d", len(setup.credentialStore.credentials))
+	}
+	if result.HistoryCleared {
+		t.Error("HistoryCleared should be false when KeepHistory is set")
+	}
+	if setup.historyRepo.saveCalls != 0 {
+		t.Errorf("Expected history not to be saved, got %d saves", setup.historyRepo.saveCalls)
+	}
+	if last := setup.historyRepo.history.GetLastSwitch(); last == nil || last.To() != testEmailWork {
+		t.Errorf("Expected history to survive reset, got %v", last)
+	}
+}
+
+// TestResetUseCase_Execute_UnmanagedCurrentAccount tests that a foreign current account is left alone
+func TestResetUseCase_Execute_UnmanagedCurrentAccount(t *testing.T) {
+	setup := setupResetTest()
+	other, _ := domain.NewAccount("other@example.com", "", "uuid-other")
+	setup.configManager.currentAccount = other
+
+	result, err := setup.useCase.Execute(context.Background(), usecases.ResetInput{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if result.ClearedCurrent {
+		t.Error("ClearedCurrent should be false for an account not managed by ccx")
+	}
+	if setup.configManager.currentAccount != other {
+		t.Error("Expected unmanaged current account to be preserved")
+	}
+}
+
+// TestResetUseCase_Execute_CredentialDeleteFailure tests credential deletion failure handling
+func TestResetUseCase_Execute_CredentialDeleteFailure(t *testing.T) {
+	setup := setupResetTest()
+	deleteErr := errors.New("keychain locked")
+	setup.credentialStore.deleteErr = deleteErr
+
+	_, err := setup.useCase.Execute(context.Background(), usecases.ResetInput{})
+	if !errors.Is(err, deleteErr) {
+		t.Errorf("Execute() error = %v, want wrapped %v", err, deleteErr)
+	}
+	if len(setup.accountRepo.accounts) != 2 {
+		t.Errorf("Expected accounts to remain after credential failure, got %d", len(setup.accountRepo.accounts))
+	}
+}
+
+// TestResetUseCase_Execute_ContextCancellation tests context cancellation
+func TestResetUseCase_Execute_ContextCancellation(t *testing.T) {
+	setup := setupResetTest()
+
+	// Create cancelled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := setup.useCase.Execute(ctx, usecases.ResetInput{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want %v", err, context.Canceled)
+	}
+	if len(setup.accountRepo.accounts) != 2 {
+		t.Error("Expected no accounts to be removed after cancellation")
+	}
+}
+
+// TestResetUseCase_Execute_ClearsSettingsAndProfiles tests that nothing is left pointing
+// at the removed accounts
+func TestResetUseCase_Execute_ClearsSettingsAndProfiles(t *testing.T) {
+	setup := setupResetTest()
+	ctx := context.Background()
+	settingsRepo := newMockSettingsRepository()
+	profileRepo := newMockProfileRepository()
+	work := setup.configManager.currentAccount
+	settingsRepo.settings.SetDefaultAccountID(work.ID())
+	settingsRepo.settings.SetCurrentAccountID(work.ID())
+	seedProfile(profileRepo, "office", work)
+	seedProfile(profileRepo, "backup", work)
+
+	useCase := usecases.NewResetService(
+		setup.accountRepo,
+		setup.credentialStore,
+		setup.configManager,
+		setup.historyRepo,
+		usecases.WithResetSettings(settingsRepo),
+		usecases.WithResetProfiles(profileRepo),
+	)
+	result, err := useCase.Execute(ctx, usecases.ResetInput{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if settingsRepo.settings.HasDefaultAccount() || settingsRepo.settings.CurrentAccountID() != "" {
+		t.Error("Expected default and current account settings to be cleared")
+	}
+	if len(profileRepo.profiles) != 0 {
+		t.Errorf("Expected no profiles left, got %d", len(profileRepo.profiles))
+	}
+	if len(result.RemovedProfiles) != 2 || result.RemovedProfiles[0] != "backup" || result.RemovedProfiles[1] != "office" {
+		t.Errorf("RemovedProfiles = %v, want [backup office]", result.RemovedProfiles)
+	}
+}
+
+// TestResetUseCase_Execute_ConfigReadError tests that an unreadable Claude config stops
+// the reset before anything is deleted
+func TestResetUseCase_Execute_ConfigReadError(t *testing.T) {
+	setup := setupResetTest()
+	setup.configManager.getErr = errors.New("config corrupt")
+
+	if _, err := setup.useCase.Execute(context.Background(), usecases.ResetInput{}); err == nil {
+		t.Fatal("Execute() error = nil, want error")
+	}
+	if len(setup.accountRepo.accounts) != 2 {
+		t.Errorf("Expected accounts to be kept, got %d", len(setup.accountRepo.accounts))
+	}
+}
@@ -0,0 +1,77 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// SearchHistoryUseCase defines the interface for finding switches in history
+type SearchHistoryUseCase interface {
+	Execute(ctx context.Context, input SearchHistoryInput) ([]SwitchInfo, error)
+}
+
+// SearchHistoryInput contains the filters for a history search. Empty fields match
+// any switch; set fields must all match.
+type SearchHistoryInput struct {
+	FromEmail string    // Only switches away from this email
+	ToEmail   string    // Only switches to this email
+	Since     time.Time // Only switches at or after this time
+	Until     time.Time // Only switches before this time
+}
+
+// SearchHistoryService implements the SearchHistoryUseCase
+type SearchHistoryService struct {
+	history ports.HistoryRepository
+}
+
+// Ensure SearchHistoryService implements SearchHistoryUseCase at compile time
+var _ SearchHistoryUseCase = (*SearchHistoryService)(nil)
+
+// NewSearchHistoryService creates a new SearchHistoryService
+func NewSearchHistoryService(history ports.HistoryRepository) SearchHistoryUseCase {
+	return &SearchHistoryService{
+		history: history,
+	}
+}
+
+// Execute returns the switches matching every filter in input, most recent first.
+// Emails are matched in normalized form, so case and surrounding whitespace are ignored.
+func (s *SearchHistoryService) Execute(ctx context.Context, input SearchHistoryInput) ([]SwitchInfo, error) {
+	if !input.Since.IsZero() && !input.Until.IsZero() && !input.Until.After(input.Since) {
+		return nil, errors.New("until must be after since")
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	history, err := s.history.LoadHistory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load history: %w", err)
+	}
+
+	from := domain.NormalizeEmail(input.FromEmail)
+	to := domain.NormalizeEmail(input.ToEmail)
+
+	entries := history.FindInRange(input.Since, input.Until)
+	matched := entries[:0]
+	for _, entry := range entries {
+		if from != "" && entry.From() != from {
+			continue
+		}
+		if to != "" && entry.To() != to {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+
+	return newSwitchInfos(matched), nil
+}
@@ -0,0 +1,98 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// seedDatedHistory records one switch per day starting at base, in order
+func seedDatedHistory(repo *mockHistoryRepository, base time.Time, pairs ...[2]domain.Email) {
+	for i, pair := range pairs {
+		entry, _ := domain.ReconstructSwitchEntry(pair[0], pair[1], base.AddDate(0, 0, i))
+		repo.history.AddEntry(entry)
+	}
+}
+
+// TestSearchHistoryUseCase_Execute_Filters tests combining email and time filters
+func TestSearchHistoryUseCase_Execute_Filters(t *testing.T) {
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	historyRepo := newMockHistoryRepository()
+	seedDatedHistory(historyRepo, base,
+		[2]domain.Email{testEmailPersonal, testEmailWork}, // day 0
+		[2]domain.Email{testEmailWork, testEmailTest},     // day 1
+		[2]domain.Email{testEmailTest, testEmailWork},     // day 2
+		[2]domain.Email{testEmailWork, testEmailPersonal}, // day 3
+	)
+	useCase := usecases.NewSearchHistoryService(historyRepo)
+
+	tests := []struct {
+		name     string
+		input    usecases.SearchHistoryInput
+		wantDays []int
+	}{
+		{"no filters", usecases.SearchHistoryInput{}, []int{3, 2, 1, 0}},
+		{"from email", usecases.SearchHistoryInput{FromEmail: testEmailWork}, []int{3, 1}},
+		{"to email ignores case", usecases.SearchHistoryInput{ToEmail: " WORK@example.com "}, []int{2, 0}},
+		{"from and to", usecases.SearchHistoryInput{FromEmail: testEmailWork, ToEmail: testEmailTest}, []int{1}},
+		{"since", usecases.SearchHistoryInput{Since: base.AddDate(0, 0, 2)}, []int{3, 2}},
+		{"until", usecases.SearchHistoryInput{Until: base.AddDate(0, 0, 1)}, []int{0}},
+		{
+			"all filters",
+			usecases.SearchHistoryInput{ToEmail: testEmailWork, Since: base.AddDate(0, 0, 1), Until: base.AddDate(0, 0, 3)},
+			[]int{2},
+		},
+		{"no match", usecases.SearchHistoryInput{FromEmail: "nobody@example.com"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			switches, err := useCase.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Execute() error = %v, want nil", err)
+			}
+			if switches == nil {
+				t.Fatal("Expected empty slice, got nil")
+			}
+			if len(switches) != len(tt.wantDays) {
+				t.Fatalf("Expected %d switches, got %d: %+v", len(tt.wantDays), len(switches), switches)
+			}
+			for i, day := range tt.wantDays {
+				if want := base.AddDate(0, 0, day); !switches[i].Timestamp.Equal(want) {
+					t.Errorf("switch %d timestamp = %v, want %v", i, switches[i].Timestamp, want)
+				}
+			}
+		})
+	}
+
+	// Searching does not change the stored history
+	if len(historyRepo.history.Entries()) != 4 {
+		t.Errorf("History has %d entries after searching, want 4", len(historyRepo.history.Entries()))
+	}
+}
+
+// TestSearchHistoryUseCase_Execute_Errors tests invalid ranges and load failures
+func TestSearchHistoryUseCase_Execute_Errors(t *testing.T) {
+	historyRepo := newMockHistoryRepository()
+	useCase := usecases.NewSearchHistoryService(historyRepo)
+
+	now := time.Now()
+	if _, err := useCase.Execute(context.Background(), usecases.SearchHistoryInput{Since: now, Until: now.Add(-time.Hour)}); err == nil {
+		t.Error("Expected error when until is before since, got nil")
+	}
+
+	historyRepo.loadErr = errors.New("history file corrupted")
+	if _, err := useCase.Execute(context.Background(), usecases.SearchHistoryInput{}); !errors.Is(err, historyRepo.loadErr) {
+		t.Errorf("Execute() error = %v, want wrapped %v", err, historyRepo.loadErr)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := useCase.Execute(ctx, usecases.SearchHistoryInput{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,72 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// SetDefaultAccountUseCase defines the interface for designating the default account
+type SetDefaultAccountUseCase interface {
+	Execute(ctx context.Context, input SetDefaultAccountInput) (*AccountInfo, error)
+}
+
+// SetDefaultAccountInput contains the input data for setting the default account
+type SetDefaultAccountInput struct {
+	AccountID string // Account ID to use as the default
+}
+
+// SetDefaultAccountService implements the SetDefaultAccountUseCase
+type SetDefaultAccountService struct {
+	accounts ports.AccountRepository
+	settings ports.SettingsRepository
+}
+
+// Ensure SetDefaultAccountService implements SetDefaultAccountUseCase at compile time
+var _ SetDefaultAccountUseCase = (*SetDefaultAccountService)(nil)
+
+// NewSetDefaultAccountService creates a new SetDefaultAccountService
+func NewSetDefaultAccountService(
+	accounts ports.AccountRepository,
+	settings ports.SettingsRepository,
+) SetDefaultAccountUseCase {
+	return &SetDefaultAccountService{
+		accounts: accounts,
+		settings: settings,
+	}
+}
+
+// Execute validates the account exists and records it as the default
+func (s *SetDefaultAccountService) Execute(ctx context.Context, input SetDefaultAccountInput) (*AccountInfo, error) {
+	if input.AccountID == "" {
+		return nil, errors.New("account ID is required")
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	account, err := s.accounts.FindByID(ctx, domain.AccountID(input.AccountID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+
+	settings, err := s.settings.LoadSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	settings.SetDefaultAccountID(account.ID())
+	if err := s.settings.SaveSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	info := newAccountInfo(account)
+	return &info, nil
+}
@@ -0,0 +1,141 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// Mock settings repository
+type mockSettingsRepository struct {
+	settings  *domain.Settings
+	saveErr   error
+	loadErr   error
+	saveCalls int
+}
+
+func newMockSettingsRepository() *mockSettingsRepository {
+	return &mockSettingsRepository{
+		settings: domain.NewSettings(),
+	}
+}
+
+func (m *mockSettingsRepository) LoadSettings(_ context.Context) (*domain.Settings, error) {
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+	return m.settings, nil
+}
+
+func (m *mockSettingsRepository) SaveSettings(_ context.Context, settings *domain.Settings) error {
+	m.saveCalls++
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.settings = settings
+	return nil
+}
+
+// Test setup helper for SetDefaultAccountUseCase
+type setDefaultAccountTestSetup struct {
+	accountRepo  *mockAccountRepository
+	settingsRepo *mockSettingsRepository
+	useCase      usecases.SetDefaultAccountUseCase
+	account      *domain.Account
+}
+
+func setupSetDefaultAccountTest() *setDefaultAccountTestSetup {
+	accountRepo := newMockAccountRepository()
+	settingsRepo := newMockSettingsRepository()
+
+	work, _ := domain.NewAccount(testEmailWork, "work", "uuid-work")
+	_ = accountRepo.Save(context.Background(), work)
+
+	return &setDefaultAccountTestSetup{
+		accountRepo:  accountRepo,
+		settingsRepo: settingsRepo,
+		useCase:      usecases.NewSetDefaultAccountService(accountRepo, settingsRepo),
+		account:      work,
+	}
+}
+
+// TestSetDefaultAccountUseCase_Execute_Success tests designating an existing account
+func TestSetDefaultAccountUseCase_Execute_Success(t *testing.T) {
+	setup := setupSetDefaultAccountTest()
+	ctx := context.Background()
+
+	info, err := setup.useCase.Execute(ctx, usecases.SetDefaultAccountInput{
+		AccountID: string(setup.account.ID()),
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if info.Email != testEmailWork {
+		t.Errorf("Expected default email %s, got %s", testEmailWork, info.Email)
+	}
+
+	if setup.settingsRepo.settings.DefaultAccountID() != setup.account.ID() {
+		t.Errorf("Expected default account %s to be persisted, got %s",
+			setup.account.ID(), setup.settingsRepo.settings.DefaultAccountID())
+	}
+}
+
+// TestSetDefaultAccountUseCase_Execute_AccountNotFound tests that the default must exist
+func TestSetDefaultAccountUseCase_Execute_AccountNotFound(t *testing.T) {
+	setup := setupSetDefaultAccountTest()
+	ctx := context.Background()
+
+	info, err := setup.useCase.Execute(ctx, usecases.SetDefaultAccountInput{AccountID: "nonexistent"})
+	if err == nil {
+		t.Error("Expected error when account does not exist, got nil")
+	}
+	if info != nil {
+		t.Errorf("Expected nil result on error, got %+v", info)
+	}
+
+	if setup.settingsRepo.saveCalls != 0 {
+		t.Error("Settings should not be saved when the account does not exist")
+	}
+}
+
+// TestSetDefaultAccountUseCase_Execute_EmptyInput tests when no account ID provided
+func TestSetDefaultAccountUseCase_Execute_EmptyInput(t *testing.T) {
+	setup := setupSetDefaultAccountTest()
+
+	if _, err := setup.useCase.Execute(context.Background(), usecases.SetDefaultAccountInput{}); err == nil {
+		t.Error("Expected error when no account ID provided, got nil")
+	}
+}
+
+// TestSetDefaultAccountUseCase_Execute_SaveFailure tests settings persistence failure
+func TestSetDefaultAccountUseCase_Execute_SaveFailure(t *testing.T) {
+	setup := setupSetDefaultAccountTest()
+	saveErr := errors.New("disk full")
+	setup.settingsRepo.saveErr = saveErr
+
+	_, err := setup.useCase.Execute(context.Background(), usecases.SetDefaultAccountInput{
+		AccountID: string(setup.account.ID()),
+	})
+	if !errors.Is(err, saveErr) {
+		t.Errorf("Execute() error = %v, want wrapped %v", err, saveErr)
+	}
+}
+
+// TestSetDefaultAccountUseCase_Execute_ContextCancellation tests context cancellation
+func TestSetDefaultAccountUseCase_Execute_ContextCancellation(t *testing.T) {
+	setup := setupSetDefaultAccountTest()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := setup.useCase.Execute(ctx, usecases.SetDefaultAccountInput{
+		AccountID: string(setup.account.ID()),
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want %v", err, context.Canceled)
+	}
+}
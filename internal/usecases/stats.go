@@ -0,0 +1,118 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// StatsUseCase defines the interface for summarizing how accounts are used
+type StatsUseCase interface {
+	Execute(ctx context.Context) (*UsageStats, error)
+}
+
+// AccountUsage summarizes the use of a single account
+type AccountUsage struct {
+	Account       AccountInfo   `json:"account"`            // The account
+	SwitchesTo    int           `json:"switches_to"`        // Switches to the account in recorded history
+	SwitchesFrom  int           `json:"switches_from"`      // Switches away from the account in recorded history
+	SinceLastUsed time.Duration `json:"since_last_used_ns"` // Time since the account was last switched to, in nanoseconds in JSON
+}
+
+// UsageStats summarizes switching across all accounts. Counts cover only the switches
+// still in history, which keeps a bounded number of entries.
+type UsageStats struct {
+	Accounts      []AccountUsage `json:"accounts"`       // One entry per account, in repository order
+	TotalSwitches int            `json:"total_switches"` // Switches in recorded history
+	// UnknownSwitches counts switches to accounts ccx no longer manages
+	UnknownSwitches int `json:"unknown_switches"`
+	// MostSwitchedTo is the account switched to most often, or nil if no switch went to
+	// a managed account. Ties go to the account listed first.
+	MostSwitchedTo *AccountUsage `json:"most_switched_to"`
+}
+
+// StatsService implements the StatsUseCase
+type StatsService struct {
+	accounts ports.AccountRepository
+	history  ports.HistoryRepository
+	now      func() time.Time
+}
+
+// Ensure StatsService implements StatsUseCase at compile time
+var _ StatsUseCase = (*StatsService)(nil)
+
+// StatsOption configures optional StatsService behavior
+type StatsOption func(*StatsService)
+
+// WithStatsClock overrides the time source used to compute SinceLastUsed
+func WithStatsClock(now func() time.Time) StatsOption {
+	return func(s *StatsService) {
+		s.now = now
+	}
+}
+
+// NewStatsService creates a new StatsService
+func NewStatsService(accounts ports.AccountRepository, history ports.HistoryRepository, opts ...StatsOption) StatsUseCase {
+	s := &StatsService{
+		accounts: accounts,
+		history:  history,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Execute combines the accounts' last-used times with the switch history. Accounts that
+// were never switched to or from are included with zero counts.
+func (s *StatsService) Execute(ctx context.Context) (*UsageStats, error) {
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	accounts, err := s.accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	history, err := s.history.LoadHistory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load history: %w", err)
+	}
+
+	now := s.now()
+	stats := &UsageStats{
+		Accounts:      make([]AccountUsage, 0, len(accounts)),
+		TotalSwitches: len(history.Entries()),
+	}
+
+	known := 0
+	for _, account := range accounts {
+		usage := AccountUsage{
+			Account:      newAccountInfo(account),
+			SwitchesTo:   len(history.FindSwitchesTo(account.Email())),
+			SwitchesFrom: len(history.FindSwitchesFrom(account.Email())),
+		}
+		if !account.LastUsed().IsZero() {
+			usage.SinceLastUsed = max(now.Sub(account.LastUsed()), 0)
+		}
+		known += usage.SwitchesTo
+		stats.Accounts = append(stats.Accounts, usage)
+	}
+	stats.UnknownSwitches = stats.TotalSwitches - known
+
+	for i := range stats.Accounts {
+		usage := &stats.Accounts[i]
+		if usage.SwitchesTo > 0 && (stats.MostSwitchedTo == nil || usage.SwitchesTo > stats.MostSwitchedTo.SwitchesTo) {
+			stats.MostSwitchedTo = usage
+		}
+	}
+
+	return stats, nil
+}
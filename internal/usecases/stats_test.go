@@ -0,0 +1,103 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// TestStatsUseCase_Execute tests per-account counts, totals, and the unknown bucket
+func TestStatsUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	accountRepo := newMockAccountRepository()
+	personal, _ := domain.ReconstructAccount("aaaa1111", testEmailPersonal, "personal", "uuid-personal", nil, now.Add(-72*time.Hour), now.Add(-2*time.Hour))
+	work, _ := domain.ReconstructAccount("bbbb2222", testEmailWork, "work", "uuid-work", nil, now.Add(-72*time.Hour), now.Add(-time.Hour))
+	idle, _ := domain.ReconstructAccount("cccc3333", testEmailTest, "idle", "uuid-idle", nil, now.Add(-72*time.Hour), now.Add(-48*time.Hour))
+	for _, account := range []*domain.Account{personal, work, idle} {
+		_ = accountRepo.Save(ctx, account)
+	}
+
+	historyRepo := newMockHistoryRepository()
+	seedHistory(historyRepo,
+		[2]domain.Email{testEmailPersonal, testEmailWork},
+		[2]domain.Email{testEmailWork, testEmailPersonal},
+		[2]domain.Email{testEmailPersonal, testEmailWork},
+		[2]domain.Email{testEmailWork, "removed@example.com"},
+		[2]domain.Email{"removed@example.com", testEmailWork},
+	)
+
+	useCase := usecases.NewStatsService(accountRepo, historyRepo, usecases.WithStatsClock(func() time.Time { return now }))
+	stats, err := useCase.Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if stats.TotalSwitches != 5 || stats.UnknownSwitches != 1 {
+		t.Errorf("TotalSwitches/UnknownSwitches = %d/%d, want 5/1", stats.TotalSwitches, stats.UnknownSwitches)
+	}
+	if len(stats.Accounts) != 3 {
+		t.Fatalf("len(Accounts) = %d, want 3", len(stats.Accounts))
+	}
+
+	want := map[string]struct {
+		to, from int
+		since    time.Duration
+	}{
+		testEmailPersonal: {1, 2, 2 * time.Hour},
+		testEmailWork:     {3, 2, time.Hour},
+		testEmailTest:     {0, 0, 48 * time.Hour},
+	}
+	for _, usage := range stats.Accounts {
+		w := want[usage.Account.Email]
+		if usage.SwitchesTo != w.to || usage.SwitchesFrom != w.from || usage.SinceLastUsed != w.since {
+			t.Errorf("%s usage = to %d, from %d, since %v; want %d, %d, %v",
+				usage.Account.Email, usage.SwitchesTo, usage.SwitchesFrom, usage.SinceLastUsed, w.to, w.from, w.since)
+		}
+	}
+
+	if stats.MostSwitchedTo == nil || stats.MostSwitchedTo.Account.Email != testEmailWork {
+		t.Errorf("MostSwitchedTo = %+v, want %s", stats.MostSwitchedTo, testEmailWork)
+	}
+}
+
+// TestStatsUseCase_Execute_NoHistory tests accounts that were never switched to
+func TestStatsUseCase_Execute_NoHistory(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository()
+	account, _ := domain.NewAccount(testEmailPersonal, "personal", "uuid-personal")
+	_ = accountRepo.Save(ctx, account)
+
+	stats, err := usecases.NewStatsService(accountRepo, newMockHistoryRepository()).Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if len(stats.Accounts) != 1 || stats.Accounts[0].SwitchesTo != 0 {
+		t.Errorf("Accounts = %+v, want one account with no switches", stats.Accounts)
+	}
+	if stats.TotalSwitches != 0 || stats.UnknownSwitches != 0 || stats.MostSwitchedTo != nil {
+		t.Errorf("Stats = %+v, want no switches", stats)
+	}
+}
+
+// TestStatsUseCase_Execute_Errors tests repository failures and cancellation
+func TestStatsUseCase_Execute_Errors(t *testing.T) {
+	accountRepo, historyRepo := newMockAccountRepository(), newMockHistoryRepository()
+	useCase := usecases.NewStatsService(accountRepo, historyRepo)
+
+	historyRepo.loadErr = errors.New("history file corrupted")
+	if _, err := useCase.Execute(context.Background()); !errors.Is(err, historyRepo.loadErr) {
+		t.Errorf("Execute() error = %v, want history error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := useCase.Execute(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want context.Canceled", err)
+	}
+}
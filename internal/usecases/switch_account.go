@@ -6,6 +6,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"time"
 
 	"github.com/evanschultz/ccx/internal/domain"
 	"github.com/evanschultz/ccx/internal/ports"
@@ -19,47 +22,178 @@ type SwitchAccountUseCase interface {
 // SwitchAccountInput contains the input data for switching accounts
 type SwitchAccountInput struct {
 	// Exactly one of these should be provided
-	AccountID string // Direct ID lookup
-	Email     string // Email lookup
-	Alias     string // Alias lookup
-	Index     int    // Quick-switch by index (1-based for CLI)
-	Previous  bool   // Switch to previous account (toggle)
+	AccountID  string // Direct ID lookup
+	Email      string // Email lookup
+	Alias      string // Alias lookup
+	Index      int    // Quick-switch by index (1-based for CLI)
+	Prefix     string // Unambiguous prefix of an alias or email
+	Previous   bool   // Switch to previous account (toggle)
+	Back       int    // Switch to the Nth most recent other account in history (1 is like Previous)
+	UseDefault bool   // Switch to the default account from ccx settings
+	MostRecent bool   // Switch to the other account with the latest last-used time
+
+	// DryRun resolves and validates the switch without changing config, history, or
+	// the account's last-used time
+	DryRun bool
+
+	// SkipHistory switches without recording the switch in history, so Previous keeps
+	// pointing at the account used before. The next switch made from the skipped
+	// account records it as changed outside ccx, which makes Previous return to the
+	// account before it.
+	SkipHistory bool
 }
 
 // SwitchAccountResult contains the result of a switch operation
 type SwitchAccountResult struct {
-	From *AccountInfo // Previous account (nil for first switch)
-	To   AccountInfo  // New current account
+	From      *AccountInfo `json:"from"`       // Previous account (nil for first switch)
+	To        AccountInfo  `json:"to"`         // New current account
+	ExpiresAt time.Time    `json:"expires_at"` // When the new account's session expires (zero if unknown)
+	Expired   bool         `json:"expired"`    // True if the new account's session has already expired
+	DryRun    bool         `json:"dry_run"`    // True if nothing was changed because DryRun was requested
+	// EnvExports holds the new account's environment variable overrides as KEY=value
+	// pairs sorted by key, for the caller to export; ccx does not change its own
+	// environment. Empty if the account has none.
+	EnvExports []string `json:"env_exports"`
+
+	// CurrentDivergedFromHistory is true if Claude's current account was not the last
+	// account switched to, meaning it was changed outside ccx. Unless this was a dry
+	// run, the out-of-band change was recorded in history before switching.
+	CurrentDivergedFromHistory bool `json:"current_diverged_from_history"`
 }
 
 // SwitchAccountService implements the SwitchAccountUseCase
 type SwitchAccountService struct {
-	accounts    ports.AccountRepository
-	credentials ports.CredentialStore
-	config      ports.ConfigManager
-	history     ports.HistoryRepository
+	accounts       ports.AccountRepository
+	credentials    ports.CredentialStore
+	config         ports.ConfigManager
+	history        ports.HistoryRepository
+	settings       ports.SettingsRepository
+	resolver       *AccountResolver
+	events         EventSink
+	validator      ports.CredentialValidator
+	previousMaxAge time.Duration
+	now            func() time.Time
 }
 
 // Ensure SwitchAccountService implements SwitchAccountUseCase at compile time
 var _ SwitchAccountUseCase = (*SwitchAccountService)(nil)
 
+// ErrNoRecentSwitch is returned when Previous is requested but the last switch is
+// older than the configured window
+var ErrNoRecentSwitch = errors.New("no recent switch to toggle back to")
+
+// ErrNoDefaultAccount is returned when UseDefault is requested but no default is set
+var ErrNoDefaultAccount = errors.New("no default account set")
+
+// ErrDefaultAccountMissing is returned when the default account no longer exists
+var ErrDefaultAccountMissing = errors.New("default account no longer exists")
+
+// ErrNoOtherAccount is returned when MostRecent is requested but the current account is
+// the only one
+var ErrNoOtherAccount = errors.New("no other account to switch to")
+
+// ErrAccountArchived is returned when the target account is archived
+var ErrAccountArchived = errors.New("account is archived")
+
+// ErrCredentialsCorrupt is returned when the target account's stored credentials
+// cannot be decrypted, so switching would leave Claude logged out
+var ErrCredentialsCorrupt = errors.New("credentials are corrupt")
+
+// ErrCredentialsRejected is returned when the credential validator rejects the target
+// account's session, for example because it has expired
+var ErrCredentialsRejected = errors.New("credentials rejected")
+
+// SwitchAccountOption configures optional SwitchAccountService behavior
+type SwitchAccountOption func(*SwitchAccountService)
+
+// WithPreviousMaxAge limits Previous to switches made within maxAge.
+// Zero (the default) means no limit.
+func WithPreviousMaxAge(maxAge time.Duration) SwitchAccountOption {
+	return func(s *SwitchAccountService) {
+		s.previousMaxAge = maxAge
+	}
+}
+
+// WithSwitchClock overrides the time source used by SwitchAccountService
+func WithSwitchClock(now func() time.Time) SwitchAccountOption {
+	return func(s *SwitchAccountService) {
+		s.now = now
+	}
+}
+
+// WithSwitchSettings gives SwitchAccountService access to ccx settings, enabling UseDefault
+// and keeping ccx's own current-account pointer in step with every switch
+func WithSwitchSettings(settings ports.SettingsRepository) SwitchAccountOption {
+	return func(s *SwitchAccountService) {
+		s.settings = settings
+	}
+}
+
+// WithSwitchEvents reports switches, failed switches, and warnings to sink
+func WithSwitchEvents(sink EventSink) SwitchAccountOption {
+	return func(s *SwitchAccountService) {
+		s.events = sink
+	}
+}
+
+// WithCredentialValidator checks the target account's credentials with validator before
+// switching, refusing the switch if they are rejected. Without one, only that the
+// credentials decrypt is checked.
+func WithCredentialValidator(validator ports.CredentialValidator) SwitchAccountOption {
+	return func(s *SwitchAccountService) {
+		s.validator = validator
+	}
+}
+
 // NewSwitchAccountService creates a new SwitchAccountService
 func NewSwitchAccountService(
 	accounts ports.AccountRepository,
 	credentials ports.CredentialStore,
 	config ports.ConfigManager,
 	history ports.HistoryRepository,
+	opts ...SwitchAccountOption,
 ) SwitchAccountUseCase {
-	return &SwitchAccountService{
+	return newSwitchAccountService(accounts, credentials, config, history, opts...)
+}
+
+// newSwitchAccountService builds the concrete service so other use cases can share its target resolution
+func newSwitchAccountService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+	config ports.ConfigManager,
+	history ports.HistoryRepository,
+	opts ...SwitchAccountOption,
+) *SwitchAccountService {
+	s := &SwitchAccountService{
 		accounts:    accounts,
 		credentials: credentials,
 		config:      config,
 		history:     history,
+		resolver:    NewAccountResolver(accounts),
+		events:      NopEventSink{},
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// Execute switches the current account based on the provided input
+// Execute switches the current account based on the provided input. A dry run is not
+// reported to the event sink.
 func (s *SwitchAccountService) Execute(ctx context.Context, input SwitchAccountInput) (*SwitchAccountResult, error) {
+	result, err := s.execute(ctx, input)
+	if err != nil {
+		if !input.DryRun {
+			s.events.OnSwitchFailed(err)
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// execute performs the switch; Execute reports its failures to the event sink
+func (s *SwitchAccountService) execute(ctx context.Context, input SwitchAccountInput) (*SwitchAccountResult, error) {
 	// Check context before proceeding
 	if err := ctx.Err(); err != nil {
 		return nil, fmt.Errorf("context cancelled: %w", err)
@@ -71,6 +205,10 @@ func (s *SwitchAccountService) Execute(ctx context.Context, input SwitchAccountI
 		return nil, fmt.Errorf("failed to get current account: %w", err)
 	}
 
+	// Record out-of-band logins first, so Previous and Back resolve against what
+	// Claude actually used
+	diverged := s.reconcileHistory(ctx, currentAccount, input.DryRun)
+
 	// Determine target account based on input
 	targetAccount, err := s.determineTargetAccount(ctx, input)
 	if err != nil {
@@ -78,97 +216,252 @@ func (s *SwitchAccountService) Execute(ctx context.Context, input SwitchAccountI
 	}
 
 	// Check if switching to same account
-	if currentAccount != nil && currentAccount.ID() == targetAccount.ID() {
-		// This is a no-op, return success
-		currentInfo := s.accountToInfo(currentAccount)
+	isCurrent, err := s.isCurrentAccount(ctx, currentAccount, targetAccount)
+	if err != nil {
+		return nil, err
+	}
+	if isCurrent {
+		// This is a no-op, return success, though ccx's pointer may still need to catch up.
+		// The environment is still returned, since the caller's shell may not have it yet.
+		if !input.DryRun {
+			s.recordCurrentAccount(ctx, targetAccount.ID())
+		}
+		currentInfo := newAccountInfo(targetAccount)
 		return &SwitchAccountResult{
-			From: &currentInfo,
-			To:   currentInfo,
+			From:                       &currentInfo,
+			To:                         currentInfo,
+			DryRun:                     input.DryRun,
+			EnvExports:                 envExports(targetAccount),
+			CurrentDivergedFromHistory: diverged,
 		}, nil
 	}
 
 	// Verify credentials exist for target account
-	_, err = s.credentials.Retrieve(ctx, targetAccount.ID())
+	creds, err := s.credentials.Retrieve(ctx, targetAccount.ID())
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve credentials for account %s: %w", targetAccount.Alias(), err)
+		return nil, fmt.Errorf("failed to retrieve credentials for account %s: %w", displayName(targetAccount), err)
+	}
+
+	// Stored credentials that no longer decrypt would only fail once Claude uses them
+	if err := checkCredentialsDecrypt(creds); err != nil {
+		name := displayName(targetAccount)
+		if errors.Is(err, domain.ErrPassphraseRequired) {
+			return nil, fmt.Errorf("%w: credentials for %s are passphrase-protected and Claude config needs them decrypted; re-encrypt them without a passphrase to switch", err, name)
+		}
+		return nil, fmt.Errorf("%w: credentials for %s cannot be decrypted (%v); repair them before switching", ErrCredentialsCorrupt, name, err)
+	}
+
+	// Validate before the config is touched, so a rejected session never reaches Claude
+	// and there is nothing to roll back
+	if s.validator != nil {
+		if err := s.validator.Validate(ctx, creds); err != nil {
+			name := displayName(targetAccount)
+			return nil, fmt.Errorf("%w: credentials for %s: %w", ErrCredentialsRejected, name, err)
+		}
 	}
 
-	// Update config with new account (this is the critical operation)
-	err = s.config.SetCurrentAccount(ctx, targetAccount)
+	if input.DryRun {
+		result := s.buildResult(currentAccount, targetAccount, creds)
+		result.DryRun = true
+		result.CurrentDivergedFromHistory = diverged
+		return result, nil
+	}
+
+	// Update config with the new account and its session (this is the critical
+	// operation). If the session cannot be written, the previous account is restored
+	// so Claude's displayed account and its auth stay in step.
+	tx := NewTransaction()
+	err = tx.Do(ctx,
+		func(ctx context.Context) error { return s.config.SetCurrentAccount(ctx, targetAccount) },
+		func(ctx context.Context) error { return s.config.SetCurrentAccount(ctx, currentAccount) },
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to set current account: %w", err)
 	}
+	err = tx.Do(ctx, func(ctx context.Context) error { return s.config.SetCredentials(ctx, creds) }, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write credentials for account %s: %w", displayName(targetAccount), err)
+	}
+	tx.Commit()
+
+	// Claude's config is what takes effect, so ccx's own pointer only warns on failure
+	s.recordCurrentAccount(ctx, targetAccount.ID())
 
 	// Save switch to history (non-critical - warn on failure)
-	if currentAccount != nil {
-		err = s.saveToHistory(ctx, currentAccount.Email(), targetAccount.Email())
-		if err != nil {
-			// Log warning but don't fail the operation
-			// In a real implementation, this would use a proper logger
-			// For now, we'll just continue
-			_ = err // Acknowledge the error was handled
+	if currentAccount != nil && !input.SkipHistory {
+		if err := s.saveToHistory(ctx, currentAccount.Email(), targetAccount.Email()); err != nil {
+			s.events.OnWarning(fmt.Errorf("failed to save switch history: %w", err))
 		}
 	}
 
-	// Build result
+	// Persist the usage timestamp (non-critical - a stale lastUsed only affects ordering).
+	// The repository may hand the same account to other callers, so update a copy.
+	targetAccount = targetAccount.Clone()
+	targetAccount.MarkUsed()
+	if err := s.accounts.Save(ctx, targetAccount); err != nil {
+		s.events.OnWarning(fmt.Errorf("failed to record last use of %s: %w", targetAccount.Email(), err))
+	}
+
+	result := s.buildResult(currentAccount, targetAccount, creds)
+	result.CurrentDivergedFromHistory = diverged
+	var fromInfo AccountInfo
+	if result.From != nil {
+		fromInfo = *result.From
+	}
+	s.events.OnSwitch(fromInfo, result.To)
+
+	return result, nil
+}
+
+// isCurrentAccount reports whether target is the account current, as read from Claude
+// config, refers to
+func (s *SwitchAccountService) isCurrentAccount(ctx context.Context, current, target *domain.Account) (bool, error) {
+	if current == nil {
+		return false, nil
+	}
+	accounts, err := s.accounts.List(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	managed := matchCurrentAccount(current, accounts)
+	return managed != nil && managed.ID() == target.ID(), nil
+}
+
+// buildResult describes a switch from current (nil for the first switch) to target
+func (s *SwitchAccountService) buildResult(current, target *domain.Account, creds *domain.Credentials) *SwitchAccountResult {
 	result := &SwitchAccountResult{
-		To: s.accountToInfo(targetAccount),
+		To:         newAccountInfo(target),
+		EnvExports: envExports(target),
 	}
-	if currentAccount != nil {
-		fromInfo := s.accountToInfo(currentAccount)
+	if current != nil {
+		fromInfo := newAccountInfo(current)
 		result.From = &fromInfo
 	}
 
-	return result, nil
+	// Expiry is advisory: the switch succeeds either way so the caller can prompt for re-auth
+	if expiresAt, ok := creds.ExpiresAt(); ok {
+		result.ExpiresAt = expiresAt
+		result.Expired = !expiresAt.After(s.now())
+	}
+
+	return result
+}
+
+// envExports returns the account's environment variable overrides as KEY=value pairs
+// sorted by key
+func envExports(account *domain.Account) []string {
+	env := account.EnvOverrides()
+	exports := make([]string, 0, len(env))
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		exports = append(exports, name+"="+env[name])
+	}
+	return exports
+}
+
+// checkCredentialsDecrypt verifies credentials can be decrypted for Claude config.
+// Passphrase-protected credentials can't be written to it without the passphrase, so
+// they return domain.ErrPassphraseRequired.
+func checkCredentialsDecrypt(creds *domain.Credentials) error {
+	if creds.IsPassphraseProtected() {
+		return domain.ErrPassphraseRequired
+	}
+	_, err := creds.Decrypt()
+	return err
 }
 
-// determineTargetAccount resolves the target account based on input
+// determineTargetAccount resolves the target account based on input. Index, Prefix,
+// Back, and MostRecent only consider unarchived accounts; any other way of naming an archived
+// account returns ErrAccountArchived.
 func (s *SwitchAccountService) determineTargetAccount(ctx context.Context, input SwitchAccountInput) (*domain.Account, error) {
+	account, err := s.resolveTargetAccount(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if account.Archived() {
+		name := displayName(account)
+		return nil, fmt.Errorf("%w: %s; unarchive it to switch to it", ErrAccountArchived, name)
+	}
+	return account, nil
+}
+
+// displayName names account in messages by its alias, or its email if it has none
+func displayName(account *domain.Account) string {
+	if account.Alias() != "" {
+		return account.Alias()
+	}
+	return string(account.Email())
+}
+
+// resolveTargetAccount finds the account named by input, archived or not
+func (s *SwitchAccountService) resolveTargetAccount(ctx context.Context, input SwitchAccountInput) (*domain.Account, error) {
+	if err := validateSwitchInput(input); err != nil {
+		return nil, err
+	}
+
 	// Handle Previous flag
 	if input.Previous {
-		// Ensure no other inputs are provided
-		if input.AccountID != "" || input.Email != "" || input.Alias != "" || input.Index > 0 {
-			return nil, errors.New("previous flag cannot be combined with other input methods")
-		}
 		return s.getPreviousAccount(ctx)
 	}
 
+	if input.UseDefault {
+		return s.getDefaultAccount(ctx)
+	}
+
+	if input.Back > 0 {
+		return s.getRecentAccount(ctx, input.Back)
+	}
+
+	if input.MostRecent {
+		return s.getMostRecentlyUsedAccount(ctx)
+	}
+
+	return s.resolver.Resolve(ctx, input.selector())
+}
+
+// selector returns the identifiers in input that name an account directly
+func (input SwitchAccountInput) selector() AccountSelector {
+	return AccountSelector{
+		AccountID: input.AccountID,
+		Email:     input.Email,
+		Alias:     input.Alias,
+		Index:     input.Index,
+		Prefix:    input.Prefix,
+	}
+}
+
+// validateSwitchInput checks that exactly one way of identifying the target is used
+func validateSwitchInput(input SwitchAccountInput) error {
 	// Count provided inputs
-	inputCount := 0
-	if input.AccountID != "" {
+	inputCount := input.selector().count()
+	if input.UseDefault {
 		inputCount++
 	}
-	if input.Email != "" {
+	if input.MostRecent {
 		inputCount++
 	}
-	if input.Alias != "" {
-		inputCount++
+	if input.Back < 0 {
+		return fmt.Errorf("invalid back count %d: must be positive", input.Back)
 	}
-	if input.Index > 0 {
+	if input.Back > 0 {
 		inputCount++
 	}
 
+	if input.Previous {
+		// Ensure no other inputs are provided
+		if inputCount > 0 {
+			return errors.New("previous flag cannot be combined with other input methods")
+		}
+		return nil
+	}
+
 	// Validate exactly one input
 	if inputCount == 0 {
-		return nil, errors.New("no account identifier provided")
+		return errors.New("no account identifier provided")
 	}
 	if inputCount > 1 {
-		return nil, errors.New("multiple account identifiers provided; use only one")
-	}
-
-	// Find account by the provided method
-	switch {
-	case input.AccountID != "":
-		return s.accounts.FindByID(ctx, domain.AccountID(input.AccountID))
-	case input.Email != "":
-		return s.accounts.FindByEmail(ctx, domain.Email(input.Email))
-	case input.Alias != "":
-		return s.accounts.FindByAlias(ctx, input.Alias)
-	case input.Index > 0:
-		return s.findByIndex(ctx, input.Index)
-	default:
-		return nil, errors.New("internal error: invalid input state")
+		return errors.New("multiple account identifiers provided; use only one")
 	}
+	return nil
 }
 
 // getPreviousAccount retrieves the previous account from history
@@ -183,14 +476,64 @@ func (s *SwitchAccountService) getPreviousAccount(ctx context.Context) (*domain.
 		return nil, errors.New("no previous account in history")
 	}
 
+	if s.previousMaxAge > 0 && s.now().Sub(lastSwitch.Timestamp()) > s.previousMaxAge {
+		return nil, fmt.Errorf("%w: last switch was at %s", ErrNoRecentSwitch, lastSwitch.Timestamp().Format(time.RFC3339))
+	}
+
 	// The "from" of the last switch is our target
 	return s.accounts.FindByEmail(ctx, lastSwitch.From())
 }
 
-// findByIndex finds an account by its position in the list (1-based)
-func (s *SwitchAccountService) findByIndex(ctx context.Context, index int) (*domain.Account, error) {
-	if index <= 0 {
-		return nil, fmt.Errorf("invalid index %d: must be positive", index)
+// getRecentAccount walks history like a directory stack and returns the nth most
+// recent account switched away from, ignoring repeats and the account switched to
+// last. Accounts that have since been removed or archived are skipped.
+func (s *SwitchAccountService) getRecentAccount(ctx context.Context, n int) (*domain.Account, error) {
+	history, err := s.history.LoadHistory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load history: %w", err)
+	}
+
+	entries := history.Entries()
+	if len(entries) == 0 {
+		return nil, errors.New("no previous account in history")
+	}
+
+	// The destination of the last switch is where we are now
+	seen := map[domain.Email]bool{entries[0].To(): true}
+	found := 0
+	for _, entry := range entries {
+		email := entry.From()
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+
+		account, err := s.accounts.FindByEmail(ctx, email)
+		if errors.Is(err, domain.ErrAccountNotFound) {
+			continue // Removed since the switch
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find account %s: %w", email, err)
+		}
+		if account.Archived() {
+			continue
+		}
+		found++
+		if found == n {
+			return account, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot go back %d: only %d recent accounts in history", n, found)
+}
+
+// getMostRecentlyUsedAccount returns the account other than the current one with the
+// latest last-used time. Unlike getRecentAccount it reads the accounts themselves, so it
+// still works after history is cleared. Ties go to the account listed first.
+func (s *SwitchAccountService) getMostRecentlyUsedAccount(ctx context.Context) (*domain.Account, error) {
+	current, err := s.config.GetCurrentAccount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current account: %w", err)
 	}
 
 	accounts, err := s.accounts.List(ctx)
@@ -198,21 +541,93 @@ func (s *SwitchAccountService) findByIndex(ctx context.Context, index int) (*dom
 		return nil, fmt.Errorf("failed to list accounts: %w", err)
 	}
 
-	if index > len(accounts) {
-		return nil, fmt.Errorf("index %d out of range (have %d accounts)", index, len(accounts))
+	// Claude config's account has no ccx ID, so find the managed account it refers to
+	managed := matchCurrentAccount(current, accounts)
+
+	var target *domain.Account
+	for _, account := range unarchived(accounts) {
+		if managed != nil && account.ID() == managed.ID() {
+			continue
+		}
+		if target == nil || account.LastUsed().After(target.LastUsed()) {
+			target = account
+		}
+	}
+	if target == nil && current != nil {
+		return nil, fmt.Errorf("%w: %s is the only managed account", ErrNoOtherAccount, current.Email())
+	}
+	if target == nil {
+		return nil, ErrNoOtherAccount
+	}
+	return target, nil
+}
+
+// getDefaultAccount resolves the default account recorded in ccx settings
+func (s *SwitchAccountService) getDefaultAccount(ctx context.Context) (*domain.Account, error) {
+	if s.settings == nil {
+		return nil, errors.New("default account is not available: no settings configured")
+	}
+
+	settings, err := s.settings.LoadSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+	if !settings.HasDefaultAccount() {
+		return nil, ErrNoDefaultAccount
+	}
+
+	account, err := s.accounts.FindByID(ctx, settings.DefaultAccountID())
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		return nil, fmt.Errorf("%w: %s was removed, set a new default account", ErrDefaultAccountMissing, settings.DefaultAccountID())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find default account: %w", err)
+	}
+	return account, nil
+}
+
+// reconcileHistory compares Claude's current account with the last switch target and,
+// if they differ, records the out-of-band change in history unless dryRun is set. It
+// reports whether they differed. History problems are only warned about, since the
+// switch itself does not depend on them.
+func (s *SwitchAccountService) reconcileHistory(ctx context.Context, current *domain.Account, dryRun bool) bool {
+	if current == nil {
+		return false
 	}
 
-	// Convert 1-based to 0-based index
-	return accounts[index-1], nil
+	history, err := s.history.LoadHistory(ctx)
+	if err != nil {
+		s.events.OnWarning(fmt.Errorf("failed to load history to check for outside switches: %w", err))
+		return false
+	}
+	if dryRun {
+		// Repositories may share the loaded history, so leave it untouched
+		history = history.Clone()
+	}
+	if !history.Reconcile(current.Email()) {
+		return false
+	}
+
+	if !dryRun {
+		if err := s.history.SaveHistory(ctx, history); err != nil {
+			s.events.OnWarning(fmt.Errorf("failed to record switch to %s made outside ccx: %w", current.Email(), err))
+		}
+	}
+	return true
 }
 
-// saveToHistory saves a switch entry to history
+// saveToHistory saves a switch entry to history, applying the configured history size
 func (s *SwitchAccountService) saveToHistory(ctx context.Context, from, to domain.Email) error {
+	size, configured := s.historySize(ctx)
+
 	// Load current history
 	history, err := s.history.LoadHistory(ctx)
 	if err != nil {
 		// If we can't load history, create a new one
-		history = domain.NewHistory(50) // Default to 50 entries
+		history = domain.NewHistory(size)
+	}
+	if configured {
+		history.SetMaxEntries(size)
 	}
 
 	// Create switch entry
@@ -228,13 +643,36 @@ func (s *SwitchAccountService) saveToHistory(ctx context.Context, from, to domai
 	return s.history.SaveHistory(ctx, history)
 }
 
-// accountToInfo converts a domain Account to AccountInfo DTO
-func (s *SwitchAccountService) accountToInfo(account *domain.Account) AccountInfo {
-	return AccountInfo{
-		ID:        string(account.ID()),
-		Email:     string(account.Email()),
-		Alias:     account.Alias(),
-		UUID:      account.UUID(),
-		CreatedAt: account.CreatedAt(),
+// recordCurrentAccount points ccx's own current-account record at id, if settings are
+// configured. Failures are reported as warnings.
+func (s *SwitchAccountService) recordCurrentAccount(ctx context.Context, id domain.AccountID) {
+	if s.settings == nil {
+		return
+	}
+
+	settings, err := s.settings.LoadSettings(ctx)
+	if err != nil {
+		s.events.OnWarning(fmt.Errorf("failed to load settings to record the current account: %w", err))
+		return
+	}
+	if settings.CurrentAccountID() == id {
+		return
+	}
+	settings.SetCurrentAccountID(id)
+	if err := s.settings.SaveSettings(ctx, settings); err != nil {
+		s.events.OnWarning(fmt.Errorf("failed to record the current account: %w", err))
+	}
+}
+
+// historySize returns the history size from ccx settings and whether one was read.
+// Without readable settings it falls back to domain.DefaultHistorySize.
+func (s *SwitchAccountService) historySize(ctx context.Context) (int, bool) {
+	if s.settings == nil {
+		return domain.DefaultHistorySize, false
+	}
+	settings, err := s.settings.LoadSettings(ctx)
+	if err != nil {
+		return domain.DefaultHistorySize, false
 	}
+	return settings.HistorySize(), true
 }
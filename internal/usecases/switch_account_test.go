@@ -2,8 +2,16 @@ package usecases_test
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/evanschultz/ccx/internal/domain"
 	"github.com/evanschultz/ccx/internal/usecases"
@@ -244,6 +252,205 @@ func TestSwitchAccountUseCase_Execute_Previous(t *testing.T) {
 	}
 }
 
+// TestSwitchAccountUseCase_Execute_SkipHistory tests that an ephemeral switch leaves
+// history alone, so Previous resolves to the account used before it
+func TestSwitchAccountUseCase_Execute_SkipHistory(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+
+	if _, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work"}); err != nil {
+		t.Fatalf("Execute(work) error = %v", err)
+	}
+	saveCalls := setup.historyRepo.saveCalls
+
+	result, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "test", SkipHistory: true})
+	if err != nil {
+		t.Fatalf("Execute(test) error = %v", err)
+	}
+	if result.From == nil || result.From.Email != testEmailWork || result.To.Email != testEmailTest {
+		t.Errorf("Switched %v -> %s, want %s -> %s", result.From, result.To.Email, testEmailWork, testEmailTest)
+	}
+	if setup.configManager.currentAccount.Email() != testEmailTest {
+		t.Errorf("Current account = %s, want %s", setup.configManager.currentAccount.Email(), testEmailTest)
+	}
+	if setup.historyRepo.saveCalls != saveCalls {
+		t.Errorf("History saved %d times by an ephemeral switch", setup.historyRepo.saveCalls-saveCalls)
+	}
+	if last := setup.historyRepo.history.GetLastSwitch(); last.To() != testEmailWork {
+		t.Errorf("Last switch went to %s, want %s", last.To(), testEmailWork)
+	}
+
+	result, err = setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Previous: true})
+	if err != nil {
+		t.Fatalf("Execute(previous) error = %v", err)
+	}
+	if result.To.Email != testEmailWork {
+		t.Errorf("Previous switched to %s, want %s", result.To.Email, testEmailWork)
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_OutOfBandSwitch tests that a login made outside ccx
+// is recorded so Previous returns to the account Claude was using before it
+func TestSwitchAccountUseCase_Execute_OutOfBandSwitch(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+
+	// ccx last switched personal -> work, then Claude was logged into test directly
+	entry, _ := domain.NewSwitchEntry(testEmailPersonal, testEmailWork)
+	setup.historyRepo.history.AddEntry(entry)
+	setup.configManager.currentAccount = setup.testAccounts["test"]
+
+	// A dry run reports the divergence without recording it
+	result, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Previous: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("Execute() dry run error = %v, want nil", err)
+	}
+	if !result.CurrentDivergedFromHistory {
+		t.Error("Expected dry run to report CurrentDivergedFromHistory")
+	}
+	if got := len(setup.historyRepo.history.Entries()); got != 1 || setup.historyRepo.saveCalls != 0 {
+		t.Fatalf("Dry run changed history: %d entries, %d saves", got, setup.historyRepo.saveCalls)
+	}
+
+	result, err = setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Previous: true})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if !result.CurrentDivergedFromHistory {
+		t.Error("Expected CurrentDivergedFromHistory to be true")
+	}
+	// Without reconciling, Previous would have gone to personal
+	if result.To.Email != testEmailWork {
+		t.Errorf("Expected to switch back to %s, got %s", testEmailWork, result.To.Email)
+	}
+
+	// History holds the outside switch followed by the switch just made
+	entries := setup.historyRepo.history.Entries()
+	want := []struct{ from, to domain.Email }{
+		{testEmailTest, testEmailWork},
+		{testEmailWork, testEmailTest},
+		{testEmailPersonal, testEmailWork},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d history entries, got %d", len(want), len(entries))
+	}
+	for i, w := range want {
+		if entries[i].From() != w.from || entries[i].To() != w.to {
+			t.Errorf("entry %d = %s -> %s, want %s -> %s", i, entries[i].From(), entries[i].To(), w.from, w.to)
+		}
+	}
+
+	// Now that history is in step, the next switch does not diverge
+	result, err = setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "personal"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.CurrentDivergedFromHistory {
+		t.Error("Expected CurrentDivergedFromHistory to be false after reconciling")
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_PreviousMaxAge tests the recent window for the previous toggle
+func TestSwitchAccountUseCase_Execute_PreviousMaxAge(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		opts    []usecases.SwitchAccountOption
+		wantErr error
+	}{
+		{"no window with stale switch", 72 * time.Hour, nil, nil},
+		{"recent switch within window", time.Minute, []usecases.SwitchAccountOption{usecases.WithPreviousMaxAge(10 * time.Minute)}, nil},
+		{"stale switch outside window", 2 * time.Hour, []usecases.SwitchAccountOption{usecases.WithPreviousMaxAge(10 * time.Minute)}, usecases.ErrNoRecentSwitch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupSwitchAccountTest()
+			ctx := context.Background()
+
+			// Record a switch from personal to work
+			entry, _ := domain.NewSwitchEntry(testEmailPersonal, testEmailWork)
+			setup.historyRepo.history.AddEntry(entry)
+			setup.configManager.currentAccount = setup.testAccounts["work"]
+
+			clock := func() time.Time { return entry.Timestamp().Add(tt.elapsed) }
+			opts := append([]usecases.SwitchAccountOption{usecases.WithSwitchClock(clock)}, tt.opts...)
+			useCase := usecases.NewSwitchAccountService(
+				setup.accountRepo,
+				setup.credentialStore,
+				setup.configManager,
+				setup.historyRepo,
+				opts...,
+			)
+
+			result, err := useCase.Execute(ctx, usecases.SwitchAccountInput{Previous: true})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Execute() error = %v, want %v", err, tt.wantErr)
+				}
+				if setup.configManager.currentAccount != setup.testAccounts["work"] {
+					t.Error("Current account should not change when previous is rejected")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Execute() error = %v, want nil", err)
+			}
+			if result.To.Email != testEmailPersonal {
+				t.Errorf("Expected to switch back to %s, got %s", testEmailPersonal, result.To.Email)
+			}
+		})
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_ReportsExpiry tests that credential expiry is surfaced without blocking the switch
+func TestSwitchAccountUseCase_Execute_ReportsExpiry(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		payload     string
+		wantExpires time.Time
+		wantExpired bool
+	}{
+		{"no expiry", `{"sessionKey":"key"}`, time.Time{}, false},
+		{"future expiry", `{"sessionKey":"key","sessionKeyExpiresAt":` + strconv.FormatInt(now.Add(time.Hour).Unix(), 10) + `}`, now.Add(time.Hour), false},
+		{"expired", `{"sessionKey":"key","sessionKeyExpiresAt":` + strconv.FormatInt(now.Add(-72*time.Hour).Unix(), 10) + `}`, now.Add(-72 * time.Hour), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupSwitchAccountTest()
+			work := setup.testAccounts["work"]
+			creds, _ := domain.NewCredentials(work.ID(), []byte(tt.payload))
+			_ = setup.credentialStore.Store(context.Background(), creds)
+
+			useCase := usecases.NewSwitchAccountService(
+				setup.accountRepo,
+				setup.credentialStore,
+				setup.configManager,
+				setup.historyRepo,
+				usecases.WithSwitchClock(func() time.Time { return now }),
+			)
+
+			result, err := useCase.Execute(context.Background(), usecases.SwitchAccountInput{Alias: "work"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v, want nil", err)
+			}
+			if setup.configManager.currentAccount != work {
+				t.Error("Switch should succeed regardless of expiry")
+			}
+			if !result.ExpiresAt.Equal(tt.wantExpires) {
+				t.Errorf("ExpiresAt = %v, want %v", result.ExpiresAt, tt.wantExpires)
+			}
+			if result.Expired != tt.wantExpired {
+				t.Errorf("Expired = %v, want %v", result.Expired, tt.wantExpired)
+			}
+		})
+	}
+}
+
 // TestSwitchAccountUseCase_Execute_FirstSwitch tests when no current account
 func TestSwitchAccountUseCase_Execute_FirstSwitch(t *testing.T) {
 	setup := setupSwitchAccountTest()
@@ -362,6 +569,13 @@ func TestSwitchAccountUseCase_Execute_AccountNotFound(t *testing.T) {
 	if result != nil {
 		t.Errorf("Expected nil result on error, got %+v", result)
 	}
+
+	// A near miss carries suggestions
+	_, err = setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "wrok"})
+	var notFound *usecases.AccountNotFoundError
+	if !errors.As(err, &notFound) || !slices.Equal(notFound.Suggestions, []string{"work"}) {
+		t.Errorf("Execute() error = %v, want a suggestion of work", err)
+	}
 }
 
 // TestSwitchAccountUseCase_Execute_CredentialsNotFound tests when credentials missing
@@ -425,6 +639,80 @@ func TestSwitchAccountUseCase_Execute_ConfigUpdateFailure(t *testing.T) {
 	}
 }
 
+// TestSwitchAccountUseCase_Execute_WritesCredentials tests that the target's session is
+// written to Claude config along with the account
+func TestSwitchAccountUseCase_Execute_WritesCredentials(t *testing.T) {
+	setup := setupSwitchAccountTest()
+
+	if _, err := setup.useCase.Execute(context.Background(), usecases.SwitchAccountInput{Alias: "work"}); err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	want := setup.testCredentials[setup.testAccounts["work"].ID()]
+	if setup.configManager.credentials != want {
+		t.Errorf("Config credentials = %v, want work's credentials", setup.configManager.credentials)
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_MissingCredentialsNamesAccount tests that an account
+// without an alias is named by its email when its credentials can't be retrieved
+func TestSwitchAccountUseCase_Execute_MissingCredentialsNamesAccount(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+	noAlias, _ := domain.NewAccount("noalias@example.com", "", "uuid-noalias")
+	_ = setup.accountRepo.Save(ctx, noAlias)
+
+	_, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Email: "noalias@example.com"})
+	if !errors.Is(err, domain.ErrCredentialsNotFound) {
+		t.Fatalf("Execute() error = %v, want ErrCredentialsNotFound", err)
+	}
+	if !strings.Contains(err.Error(), "account noalias@example.com:") {
+		t.Errorf("Execute() error = %q, want it to name the account by email", err)
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_CredentialsWriteFailure tests that the previous account
+// is restored when the session cannot be written
+func TestSwitchAccountUseCase_Execute_CredentialsWriteFailure(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	setup.configManager.credsErr = errors.New("config file locked")
+
+	result, err := setup.useCase.Execute(context.Background(), usecases.SwitchAccountInput{Alias: "work"})
+	if !errors.Is(err, setup.configManager.credsErr) {
+		t.Fatalf("Execute() error = %v, want credentials write error", err)
+	}
+	if result != nil {
+		t.Errorf("Expected nil result on error, got %+v", result)
+	}
+
+	if current := setup.configManager.currentAccount; current == nil || current.ID() != setup.testAccounts["personal"].ID() {
+		t.Errorf("Current account = %v, want personal restored", current)
+	}
+	if setup.historyRepo.saveCalls != 0 {
+		t.Error("History should not be saved when the switch is rolled back")
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_CredentialsWriteFailureNamesAccount tests that an
+// account without an alias is named by its email when its session can't be written
+func TestSwitchAccountUseCase_Execute_CredentialsWriteFailureNamesAccount(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+	noAlias, _ := domain.NewAccount("noalias@example.com", "", "uuid-noalias")
+	_ = setup.accountRepo.Save(ctx, noAlias)
+	creds, _ := domain.NewCredentials(noAlias.ID(), []byte(`{"sessionKey": "key-noalias"}`))
+	_ = setup.credentialStore.Store(ctx, creds)
+	setup.configManager.credsErr = errors.New("config file locked")
+
+	_, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Email: "noalias@example.com"})
+	if !errors.Is(err, setup.configManager.credsErr) {
+		t.Fatalf("Execute() error = %v, want credentials write error", err)
+	}
+	if !strings.Contains(err.Error(), "account noalias@example.com:") {
+		t.Errorf("Execute() error = %q, want it to name the account by email", err)
+	}
+}
+
 // TestSwitchAccountUseCase_Execute_HistoryFailure tests history save failure
 func TestSwitchAccountUseCase_Execute_HistoryFailure(t *testing.T) {
 	setup := setupSwitchAccountTest()
@@ -478,6 +766,42 @@ func TestSwitchAccountUseCase_Execute_ContextCancellation(t *testing.T) {
 	}
 }
 
+// cancellingConfigManager cancels the operation's context once the current account is set,
+// as a Ctrl-C between the switch's two config writes would
+type cancellingConfigManager struct {
+	*mockConfigManager
+	cancel context.CancelFunc
+}
+
+func (m *cancellingConfigManager) SetCurrentAccount(ctx context.Context, account *domain.Account) error {
+	err := m.mockConfigManager.SetCurrentAccount(ctx, account)
+	m.cancel()
+	return err
+}
+
+// TestSwitchAccountUseCase_Execute_CancelledMidSwitch tests that cancelling after the
+// first config write restores the previous account instead of leaving a half switch
+func TestSwitchAccountUseCase_Execute_CancelledMidSwitch(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	previous := setup.configManager.currentAccount
+
+	configManager := &cancellingConfigManager{mockConfigManager: setup.configManager, cancel: cancel}
+	useCase := usecases.NewSwitchAccountService(setup.accountRepo, setup.credentialStore, configManager, setup.historyRepo)
+
+	_, err := useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Execute() error = %v, want %v", err, context.Canceled)
+	}
+	if setup.configManager.currentAccount != previous {
+		t.Errorf("current account = %v, want the previous account restored", setup.configManager.currentAccount)
+	}
+	if setup.configManager.credentials != nil {
+		t.Error("credentials were written after cancellation")
+	}
+}
+
 // TestSwitchAccountUseCase_Execute_PreviousWithNoHistory tests previous when no history
 func TestSwitchAccountUseCase_Execute_PreviousWithNoHistory(t *testing.T) {
 	setup := setupSwitchAccountTest()
@@ -560,3 +884,692 @@ func TestSwitchAccountUseCase_Execute_InvalidIndex(t *testing.T) {
 		})
 	}
 }
+
+// TestSwitchAccountUseCase_Execute_MarksUsed tests that a switch persists the target's lastUsed
+func TestSwitchAccountUseCase_Execute_MarksUsed(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+
+	work := setup.testAccounts["work"]
+	before := work.LastUsed()
+	time.Sleep(time.Millisecond)
+
+	result, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	saved, _ := setup.accountRepo.FindByID(ctx, work.ID())
+	if !saved.LastUsed().After(before) {
+		t.Errorf("LastUsed = %v, want after %v", saved.LastUsed(), before)
+	}
+	if !result.To.LastUsed.Equal(saved.LastUsed()) {
+		t.Errorf("result LastUsed = %v, want %v", result.To.LastUsed, saved.LastUsed())
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_MarkUsedFailure tests that failing to persist lastUsed
+// does not fail the switch
+func TestSwitchAccountUseCase_Execute_MarkUsedFailure(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+
+	setup.accountRepo.saveErr = errors.New("disk full")
+
+	result, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work"})
+	if err != nil {
+		t.Fatalf("Expected success despite lastUsed save failure, got error: %v", err)
+	}
+	if result.To.Email != testEmailWork {
+		t.Errorf("Expected switch to %s, got %s", testEmailWork, result.To.Email)
+	}
+	if setup.configManager.currentAccount.Email() != testEmailWork {
+		t.Error("Config should be updated even if lastUsed save fails")
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_ByPrefix tests switching by an alias or email prefix
+func TestSwitchAccountUseCase_Execute_ByPrefix(t *testing.T) {
+	tests := []struct {
+		name      string
+		prefix    string
+		wantEmail string
+		wantErr   bool
+	}{
+		{"alias prefix", "te", testEmailTest, false},
+		{"case-insensitive", "WO", testEmailWork, false},
+		{"email prefix", "work@", testEmailWork, false},
+		{"no match", "xyz", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupSwitchAccountTest()
+
+			result, err := setup.useCase.Execute(context.Background(), usecases.SwitchAccountInput{Prefix: tt.prefix})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && result.To.Email != tt.wantEmail {
+				t.Errorf("Expected switch to %s, got %s", tt.wantEmail, result.To.Email)
+			}
+		})
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_AmbiguousPrefix tests that an ambiguous prefix lists candidates
+func TestSwitchAccountUseCase_Execute_AmbiguousPrefix(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+
+	worker, _ := domain.NewAccount("worker@corp.com", "contractor", "uuid-worker")
+	_ = setup.accountRepo.Save(ctx, worker)
+
+	_, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Prefix: "wo"})
+	var ambiguous *usecases.AmbiguousPrefixError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Execute() error = %v, want AmbiguousPrefixError", err)
+	}
+	if len(ambiguous.Candidates) != 2 ||
+		ambiguous.Candidates[0].Email != testEmailWork || ambiguous.Candidates[1].Email != "worker@corp.com" {
+		t.Errorf("Candidates = %+v, want work and worker sorted by email", ambiguous.Candidates)
+	}
+	if setup.configManager.currentAccount.Email() != testEmailPersonal {
+		t.Error("Config should not change on an ambiguous prefix")
+	}
+
+	// Prefix counts as an identifier for mutual exclusion
+	_, err = setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Prefix: "wo", Alias: "work"})
+	if err == nil {
+		t.Error("Execute() with Prefix and Alias error = nil, want error")
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_RecordsCurrentAccount tests that ccx's own pointer
+// follows every switch and that failing to record it only warns
+func TestSwitchAccountUseCase_Execute_RecordsCurrentAccount(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+	settingsRepo := newMockSettingsRepository()
+	events := &recordingEventSink{}
+
+	useCase := usecases.NewSwitchAccountService(setup.accountRepo, setup.credentialStore,
+		setup.configManager, setup.historyRepo, usecases.WithSwitchSettings(settingsRepo), usecases.WithSwitchEvents(events))
+
+	if _, err := useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work", DryRun: true}); err != nil {
+		t.Fatalf("dry run Execute() error = %v", err)
+	}
+	if settingsRepo.saveCalls != 0 {
+		t.Error("dry run recorded the current account")
+	}
+
+	if _, err := useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got := settingsRepo.settings.CurrentAccountID(); got != setup.testAccounts["work"].ID() {
+		t.Errorf("CurrentAccountID() = %s, want the work account", got)
+	}
+
+	settingsRepo.saveErr = errors.New("settings file locked")
+	if _, err := useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "test"}); err != nil {
+		t.Fatalf("Execute() with unwritable settings error = %v, want nil", err)
+	}
+	if setup.configManager.currentAccount.ID() != setup.testAccounts["test"].ID() {
+		t.Error("Claude config should be switched even if the pointer can't be recorded")
+	}
+	if len(events.warnings) == 0 {
+		t.Error("failing to record the current account should warn")
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_UseDefault tests switching to the default account
+func TestSwitchAccountUseCase_Execute_UseDefault(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+	settingsRepo := newMockSettingsRepository()
+
+	useCase := usecases.NewSwitchAccountService(setup.accountRepo, setup.credentialStore,
+		setup.configManager, setup.historyRepo, usecases.WithSwitchSettings(settingsRepo))
+	input := usecases.SwitchAccountInput{UseDefault: true}
+
+	// No default set
+	if _, err := useCase.Execute(ctx, input); !errors.Is(err, usecases.ErrNoDefaultAccount) {
+		t.Errorf("Execute() error = %v, want ErrNoDefaultAccount", err)
+	}
+
+	// Default set
+	settingsRepo.settings.SetDefaultAccountID(setup.testAccounts["work"].ID())
+	result, err := useCase.Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.To.Email != testEmailWork {
+		t.Errorf("Expected switch to %s, got %s", testEmailWork, result.To.Email)
+	}
+
+	// Default account was removed
+	_ = setup.accountRepo.Delete(ctx, setup.testAccounts["work"].ID())
+	_, err = useCase.Execute(ctx, input)
+	if !errors.Is(err, usecases.ErrDefaultAccountMissing) || !strings.Contains(err.Error(), "set a new default") {
+		t.Errorf("Execute() error = %v, want ErrDefaultAccountMissing asking for a new default", err)
+	}
+
+	// UseDefault is exclusive with other identifiers
+	if _, err := useCase.Execute(ctx, usecases.SwitchAccountInput{UseDefault: true, Alias: "test"}); err == nil {
+		t.Error("Execute() with UseDefault and Alias error = nil, want error")
+	}
+
+	// Without settings the default cannot be resolved
+	if _, err := setup.useCase.Execute(ctx, input); err == nil {
+		t.Error("Execute() without settings error = nil, want error")
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_Back tests rotating through recent accounts
+func TestSwitchAccountUseCase_Execute_Back(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+
+	// personal -> work -> test -> work leaves test and personal as the recent others
+	for _, alias := range []string{"work", "test", "work"} {
+		if _, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: alias}); err != nil {
+			t.Fatalf("Execute(%s) error = %v", alias, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		back    int
+		want    string
+		wantErr bool
+	}{
+		{"one back", 1, testEmailTest, false},
+		{"two back skips repeats", 2, testEmailPersonal, false},
+		{"beyond history", 3, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preflight := usecases.NewPreflightSwitchService(setup.accountRepo, setup.credentialStore, setup.configManager, setup.historyRepo)
+			result, err := preflight.Execute(ctx, usecases.SwitchAccountInput{Back: tt.back})
+			if err != nil {
+				t.Fatalf("preflight error = %v", err)
+			}
+			if tt.wantErr {
+				if result.Target != nil {
+					t.Errorf("Expected no target, got %s", result.Target.Email)
+				}
+				return
+			}
+			if result.Target == nil || result.Target.Email != tt.want {
+				t.Errorf("Back %d target = %+v, want %s", tt.back, result.Target, tt.want)
+			}
+		})
+	}
+
+	// Going two back performs the switch and reports both sides
+	result, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Back: 2})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.From == nil || result.From.Email != testEmailWork || result.To.Email != testEmailPersonal {
+		t.Errorf("Switched %v -> %s, want %s -> %s", result.From, result.To.Email, testEmailWork, testEmailPersonal)
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_BackSkipsRemoved tests that removed accounts in
+// history are skipped
+func TestSwitchAccountUseCase_Execute_BackSkipsRemoved(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+
+	for _, alias := range []string{"work", "test"} {
+		if _, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: alias}); err != nil {
+			t.Fatalf("Execute(%s) error = %v", alias, err)
+		}
+	}
+	_ = setup.accountRepo.Delete(ctx, setup.testAccounts["work"].ID())
+
+	result, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Back: 1})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.To.Email != testEmailPersonal {
+		t.Errorf("Expected to skip removed account and switch to %s, got %s", testEmailPersonal, result.To.Email)
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_BackSkipsArchived tests that archived accounts in
+// history are skipped, as the other selectors skip them
+func TestSwitchAccountUseCase_Execute_BackSkipsArchived(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+
+	for _, alias := range []string{"work", "test"} {
+		if _, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: alias}); err != nil {
+			t.Fatalf("Execute(%s) error = %v", alias, err)
+		}
+	}
+	work := setup.testAccounts["work"].Clone()
+	work.Archive(time.Now())
+	_ = setup.accountRepo.Save(ctx, work)
+
+	result, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Back: 1})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.To.Email != testEmailPersonal {
+		t.Errorf("Expected to skip archived account and switch to %s, got %s", testEmailPersonal, result.To.Email)
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_BackValidation tests invalid Back inputs
+func TestSwitchAccountUseCase_Execute_BackValidation(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+
+	for _, input := range []usecases.SwitchAccountInput{
+		{Back: -1},
+		{Back: 1, Previous: true},
+		{Back: 1, Alias: "work"},
+		{Back: 1}, // No history yet
+	} {
+		if _, err := setup.useCase.Execute(ctx, input); err == nil {
+			t.Errorf("Execute(%+v) error = nil, want error", input)
+		}
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_MostRecent tests switching to the other account with
+// the latest lastUsed, which works without any history
+func TestSwitchAccountUseCase_Execute_MostRecent(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+	now := time.Now()
+
+	// The current account is the most recently used, so it must be ignored
+	lastUsed := map[string]time.Time{
+		"personal": now,
+		"work":     now.Add(-time.Hour),
+		"test":     now.Add(-2 * time.Hour),
+	}
+	for alias, used := range lastUsed {
+		original := setup.testAccounts[alias]
+		account, err := domain.ReconstructAccount(original.ID(), string(original.Email()), alias,
+			"uuid-"+alias, nil, now.Add(-72*time.Hour), used)
+		if err != nil {
+			t.Fatalf("ReconstructAccount(%s) error = %v", alias, err)
+		}
+		_ = setup.accountRepo.Save(ctx, account)
+	}
+
+	result, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{MostRecent: true})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.To.Email != testEmailWork {
+		t.Errorf("Switched to %s, want %s", result.To.Email, testEmailWork)
+	}
+
+	// Work is now current and personal the most recent of the others
+	result, err = setup.useCase.Execute(ctx, usecases.SwitchAccountInput{MostRecent: true})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.To.Email != testEmailPersonal {
+		t.Errorf("Switched to %s, want %s", result.To.Email, testEmailPersonal)
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_MostRecentOnlyCurrent tests that MostRecent fails
+// clearly when the current account is the only one
+func TestSwitchAccountUseCase_Execute_MostRecentOnlyCurrent(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+	_ = setup.accountRepo.Delete(ctx, setup.testAccounts["work"].ID())
+	_ = setup.accountRepo.Delete(ctx, setup.testAccounts["test"].ID())
+
+	_, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{MostRecent: true})
+	if !errors.Is(err, usecases.ErrNoOtherAccount) {
+		t.Errorf("Execute() error = %v, want ErrNoOtherAccount", err)
+	}
+
+	for _, input := range []usecases.SwitchAccountInput{
+		{MostRecent: true, Previous: true},
+		{MostRecent: true, Alias: "personal"},
+		{MostRecent: true, Back: 1},
+	} {
+		if _, err := setup.useCase.Execute(ctx, input); err == nil || errors.Is(err, usecases.ErrNoOtherAccount) {
+			t.Errorf("Execute(%+v) error = %v, want input validation error", input, err)
+		}
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_Archived tests that archived accounts are skipped by
+// index, prefix, and most-recent resolution and refused when named directly
+func TestSwitchAccountUseCase_Execute_Archived(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+	setup.testAccounts["work"].Archive(time.Now())
+
+	for _, input := range []usecases.SwitchAccountInput{
+		{Alias: "work"},
+		{Email: testEmailWork},
+		{AccountID: string(setup.testAccounts["work"].ID())},
+	} {
+		if _, err := setup.useCase.Execute(ctx, input); !errors.Is(err, usecases.ErrAccountArchived) {
+			t.Errorf("Execute(%+v) error = %v, want ErrAccountArchived", input, err)
+		}
+	}
+
+	// Only personal and test remain, so index 3 is out of range and "w" matches nothing
+	if _, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Index: 3}); err == nil {
+		t.Error("Execute(Index: 3) error = nil, want out of range")
+	}
+	if _, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Prefix: "w"}); err == nil || errors.Is(err, usecases.ErrAccountArchived) {
+		t.Errorf("Execute(Prefix: w) error = %v, want no match", err)
+	}
+
+	result, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{MostRecent: true})
+	if err != nil {
+		t.Fatalf("Execute(MostRecent) error = %v", err)
+	}
+	if result.To.Email != testEmailTest {
+		t.Errorf("MostRecent switched to %s, want %s", result.To.Email, testEmailTest)
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_HistorySize tests that history keeps the configured
+// number of switches
+func TestSwitchAccountUseCase_Execute_HistorySize(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("configured size applied to loaded history", func(t *testing.T) {
+		setup := setupSwitchAccountTest()
+		settingsRepo := newMockSettingsRepository()
+		_ = settingsRepo.settings.SetHistorySize(2)
+		useCase := usecases.NewSwitchAccountService(setup.accountRepo, setup.credentialStore,
+			setup.configManager, setup.historyRepo, usecases.WithSwitchSettings(settingsRepo))
+
+		for _, alias := range []string{"work", "test", "personal", "work"} {
+			if _, err := useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: alias}); err != nil {
+				t.Fatalf("Execute(%s) error = %v", alias, err)
+			}
+		}
+
+		if got := setup.historyRepo.history.MaxEntries(); got != 2 {
+			t.Errorf("MaxEntries() = %d, want 2", got)
+		}
+		if got := len(setup.historyRepo.history.Entries()); got != 2 {
+			t.Errorf("history has %d entries, want 2", got)
+		}
+	})
+
+	t.Run("unreadable history replaced with default size", func(t *testing.T) {
+		setup := setupSwitchAccountTest()
+		setup.historyRepo.loadErr = errors.New("history corrupted")
+
+		if _, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work"}); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if got := setup.historyRepo.history.MaxEntries(); got != domain.DefaultHistorySize {
+			t.Errorf("MaxEntries() = %d, want %d", got, domain.DefaultHistorySize)
+		}
+	})
+}
+
+// TestSwitchAccountUseCase_Execute_CorruptCredentials tests that credentials which no
+// longer decrypt block the switch before the config is touched
+func TestSwitchAccountUseCase_Execute_CorruptCredentials(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	work := setup.testAccounts["work"]
+
+	// Flip a byte of the ciphertext so authentication fails
+	blob := setup.testCredentials[work.ID()].EncryptedData()
+	blob[len(blob)-1] ^= 0xff
+	serialized := `{"accountId":"` + string(work.ID()) + `","encryptedData":"` + base64.StdEncoding.EncodeToString(blob) + `"}`
+	corrupt, err := domain.DeserializeCredentials([]byte(serialized))
+	if err != nil {
+		t.Fatalf("failed to deserialize credentials: %v", err)
+	}
+	_ = setup.credentialStore.Store(ctx, corrupt)
+
+	_, err = setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work"})
+	if !errors.Is(err, usecases.ErrCredentialsCorrupt) {
+		t.Fatalf("Execute() error = %v, want ErrCredentialsCorrupt", err)
+	}
+	if !strings.Contains(err.Error(), "work") {
+		t.Errorf("Error should name the account, got: %v", err)
+	}
+
+	if setup.configManager.currentAccount.Email() != testEmailPersonal {
+		t.Errorf("Config changed to %s, want it left at %s", setup.configManager.currentAccount.Email(), testEmailPersonal)
+	}
+	if setup.historyRepo.saveCalls != 0 {
+		t.Error("History should not be saved for a blocked switch")
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_PassphraseProtected tests that passphrase-protected
+// credentials, which Claude config can't be given, block the switch before the config
+// is touched
+func TestSwitchAccountUseCase_Execute_PassphraseProtected(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	work := setup.testAccounts["work"]
+
+	protected, _ := domain.NewCredentialsWithPassphrase(work.ID(), []byte(`{"sessionKey": "key-work"}`), []byte("passphrase"))
+	serialized, _ := protected.Serialize()
+	protected, _ = domain.DeserializeCredentials(serialized)
+	_ = setup.credentialStore.Store(ctx, protected)
+
+	_, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work"})
+	if !errors.Is(err, domain.ErrPassphraseRequired) {
+		t.Fatalf("Execute() error = %v, want ErrPassphraseRequired", err)
+	}
+	if errors.Is(err, usecases.ErrCredentialsCorrupt) {
+		t.Errorf("Execute() error = %v, should not report the credentials as corrupt", err)
+	}
+	if setup.configManager.currentAccount.Email() != testEmailPersonal {
+		t.Errorf("Config changed to %s, want it left at %s", setup.configManager.currentAccount.Email(), testEmailPersonal)
+	}
+}
+
+// stubCredentialValidator rejects credentials for the accounts in rejected
+type stubCredentialValidator struct {
+	rejected  map[domain.AccountID]error
+	validated []domain.AccountID
+}
+
+func (v *stubCredentialValidator) Validate(_ context.Context, creds *domain.Credentials) error {
+	v.validated = append(v.validated, creds.AccountID())
+	return v.rejected[creds.AccountID()]
+}
+
+// TestSwitchAccountUseCase_Execute_CredentialValidator tests that rejected credentials
+// block the switch before Claude's config is changed
+func TestSwitchAccountUseCase_Execute_CredentialValidator(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	work, test := setup.testAccounts["work"], setup.testAccounts["test"]
+	errExpired := errors.New("session expired")
+	validator := &stubCredentialValidator{rejected: map[domain.AccountID]error{work.ID(): errExpired}}
+	useCase := usecases.NewSwitchAccountService(setup.accountRepo, setup.credentialStore, setup.configManager,
+		setup.historyRepo, usecases.WithCredentialValidator(validator))
+
+	_, err := useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work"})
+	if !errors.Is(err, usecases.ErrCredentialsRejected) || !errors.Is(err, errExpired) {
+		t.Fatalf("Execute() error = %v, want ErrCredentialsRejected wrapping the validator's error", err)
+	}
+	if !strings.Contains(err.Error(), "work") {
+		t.Errorf("Error should name the account, got: %v", err)
+	}
+	if setup.configManager.currentAccount.Email() != testEmailPersonal {
+		t.Errorf("Config changed to %s, want it left at %s", setup.configManager.currentAccount.Email(), testEmailPersonal)
+	}
+	if setup.configManager.credentials != nil {
+		t.Error("Credentials should not be written for a rejected switch")
+	}
+	if setup.historyRepo.saveCalls != 0 {
+		t.Error("History should not be saved for a rejected switch")
+	}
+
+	// A dry run reports the rejection too
+	if _, err := useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work", DryRun: true}); !errors.Is(err, usecases.ErrCredentialsRejected) {
+		t.Errorf("Execute(dry run) error = %v, want ErrCredentialsRejected", err)
+	}
+
+	result, err := useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "test"})
+	if err != nil {
+		t.Fatalf("Execute(test) error = %v", err)
+	}
+	if result.To.Email != testEmailTest || setup.configManager.currentAccount.Email() != testEmailTest {
+		t.Errorf("Switched to %s, want %s", result.To.Email, testEmailTest)
+	}
+	if want := []domain.AccountID{work.ID(), work.ID(), test.ID()}; !slices.Equal(validator.validated, want) {
+		t.Errorf("Validated %v, want %v", validator.validated, want)
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_EnvExports tests that the target account's
+// environment overrides are returned for the caller to export, without touching the
+// process environment
+func TestSwitchAccountUseCase_Execute_EnvExports(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	work := setup.testAccounts["work"]
+	env := map[string]string{
+		"HTTPS_PROXY":        "http://proxy.corp:8080",
+		"ANTHROPIC_BASE_URL": "https://gateway.corp/claude",
+	}
+	if err := work.SetEnvOverrides(env); err != nil {
+		t.Fatalf("SetEnvOverrides() error = %v", err)
+	}
+	_ = setup.accountRepo.Save(ctx, work)
+	t.Setenv("HTTPS_PROXY", "unchanged")
+
+	want := []string{"ANTHROPIC_BASE_URL=https://gateway.corp/claude", "HTTPS_PROXY=http://proxy.corp:8080"}
+	for _, dryRun := range []bool{true, false, false} {
+		// The second real switch is a no-op, which still returns the exports
+		result, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work", DryRun: dryRun})
+		if err != nil {
+			t.Fatalf("Execute(dry run %v) error = %v, want nil", dryRun, err)
+		}
+		if !slices.Equal(result.EnvExports, want) {
+			t.Errorf("EnvExports = %v, want %v", result.EnvExports, want)
+		}
+		saved, _ := setup.accountRepo.FindByID(ctx, work.ID())
+		if got := saved.EnvOverrides(); !maps.Equal(got, env) {
+			t.Errorf("EnvOverrides() after switch = %v, want %v", got, env)
+		}
+	}
+	if got := os.Getenv("HTTPS_PROXY"); got != "unchanged" {
+		t.Errorf("HTTPS_PROXY = %q, want the process environment left alone", got)
+	}
+}
+
+// TestSwitchAccountUseCase_Execute_DryRun tests that a dry run validates the switch
+// without changing config, history, or last-used time
+func TestSwitchAccountUseCase_Execute_DryRun(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	sink := &recordingEventSink{}
+	useCase := usecases.NewSwitchAccountService(setup.accountRepo, setup.credentialStore, setup.configManager,
+		setup.historyRepo, usecases.WithSwitchEvents(sink))
+	work := setup.testAccounts["work"]
+	lastUsed := work.LastUsed()
+
+	result, err := useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work", DryRun: true})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if !result.DryRun || result.To.Email != testEmailWork || result.From == nil || result.From.Email != testEmailPersonal {
+		t.Errorf("Execute() result = %+v, want dry run from personal to work", result)
+	}
+	if len(result.EnvExports) != 0 {
+		t.Errorf("EnvExports = %v, want none for an account without overrides", result.EnvExports)
+	}
+
+	if setup.configManager.currentAccount.Email() != testEmailPersonal {
+		t.Errorf("Config changed to %s by a dry run", setup.configManager.currentAccount.Email())
+	}
+	if setup.historyRepo.saveCalls != 0 {
+		t.Errorf("History saved %d times during a dry run", setup.historyRepo.saveCalls)
+	}
+	if !work.LastUsed().Equal(lastUsed) {
+		t.Error("LastUsed changed during a dry run")
+	}
+	if len(sink.switches) != 0 {
+		t.Error("OnSwitch should not be called for a dry run")
+	}
+
+	// Validation still runs: missing credentials fail the dry run without a failure event
+	_ = setup.credentialStore.Delete(ctx, work.ID())
+	if _, err := useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work", DryRun: true}); err == nil {
+		t.Error("Execute() dry run without credentials error = nil, want error")
+	}
+	if len(sink.failures) != 0 {
+		t.Error("OnSwitchFailed should not be called for a dry run")
+	}
+}
+
+// sharedAccountRepository hands every caller the same account pointers, as a caching
+// repository would. It is safe for concurrent use.
+type sharedAccountRepository struct {
+	*mockAccountRepository
+	mu sync.RWMutex
+}
+
+func (r *sharedAccountRepository) Save(ctx context.Context, account *domain.Account) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.mockAccountRepository.Save(ctx, account)
+}
+
+func (r *sharedAccountRepository) FindByID(ctx context.Context, id domain.AccountID) (*domain.Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.mockAccountRepository.FindByID(ctx, id)
+}
+
+// TestSwitchAccountUseCase_Execute_ConcurrentReaders tests that switching never mutates
+// an account another goroutine may be reading. Run with -race.
+func TestSwitchAccountUseCase_Execute_ConcurrentReaders(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	repo := &sharedAccountRepository{mockAccountRepository: setup.accountRepo}
+	work := setup.testAccounts["work"]
+
+	var wg sync.WaitGroup
+	for range 8 {
+		// Each switcher has its own config and history; only the repository is shared
+		configManager := newMockConfigManager()
+		configManager.currentAccount = setup.testAccounts["personal"]
+		useCase := usecases.NewSwitchAccountService(repo, setup.credentialStore, configManager, newMockHistoryRepository())
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := useCase.Execute(context.Background(), usecases.SwitchAccountInput{AccountID: string(work.ID())})
+			if err != nil {
+				t.Errorf("Execute() error = %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			account, err := repo.FindByID(context.Background(), work.ID())
+			if err != nil {
+				t.Errorf("FindByID() error = %v", err)
+				return
+			}
+			_ = account.LastUsed()
+			_ = account.Alias()
+		}()
+	}
+	wg.Wait()
+
+	saved, _ := repo.FindByID(context.Background(), work.ID())
+	if saved == work {
+		t.Error("Switch saved the shared account instead of a copy")
+	}
+}
@@ -0,0 +1,75 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// SwitchProfileUseCase defines the interface for switching to a named profile
+type SwitchProfileUseCase interface {
+	Execute(ctx context.Context, input SwitchProfileInput) (*SwitchAccountResult, error)
+}
+
+// SwitchProfileInput contains the input data for switching profiles
+type SwitchProfileInput struct {
+	Name   string // Profile to switch to
+	DryRun bool   // Resolve and validate the switch without changing anything
+}
+
+// SwitchProfileService implements the SwitchProfileUseCase
+type SwitchProfileService struct {
+	profiles ports.ProfileRepository
+	switcher *SwitchAccountService
+}
+
+// Ensure SwitchProfileService implements SwitchProfileUseCase at compile time
+var _ SwitchProfileUseCase = (*SwitchProfileService)(nil)
+
+// NewSwitchProfileService creates a new SwitchProfileService. Options are the same as
+// for SwitchAccountService, which performs the switch.
+func NewSwitchProfileService(
+	profiles ports.ProfileRepository,
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+	config ports.ConfigManager,
+	history ports.HistoryRepository,
+	opts ...SwitchAccountOption,
+) SwitchProfileUseCase {
+	return &SwitchProfileService{
+		profiles: profiles,
+		switcher: newSwitchAccountService(accounts, credentials, config, history, opts...),
+	}
+}
+
+// Execute resolves the profile to its account and switches to it exactly as a switch by
+// account ID would, including history and events
+func (s *SwitchProfileService) Execute(ctx context.Context, input SwitchProfileInput) (*SwitchAccountResult, error) {
+	if input.Name == "" {
+		return nil, errors.New("profile name is required")
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	profile, err := s.profiles.FindByName(ctx, input.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find profile %s: %w", input.Name, err)
+	}
+
+	result, err := s.switcher.Execute(ctx, SwitchAccountInput{
+		AccountID: string(profile.AccountID()),
+		DryRun:    input.DryRun,
+	})
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		return nil, fmt.Errorf("profile %s points at account %s, which no longer exists: %w", profile.Name(), profile.AccountID(), err)
+	}
+	return result, err
+}
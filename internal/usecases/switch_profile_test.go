@@ -0,0 +1,66 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// TestSwitchProfileUseCase_Execute tests switching to the account a profile points at
+func TestSwitchProfileUseCase_Execute(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+	work := setup.testAccounts["work"]
+
+	profileRepo := newMockProfileRepository()
+	seedProfile(profileRepo, "client-a", work)
+	useCase := usecases.NewSwitchProfileService(profileRepo, setup.accountRepo, setup.credentialStore,
+		setup.configManager, setup.historyRepo)
+
+	result, err := useCase.Execute(ctx, usecases.SwitchProfileInput{Name: "client-a", DryRun: true})
+	if err != nil {
+		t.Fatalf("Execute() dry run error = %v, want nil", err)
+	}
+	if !result.DryRun || setup.configManager.currentAccount == work {
+		t.Error("Dry run should not change the current account")
+	}
+
+	result, err = useCase.Execute(ctx, usecases.SwitchProfileInput{Name: "client-a"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.To.Email != testEmailWork || setup.configManager.currentAccount != work {
+		t.Errorf("Switched to %s, want %s", result.To.Email, testEmailWork)
+	}
+	if last := setup.historyRepo.history.GetLastSwitch(); last == nil || last.To() != testEmailWork {
+		t.Error("Profile switch was not recorded in history")
+	}
+}
+
+// TestSwitchProfileUseCase_Execute_Errors tests unknown and orphaned profiles
+func TestSwitchProfileUseCase_Execute_Errors(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	ctx := context.Background()
+
+	removed, _ := domain.NewAccount("removed@example.com", "removed", "uuid-removed")
+	profileRepo := newMockProfileRepository()
+	seedProfile(profileRepo, "old", removed)
+	useCase := usecases.NewSwitchProfileService(profileRepo, setup.accountRepo, setup.credentialStore,
+		setup.configManager, setup.historyRepo)
+
+	if _, err := useCase.Execute(ctx, usecases.SwitchProfileInput{Name: "missing"}); !errors.Is(err, domain.ErrProfileNotFound) {
+		t.Errorf("Execute() error = %v, want ErrProfileNotFound", err)
+	}
+	if _, err := useCase.Execute(ctx, usecases.SwitchProfileInput{Name: "old"}); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("Execute() error = %v, want ErrAccountNotFound", err)
+	}
+	if _, err := useCase.Execute(ctx, usecases.SwitchProfileInput{}); err == nil {
+		t.Error("Expected error for empty name, got nil")
+	}
+	if setup.configManager.currentAccount != setup.testAccounts["personal"] {
+		t.Error("Failed profile switches should not change the current account")
+	}
+}
@@ -0,0 +1,63 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// TouchAccountUseCase defines the interface for marking an account as just used
+type TouchAccountUseCase interface {
+	Execute(ctx context.Context, input TouchAccountInput) error
+}
+
+// TouchAccountInput contains the input data for touching an account
+type TouchAccountInput struct {
+	AccountID string // Account ID to mark as used
+}
+
+// TouchAccountService implements the TouchAccountUseCase
+type TouchAccountService struct {
+	accounts ports.AccountRepository
+}
+
+// Ensure TouchAccountService implements TouchAccountUseCase at compile time
+var _ TouchAccountUseCase = (*TouchAccountService)(nil)
+
+// NewTouchAccountService creates a new TouchAccountService
+func NewTouchAccountService(accounts ports.AccountRepository) TouchAccountUseCase {
+	return &TouchAccountService{
+		accounts: accounts,
+	}
+}
+
+// Execute updates the account's last-used time, promoting it in recency ordering and
+// for MostRecent switches. Unlike switching, Claude config and history are untouched.
+func (s *TouchAccountService) Execute(ctx context.Context, input TouchAccountInput) error {
+	if input.AccountID == "" {
+		return errors.New("account ID is required")
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context cancelled: %w", err)
+	}
+
+	account, err := s.accounts.FindByID(ctx, domain.AccountID(input.AccountID))
+	if err != nil {
+		return fmt.Errorf("failed to find account: %w", err)
+	}
+
+	// The repository may hand the same account to other callers, so touch a copy
+	account = account.Clone()
+	account.MarkUsed()
+	if err := s.accounts.Save(ctx, account); err != nil {
+		return fmt.Errorf("failed to save account: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+func TestTouchAccountUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	work := setup.testAccounts["work"]
+	before := work.LastUsed()
+	time.Sleep(time.Millisecond)
+
+	useCase := usecases.NewTouchAccountService(setup.accountRepo)
+	if err := useCase.Execute(ctx, usecases.TouchAccountInput{AccountID: string(work.ID())}); err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	stored, _ := setup.accountRepo.FindByID(ctx, work.ID())
+	if !stored.LastUsed().After(before) {
+		t.Errorf("LastUsed() = %v, want after %v", stored.LastUsed(), before)
+	}
+	if setup.configManager.currentAccount != setup.testAccounts["personal"] || len(setup.historyRepo.history.Entries()) != 0 {
+		t.Error("Execute() changed Claude config or history")
+	}
+}
+
+func TestTouchAccountUseCase_Execute_Errors(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	useCase := usecases.NewTouchAccountService(setup.accountRepo)
+
+	if err := useCase.Execute(context.Background(), usecases.TouchAccountInput{}); err == nil {
+		t.Error("Execute() without account ID error = nil, want error")
+	}
+
+	err := useCase.Execute(context.Background(), usecases.TouchAccountInput{AccountID: "nonexistent"})
+	if !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("Execute() unknown account error = %v, want ErrAccountNotFound", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = useCase.Execute(ctx, usecases.TouchAccountInput{AccountID: string(setup.testAccounts["work"].ID())})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() with cancelled context error = %v, want context.Canceled", err)
+	}
+
+	setup.accountRepo.saveErr = errors.New("disk full")
+	err = useCase.Execute(context.Background(), usecases.TouchAccountInput{AccountID: string(setup.testAccounts["work"].ID())})
+	if !errors.Is(err, setup.accountRepo.saveErr) {
+		t.Errorf("Execute() with failing save error = %v, want wrapped save error", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Transaction is a unit of work over ports that have no native transactions. Each
+// completed step registers a compensating action; on failure the compensations run
+// in reverse order so the store ends up as it was before the first step.
+type Transaction struct {
+	undo []func(ctx context.Context) error
+}
+
+// NewTransaction starts an empty unit of work
+func NewTransaction() *Transaction {
+	return &Transaction{}
+}
+
+// Do runs step and, if it succeeds, records undo to reverse it. A nil undo marks a
+// step that needs no compensation. If step fails, the transaction is rolled back and
+// the step's error is returned, joined with any rollback failure. If ctx is already
+// done, as after a Ctrl-C between steps, step is skipped and the transaction is rolled
+// back the same way, returning the context's error.
+func (t *Transaction) Do(ctx context.Context, step, undo func(ctx context.Context) error) error {
+	run := step
+	if err := ctx.Err(); err != nil {
+		run = func(context.Context) error { return fmt.Errorf("context cancelled: %w", err) }
+	}
+
+	if err := run(ctx); err != nil {
+		if rbErr := t.Rollback(ctx); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	if undo != nil {
+		t.undo = append(t.undo, undo)
+	}
+	return nil
+}
+
+// Rollback runs every recorded compensation, most recent first, and clears them.
+// It keeps going past failures and ignores cancellation of ctx, since stopping
+// half way would leave the store in neither state.
+func (t *Transaction) Rollback(ctx context.Context) error {
+	ctx = context.WithoutCancel(ctx)
+
+	var errs []error
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		if err := t.undo[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	t.undo = nil
+
+	if len(errs) > 0 {
+		return fmt.Errorf("rollback incomplete: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Commit discards the recorded compensations once every step has succeeded
+func (t *Transaction) Commit() {
+	t.undo = nil
+}
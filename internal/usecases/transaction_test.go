@@ -0,0 +1,116 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// recordingStep returns a step and undo that append their names to log
+func recordingStep(log *[]string, name string, stepErr error) (step, undo func(context.Context) error) {
+	step = func(context.Context) error {
+		if stepErr != nil {
+			return stepErr
+		}
+		*log = append(*log, name)
+		return nil
+	}
+	undo = func(context.Context) error {
+		*log = append(*log, "undo "+name)
+		return nil
+	}
+	return step, undo
+}
+
+// TestTransaction_RollsBackInReverseOrder tests that a failed step undoes completed steps newest first
+func TestTransaction_RollsBackInReverseOrder(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	tx := usecases.NewTransaction()
+
+	for _, name := range []string{"a", "b"} {
+		step, undo := recordingStep(&log, name, nil)
+		if err := tx.Do(ctx, step, undo); err != nil {
+			t.Fatalf("Do(%s) error = %v", name, err)
+		}
+	}
+
+	stepErr := errors.New("step c failed")
+	step, undo := recordingStep(&log, "c", stepErr)
+	if err := tx.Do(ctx, step, undo); !errors.Is(err, stepErr) {
+		t.Fatalf("Do(c) error = %v, want %v", err, stepErr)
+	}
+
+	want := []string{"a", "b", "undo b", "undo a"}
+	if !slices.Equal(log, want) {
+		t.Errorf("log = %v, want %v", log, want)
+	}
+}
+
+// TestTransaction_Commit tests that committed steps are never undone
+func TestTransaction_Commit(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	tx := usecases.NewTransaction()
+
+	step, undo := recordingStep(&log, "a", nil)
+	_ = tx.Do(ctx, step, undo)
+	tx.Commit()
+
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if !slices.Equal(log, []string{"a"}) {
+		t.Errorf("log = %v, want [a]", log)
+	}
+}
+
+// TestTransaction_RollbackErrors tests that rollback continues past failures, reports
+// them, and is not stopped by a cancelled context
+func TestTransaction_RollbackErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var log []string
+	tx := usecases.NewTransaction()
+
+	step, undo := recordingStep(&log, "a", nil)
+	_ = tx.Do(ctx, step, undo)
+	undoErr := errors.New("undo b failed")
+	_ = tx.Do(ctx, func(context.Context) error { return nil }, func(context.Context) error { return undoErr })
+	// Steps without compensation are allowed
+	_ = tx.Do(ctx, func(context.Context) error { return nil }, nil)
+
+	cancel()
+	err := tx.Rollback(ctx)
+	if !errors.Is(err, undoErr) {
+		t.Errorf("Rollback() error = %v, want %v", err, undoErr)
+	}
+	if !slices.Equal(log, []string{"a", "undo a"}) {
+		t.Errorf("log = %v, want [a undo a]", log)
+	}
+}
+
+// TestTransaction_CancelledBetweenSteps tests that a step after cancellation is skipped
+// and the completed steps are undone
+func TestTransaction_CancelledBetweenSteps(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var log []string
+	tx := usecases.NewTransaction()
+
+	step, undo := recordingStep(&log, "a", nil)
+	if err := tx.Do(ctx, step, undo); err != nil {
+		t.Fatalf("Do(a) error = %v", err)
+	}
+
+	cancel()
+	step, undo = recordingStep(&log, "b", nil)
+	if err := tx.Do(ctx, step, undo); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Do(b) error = %v, want %v", err, context.Canceled)
+	}
+
+	if !slices.Equal(log, []string{"a", "undo a"}) {
+		t.Errorf("log = %v, want [a undo a]", log)
+	}
+}
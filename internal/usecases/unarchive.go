@@ -0,0 +1,88 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// ErrAccountNotArchived is returned when unarchiving an account that is not archived
+var ErrAccountNotArchived = errors.New("account is not archived")
+
+// UnarchiveUseCase defines the interface for restoring an archived account
+type UnarchiveUseCase interface {
+	Execute(ctx context.Context, input UnarchiveInput) (*UnarchiveResult, error)
+}
+
+// UnarchiveInput contains the input data for unarchiving an account
+type UnarchiveInput struct {
+	AccountID string // Account ID to restore
+}
+
+// UnarchiveResult contains the result of an unarchive operation
+type UnarchiveResult struct {
+	Account AccountInfo `json:"account"` // The restored account
+	// HasCredentials is false if the account's credentials went missing while it was
+	// archived, so they must be repaired before switching to it
+	HasCredentials bool `json:"has_credentials"`
+}
+
+// UnarchiveService implements the UnarchiveUseCase
+type UnarchiveService struct {
+	accounts    ports.AccountRepository
+	credentials ports.CredentialStore
+}
+
+// Ensure UnarchiveService implements UnarchiveUseCase at compile time
+var _ UnarchiveUseCase = (*UnarchiveService)(nil)
+
+// NewUnarchiveService creates a new UnarchiveService
+func NewUnarchiveService(accounts ports.AccountRepository, credentials ports.CredentialStore) UnarchiveUseCase {
+	return &UnarchiveService{
+		accounts:    accounts,
+		credentials: credentials,
+	}
+}
+
+// Execute returns an archived account to listings and switching. Its credentials were
+// kept when it was archived, so it can be switched to right away.
+func (s *UnarchiveService) Execute(ctx context.Context, input UnarchiveInput) (*UnarchiveResult, error) {
+	if input.AccountID == "" {
+		return nil, errors.New("account ID is required")
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	account, err := s.accounts.FindByID(ctx, domain.AccountID(input.AccountID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+	if !account.Archived() {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotArchived, account.Email())
+	}
+
+	// The repository may hand the same account to other callers, so restore a copy
+	account = account.Clone()
+	account.Unarchive()
+	if err := s.accounts.Save(ctx, account); err != nil {
+		return nil, fmt.Errorf("failed to save account: %w", err)
+	}
+
+	_, err = s.credentials.Retrieve(ctx, account.ID())
+	if err != nil && !errors.Is(err, domain.ErrCredentialsNotFound) {
+		return nil, fmt.Errorf("failed to check credentials for %s: %w", account.Email(), err)
+	}
+
+	return &UnarchiveResult{
+		Account:        newAccountInfo(account),
+		HasCredentials: err == nil,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+func TestUnarchiveUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	work := setup.testAccounts["work"]
+	work.Archive(time.Now())
+
+	useCase := usecases.NewUnarchiveService(setup.accountRepo, setup.credentialStore)
+	result, err := useCase.Execute(ctx, usecases.UnarchiveInput{AccountID: string(work.ID())})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.Account.Archived || !result.Account.ArchivedAt.IsZero() || !result.HasCredentials {
+		t.Errorf("Result = %+v, want an unarchived account with credentials", result)
+	}
+
+	// The restored account can be switched to with its original credentials
+	switched, err := setup.useCase.Execute(ctx, usecases.SwitchAccountInput{Alias: "work"})
+	if err != nil {
+		t.Fatalf("Switch after unarchive error = %v, want nil", err)
+	}
+	if switched.To.Email != testEmailWork || setup.configManager.credentials != setup.testCredentials[work.ID()] {
+		t.Errorf("Switched to %s, want %s with its stored credentials", switched.To.Email, testEmailWork)
+	}
+
+	if _, err := useCase.Execute(ctx, usecases.UnarchiveInput{AccountID: string(work.ID())}); !errors.Is(err, usecases.ErrAccountNotArchived) {
+		t.Errorf("Execute() on unarchived account error = %v, want ErrAccountNotArchived", err)
+	}
+}
+
+func TestUnarchiveUseCase_Execute_MissingCredentials(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	test := setup.testAccounts["test"]
+	test.Archive(time.Now())
+	_ = setup.credentialStore.Delete(ctx, test.ID())
+
+	result, err := usecases.NewUnarchiveService(setup.accountRepo, setup.credentialStore).
+		Execute(ctx, usecases.UnarchiveInput{AccountID: string(test.ID())})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.HasCredentials {
+		t.Error("HasCredentials = true, want false")
+	}
+	if stored, _ := setup.accountRepo.FindByID(ctx, test.ID()); stored.Archived() {
+		t.Error("Account still archived")
+	}
+}
+
+func TestUnarchiveUseCase_Execute_Validation(t *testing.T) {
+	useCase := usecases.NewUnarchiveService(newMockAccountRepository(), newMockCredentialStore())
+	if _, err := useCase.Execute(context.Background(), usecases.UnarchiveInput{}); err == nil {
+		t.Error("Execute() without account ID error = nil, want error")
+	}
+	_, err := useCase.Execute(context.Background(), usecases.UnarchiveInput{AccountID: "missing"})
+	if !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("Execute() error = %v, want ErrAccountNotFound", err)
+	}
+}
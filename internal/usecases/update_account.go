@@ -0,0 +1,127 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// UpdateAccountUseCase defines the interface for updating an existing account's details
+type UpdateAccountUseCase interface {
+	Execute(ctx context.Context, input UpdateAccountInput) (*UpdateAccountResult, error)
+}
+
+// UpdateAccountInput contains the input data for updating an account
+type UpdateAccountInput struct {
+	// Exactly one of these selects the account to update
+	AccountID string // Account ID to update
+	Email     string // Email of the account to update
+	Alias     string // Current alias of the account to update
+
+	NewAlias string // New alias; empty removes the alias
+}
+
+// UpdateAccountResult contains the result of an update operation
+type UpdateAccountResult struct {
+	Account  AccountInfo `json:"account"`   // Account after the update
+	OldAlias string      `json:"old_alias"` // Alias before the update
+	NewAlias string      `json:"new_alias"` // Alias after the update
+}
+
+// UpdateAccountService implements the UpdateAccountUseCase
+type UpdateAccountService struct {
+	accounts ports.AccountRepository
+	resolver *AccountResolver
+}
+
+// Ensure UpdateAccountService implements UpdateAccountUseCase at compile time
+var _ UpdateAccountUseCase = (*UpdateAccountService)(nil)
+
+// NewUpdateAccountService creates a new UpdateAccountService
+func NewUpdateAccountService(accounts ports.AccountRepository) UpdateAccountUseCase {
+	return &UpdateAccountService{
+		accounts: accounts,
+		resolver: NewAccountResolver(accounts),
+	}
+}
+
+// Execute renames the account's alias, keeping its ID, credentials, and timestamps.
+// The account is selected by exactly one of its ID, email, or current alias.
+func (s *UpdateAccountService) Execute(ctx context.Context, input UpdateAccountInput) (*UpdateAccountResult, error) {
+	selector := AccountSelector{
+		AccountID: input.AccountID,
+		Email:     input.Email,
+		Alias:     input.Alias,
+	}
+	if err := selector.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	account, err := s.resolver.Resolve(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+
+	oldAlias := account.Alias()
+	if oldAlias == input.NewAlias {
+		// Nothing to change
+		return &UpdateAccountResult{
+			Account:  newAccountInfo(account),
+			OldAlias: oldAlias,
+			NewAlias: input.NewAlias,
+		}, nil
+	}
+
+	if err := s.checkAliasAvailable(ctx, account.ID(), input.NewAlias); err != nil {
+		return nil, err
+	}
+
+	if err := account.UpdateAlias(input.NewAlias); err != nil {
+		return nil, fmt.Errorf("invalid alias: %w", err)
+	}
+
+	if err := s.accounts.Save(ctx, account); err != nil {
+		return nil, fmt.Errorf("failed to save account: %w", err)
+	}
+
+	return &UpdateAccountResult{
+		Account:  newAccountInfo(account),
+		OldAlias: oldAlias,
+		NewAlias: input.NewAlias,
+	}, nil
+}
+
+// checkAliasAvailable verifies no other account already uses the alias
+func (s *UpdateAccountService) checkAliasAvailable(ctx context.Context, id domain.AccountID, alias string) error {
+	return checkAliasAvailable(ctx, s.accounts, id, alias)
+}
+
+// checkAliasAvailable returns ErrDuplicateAlias if an account other than id uses alias.
+// Empty aliases are never considered taken.
+func checkAliasAvailable(ctx context.Context, accounts ports.AccountRepository, id domain.AccountID, alias string) error {
+	if alias == "" {
+		return nil
+	}
+
+	existing, err := accounts.FindByAlias(ctx, alias)
+	switch {
+	case errors.Is(err, domain.ErrAccountNotFound):
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to check for existing alias: %w", err)
+	case existing.ID() != id:
+		return fmt.Errorf("%w: %s is used by %s", domain.ErrDuplicateAlias, alias, existing.Email())
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,215 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// Test setup helper for UpdateAccountUseCase
+type updateAccountTestSetup struct {
+	accountRepo *mockAccountRepository
+	useCase     usecases.UpdateAccountUseCase
+	work        *domain.Account
+	personal    *domain.Account
+}
+
+func setupUpdateAccountTest() *updateAccountTestSetup {
+	accountRepo := newMockAccountRepository()
+
+	work, _ := domain.NewAccount(testEmailWork, "wrk", "uuid-work")
+	personal, _ := domain.NewAccount(testEmailPersonal, "personal", "uuid-personal")
+	_ = accountRepo.Save(context.Background(), work)
+	_ = accountRepo.Save(context.Background(), personal)
+
+	return &updateAccountTestSetup{
+		accountRepo: accountRepo,
+		useCase:     usecases.NewUpdateAccountService(accountRepo),
+		work:        work,
+		personal:    personal,
+	}
+}
+
+// TestUpdateAccountUseCase_Execute_RenameAlias tests the happy path
+func TestUpdateAccountUseCase_Execute_RenameAlias(t *testing.T) {
+	setup := setupUpdateAccountTest()
+	ctx := context.Background()
+	createdAt := setup.work.CreatedAt()
+
+	result, err := setup.useCase.Execute(ctx, usecases.UpdateAccountInput{
+		AccountID: string(setup.work.ID()),
+		NewAlias:  "work",
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if result.OldAlias != "wrk" || result.NewAlias != "work" {
+		t.Errorf("Expected wrk -> work, got %s -> %s", result.OldAlias, result.NewAlias)
+	}
+
+	// Verify the rename was persisted and identity preserved
+	found, err := setup.accountRepo.FindByAlias(ctx, "work")
+	if err != nil {
+		t.Fatalf("Expected account to be found by new alias: %v", err)
+	}
+	if found.ID() != setup.work.ID() {
+		t.Error("Account ID should not change on rename")
+	}
+	if !found.CreatedAt().Equal(createdAt) {
+		t.Error("CreatedAt should not change on rename")
+	}
+}
+
+// TestUpdateAccountUseCase_Execute_BySelector tests selecting the account by its
+// current alias or email instead of its ID
+func TestUpdateAccountUseCase_Execute_BySelector(t *testing.T) {
+	tests := []struct {
+		name  string
+		input usecases.UpdateAccountInput
+	}{
+		{name: "alias", input: usecases.UpdateAccountInput{Alias: "wrk", NewAlias: "work"}},
+		{name: "email", input: usecases.UpdateAccountInput{Email: "Work@Example.com", NewAlias: "work"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupUpdateAccountTest()
+			ctx := context.Background()
+
+			result, err := setup.useCase.Execute(ctx, tt.input)
+			if err != nil {
+				t.Fatalf("Execute() error = %v, want nil", err)
+			}
+			if result.Account.ID != string(setup.work.ID()) || result.OldAlias != "wrk" {
+				t.Errorf("updated %s (old alias %s), want %s (old alias wrk)", result.Account.ID, result.OldAlias, setup.work.ID())
+			}
+			if found, err := setup.accountRepo.FindByAlias(ctx, "work"); err != nil || found.ID() != setup.work.ID() {
+				t.Errorf("FindByAlias(work) = %v, %v; want the work account", found, err)
+			}
+		})
+	}
+}
+
+// TestUpdateAccountUseCase_Execute_ClearAlias tests removing an alias
+func TestUpdateAccountUseCase_Execute_ClearAlias(t *testing.T) {
+	setup := setupUpdateAccountTest()
+
+	result, err := setup.useCase.Execute(context.Background(), usecases.UpdateAccountInput{
+		AccountID: string(setup.work.ID()),
+		NewAlias:  "",
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if result.NewAlias != "" || result.Account.Alias != "" {
+		t.Errorf("Expected alias to be cleared, got %+v", result)
+	}
+}
+
+// TestUpdateAccountUseCase_Execute_AliasCollision tests rejecting an alias used by another account
+func TestUpdateAccountUseCase_Execute_AliasCollision(t *testing.T) {
+	setup := setupUpdateAccountTest()
+	ctx := context.Background()
+
+	result, err := setup.useCase.Execute(ctx, usecases.UpdateAccountInput{
+		AccountID: string(setup.work.ID()),
+		NewAlias:  "personal",
+	})
+	if !errors.Is(err, domain.ErrDuplicateAlias) {
+		t.Errorf("Execute() error = %v, want ErrDuplicateAlias", err)
+	}
+	if result != nil {
+		t.Errorf("Expected nil result on error, got %+v", result)
+	}
+
+	if setup.work.Alias() != "wrk" {
+		t.Error("Alias should not change when it collides")
+	}
+}
+
+// TestUpdateAccountUseCase_Execute_Errors tests validation and lookup failures
+func TestUpdateAccountUseCase_Execute_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input func(setup *updateAccountTestSetup) usecases.UpdateAccountInput
+	}{
+		{
+			name: "empty account ID",
+			input: func(_ *updateAccountTestSetup) usecases.UpdateAccountInput {
+				return usecases.UpdateAccountInput{NewAlias: "x"}
+			},
+		},
+		{
+			name: "multiple selectors",
+			input: func(setup *updateAccountTestSetup) usecases.UpdateAccountInput {
+				return usecases.UpdateAccountInput{AccountID: string(setup.work.ID()), Alias: "wrk", NewAlias: "x"}
+			},
+		},
+		{
+			name: "account not found",
+			input: func(_ *updateAccountTestSetup) usecases.UpdateAccountInput {
+				return usecases.UpdateAccountInput{AccountID: "nonexistent", NewAlias: "x"}
+			},
+		},
+		{
+			name: "invalid alias",
+			input: func(setup *updateAccountTestSetup) usecases.UpdateAccountInput {
+				return usecases.UpdateAccountInput{AccountID: string(setup.work.ID()), NewAlias: "has space"}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupUpdateAccountTest()
+
+			result, err := setup.useCase.Execute(context.Background(), tt.input(setup))
+			if err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if result != nil {
+				t.Errorf("Expected nil result on error, got %+v", result)
+			}
+		})
+	}
+}
+
+// TestUpdateAccountUseCase_Execute_SaveFailure tests repository failure handling
+func TestUpdateAccountUseCase_Execute_SaveFailure(t *testing.T) {
+	setup := setupUpdateAccountTest()
+	saveErr := errors.New("disk full")
+	setup.accountRepo.saveErr = saveErr
+
+	_, err := setup.useCase.Execute(context.Background(), usecases.UpdateAccountInput{
+		AccountID: string(setup.work.ID()),
+		NewAlias:  "work",
+	})
+	if !errors.Is(err, saveErr) {
+		t.Errorf("Execute() error = %v, want wrapped %v", err, saveErr)
+	}
+}
+
+// TestUpdateAccountUseCase_Execute_ContextCancellation tests context cancellation
+func TestUpdateAccountUseCase_Execute_ContextCancellation(t *testing.T) {
+	setup := setupUpdateAccountTest()
+
+	// Create cancelled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := setup.useCase.Execute(ctx, usecases.UpdateAccountInput{
+		AccountID: string(setup.work.ID()),
+		NewAlias:  "work",
+	})
+	if result != nil {
+		t.Errorf("Execute() result = %v, want nil", result)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want %v", err, context.Canceled)
+	}
+}
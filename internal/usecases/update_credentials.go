@@ -0,0 +1,97 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// UpdateCredentialsUseCase defines the interface for rotating an account's stored credentials
+type UpdateCredentialsUseCase interface {
+	Execute(ctx context.Context, input UpdateCredentialsInput) (*UpdateCredentialsResult, error)
+}
+
+// UpdateCredentialsInput contains the input data for rotating credentials
+type UpdateCredentialsInput struct {
+	AccountID string // Account whose credentials are rotated
+	// NewData is the refreshed session JSON; it must contain a non-empty sessionKey.
+	// Claude config carries no session credentials, so it cannot be read from there.
+	NewData []byte
+}
+
+// UpdateCredentialsResult contains the result of a credential rotation
+type UpdateCredentialsResult struct {
+	Account   AccountInfo `json:"account"`    // Account whose credentials were rotated
+	RotatedAt time.Time   `json:"rotated_at"` // When the new credentials were stored
+}
+
+// UpdateCredentialsService implements the UpdateCredentialsUseCase
+type UpdateCredentialsService struct {
+	accounts    ports.AccountRepository
+	credentials ports.CredentialStore
+	now         func() time.Time
+}
+
+// Ensure UpdateCredentialsService implements UpdateCredentialsUseCase at compile time
+var _ UpdateCredentialsUseCase = (*UpdateCredentialsService)(nil)
+
+// NewUpdateCredentialsService creates a new UpdateCredentialsService
+func NewUpdateCredentialsService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+) UpdateCredentialsUseCase {
+	return &UpdateCredentialsService{
+		accounts:    accounts,
+		credentials: credentials,
+		now:         time.Now,
+	}
+}
+
+// Execute replaces the data of the account's existing credentials, keeping the account ID,
+// history, and encryption scheme. Passphrase-protected credentials cannot be rotated
+// this way and return domain.ErrPassphraseRequired.
+func (s *UpdateCredentialsService) Execute(ctx context.Context, input UpdateCredentialsInput) (*UpdateCredentialsResult, error) {
+	if input.AccountID == "" {
+		return nil, errors.New("account ID is required")
+	}
+	if len(input.NewData) == 0 {
+		return nil, errors.New("credentials data is required: Claude config does not include a session key")
+	}
+	if err := domain.ValidateCredentialData(input.NewData); err != nil {
+		return nil, err
+	}
+
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	account, err := s.accounts.FindByID(ctx, domain.AccountID(input.AccountID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+
+	creds, err := s.credentials.Retrieve(ctx, account.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credentials for %s: %w", account.Email(), err)
+	}
+
+	if err := creds.UpdateData(input.NewData); err != nil {
+		return nil, fmt.Errorf("failed to update credentials for %s: %w", account.Email(), err)
+	}
+
+	if err := s.credentials.Store(ctx, creds); err != nil {
+		return nil, fmt.Errorf("failed to store credentials: %w", err)
+	}
+
+	return &UpdateCredentialsResult{
+		Account:   newAccountInfo(account),
+		RotatedAt: s.now(),
+	}, nil
+}
@@ -0,0 +1,161 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+const rotatedPlaintext = `{"sessionKey":"rotated"}`
+
+func setupUpdateCredentialsTest(t *testing.T) (*mockCredentialStore, *domain.Account, usecases.UpdateCredentialsUseCase) {
+	t.Helper()
+	accountRepo := newMockAccountRepository()
+	credentialStore := newMockCredentialStore()
+
+	account, _ := domain.NewAccount(testEmailPersonal, "personal", "uuid-personal")
+	_ = accountRepo.Save(context.Background(), account)
+	creds, err := domain.NewCredentials(account.ID(), []byte(`{"sessionKey":"original"}`))
+	if err != nil {
+		t.Fatalf("failed to create credentials: %v", err)
+	}
+	_ = credentialStore.Store(context.Background(), creds)
+
+	return credentialStore, account, usecases.NewUpdateCredentialsService(accountRepo, credentialStore)
+}
+
+// TestUpdateCredentialsUseCase_Execute_Rotates tests replacing the stored session data
+func TestUpdateCredentialsUseCase_Execute_Rotates(t *testing.T) {
+	ctx := context.Background()
+	credentialStore, account, useCase := setupUpdateCredentialsTest(t)
+	before := time.Now()
+
+	result, err := useCase.Execute(ctx, usecases.UpdateCredentialsInput{
+		AccountID: string(account.ID()),
+		NewData:   []byte(rotatedPlaintext),
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+
+	if result.Account.ID != string(account.ID()) {
+		t.Errorf("Account.ID = %s, want %s", result.Account.ID, account.ID())
+	}
+	if result.RotatedAt.Before(before) {
+		t.Errorf("RotatedAt = %v, want after %v", result.RotatedAt, before)
+	}
+
+	creds, err := credentialStore.Retrieve(ctx, account.ID())
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	data, err := creds.Decrypt()
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if string(data) != rotatedPlaintext {
+		t.Errorf("Decrypted data = %s, want %s", data, rotatedPlaintext)
+	}
+}
+
+// TestUpdateCredentialsUseCase_Execute_Validation tests rejected rotations
+func TestUpdateCredentialsUseCase_Execute_Validation(t *testing.T) {
+	credentialStore, account, useCase := setupUpdateCredentialsTest(t)
+	id := string(account.ID())
+
+	tests := []struct {
+		name  string
+		input usecases.UpdateCredentialsInput
+	}{
+		{"missing ID", usecases.UpdateCredentialsInput{NewData: []byte(rotatedPlaintext)}},
+		{"missing data", usecases.UpdateCredentialsInput{AccountID: id}},
+		{"no session key", usecases.UpdateCredentialsInput{AccountID: id, NewData: []byte(`{"foo":"bar"}`)}},
+		{"unknown account", usecases.UpdateCredentialsInput{AccountID: "nonexistent", NewData: []byte(rotatedPlaintext)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := useCase.Execute(context.Background(), tt.input); err == nil {
+				t.Error("Execute() error = nil, want error")
+			}
+		})
+	}
+
+	// Nothing was replaced
+	creds, _ := credentialStore.Retrieve(context.Background(), account.ID())
+	if data, _ := creds.Decrypt(); string(data) != `{"sessionKey":"original"}` {
+		t.Errorf("Credentials changed to %s after rejected rotations", data)
+	}
+}
+
+// TestUpdateCredentialsUseCase_Execute_MissingCredentials tests accounts without stored credentials
+func TestUpdateCredentialsUseCase_Execute_MissingCredentials(t *testing.T) {
+	credentialStore, account, useCase := setupUpdateCredentialsTest(t)
+	_ = credentialStore.Delete(context.Background(), account.ID())
+
+	_, err := useCase.Execute(context.Background(), usecases.UpdateCredentialsInput{
+		AccountID: string(account.ID()),
+		NewData:   []byte(rotatedPlaintext),
+	})
+	if err == nil {
+		t.Fatal("Execute() error = nil, want error")
+	}
+}
+
+// TestUpdateCredentialsUseCase_Execute_PassphraseProtected tests that credentials whose
+// key is not available are refused
+func TestUpdateCredentialsUseCase_Execute_PassphraseProtected(t *testing.T) {
+	credentialStore, account, useCase := setupUpdateCredentialsTest(t)
+
+	protected, err := domain.NewCredentialsWithPassphrase(account.ID(), []byte(`{"sessionKey":"original"}`), []byte("secret"))
+	if err != nil {
+		t.Fatalf("failed to create credentials: %v", err)
+	}
+	serialized, _ := protected.Serialize()
+	loaded, err := domain.DeserializeCredentials(serialized)
+	if err != nil {
+		t.Fatalf("failed to deserialize credentials: %v", err)
+	}
+	_ = credentialStore.Store(context.Background(), loaded)
+
+	_, err = useCase.Execute(context.Background(), usecases.UpdateCredentialsInput{
+		AccountID: string(account.ID()),
+		NewData:   []byte(rotatedPlaintext),
+	})
+	if !errors.Is(err, domain.ErrPassphraseRequired) {
+		t.Errorf("Execute() error = %v, want ErrPassphraseRequired", err)
+	}
+}
+
+// TestUpdateCredentialsUseCase_Execute_StoreFailure tests a failing credential store
+func TestUpdateCredentialsUseCase_Execute_StoreFailure(t *testing.T) {
+	credentialStore, account, useCase := setupUpdateCredentialsTest(t)
+	credentialStore.storeErr = errors.New("keychain unavailable")
+
+	_, err := useCase.Execute(context.Background(), usecases.UpdateCredentialsInput{
+		AccountID: string(account.ID()),
+		NewData:   []byte(rotatedPlaintext),
+	})
+	if !errors.Is(err, credentialStore.storeErr) {
+		t.Errorf("Execute() error = %v, want store error", err)
+	}
+}
+
+// TestUpdateCredentialsUseCase_Execute_ContextCancellation tests context cancellation
+func TestUpdateCredentialsUseCase_Execute_ContextCancellation(t *testing.T) {
+	_, account, useCase := setupUpdateCredentialsTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := useCase.Execute(ctx, usecases.UpdateCredentialsInput{
+		AccountID: string(account.ID()),
+		NewData:   []byte(rotatedPlaintext),
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want context.Canceled", err)
+	}
+}
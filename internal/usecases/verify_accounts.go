@@ -0,0 +1,98 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// VerifyAccountsUseCase defines the interface for checking every account's credentials in one pass
+type VerifyAccountsUseCase interface {
+	Execute(ctx context.Context) ([]AccountVerification, error)
+}
+
+// AccountVerification reports the credential state of a single account
+type AccountVerification struct {
+	Account          AccountInfo `json:"account"`           // Account that was checked
+	CredentialsFound bool        `json:"credentials_found"` // True if the credential store has credentials for the account
+	Decryptable      bool        `json:"decryptable"`       // True if the stored credentials decrypt successfully
+	ExpiresAt        time.Time   `json:"expires_at"`        // Session expiry, zero if unknown or not set
+	Expired          bool        `json:"expired"`           // True if ExpiresAt is known and in the past
+	Problem          string      `json:"problem"`           // Human-readable reason the account failed verification
+}
+
+// OK reports whether the account's credentials passed every check
+func (v AccountVerification) OK() bool {
+	return v.CredentialsFound && v.Decryptable && !v.Expired
+}
+
+// VerifyAccountsService implements the VerifyAccountsUseCase
+type VerifyAccountsService struct {
+	accounts    ports.AccountRepository
+	credentials ports.CredentialStore
+}
+
+// Ensure VerifyAccountsService implements VerifyAccountsUseCase at compile time
+var _ VerifyAccountsUseCase = (*VerifyAccountsService)(nil)
+
+// NewVerifyAccountsService creates a new VerifyAccountsService
+func NewVerifyAccountsService(
+	accounts ports.AccountRepository,
+	credentials ports.CredentialStore,
+) VerifyAccountsUseCase {
+	return &VerifyAccountsService{
+		accounts:    accounts,
+		credentials: credentials,
+	}
+}
+
+// Execute checks each account's credentials for existence, decryption, and expiry.
+// A failing account is reported, not returned as an error.
+func (s *VerifyAccountsService) Execute(ctx context.Context) ([]AccountVerification, error) {
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	accounts, err := s.accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	now := time.Now()
+	report := make([]AccountVerification, 0, len(accounts))
+	for _, account := range accounts {
+		verification := AccountVerification{Account: newAccountInfo(account)}
+
+		creds, err := s.credentials.Retrieve(ctx, account.ID())
+		if err != nil {
+			verification.Problem = fmt.Sprintf("credentials unavailable: %v", err)
+			report = append(report, verification)
+			continue
+		}
+		verification.CredentialsFound = true
+
+		if _, err := creds.Decrypt(); err != nil {
+			verification.Problem = "credentials cannot be decrypted"
+			report = append(report, verification)
+			continue
+		}
+		verification.Decryptable = true
+
+		if expiresAt, ok := creds.ExpiresAt(); ok {
+			verification.ExpiresAt = expiresAt
+			if expiresAt.Before(now) {
+				verification.Expired = true
+				verification.Problem = "session expired at " + expiresAt.Format(time.RFC3339)
+			}
+		}
+
+		report = append(report, verification)
+	}
+
+	return report, nil
+}
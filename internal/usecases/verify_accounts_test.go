@@ -0,0 +1,144 @@
+package usecases_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// undecryptableCredentials builds credentials for accountID whose payload was
+// encrypted under a different account's key, so Decrypt fails
+func undecryptableCredentials(t *testing.T, accountID domain.AccountID) *domain.Credentials {
+	t.Helper()
+
+	other, err := domain.NewCredentials("other-id", []byte(`{"sessionKey":"secret"}`))
+	if err != nil {
+		t.Fatalf("failed to create credentials: %v", err)
+	}
+
+	serialized := `{"accountId":"` + string(accountID) + `","encryptedData":"` +
+		base64.StdEncoding.EncodeToString(other.EncryptedData()) + `"}`
+	creds, err := domain.DeserializeCredentials([]byte(serialized))
+	if err != nil {
+		t.Fatalf("failed to deserialize credentials: %v", err)
+	}
+	return creds
+}
+
+// TestVerifyAccountsUseCase_Execute_Report tests a report across varying credential states
+func TestVerifyAccountsUseCase_Execute_Report(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepository()
+	credentialStore := newMockCredentialStore()
+
+	healthy, _ := domain.NewAccount("healthy@example.com", "healthy", "uuid-healthy")
+	missing, _ := domain.NewAccount("missing@example.com", "missing", "uuid-missing")
+	corrupt, _ := domain.NewAccount("corrupt@example.com", "corrupt", "uuid-corrupt")
+	expired, _ := domain.NewAccount("expired@example.com", "expired", "uuid-expired")
+	for _, account := range []*domain.Account{healthy, missing, corrupt, expired} {
+		_ = accountRepo.Save(ctx, account)
+	}
+
+	future := time.Now().Add(24 * time.Hour).Unix()
+	past := time.Now().Add(-24 * time.Hour).Unix()
+
+	healthyCreds, _ := domain.NewCredentials(healthy.ID(),
+		[]byte(`{"sessionKey":"k","sessionKeyExpiresAt":`+strconv.FormatInt(future, 10)+`}`))
+	expiredCreds, _ := domain.NewCredentials(expired.ID(),
+		[]byte(`{"sessionKey":"k","sessionKeyExpiresAt":`+strconv.FormatInt(past, 10)+`}`))
+	_ = credentialStore.Store(ctx, healthyCreds)
+	_ = credentialStore.Store(ctx, expiredCreds)
+	_ = credentialStore.Store(ctx, undecryptableCredentials(t, corrupt.ID()))
+
+	useCase := usecases.NewVerifyAccountsService(accountRepo, credentialStore)
+
+	report, err := useCase.Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if len(report) != 4 {
+		t.Fatalf("Expected 4 verifications, got %d", len(report))
+	}
+
+	byAlias := make(map[string]usecases.AccountVerification)
+	for _, v := range report {
+		byAlias[v.Account.Alias] = v
+	}
+
+	tests := []struct {
+		alias       string
+		found       bool
+		decryptable bool
+		expired     bool
+		ok          bool
+	}{
+		{"healthy", true, true, false, true},
+		{"missing", false, false, false, false},
+		{"corrupt", true, false, false, false},
+		{"expired", true, true, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			v, exists := byAlias[tt.alias]
+			if !exists {
+				t.Fatalf("No verification for %s", tt.alias)
+			}
+			if v.CredentialsFound != tt.found {
+				t.Errorf("CredentialsFound = %v, want %v", v.CredentialsFound, tt.found)
+			}
+			if v.Decryptable != tt.decryptable {
+				t.Errorf("Decryptable = %v, want %v", v.Decryptable, tt.decryptable)
+			}
+			if v.Expired != tt.expired {
+				t.Errorf("Expired = %v, want %v", v.Expired, tt.expired)
+			}
+			if v.OK() != tt.ok {
+				t.Errorf("OK() = %v, want %v", v.OK(), tt.ok)
+			}
+			if !tt.ok && v.Problem == "" {
+				t.Error("Expected a problem description for a failing account")
+			}
+		})
+	}
+
+	if byAlias["healthy"].ExpiresAt.IsZero() {
+		t.Error("Expected expiry to be reported for healthy account")
+	}
+}
+
+// TestVerifyAccountsUseCase_Execute_RepositoryError tests repository failure handling
+func TestVerifyAccountsUseCase_Execute_RepositoryError(t *testing.T) {
+	accountRepo := newMockAccountRepository()
+	repoErr := errors.New("database connection failed")
+	accountRepo.findErr = repoErr
+
+	useCase := usecases.NewVerifyAccountsService(accountRepo, newMockCredentialStore())
+
+	report, err := useCase.Execute(context.Background())
+	if !errors.Is(err, repoErr) {
+		t.Errorf("Execute() error = %v, want wrapped %v", err, repoErr)
+	}
+	if report != nil {
+		t.Errorf("Expected nil report on error, got %v", report)
+	}
+}
+
+// TestVerifyAccountsUseCase_Execute_ContextCancellation tests context cancellation
+func TestVerifyAccountsUseCase_Execute_ContextCancellation(t *testing.T) {
+	useCase := usecases.NewVerifyAccountsService(newMockAccountRepository(), newMockCredentialStore())
+
+	// Create cancelled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := useCase.Execute(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,152 @@
+// Package usecases contains the application business rules and orchestrates the flow
+// between the domain entities and the infrastructure adapters.
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/ports"
+)
+
+// WhoAmIUseCase defines the interface for reporting the active Claude account
+type WhoAmIUseCase interface {
+	Execute(ctx context.Context) (*WhoAmIResult, error)
+}
+
+// WhoAmIResult contains the active account as ccx knows it
+type WhoAmIResult struct {
+	// Account is the ccx account when KnownToCCX; otherwise only Email and UUID from
+	// Claude config are set
+	Account    AccountInfo `json:"account"`
+	KnownToCCX bool        `json:"known_to_ccx"` // True if the active Claude account is managed by ccx
+	// FromCCX is true when Claude config names no account and the account is the one
+	// ccx last switched to, as recorded in its own settings
+	FromCCX bool `json:"from_ccx"`
+}
+
+// WhoAmIService implements the WhoAmIUseCase
+type WhoAmIService struct {
+	accounts ports.AccountRepository
+	config   ports.ConfigManager
+	settings ports.SettingsRepository
+}
+
+// Ensure WhoAmIService implements WhoAmIUseCase at compile time
+var _ WhoAmIUseCase = (*WhoAmIService)(nil)
+
+// WhoAmIOption configures optional WhoAmIService behavior
+type WhoAmIOption func(*WhoAmIService)
+
+// WithWhoAmISettings gives WhoAmIService access to ccx settings, so the account ccx last
+// switched to is reported when Claude config is missing or names no account
+func WithWhoAmISettings(settings ports.SettingsRepository) WhoAmIOption {
+	return func(s *WhoAmIService) {
+		s.settings = settings
+	}
+}
+
+// NewWhoAmIService creates a new WhoAmIService
+func NewWhoAmIService(accounts ports.AccountRepository, config ports.ConfigManager, opts ...WhoAmIOption) WhoAmIUseCase {
+	s := &WhoAmIService{
+		accounts: accounts,
+		config:   config,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Execute reads the current account from Claude config and matches it to a ccx account,
+// by UUID first and then by email, to recover its alias, ID, and timestamps
+func (s *WhoAmIService) Execute(ctx context.Context) (*WhoAmIResult, error) {
+	// Check context before proceeding
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context cancelled: %w", err)
+	}
+
+	current, err := s.config.GetCurrentAccount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current account: %w", err)
+	}
+	if current == nil {
+		return s.fromSettings(ctx)
+	}
+
+	account, err := s.findManaged(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+	if account != nil {
+		return &WhoAmIResult{Account: newAccountInfo(account), KnownToCCX: true}, nil
+	}
+
+	// Claude config has no ccx ID, alias, or timestamps to report
+	return &WhoAmIResult{
+		Account: AccountInfo{
+			Email: string(current.Email()),
+			UUID:  current.UUID(),
+			Tags:  []string{},
+		},
+	}, nil
+}
+
+// fromSettings reports the account recorded in ccx's own current-account pointer, or
+// domain.ErrNoCurrentAccount if there is none
+func (s *WhoAmIService) fromSettings(ctx context.Context) (*WhoAmIResult, error) {
+	if s.settings == nil {
+		return nil, domain.ErrNoCurrentAccount
+	}
+
+	settings, err := s.settings.LoadSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+	if settings.CurrentAccountID() == "" {
+		return nil, domain.ErrNoCurrentAccount
+	}
+
+	account, err := s.accounts.FindByID(ctx, settings.CurrentAccountID())
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		return nil, domain.ErrNoCurrentAccount
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find account: %w", err)
+	}
+	return &WhoAmIResult{Account: newAccountInfo(account), KnownToCCX: true, FromCCX: true}, nil
+}
+
+// findManaged returns the ccx account matching current, or nil if there is none
+func (s *WhoAmIService) findManaged(ctx context.Context, current *domain.Account) (*domain.Account, error) {
+	accounts, err := s.accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	return matchCurrentAccount(current, accounts), nil
+}
+
+// matchCurrentAccount returns the account in accounts that current, as read from Claude
+// config, refers to, or nil if there is none. Claude config carries no ccx ID, so the
+// account is matched by UUID first and then by email.
+func matchCurrentAccount(current *domain.Account, accounts []*domain.Account) *domain.Account {
+	if current == nil {
+		return nil
+	}
+
+	// The UUID survives an email change, so it is the better match
+	for _, account := range accounts {
+		if current.UUID() != "" && account.UUID() == current.UUID() {
+			return account
+		}
+	}
+	for _, account := range accounts {
+		if account.Email() == current.Email() {
+			return account
+		}
+	}
+	return nil
+}
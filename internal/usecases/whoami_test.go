@@ -0,0 +1,127 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/evanschultz/ccx/internal/domain"
+	"github.com/evanschultz/ccx/internal/usecases"
+)
+
+// TestWhoAmIUseCase_Execute_Known tests enriching the current account with ccx metadata
+func TestWhoAmIUseCase_Execute_Known(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	work := setup.testAccounts["work"]
+
+	tests := []struct {
+		name    string
+		current *domain.Account
+	}{
+		// Claude config accounts never carry an alias or the ccx ID
+		{"matched by UUID after an email change", mustAccount(t, "changed@example.com", work.UUID())},
+		{"matched by email", mustAccount(t, testEmailWork, "uuid-unknown")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup.configManager.currentAccount = tt.current
+
+			result, err := usecases.NewWhoAmIService(setup.accountRepo, setup.configManager).Execute(ctx)
+			if err != nil {
+				t.Fatalf("Execute() error = %v, want nil", err)
+			}
+			if !result.KnownToCCX {
+				t.Fatal("KnownToCCX = false, want true")
+			}
+			if result.Account.ID != string(work.ID()) || result.Account.Alias != "work" {
+				t.Errorf("Account = %+v, want the work account", result.Account)
+			}
+		})
+	}
+}
+
+// TestWhoAmIUseCase_Execute_Unknown tests reporting an account ccx does not manage
+func TestWhoAmIUseCase_Execute_Unknown(t *testing.T) {
+	setup := setupSwitchAccountTest()
+	setup.configManager.currentAccount = mustAccount(t, "stranger@example.com", "uuid-stranger")
+
+	result, err := usecases.NewWhoAmIService(setup.accountRepo, setup.configManager).Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if result.KnownToCCX {
+		t.Error("KnownToCCX = true, want false")
+	}
+	if result.Account.Email != "stranger@example.com" || result.Account.UUID != "uuid-stranger" {
+		t.Errorf("Account = %+v, want the Claude config identity", result.Account)
+	}
+	if result.Account.ID != "" || result.Account.Alias != "" || !result.Account.LastUsed.IsZero() {
+		t.Errorf("Account = %+v, want no ccx metadata", result.Account)
+	}
+}
+
+// TestWhoAmIUseCase_Execute_Errors tests missing and unreadable Claude config
+func TestWhoAmIUseCase_Execute_Errors(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	useCase := usecases.NewWhoAmIService(setup.accountRepo, setup.configManager)
+
+	setup.configManager.currentAccount = nil
+	if _, err := useCase.Execute(ctx); !errors.Is(err, domain.ErrNoCurrentAccount) {
+		t.Errorf("Execute() with no current account error = %v, want ErrNoCurrentAccount", err)
+	}
+
+	setup.configManager.getErr = errors.New("config corrupted")
+	if _, err := useCase.Execute(ctx); !errors.Is(err, setup.configManager.getErr) {
+		t.Errorf("Execute() error = %v, want config error", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := useCase.Execute(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute() error = %v, want context.Canceled", err)
+	}
+}
+
+// TestWhoAmIUseCase_Execute_FromSettings tests falling back to ccx's own pointer when
+// Claude config names no account
+func TestWhoAmIUseCase_Execute_FromSettings(t *testing.T) {
+	ctx := context.Background()
+	setup := setupSwitchAccountTest()
+	setup.configManager.currentAccount = nil
+	settingsRepo := newMockSettingsRepository()
+	useCase := usecases.NewWhoAmIService(setup.accountRepo, setup.configManager, usecases.WithWhoAmISettings(settingsRepo))
+
+	if _, err := useCase.Execute(ctx); !errors.Is(err, domain.ErrNoCurrentAccount) {
+		t.Errorf("Execute() without a pointer error = %v, want ErrNoCurrentAccount", err)
+	}
+
+	work := setup.testAccounts["work"]
+	settingsRepo.settings.SetCurrentAccountID(work.ID())
+	result, err := useCase.Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if !result.FromCCX || !result.KnownToCCX || result.Account.ID != string(work.ID()) {
+		t.Errorf("result = %+v, want the work account from ccx's pointer", result)
+	}
+
+	// Claude config takes precedence when it names an account
+	setup.configManager.currentAccount = setup.testAccounts["personal"]
+	result, err = useCase.Execute(ctx)
+	if err != nil || result.FromCCX || result.Account.Email != testEmailPersonal {
+		t.Errorf("result = %+v (err %v), want the account from Claude config", result, err)
+	}
+}
+
+// mustAccount creates an account as Claude config would describe it
+func mustAccount(t *testing.T, email, uuid string) *domain.Account {
+	t.Helper()
+	account, err := domain.NewAccount(email, "", uuid)
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	return account
+}